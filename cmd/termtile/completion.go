@@ -0,0 +1,306 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/1broseidon/termtile/internal/config"
+	"github.com/1broseidon/termtile/internal/workspace"
+)
+
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// completionWordRE matches names that can be embedded in a completion script
+// without quoting. Anything else is left out of the script.
+var completionWordRE = regexp.MustCompile(`^[A-Za-z0-9._@+:-]+$`)
+
+// completionValues holds the dynamic names baked into a completion script.
+type completionValues struct {
+	layouts    []string
+	workspaces []string
+}
+
+// positional maps "command subcommand" to the words its positional
+// arguments complete to.
+func (v completionValues) positional() map[string][]string {
+	return map[string][]string{
+		"layout apply":            v.layouts,
+		"layout default":          v.layouts,
+		"layout preview":          v.layouts,
+		"layout delete":           v.layouts,
+		"workspace load":          v.workspaces,
+		"workspace save":          v.workspaces,
+		"workspace close":         v.workspaces,
+		"workspace focus":         v.workspaces,
+		"workspace reattach":      v.workspaces,
+		"workspace delete":        v.workspaces,
+		"workspace rename":        v.workspaces,
+		"workspace restore":       v.workspaces,
+		"workspace enable-agent":  v.workspaces,
+		"workspace disable-agent": v.workspaces,
+		"workspace sync":          {"pull", "push"},
+		"workspace registry":      {"export", "import"},
+	}
+}
+
+// flags maps flag names to the words their values complete to.
+func (v completionValues) flags() map[string][]string {
+	return map[string][]string{
+		"layout":    v.layouts,
+		"workspace": v.workspaces,
+		"to":        v.workspaces,
+	}
+}
+
+func printCompletionUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: termtile completion bash|zsh|fish")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Print a shell completion script for commands, layout names and workspace")
+	fmt.Fprintln(w, "names. Names are read when the script is generated; regenerate it after")
+	fmt.Fprintln(w, "adding layouts or workspaces.")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Examples:")
+	fmt.Fprintln(w, "  source <(termtile completion bash)")
+	fmt.Fprintln(w, "  termtile completion zsh > \"${fpath[1]}/_termtile\"")
+	fmt.Fprintln(w, "  termtile completion fish > ~/.config/fish/completions/termtile.fish")
+}
+
+func runCompletion(args []string) int {
+	if len(args) == 1 && (args[0] == "help" || args[0] == "-h" || args[0] == "--help") {
+		printCompletionUsage(os.Stdout)
+		return 0
+	}
+	if len(args) != 1 {
+		printCompletionUsage(os.Stderr)
+		return 2
+	}
+
+	script, err := completionScript(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		printCompletionUsage(os.Stderr)
+		return 2
+	}
+	fmt.Print(script)
+	return 0
+}
+
+// completionScript renders the completion script for shell with the current
+// layout and workspace names.
+func completionScript(shell string) (string, error) {
+	var b strings.Builder
+	cmds := commandTable()
+	values := loadCompletionValues()
+	switch shell {
+	case "bash":
+		writeBashCompletion(&b, cmds, values)
+	case "zsh":
+		writeZshCompletion(&b, cmds, values)
+	case "fish":
+		writeFishCompletion(&b, cmds, values)
+	default:
+		return "", fmt.Errorf("unsupported shell %q (want %s)", shell, strings.Join(completionShells, ", "))
+	}
+	return b.String(), nil
+}
+
+// loadCompletionValues collects layout names from the config and workspace
+// names from saved workspaces and the desktop registry. Failures leave the
+// affected list short rather than failing the script.
+func loadCompletionValues() completionValues {
+	var values completionValues
+
+	layouts := config.DefaultConfig().Layouts
+	if cfg, err := config.Load(); err == nil {
+		layouts = cfg.Layouts
+	}
+	for name, layout := range layouts {
+		values.layouts = append(values.layouts, name)
+		values.layouts = append(values.layouts, layout.Aliases...)
+	}
+
+	if saved, err := workspace.List(); err == nil {
+		values.workspaces = append(values.workspaces, saved...)
+	}
+	if registered, err := workspace.GetAllWorkspaces(); err == nil {
+		for _, ws := range registered {
+			values.workspaces = append(values.workspaces, ws.Name)
+		}
+	}
+
+	values.layouts = completionWords(values.layouts)
+	values.workspaces = completionWords(values.workspaces)
+	return values
+}
+
+// completionWords sorts and de-duplicates names, dropping any that would
+// need quoting inside a script.
+func completionWords(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		if seen[name] || !completionWordRE.MatchString(name) {
+			continue
+		}
+		seen[name] = true
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func commandNames(cmds []command) []string {
+	names := make([]string, 0, len(cmds))
+	for _, cmd := range cmds {
+		names = append(names, cmd.name)
+	}
+	return names
+}
+
+func sortedCompletionKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func writeBashCompletion(w io.Writer, cmds []command, values completionValues) {
+	fmt.Fprintln(w, "# bash completion for termtile, generated by `termtile completion bash`.")
+	fmt.Fprintln(w, "# Regenerate it to pick up new layouts and workspaces.")
+	fmt.Fprintln(w, "_termtile() {")
+	fmt.Fprintln(w, `    local cur="${COMP_WORDS[COMP_CWORD]}"`)
+	fmt.Fprintln(w, `    local prev="${COMP_WORDS[COMP_CWORD-1]}"`)
+	fmt.Fprintln(w, `    local words=""`)
+	fmt.Fprintln(w, `    case "$prev" in`)
+	flags := values.flags()
+	for _, name := range sortedCompletionKeys(flags) {
+		fmt.Fprintf(w, "        -%s|--%s) words=%q ;;\n", name, name, strings.Join(flags[name], " "))
+	}
+	fmt.Fprintln(w, "        *)")
+	fmt.Fprintln(w, "            local -a args=()")
+	fmt.Fprintln(w, "            local word")
+	fmt.Fprintln(w, `            for word in "${COMP_WORDS[@]:1:COMP_CWORD-1}"; do`)
+	fmt.Fprintln(w, `                [[ $word == -* ]] || args+=("$word")`)
+	fmt.Fprintln(w, "            done")
+	fmt.Fprintln(w, `            case "${#args[@]}" in`)
+	fmt.Fprintf(w, "                0) words=%q ;;\n", strings.Join(commandNames(cmds), " "))
+	fmt.Fprintln(w, "                1)")
+	fmt.Fprintln(w, `                    case "${args[0]}" in`)
+	for _, cmd := range cmds {
+		if len(cmd.subcommands) > 0 {
+			fmt.Fprintf(w, "                        %s) words=%q ;;\n", cmd.name, strings.Join(cmd.subcommands, " "))
+		}
+	}
+	fmt.Fprintln(w, "                    esac")
+	fmt.Fprintln(w, "                    ;;")
+	fmt.Fprintln(w, "                *)")
+	fmt.Fprintln(w, `                    case "${args[0]} ${args[1]}" in`)
+	positional := values.positional()
+	for _, key := range sortedCompletionKeys(positional) {
+		fmt.Fprintf(w, "                        %q) words=%q ;;\n", key, strings.Join(positional[key], " "))
+	}
+	fmt.Fprintln(w, "                    esac")
+	fmt.Fprintln(w, "                    ;;")
+	fmt.Fprintln(w, "            esac")
+	fmt.Fprintln(w, "            ;;")
+	fmt.Fprintln(w, "    esac")
+	fmt.Fprintln(w, `    COMPREPLY=($(compgen -W "$words" -- "$cur"))`)
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w, "complete -F _termtile termtile")
+}
+
+func writeZshCompletion(w io.Writer, cmds []command, values completionValues) {
+	fmt.Fprintln(w, "#compdef termtile")
+	fmt.Fprintln(w, "# zsh completion for termtile, generated by `termtile completion zsh`.")
+	fmt.Fprintln(w, "# Regenerate it to pick up new layouts and workspaces.")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "_termtile() {")
+	fmt.Fprintln(w, "    local -a args values")
+	fmt.Fprintln(w, `    case "${words[CURRENT-1]}" in`)
+	flags := values.flags()
+	for _, name := range sortedCompletionKeys(flags) {
+		fmt.Fprintf(w, "        -%s|--%s) values=(%s) ;;\n", name, name, strings.Join(flags[name], " "))
+	}
+	fmt.Fprintln(w, "        *)")
+	fmt.Fprintln(w, "            args=(${${words[2,CURRENT-1]}:#-*})")
+	fmt.Fprintln(w, "            case ${#args} in")
+	fmt.Fprintf(w, "                0) values=(%s) ;;\n", strings.Join(commandNames(cmds), " "))
+	fmt.Fprintln(w, "                1)")
+	fmt.Fprintln(w, `                    case "${args[1]}" in`)
+	for _, cmd := range cmds {
+		if len(cmd.subcommands) > 0 {
+			fmt.Fprintf(w, "                        %s) values=(%s) ;;\n", cmd.name, strings.Join(cmd.subcommands, " "))
+		}
+	}
+	fmt.Fprintln(w, "                    esac")
+	fmt.Fprintln(w, "                    ;;")
+	fmt.Fprintln(w, "                *)")
+	fmt.Fprintln(w, `                    case "${args[1]} ${args[2]}" in`)
+	positional := values.positional()
+	for _, key := range sortedCompletionKeys(positional) {
+		fmt.Fprintf(w, "                        %q) values=(%s) ;;\n", key, strings.Join(positional[key], " "))
+	}
+	fmt.Fprintln(w, "                    esac")
+	fmt.Fprintln(w, "                    ;;")
+	fmt.Fprintln(w, "            esac")
+	fmt.Fprintln(w, "            ;;")
+	fmt.Fprintln(w, "    esac")
+	fmt.Fprintln(w, "    compadd -a values")
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, `if [ "$funcstack[1]" = "_termtile" ]; then`)
+	fmt.Fprintln(w, `    _termtile "$@"`)
+	fmt.Fprintln(w, "else")
+	fmt.Fprintln(w, "    compdef _termtile termtile")
+	fmt.Fprintln(w, "fi")
+}
+
+func writeFishCompletion(w io.Writer, cmds []command, values completionValues) {
+	fmt.Fprintln(w, "# fish completion for termtile, generated by `termtile completion fish`.")
+	fmt.Fprintln(w, "# Regenerate it to pick up new layouts and workspaces.")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "# Positional words typed so far, without the command name and flags.")
+	fmt.Fprintln(w, "function __termtile_args")
+	fmt.Fprintln(w, "    string match -v -- '-*' (commandline -opc)[2..-1]")
+	fmt.Fprintln(w, "end")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "# True when the positional words start with argv; with --exact, when")
+	fmt.Fprintln(w, "# they are exactly argv.")
+	fmt.Fprintln(w, "function __termtile_at")
+	fmt.Fprintln(w, "    set -l exact 0")
+	fmt.Fprintln(w, "    if test \"$argv[1]\" = --exact")
+	fmt.Fprintln(w, "        set exact 1")
+	fmt.Fprintln(w, "        set -e argv[1]")
+	fmt.Fprintln(w, "    end")
+	fmt.Fprintln(w, "    set -l args (__termtile_args)")
+	fmt.Fprintln(w, "    test (count $args) -ge (count $argv); or return 1")
+	fmt.Fprintln(w, "    if test $exact -eq 1")
+	fmt.Fprintln(w, "        test (count $args) -eq (count $argv); or return 1")
+	fmt.Fprintln(w, "    end")
+	fmt.Fprintln(w, "    for i in (seq (count $argv))")
+	fmt.Fprintln(w, "        test \"$args[$i]\" = \"$argv[$i]\"; or return 1")
+	fmt.Fprintln(w, "    end")
+	fmt.Fprintln(w, "end")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "complete -c termtile -f")
+	fmt.Fprintf(w, "complete -c termtile -n '__termtile_at --exact' -a '%s'\n", strings.Join(commandNames(cmds), " "))
+	for _, cmd := range cmds {
+		if len(cmd.subcommands) > 0 {
+			fmt.Fprintf(w, "complete -c termtile -n '__termtile_at --exact %s' -a '%s'\n", cmd.name, strings.Join(cmd.subcommands, " "))
+		}
+	}
+	positional := values.positional()
+	for _, key := range sortedCompletionKeys(positional) {
+		fmt.Fprintf(w, "complete -c termtile -n '__termtile_at %s' -a '%s'\n", key, strings.Join(positional[key], " "))
+	}
+	flags := values.flags()
+	for _, name := range sortedCompletionKeys(flags) {
+		fmt.Fprintf(w, "complete -c termtile -l %s -o %s -x -a '%s'\n", name, name, strings.Join(flags[name], " "))
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/1broseidon/termtile/internal/workspace"
+)
+
+func TestCompletionScript_IncludesCommandsAndNames(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	configDir := filepath.Join(home, ".config", "termtile")
+	if err := os.MkdirAll(configDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := "layouts:\n  docked-left:\n    inherits: builtin:grid\n    aliases: [dl]\n"
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(cfg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := workspace.Write(&workspace.WorkspaceConfig{Name: "api-dev", Layout: "grid"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, shell := range completionShells {
+		script, err := completionScript(shell)
+		if err != nil {
+			t.Fatalf("%s: %v", shell, err)
+		}
+		for _, want := range []string{"docked-left", "dl", "grid", "api-dev", "workspace", "preview", "restart"} {
+			if !strings.Contains(script, want) {
+				t.Errorf("%s script does not mention %q", shell, want)
+			}
+		}
+	}
+}
+
+func TestCompletionScript_UnknownShell(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	if _, err := completionScript("tcsh"); err == nil {
+		t.Fatal("expected an error for an unsupported shell")
+	}
+}
+
+func TestCompletionWords_DropsUnsafeNames(t *testing.T) {
+	got := completionWords([]string{"grid", "my layout", "grid", "a\"b", "dev-1"})
+	if strings.Join(got, ",") != "dev-1,grid" {
+		t.Fatalf("completionWords = %v, want [dev-1 grid]", got)
+	}
+}
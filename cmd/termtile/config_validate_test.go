@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunConfigValidate_StrictFailsOnWarning(t *testing.T) {
+	dir := t.TempDir()
+	warned := filepath.Join(dir, "warned.yaml")
+	if err := os.WriteFile(warned, []byte("preferred_terminal: no-such-terminal\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	clean := filepath.Join(dir, "clean.yaml")
+	if err := os.WriteFile(clean, []byte("gap_size: 8\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if rc := runConfig([]string{"validate", "--path", warned}); rc != 0 {
+		t.Fatalf("validate with warning exit=%d, want 0", rc)
+	}
+	if rc := runConfig([]string{"validate", "--strict", "--path", warned}); rc != 1 {
+		t.Fatalf("validate --strict with warning exit=%d, want 1", rc)
+	}
+	if rc := runConfig([]string{"validate", "--strict", "--path", clean}); rc != 0 {
+		t.Fatalf("validate --strict on clean config exit=%d, want 0", rc)
+	}
+}
+
+func TestRunConfigSchema_ExitCodes(t *testing.T) {
+	devnull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devnull.Close()
+	stdout := os.Stdout
+	os.Stdout = devnull
+	defer func() { os.Stdout = stdout }()
+
+	if rc := runConfig([]string{"schema"}); rc != 0 {
+		t.Fatalf("schema exit=%d, want 0", rc)
+	}
+	if rc := runConfig([]string{"schema", "extra"}); rc != 2 {
+		t.Fatalf("schema with argument exit=%d, want 2", rc)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/1broseidon/termtile/internal/daemon"
+	"github.com/1broseidon/termtile/internal/ipc"
+	"github.com/1broseidon/termtile/internal/tiling"
+)
+
+// daemonStopTimeout bounds how long daemon stop waits for the old daemon's
+// IPC socket to go away.
+const daemonStopTimeout = 5 * time.Second
+
+func printDaemonUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage:")
+	fmt.Fprintln(w, "  termtile daemon")
+	fmt.Fprintln(w, "  termtile daemon stop")
+	fmt.Fprintln(w, "  termtile daemon restart")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "With no subcommand, start the daemon in the foreground.")
+	fmt.Fprintln(w, "stop asks the running daemon to exit cleanly, as on SIGTERM.")
+	fmt.Fprintln(w, "restart stops the running daemon, then starts a new one in the foreground.")
+}
+
+func runDaemonCommand(args []string) int {
+	if len(args) == 0 {
+		runDaemon()
+		return 0
+	}
+
+	switch args[0] {
+	case "help", "-h", "--help":
+		printDaemonUsage(os.Stdout)
+		return 0
+	case "stop", "restart":
+		if len(args) > 1 {
+			fmt.Fprintf(os.Stderr, "daemon %s takes no arguments\n\n", args[0])
+			printDaemonUsage(os.Stderr)
+			return 2
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown daemon subcommand: %s\n\n", args[0])
+		printDaemonUsage(os.Stderr)
+		return 2
+	}
+
+	client := ipc.NewClient()
+	running := client.Ping() == nil
+
+	if args[0] == "stop" {
+		if !running {
+			fmt.Fprintln(os.Stderr, "daemon is not running")
+			return 1
+		}
+		if err := stopDaemon(client); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	if running {
+		if err := stopDaemon(client); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to find executable:", err)
+		return 1
+	}
+	if err := syscall.Exec(exe, []string{exe, "daemon"}, os.Environ()); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to start daemon:", err)
+		return 1
+	}
+	return 0
+}
+
+// stopDaemon sends SHUTDOWN and waits for the daemon to stop answering.
+func stopDaemon(client *ipc.Client) error {
+	if err := client.Shutdown(); err != nil {
+		return err
+	}
+	deadline := time.Now().Add(daemonStopTimeout)
+	for client.Ping() == nil {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("daemon did not exit within %s", daemonStopTimeout)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return nil
+}
+
+// restoreActiveLayout makes the layout saved by persist_active_layout
+// active. A missing, unreadable or unknown saved layout leaves the default
+// in place.
+func restoreActiveLayout(tiler *tiling.Tiler) {
+	name, err := daemon.LoadActiveLayout()
+	if err != nil {
+		log.Printf("Warning: failed to restore active layout: %v", err)
+		return
+	}
+	if name == "" {
+		return
+	}
+	if err := tiler.SetActiveLayout(name); err != nil {
+		log.Printf("Warning: saved active layout %q no longer exists; using %q", name, tiler.GetActiveLayoutName())
+		return
+	}
+	log.Printf("Restored active layout: %s", tiler.GetActiveLayoutName())
+}
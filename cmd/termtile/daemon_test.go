@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/1broseidon/termtile/internal/config"
+	"github.com/1broseidon/termtile/internal/daemon"
+	"github.com/1broseidon/termtile/internal/terminals"
+	"github.com/1broseidon/termtile/internal/tiling"
+)
+
+func TestRestoreActiveLayout(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	columns := cfg.Layouts["columns"]
+	columns.Aliases = []string{"c"}
+	cfg.Layouts["columns"] = columns
+
+	restored := func() string {
+		tiler := tiling.NewTiler(nil, terminals.NewDetector(nil), cfg)
+		restoreActiveLayout(tiler)
+		return tiler.GetActiveLayoutName()
+	}
+
+	if got := restored(); got != cfg.DefaultLayout {
+		t.Fatalf("nothing saved: layout = %q, want %q", got, cfg.DefaultLayout)
+	}
+	// An alias is restored as the layout's primary name.
+	if err := daemon.SaveActiveLayout("c"); err != nil {
+		t.Fatal(err)
+	}
+	if got := restored(); got != "columns" {
+		t.Fatalf("restored layout = %q, want columns", got)
+	}
+	// A layout removed from the config since it was saved falls back to the default.
+	if err := daemon.SaveActiveLayout("gone"); err != nil {
+		t.Fatal(err)
+	}
+	if got := restored(); got != cfg.DefaultLayout {
+		t.Fatalf("layout after saving unknown name = %q, want %q", got, cfg.DefaultLayout)
+	}
+}
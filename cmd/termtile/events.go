@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/1broseidon/termtile/internal/ipc"
+	"github.com/1broseidon/termtile/internal/movemode"
+	"github.com/1broseidon/termtile/internal/platform"
+	"github.com/1broseidon/termtile/internal/workspace"
+)
+
+func runEvents(args []string) int {
+	fs := flag.NewFlagSet("events", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: termtile events")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Streams daemon events as line-delimited JSON until interrupted: tile,")
+		fmt.Fprintln(os.Stderr, "layout_changed, workspace_active_changed and move_completed.")
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(os.Stderr, "events takes no arguments")
+		fs.Usage()
+		return 2
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	err := ipc.NewClient().SubscribeEvents(func(ev ipc.Event) error {
+		return enc.Encode(ev)
+	})
+	fmt.Fprintln(os.Stderr, err)
+	return 1
+}
+
+// moveCompletedEvent describes a move-mode result for event subscribers.
+func moveCompletedEvent(result movemode.MoveResult) ipc.Event {
+	monitor, source, target := result.DisplayID, result.SourceSlot, result.TargetSlot
+	return ipc.Event{
+		Type:       ipc.EventMoveCompleted,
+		Monitor:    &monitor,
+		SourceSlot: &source,
+		TargetSlot: &target,
+		Swap:       result.IsSwap,
+	}
+}
+
+// activeWorkspacePollInterval paces the daemon's check for a change of the
+// active workspace while events are subscribed.
+const activeWorkspacePollInterval = time.Second
+
+// activeWorkspaceWatcher detects changes of the workspace on the current
+// desktop. The registry is written by other termtile processes, so the
+// daemon polls it rather than being told.
+type activeWorkspaceWatcher struct {
+	// lookup returns the current desktop and its workspace name ("" for
+	// none).
+	lookup  func() (int, string, error)
+	known   bool
+	desktop int
+	name    string
+}
+
+// poll returns a workspace_active_changed event when the desktop or its
+// workspace differs from the previous poll. The first poll only records the
+// state.
+func (w *activeWorkspaceWatcher) poll() (ipc.Event, bool) {
+	desktop, name, err := w.lookup()
+	if err != nil {
+		return ipc.Event{}, false
+	}
+	changed := w.known && (desktop != w.desktop || name != w.name)
+	w.known, w.desktop, w.name = true, desktop, name
+	if !changed {
+		return ipc.Event{}, false
+	}
+	return ipc.Event{Type: ipc.EventWorkspaceActiveChanged, Workspace: name, Desktop: &desktop}, true
+}
+
+// reset forgets the recorded state, so the next poll starts afresh.
+func (w *activeWorkspaceWatcher) reset() {
+	w.known = false
+}
+
+func lookupActiveWorkspace() (int, string, error) {
+	desktop, err := platform.GetCurrentDesktopStandalone()
+	if err != nil {
+		return 0, "", err
+	}
+	info, _ := workspace.GetWorkspaceByDesktop(desktop)
+	return desktop, info.Name, nil
+}
+
+// watchActiveWorkspace publishes workspace_active_changed events until ctx
+// is done. It only polls while someone is subscribed.
+func watchActiveWorkspace(ctx context.Context, server *ipc.Server) {
+	w := &activeWorkspaceWatcher{lookup: lookupActiveWorkspace}
+	ticker := time.NewTicker(activeWorkspacePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if server.SubscriberCount() == 0 {
+				w.reset()
+				continue
+			}
+			if ev, ok := w.poll(); ok {
+				server.Publish(ev)
+			}
+		}
+	}
+}
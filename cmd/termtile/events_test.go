@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/1broseidon/termtile/internal/ipc"
+)
+
+func TestActiveWorkspaceWatcher_ReportsChanges(t *testing.T) {
+	type state struct {
+		desktop int
+		name    string
+		err     error
+	}
+	states := []state{
+		{desktop: 0, name: "dev"},
+		{desktop: 0, name: "dev"},
+		{desktop: 1, name: ""},
+		{err: errors.New("no display")},
+		{desktop: 1, name: "agents"},
+	}
+	i := 0
+	w := &activeWorkspaceWatcher{lookup: func() (int, string, error) {
+		s := states[i]
+		i++
+		return s.desktop, s.name, s.err
+	}}
+
+	var got []ipc.Event
+	for range states {
+		if ev, ok := w.poll(); ok {
+			got = append(got, ev)
+		}
+	}
+	if len(got) != 2 {
+		t.Fatalf("events = %+v, want a change to desktop 1 and to agents", got)
+	}
+	if got[0].Type != ipc.EventWorkspaceActiveChanged || got[0].Workspace != "" || *got[0].Desktop != 1 {
+		t.Fatalf("first event = %+v, want desktop 1 without a workspace", got[0])
+	}
+	if got[1].Workspace != "agents" || *got[1].Desktop != 1 {
+		t.Fatalf("second event = %+v, want agents on desktop 1", got[1])
+	}
+
+	// After a reset the next poll only records the state again.
+	w.reset()
+	states = append(states, state{desktop: 2, name: "web"})
+	if ev, ok := w.poll(); ok {
+		t.Fatalf("poll after reset = %+v, want no event", ev)
+	}
+}
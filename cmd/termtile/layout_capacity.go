@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/1broseidon/termtile/internal/config"
+	"github.com/1broseidon/termtile/internal/tiling"
+	"github.com/1broseidon/termtile/internal/workspace"
+)
+
+// layoutGridJSON is the grid a layout uses for a given terminal count.
+type layoutGridJSON struct {
+	Count  int `json:"count"`
+	Rows   int `json:"rows"`
+	Cols   int `json:"cols"`
+	Placed int `json:"placed"`
+}
+
+// activeTerminalCount returns the terminal count of the workspace on the
+// current desktop, or 0 when there is none.
+func activeTerminalCount() int {
+	ws, err := workspace.GetActiveWorkspace()
+	if err != nil || ws.Name == "" {
+		return 0
+	}
+	return ws.TerminalCount
+}
+
+// layoutCapacityJSON returns the capacity and, for count > 0, the grid used
+// for count terminals.
+func layoutCapacityJSON(l *config.Layout, count int) (int, *layoutGridJSON) {
+	capacity := tiling.LayoutCapacity(l)
+	if count <= 0 {
+		return capacity, nil
+	}
+	rows, cols, placed := tiling.LayoutGrid(l, count)
+	return capacity, &layoutGridJSON{Count: count, Rows: rows, Cols: cols, Placed: placed}
+}
+
+// describeLayoutCapacity summarises a layout's capacity for layout list,
+// e.g. "holds 9 (3x3)" or "no limit; 5 terminals -> 2x3". Without a count,
+// capped layouts are shown at capacity.
+func describeLayoutCapacity(l *config.Layout, count int) string {
+	capacity := tiling.LayoutCapacity(l)
+	if count <= 0 {
+		if capacity == 0 {
+			return "no limit"
+		}
+		return fmt.Sprintf("holds %d (%s)", capacity, describeLayoutGrid(l, capacity))
+	}
+
+	prefix := "no limit"
+	if capacity > 0 {
+		prefix = fmt.Sprintf("holds %d", capacity)
+	}
+	out := fmt.Sprintf("%s; %d terminal(s) -> %s", prefix, count, describeLayoutGrid(l, count))
+	if _, _, placed := tiling.LayoutGrid(l, count); placed < count {
+		out += fmt.Sprintf(", %d untiled", count-placed)
+	}
+	return out
+}
+
+// describeLayoutGrid renders the grid for count terminals, naming the
+// master pane and reserved slot when the layout has them.
+func describeLayoutGrid(l *config.Layout, count int) string {
+	rows, cols, placed := tiling.LayoutGrid(l, count)
+	grid := fmt.Sprintf("%dx%d", rows, cols)
+	if l.Mode == config.LayoutModeMasterStack {
+		if rows == 0 {
+			grid = "master"
+		} else {
+			grid = "master + " + grid + " stack"
+		}
+	}
+	if l.ReservedRegion != nil && l.ReservedSlot < placed {
+		grid += " + reserved"
+	}
+	return grid
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/1broseidon/termtile/internal/config"
+)
+
+func TestDescribeLayoutCapacity(t *testing.T) {
+	fixed := &config.Layout{Mode: config.LayoutModeFixed, FixedGrid: config.FixedGrid{Rows: 3, Cols: 3}}
+	masterStack := &config.Layout{
+		Mode:        config.LayoutModeMasterStack,
+		MasterStack: config.MasterStack{MasterWidthPercent: 50, MaxStackRows: 3, MaxStackCols: 2},
+	}
+	docked := &config.Layout{
+		Mode:           config.LayoutModeFixed,
+		FixedGrid:      config.FixedGrid{Rows: 1, Cols: 2},
+		ReservedRegion: &config.TileRegion{Type: config.RegionBottomHalf},
+	}
+	auto := &config.Layout{Mode: config.LayoutModeAuto}
+
+	tests := []struct {
+		name   string
+		layout *config.Layout
+		count  int
+		want   string
+	}{
+		{"fixed at capacity", fixed, 0, "holds 9 (3x3)"},
+		{"fixed under", fixed, 4, "holds 9; 4 terminal(s) -> 3x3"},
+		{"fixed over", fixed, 11, "holds 9; 11 terminal(s) -> 3x3, 2 untiled"},
+		{"auto no count", auto, 0, "no limit"},
+		{"auto", auto, 5, "no limit; 5 terminal(s) -> 2x3"},
+		{"vertical", &config.Layout{Mode: config.LayoutModeVertical}, 3, "no limit; 3 terminal(s) -> 3x1"},
+		{"master-stack at capacity", masterStack, 0, "holds 7 (master + 3x2 stack)"},
+		{"master-stack alone", masterStack, 1, "holds 7; 1 terminal(s) -> master"},
+		{"reserved", docked, 0, "holds 3 (1x2 + reserved)"},
+	}
+	for _, tt := range tests {
+		if got := describeLayoutCapacity(tt.layout, tt.count); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLayoutCapacityJSON(t *testing.T) {
+	fixed := &config.Layout{Mode: config.LayoutModeFixed, FixedGrid: config.FixedGrid{Rows: 2, Cols: 2}}
+	capacity, grid := layoutCapacityJSON(fixed, 0)
+	if capacity != 4 || grid != nil {
+		t.Fatalf("no count: capacity=%d grid=%+v, want 4 and nil", capacity, grid)
+	}
+	capacity, grid = layoutCapacityJSON(fixed, 6)
+	want := layoutGridJSON{Count: 6, Rows: 2, Cols: 2, Placed: 4}
+	if capacity != 4 || grid == nil || *grid != want {
+		t.Fatalf("count 6: capacity=%d grid=%+v, want 4 and %+v", capacity, grid, want)
+	}
+	if capacity, _ := layoutCapacityJSON(&config.Layout{Mode: config.LayoutModeHorizontal}, 3); capacity != 0 {
+		t.Fatalf("horizontal capacity = %d, want 0", capacity)
+	}
+}
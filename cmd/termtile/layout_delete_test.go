@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/1broseidon/termtile/internal/config"
+)
+
+func TestRunLayoutDelete(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	configDir := filepath.Join(home, ".config", "termtile")
+	if err := os.MkdirAll(configDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	data := `
+default_layout: main
+layouts:
+  main:
+    inherits: builtin:grid
+  docked-left:
+    inherits: builtin:columns
+    aliases: [dl]
+`
+	path := filepath.Join(configDir, "config.yaml")
+	if err := os.WriteFile(path, []byte(strings.TrimSpace(data)+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"grid", "main", "missing"} {
+		if rc := runLayoutDelete([]string{name}); rc != 1 {
+			t.Fatalf("layout delete %s exit=%d, want 1", name, rc)
+		}
+	}
+	if rc := runLayoutDelete(nil); rc != 2 {
+		t.Fatalf("layout delete without a name exit=%d, want 2", rc)
+	}
+
+	if rc := runLayoutDelete([]string{"dl"}); rc != 0 {
+		t.Fatalf("layout delete dl exit=%d, want 0", rc)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("reload config: %v", err)
+	}
+	if _, ok := cfg.Layouts["docked-left"]; ok {
+		t.Fatal("docked-left still in config after delete")
+	}
+	if _, ok := cfg.Layouts["main"]; !ok || cfg.DefaultLayout != "main" {
+		t.Fatalf("delete disturbed other settings: default=%q layouts=%v", cfg.DefaultLayout, cfg.Layouts)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// previewCycleGap is how long the original geometry stays visible between
+// two previews in a --cycle run; tests shorten it.
+var previewCycleGap = 500 * time.Millisecond
+
+// parsePreviewCycle splits a comma-separated --cycle value into layout names.
+func parsePreviewCycle(value string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("--cycle %q contains an empty layout name", value)
+		}
+		names = append(names, name)
+	}
+	if len(names) < 2 {
+		return nil, fmt.Errorf("--cycle needs at least two layouts, got %q", value)
+	}
+	return names, nil
+}
+
+// cycleLayoutPreviews previews each layout in order. The daemon restores the
+// original geometry when each preview expires, so after every preview this
+// waits out its duration plus previewCycleGap before starting the next one;
+// when it returns nil the last preview has been restored too. A cancelled
+// ctx stops the cycle early, leaving the running preview to restore itself.
+func cycleLayoutPreviews(ctx context.Context, names []string, duration time.Duration, preview func(string) error, onPreview func(string)) error {
+	for _, name := range names {
+		if err := preview(name); err != nil {
+			return fmt.Errorf("preview %q: %w", name, err)
+		}
+		if onPreview != nil {
+			onPreview(name)
+		}
+		timer := time.NewTimer(duration + previewCycleGap)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParsePreviewCycle(t *testing.T) {
+	names, err := parsePreviewCycle(" grid, master ,vstack")
+	if err != nil {
+		t.Fatalf("parsePreviewCycle: %v", err)
+	}
+	if want := []string{"grid", "master", "vstack"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("names = %q, want %q", names, want)
+	}
+	for _, bad := range []string{"grid", "grid,,columns", "grid,"} {
+		if _, err := parsePreviewCycle(bad); err == nil {
+			t.Fatalf("parsePreviewCycle(%q) error = nil", bad)
+		}
+	}
+}
+
+func TestCycleLayoutPreviews_Order(t *testing.T) {
+	orig := previewCycleGap
+	previewCycleGap = time.Millisecond
+	defer func() { previewCycleGap = orig }()
+
+	var calls []string
+	var announced []string
+	start := time.Now()
+	err := cycleLayoutPreviews(context.Background(), []string{"grid", "master", "vstack"}, 5*time.Millisecond,
+		func(name string) error {
+			calls = append(calls, name)
+			return nil
+		},
+		func(name string) { announced = append(announced, name) })
+	if err != nil {
+		t.Fatalf("cycleLayoutPreviews: %v", err)
+	}
+	if want := []string{"grid", "master", "vstack"}; !reflect.DeepEqual(calls, want) || !reflect.DeepEqual(announced, want) {
+		t.Fatalf("previewed %q, announced %q, want %q", calls, announced, want)
+	}
+	// Returning only after the last preview's duration means the daemon
+	// has already restored the original geometry.
+	if elapsed := time.Since(start); elapsed < 3*6*time.Millisecond {
+		t.Fatalf("cycle returned after %s, before the last preview expired", elapsed)
+	}
+}
+
+func TestCycleLayoutPreviews_StopsOnError(t *testing.T) {
+	orig := previewCycleGap
+	previewCycleGap = time.Millisecond
+	defer func() { previewCycleGap = orig }()
+
+	boom := errors.New("unknown layout")
+	var calls []string
+	err := cycleLayoutPreviews(context.Background(), []string{"grid", "nope", "vstack"}, time.Millisecond,
+		func(name string) error {
+			calls = append(calls, name)
+			if name == "nope" {
+				return boom
+			}
+			return nil
+		}, nil)
+	if !errors.Is(err, boom) {
+		t.Fatalf("err = %v, want %v", err, boom)
+	}
+	if want := []string{"grid", "nope"}; !reflect.DeepEqual(calls, want) {
+		t.Fatalf("previewed %q, want %q", calls, want)
+	}
+}
+
+func TestCycleLayoutPreviews_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls []string
+	err := cycleLayoutPreviews(ctx, []string{"grid", "master"}, time.Hour,
+		func(name string) error {
+			calls = append(calls, name)
+			cancel()
+			return nil
+		}, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if want := []string{"grid"}; !reflect.DeepEqual(calls, want) {
+		t.Fatalf("previewed %q, want %q", calls, want)
+	}
+}
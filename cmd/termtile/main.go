@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -12,14 +13,15 @@ import (
 	"os/exec"
 	"os/signal"
 	"sort"
+	"strconv"
 	"syscall"
-	"time"
 
 	"github.com/1broseidon/termtile/internal/agent"
 	"github.com/1broseidon/termtile/internal/config"
 	"github.com/1broseidon/termtile/internal/daemon"
 	"github.com/1broseidon/termtile/internal/hotkeys"
 	"github.com/1broseidon/termtile/internal/ipc"
+	"github.com/1broseidon/termtile/internal/mcp"
 	"github.com/1broseidon/termtile/internal/movemode"
 	"github.com/1broseidon/termtile/internal/platform"
 	"github.com/1broseidon/termtile/internal/terminals"
@@ -29,83 +31,116 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// command is a top-level termtile command. subcommands lists the words it
+// dispatches on; shell completion walks the same table.
+type command struct {
+	name        string
+	subcommands []string
+	run         func(args []string) int
+}
+
+func commandTable() []command {
+	return []command{
+		{name: "daemon", subcommands: []string{"stop", "restart"}, run: runDaemonCommand},
+		{name: "status", run: runStatus},
+		{name: "undo", run: runUndo},
+		{name: "events", run: runEvents},
+		{name: "layout", subcommands: []string{"list", "apply", "default", "preview", "delete"}, run: runLayout},
+		{name: "terminal", subcommands: []string{"add", "remove", "move", "send", "paste", "read", "status", "list"}, run: runTerminal},
+		{name: "config", subcommands: []string{"validate", "print", "explain", "schema"}, run: runConfig},
+		{name: "workspace", subcommands: []string{"new", "save", "load", "close", "focus", "reattach", "list", "delete", "prune", "rename", "restore", "enable-agent", "disable-agent", "init", "link", "sync", "registry"}, run: runWorkspace},
+		{name: "palette", run: runPalette},
+		{name: "tui", run: runTUI},
+		{name: "mcp", subcommands: []string{"serve", "cleanup"}, run: runMCP},
+		{name: "hook", subcommands: []string{"start", "check", "emit"}, run: runHook},
+		{name: "monitor", subcommands: []string{"list"}, run: runMonitor},
+		{name: "replay", run: runReplay},
+		{name: "completion", subcommands: completionShells, run: runCompletion},
+	}
+}
+
 func main() {
-	if len(os.Args) < 2 {
+	args, quiet := parseGlobalFlags(os.Args[1:])
+	warnings.setQuiet(quiet)
+	config.SetWarningOutput(warnings)
+	workspace.SetWarningOutput(warnings)
+
+	if len(args) < 1 {
 		printMainUsage(os.Stdout)
 		os.Exit(0)
 	}
 
-	switch os.Args[1] {
-	case "daemon":
-		if len(os.Args) > 2 && (os.Args[2] == "help" || os.Args[2] == "-h" || os.Args[2] == "--help") {
-			fmt.Fprintln(os.Stdout, "Usage: termtile daemon")
-			os.Exit(0)
-		}
-		if len(os.Args) > 2 {
-			fmt.Fprintln(os.Stderr, "daemon takes no arguments")
-			fmt.Fprintln(os.Stderr, "")
-			fmt.Fprintln(os.Stderr, "Usage: termtile daemon")
-			os.Exit(2)
-		}
-		runDaemon()
-	case "status":
-		os.Exit(runStatus(os.Args[2:]))
-	case "undo":
-		os.Exit(runUndo(os.Args[2:]))
-	case "layout":
-		os.Exit(runLayout(os.Args[2:]))
-	case "terminal":
-		os.Exit(runTerminal(os.Args[2:]))
-	case "config":
-		os.Exit(runConfig(os.Args[2:]))
-	case "workspace":
-		os.Exit(runWorkspace(os.Args[2:]))
-	case "palette":
-		os.Exit(runPalette(os.Args[2:]))
-	case "tui":
-		os.Exit(runTUI(os.Args[2:]))
-	case "mcp":
-		os.Exit(runMCP(os.Args[2:]))
-	case "hook":
-		os.Exit(runHook(os.Args[2:]))
+	switch args[0] {
 	case "help", "-h", "--help":
 		printMainUsage(os.Stdout)
 		os.Exit(0)
-	default:
-		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", os.Args[1])
-		printMainUsage(os.Stderr)
-		os.Exit(2)
 	}
+	for _, cmd := range commandTable() {
+		if cmd.name == args[0] {
+			os.Exit(cmd.run(args[1:]))
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", args[0])
+	printMainUsage(os.Stderr)
+	os.Exit(2)
+}
+
+// terminalClassMatches converts the configured terminal_classes into the
+// detector's class/instance matches.
+func terminalClassMatches(cfg *config.Config) []terminals.ClassMatch {
+	matches := make([]terminals.ClassMatch, 0, len(cfg.TerminalClasses))
+	for _, tc := range cfg.TerminalClasses {
+		matches = append(matches, terminals.ClassMatch{Class: tc.Class, Instance: tc.Instance})
+	}
+	return matches
 }
 
 func printMainUsage(w io.Writer) {
-	fmt.Fprintln(w, "Usage: termtile <command> [options]")
+	fmt.Fprintln(w, "Usage: termtile [--quiet] <command> [options]")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Global options:")
+	fmt.Fprintln(w, "  -q, --quiet         Send non-fatal warnings to the agent action log instead of stderr")
+	fmt.Fprintln(w, "                      (also TERMTILE_QUIET=1)")
 	fmt.Fprintln(w, "")
 	fmt.Fprintln(w, "Commands:")
 	fmt.Fprintln(w, "  daemon              Start the termtile daemon (foreground)")
+	fmt.Fprintln(w, "  daemon stop         Stop the running daemon")
+	fmt.Fprintln(w, "  daemon restart      Stop the running daemon and start it here")
 	fmt.Fprintln(w, "  status              Show daemon status")
 	fmt.Fprintln(w, "  undo                Undo last tiling operation")
+	fmt.Fprintln(w, "  events              Stream daemon events as JSON lines")
+	fmt.Fprintln(w, "  replay              Replay a recorded tiling session")
 	fmt.Fprintln(w, "")
 	fmt.Fprintln(w, "  layout list         List available layouts")
 	fmt.Fprintln(w, "  layout apply        Apply a layout")
 	fmt.Fprintln(w, "  layout default      Set default layout")
 	fmt.Fprintln(w, "  layout preview      Preview a layout temporarily")
+	fmt.Fprintln(w, "  layout delete       Delete a user layout from config")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "  monitor list        List connected monitors")
 	fmt.Fprintln(w, "")
 	fmt.Fprintln(w, "  workspace new       Create a new workspace")
 	fmt.Fprintln(w, "  workspace save      Save current terminal state")
 	fmt.Fprintln(w, "  workspace load      Load a saved workspace")
 	fmt.Fprintln(w, "  workspace close     Close active workspace")
+	fmt.Fprintln(w, "  workspace reattach  Reopen windows for an agent workspace's detached sessions")
 	fmt.Fprintln(w, "  workspace list      List saved workspaces")
 	fmt.Fprintln(w, "  workspace delete    Delete a workspace")
+	fmt.Fprintln(w, "  workspace prune     Delete saved workspaces that are not open")
 	fmt.Fprintln(w, "  workspace rename    Rename a workspace")
+	fmt.Fprintln(w, "  workspace restore   Roll a workspace back to a saved snapshot")
+	fmt.Fprintln(w, "  workspace enable-agent   Turn on agent mode for a workspace")
+	fmt.Fprintln(w, "  workspace disable-agent  Turn off agent mode for a workspace")
 	fmt.Fprintln(w, "  workspace init      Initialize project workspace config")
 	fmt.Fprintln(w, "  workspace link      Link project to a canonical workspace")
 	fmt.Fprintln(w, "  workspace sync      Sync project view pull/push")
+	fmt.Fprintln(w, "  workspace registry  Export/import the desktop registry")
 	fmt.Fprintln(w, "")
 	fmt.Fprintln(w, "  terminal add        Add terminal to workspace")
 	fmt.Fprintln(w, "  terminal remove     Remove terminal from workspace")
 	fmt.Fprintln(w, "  terminal move       Move terminal to another workspace")
 	fmt.Fprintln(w, "  terminal send       Send input to terminal slot")
+	fmt.Fprintln(w, "  terminal paste      Paste clipboard into terminal slot")
 	fmt.Fprintln(w, "  terminal read       Read output from terminal slot")
 	fmt.Fprintln(w, "  terminal status     Show terminal/session status")
 	fmt.Fprintln(w, "  terminal list       List current terminals")
@@ -121,6 +156,8 @@ func printMainUsage(w io.Writer) {
 	fmt.Fprintln(w, "  mcp cleanup         List/clean orphaned termtile tmux sessions")
 	fmt.Fprintln(w, "  hook emit           Write hook output artifact for a workspace slot")
 	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "  completion          Print a shell completion script (bash, zsh, fish)")
+	fmt.Fprintln(w, "")
 	fmt.Fprintln(w, "Run 'termtile <command> --help' for command-specific options.")
 }
 
@@ -187,10 +224,12 @@ func runUndo(args []string) int {
 
 func printLayoutUsage(w io.Writer) {
 	fmt.Fprintln(w, "Usage:")
-	fmt.Fprintln(w, "  termtile layout list [--json]")
+	fmt.Fprintln(w, "  termtile layout list [--json] [--count N]")
 	fmt.Fprintln(w, "  termtile layout apply [--tile] <layout>")
 	fmt.Fprintln(w, "  termtile layout default [--tile] <layout>")
 	fmt.Fprintln(w, "  termtile layout preview [--duration N] <layout>")
+	fmt.Fprintln(w, "  termtile layout preview [--duration N] --cycle LAYOUT,LAYOUT[,...]")
+	fmt.Fprintln(w, "  termtile layout delete <layout>")
 	fmt.Fprintln(w, "")
 	fmt.Fprintln(w, "Run 'termtile layout <command> --help' for command-specific options.")
 }
@@ -212,14 +251,17 @@ func runLayout(args []string) int {
 		fs := flag.NewFlagSet("list", flag.ContinueOnError)
 		fs.SetOutput(os.Stderr)
 		fs.Usage = func() {
-			fmt.Fprintln(os.Stderr, "Usage: termtile layout list [--json]")
+			fmt.Fprintln(os.Stderr, "Usage: termtile layout list [--json] [--count N]")
 			fmt.Fprintln(os.Stderr, "")
 			fmt.Fprintln(os.Stderr, "List available layouts (and current selection when the daemon is running).")
+			fmt.Fprintln(os.Stderr, "Each layout shows its capacity and the grid it uses for the active")
+			fmt.Fprintln(os.Stderr, "workspace's terminal count, or for --count N.")
 			fmt.Fprintln(os.Stderr, "")
 			fmt.Fprintln(os.Stderr, "Flags:")
 			fs.PrintDefaults()
 		}
 		jsonOut := fs.Bool("json", false, "Output full layout details as JSON")
+		count := fs.Int("count", -1, "Terminal count to report capacity for (default: active workspace's)")
 		if err := fs.Parse(args[1:]); err != nil {
 			if err == flag.ErrHelp {
 				return 0
@@ -231,9 +273,12 @@ func runLayout(args []string) int {
 			fs.Usage()
 			return 2
 		}
+		if *count < 0 {
+			*count = activeTerminalCount()
+		}
 
 		if *jsonOut {
-			return layoutListJSON()
+			return layoutListJSON(*count)
 		}
 
 		data, err := client.ListLayouts()
@@ -241,9 +286,16 @@ func runLayout(args []string) int {
 			fmt.Fprintln(os.Stderr, err)
 			return 1
 		}
+		// Capacity needs the layout definitions; list names alone if the
+		// daemon does not send them.
+		layouts := data.Definitions
 		fmt.Printf("default_layout: %s\n", data.DefaultLayout)
 		fmt.Printf("active_layout:  %s\n", data.ActiveLayout)
 		for _, name := range data.Layouts {
+			if l, ok := layouts[name]; ok {
+				fmt.Printf("- %-20s %s\n", name, describeLayoutCapacity(&l, *count))
+				continue
+			}
 			fmt.Printf("- %s\n", name)
 		}
 		return 0
@@ -252,14 +304,19 @@ func runLayout(args []string) int {
 		fs := flag.NewFlagSet("apply", flag.ContinueOnError)
 		fs.SetOutput(os.Stderr)
 		fs.Usage = func() {
-			fmt.Fprintln(os.Stderr, "Usage: termtile layout apply [--tile] <layout>")
+			fmt.Fprintln(os.Stderr, "Usage: termtile layout apply [--tile] [--all-monitors] [--fill] <layout>")
 			fmt.Fprintln(os.Stderr, "")
 			fmt.Fprintln(os.Stderr, "Set the daemon's active layout (optionally tiling immediately).")
+			fmt.Fprintln(os.Stderr, "Without --all-monitors, --tile follows retile_target from config.")
+			fmt.Fprintln(os.Stderr, "--fill spawns terminals into the active workspace until a fixed or")
+			fmt.Fprintln(os.Stderr, "master-stack layout is at capacity.")
 			fmt.Fprintln(os.Stderr, "")
 			fmt.Fprintln(os.Stderr, "Flags:")
 			fs.PrintDefaults()
 		}
 		tileNow := fs.Bool("tile", false, "Tile immediately")
+		allMonitors := fs.Bool("all-monitors", false, "Tile every monitor immediately (implies --tile)")
+		fill := fs.Bool("fill", false, "Spawn terminals until the layout's grid is full (implies --tile)")
 		if err := fs.Parse(args[1:]); err != nil {
 			if err == flag.ErrHelp {
 				return 0
@@ -271,7 +328,22 @@ func runLayout(args []string) int {
 			fs.Usage()
 			return 2
 		}
-		if err := client.ApplyLayout(fs.Arg(0), *tileNow); err != nil {
+		if *fill {
+			if rc := fillLayout(client, fs.Arg(0)); rc != 0 {
+				return rc
+			}
+		}
+		var err error
+		if *allMonitors {
+			err = client.ApplyLayoutOnMonitors(fs.Arg(0), true)
+		} else {
+			err = client.ApplyLayout(fs.Arg(0), *tileNow || *fill)
+		}
+		if errors.Is(err, tiling.ErrNoTerminals) {
+			fmt.Println(err)
+			return 0
+		}
+		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			return 1
 		}
@@ -300,30 +372,87 @@ func runLayout(args []string) int {
 			fs.Usage()
 			return 2
 		}
-		if err := client.SetDefaultLayout(fs.Arg(0), *tileNow); err != nil {
+		if err := client.SetDefaultLayout(fs.Arg(0), *tileNow); errors.Is(err, tiling.ErrNoTerminals) {
+			fmt.Println(err)
+		} else if err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			return 1
 		}
 		return 0
 
+	case "delete":
+		return runLayoutDelete(args[1:])
+
 	case "preview":
 		fs := flag.NewFlagSet("preview", flag.ContinueOnError)
 		fs.SetOutput(os.Stderr)
 		fs.Usage = func() {
 			fmt.Fprintln(os.Stderr, "Usage: termtile layout preview [--duration N] <layout>")
+			fmt.Fprintln(os.Stderr, "       termtile layout preview [--duration N] --from-file PATH")
+			fmt.Fprintln(os.Stderr, "       termtile layout preview [--duration N] --cycle LAYOUT,LAYOUT[,...]")
 			fmt.Fprintln(os.Stderr, "")
 			fmt.Fprintln(os.Stderr, "Temporarily apply a layout and restore after a duration.")
+			fmt.Fprintln(os.Stderr, "With --from-file, preview a single layout definition (YAML, as under")
+			fmt.Fprintln(os.Stderr, "layouts.<name>) without adding it to the config. Use - for stdin.")
+			fmt.Fprintln(os.Stderr, "With --cycle, preview each listed layout in turn for the duration,")
+			fmt.Fprintln(os.Stderr, "restoring in between, and return once the last one is restored.")
 			fmt.Fprintln(os.Stderr, "")
 			fmt.Fprintln(os.Stderr, "Flags:")
 			fs.PrintDefaults()
 		}
 		durationSeconds := fs.Int("duration", 3, "Preview duration in seconds")
+		fromFile := fs.String("from-file", "", "Preview a layout definition read from `PATH` (- for stdin)")
+		cycle := fs.String("cycle", "", "Preview a comma-separated list of `LAYOUTS` one after another")
 		if err := fs.Parse(args[1:]); err != nil {
 			if err == flag.ErrHelp {
 				return 0
 			}
 			return 2
 		}
+		if *cycle != "" {
+			if fs.NArg() > 0 || *fromFile != "" {
+				fmt.Fprintln(os.Stderr, "layout preview: --cycle cannot be combined with <layout> or --from-file")
+				fs.Usage()
+				return 2
+			}
+			names, err := parsePreviewCycle(*cycle)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "layout preview:", err)
+				return 2
+			}
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			err = cycleLayoutPreviews(ctx, names, ipc.PreviewDuration(*durationSeconds), func(name string) error {
+				return client.PreviewLayout(name, *durationSeconds)
+			}, func(name string) {
+				fmt.Printf("previewing %s\n", name)
+			})
+			if errors.Is(err, context.Canceled) {
+				return 0
+			}
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+			return 0
+		}
+		if *fromFile != "" {
+			if fs.NArg() > 0 {
+				fmt.Fprintln(os.Stderr, "layout preview: --from-file cannot be combined with <layout>")
+				fs.Usage()
+				return 2
+			}
+			layout, err := readLayoutDefinition(*fromFile, os.Stdin)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+			if err := client.PreviewLayoutDefinition(layout, *durationSeconds); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+			return 0
+		}
 		if fs.NArg() < 1 {
 			fmt.Fprintln(os.Stderr, "layout preview requires <layout>")
 			fs.Usage()
@@ -342,14 +471,38 @@ func runLayout(args []string) int {
 	}
 }
 
+// readLayoutDefinition reads and validates a standalone layout definition
+// from path, or from stdin when path is "-".
+func readLayoutDefinition(path string, stdin io.Reader) (config.Layout, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return config.Layout{}, fmt.Errorf("failed to read layout definition: %w", err)
+	}
+	return config.ParseLayout(data)
+}
+
 type layoutJSON struct {
-	Name              string         `json:"name"`
-	Mode              string         `json:"mode"`
-	TileRegion        tileRegionJSON `json:"tile_region"`
-	FixedGrid         fixedGridJSON  `json:"fixed_grid,omitempty"`
-	MaxTerminalWidth  int            `json:"max_terminal_width"`
-	MaxTerminalHeight int            `json:"max_terminal_height"`
-	FlexibleLastRow   bool           `json:"flexible_last_row"`
+	Name              string          `json:"name"`
+	Mode              string          `json:"mode"`
+	TileRegion        tileRegionJSON  `json:"tile_region"`
+	FixedGrid         fixedGridJSON   `json:"fixed_grid,omitempty"`
+	MaxTerminalWidth  int             `json:"max_terminal_width"`
+	MaxTerminalHeight int             `json:"max_terminal_height"`
+	FlexibleLastRow   bool            `json:"flexible_last_row"`
+	SingleWindowNoGap bool            `json:"single_window_no_gap,omitempty"`
+	Order             string          `json:"order,omitempty"`
+	RemainderBias     string          `json:"remainder_bias,omitempty"`
+	Aliases           []string        `json:"aliases,omitempty"`
+	Capacity          int             `json:"capacity"` // 0 = no limit
+	Grid              *layoutGridJSON `json:"grid,omitempty"`
 }
 
 type tileRegionJSON struct {
@@ -365,7 +518,100 @@ type fixedGridJSON struct {
 	Cols int `json:"cols"`
 }
 
-func layoutListJSON() int {
+// runLayoutDelete removes a user layout from config.yaml and reloads a
+// running daemon so it stops offering the layout.
+func runLayoutDelete(args []string) int {
+	fs := flag.NewFlagSet("delete", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: termtile layout delete <layout>")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Remove a user layout from the config file. Built-in layouts and the")
+		fmt.Fprintln(os.Stderr, "default_layout cannot be deleted.")
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "layout delete requires <layout>")
+		fs.Usage()
+		return 2
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	name, err := cfg.DeleteLayout(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := cfg.Save(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	client := ipc.NewClient()
+	if client.Ping() == nil {
+		if err := client.Reload(); err != nil {
+			warnf("daemon reload failed: %v", err)
+		}
+	}
+	fmt.Printf("Deleted layout %q\n", name)
+	return 0
+}
+
+// fillLayout makes layoutName active and spawns terminals into the workspace
+// on the current desktop until the layout's capacity is reached. The spawns
+// go through one batched `terminal add -n`, so the workspace's class, cwd,
+// agent mode and limits are honoured and the layout is re-tiled once.
+func fillLayout(client *ipc.Client, layoutName string) int {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	layout, err := cfg.GetLayout(layoutName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	layoutName, _ = cfg.ResolveLayoutName(layoutName)
+
+	wsInfo, err := workspace.GetActiveWorkspace()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if wsInfo.Name == "" {
+		fmt.Fprintln(os.Stderr, "--fill requires a workspace on the current desktop")
+		return 1
+	}
+
+	missing := tiling.FillCount(layout, wsInfo.TerminalCount)
+	if missing == 0 {
+		if tiling.LayoutCapacity(layout) == 0 {
+			fmt.Printf("Layout %q has no fixed capacity; nothing to fill\n", layoutName)
+		} else {
+			fmt.Printf("Workspace %q already fills layout %q\n", wsInfo.Name, layoutName)
+		}
+		return 0
+	}
+
+	// Activate the layout first so `terminal add` retiles into it.
+	if err := client.ApplyLayout(layoutName, false); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return runTerminalAdd([]string{"-n", strconv.Itoa(missing)})
+}
+
+func layoutListJSON(count int) int {
 	res, err := config.LoadWithSources()
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -387,6 +633,10 @@ func layoutListJSON() int {
 			MaxTerminalWidth:  l.MaxTerminalWidth,
 			MaxTerminalHeight: l.MaxTerminalHeight,
 			FlexibleLastRow:   l.FlexibleLastRow,
+			SingleWindowNoGap: l.SingleWindowNoGap,
+			Order:             string(l.Order),
+			RemainderBias:     string(l.RemainderBias),
+			Aliases:           l.Aliases,
 			TileRegion: tileRegionJSON{
 				Type:          string(l.TileRegion.Type),
 				XPercent:      l.TileRegion.XPercent,
@@ -398,6 +648,7 @@ func layoutListJSON() int {
 		if l.Mode == config.LayoutModeFixed {
 			entry.FixedGrid = fixedGridJSON{Rows: l.FixedGrid.Rows, Cols: l.FixedGrid.Cols}
 		}
+		entry.Capacity, entry.Grid = layoutCapacityJSON(&l, count)
 		layouts = append(layouts, entry)
 	}
 
@@ -413,9 +664,10 @@ func layoutListJSON() int {
 func runConfig(args []string) int {
 	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
 		fmt.Fprintln(os.Stderr, "Usage:")
-		fmt.Fprintln(os.Stderr, "  termtile config validate [--path PATH]")
+		fmt.Fprintln(os.Stderr, "  termtile config validate [--path PATH] [--strict]")
 		fmt.Fprintln(os.Stderr, "  termtile config print [--path PATH] [--effective|--defaults]")
-		fmt.Fprintln(os.Stderr, "  termtile config explain [--path PATH] <yaml.path>")
+		fmt.Fprintln(os.Stderr, "  termtile config explain [--path PATH | --from-daemon] <yaml.path>")
+		fmt.Fprintln(os.Stderr, "  termtile config schema")
 		return 2
 	}
 
@@ -424,20 +676,26 @@ func runConfig(args []string) int {
 		fs := flag.NewFlagSet("validate", flag.ContinueOnError)
 		fs.SetOutput(os.Stderr)
 		path := fs.String("path", "", "Config file path (default: ~/.config/termtile/config.yaml)")
+		strict := fs.Bool("strict", false, "Treat warnings as errors")
 		if err := fs.Parse(args[1:]); err != nil {
 			return 2
 		}
 
+		var res *config.LoadResult
 		var err error
 		if *path == "" {
-			_, err = config.LoadWithSources()
+			res, err = config.LoadWithSources()
 		} else {
-			_, err = config.LoadFromPath(*path)
+			res, err = config.LoadFromPath(*path)
 		}
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			return 1
 		}
+		if *strict && len(res.Warnings) > 0 {
+			fmt.Fprintf(os.Stderr, "config: %d warning(s) in strict mode\n", len(res.Warnings))
+			return 1
+		}
 		fmt.Println("config: ok")
 		return 0
 
@@ -492,6 +750,7 @@ func runConfig(args []string) int {
 		fs := flag.NewFlagSet("explain", flag.ContinueOnError)
 		fs.SetOutput(os.Stderr)
 		path := fs.String("path", "", "Config file path (default: ~/.config/termtile/config.yaml)")
+		fromDaemon := fs.Bool("from-daemon", false, "Explain the running daemon's live config instead of reading from disk")
 		if err := fs.Parse(args[1:]); err != nil {
 			return 2
 		}
@@ -501,6 +760,22 @@ func runConfig(args []string) int {
 		}
 		queryPath := fs.Arg(0)
 
+		if *fromDaemon {
+			if *path != "" {
+				fmt.Fprintln(os.Stderr, "--from-daemon cannot be combined with --path")
+				return 2
+			}
+			data, err := ipc.NewClient().ExplainValue(queryPath)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+			fmt.Printf("path: %s\n", data.Path)
+			fmt.Printf("source: %s\n", formatSource(data.Source))
+			fmt.Printf("value:\n%s", data.Value)
+			return 0
+		}
+
 		var res *config.LoadResult
 		var err error
 		if *path == "" {
@@ -530,6 +805,25 @@ func runConfig(args []string) int {
 		fmt.Printf("value:\n%s", string(out))
 		return 0
 
+	case "schema":
+		fs := flag.NewFlagSet("schema", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+		if fs.NArg() > 0 {
+			fmt.Fprintln(os.Stderr, "schema takes no arguments")
+			return 2
+		}
+
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(config.Schema()); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown config subcommand: %s\n", args[0])
 		return 2
@@ -598,9 +892,18 @@ func formatSource(src config.Source) string {
 	}
 }
 
+// moveMultiplexer returns the multiplexer move callbacks rename sessions
+// with; tests swap it for a fake.
+var moveMultiplexer = terminalMultiplexer
+
 // handleMoveComplete is called after a window move/swap operation completes.
-// It renames tmux sessions to match the new slot positions.
+// It renames tmux sessions to match the new slot positions. Undo calls it
+// again with result.Reverse() to put them back.
 func handleMoveComplete(result movemode.MoveResult) {
+	if result.KeepSessions {
+		return
+	}
+
 	// Get current workspace info
 	wsInfo, err := workspace.GetActiveWorkspace()
 	if err != nil || wsInfo.Name == "" {
@@ -611,66 +914,61 @@ func handleMoveComplete(result movemode.MoveResult) {
 		return // Not agent mode, nothing to rename
 	}
 
-	tmux := agent.NewTmuxMultiplexer()
+	tmux := moveMultiplexer()
 
-	// Build session names
-	sourceSession := agent.SessionName(wsInfo.Name, result.SourceSlot)
-	targetSession := agent.SessionName(wsInfo.Name, result.TargetSlot)
+	// Resolve session names through the agent meta so session_name
+	// overrides are found. Derived names follow the slot; overrides are kept.
+	sourceSession := mcp.SlotSessionName(wsInfo.Name, result.SourceSlot)
+	targetSession := mcp.SlotSessionName(wsInfo.Name, result.TargetSlot)
+	newSourceSession := retargetSession(sourceSession, wsInfo.Name, result.TargetSlot)
+	newTargetSession := retargetSession(targetSession, wsInfo.Name, result.SourceSlot)
 
 	// Check which sessions exist
 	sourceExists, _ := tmux.HasSession(sourceSession)
 	targetExists, _ := tmux.HasSession(targetSession)
 
 	if result.IsSwap {
-		// Both terminals swapped positions - need to rename both sessions
-		// Use a temporary name to avoid collision
-		if sourceExists && targetExists {
-			tempSession := agent.SessionName(wsInfo.Name, -9999) // Temporary name
+		// Both terminals swapped positions. Park the source session under a
+		// temporary name so the renames cannot collide.
+		tempSession := agent.SessionName(wsInfo.Name, -9999)
+		moveSource := sourceExists && sourceSession != newSourceSession
+		moveTarget := targetExists && targetSession != newTargetSession
 
-			// Rename source -> temp
+		if moveSource {
 			if err := tmux.RenameSession(sourceSession, tempSession); err != nil {
 				log.Printf("Move callback: failed to rename %s to temp: %v", sourceSession, err)
 				return
 			}
-
-			// Rename target -> source
-			if err := tmux.RenameSession(targetSession, sourceSession); err != nil {
-				log.Printf("Move callback: failed to rename %s to %s: %v", targetSession, sourceSession, err)
+		}
+		if moveTarget {
+			if err := tmux.RenameSession(targetSession, newTargetSession); err != nil {
+				log.Printf("Move callback: failed to rename %s to %s: %v", targetSession, newTargetSession, err)
 				// Try to restore
-				_ = tmux.RenameSession(tempSession, sourceSession)
+				if moveSource {
+					_ = tmux.RenameSession(tempSession, sourceSession)
+				}
 				return
 			}
-
-			// Rename temp -> target
-			if err := tmux.RenameSession(tempSession, targetSession); err != nil {
-				log.Printf("Move callback: failed to rename temp to %s: %v", targetSession, err)
+		}
+		if moveSource {
+			if err := tmux.RenameSession(tempSession, newSourceSession); err != nil {
+				log.Printf("Move callback: failed to rename temp to %s: %v", newSourceSession, err)
 				return
 			}
-
+		}
+		if moveSource || moveTarget {
 			log.Printf("Move callback: swapped sessions %s <-> %s", sourceSession, targetSession)
-		} else if sourceExists {
-			// Only source exists, move it to target
-			if err := tmux.RenameSession(sourceSession, targetSession); err != nil {
-				log.Printf("Move callback: failed to rename %s to %s: %v", sourceSession, targetSession, err)
-			} else {
-				log.Printf("Move callback: renamed %s -> %s", sourceSession, targetSession)
-			}
-		} else if targetExists {
-			// Only target exists, move it to source
-			if err := tmux.RenameSession(targetSession, sourceSession); err != nil {
-				log.Printf("Move callback: failed to rename %s to %s: %v", targetSession, sourceSession, err)
-			} else {
-				log.Printf("Move callback: renamed %s -> %s", targetSession, sourceSession)
-			}
+		}
+
+		if err := mcp.SwapArtifactDirs(wsInfo.Name, result.SourceSlot, result.TargetSlot); err != nil {
+			log.Printf("Move callback: failed to swap agent artifacts: %v", err)
 		}
 	} else {
-		// Move to empty slot - just rename the session
-		if sourceExists {
-			if err := tmux.RenameSession(sourceSession, targetSession); err != nil {
-				log.Printf("Move callback: failed to rename %s to %s: %v", sourceSession, targetSession, err)
-			} else {
-				log.Printf("Move callback: renamed %s -> %s", sourceSession, targetSession)
-			}
+		// Move to empty slot - rename the session and move its artifacts
+		if err := shiftSlotSession(tmux, wsInfo.Name, result.SourceSlot, wsInfo.Name, result.TargetSlot); err != nil {
+			log.Printf("Move callback: %v", err)
+		} else if sourceExists && sourceSession != newSourceSession {
+			log.Printf("Move callback: renamed %s -> %s", sourceSession, newSourceSession)
 		}
 	}
 
@@ -680,9 +978,9 @@ func handleMoveComplete(result movemode.MoveResult) {
 		// Update session names in config
 		for i := range wsCfg.Terminals {
 			if wsCfg.Terminals[i].SlotIndex == result.SourceSlot {
-				wsCfg.Terminals[i].SessionName = targetSession
+				wsCfg.Terminals[i].SessionName = newSourceSession
 			} else if result.IsSwap && wsCfg.Terminals[i].SlotIndex == result.TargetSlot {
-				wsCfg.Terminals[i].SessionName = sourceSession
+				wsCfg.Terminals[i].SessionName = newTargetSession
 			}
 		}
 
@@ -711,12 +1009,23 @@ func handleMoveComplete(result movemode.MoveResult) {
 	}
 }
 
+// logHotkeyRegisterError logs a hotkey that failed to register. Conflicts
+// are left to the consolidated report logged once registration is done.
+func logHotkeyRegisterError(name string, err error) {
+	var conflict *hotkeys.ConflictError
+	if errors.As(err, &conflict) {
+		return
+	}
+	log.Printf("Warning: Failed to register %s: %v", name, err)
+}
+
 func runDaemon() {
-	// Load configuration
-	cfg, err := config.Load()
+	// Load configuration, keeping sources for EXPLAIN_VALUE.
+	cfgRes, err := config.LoadWithSources()
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
+	cfg := cfgRes.Config
 	log.Printf("Configuration loaded (hotkey: %s, gap: %dpx)", cfg.Hotkey, cfg.GapSize)
 
 	// Connect to display server
@@ -729,11 +1038,26 @@ func runDaemon() {
 	log.Println("termtile daemon started successfully")
 
 	// Create terminal detector
-	detector := terminals.NewDetector(cfg.TerminalClassNames())
+	detector := terminals.NewMatchDetector(terminalClassMatches(cfg))
 	log.Printf("Terminal detector initialized with %d terminal classes", len(cfg.TerminalClasses))
 
+	// Optionally record every tiler MoveResize for `termtile replay`.
+	var tilerBackend platform.Backend = backend
+	if recordPath := os.Getenv(platform.RecordEnvVar); recordPath != "" {
+		f, err := os.OpenFile(recordPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			log.Fatalf("Failed to open %s file: %v", platform.RecordEnvVar, err)
+		}
+		defer f.Close()
+		tilerBackend = platform.NewRecordingBackend(backend, f)
+		log.Printf("Recording tiling session to %s", recordPath)
+	}
+
 	// Create tiler
-	tiler := tiling.NewTiler(backend, detector, cfg)
+	tiler := tiling.NewTiler(tilerBackend, detector, cfg)
+	if cfg.PersistActiveLayout {
+		restoreActiveLayout(tiler)
+	}
 	log.Println("Tiler initialized")
 
 	// Setup hotkey handler
@@ -746,15 +1070,19 @@ func runDaemon() {
 	moveModeCtrl := movemode.NewMode(backend, detector, cfg, tiler)
 	hotkeyHandler.SetMoveMode(moveModeCtrl)
 
-	// Wire up callback to rename tmux sessions after window moves
+	// Wire up callback to rename tmux sessions after window moves, and make
+	// the move what undo reverts: geometry first, then the session renames.
 	moveModeCtrl.OnMoveComplete = func(result movemode.MoveResult) {
 		handleMoveComplete(result)
+		tiler.RecordMove(result.DisplayID, result.Previous, func() {
+			handleMoveComplete(result.Reverse())
+		})
 	}
 
 	// Register move mode hotkey if configured
 	if cfg.MoveModeHotkey != "" {
 		if err := hotkeyHandler.RegisterMoveMode(cfg.MoveModeHotkey); err != nil {
-			log.Printf("Warning: Failed to register move mode hotkey: %v", err)
+			logHotkeyRegisterError("move mode hotkey", err)
 		} else {
 			log.Printf("Move mode hotkey registered: %s", cfg.MoveModeHotkey)
 		}
@@ -762,7 +1090,7 @@ func runDaemon() {
 
 	// Register terminal-add hotkey if configured.
 	if cfg.TerminalAddHotkey != "" {
-		if err := hotkeyHandler.RegisterFunc(cfg.TerminalAddHotkey, func() {
+		if err := hotkeyHandler.RegisterFunc("terminal_add_hotkey", cfg.TerminalAddHotkey, func() {
 			wsInfo, err := workspace.GetActiveWorkspace()
 			if err != nil {
 				log.Printf("Terminal-add hotkey: failed to resolve active workspace: %v", err)
@@ -791,7 +1119,7 @@ func runDaemon() {
 				}
 			}()
 		}); err != nil {
-			log.Printf("Warning: Failed to register terminal add hotkey: %v", err)
+			logHotkeyRegisterError("terminal add hotkey", err)
 		} else {
 			log.Printf("Terminal add hotkey registered: %s", cfg.TerminalAddHotkey)
 		}
@@ -799,7 +1127,7 @@ func runDaemon() {
 
 	// Register palette hotkey if configured
 	if cfg.PaletteHotkey != "" {
-		if err := hotkeyHandler.RegisterFunc(cfg.PaletteHotkey, func() {
+		if err := hotkeyHandler.RegisterFunc("palette_hotkey", cfg.PaletteHotkey, func() {
 			exe, err := os.Executable()
 			if err != nil {
 				log.Printf("Palette: failed to find executable: %v", err)
@@ -813,7 +1141,7 @@ func runDaemon() {
 			}
 			go cmd.Wait()
 		}); err != nil {
-			log.Printf("Warning: Failed to register palette hotkey: %v", err)
+			logHotkeyRegisterError("palette hotkey", err)
 		} else {
 			log.Printf("Palette hotkey registered: %s", cfg.PaletteHotkey)
 		}
@@ -821,88 +1149,161 @@ func runDaemon() {
 
 	// Optional: Cycle layouts without editing config.
 	if cfg.CycleLayoutHotkey != "" {
-		if err := hotkeyHandler.RegisterFunc(cfg.CycleLayoutHotkey, func() {
+		if err := hotkeyHandler.RegisterFunc("cycle_layout_hotkey", cfg.CycleLayoutHotkey, func() {
 			name, err := tiler.CycleActiveLayout(1)
 			if err != nil {
 				log.Printf("Failed to cycle layout: %v", err)
 				return
 			}
 			log.Printf("Switched to layout: %s", name)
-			if err := tiler.TileCurrentMonitor(); err != nil {
+			if err := tiler.TileCurrentMonitor(); err != nil && !errors.Is(err, tiling.ErrNoTerminals) {
 				log.Printf("Tiling failed: %v", err)
 			}
 		}); err != nil {
-			log.Printf("Warning: Failed to register cycle_layout_hotkey: %v", err)
+			logHotkeyRegisterError("cycle_layout_hotkey", err)
 		}
 	}
 	if cfg.CycleLayoutReverseHotkey != "" {
-		if err := hotkeyHandler.RegisterFunc(cfg.CycleLayoutReverseHotkey, func() {
+		if err := hotkeyHandler.RegisterFunc("cycle_layout_reverse_hotkey", cfg.CycleLayoutReverseHotkey, func() {
 			name, err := tiler.CycleActiveLayout(-1)
 			if err != nil {
 				log.Printf("Failed to cycle layout: %v", err)
 				return
 			}
 			log.Printf("Switched to layout: %s", name)
-			if err := tiler.TileCurrentMonitor(); err != nil {
+			if err := tiler.TileCurrentMonitor(); err != nil && !errors.Is(err, tiling.ErrNoTerminals) {
 				log.Printf("Tiling failed: %v", err)
 			}
 		}); err != nil {
-			log.Printf("Warning: Failed to register cycle_layout_reverse_hotkey: %v", err)
+			logHotkeyRegisterError("cycle_layout_reverse_hotkey", err)
 		}
 	}
 
 	// Optional: Restore previous terminal geometry.
 	if cfg.UndoHotkey != "" {
-		if err := hotkeyHandler.RegisterFunc(cfg.UndoHotkey, func() {
+		if err := hotkeyHandler.RegisterFunc("undo_hotkey", cfg.UndoHotkey, func() {
 			if err := tiler.UndoCurrentMonitor(); err != nil {
 				log.Printf("Undo failed: %v", err)
 			}
 		}); err != nil {
-			log.Printf("Warning: Failed to register undo_hotkey: %v", err)
+			logHotkeyRegisterError("undo_hotkey", err)
 		}
 	}
 
-	// Create config reload channel
-	reloadChan := make(chan struct{}, 1)
+	// Optional: Toggle the focused terminal to fill the tile region.
+	if cfg.MonocleHotkey != "" {
+		if err := hotkeyHandler.RegisterFunc("monocle_hotkey", cfg.MonocleHotkey, func() {
+			if err := tiler.ToggleMonocleCurrentMonitor(); err != nil && !errors.Is(err, tiling.ErrNoTerminals) {
+				log.Printf("Monocle toggle failed: %v", err)
+			}
+		}); err != nil {
+			logHotkeyRegisterError("monocle_hotkey", err)
+		}
+	}
 
-	// Start IPC server
-	ipcServer, err := ipc.NewServer(cfg, tiler, backend, reloadChan)
-	if err != nil {
-		log.Fatalf("Failed to create IPC server: %v", err)
+	// Optional: Per-layout hotkeys, e.g. "Mod4-space g" for grid.
+	layoutHotkeyNames := make([]string, 0, len(cfg.LayoutHotkeys))
+	for name := range cfg.LayoutHotkeys {
+		layoutHotkeyNames = append(layoutHotkeyNames, name)
 	}
-	if err := ipcServer.Start(); err != nil {
-		log.Fatalf("Failed to start IPC server: %v", err)
+	sort.Strings(layoutHotkeyNames)
+	for _, name := range layoutHotkeyNames {
+		layoutName := name
+		hotkey := cfg.LayoutHotkeys[layoutName]
+		if err := hotkeyHandler.RegisterFunc("layout_hotkeys."+layoutName, hotkey, func() {
+			if err := tiler.SetActiveLayout(layoutName); err != nil {
+				log.Printf("Failed to switch layout: %v", err)
+				return
+			}
+			log.Printf("Switched to layout: %s", layoutName)
+			if err := tiler.TileCurrentMonitor(); err != nil && !errors.Is(err, tiling.ErrNoTerminals) {
+				log.Printf("Tiling failed: %v", err)
+			}
+		}); err != nil {
+			logHotkeyRegisterError("layout_hotkeys."+layoutName, err)
+		}
+	}
+
+	if conflicts := hotkeyHandler.Conflicts(); len(conflicts) > 0 {
+		log.Printf("Warning: %d hotkey conflict(s); the later binding was skipped:", len(conflicts))
+		for _, c := range conflicts {
+			log.Printf("  %v", c)
+		}
 	}
-	defer ipcServer.Stop()
 
 	// Setup state synchronizer and reconciler
 	syncLogger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
 		Level: slog.LevelInfo,
 	}))
-	stateSynchronizer := daemon.NewStateSynchronizer(syncLogger)
+	stateSynchronizer := daemon.NewStateSynchronizer(agent.MultiplexerFor(cfg), syncLogger)
+	stateSynchronizer.KeepSessions(mcp.IsDetachedSession)
 
 	// Create window lister function for reconciler
 	windowLister := daemon.WindowListerFromBackend(backend)
 
+	// With run_on_start the reconciler's first pass cleans stale workspace
+	// entries from a previous daemon lifecycle. It runs before the IPC
+	// server starts so no client sees them.
 	reconciler := daemon.NewReconciler(daemon.ReconcilerConfig{
-		Interval:        10 * time.Second,
+		Interval:        cfg.Reconciler.GetInterval(),
 		CleanupOrphaned: true,
+		RunOnStart:      cfg.Reconciler.GetRunOnStart(),
+		OrphanGrace:     cfg.Reconciler.GetOrphanGrace(),
 		Logger:          syncLogger,
 	}, stateSynchronizer, windowLister)
 
-	// Run an immediate reconciliation pass on startup to clean stale
-	// workspace entries from a previous daemon lifecycle.
-	reconciler.ReconcileNow()
-
-	// Start reconciler in background
 	reconcilerCtx, reconcilerCancel := context.WithCancel(context.Background())
 	defer reconcilerCancel()
-	go reconciler.Run(reconcilerCtx)
+	reconciler.Start(reconcilerCtx)
+
+	// Create config reload channel
+	reloadChan := make(chan struct{}, 1)
+
+	// Start IPC server
+	ipcServer, err := ipc.NewServer(cfg, tiler, backend, reloadChan)
+	if err != nil {
+		log.Fatalf("Failed to create IPC server: %v", err)
+	}
+	ipcServer.UpdateLoadResult(cfgRes)
+	if err := ipcServer.Start(); err != nil {
+		log.Fatalf("Failed to start IPC server: %v", err)
+	}
+	defer ipcServer.Stop()
+
+	// Feed tiler changes, moves and active-workspace changes to
+	// `termtile events` subscribers, and save layout changes for
+	// persist_active_layout.
+	layoutPersister := daemon.NewLayoutPersister(cfg.PersistActiveLayout, syncLogger)
+	tiler.SetEventHandler(func(ev tiling.Event) {
+		if ev.Kind == tiling.EventLayoutChanged {
+			layoutPersister.Save(ev.Layout)
+		}
+		ipcServer.PublishTilingEvent(ev)
+	})
+	onMoveComplete := moveModeCtrl.OnMoveComplete
+	moveModeCtrl.OnMoveComplete = func(result movemode.MoveResult) {
+		onMoveComplete(result)
+		ipcServer.Publish(moveCompletedEvent(result))
+	}
+	eventsCtx, eventsCancel := context.WithCancel(context.Background())
+	defer eventsCancel()
+	go watchActiveWorkspace(eventsCtx, ipcServer)
+	go layoutPersister.Run(eventsCtx)
+	go moveModeCtrl.RunAnnouncements(eventsCtx)
 
 	// Setup signal handlers
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
 
+	// shutdown is the clean exit path for SIGTERM, SIGINT and IPC SHUTDOWN.
+	shutdown := func() {
+		log.Println("Shutting down termtile daemon...")
+		reconcilerCancel()
+		eventsCancel()
+		ipcServer.Stop()
+		os.Exit(0)
+	}
+
 	// Handle signals and config reloads
 	go func() {
 		for {
@@ -911,20 +1312,22 @@ func runDaemon() {
 				switch sig {
 				case syscall.SIGHUP:
 					log.Println("Received SIGHUP, reloading config...")
-					newCfg, err := config.Load()
+					newRes, err := config.LoadWithSources()
 					if err != nil {
 						log.Printf("Config reload failed: %v", err)
 						continue
 					}
+					newCfg := newRes.Config
 
 					// Update config in IPC server
-					ipcServer.UpdateConfig(newCfg)
+					ipcServer.UpdateLoadResult(newRes)
 
 					// Update tiler config
+					layoutPersister.SetEnabled(newCfg.PersistActiveLayout, tiler.GetActiveLayoutName())
 					tiler.UpdateConfig(newCfg)
 
 					// Update detector terminal classes
-					detector.UpdateTerminalClasses(newCfg.TerminalClassNames())
+					detector.UpdateMatches(terminalClassMatches(newCfg))
 
 					// Update move mode config
 					moveModeCtrl.UpdateConfig(newCfg)
@@ -932,17 +1335,18 @@ func runDaemon() {
 					log.Println("Config reloaded successfully")
 
 				case os.Interrupt, syscall.SIGTERM:
-					log.Println("Shutting down termtile daemon...")
-					reconcilerCancel()
-					ipcServer.Stop()
-					os.Exit(0)
+					shutdown()
 				}
 
+			case <-ipcServer.ShutdownRequested():
+				shutdown()
+
 			case <-reloadChan:
 				// Config was reloaded via IPC, update components
 				newCfg := ipcServer.GetConfig()
+				layoutPersister.SetEnabled(newCfg.PersistActiveLayout, tiler.GetActiveLayoutName())
 				tiler.UpdateConfig(newCfg)
-				detector.UpdateTerminalClasses(newCfg.TerminalClassNames())
+				detector.UpdateMatches(terminalClassMatches(newCfg))
 				moveModeCtrl.UpdateConfig(newCfg)
 			}
 		}
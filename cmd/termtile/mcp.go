@@ -2,13 +2,11 @@ package main
 
 import (
 	"context"
-	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
-	"os/exec"
 	"os/signal"
 	"strconv"
 	"strings"
@@ -25,7 +23,7 @@ func printMCPUsage(w io.Writer) {
 	fmt.Fprintln(w, "")
 	fmt.Fprintln(w, "Commands:")
 	fmt.Fprintln(w, "  serve    Start the MCP server (stdio transport)")
-	fmt.Fprintln(w, "  cleanup  List and optionally kill orphaned termtile tmux sessions")
+	fmt.Fprintln(w, "  cleanup  List and optionally kill orphaned termtile multiplexer sessions")
 	fmt.Fprintln(w, "")
 	fmt.Fprintln(w, "Run 'termtile mcp <command> --help' for command-specific options.")
 }
@@ -104,7 +102,7 @@ func runMCPCleanup(args []string) int {
 	fs.Usage = func() {
 		fmt.Fprintln(os.Stderr, "Usage: termtile mcp cleanup [--force]")
 		fmt.Fprintln(os.Stderr, "")
-		fmt.Fprintln(os.Stderr, "List termtile tmux sessions and identify tracked vs orphan sessions.")
+		fmt.Fprintln(os.Stderr, "List termtile multiplexer sessions and identify tracked vs orphan sessions.")
 		fmt.Fprintln(os.Stderr, "Use --force to kill only orphan sessions.")
 		fmt.Fprintln(os.Stderr, "")
 		fmt.Fprintln(os.Stderr, "Flags:")
@@ -118,26 +116,22 @@ func runMCPCleanup(args []string) int {
 		return 2
 	}
 
-	out, err := exec.Command("tmux", "list-sessions", "-F", "#{session_name}").Output()
+	mux := terminalMultiplexer()
+	names, err := mux.ListSessions()
 	if err != nil {
-		var exitErr *exec.ExitError
-		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
-			fmt.Fprintln(os.Stdout, "No termtile tmux sessions found.")
-			return 0
-		}
-		fmt.Fprintf(os.Stderr, "failed to list tmux sessions: %v\n", err)
+		fmt.Fprintf(os.Stderr, "failed to list %s sessions: %v\n", mux.Name(), err)
 		return 1
 	}
 
 	var sessions []mcpCleanupSession
-	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
-		sessionName := strings.TrimSpace(line)
+	for _, name := range names {
+		sessionName := strings.TrimSpace(name)
 		if sessionName == "" || !strings.HasPrefix(sessionName, "termtile-") {
 			continue
 		}
 
 		wsName, slot, slotValid := parseTermtileSessionName(sessionName)
-		alive := exec.Command("tmux", "has-session", "-t", sessionName).Run() == nil
+		alive, _ := mux.HasSession(sessionName)
 		tracked := workspace.HasSessionInRegistry(sessionName)
 		sessions = append(sessions, mcpCleanupSession{
 			name:      sessionName,
@@ -150,11 +144,11 @@ func runMCPCleanup(args []string) int {
 	}
 
 	if len(sessions) == 0 {
-		fmt.Fprintln(os.Stdout, "No termtile tmux sessions found.")
+		fmt.Fprintf(os.Stdout, "No termtile %s sessions found.\n", mux.Name())
 		return 0
 	}
 
-	fmt.Fprintln(os.Stdout, "Discovered termtile tmux sessions:")
+	fmt.Fprintf(os.Stdout, "Discovered termtile %s sessions:\n", mux.Name())
 	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 	fmt.Fprintln(tw, "SESSION\tWORKSPACE\tSLOT\tSTATUS\tALIVE")
 	orphanCount := 0
@@ -194,7 +188,7 @@ func runMCPCleanup(args []string) int {
 		if session.tracked || !session.alive {
 			continue
 		}
-		if err := exec.Command("tmux", "kill-session", "-t", session.name).Run(); err != nil {
+		if err := mux.KillSession(session.name); err != nil {
 			fmt.Fprintf(os.Stderr, "failed to kill session %q: %v\n", session.name, err)
 			return 1
 		}
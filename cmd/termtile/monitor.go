@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/1broseidon/termtile/internal/platform"
+)
+
+// MonitorInfo describes a monitor for `termtile monitor list`.
+type MonitorInfo struct {
+	Index  int         `json:"index"`
+	ID     int         `json:"id"`
+	Name   string      `json:"name"`
+	Active bool        `json:"active"`
+	Bounds MonitorRect `json:"bounds"`
+	Usable MonitorRect `json:"usable"`
+}
+
+// MonitorRect is a monitor geometry in screen coordinates.
+type MonitorRect struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+func monitorRect(r platform.Rect) MonitorRect {
+	return MonitorRect{X: r.X, Y: r.Y, Width: r.Width, Height: r.Height}
+}
+
+func runMonitor(args []string) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		fmt.Fprintln(os.Stderr, "Usage:")
+		fmt.Fprintln(os.Stderr, "  termtile monitor list [--json]  List connected monitors")
+		return 2
+	}
+
+	switch args[0] {
+	case "list":
+		return runMonitorList(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown monitor subcommand: %s\n", args[0])
+		return 2
+	}
+}
+
+func runMonitorList(args []string) int {
+	fs := flag.NewFlagSet("monitor list", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	jsonOut := fs.Bool("json", false, "Output JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: termtile monitor list [--json]")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Lists connected monitors with their index, name, geometry and usable area.")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Flags:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	backend, err := platform.NewLinuxBackendFromDisplay()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer backend.Disconnect()
+
+	monitors, err := listMonitors(backend)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(monitors); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+	printMonitors(os.Stdout, monitors)
+	return 0
+}
+
+// listMonitors enumerates the backend's displays in backend order, marking
+// the active one. A failure to resolve the active display is not fatal.
+func listMonitors(backend platform.Backend) ([]MonitorInfo, error) {
+	displays, err := backend.Displays()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list monitors: %w", err)
+	}
+
+	activeID := -1
+	if active, err := backend.ActiveDisplay(); err == nil {
+		activeID = active.ID
+	}
+
+	monitors := make([]MonitorInfo, 0, len(displays))
+	for i, d := range displays {
+		monitors = append(monitors, MonitorInfo{
+			Index:  i,
+			ID:     d.ID,
+			Name:   d.Name,
+			Active: d.ID == activeID,
+			Bounds: monitorRect(d.Bounds),
+			Usable: monitorRect(d.Usable),
+		})
+	}
+	return monitors, nil
+}
+
+func printMonitors(w io.Writer, monitors []MonitorInfo) {
+	if len(monitors) == 0 {
+		fmt.Fprintln(w, "No monitors found")
+		return
+	}
+	for _, m := range monitors {
+		marker := " "
+		if m.Active {
+			marker = "*"
+		}
+		fmt.Fprintf(w, "%s [%d] %s (id %d): %dx%d+%d+%d, usable %dx%d+%d+%d\n",
+			marker, m.Index, m.Name, m.ID,
+			m.Bounds.Width, m.Bounds.Height, m.Bounds.X, m.Bounds.Y,
+			m.Usable.Width, m.Usable.Height, m.Usable.X, m.Usable.Y)
+	}
+}
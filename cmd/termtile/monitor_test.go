@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/1broseidon/termtile/internal/platform"
+)
+
+type fakeMonitorBackend struct {
+	platform.Backend
+	displays []platform.Display
+	active   int
+}
+
+func (f *fakeMonitorBackend) Displays() ([]platform.Display, error) { return f.displays, nil }
+func (f *fakeMonitorBackend) ActiveDisplay() (platform.Display, error) {
+	return f.displays[f.active], nil
+}
+
+func TestListMonitorsTwoMonitors(t *testing.T) {
+	backend := &fakeMonitorBackend{
+		displays: []platform.Display{
+			{
+				ID:     0,
+				Name:   "DP-1",
+				Bounds: platform.Rect{X: 0, Y: 0, Width: 2560, Height: 1440},
+				Usable: platform.Rect{X: 0, Y: 32, Width: 2560, Height: 1408},
+			},
+			{
+				ID:     1,
+				Name:   "HDMI-1",
+				Bounds: platform.Rect{X: 2560, Y: 0, Width: 1920, Height: 1080},
+				Usable: platform.Rect{X: 2560, Y: 0, Width: 1920, Height: 1080},
+			},
+		},
+		active: 1,
+	}
+
+	monitors, err := listMonitors(backend)
+	if err != nil {
+		t.Fatalf("listMonitors: %v", err)
+	}
+	if len(monitors) != 2 {
+		t.Fatalf("len=%d, want 2", len(monitors))
+	}
+	if m := monitors[0]; m.Index != 0 || m.Name != "DP-1" || m.Active || m.Usable.Y != 32 {
+		t.Fatalf("monitors[0]=%+v", m)
+	}
+	if m := monitors[1]; m.Index != 1 || m.Name != "HDMI-1" || !m.Active || m.Bounds.X != 2560 {
+		t.Fatalf("monitors[1]=%+v", m)
+	}
+
+	var out bytes.Buffer
+	printMonitors(&out, monitors)
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("printed %d lines, want 2:\n%s", len(lines), out.String())
+	}
+	if want := "  [0] DP-1 (id 0): 2560x1440+0+0, usable 2560x1408+0+32"; lines[0] != want {
+		t.Fatalf("line 0 = %q, want %q", lines[0], want)
+	}
+	if !strings.HasPrefix(lines[1], "* [1] HDMI-1") {
+		t.Fatalf("line 1 = %q, want active marker", lines[1])
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/1broseidon/termtile/internal/agent"
+	"github.com/1broseidon/termtile/internal/mcp"
+	"github.com/1broseidon/termtile/internal/movemode"
+	"github.com/1broseidon/termtile/internal/workspace"
+)
+
+// renameMultiplexer tracks live sessions by name; only the calls
+// handleMoveComplete makes are implemented.
+type renameMultiplexer struct {
+	agent.Multiplexer
+	sessions map[string]string // session name -> what runs in it
+}
+
+func (m *renameMultiplexer) HasSession(session string) (bool, error) {
+	_, ok := m.sessions[session]
+	return ok, nil
+}
+
+func (m *renameMultiplexer) RenameSession(oldName, newName string) error {
+	m.sessions[newName] = m.sessions[oldName]
+	delete(m.sessions, oldName)
+	return nil
+}
+
+func TestHandleMoveComplete_UndoSwapRestoresSessions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	mux := &renameMultiplexer{sessions: map[string]string{
+		agent.SessionName("dev", 0): "claude",
+		agent.SessionName("dev", 1): "codex",
+	}}
+	orig := moveMultiplexer
+	moveMultiplexer = func() agent.Multiplexer { return mux }
+	t.Cleanup(func() { moveMultiplexer = orig })
+
+	if err := workspace.SetActiveWorkspace("dev", 2, true, 0, []int{0, 1}); err != nil {
+		t.Fatalf("SetActiveWorkspace: %v", err)
+	}
+	if err := workspace.Write(&workspace.WorkspaceConfig{
+		Name:      "dev",
+		Layout:    "grid",
+		AgentMode: true,
+		Terminals: []workspace.TerminalConfig{
+			{WMClass: "kitty", SlotIndex: 0, SessionName: agent.SessionName("dev", 0), Cwd: "/a"},
+			{WMClass: "kitty", SlotIndex: 1, SessionName: agent.SessionName("dev", 1), Cwd: "/b"},
+		},
+	}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	result := movemode.MoveResult{SourceSlot: 0, TargetSlot: 1, IsSwap: true}
+	handleMoveComplete(result)
+	if got := mux.sessions[agent.SessionName("dev", 1)]; got != "claude" {
+		t.Fatalf("after swap slot 1 runs %q, want claude", got)
+	}
+
+	handleMoveComplete(result.Reverse())
+	if got := mux.sessions[agent.SessionName("dev", 0)]; got != "claude" {
+		t.Fatalf("after undo slot 0 runs %q, want claude", got)
+	}
+	if got := mux.sessions[agent.SessionName("dev", 1)]; got != "codex" {
+		t.Fatalf("after undo slot 1 runs %q, want codex", got)
+	}
+
+	cfg, err := workspace.Read("dev")
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	for _, term := range cfg.Terminals {
+		want := map[string]int{"/a": 0, "/b": 1}[term.Cwd]
+		if term.SlotIndex != want || term.SessionName != agent.SessionName("dev", want) {
+			t.Fatalf("terminal %s = slot %d session %q, want slot %d", term.Cwd, term.SlotIndex, term.SessionName, want)
+		}
+	}
+}
+
+func TestHandleMoveComplete_UndoMoveToEmptySlot(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	mux := &renameMultiplexer{sessions: map[string]string{
+		agent.SessionName("dev", 0): "claude",
+	}}
+	orig := moveMultiplexer
+	moveMultiplexer = func() agent.Multiplexer { return mux }
+	t.Cleanup(func() { moveMultiplexer = orig })
+
+	if err := workspace.SetActiveWorkspace("dev", 1, true, 0, []int{0}); err != nil {
+		t.Fatalf("SetActiveWorkspace: %v", err)
+	}
+
+	result := movemode.MoveResult{SourceSlot: 0, TargetSlot: 2}
+	handleMoveComplete(result)
+	if _, ok := mux.sessions[agent.SessionName("dev", 2)]; !ok {
+		t.Fatalf("move did not rename session to slot 2: %v", mux.sessions)
+	}
+
+	handleMoveComplete(result.Reverse())
+	if got := mux.sessions[agent.SessionName("dev", 0)]; got != "claude" || len(mux.sessions) != 1 {
+		t.Fatalf("after undo sessions = %v, want only slot 0", mux.sessions)
+	}
+}
+
+func TestHandleMoveComplete_SwapKeepsCustomSessionName(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	mux := &renameMultiplexer{sessions: map[string]string{
+		"my-agent":                  "claude",
+		agent.SessionName("dev", 1): "codex",
+	}}
+	orig := moveMultiplexer
+	moveMultiplexer = func() agent.Multiplexer { return mux }
+	t.Cleanup(func() { moveMultiplexer = orig })
+
+	if err := workspace.SetActiveWorkspace("dev", 2, true, 0, []int{0, 1}); err != nil {
+		t.Fatalf("SetActiveWorkspace: %v", err)
+	}
+	writeSessionNameMeta(t, "dev", 0, "my-agent")
+
+	handleMoveComplete(movemode.MoveResult{SourceSlot: 0, TargetSlot: 1, IsSwap: true})
+
+	if got := mux.sessions["my-agent"]; got != "claude" {
+		t.Fatalf("custom session runs %q after swap, want claude: %v", got, mux.sessions)
+	}
+	if got := mux.sessions[agent.SessionName("dev", 0)]; got != "codex" {
+		t.Fatalf("slot 0 runs %q after swap, want codex: %v", got, mux.sessions)
+	}
+	if got := mcp.SlotSessionName("dev", 1); got != "my-agent" {
+		t.Fatalf("SlotSessionName(dev, 1) = %q, want my-agent", got)
+	}
+	if got := mcp.SlotSessionName("dev", 0); got != agent.SessionName("dev", 0) {
+		t.Fatalf("SlotSessionName(dev, 0) = %q, want derived name", got)
+	}
+}
@@ -13,8 +13,10 @@ import (
 	"github.com/1broseidon/termtile/internal/agent"
 	"github.com/1broseidon/termtile/internal/config"
 	"github.com/1broseidon/termtile/internal/ipc"
+	"github.com/1broseidon/termtile/internal/mcp"
 	"github.com/1broseidon/termtile/internal/palette"
 	"github.com/1broseidon/termtile/internal/platform"
+	"github.com/1broseidon/termtile/internal/tiling"
 	"github.com/1broseidon/termtile/internal/workspace"
 )
 
@@ -436,7 +438,9 @@ func executePrimaryAction(action string, tileNow bool) int {
 	case strings.HasPrefix(action, "layout:"):
 		layoutName := strings.TrimPrefix(action, "layout:")
 		client := ipc.NewClient()
-		if err := client.ApplyLayout(layoutName, tileNow); err != nil {
+		if err := client.ApplyLayout(layoutName, tileNow); errors.Is(err, tiling.ErrNoTerminals) {
+			fmt.Println(err)
+		} else if err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			return 1
 		}
@@ -603,7 +607,7 @@ func terminalSlotLabel(wsInfo workspace.WorkspaceInfo, slot int) (string, string
 		return fmt.Sprintf("[%d] terminal", slot), "utilities-terminal"
 	}
 
-	sessionName := agent.SessionName(wsInfo.Name, slot)
+	sessionName := mcp.SlotSessionName(wsInfo.Name, slot)
 	status, err := agent.GetSessionStatus(sessionName)
 	if err != nil || !status.Exists {
 		return fmt.Sprintf("[%d] terminal", slot), "utilities-terminal"
@@ -646,7 +650,7 @@ func focusTerminalBySlot(slot int) int {
 
 	// Fallback for agent-mode: find window by tmux session title
 	if wsInfo.AgentMode {
-		sessionName := agent.SessionName(wsInfo.Name, slot)
+		sessionName := mcp.SlotSessionName(wsInfo.Name, slot)
 		windowID, err := platform.FindWindowByTitleStandalone(sessionName)
 		if err == nil {
 			if err := platform.FocusWindowStandalone(windowID); err != nil {
@@ -210,6 +210,101 @@ func TestRunProjectSyncPushFailsWhenSnapshotMissingRequiredFields(t *testing.T)
 	}
 }
 
+func TestAutoSyncProjectRespectsFlags(t *testing.T) {
+	repo := t.TempDir()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	mustMkdir(t, filepath.Join(repo, ".git"))
+	chdir(t, repo)
+
+	if err := workspace.Write(&workspace.WorkspaceConfig{
+		Name:      "dev",
+		Layout:    "grid",
+		Terminals: []workspace.TerminalConfig{{WMClass: "ghostty", SlotIndex: 0}},
+	}); err != nil {
+		t.Fatalf("workspace.Write: %v", err)
+	}
+	if rc := runWorkspace([]string{"init", "--workspace", "dev"}); rc != 0 {
+		t.Fatalf("runWorkspace init rc=%d, want 0", rc)
+	}
+
+	cfgPath := filepath.Join(repo, projectDirName, projectWorkspaceCfgFile)
+	localPath := filepath.Join(repo, projectDirName, projectLocalCfgFile)
+	setFlags := func(pull, push bool) {
+		t.Helper()
+		cfg, err := readProjectWorkspaceConfig(cfgPath)
+		if err != nil {
+			t.Fatalf("readProjectWorkspaceConfig: %v", err)
+		}
+		cfg.Sync.PullOnWorkspaceLoad = pull
+		cfg.Sync.PushOnWorkspaceSave = push
+		if err := writeProjectWorkspaceConfig(cfgPath, cfg); err != nil {
+			t.Fatalf("writeProjectWorkspaceConfig: %v", err)
+		}
+	}
+
+	// Pull is skipped when the flag is off or the project is linked elsewhere.
+	setFlags(false, false)
+	if err := autoSyncProject("dev", "pull"); err != nil {
+		t.Fatalf("autoSyncProject pull (disabled): %v", err)
+	}
+	setFlags(true, false)
+	if err := autoSyncProject("other", "pull"); err != nil {
+		t.Fatalf("autoSyncProject pull (other workspace): %v", err)
+	}
+	if exists(localPath) {
+		t.Fatalf("%s written although pull should have been skipped", localPath)
+	}
+
+	if err := autoSyncProject("dev", "pull"); err != nil {
+		t.Fatalf("autoSyncProject pull: %v", err)
+	}
+	localCfg, err := readProjectLocalConfig(localPath)
+	if err != nil {
+		t.Fatalf("readProjectLocalConfig: %v", err)
+	}
+	if localCfg.Snapshot.Layout == nil || *localCfg.Snapshot.Layout != "grid" {
+		t.Fatalf("snapshot.layout=%v, want %q", localCfg.Snapshot.Layout, "grid")
+	}
+
+	layout := "columns"
+	localCfg.Snapshot.Layout = &layout
+	if err := writeProjectLocalConfig(localPath, localCfg); err != nil {
+		t.Fatalf("writeProjectLocalConfig: %v", err)
+	}
+
+	// Push is off by default.
+	if err := autoSyncProject("dev", "push"); err != nil {
+		t.Fatalf("autoSyncProject push (disabled): %v", err)
+	}
+	if got, _ := workspace.Read("dev"); got == nil || got.Layout != "grid" {
+		t.Fatalf("workspace layout changed by disabled push: %+v", got)
+	}
+
+	setFlags(true, true)
+	if err := autoSyncProject("dev", "push"); err != nil {
+		t.Fatalf("autoSyncProject push: %v", err)
+	}
+	got, err := workspace.Read("dev")
+	if err != nil {
+		t.Fatalf("workspace.Read: %v", err)
+	}
+	if got.Layout != "columns" {
+		t.Fatalf("workspace layout=%q, want %q", got.Layout, "columns")
+	}
+}
+
+func TestAutoSyncProjectOutsideProject(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	chdir(t, dir)
+
+	if err := autoSyncProject("dev", "pull"); err != nil {
+		t.Fatalf("autoSyncProject outside a project: %v", err)
+	}
+}
+
 func mustMkdir(t *testing.T, path string) {
 	t.Helper()
 	if err := os.MkdirAll(path, 0755); err != nil {
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/1broseidon/termtile/internal/platform"
+)
+
+func runReplay(args []string) int {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	realtime := fs.Bool("realtime", false, "Keep the recorded delay between calls")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: termtile replay [--realtime] <file|->")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Re-issues MoveResize calls recorded by a daemon started with")
+		fmt.Fprintf(os.Stderr, "%s=<file> against the current windows.\n", platform.RecordEnvVar)
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Flags:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	var r io.Reader = os.Stdin
+	if path := fs.Arg(0); path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer f.Close()
+		r = f
+	}
+
+	records, err := platform.ReadRecording(r)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if len(records) == 0 {
+		fmt.Println("Recording is empty; nothing to replay")
+		return 0
+	}
+
+	backend, err := platform.NewLinuxBackendFromDisplay()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer backend.Disconnect()
+
+	var sleep func(time.Duration)
+	if *realtime {
+		sleep = time.Sleep
+	}
+	applied, err := platform.Replay(backend, records, sleep)
+	fmt.Printf("Replayed %d/%d move/resize calls\n", applied, len(records))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
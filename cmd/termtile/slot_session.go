@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/1broseidon/termtile/internal/agent"
+	"github.com/1broseidon/termtile/internal/mcp"
+)
+
+// retargetSession returns the name session should have once its slot becomes
+// slot of workspace. Derived names follow the slot; spawn_agent session_name
+// overrides do not encode the slot and are kept.
+func retargetSession(session, workspace string, slot int) string {
+	if agent.IsDerivedSessionName(session) {
+		return agent.SessionName(workspace, slot)
+	}
+	return session
+}
+
+// shiftSlotSession moves a slot's session and agent artifacts to another
+// slot, so the recorded agent meta keeps describing the session it belongs
+// to after slots are compacted or moved.
+func shiftSlotSession(mux agent.Multiplexer, srcWorkspace string, srcSlot int, dstWorkspace string, dstSlot int) error {
+	oldSession := mcp.SlotSessionName(srcWorkspace, srcSlot)
+	newSession := retargetSession(oldSession, dstWorkspace, dstSlot)
+	if oldSession != newSession {
+		if exists, _ := mux.HasSession(oldSession); exists {
+			if err := mux.RenameSession(oldSession, newSession); err != nil {
+				return fmt.Errorf("failed to shift session %s to %s: %w", oldSession, newSession, err)
+			}
+		}
+	}
+	if err := mcp.MoveArtifactDir(srcWorkspace, srcSlot, dstWorkspace, dstSlot); err != nil {
+		return fmt.Errorf("failed to move agent artifacts for slot %d: %w", srcSlot, err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/1broseidon/termtile/internal/agent"
+	"github.com/1broseidon/termtile/internal/mcp"
+)
+
+// writeSessionNameMeta records a spawn_agent session_name override for a
+// slot the way spawn_agent does.
+func writeSessionNameMeta(t *testing.T, ws string, slot int, session string) {
+	t.Helper()
+	dir, err := mcp.EnsureArtifactDir(ws, slot)
+	if err != nil {
+		t.Fatalf("EnsureArtifactDir: %v", err)
+	}
+	meta := `{"agent_type":"claude","session_name":"` + session + `"}`
+	if err := os.WriteFile(filepath.Join(dir, "agent_meta.json"), []byte(meta), 0o644); err != nil {
+		t.Fatalf("write agent meta: %v", err)
+	}
+}
+
+func TestShiftSlotSession(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	mux := &renameMultiplexer{sessions: map[string]string{
+		"my-agent":                  "claude",
+		agent.SessionName("dev", 3): "codex",
+	}}
+	writeSessionNameMeta(t, "dev", 2, "my-agent")
+
+	// Compact slots 2 and 3 down by one, as terminal remove does.
+	for _, slot := range []int{2, 3} {
+		if err := shiftSlotSession(mux, "dev", slot, "dev", slot-1); err != nil {
+			t.Fatalf("shiftSlotSession(%d): %v", slot, err)
+		}
+	}
+
+	if _, ok := mux.sessions["my-agent"]; !ok {
+		t.Fatalf("custom session was renamed: %v", mux.sessions)
+	}
+	if got := mux.sessions[agent.SessionName("dev", 2)]; got != "codex" {
+		t.Fatalf("slot 2 runs %q, want codex: %v", got, mux.sessions)
+	}
+	if got := mcp.SlotSessionName("dev", 1); got != "my-agent" {
+		t.Fatalf("SlotSessionName(dev, 1) = %q, want my-agent", got)
+	}
+}
@@ -1,20 +1,28 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
+	"os/signal"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/1broseidon/termtile/internal/agent"
+	"github.com/1broseidon/termtile/internal/clipboard"
 	"github.com/1broseidon/termtile/internal/config"
 	"github.com/1broseidon/termtile/internal/ipc"
+	"github.com/1broseidon/termtile/internal/mcp"
 	"github.com/1broseidon/termtile/internal/platform"
+	"github.com/1broseidon/termtile/internal/tiling"
 	"github.com/1broseidon/termtile/internal/workspace"
 )
 
@@ -26,6 +34,9 @@ var (
 // getTerminalLogger returns the shared terminal logger, initializing it if needed.
 func getTerminalLogger() *agent.Logger {
 	terminalLoggerOnce.Do(func() {
+		warnings.hold()
+		defer func() { warnings.release(terminalLogger) }()
+
 		res, err := config.LoadWithSources()
 		if err != nil {
 			return
@@ -44,7 +55,7 @@ func getTerminalLogger() *agent.Logger {
 			PreviewLength:  logCfg.PreviewLength,
 		})
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to initialize terminal logger: %v\n", err)
+			warnf("failed to initialize terminal logger: %v", err)
 		}
 	})
 	return terminalLogger
@@ -66,6 +77,23 @@ type TerminalSlotStatus struct {
 	Exists         bool   `json:"exists"`
 	CurrentCommand string `json:"current_command,omitempty"`
 	IsIdle         bool   `json:"is_idle"`
+	SpawnedAt      string `json:"spawned_at,omitempty"`     // RFC 3339, from the slot's agent meta
+	UptimeSeconds  int64  `json:"uptime_seconds,omitempty"` // time since SpawnedAt
+	LastActivity   string `json:"last_activity,omitempty"`  // RFC 3339; last pipe-pane output
+}
+
+// setSlotActivity fills the spawn and output times of a running slot. Zero
+// times are unknown and leave their fields empty.
+func setSlotActivity(status *TerminalSlotStatus, spawnedAt, lastActivity, now time.Time) {
+	if !spawnedAt.IsZero() {
+		status.SpawnedAt = spawnedAt.UTC().Format(time.RFC3339)
+		if now.After(spawnedAt) {
+			status.UptimeSeconds = int64(now.Sub(spawnedAt) / time.Second)
+		}
+	}
+	if !lastActivity.IsZero() {
+		status.LastActivity = lastActivity.UTC().Format(time.RFC3339)
+	}
 }
 
 func printTerminalUsage(w *os.File) {
@@ -74,8 +102,9 @@ func printTerminalUsage(w *os.File) {
 	fmt.Fprintln(w, "  termtile terminal remove [flags]           Remove terminal from workspace")
 	fmt.Fprintln(w, "  termtile terminal move [flags]             Move terminal to another workspace")
 	fmt.Fprintln(w, "  termtile terminal send --slot N <text>     Send input to terminal session")
+	fmt.Fprintln(w, "  termtile terminal paste --slot N [--enter] Paste clipboard into terminal session")
 	fmt.Fprintln(w, "  termtile terminal read --slot N [flags]    Read output from terminal session")
-	fmt.Fprintln(w, "  termtile terminal status [--json]          Show terminal/session status")
+	fmt.Fprintln(w, "  termtile terminal status [--json|--watch]  Show terminal/session status")
 	fmt.Fprintln(w, "  termtile terminal list                     List current terminals")
 	fmt.Fprintln(w, "")
 	fmt.Fprintln(w, "Run 'termtile terminal <command> --help' for command-specific options.")
@@ -100,6 +129,8 @@ func runTerminal(args []string) int {
 		return runTerminalMove(args[1:])
 	case "send":
 		return runTerminalSend(args[1:])
+	case "paste":
+		return runTerminalPaste(args[1:])
 	case "read":
 		return runTerminalRead(args[1:])
 	case "status":
@@ -120,24 +151,92 @@ func getTerminalWorkspaceInfo() *agent.WorkspaceInfo {
 		return nil
 	}
 	return &agent.WorkspaceInfo{
-		Name:       wsInfo.Name,
-		AgentMode:  wsInfo.AgentMode,
-		AgentSlots: wsInfo.AgentSlots,
+		Name:          wsInfo.Name,
+		AgentMode:     wsInfo.AgentMode,
+		AgentSlots:    wsInfo.AgentSlots,
+		TerminalCount: wsInfo.TerminalCount,
 	}
 }
 
+// slotFlag is a --slot value that tells "not given" apart from a slot index.
+// Negative values count back from the end of the workspace (-1 = last slot).
+type slotFlag struct {
+	value int
+	set   bool
+}
+
+func (f *slotFlag) String() string {
+	if f == nil || !f.set {
+		return ""
+	}
+	return strconv.Itoa(f.value)
+}
+
+func (f *slotFlag) Set(v string) error {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("invalid slot %q", v)
+	}
+	f.value = n
+	f.set = true
+	return nil
+}
+
+// resolveTerminalSession turns --slot/--workspace into a tmux session name and
+// the absolute slot index it refers to.
+func resolveTerminalSession(workspaceName string, slot slotFlag, wsInfo *agent.WorkspaceInfo) (string, int, error) {
+	if !slot.set {
+		return "", -1, fmt.Errorf("--slot is required")
+	}
+
+	idx := slot.value
+	if idx < 0 {
+		count := 0
+		if name := strings.TrimSpace(workspaceName); name != "" {
+			ws, err := workspace.GetWorkspaceByName(name)
+			if err != nil {
+				return "", -1, err
+			}
+			count = ws.TerminalCount
+		} else if wsInfo != nil {
+			count = wsInfo.TerminalCount
+		} else {
+			return "", -1, fmt.Errorf("no workspace on current desktop")
+		}
+		var err error
+		if idx, err = agent.ResolveSlot(idx, count); err != nil {
+			return "", -1, err
+		}
+	}
+
+	session, err := agent.ResolveSession(workspaceName, idx, wsInfo)
+	if err != nil {
+		return "", -1, err
+	}
+	// Prefer a session_name override recorded for the slot.
+	wsName := strings.TrimSpace(workspaceName)
+	if wsName == "" {
+		wsName = wsInfo.Name
+	}
+	if recorded := mcp.SlotSessionName(wsName, idx); !agent.IsDerivedSessionName(recorded) {
+		session = recorded
+	}
+	return session, idx, nil
+}
+
 func runTerminalSend(args []string) int {
 	fs := flag.NewFlagSet("send", flag.ContinueOnError)
 	fs.SetOutput(os.Stderr)
 	fs.Usage = func() {
 		fmt.Fprintln(os.Stderr, "Usage: termtile terminal send --slot N [--workspace NAME] <text>")
 		fmt.Fprintln(os.Stderr, "")
-		fmt.Fprintln(os.Stderr, "Send input to a tmux-backed terminal slot.")
+		fmt.Fprintln(os.Stderr, "Send input to a multiplexer-backed (tmux or screen) terminal slot.")
 		fmt.Fprintln(os.Stderr, "")
 		fmt.Fprintln(os.Stderr, "Flags:")
 		fs.PrintDefaults()
 	}
-	slot := fs.Int("slot", -1, "Target workspace slot index")
+	var slot slotFlag
+	fs.Var(&slot, "slot", "Target workspace slot index (negative counts from the end, -1 = last)")
 	workspaceName := fs.String("workspace", "", "Target workspace name (default: current desktop's workspace)")
 	if err := fs.Parse(args); err != nil {
 		if err == flag.ErrHelp {
@@ -151,32 +250,33 @@ func runTerminalSend(args []string) int {
 		return 2
 	}
 
-	if err := agent.RequireTmux(); err != nil {
-		fmt.Fprintln(os.Stderr, "tmux not available (required for terminal send/read):", err)
+	mux := terminalMultiplexer()
+	if !mux.Available() {
+		fmt.Fprintf(os.Stderr, "%s not available (required for terminal send/read)\n", mux.Name())
 		return 1
 	}
 
 	// Get workspace info from current desktop for auto-detection
 	wsInfo := getTerminalWorkspaceInfo()
 
-	session, err := agent.ResolveSession(*workspaceName, *slot, wsInfo)
+	session, slotIdx, err := resolveTerminalSession(*workspaceName, slot, wsInfo)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		return 2
 	}
 
-	ok, err := agent.HasSession(session)
+	ok, err := mux.HasSession(session)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		return 1
 	}
 	if !ok {
-		fmt.Fprintf(os.Stderr, "tmux session %q not found (load a workspace with agent-mode first)\n", session)
+		fmt.Fprintf(os.Stderr, "%s session %q not found (load a workspace with agent-mode first)\n", mux.Name(), session)
 		return 1
 	}
 
 	text := strings.Join(fs.Args(), " ")
-	if err := agent.SendKeys(session, text); err != nil {
+	if err := mux.SendKeys(session, text); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		return 1
 	}
@@ -197,65 +297,203 @@ func runTerminalSend(args []string) int {
 			logCfg := res.Config.GetLoggingConfig()
 			previewLen = logCfg.PreviewLength
 			if logCfg.IncludeContent {
-				details["content"] = text
+				details["content"] = agent.TruncateContent(text, logCfg.MaxContentBytes)
 			} else {
 				details["preview"] = agent.Truncate(text, previewLen)
 			}
 		} else {
 			details["preview"] = agent.Truncate(text, previewLen)
 		}
-		logger.Log(agent.ActionSend, wsName, *slot, details)
+		logger.Log(agent.ActionSend, wsName, slotIdx, details)
 	}
 
 	return 0
 }
 
+// terminalMultiplexer returns the multiplexer selected by
+// agent_mode.multiplexer, defaulting to tmux when config cannot be loaded.
+func terminalMultiplexer() agent.Multiplexer {
+	var cfg *config.Config
+	if res, err := config.LoadWithSources(); err == nil {
+		cfg = res.Config
+	}
+	return agent.MultiplexerFor(cfg)
+}
+
+// detectClipboard is a seam for terminal paste; tests swap it for a fake.
+var detectClipboard = clipboard.Detect
+
+func runTerminalPaste(args []string) int {
+	fs := flag.NewFlagSet("paste", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: termtile terminal paste --slot N [--workspace NAME] [--enter]")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Paste the system clipboard verbatim into an agent-mode terminal slot.")
+		fmt.Fprintln(os.Stderr, "The clipboard is read with wl-paste (Wayland), xclip, or xsel.")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Flags:")
+		fs.PrintDefaults()
+	}
+	var slot slotFlag
+	fs.Var(&slot, "slot", "Target workspace slot index (negative counts from the end, -1 = last)")
+	workspaceName := fs.String("workspace", "", "Target workspace name (default: current desktop's workspace)")
+	enter := fs.Bool("enter", false, "Press Enter after pasting")
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(os.Stderr, "paste takes no positional arguments")
+		fs.Usage()
+		return 2
+	}
+
+	mux := terminalMultiplexer()
+	if !mux.Available() {
+		fmt.Fprintf(os.Stderr, "%s not available (required for terminal paste)\n", mux.Name())
+		return 1
+	}
+	paster, ok := mux.(agent.Paster)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "terminal paste is not supported with %s\n", mux.Name())
+		return 1
+	}
+
+	wsInfo := getTerminalWorkspaceInfo()
+
+	session, slotIdx, err := resolveTerminalSession(*workspaceName, slot, wsInfo)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	exists, err := mux.HasSession(session)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if !exists {
+		fmt.Fprintf(os.Stderr, "%s session %q not found (load a workspace with agent-mode first)\n", mux.Name(), session)
+		return 1
+	}
+
+	reader, err := detectClipboard()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	n, err := pasteClipboard(reader, paster, session, *enter)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	wsName := *workspaceName
+	if wsName == "" && wsInfo != nil {
+		wsName = wsInfo.Name
+	}
+	logTerminalAction(agent.ActionSend, wsName, slotIdx, map[string]interface{}{
+		"len":       n,
+		"clipboard": reader.Name(),
+		"enter":     *enter,
+	})
+	return 0
+}
+
+// pasteClipboard reads the clipboard and pastes it into session, returning
+// the number of bytes sent.
+func pasteClipboard(reader clipboard.Reader, paster agent.Paster, session string, enter bool) (int, error) {
+	text, err := reader.Read()
+	if err != nil {
+		return 0, err
+	}
+	if text == "" {
+		return 0, fmt.Errorf("clipboard is empty")
+	}
+	if err := paster.PasteText(session, text, enter); err != nil {
+		return 0, err
+	}
+	return len(text), nil
+}
+
 func runTerminalRead(args []string) int {
 	fs := flag.NewFlagSet("read", flag.ContinueOnError)
 	fs.SetOutput(os.Stderr)
 	fs.Usage = func() {
 		fmt.Fprintln(os.Stderr, "Usage:")
-		fmt.Fprintln(os.Stderr, "  termtile terminal read --slot N [--workspace NAME] [--lines M]")
-		fmt.Fprintln(os.Stderr, "  termtile terminal read --slot N [--workspace NAME] --wait-for <pattern> [--timeout S] [--lines M]")
+		fmt.Fprintln(os.Stderr, "  termtile terminal read --slot N [--workspace NAME] [--lines M] [--clean|--raw]")
+		fmt.Fprintln(os.Stderr, "  termtile terminal read --slot N [--workspace NAME] --wait-for <pattern> [--timeout S] [--lines M] [--clean|--raw]")
+		fmt.Fprintln(os.Stderr, "  termtile terminal read --slot N [--workspace NAME] --last-response [--lines M]")
 		fmt.Fprintln(os.Stderr, "")
-		fmt.Fprintln(os.Stderr, "Read output from a tmux-backed terminal slot.")
+		fmt.Fprintln(os.Stderr, "Read output from a multiplexer-backed (tmux or screen) terminal slot.")
+		fmt.Fprintln(os.Stderr, "--clean processes output as read_from_agent does with clean: true.")
+		fmt.Fprintln(os.Stderr, "--last-response prints only the agent's most recent fenced response.")
 		fmt.Fprintln(os.Stderr, "")
 		fmt.Fprintln(os.Stderr, "Flags:")
 		fs.PrintDefaults()
 	}
-	slot := fs.Int("slot", -1, "Target workspace slot index")
+	var slot slotFlag
+	fs.Var(&slot, "slot", "Target workspace slot index (negative counts from the end, -1 = last)")
 	workspaceName := fs.String("workspace", "", "Target workspace name (default: current desktop's workspace)")
-	lines := fs.Int("lines", 200, "Number of lines to capture from the pane (approx; uses tmux -S -N)")
+	lines := fs.Int("lines", 200, "Number of lines to capture from the pane (approx; uses tmux -S -N or screen scrollback; with --last-response, default is the whole history)")
 	waitFor := fs.String("wait-for", "", "Wait until output contains this substring")
 	timeoutSeconds := fs.Int("timeout", 10, "Wait timeout in seconds (used with --wait-for)")
+	clean := fs.Bool("clean", false, "Join wrapped lines, drop TUI chrome and control characters, and keep the last --lines lines")
+	raw := fs.Bool("raw", false, "Print the capture unchanged (default)")
+	lastResponse := fs.Bool("last-response", false, "Print only the content of the most recent [termtile-response] fenced block")
 	if err := fs.Parse(args); err != nil {
 		if err == flag.ErrHelp {
 			return 0
 		}
 		return 2
 	}
+	if *clean && *raw {
+		fmt.Fprintln(os.Stderr, "--clean and --raw are mutually exclusive")
+		return 2
+	}
+	if *lastResponse && (*clean || *raw || strings.TrimSpace(*waitFor) != "") {
+		fmt.Fprintln(os.Stderr, "--last-response cannot be combined with --clean, --raw or --wait-for")
+		return 2
+	}
+	captureLines := readCaptureLines(fs, *lines, *lastResponse)
 
-	if err := agent.RequireTmux(); err != nil {
-		fmt.Fprintln(os.Stderr, "tmux not available (required for terminal send/read):", err)
+	mux := terminalMultiplexer()
+	if !mux.Available() {
+		fmt.Fprintf(os.Stderr, "%s not available (required for terminal send/read)\n", mux.Name())
 		return 1
 	}
 
 	// Get workspace info from current desktop for auto-detection
 	wsInfo := getTerminalWorkspaceInfo()
 
-	session, err := agent.ResolveSession(*workspaceName, *slot, wsInfo)
+	session, slotIdx, err := resolveTerminalSession(*workspaceName, slot, wsInfo)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		return 2
 	}
 
-	ok, err := agent.HasSession(session)
+	ok, err := mux.HasSession(session)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		return 1
 	}
 	if !ok {
-		fmt.Fprintf(os.Stderr, "tmux session %q not found (load a workspace with agent-mode first)\n", session)
+		wsName := *workspaceName
+		if wsName == "" && wsInfo != nil {
+			wsName = wsInfo.Name
+		}
+		if ended, err := mcp.ReadEndedOutput(wsName, slotIdx); err == nil {
+			if *lastResponse {
+				fmt.Fprintf(os.Stderr, "session %q ended at %s; showing its saved final output\n", ended.SessionName, ended.EndedAt.Local().Format(time.RFC3339))
+				return printLastResponse(os.Stdout, os.Stderr, ended.Output)
+			}
+			return printEndedSlotOutput(os.Stdout, os.Stderr, ended, *lines, *waitFor, *clean)
+		}
+		fmt.Fprintf(os.Stderr, "%s session %q not found (load a workspace with agent-mode first)\n", mux.Name(), session)
 		return 1
 	}
 
@@ -266,14 +504,20 @@ func runTerminalRead(args []string) int {
 			if wsName == "" && wsInfo != nil {
 				wsName = wsInfo.Name
 			}
-			logger.Log(agent.ActionRead, wsName, *slot, map[string]interface{}{
+			logger.Log(agent.ActionRead, wsName, slotIdx, map[string]interface{}{
 				"lines": *lines,
 			})
 		}
 	}
 
+	capture, wait := mux.CapturePane, mux.WaitFor
+	if joined, ok := mux.(joinedCapturer); ok && (*clean || *lastResponse) {
+		capture, wait = joined.CapturePaneJoined, joined.WaitForJoined
+	}
+
 	if strings.TrimSpace(*waitFor) != "" {
-		out, err := agent.WaitFor(session, *waitFor, time.Duration(*timeoutSeconds)*time.Second, *lines)
+		out, err := wait(session, *waitFor, time.Duration(*timeoutSeconds)*time.Second, *lines)
+		out = formatReadOutput(out, *clean, *lines)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			if strings.TrimSpace(out) != "" {
@@ -286,47 +530,184 @@ func runTerminalRead(args []string) int {
 		return 0
 	}
 
-	out, err := agent.CapturePane(session, *lines)
+	out, err := capture(session, captureLines)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		return 1
 	}
-	fmt.Fprint(os.Stdout, out)
+	if *lastResponse {
+		code := printLastResponse(os.Stdout, os.Stderr, out)
+		if code == 0 {
+			logRead()
+		}
+		return code
+	}
+	fmt.Fprint(os.Stdout, formatReadOutput(out, *clean, *lines))
 	logRead()
 	return 0
 }
 
+// readCaptureLines returns how many lines terminal read captures. With
+// --last-response and no explicit --lines it captures the whole scrollback,
+// so a long response is not cut off by the default.
+func readCaptureLines(fs *flag.FlagSet, lines int, lastResponse bool) int {
+	if lastResponse && !flagSet(fs, "lines") {
+		return -1
+	}
+	return lines
+}
+
+// flagSet reports whether the flag called name was given on the command line.
+func flagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// printLastResponse prints the most recent fenced response in out, as
+// read_from_agent extracts it for fence-enabled agents. It fails when the
+// capture holds no complete response.
+func printLastResponse(stdout, stderr io.Writer, out string) int {
+	content, ok := mcp.LastResponse(out)
+	if !ok {
+		fmt.Fprintln(stderr, "no fenced response found in output (agent may still be responding, or try a larger --lines)")
+		return 1
+	}
+	fmt.Fprintln(stdout, content)
+	return 0
+}
+
+// skipAutoTile reports whether any of the named workspaces has auto_tile
+// disabled, in which case terminal add/remove/move leave tiling alone.
+func skipAutoTile(names ...string) bool {
+	for _, name := range names {
+		if !workspace.AutoTileEnabled(name) {
+			fmt.Fprintf(os.Stderr, "workspace %q has auto_tile disabled; not re-tiling\n", name)
+			return true
+		}
+	}
+	return false
+}
+
+// joinedCapturer is implemented by multiplexers that can undo line wrapping
+// when capturing (tmux).
+type joinedCapturer interface {
+	CapturePaneJoined(session string, lines int) (string, error)
+	WaitForJoined(session, pattern string, timeout time.Duration, lines int) (string, error)
+}
+
+// formatReadOutput applies terminal read --clean to captured output using the
+// same processing as read_from_agent; without clean, out is returned as is.
+func formatReadOutput(out string, clean bool, lines int) string {
+	if !clean {
+		return out
+	}
+	cleaned := mcp.ProcessReadOutput(out, true, lines)
+	if cleaned == "" {
+		return ""
+	}
+	return cleaned + "\n"
+}
+
+// printEndedSlotOutput prints the output kill_agent saved for a slot whose
+// session is gone. With waitFor, the saved output must already contain it.
+func printEndedSlotOutput(stdout, stderr io.Writer, ended mcp.EndedOutput, lines int, waitFor string, clean bool) int {
+	fmt.Fprintf(stderr, "session %q ended at %s; showing its saved final output\n", ended.SessionName, ended.EndedAt.Local().Format(time.RFC3339))
+	out := ended.Tail(lines)
+	if clean {
+		out = formatReadOutput(ended.Output, true, lines)
+	}
+	if strings.TrimSpace(waitFor) != "" && !strings.Contains(out, waitFor) {
+		fmt.Fprintf(stderr, "pattern %q not found in ended session output\n", waitFor)
+		if strings.TrimSpace(out) != "" {
+			fmt.Fprint(stdout, out)
+		}
+		return 1
+	}
+	fmt.Fprint(stdout, out)
+	return 0
+}
+
 func runTerminalStatus(args []string) int {
 	fs := flag.NewFlagSet("status", flag.ContinueOnError)
 	fs.SetOutput(os.Stderr)
 	fs.Usage = func() {
-		fmt.Fprintln(os.Stderr, "Usage: termtile terminal status [--json] [--workspace NAME]")
+		fmt.Fprintln(os.Stderr, "Usage: termtile terminal status [--json] [--workspace NAME] [--watch [--interval D]]")
 		fmt.Fprintln(os.Stderr, "")
 		fmt.Fprintln(os.Stderr, "Show status of all workspaces with tmux sessions.")
+		fmt.Fprintln(os.Stderr, "With --watch, the table is redrawn in place until interrupted (Ctrl-C).")
 		fmt.Fprintln(os.Stderr, "")
 		fmt.Fprintln(os.Stderr, "Flags:")
 		fs.PrintDefaults()
 	}
 	jsonOut := fs.Bool("json", false, "Output as JSON")
 	workspaceName := fs.String("workspace", "", "Filter to specific workspace")
+	watch := fs.Bool("watch", false, "Refresh the status table until interrupted")
+	interval := fs.Duration("interval", 2*time.Second, "Refresh interval for --watch")
 	if err := fs.Parse(args); err != nil {
 		if err == flag.ErrHelp {
 			return 0
 		}
 		return 2
 	}
+	if *watch && *jsonOut {
+		fmt.Fprintln(os.Stderr, "--watch cannot be combined with --json")
+		return 2
+	}
+	if *interval <= 0 {
+		fmt.Fprintln(os.Stderr, "--interval must be > 0")
+		return 2
+	}
 
-	// Check tmux availability
-	if err := agent.RequireTmux(); err != nil {
-		fmt.Fprintln(os.Stderr, "tmux not available:", err)
+	provider := func() ([]TerminalWorkspaceStatus, error) {
+		return buildTerminalStatus(*workspaceName)
+	}
+
+	if *watch {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		watchTerminalStatus(ctx, os.Stdout, *interval, provider)
+		return 0
+	}
+
+	results, err := provider()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
 		return 1
 	}
 
+	// Output
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			fmt.Fprintln(os.Stderr, "failed to encode JSON:", err)
+			return 1
+		}
+		return 0
+	}
+
+	printTerminalStatus(os.Stdout, results)
+	return 0
+}
+
+// buildTerminalStatus collects slot status for every agent-mode workspace in
+// the registry, optionally filtered to a single workspace name.
+func buildTerminalStatus(workspaceName string) ([]TerminalWorkspaceStatus, error) {
+	mux := terminalMultiplexer()
+	if !mux.Available() {
+		return nil, fmt.Errorf("%s not available", mux.Name())
+	}
+	reporter, _ := mux.(agent.StatusReporter)
+
 	// Get all workspaces
 	allWs, err := workspace.GetAllWorkspaces()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "failed to get workspaces:", err)
-		return 1
+		return nil, fmt.Errorf("failed to get workspaces: %w", err)
 	}
 
 	// Filter and build status for agent-mode workspaces
@@ -335,7 +716,7 @@ func runTerminalStatus(args []string) int {
 		if !ws.AgentMode {
 			continue
 		}
-		if *workspaceName != "" && ws.Name != *workspaceName {
+		if workspaceName != "" && ws.Name != workspaceName {
 			continue
 		}
 
@@ -348,18 +729,26 @@ func runTerminalStatus(args []string) int {
 		}
 
 		for _, slot := range ws.AgentSlots {
-			session := agent.SessionName(ws.Name, slot)
+			session := mcp.SlotSessionName(ws.Name, slot)
 			slotStatus := TerminalSlotStatus{
 				Slot:        slot,
 				SessionName: session,
 			}
 
-			// Query tmux session status
-			sessionStatus, err := agent.GetSessionStatus(session)
-			if err == nil {
-				slotStatus.Exists = sessionStatus.Exists
-				slotStatus.CurrentCommand = sessionStatus.CurrentCommand
-				slotStatus.IsIdle = sessionStatus.IsIdle
+			// Multiplexers without a status query only report existence.
+			if reporter != nil {
+				sessionStatus, err := reporter.GetSessionStatus(session)
+				if err == nil {
+					slotStatus.Exists = sessionStatus.Exists
+					slotStatus.CurrentCommand = sessionStatus.CurrentCommand
+					slotStatus.IsIdle = sessionStatus.IsIdle
+				}
+			} else if exists, err := mux.HasSession(session); err == nil {
+				slotStatus.Exists = exists
+			}
+			if slotStatus.Exists {
+				spawnedAt, lastActivity := mcp.SlotActivity(ws.Name, slot)
+				setSlotActivity(&slotStatus, spawnedAt, lastActivity, time.Now())
 			}
 
 			status.Slots = append(status.Slots, slotStatus)
@@ -373,27 +762,20 @@ func runTerminalStatus(args []string) int {
 		return results[i].Desktop < results[j].Desktop
 	})
 
-	// Output
-	if *jsonOut {
-		enc := json.NewEncoder(os.Stdout)
-		enc.SetIndent("", "  ")
-		if err := enc.Encode(results); err != nil {
-			fmt.Fprintln(os.Stderr, "failed to encode JSON:", err)
-			return 1
-		}
-		return 0
-	}
+	return results, nil
+}
 
-	// Human-readable output
+// printTerminalStatus writes the human-readable status table.
+func printTerminalStatus(w io.Writer, results []TerminalWorkspaceStatus) {
 	if len(results) == 0 {
-		fmt.Println("No workspaces with tmux sessions found")
-		return 0
+		fmt.Fprintln(w, "No workspaces with agent sessions found")
+		return
 	}
 
 	for i, ws := range results {
-		fmt.Printf("Workspace: %s (Desktop %d)\n", ws.Name, ws.Desktop)
-		fmt.Printf("  Terminals: %d\n", ws.TerminalCount)
-		fmt.Printf("  Slots:\n")
+		fmt.Fprintf(w, "Workspace: %s (Desktop %d)\n", ws.Name, ws.Desktop)
+		fmt.Fprintf(w, "  Terminals: %d\n", ws.TerminalCount)
+		fmt.Fprintf(w, "  Slots:\n")
 		for _, slot := range ws.Slots {
 			status := "not running"
 			if slot.Exists {
@@ -403,14 +785,68 @@ func runTerminalStatus(args []string) int {
 					status = fmt.Sprintf("running (%s)", slot.CurrentCommand)
 				}
 			}
-			fmt.Printf("    [%d] %s: %s\n", slot.Slot, slot.SessionName, status)
+			fmt.Fprintf(w, "    [%d] %s: %s%s\n", slot.Slot, slot.SessionName, status, slotActivitySuffix(slot, time.Now()))
 		}
 		if i < len(results)-1 {
-			fmt.Println()
+			fmt.Fprintln(w)
 		}
 	}
+}
 
-	return 0
+// slotActivitySuffix describes a slot's uptime and last output for the
+// status table, e.g. " (up 1h5m, last output 12s ago)". It is empty when
+// neither is known.
+func slotActivitySuffix(slot TerminalSlotStatus, now time.Time) string {
+	var parts []string
+	if slot.SpawnedAt != "" {
+		parts = append(parts, "up "+(time.Duration(slot.UptimeSeconds)*time.Second).String())
+	}
+	if last, err := time.Parse(time.RFC3339, slot.LastActivity); err == nil {
+		ago := now.Sub(last).Truncate(time.Second)
+		if ago < 0 {
+			ago = 0
+		}
+		parts = append(parts, "last output "+ago.String()+" ago")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+// clearScreen moves the cursor home and clears the terminal so each refresh
+// redraws in place.
+const clearScreen = "\033[H\033[2J"
+
+// watchTerminalStatus redraws the status table every interval until ctx is
+// cancelled. Provider errors (e.g. tmux going away) are shown in place of the
+// table rather than ending the watch, so the view recovers on its own.
+func watchTerminalStatus(ctx context.Context, w io.Writer, interval time.Duration, provider func() ([]TerminalWorkspaceStatus, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	watchTerminalStatusTicks(ctx, w, interval, ticker.C, provider)
+}
+
+// watchTerminalStatusTicks redraws the status table once up front and again
+// on every value from ticks until ctx is cancelled.
+func watchTerminalStatusTicks(ctx context.Context, w io.Writer, interval time.Duration, ticks <-chan time.Time, provider func() ([]TerminalWorkspaceStatus, error)) {
+	for {
+		fmt.Fprint(w, clearScreen)
+		fmt.Fprintf(w, "termtile terminal status (every %s, Ctrl-C to exit)  %s\n\n", interval, time.Now().Format("15:04:05"))
+
+		results, err := provider()
+		if err != nil {
+			fmt.Fprintln(w, err)
+		} else {
+			printTerminalStatus(w, results)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticks:
+		}
+	}
 }
 
 func runTerminalList(args []string) int {
@@ -419,7 +855,8 @@ func runTerminalList(args []string) int {
 	fs.Usage = func() {
 		fmt.Fprintln(os.Stderr, "Usage: termtile terminal list [--json]")
 		fmt.Fprintln(os.Stderr, "")
-		fmt.Fprintln(os.Stderr, "List terminals in the current workspace.")
+		fmt.Fprintln(os.Stderr, "List terminals in the current workspace, with each slot's window")
+		fmt.Fprintln(os.Stderr, "geometry when a display is available.")
 		fmt.Fprintln(os.Stderr, "")
 		fmt.Fprintln(os.Stderr, "Flags:")
 		fs.PrintDefaults()
@@ -442,6 +879,13 @@ func runTerminalList(args []string) int {
 		return 1
 	}
 
+	geometry, err := currentTerminalGeometry()
+	if err != nil {
+		warnf("terminal geometry unavailable: %v", err)
+		// Keep "terminals" a list in the JSON output.
+		geometry = []TerminalGeometry{}
+	}
+
 	if *jsonOut {
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
@@ -451,6 +895,7 @@ func runTerminalList(args []string) int {
 			"terminal_count": wsInfo.TerminalCount,
 			"agent_mode":     wsInfo.AgentMode,
 			"agent_slots":    wsInfo.AgentSlots,
+			"terminals":      geometry,
 		})
 		return 0
 	}
@@ -461,9 +906,67 @@ func runTerminalList(args []string) int {
 		fmt.Printf("Agent Mode: yes\n")
 		fmt.Printf("Slots: %v\n", wsInfo.AgentSlots)
 	}
+	printTerminalGeometry(os.Stdout, geometry)
 	return 0
 }
 
+// TerminalGeometry is where one slot's window sits on screen.
+type TerminalGeometry struct {
+	Slot     int    `json:"slot"`
+	WindowID uint32 `json:"window_id"`
+	X        int    `json:"x"`
+	Y        int    `json:"y"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+}
+
+// currentTerminalGeometry connects to the display and reads the geometry of
+// the terminals on the active monitor.
+func currentTerminalGeometry() ([]TerminalGeometry, error) {
+	res, err := config.LoadWithSources()
+	if err != nil {
+		return nil, err
+	}
+	backend, err := platform.NewLinuxBackendFromDisplay()
+	if err != nil {
+		return nil, err
+	}
+	defer backend.Disconnect()
+	return listTerminalGeometry(newTerminalLister(backend, res.Config))
+}
+
+// listTerminalGeometry numbers the lister's terminals as slots, in the same
+// order terminal remove and move resolve them.
+func listTerminalGeometry(lister workspace.TerminalLister) ([]TerminalGeometry, error) {
+	windows, err := lister.ListTerminals()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]TerminalGeometry, 0, len(windows))
+	for i, w := range windows {
+		out = append(out, TerminalGeometry{
+			Slot:     i,
+			WindowID: w.WindowID,
+			X:        w.X,
+			Y:        w.Y,
+			Width:    w.Width,
+			Height:   w.Height,
+		})
+	}
+	return out, nil
+}
+
+// printTerminalGeometry writes one line per slot for the human list output.
+func printTerminalGeometry(w io.Writer, geometry []TerminalGeometry) {
+	if len(geometry) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "Geometry:")
+	for _, g := range geometry {
+		fmt.Fprintf(w, "  [%d] 0x%x  %dx%d+%d+%d\n", g.Slot, g.WindowID, g.Width, g.Height, g.X, g.Y)
+	}
+}
+
 func runTerminalAdd(args []string) int {
 	fs := flag.NewFlagSet("add", flag.ContinueOnError)
 	fs.SetOutput(os.Stderr)
@@ -480,6 +983,7 @@ func runTerminalAdd(args []string) int {
 		fmt.Fprintln(os.Stderr, "  termtile terminal add --cwd ~/code  # New terminal in ~/code")
 		fmt.Fprintln(os.Stderr, "  termtile terminal add --no-agent    # Skip tmux session on agent-mode workspace")
 		fmt.Fprintln(os.Stderr, "  termtile terminal add --slot 1      # Insert at slot 1, shift others up")
+		fmt.Fprintln(os.Stderr, "  termtile terminal add -n 3          # Add three terminals, re-tile once")
 	}
 	path := fs.String("path", "", "Config file path")
 	workspaceName := fs.String("workspace", "", "Target workspace name (default: workspace on current desktop)")
@@ -488,6 +992,7 @@ func runTerminalAdd(args []string) int {
 	ignoreLimits := fs.Bool("ignore-limits", false, "Ignore configured workspace limits")
 	timeout := fs.Int("timeout", 10, "Spawn synchronization timeout in seconds")
 	slotPos := fs.Int("slot", -1, "Insert at specific slot position (shifts existing slots up)")
+	count := fs.Int("n", 1, "Number of terminals to add")
 
 	if err := fs.Parse(args); err != nil {
 		if err == flag.ErrHelp {
@@ -495,6 +1000,10 @@ func runTerminalAdd(args []string) int {
 		}
 		return 2
 	}
+	if *count < 1 {
+		fmt.Fprintln(os.Stderr, "-n must be >= 1")
+		return 2
+	}
 
 	// IMPORTANT: Capture desktop immediately to avoid race conditions
 	// if user switches desktops while command is running
@@ -546,7 +1055,7 @@ func runTerminalAdd(args []string) int {
 	}
 
 	if !*ignoreLimits {
-		if err := workspace.CheckCanAddTerminal(wsInfo.Name, wsInfo.TerminalCount, res.Config); err != nil {
+		if err := workspace.CheckCanAddTerminals(wsInfo.Name, wsInfo.TerminalCount, *count, res.Config); err != nil {
 			fmt.Fprintln(os.Stderr, "cannot add terminal:", err)
 			return 1
 		}
@@ -632,126 +1141,197 @@ func runTerminalAdd(args []string) int {
 	createTmux := wsInfo.AgentMode && !*noAgent
 
 	// If inserting at a position (not appending), shift existing sessions up
+	tmux := agent.MultiplexerFor(res.Config)
 	if insertMode && createTmux {
-		tmux := agent.NewTmuxMultiplexer()
 
-		// Shift existing sessions UP (from end to insertSlot, to avoid collisions)
-		// If inserting at slot 1 with 3 terminals:
+		// Shift existing sessions UP by the batch size (from end to
+		// insertSlot, to avoid collisions). If inserting one terminal at
+		// slot 1 with 3 terminals:
 		//   slot 2 → slot 3
 		//   slot 1 → slot 2
 		//   (then new terminal takes slot 1)
 		for i := wsInfo.TerminalCount - 1; i >= newSlot; i-- {
-			oldSession := agent.SessionName(wsInfo.Name, i)
-			newSession := agent.SessionName(wsInfo.Name, i+1)
-
-			if exists, _ := tmux.HasSession(oldSession); exists {
-				if err := tmux.RenameSession(oldSession, newSession); err != nil {
-					fmt.Fprintf(os.Stderr, "failed to shift session %s to %s: %v\n", oldSession, newSession, err)
-					return 1
-				}
+			if err := shiftSlotSession(tmux, wsInfo.Name, i, wsInfo.Name, i+*count); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
 			}
 		}
 	}
 
-	// Build spawn command
-	var cmdOverride string
+	// Build spawn commands
+	var configMgr *agent.ConfigManager
 	if createTmux {
-		appCfg := res.Config
-		configMgr, err := agent.NewConfigManager(appCfg)
+		configMgr, err = agent.NewConfigManager(res.Config)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "failed to initialize multiplexer: %v\n", err)
 			return 1
 		}
+	}
 
-		session := agent.SessionName(wsInfo.Name, newSlot)
-		sessionCmd := configMgr.SessionCommand(session)
+	// Get current layout from daemon
+	layoutName := savedWs.Layout
+	if status, err := applier.client.GetStatus(); err == nil && status.ActiveLayout != "" {
+		layoutName = status.ActiveLayout
+	}
 
-		// Build command with cwd
-		baseArgs, err := splitCommand(sessionCmd)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "failed to parse multiplexer command: %v\n", err)
-			return 1
-		}
-		muxArgs := append(baseArgs, "-c", workDir)
-		cmdOverride = shellJoin(muxArgs)
+	batch := terminalBatch{
+		spawn: func(slot int) error {
+			var cmdOverride string
+			if configMgr != nil {
+				session := agent.SessionName(wsInfo.Name, slot)
+				sessionCmd := configMgr.SessionCommand(session)
+
+				// Build command with cwd
+				baseArgs, err := splitCommand(sessionCmd)
+				if err != nil {
+					return fmt.Errorf("failed to parse multiplexer command: %w", err)
+				}
+				muxArgs := append(baseArgs, "-c", workDir)
+				cmdOverride = shellJoin(muxArgs)
+			}
+
+			termConfig := workspace.TerminalConfig{
+				WMClass:   termClass,
+				Cwd:       workDir,
+				SlotIndex: slot,
+			}
+			return spawnTerminalWithCommand(termConfig, res.Config.TerminalSpawnCommands, cmdOverride, res.Config.SpawnEnvList(termClass))
+		},
+		wait: func(want int) ([]uint32, error) {
+			return waitForNewTerminals(lister, existing, want, time.Duration(*timeout)*time.Second)
+		},
+		register: func(slot int) {
+			// Update workspace state
+			var err error
+			if insertMode {
+				err = workspace.InsertTerminalAtSlot(wsInfo.Desktop, slot, createTmux)
+			} else {
+				_, err = workspace.AddTerminalToWorkspace(wsInfo.Desktop, createTmux)
+			}
+			if err != nil {
+				warnf("failed to update workspace state: %v", err)
+			}
+			// Log add-terminal action
+			logTerminalAction(agent.ActionAddTerminal, wsInfo.Name, slot, nil)
+		},
+		retile: func(newWindowIDs []uint32) {
+			if skipAutoTile(wsInfo.Name) {
+				return
+			}
+			if !insertMode {
+				if err := applier.ApplyLayout(layoutName, true); err != nil && !errors.Is(err, tiling.ErrNoTerminals) {
+					warnf("failed to re-tile: %v", err)
+				}
+				return
+			}
+
+			// For insert mode, we need to specify the window order
+			// Sort existing windows by their visual position (Y then X)
+			sort.Slice(before, func(i, j int) bool {
+				if before[i].Y != before[j].Y {
+					return before[i].Y < before[j].Y
+				}
+				return before[i].X < before[j].X
+			})
+			windowOrder := insertWindowOrder(before, newSlot, newWindowIDs)
+			if err := applier.ApplyLayoutWithOrder(layoutName, windowOrder); err != nil && !errors.Is(err, tiling.ErrNoTerminals) {
+				warnf("failed to re-tile: %v", err)
+			}
+		},
 	}
 
-	// Spawn the terminal
-	termConfig := workspace.TerminalConfig{
-		WMClass:   termClass,
-		Cwd:       workDir,
-		SlotIndex: newSlot,
+	added, err := batch.run(*count, newSlot)
+	if insertMode && createTmux && len(added) < *count {
+		unshiftInsertedSessions(tmux, wsInfo.Name, newSlot, wsInfo.TerminalCount, *count, len(added))
 	}
-	if err := spawnTerminalWithCommand(termConfig, res.Config.TerminalSpawnCommands, cmdOverride); err != nil {
+	if len(added) == 0 {
+		if err == nil {
+			err = fmt.Errorf("terminal spawned but window not detected")
+		}
 		fmt.Fprintln(os.Stderr, err)
 		return 1
 	}
 
-	// Wait for the new terminal to appear
-	newWindowIDs, err := waitForNewTerminal(lister, existing, time.Duration(*timeout)*time.Second)
-	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		return 1
+	if len(added) == 1 {
+		fmt.Printf("Added terminal (slot %d) to workspace %q\n", newSlot, wsInfo.Name)
+	} else {
+		fmt.Printf("Added %d terminals (slots %d-%d) to workspace %q\n", len(added), newSlot, newSlot+len(added)-1, wsInfo.Name)
 	}
-	if len(newWindowIDs) == 0 {
-		fmt.Fprintln(os.Stderr, "terminal spawned but window not detected")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "added %d of %d terminals: %v\n", len(added), *count, err)
 		return 1
 	}
+	return 0
+}
 
-	// Update workspace state
-	if insertMode {
-		err = workspace.InsertTerminalAtSlot(wsInfo.Desktop, newSlot, createTmux)
-	} else {
-		_, err = workspace.AddTerminalToWorkspace(wsInfo.Desktop, createTmux)
+// terminalBatch spawns terminals into consecutive slots, waits for all of
+// their windows and re-tiles once at the end.
+type terminalBatch struct {
+	spawn    func(slot int) error
+	wait     func(want int) ([]uint32, error)
+	register func(slot int)
+	retile   func(newWindowIDs []uint32)
+}
+
+// run adds count terminals starting at firstSlot and returns the new window
+// IDs. If a spawn fails or not every window appears, the terminals that did
+// appear are still registered and tiled, and the error is returned with them.
+func (b terminalBatch) run(count, firstSlot int) ([]uint32, error) {
+	spawned := 0
+	var spawnErr error
+	for i := 0; i < count; i++ {
+		if err := b.spawn(firstSlot + i); err != nil {
+			spawnErr = err
+			break
+		}
+		spawned++
 	}
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "warning: failed to update workspace state: %v\n", err)
+	if spawned == 0 {
+		return nil, spawnErr
 	}
 
-	// Get current layout from daemon
-	layoutName := savedWs.Layout
-	if status, err := applier.client.GetStatus(); err == nil && status.ActiveLayout != "" {
-		layoutName = status.ActiveLayout
+	newWindowIDs, waitErr := b.wait(spawned)
+	if len(newWindowIDs) > spawned {
+		newWindowIDs = newWindowIDs[:spawned]
+	}
+	if len(newWindowIDs) == 0 {
+		return nil, errors.Join(spawnErr, waitErr)
 	}
 
-	// Re-tile all terminals
-	if insertMode && len(newWindowIDs) > 0 {
-		// For insert mode, we need to specify the window order
-		// Sort existing windows by their visual position (Y then X)
-		sort.Slice(before, func(i, j int) bool {
-			if before[i].Y != before[j].Y {
-				return before[i].Y < before[j].Y
-			}
-			return before[i].X < before[j].X
-		})
+	for i := range newWindowIDs {
+		b.register(firstSlot + i)
+	}
+	b.retile(newWindowIDs)
+	return newWindowIDs, errors.Join(spawnErr, waitErr)
+}
 
-		// Build window order with new window inserted at the correct position
-		windowOrder := make([]uint32, 0, len(before)+1)
-		for i, w := range before {
-			if i == newSlot {
-				windowOrder = append(windowOrder, newWindowIDs[0])
-			}
-			windowOrder = append(windowOrder, w.WindowID)
-		}
-		// If inserting at the end (shouldn't happen in insertMode, but handle it)
-		if newSlot >= len(before) {
-			windowOrder = append(windowOrder, newWindowIDs[0])
+// unshiftInsertedSessions undoes part of the shift made before an insert:
+// the sessions of slots firstSlot..oldCount-1 were moved up by shifted, but
+// only added terminals appeared, so they move back down to sit added slots
+// up, matching the registry.
+func unshiftInsertedSessions(mux agent.Multiplexer, workspaceName string, firstSlot, oldCount, shifted, added int) {
+	for i := firstSlot; i < oldCount; i++ {
+		if err := shiftSlotSession(mux, workspaceName, i+shifted, workspaceName, i+added); err != nil {
+			warnf("%v", err)
 		}
+	}
+}
 
-		if err := applier.ApplyLayoutWithOrder(layoutName, windowOrder); err != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to re-tile: %v\n", err)
-		}
-	} else {
-		if err := applier.ApplyLayout(layoutName, true); err != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to re-tile: %v\n", err)
+// insertWindowOrder returns the tiling order for existing windows (already in
+// slot order) with newWindowIDs inserted at slot.
+func insertWindowOrder(existing []workspace.TerminalWindow, slot int, newWindowIDs []uint32) []uint32 {
+	order := make([]uint32, 0, len(existing)+len(newWindowIDs))
+	for i, w := range existing {
+		if i == slot {
+			order = append(order, newWindowIDs...)
 		}
+		order = append(order, w.WindowID)
 	}
-
-	// Log add-terminal action
-	logTerminalAction(agent.ActionAddTerminal, wsInfo.Name, newSlot, nil)
-
-	fmt.Printf("Added terminal (slot %d) to workspace %q\n", newSlot, wsInfo.Name)
-	return 0
+	// If inserting at the end (shouldn't happen in insert mode, but handle it)
+	if slot >= len(existing) {
+		order = append(order, newWindowIDs...)
+	}
+	return order
 }
 
 func runTerminalRemove(args []string) int {
@@ -768,11 +1348,13 @@ func runTerminalRemove(args []string) int {
 		fmt.Fprintln(os.Stderr, "Examples:")
 		fmt.Fprintln(os.Stderr, "  termtile terminal remove --slot 2     # Remove terminal at slot 2")
 		fmt.Fprintln(os.Stderr, "  termtile terminal remove --last       # Remove the highest numbered slot")
+		fmt.Fprintln(os.Stderr, "  termtile terminal remove --slot -2    # Remove the second-to-last slot")
 		fmt.Fprintln(os.Stderr, "  termtile terminal remove --slot 1 --force  # Force remove even if busy")
 	}
 	path := fs.String("path", "", "Config file path")
 	workspaceName := fs.String("workspace", "", "Target workspace name (default: workspace on current desktop)")
-	slot := fs.Int("slot", -1, "Slot index to remove")
+	var slot slotFlag
+	fs.Var(&slot, "slot", "Slot index to remove (negative counts from the end, -1 = last)")
 	last := fs.Bool("last", false, "Remove the last/highest slot")
 	force := fs.Bool("force", false, "Skip confirmation for non-empty tmux sessions")
 
@@ -784,12 +1366,12 @@ func runTerminalRemove(args []string) int {
 	}
 
 	// Validate flags
-	if *slot < 0 && !*last {
+	if !slot.set && !*last {
 		fmt.Fprintln(os.Stderr, "either --slot N or --last is required")
 		fs.Usage()
 		return 2
 	}
-	if *slot >= 0 && *last {
+	if slot.set && *last {
 		fmt.Fprintln(os.Stderr, "--slot and --last are mutually exclusive")
 		return 2
 	}
@@ -844,9 +1426,16 @@ func runTerminalRemove(args []string) int {
 	}
 
 	// Determine slot to remove
-	targetSlot := *slot
+	targetSlot := slot.value
 	if *last {
 		targetSlot = wsInfo.TerminalCount - 1
+	} else if targetSlot < 0 {
+		resolved, err := agent.ResolveSlot(targetSlot, wsInfo.TerminalCount)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		targetSlot = resolved
 	}
 
 	if targetSlot < 0 || targetSlot >= wsInfo.TerminalCount {
@@ -861,17 +1450,21 @@ func runTerminalRemove(args []string) int {
 		return 1
 	}
 
-	// Check if slot has active tmux session
-	session := agent.SessionName(wsInfo.Name, targetSlot)
-	hasSession, _ := agent.HasSession(session)
+	// Check if slot has an active multiplexer session
+	mux := agent.MultiplexerFor(res.Config)
+	session := mcp.SlotSessionName(wsInfo.Name, targetSlot)
+	hasSession, _ := mux.HasSession(session)
 
 	if hasSession && !*force {
-		// Check if session is busy
-		status, err := agent.GetSessionStatus(session)
-		if err == nil && status.Exists && !status.IsIdle {
-			fmt.Fprintf(os.Stderr, "slot %d has running process (%s); use --force to remove anyway\n",
-				targetSlot, status.CurrentCommand)
-			return 1
+		// Check if session is busy; multiplexers that cannot report it are
+		// treated as idle.
+		if reporter, ok := mux.(agent.StatusReporter); ok {
+			status, err := reporter.GetSessionStatus(session)
+			if err == nil && status.Exists && !status.IsIdle {
+				fmt.Fprintf(os.Stderr, "slot %d has running process (%s); use --force to remove anyway\n",
+					targetSlot, status.CurrentCommand)
+				return 1
+			}
 		}
 	}
 
@@ -897,17 +1490,17 @@ func runTerminalRemove(args []string) int {
 		return 1
 	}
 
-	// For agent-mode terminals, killing tmux will close the window automatically
+	// For agent-mode terminals, killing the session will close the window automatically
 	// For non-agent terminals, we need to close the window via the backend
 	if hasSession {
-		// Kill tmux session - this will close the terminal window automatically
-		if err := exec.Command("tmux", "kill-session", "-t", session).Run(); err != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to kill tmux session: %v\n", err)
+		// Kill the session - this will close the terminal window automatically
+		if err := mux.KillSession(session); err != nil {
+			warnf("failed to kill %s session: %v", mux.Name(), err)
 		}
 		// Give the window time to close
 		time.Sleep(200 * time.Millisecond)
 	} else {
-		// No tmux session - close the window via platform backend
+		// No session - close the window via platform backend
 		targetWindow := windows[targetSlot]
 		if err := closeWindowViaBackend(backend, targetWindow.WindowID); err != nil {
 			fmt.Fprintf(os.Stderr, "failed to close window: %v\n", err)
@@ -918,22 +1511,16 @@ func runTerminalRemove(args []string) int {
 	// Shift remaining tmux sessions DOWN to keep IDs matching visual positions
 	// If removing slot 2 from [0,1,2,3,4], shift: 3→2, 4→3
 	if wsInfo.AgentMode && targetSlot < wsInfo.TerminalCount-1 {
-		tmux := agent.NewTmuxMultiplexer()
 		for i := targetSlot + 1; i < wsInfo.TerminalCount; i++ {
-			oldSession := agent.SessionName(wsInfo.Name, i)
-			newSession := agent.SessionName(wsInfo.Name, i-1)
-
-			if exists, _ := tmux.HasSession(oldSession); exists {
-				if err := tmux.RenameSession(oldSession, newSession); err != nil {
-					fmt.Fprintf(os.Stderr, "warning: failed to shift session %s to %s: %v\n", oldSession, newSession, err)
-				}
+			if err := shiftSlotSession(mux, wsInfo.Name, i, wsInfo.Name, i-1); err != nil {
+				warnf("%v", err)
 			}
 		}
 	}
 
 	// Update workspace state
 	if err := workspace.RemoveTerminalFromWorkspace(wsInfo.Desktop, targetSlot); err != nil {
-		fmt.Fprintf(os.Stderr, "warning: failed to update workspace state: %v\n", err)
+		warnf("failed to update workspace state: %v", err)
 	}
 
 	// Re-tile remaining terminals
@@ -947,8 +1534,10 @@ func runTerminalRemove(args []string) int {
 	// Small delay to let window close
 	time.Sleep(100 * time.Millisecond)
 
-	if err := applier.ApplyLayout(layoutName, true); err != nil {
-		fmt.Fprintf(os.Stderr, "warning: failed to re-tile: %v\n", err)
+	if !skipAutoTile(wsInfo.Name) {
+		if err := applier.ApplyLayout(layoutName, true); err != nil && !errors.Is(err, tiling.ErrNoTerminals) {
+			warnf("failed to re-tile: %v", err)
+		}
 	}
 
 	// Log remove-terminal action
@@ -991,9 +1580,10 @@ func runTerminalMove(args []string) int {
 		return 2
 	}
 
-	// Check tmux availability
-	if err := agent.RequireTmux(); err != nil {
-		fmt.Fprintln(os.Stderr, "tmux not available:", err)
+	// Check multiplexer availability
+	mux := terminalMultiplexer()
+	if !mux.Available() {
+		fmt.Fprintf(os.Stderr, "%s is not available in PATH\n", mux.Name())
 		return 1
 	}
 
@@ -1040,11 +1630,11 @@ func runTerminalMove(args []string) int {
 	}
 
 	// Find and move X11 window
-	oldSessionName := agent.SessionName(srcWsInfo.Name, *slot)
+	oldSessionName := mcp.SlotSessionName(srcWsInfo.Name, *slot)
 	if srcWsInfo.Desktop != dstWsInfo.Desktop {
 		if windowID, err := platform.FindWindowByTitleStandalone(oldSessionName); err == nil && windowID != 0 {
 			if err := platform.MoveWindowToDesktopStandalone(windowID, dstWsInfo.Desktop); err != nil {
-				fmt.Fprintf(os.Stderr, "warning: failed to move window to desktop %d: %v\n", dstWsInfo.Desktop, err)
+				warnf("failed to move window to desktop %d: %v", dstWsInfo.Desktop, err)
 			}
 		}
 	}
@@ -1056,11 +1646,9 @@ func runTerminalMove(args []string) int {
 		return 1
 	}
 
-	// Rename tmux session
-	newSessionName := agent.SessionName(dstWsInfo.Name, newSlot)
-	tmux := agent.NewTmuxMultiplexer()
-	if err := tmux.RenameSession(oldSessionName, newSessionName); err != nil {
-		fmt.Fprintf(os.Stderr, "warning: failed to rename tmux session: %v\n", err)
+	// Rename the session and move its agent artifacts to the new slot
+	if err := shiftSlotSession(mux, srcWsInfo.Name, *slot, dstWsInfo.Name, newSlot); err != nil {
+		warnf("%v", err)
 	}
 
 	// Retile via IPC
@@ -1069,10 +1657,10 @@ func runTerminalMove(args []string) int {
 	if status, err := client.GetStatus(); err == nil && status.ActiveLayout != "" {
 		layoutName = status.ActiveLayout
 	}
-	if layoutName != "" {
+	if layoutName != "" && !skipAutoTile(srcWsInfo.Name, dstWsInfo.Name) {
 		time.Sleep(300 * time.Millisecond)
-		if err := client.ApplyLayout(layoutName, true); err != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to re-tile: %v\n", err)
+		if err := client.ApplyLayout(layoutName, true); err != nil && !errors.Is(err, tiling.ErrNoTerminals) {
+			warnf("failed to re-tile: %v", err)
 		}
 	}
 
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/1broseidon/termtile/internal/agent"
+	"github.com/1broseidon/termtile/internal/workspace"
+)
+
+// fakeTerminalBatch records the calls a terminalBatch makes. spawnFailAt is
+// the slot whose spawn fails (-1 for none) and appear caps how many windows
+// the wait reports.
+type fakeTerminalBatch struct {
+	spawnFailAt int
+	appear      int
+
+	spawned    []int
+	waits      []int
+	registered []int
+	retiles    [][]uint32
+}
+
+func (f *fakeTerminalBatch) batch() terminalBatch {
+	return terminalBatch{
+		spawn: func(slot int) error {
+			if slot == f.spawnFailAt {
+				return errors.New("spawn failed")
+			}
+			f.spawned = append(f.spawned, slot)
+			return nil
+		},
+		wait: func(want int) ([]uint32, error) {
+			f.waits = append(f.waits, want)
+			n := want
+			if f.appear < n {
+				n = f.appear
+			}
+			ids := make([]uint32, n)
+			for i := range ids {
+				ids[i] = uint32(100 + i)
+			}
+			if n < want {
+				return ids, errors.New("timeout waiting for new terminals")
+			}
+			return ids, nil
+		},
+		register: func(slot int) { f.registered = append(f.registered, slot) },
+		retile:   func(ids []uint32) { f.retiles = append(f.retiles, ids) },
+	}
+}
+
+func TestTerminalBatch_SpawnsCountAndRetilesOnce(t *testing.T) {
+	f := &fakeTerminalBatch{spawnFailAt: -1, appear: 10}
+
+	added, err := f.batch().run(3, 2)
+	if err != nil {
+		t.Fatalf("run() err=%v", err)
+	}
+	if !reflect.DeepEqual(f.spawned, []int{2, 3, 4}) {
+		t.Fatalf("spawned slots=%v, want [2 3 4]", f.spawned)
+	}
+	if !reflect.DeepEqual(f.waits, []int{3}) {
+		t.Fatalf("waits=%v, want a single wait for 3 windows", f.waits)
+	}
+	if !reflect.DeepEqual(f.registered, []int{2, 3, 4}) {
+		t.Fatalf("registered slots=%v, want [2 3 4]", f.registered)
+	}
+	want := []uint32{100, 101, 102}
+	if !reflect.DeepEqual(f.retiles, [][]uint32{want}) || !reflect.DeepEqual(added, want) {
+		t.Fatalf("retiles=%v added=%v, want one retile with %v", f.retiles, added, want)
+	}
+}
+
+func TestTerminalBatch_PartialBatchStillRetilesOnce(t *testing.T) {
+	// The third spawn fails and only one of the two spawned windows appears.
+	f := &fakeTerminalBatch{spawnFailAt: 2, appear: 1}
+
+	added, err := f.batch().run(3, 0)
+	if err == nil || !strings.Contains(err.Error(), "spawn failed") || !strings.Contains(err.Error(), "timeout") {
+		t.Fatalf("run() err=%v, want spawn and wait errors", err)
+	}
+	if !reflect.DeepEqual(f.waits, []int{2}) {
+		t.Fatalf("waits=%v, want a single wait for the 2 spawned", f.waits)
+	}
+	if !reflect.DeepEqual(f.registered, []int{0}) || len(f.retiles) != 1 || !reflect.DeepEqual(added, []uint32{100}) {
+		t.Fatalf("registered=%v retiles=%v added=%v, want the one window registered and tiled once", f.registered, f.retiles, added)
+	}
+}
+
+func TestTerminalBatch_NothingSpawnedSkipsRetile(t *testing.T) {
+	f := &fakeTerminalBatch{spawnFailAt: 0, appear: 10}
+
+	if _, err := f.batch().run(2, 0); err == nil {
+		t.Fatal("run() succeeded, want spawn error")
+	}
+	if len(f.waits) != 0 || len(f.registered) != 0 || len(f.retiles) != 0 {
+		t.Fatalf("waits=%v registered=%v retiles=%v, want none", f.waits, f.registered, f.retiles)
+	}
+}
+
+func TestInsertWindowOrder(t *testing.T) {
+	existing := []workspace.TerminalWindow{{WindowID: 1}, {WindowID: 2}, {WindowID: 3}}
+
+	got := insertWindowOrder(existing, 1, []uint32{10, 11})
+	if want := []uint32{1, 10, 11, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("insertWindowOrder(slot 1)=%v, want %v", got, want)
+	}
+	got = insertWindowOrder(existing, 3, []uint32{10})
+	if want := []uint32{1, 2, 3, 10}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("insertWindowOrder(slot 3)=%v, want %v", got, want)
+	}
+}
+
+func TestUnshiftInsertedSessions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	// Inserting 3 terminals at slot 1 of [0,1,2] shifted 1->4 and 2->5, but
+	// only one window appeared, so the registry holds 0,new,1,2.
+	mux := &renameMultiplexer{sessions: map[string]string{
+		agent.SessionName("dev", 0): "a",
+		agent.SessionName("dev", 4): "b",
+		agent.SessionName("dev", 5): "c",
+	}}
+	unshiftInsertedSessions(mux, "dev", 1, 3, 3, 1)
+
+	want := map[string]string{
+		agent.SessionName("dev", 0): "a",
+		agent.SessionName("dev", 2): "b",
+		agent.SessionName("dev", 3): "c",
+	}
+	if !reflect.DeepEqual(mux.sessions, want) {
+		t.Fatalf("sessions = %v, want %v", mux.sessions, want)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/1broseidon/termtile/internal/workspace"
+)
+
+type fakeGeometryLister struct {
+	windows []workspace.TerminalWindow
+	err     error
+}
+
+func (f *fakeGeometryLister) ListTerminals() ([]workspace.TerminalWindow, error) {
+	return f.windows, f.err
+}
+
+func (f *fakeGeometryLister) ActiveWindowID() (uint32, error) { return 0, nil }
+
+func TestListTerminalGeometry(t *testing.T) {
+	lister := &fakeGeometryLister{windows: []workspace.TerminalWindow{
+		{WindowID: 0x1a00003, WMClass: "kitty", X: 0, Y: 0, Width: 960, Height: 1080},
+		{WindowID: 0x1c00007, WMClass: "kitty", X: 960, Y: 0, Width: 960, Height: 540},
+	}}
+
+	got, err := listTerminalGeometry(lister)
+	if err != nil {
+		t.Fatalf("listTerminalGeometry: %v", err)
+	}
+	want := []TerminalGeometry{
+		{Slot: 0, WindowID: 0x1a00003, X: 0, Y: 0, Width: 960, Height: 1080},
+		{Slot: 1, WindowID: 0x1c00007, X: 960, Y: 0, Width: 960, Height: 540},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("entry %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	var out bytes.Buffer
+	printTerminalGeometry(&out, got)
+	if !strings.Contains(out.String(), "[1] 0x1c00007  960x540+960+0") {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+}
+
+func TestListTerminalGeometry_ListerError(t *testing.T) {
+	lister := &fakeGeometryLister{err: errors.New("no active display")}
+	if _, err := listTerminalGeometry(lister); err == nil {
+		t.Fatal("expected lister error")
+	}
+
+	var out bytes.Buffer
+	printTerminalGeometry(&out, nil)
+	if out.Len() != 0 {
+		t.Fatalf("expected no geometry section, got:\n%s", out.String())
+	}
+}
+
+func TestListTerminalGeometry_EmptyWorkspaceIsList(t *testing.T) {
+	got, err := listTerminalGeometry(&fakeGeometryLister{})
+	if err != nil {
+		t.Fatalf("listTerminalGeometry: %v", err)
+	}
+	data, err := json.Marshal(map[string]interface{}{"terminals": got})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(data) != `{"terminals":[]}` {
+		t.Fatalf("JSON = %s, want an empty terminals list", data)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+type fakeClipboard struct {
+	text string
+	err  error
+}
+
+func (f *fakeClipboard) Name() string          { return "fake" }
+func (f *fakeClipboard) Read() (string, error) { return f.text, f.err }
+
+type pasteCall struct {
+	session string
+	text    string
+	enter   bool
+}
+
+type fakePaster struct {
+	calls []pasteCall
+}
+
+func (f *fakePaster) PasteText(session, text string, enter bool) error {
+	f.calls = append(f.calls, pasteCall{session: session, text: text, enter: enter})
+	return nil
+}
+
+func TestPasteClipboardSendsLiteralText(t *testing.T) {
+	p := &fakePaster{}
+
+	text := "echo 'quoted' \"$HOME\"\nsecond line"
+	n, err := pasteClipboard(&fakeClipboard{text: text}, p, "termtile-dev-1", false)
+	if err != nil {
+		t.Fatalf("pasteClipboard: %v", err)
+	}
+	if n != len(text) {
+		t.Fatalf("n=%d, want %d", n, len(text))
+	}
+	if len(p.calls) != 1 {
+		t.Fatalf("paste calls=%d, want 1", len(p.calls))
+	}
+	got := p.calls[0]
+	if got.session != "termtile-dev-1" || got.text != text || got.enter {
+		t.Fatalf("unexpected paste call: %+v", got)
+	}
+}
+
+func TestPasteClipboardHonoursEnter(t *testing.T) {
+	p := &fakePaster{}
+
+	if _, err := pasteClipboard(&fakeClipboard{text: "ls"}, p, "termtile-dev-0", true); err != nil {
+		t.Fatalf("pasteClipboard: %v", err)
+	}
+	if len(p.calls) != 1 || !p.calls[0].enter {
+		t.Fatalf("expected a single paste with enter, got %+v", p.calls)
+	}
+}
+
+func TestPasteClipboardErrorsSkipSend(t *testing.T) {
+	p := &fakePaster{}
+
+	if _, err := pasteClipboard(&fakeClipboard{text: ""}, p, "s", false); err == nil {
+		t.Fatalf("expected error for empty clipboard")
+	}
+	if _, err := pasteClipboard(&fakeClipboard{err: errors.New("xclip failed")}, p, "s", false); err == nil {
+		t.Fatalf("expected reader error to propagate")
+	}
+	if len(p.calls) != 0 {
+		t.Fatalf("expected no paste calls, got %+v", p.calls)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1broseidon/termtile/internal/mcp"
+)
+
+func TestPrintEndedSlotOutput(t *testing.T) {
+	ended := mcp.EndedOutput{
+		SessionName: "termtile-dev-1",
+		Output:      "line one\nline two\nfinal answer",
+		EndedAt:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := printEndedSlotOutput(&stdout, &stderr, ended, 2, "", false); code != 0 {
+		t.Fatalf("exit code = %d, want 0", code)
+	}
+	if got := stdout.String(); strings.Contains(got, "line one") || !strings.Contains(got, "final answer") {
+		t.Fatalf("stdout = %q, want the last 2 lines", got)
+	}
+	if !strings.Contains(stderr.String(), "termtile-dev-1") {
+		t.Fatalf("stderr = %q, want the ended session named", stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if code := printEndedSlotOutput(&stdout, &stderr, ended, 50, "final answer", false); code != 0 {
+		t.Fatalf("wait-for present: exit code = %d, want 0", code)
+	}
+	if code := printEndedSlotOutput(&stdout, &stderr, ended, 50, "never printed", false); code != 1 {
+		t.Fatalf("wait-for missing: exit code = %d, want 1", code)
+	}
+}
+
+func TestFormatReadOutput_CleanVsRaw(t *testing.T) {
+	raw := "\n╭──────────╮\nfirst\x07 line\n\n\n\n\nsecond line\n+----------+\nlast line\n\n"
+
+	if got := formatReadOutput(raw, false, 2); got != raw {
+		t.Fatalf("raw output = %q, want it unchanged", got)
+	}
+
+	want := "second line\nlast line\n"
+	if got := formatReadOutput(raw, true, 2); got != want {
+		t.Fatalf("clean output = %q, want %q", got, want)
+	}
+	if got := formatReadOutput(raw, true, 0); got != "first line\n\n\nsecond line\nlast line\n" {
+		t.Fatalf("clean output without line limit = %q", got)
+	}
+	if got := formatReadOutput("──────\n\n", true, 10); got != "" {
+		t.Fatalf("clean output of chrome only = %q, want empty", got)
+	}
+}
+
+func TestPrintEndedSlotOutput_Clean(t *testing.T) {
+	ended := mcp.EndedOutput{
+		SessionName: "termtile-dev-1",
+		Output:      "answer\n══════\n",
+		EndedAt:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	var stdout, stderr bytes.Buffer
+	if code := printEndedSlotOutput(&stdout, &stderr, ended, 50, "", true); code != 0 {
+		t.Fatalf("exit code = %d, want 0", code)
+	}
+	if got := stdout.String(); got != "answer\n" {
+		t.Fatalf("stdout = %q, want cleaned output", got)
+	}
+}
+
+func TestPrintLastResponse(t *testing.T) {
+	out := "[termtile-response]\nold\n[/termtile-response]\n[termtile-response]\nnew\n[/termtile-response]\n> "
+
+	var stdout, stderr bytes.Buffer
+	if code := printLastResponse(&stdout, &stderr, out); code != 0 {
+		t.Fatalf("exit code = %d, want 0 (stderr %q)", code, stderr.String())
+	}
+	if got := stdout.String(); got != "new\n" {
+		t.Fatalf("stdout = %q, want the last response", got)
+	}
+
+	// A response still being written is skipped for the last complete one.
+	stdout.Reset()
+	if code := printLastResponse(&stdout, &stderr, out+"\n[termtile-response]\npartial"); code != 0 || stdout.String() != "new\n" {
+		t.Fatalf("unclosed block: code %d, stdout %q; want the last complete response", code, stdout.String())
+	}
+
+	stdout.Reset()
+	if code := printLastResponse(&stdout, &stderr, "no fences here\n"); code != 1 {
+		t.Fatalf("no response: exit code = %d, want 1", code)
+	}
+	if stdout.Len() != 0 {
+		t.Fatalf("no response: stdout = %q, want empty", stdout.String())
+	}
+}
+
+func TestReadCaptureLines_LastResponseReadsScrollback(t *testing.T) {
+	for _, tt := range []struct {
+		args []string
+		want int
+	}{
+		{args: []string{"--last-response"}, want: -1},
+		{args: []string{"--last-response", "--lines", "200"}, want: 200},
+		{args: []string{"--lines", "30"}, want: 30},
+	} {
+		fs := flag.NewFlagSet("read", flag.ContinueOnError)
+		lines := fs.Int("lines", 50, "")
+		lastResponse := fs.Bool("last-response", false, "")
+		if err := fs.Parse(tt.args); err != nil {
+			t.Fatalf("Parse(%v): %v", tt.args, err)
+		}
+		if got := readCaptureLines(fs, *lines, *lastResponse); got != tt.want {
+			t.Errorf("readCaptureLines(%v) = %d, want %d", tt.args, got, tt.want)
+		}
+	}
+}
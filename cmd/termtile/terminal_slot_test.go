@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/1broseidon/termtile/internal/agent"
+	"github.com/1broseidon/termtile/internal/workspace"
+)
+
+func TestSlotFlagAcceptsNegativeIndex(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var slot slotFlag
+	fs.Var(&slot, "slot", "")
+
+	if err := fs.Parse([]string{"--slot", "-2", "hello"}); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !slot.set || slot.value != -2 {
+		t.Fatalf("slot = %+v, want set -2", slot)
+	}
+	if fs.Arg(0) != "hello" {
+		t.Fatalf("args = %v, want [hello]", fs.Args())
+	}
+
+	var unset slotFlag
+	if unset.String() != "" {
+		t.Fatalf("unset String() = %q, want empty", unset.String())
+	}
+}
+
+func TestResolveTerminalSessionNegativeSlot(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	if err := workspace.SetActiveWorkspace("dev", 3, true, 1, []int{0, 1, 2}); err != nil {
+		t.Fatalf("SetActiveWorkspace: %v", err)
+	}
+
+	wsInfo := &agent.WorkspaceInfo{Name: "dev", AgentMode: true, AgentSlots: []int{0, 1, 2}, TerminalCount: 3}
+	tests := []struct {
+		name      string
+		workspace string
+		wsInfo    *agent.WorkspaceInfo
+		slot      int
+		wantSlot  int
+	}{
+		{name: "current desktop last", wsInfo: wsInfo, slot: -1, wantSlot: 2},
+		{name: "current desktop second to last", wsInfo: wsInfo, slot: -2, wantSlot: 1},
+		{name: "named workspace last", workspace: "dev", slot: -1, wantSlot: 2},
+		{name: "positive unchanged", wsInfo: wsInfo, slot: 1, wantSlot: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, idx, err := resolveTerminalSession(tt.workspace, slotFlag{value: tt.slot, set: true}, tt.wsInfo)
+			if err != nil {
+				t.Fatalf("resolveTerminalSession: %v", err)
+			}
+			if idx != tt.wantSlot {
+				t.Fatalf("slot = %d, want %d", idx, tt.wantSlot)
+			}
+			if want := agent.SessionName("dev", tt.wantSlot); session != want {
+				t.Fatalf("session = %q, want %q", session, want)
+			}
+		})
+	}
+
+	if _, _, err := resolveTerminalSession("dev", slotFlag{value: -4, set: true}, nil); err == nil {
+		t.Fatal("expected out-of-range error for --slot -4")
+	}
+	if _, _, err := resolveTerminalSession("", slotFlag{}, wsInfo); err == nil {
+		t.Fatal("expected error when --slot is missing")
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatchTerminalStatusRedrawsUntilCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	provider := func() ([]TerminalWorkspaceStatus, error) {
+		calls++
+		switch calls {
+		case 2:
+			return nil, errors.New("tmux not available: server exited")
+		case 3:
+			cancel()
+		}
+		return []TerminalWorkspaceStatus{{
+			Name:          "agents",
+			Desktop:       1,
+			TerminalCount: 1,
+			Slots: []TerminalSlotStatus{{
+				Slot:        0,
+				SessionName: "termtile-agents-0",
+				Exists:      true,
+				IsIdle:      true,
+			}},
+		}}, nil
+	}
+
+	var out bytes.Buffer
+	ticks := make(chan time.Time)
+	done := make(chan struct{})
+	go func() {
+		watchTerminalStatusTicks(ctx, &out, time.Second, ticks, provider)
+		close(done)
+	}()
+
+	// The first draw happens up front; each tick drives one more. The third
+	// draw cancels, so no further tick is sent.
+	for i := 0; i < 2; i++ {
+		select {
+		case ticks <- time.Now():
+		case <-time.After(5 * time.Second):
+			t.Fatalf("watch loop did not take tick %d", i+1)
+		}
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("watch loop did not stop after cancel")
+	}
+
+	if calls != 3 {
+		t.Fatalf("provider calls=%d, want 3", calls)
+	}
+	got := out.String()
+	if n := strings.Count(got, clearScreen); n != 3 {
+		t.Fatalf("redraws=%d, want 3", n)
+	}
+	if !strings.Contains(got, "tmux not available: server exited") {
+		t.Fatalf("expected provider error to be rendered, got:\n%s", got)
+	}
+	if n := strings.Count(got, "[0] termtile-agents-0: idle"); n != 2 {
+		t.Fatalf("slot rows=%d, want 2; output:\n%s", n, got)
+	}
+}
+
+func TestRunTerminalStatusRejectsWatchWithJSON(t *testing.T) {
+	if rc := runTerminalStatus([]string{"--watch", "--json"}); rc != 2 {
+		t.Fatalf("rc=%d, want 2", rc)
+	}
+}
+
+func TestSlotActivityInStatus(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var slot TerminalSlotStatus
+	setSlotActivity(&slot, now.Add(-65*time.Minute), now.Add(-12*time.Second), now)
+	if slot.UptimeSeconds != 3900 {
+		t.Fatalf("UptimeSeconds=%d, want 3900", slot.UptimeSeconds)
+	}
+	if slot.SpawnedAt != "2026-03-01T10:55:00Z" || slot.LastActivity != "2026-03-01T11:59:48Z" {
+		t.Fatalf("SpawnedAt=%q LastActivity=%q", slot.SpawnedAt, slot.LastActivity)
+	}
+	if got, want := slotActivitySuffix(slot, now), " (up 1h5m0s, last output 12s ago)"; got != want {
+		t.Fatalf("suffix=%q, want %q", got, want)
+	}
+
+	var unknown TerminalSlotStatus
+	setSlotActivity(&unknown, time.Time{}, time.Time{}, now)
+	if unknown.SpawnedAt != "" || unknown.UptimeSeconds != 0 || unknown.LastActivity != "" {
+		t.Fatalf("unknown times filled fields: %+v", unknown)
+	}
+	if got := slotActivitySuffix(unknown, now); got != "" {
+		t.Fatalf("suffix=%q, want empty", got)
+	}
+}
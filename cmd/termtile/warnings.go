@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/1broseidon/termtile/internal/agent"
+)
+
+// quietEnvVar names the environment variable that, when truthy, has the
+// same effect as the --quiet global flag.
+const quietEnvVar = "TERMTILE_QUIET"
+
+// warnings is the shared sink for non-fatal warnings printed by commands.
+var warnings = &warningSink{out: os.Stderr}
+
+// warningSink prints warnings to out, or with quiet set, records them in the
+// agent action log instead. Warnings raised while the log is being opened
+// are held and written once it is ready.
+type warningSink struct {
+	mu      sync.Mutex
+	out     io.Writer
+	quiet   bool
+	logger  func() *agent.Logger // nil means getTerminalLogger
+	held    bool
+	pending []string
+}
+
+// warnf reports a non-fatal problem through the shared warning sink.
+func warnf(format string, args ...any) {
+	warnings.emit(fmt.Sprintf(format, args...))
+}
+
+func (s *warningSink) setQuiet(quiet bool) {
+	s.mu.Lock()
+	s.quiet = quiet
+	s.mu.Unlock()
+}
+
+func (s *warningSink) emit(msg string) {
+	s.mu.Lock()
+	if !s.quiet {
+		out := s.out
+		s.mu.Unlock()
+		fmt.Fprintln(out, "warning:", msg)
+		return
+	}
+	if s.held {
+		s.pending = append(s.pending, msg)
+		s.mu.Unlock()
+		return
+	}
+	loggerFn := s.logger
+	s.mu.Unlock()
+
+	if loggerFn == nil {
+		loggerFn = getTerminalLogger
+	}
+	logWarning(loggerFn(), msg)
+}
+
+// hold queues quiet warnings until release. getTerminalLogger holds the
+// sink while it loads config, whose warnings would otherwise re-enter it.
+func (s *warningSink) hold() {
+	s.mu.Lock()
+	s.held = true
+	s.mu.Unlock()
+}
+
+// release writes queued warnings to logger and resumes normal delivery.
+func (s *warningSink) release(logger *agent.Logger) {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = nil
+	s.held = false
+	s.mu.Unlock()
+	for _, msg := range pending {
+		logWarning(logger, msg)
+	}
+}
+
+// Write lets the sink stand in for an io.Writer that receives
+// "warning: ..." lines, such as the config loader's warning output.
+func (s *warningSink) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		line = strings.TrimPrefix(strings.TrimSpace(line), "warning:")
+		if line = strings.TrimSpace(line); line != "" {
+			s.emit(line)
+		}
+	}
+	return len(p), nil
+}
+
+func logWarning(logger *agent.Logger, msg string) {
+	if logger != nil {
+		logger.Log(agent.ActionWarning, "", -1, map[string]interface{}{"message": msg})
+	}
+}
+
+// quietFromEnv reports whether TERMTILE_QUIET is set to a truthy value.
+func quietFromEnv() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(quietEnvVar))) {
+	case "1", "true", "yes", "y", "on":
+		return true
+	default:
+		return false
+	}
+}
+
+// parseGlobalFlags consumes the global flags that precede the command name
+// and returns the remaining arguments.
+func parseGlobalFlags(args []string) (rest []string, quiet bool) {
+	quiet = quietFromEnv()
+	for len(args) > 0 {
+		switch args[0] {
+		case "-q", "--quiet", "-quiet":
+			quiet = true
+		default:
+			return args, quiet
+		}
+		args = args[1:]
+	}
+	return args, quiet
+}
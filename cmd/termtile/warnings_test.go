@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/1broseidon/termtile/internal/agent"
+	"github.com/1broseidon/termtile/internal/config"
+)
+
+func newTestWarningLog(t *testing.T) (*agent.Logger, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "actions.log")
+	logger, err := agent.NewLogger(agent.LogConfig{
+		Enabled:   true,
+		Level:     agent.LevelDebug,
+		FilePath:  path,
+		MaxSizeMB: 1,
+		MaxFiles:  1,
+	})
+	if err != nil {
+		t.Fatalf("NewLogger: %v", err)
+	}
+	t.Cleanup(func() { logger.Close() })
+	return logger, path
+}
+
+func readWarningLog(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	return string(data)
+}
+
+func TestWarningSink_PrintsToOutput(t *testing.T) {
+	var out bytes.Buffer
+	sink := &warningSink{out: &out, logger: func() *agent.Logger {
+		t.Fatal("logger used without quiet")
+		return nil
+	}}
+
+	sink.emit("failed to re-tile: boom")
+	if got, want := out.String(), "warning: failed to re-tile: boom\n"; got != want {
+		t.Fatalf("output = %q, want %q", got, want)
+	}
+}
+
+func TestWarningSink_QuietRoutesToLog(t *testing.T) {
+	logger, path := newTestWarningLog(t)
+	var out bytes.Buffer
+	sink := &warningSink{out: &out, quiet: true, logger: func() *agent.Logger { return logger }}
+
+	sink.emit("failed to re-tile: boom")
+	if _, err := sink.Write([]byte("warning: preferred_terminal not found\n")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	if out.Len() != 0 {
+		t.Fatalf("quiet sink printed %q", out.String())
+	}
+	log := readWarningLog(t, path)
+	for _, want := range []string{
+		`[WARNING] message="failed to re-tile: boom"`,
+		`[WARNING] message="preferred_terminal not found"`,
+	} {
+		if !strings.Contains(log, want) {
+			t.Fatalf("log missing %q:\n%s", want, log)
+		}
+	}
+}
+
+func TestWarningSink_HoldsWarningsRaisedWhileOpeningLog(t *testing.T) {
+	logger, path := newTestWarningLog(t)
+	var out bytes.Buffer
+	sink := &warningSink{out: &out, quiet: true}
+	sink.logger = func() *agent.Logger {
+		// Mirrors getTerminalLogger: loading config may warn before the
+		// logger exists.
+		sink.hold()
+		sink.emit("config warning during init")
+		sink.release(logger)
+		return logger
+	}
+
+	sink.emit("after init")
+
+	if out.Len() != 0 {
+		t.Fatalf("quiet sink printed %q", out.String())
+	}
+	log := readWarningLog(t, path)
+	first := strings.Index(log, "config warning during init")
+	second := strings.Index(log, "after init")
+	if first < 0 || second < 0 || first > second {
+		t.Fatalf("log = %q, want held warning followed by the triggering one", log)
+	}
+}
+
+func TestRunConfigValidate_QuietSuppressesWarnings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "warned.yaml")
+	if err := os.WriteFile(path, []byte("preferred_terminal: no-such-terminal\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	devnull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devnull.Close()
+	stdout := os.Stdout
+	os.Stdout = devnull
+	defer func() { os.Stdout = stdout }()
+	defer config.SetWarningOutput(os.Stderr)
+
+	for _, quiet := range []bool{false, true} {
+		logger, logPath := newTestWarningLog(t)
+		var out bytes.Buffer
+		config.SetWarningOutput(&warningSink{out: &out, quiet: quiet, logger: func() *agent.Logger { return logger }})
+
+		if rc := runConfig([]string{"validate", "--path", path}); rc != 0 {
+			t.Fatalf("quiet=%v: validate exit=%d, want 0", quiet, rc)
+		}
+		printed := strings.Contains(out.String(), "no-such-terminal")
+		logged := strings.Contains(readWarningLog(t, logPath), "no-such-terminal")
+		if printed == quiet || logged != quiet {
+			t.Fatalf("quiet=%v: printed=%v logged=%v (output %q)", quiet, printed, logged, out.String())
+		}
+	}
+}
+
+func TestParseGlobalFlags(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       string
+		args      []string
+		wantRest  []string
+		wantQuiet bool
+	}{
+		{name: "none", args: []string{"status"}, wantRest: []string{"status"}},
+		{name: "long flag", args: []string{"--quiet", "workspace", "list"}, wantRest: []string{"workspace", "list"}, wantQuiet: true},
+		{name: "short flag", args: []string{"-q", "status"}, wantRest: []string{"status"}, wantQuiet: true},
+		{name: "command flag untouched", args: []string{"terminal", "-q"}, wantRest: []string{"terminal", "-q"}},
+		{name: "env", env: "1", args: []string{"status"}, wantRest: []string{"status"}, wantQuiet: true},
+		{name: "env false", env: "false", args: []string{"status"}, wantRest: []string{"status"}},
+		{name: "flag only", args: []string{"--quiet"}, wantRest: []string{}, wantQuiet: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(quietEnvVar, tt.env)
+			rest, quiet := parseGlobalFlags(tt.args)
+			if !reflect.DeepEqual(rest, tt.wantRest) || quiet != tt.wantQuiet {
+				t.Fatalf("parseGlobalFlags(%q) = (%q, %v), want (%q, %v)", tt.args, rest, quiet, tt.wantRest, tt.wantQuiet)
+			}
+		})
+	}
+}
@@ -6,14 +6,15 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/1broseidon/termtile/internal/agent"
 	"github.com/1broseidon/termtile/internal/config"
 	"github.com/1broseidon/termtile/internal/ipc"
+	"github.com/1broseidon/termtile/internal/mcp"
 	"github.com/1broseidon/termtile/internal/platform"
 	"github.com/1broseidon/termtile/internal/terminals"
 	"github.com/1broseidon/termtile/internal/workspace"
@@ -56,8 +57,8 @@ func (l *platformTerminalLister) ListTerminals() ([]workspace.TerminalWindow, er
 
 	out := make([]workspace.TerminalWindow, 0, len(terms))
 	for _, t := range terms {
-		pid := 0
-		if l.xu != nil {
+		pid := t.PID
+		if pid == 0 && l.xu != nil {
 			if p, err := ewmh.WmPidGet(l.xu, xproto.Window(t.WindowID)); err == nil {
 				pid = int(p)
 			}
@@ -65,8 +66,11 @@ func (l *platformTerminalLister) ListTerminals() ([]workspace.TerminalWindow, er
 		out = append(out, workspace.TerminalWindow{
 			WindowID: uint32(t.WindowID),
 			WMClass:  t.Class,
+			Title:    t.Title,
 			X:        t.X,
 			Y:        t.Y,
+			Width:    t.Width,
+			Height:   t.Height,
 			PID:      pid,
 		})
 	}
@@ -74,6 +78,17 @@ func (l *platformTerminalLister) ListTerminals() ([]workspace.TerminalWindow, er
 	return out, nil
 }
 
+// MonitorBounds reports the bounds of the active display, whose terminals
+// ListTerminals returns.
+func (l *platformTerminalLister) MonitorBounds() (workspace.Rect, error) {
+	display, err := l.backend.ActiveDisplay()
+	if err != nil {
+		return workspace.Rect{}, err
+	}
+	b := display.Bounds
+	return workspace.Rect{X: b.X, Y: b.Y, Width: b.Width, Height: b.Height}, nil
+}
+
 func (l *platformTerminalLister) ActiveWindowID() (uint32, error) {
 	win, err := l.backend.ActiveWindow()
 	return uint32(win), err
@@ -115,6 +130,19 @@ func (m *platformWindowMinimizer) MinimizeWindow(windowID uint32) error {
 	return m.backend.Minimize(platform.WindowID(windowID))
 }
 
+type platformWindowMover struct {
+	backend platform.Backend
+}
+
+func (m *platformWindowMover) MoveResizeWindow(windowID uint32, rect workspace.Rect) error {
+	return m.backend.MoveResize(platform.WindowID(windowID), platform.Rect{
+		X:      rect.X,
+		Y:      rect.Y,
+		Width:  rect.Width,
+		Height: rect.Height,
+	})
+}
+
 type ipcLayoutApplier struct {
 	client *ipc.Client
 }
@@ -135,7 +163,7 @@ func newTerminalLister(backend platform.Backend, cfg *config.Config) *platformTe
 	}
 	return &platformTerminalLister{
 		backend:  backend,
-		detector: terminals.NewDetector(cfg.TerminalClassNames()),
+		detector: terminals.NewMatchDetector(terminalClassMatches(cfg)),
 		xu:       xu,
 	}
 }
@@ -146,13 +174,20 @@ func runWorkspace(args []string) int {
 		fmt.Fprintln(os.Stderr, "  termtile workspace new [flags] <name>     Create and launch a new workspace")
 		fmt.Fprintln(os.Stderr, "  termtile workspace save [flags] <name>    Save current terminal state")
 		fmt.Fprintln(os.Stderr, "  termtile workspace load [flags] <name>    Load a saved workspace")
-		fmt.Fprintln(os.Stderr, "  termtile workspace close <name>           Close active workspace")
+		fmt.Fprintln(os.Stderr, "  termtile workspace close [flags] <name>   Close active workspace")
+		fmt.Fprintln(os.Stderr, "  termtile workspace focus <name>           Switch to an active workspace's desktop")
+		fmt.Fprintln(os.Stderr, "  termtile workspace reattach <name>        Reopen windows for detached agent sessions")
 		fmt.Fprintln(os.Stderr, "  termtile workspace list                   List saved workspaces")
-		fmt.Fprintln(os.Stderr, "  termtile workspace delete <name>          Delete a saved workspace")
+		fmt.Fprintln(os.Stderr, "  termtile workspace delete [flags] <name>  Delete a saved workspace")
+		fmt.Fprintln(os.Stderr, "  termtile workspace prune [flags]          Delete saved workspaces that are not open")
 		fmt.Fprintln(os.Stderr, "  termtile workspace rename <old> <new>     Rename a workspace")
+		fmt.Fprintln(os.Stderr, "  termtile workspace restore [flags] <name> List snapshots or roll back to one")
+		fmt.Fprintln(os.Stderr, "  termtile workspace enable-agent <name>    Turn on agent mode (tmux sessions) for a workspace")
+		fmt.Fprintln(os.Stderr, "  termtile workspace disable-agent <name>   Turn off agent mode and kill its sessions")
 		fmt.Fprintln(os.Stderr, "  termtile workspace init --workspace <name> Initialize project workspace config")
 		fmt.Fprintln(os.Stderr, "  termtile workspace link --workspace <name> Link project to a canonical workspace")
 		fmt.Fprintln(os.Stderr, "  termtile workspace sync pull|push          Sync project view pull/push")
+		fmt.Fprintln(os.Stderr, "  termtile workspace registry export|import  Back up or restore the desktop registry")
 		fmt.Fprintln(os.Stderr, "")
 		fmt.Fprintln(os.Stderr, "Run 'termtile workspace <command> --help' for command-specific options.")
 		return 2
@@ -182,19 +217,29 @@ func runWorkspace(args []string) int {
 			fs.PrintDefaults()
 			fmt.Fprintln(os.Stderr, "")
 			fmt.Fprintln(os.Stderr, "Examples:")
-			fmt.Fprintln(os.Stderr, "  termtile workspace new myproject              # 3 terminals in current directory")
+			fmt.Fprintln(os.Stderr, "  termtile workspace new myproject              # Fill the layout (3 if it has no cap) in current directory")
 			fmt.Fprintln(os.Stderr, "  termtile workspace new -n 4 dev               # 4 terminals")
 			fmt.Fprintln(os.Stderr, "  termtile workspace new -n 2 --cwd ~/code api  # 2 terminals in ~/code")
 			fmt.Fprintln(os.Stderr, "  termtile workspace new --agent-mode agents    # With tmux sessions for agent control")
+			fmt.Fprintln(os.Stderr, "  termtile workspace new --slot-cwd 0:~/web,1:~/api --slot-cmd 0:nvim dev")
 		}
 		path := fs.String("path", "", "Config file path")
-		numTerminals := fs.Int("n", 3, "Number of terminal windows to create")
+		var numTerminals terminalCountFlag
+		fs.Var(&numTerminals, "n", "Number of terminal windows to create (default: the layout's capacity, or 3 if it has none)")
 		cwd := fs.String("cwd", "", "Working directory for all terminals (default: current directory)")
 		layout := fs.String("layout", "", "Layout to use (default: active or config default)")
 		agentMode := fs.Bool("agent-mode", false, "Create tmux sessions for inter-terminal agent control")
 		terminalClass := fs.String("terminal", "", "Terminal class to use (default: resolved from config and system defaults)")
 		ignoreLimits := fs.Bool("ignore-limits", false, "Ignore configured workspace limits")
 		timeout := fs.Int("timeout", 10, "Spawn synchronization timeout in seconds")
+		keepPartial := fs.Bool("keep-partial", false, "Leave already-spawned terminals open if creation fails")
+		defaultAgent := fs.String("default-agent", "", "Agent type spawn_agent uses for this workspace when agent_type is omitted")
+		env := envFlag{}
+		fs.Var(env, "env", "Set KEY=VALUE in every agent spawned in this workspace (repeatable)")
+		slotCwd := slotMapFlag{values: map[int]string{}, list: true}
+		fs.Var(&slotCwd, "slot-cwd", "Per-slot working directory as SLOT:DIR, comma-separated or repeated (e.g. 0:~/web,1:~/api)")
+		slotCmd := slotMapFlag{values: map[int]string{}}
+		fs.Var(&slotCmd, "slot-cmd", "Per-slot startup command as SLOT:COMMAND (repeatable)")
 
 		if err := fs.Parse(args[1:]); err != nil {
 			if err == flag.ErrHelp {
@@ -222,6 +267,28 @@ func runWorkspace(args []string) int {
 			return 1
 		}
 
+		if err := validateDefaultAgent(*defaultAgent, res.Config); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+
+		// Determine layout
+		layoutName := *layout
+		if layoutName == "" {
+			// Try to get active layout from daemon
+			if status, err := ipc.NewClient().GetStatus(); err == nil && status.ActiveLayout != "" {
+				layoutName = status.ActiveLayout
+			} else {
+				layoutName = res.Config.DefaultLayout
+			}
+		}
+
+		// Without -n, fill the layout.
+		terminalCount := numTerminals.n
+		if !numTerminals.set {
+			terminalCount = newWorkspaceTerminalCount(res.Config, layoutName)
+		}
+
 		if !*ignoreLimits {
 			activeWs, err := workspace.GetActiveWorkspace()
 			if err != nil || activeWs.Name == "" {
@@ -230,7 +297,7 @@ func runWorkspace(args []string) int {
 					return 1
 				}
 			}
-			if err := workspace.CheckCanCreateTerminals(name, *numTerminals, res.Config); err != nil {
+			if err := workspace.CheckCanCreateTerminals(name, terminalCount, res.Config); err != nil {
 				fmt.Fprintln(os.Stderr, "cannot create workspace:", err)
 				return 1
 			}
@@ -246,17 +313,6 @@ func runWorkspace(args []string) int {
 			}
 		}
 
-		// Determine layout
-		layoutName := *layout
-		if layoutName == "" {
-			// Try to get active layout from daemon
-			if status, err := ipc.NewClient().GetStatus(); err == nil && status.ActiveLayout != "" {
-				layoutName = status.ActiveLayout
-			} else {
-				layoutName = res.Config.DefaultLayout
-			}
-		}
-
 		// Determine terminal class
 		termClass := *terminalClass
 		if termClass == "" {
@@ -269,17 +325,18 @@ func runWorkspace(args []string) int {
 
 		// Build workspace config
 		ws := &workspace.WorkspaceConfig{
-			Name:      name,
-			Layout:    layoutName,
-			AgentMode: *agentMode,
-			Terminals: make([]workspace.TerminalConfig, *numTerminals),
-		}
-		for i := 0; i < *numTerminals; i++ {
-			ws.Terminals[i] = workspace.TerminalConfig{
-				WMClass:   termClass,
-				Cwd:       workDir,
-				SlotIndex: i,
-			}
+			Name:         name,
+			Layout:       layoutName,
+			AgentMode:    *agentMode,
+			DefaultAgent: strings.TrimSpace(*defaultAgent),
+		}
+		if len(env) > 0 {
+			ws.Env = env
+		}
+		ws.Terminals, err = buildNewWorkspaceTerminals(terminalCount, termClass, workDir, slotCwd.values, slotCmd.values)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
 		}
 
 		// Connect to display
@@ -311,7 +368,10 @@ func runWorkspace(args []string) int {
 			Timeout:              time.Duration(*timeout) * time.Second,
 			AutoSaveLayout:       autoSaveLayout,
 			AutoSaveTerminalSort: res.Config.TerminalSort,
+			NoAutoSave:           !res.Config.AutosavePrevious,
 			AppConfig:            res.Config,
+			KeepPartial:          *keepPartial,
+			RerunCommand:         len(slotCmd.values) > 0,
 		}); err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			return 1
@@ -319,7 +379,7 @@ func runWorkspace(args []string) int {
 
 		// Save the workspace config
 		if err := workspace.Write(ws); err != nil {
-			fmt.Fprintln(os.Stderr, "warning: workspace created but failed to save:", err)
+			warnf("workspace created but failed to save: %v", err)
 		}
 
 		// Collect agent slots for agent-mode workspaces
@@ -332,23 +392,39 @@ func runWorkspace(args []string) int {
 
 		// Record active workspace on current desktop with agent slots
 		if err := workspace.SetActiveWorkspace(ws.Name, len(ws.Terminals), ws.AgentMode, -1, agentSlots); err != nil {
-			fmt.Fprintln(os.Stderr, "warning:", err)
+			warnf("%v", err)
 		}
 
 		// Log workspace creation
 		logWorkspaceAction(agent.ActionWorkspaceNew, name, -1, map[string]interface{}{
-			"terminals": *numTerminals,
+			"terminals": terminalCount,
 		})
 
-		fmt.Printf("Created workspace %q with %d terminals\n", name, *numTerminals)
+		fmt.Printf("Created workspace %q with %d terminals\n", name, terminalCount)
 		return 0
 
 	case "delete":
-		if len(args) < 2 {
+		fs := flag.NewFlagSet("delete", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		dryRun := fs.Bool("dry-run", false, "List the files delete would remove without removing them")
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+		if fs.NArg() < 1 {
 			fmt.Fprintln(os.Stderr, "workspace delete requires <name>")
 			return 2
 		}
-		if err := workspace.Delete(args[1]); err != nil {
+		name := fs.Arg(0)
+		if *dryRun {
+			paths, err := workspace.DeleteTargets(name)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+			printDeleteDryRun(os.Stdout, paths)
+			return 0
+		}
+		if err := workspace.Delete(name); err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			return 1
 		}
@@ -360,6 +436,11 @@ func runWorkspace(args []string) int {
 		path := fs.String("path", "", "Config file path (default: ~/.config/termtile/config.yaml)")
 		includeCmd := fs.Bool("cmd", false, "Also capture /proc/PID/cmdline (best-effort)")
 		agentMode := fs.Bool("agent-mode", false, "Spawn this workspace inside tmux sessions for inter-terminal agent control")
+		defaultAgent := fs.String("default-agent", "", "Agent type spawn_agent uses for this workspace when agent_type is omitted (default: keep the saved value)")
+		env := envFlag{}
+		fs.Var(env, "env", "Set KEY=VALUE in every agent spawned in this workspace (repeatable; merged into the saved env)")
+		noSync := fs.Bool("no-sync", false, "Skip the project's push_on_workspace_save sync")
+		geometry := fs.String("geometry", "", "Also save terminal rects: relative (monitor ratios) or absolute (pixels) (default: keep the saved value)")
 		if err := fs.Parse(args[1:]); err != nil {
 			return 2
 		}
@@ -368,6 +449,16 @@ func runWorkspace(args []string) int {
 			return 2
 		}
 		name := fs.Arg(0)
+		geometryMode, err := workspace.NormalizeGeometryMode(*geometry)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		if geometryMode == "" {
+			if saved, err := workspace.Read(name); err == nil {
+				geometryMode = saved.GeometryMode
+			}
+		}
 
 		// Check if there's a workspace on the current desktop
 		activeWs, err := workspace.GetActiveWorkspace()
@@ -391,6 +482,11 @@ func runWorkspace(args []string) int {
 			return 1
 		}
 
+		if err := validateDefaultAgent(*defaultAgent, res.Config); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+
 		layout := res.Config.DefaultLayout
 		if status, err := ipc.NewClient().GetStatus(); err == nil && status.ActiveLayout != "" {
 			layout = status.ActiveLayout
@@ -405,17 +501,48 @@ func runWorkspace(args []string) int {
 
 		lister := newTerminalLister(backend, res.Config)
 
-		ws, err := workspace.Save(name, layout, res.Config.TerminalSort, *includeCmd, lister)
+		ws, err := workspace.Save(name, layout, res.Config.TerminalSort, *includeCmd, geometryMode, lister)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			return 1
 		}
 		// Preserve agent mode from active workspace state, or use explicit flag
 		ws.AgentMode = *agentMode || activeWs.AgentMode
+		// Keep spawn_agent session_name overrides instead of the derived names
+		for i, term := range ws.Terminals {
+			if session := mcp.SlotSessionName(activeWs.Name, term.SlotIndex); !agent.IsDerivedSessionName(session) {
+				ws.Terminals[i].SessionName = session
+			}
+		}
+		ws.DefaultAgent = strings.TrimSpace(*defaultAgent)
+		if saved, err := workspace.Read(name); err == nil {
+			if ws.DefaultAgent == "" {
+				ws.DefaultAgent = saved.DefaultAgent
+			}
+			ws.Env = saved.Env
+			ws.AutoTile = saved.AutoTile
+		}
+		if len(env) > 0 {
+			if ws.Env == nil {
+				ws.Env = make(map[string]string, len(env))
+			}
+			for k, v := range env {
+				ws.Env[k] = v
+			}
+		}
+		if _, err := workspace.SnapshotWorkspace(ws.Name, res.Config.WorkspaceHistoryDepth); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
 		if err := workspace.Write(ws); err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			return 1
 		}
+		if !*noSync {
+			if err := autoSyncProject(ws.Name, "push"); err != nil {
+				warnf("project sync push failed: %v", err)
+			}
+		}
 		return 0
 
 	case "load":
@@ -426,6 +553,8 @@ func runWorkspace(args []string) int {
 		rerun := fs.Bool("rerun", false, "If your spawn template includes {{cmd}}, substitute the saved cmdline")
 		noReplace := fs.Bool("no-replace", false, "Add new terminals without minimizing existing ones or auto-saving to _previous")
 		ignoreLimits := fs.Bool("ignore-limits", false, "Ignore configured workspace limits")
+		keepPartial := fs.Bool("keep-partial", false, "Leave already-spawned terminals open if the load fails")
+		noSync := fs.Bool("no-sync", false, "Skip the project's pull_on_workspace_load sync")
 		if err := fs.Parse(args[1:]); err != nil {
 			return 2
 		}
@@ -489,7 +618,7 @@ func runWorkspace(args []string) int {
 
 		autoSaveLayout := ""
 		autoSaveTerminalSort := ""
-		if !*noReplace && ws.Name != "_previous" {
+		if !*noReplace && ws.Name != "_previous" && res.Config.AutosavePrevious {
 			autoSaveLayout = res.Config.DefaultLayout
 			if status, err := applier.client.GetStatus(); err == nil && status.ActiveLayout != "" {
 				autoSaveLayout = status.ActiveLayout
@@ -504,6 +633,10 @@ func runWorkspace(args []string) int {
 
 			AutoSaveLayout:       autoSaveLayout,
 			AutoSaveTerminalSort: autoSaveTerminalSort,
+			NoAutoSave:           !res.Config.AutosavePrevious,
+			AppConfig:            res.Config,
+			KeepPartial:          *keepPartial,
+			Mover:                &platformWindowMover{backend: backend},
 		}); err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			return 1
@@ -519,17 +652,29 @@ func runWorkspace(args []string) int {
 
 		// Record active workspace on current desktop with agent slots
 		if err := workspace.SetActiveWorkspace(ws.Name, len(ws.Terminals), ws.AgentMode, -1, agentSlots); err != nil {
-			fmt.Fprintln(os.Stderr, "warning:", err)
+			warnf("%v", err)
+		}
+
+		if !*noSync {
+			if err := autoSyncProject(ws.Name, "pull"); err != nil {
+				warnf("project sync pull failed: %v", err)
+			}
 		}
 
 		return 0
 
 	case "close":
-		if len(args) < 2 {
+		fs := flag.NewFlagSet("close", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		dryRun := fs.Bool("dry-run", false, "List the windows, sessions, and registry entry close would affect without closing anything")
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+		if fs.NArg() < 1 {
 			fmt.Fprintln(os.Stderr, "workspace close requires <name>")
 			return 2
 		}
-		name := args[1]
+		name := fs.Arg(0)
 
 		// Verify this is the active workspace on the current desktop
 		activeWs, err := workspace.GetActiveWorkspace()
@@ -562,15 +707,32 @@ func runWorkspace(args []string) int {
 
 		lister := newTerminalLister(backend, res.Config)
 
+		// Enumerate everything close touches before acting, so --dry-run
+		// reports exactly what a real close would do.
+		windows, err := workspace.CloseTargets(lister)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if *dryRun {
+			slots, err := workspace.GetSlotsByDesktop(activeWs.Desktop)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+			printCloseDryRun(os.Stdout, activeWs, windows, slots)
+			return 0
+		}
+
 		// Close all terminal windows
-		if err := workspace.CloseTerminals(lister); err != nil {
+		if err := workspace.CloseWindows(windows); err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			return 1
 		}
 
 		// Clear workspace state on current desktop
 		if err := workspace.ClearWorkspace(-1); err != nil {
-			fmt.Fprintln(os.Stderr, "warning:", err)
+			warnf("%v", err)
 		}
 
 		// Log workspace close
@@ -580,14 +742,28 @@ func runWorkspace(args []string) int {
 
 		return 0
 
+	case "focus":
+		return runWorkspaceFocus(args[1:])
+	case "reattach":
+		return runWorkspaceReattach(args[1:])
+	case "prune":
+		return runWorkspacePrune(args[1:])
 	case "rename":
 		return runWorkspaceRename(args[1:])
+	case "restore":
+		return runWorkspaceRestore(args[1:])
+	case "enable-agent":
+		return runWorkspaceAgentMode(args[1:], true)
+	case "disable-agent":
+		return runWorkspaceAgentMode(args[1:], false)
 	case "init":
 		return runProjectInit(args[1:])
 	case "link":
 		return runProjectLink(args[1:])
 	case "sync":
 		return runProjectSync(args[1:])
+	case "registry":
+		return runWorkspaceRegistry(args[1:])
 
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown workspace subcommand: %s\n", args[0])
@@ -949,6 +1125,50 @@ func projectSyncPush(workspaceName string, include []string, localCfgPath string
 	return workspace.Write(ws)
 }
 
+// autoSyncProject runs the sync that the current project enables for a
+// workspace event: "pull" after load (sync.pull_on_workspace_load) and
+// "push" after save (sync.push_on_workspace_save). It does nothing outside a
+// project, when the project is linked to a different workspace, or when the
+// flag is off.
+func autoSyncProject(workspaceName, mode string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	cfgPath := filepath.Join(findProjectRootFrom(cwd), projectDirName, projectWorkspaceCfgFile)
+	if !exists(cfgPath) {
+		return nil
+	}
+	projectCfg, err := readProjectWorkspaceConfig(cfgPath)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(projectCfg.Workspace) != workspaceName {
+		return nil
+	}
+
+	var enabled bool
+	var run func(string, []string, string) error
+	switch mode {
+	case "pull":
+		enabled, run = projectCfg.Sync.PullOnWorkspaceLoad, projectSyncPull
+	case "push":
+		enabled, run = projectCfg.Sync.PushOnWorkspaceSave, projectSyncPush
+	default:
+		return fmt.Errorf("unknown sync mode %q (expected pull or push)", mode)
+	}
+	if !enabled {
+		return nil
+	}
+
+	include, err := normalizeProjectSyncInclude(projectCfg.Sync.Include)
+	if err != nil {
+		return err
+	}
+	localCfgPath := filepath.Join(filepath.Dir(cfgPath), projectLocalCfgFile)
+	return run(workspaceName, include, localCfgPath)
+}
+
 func resolveProjectRootForInit() (string, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -1146,104 +1366,91 @@ func decodeStrictYAMLFile(path string, out any) error {
 	return nil
 }
 
-// closeWindowViaBackend closes a window using the platform backend.
-func closeWindowViaBackend(backend platform.Backend, windowID uint32) error {
-	return backend.Close(platform.WindowID(windowID))
-}
+// envFlag collects repeated --env KEY=VALUE flags.
+type envFlag map[string]string
 
-// spawnTerminalWithCommand spawns a terminal with an optional command override.
-func spawnTerminalWithCommand(term workspace.TerminalConfig, templates map[string]string, cmdOverride string) error {
-	class := strings.TrimSpace(term.WMClass)
-	if class == "" {
-		return fmt.Errorf("terminal WMClass is empty")
+func (f envFlag) String() string {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
 	}
-
-	template, ok := lookupSpawnTemplate(templates, class)
-	if !ok {
-		return fmt.Errorf("no spawn template configured for terminal class %q (set terminal_spawn_commands.%s)", class, class)
-	}
-	if cmdOverride != "" && !strings.Contains(template, "{{cmd}}") {
-		return fmt.Errorf("spawn template for %q must include {{cmd}} for agent-mode workspaces (set terminal_spawn_commands.%s)", class, class)
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+f[k])
 	}
+	return strings.Join(pairs, ",")
+}
 
-	cwd := strings.TrimSpace(term.Cwd)
-	if cwd == "" {
-		home, _ := os.UserHomeDir()
-		cwd = home
+func (f envFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return fmt.Errorf("expected KEY=VALUE, got %q", value)
 	}
+	f[key] = val
+	return nil
+}
 
-	argv, err := renderCommandTemplate(template, cwd, cmdOverride)
-	if err != nil {
-		return fmt.Errorf("failed to render spawn template for %q: %w", class, err)
+// validateDefaultAgent checks that a --default-agent value names a
+// configured agent. An empty value is always valid.
+func validateDefaultAgent(agentType string, cfg *config.Config) error {
+	agentType = strings.TrimSpace(agentType)
+	if agentType == "" {
+		return nil
 	}
-	if len(argv) == 0 {
-		return fmt.Errorf("spawn template for %q produced empty command", class)
+	if _, ok := cfg.Agents[agentType]; ok {
+		return nil
 	}
-
-	cmd := exec.Command(argv[0], argv[1:]...)
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("failed to spawn %q: %w", class, err)
+	available := make([]string, 0, len(cfg.Agents))
+	for name := range cfg.Agents {
+		available = append(available, name)
 	}
-	return nil
+	sort.Strings(available)
+	return fmt.Errorf("unknown --default-agent %q; available: %v", agentType, available)
 }
 
-// lookupSpawnTemplate looks up spawn template for a terminal class.
-func lookupSpawnTemplate(templates map[string]string, class string) (string, bool) {
-	if templates == nil {
-		return "", false
+// printCloseDryRun lists what workspace close would do to ws: the terminals
+// it kills, the sessions left without a window, and the registry entry it
+// clears.
+func printCloseDryRun(w io.Writer, ws workspace.WorkspaceInfo, windows []workspace.TerminalWindow, slots []workspace.SlotInfo) {
+	for _, win := range windows {
+		fmt.Fprintf(w, "would close window %d (pid %d, %s) %q\n", win.WindowID, win.PID, win.WMClass, win.Title)
 	}
-	if v, ok := templates[class]; ok {
-		return v, true
-	}
-	if v, ok := templates[strings.ToLower(class)]; ok {
-		return v, true
-	}
-	lower := strings.ToLower(class)
-	for k, v := range templates {
-		if strings.ToLower(k) == lower {
-			return v, true
+	sort.Slice(slots, func(i, j int) bool { return slots[i].SlotIndex < slots[j].SlotIndex })
+	for _, slot := range slots {
+		if slot.SessionName == "" {
+			continue
 		}
+		fmt.Fprintf(w, "would orphan session %s (slot %d); the daemon reconciler kills it once its window is gone\n", slot.SessionName, slot.SlotIndex)
 	}
-	return "", false
+	fmt.Fprintf(w, "would clear workspace %q from desktop %d\n", ws.Name, ws.Desktop)
 }
 
-// renderCommandTemplate renders a spawn command template with directory and command.
-func renderCommandTemplate(template, dir, cmd string) ([]string, error) {
-	argv, err := splitCommand(template)
-	if err != nil {
-		return nil, err
+// printDeleteDryRun lists the files workspace delete would remove.
+func printDeleteDryRun(w io.Writer, paths []string) {
+	for _, path := range paths {
+		fmt.Fprintf(w, "would delete %s\n", path)
 	}
+}
 
-	argvOut := make([]string, 0, len(argv))
-	for _, arg := range argv {
-		hadCmdPlaceholder := strings.Contains(arg, "{{cmd}}")
-		arg = strings.ReplaceAll(arg, "{{dir}}", dir)
-		if cmd != "" {
-			arg = strings.ReplaceAll(arg, "{{cmd}}", cmd)
-		} else {
-			arg = strings.ReplaceAll(arg, "{{cmd}}", "")
-		}
-		arg = strings.TrimSpace(arg)
-		if arg == "" {
-			if hadCmdPlaceholder && cmd == "" && len(argvOut) > 0 {
-				prev := argvOut[len(argvOut)-1]
-				if strings.HasPrefix(prev, "-") {
-					argvOut = argvOut[:len(argvOut)-1]
-				}
-			}
-			continue
-		}
-		if hadCmdPlaceholder && cmd != "" {
-			parts, err := splitCommand(arg)
-			if err == nil && len(parts) > 0 {
-				argvOut = append(argvOut, parts...)
-				continue
-			}
-		}
-		argvOut = append(argvOut, arg)
-	}
+// closeWindowViaBackend closes a window using the platform backend.
+func closeWindowViaBackend(backend platform.Backend, windowID uint32) error {
+	return backend.Close(platform.WindowID(windowID))
+}
 
-	return argvOut, nil
+// spawnTerminalWithCommand spawns a terminal with an optional command
+// override. env (from terminal_spawn_env) is added to the terminal's
+// environment.
+func spawnTerminalWithCommand(term workspace.TerminalConfig, templates map[string]string, cmdOverride string, env []string) error {
+	cmd, err := workspace.TerminalSpawnCmd(term, templates, false, cmdOverride, env)
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to spawn %q: %w", strings.TrimSpace(term.WMClass), err)
+	}
+	return nil
 }
 
 // splitCommand splits a shell command string into arguments.
@@ -1323,8 +1530,9 @@ func shellQuote(s string) string {
 	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
 }
 
-// waitForNewTerminal waits for a single new terminal to appear.
-func waitForNewTerminal(lister *platformTerminalLister, existing map[uint32]struct{}, timeout time.Duration) ([]uint32, error) {
+// waitForNewTerminals polls lister until want windows not in existing have
+// appeared. On timeout it returns the windows seen so far with an error.
+func waitForNewTerminals(lister workspace.TerminalLister, existing map[uint32]struct{}, want int, timeout time.Duration) ([]uint32, error) {
 	deadline := time.Now().Add(timeout)
 	ticker := time.NewTicker(150 * time.Millisecond)
 	defer ticker.Stop()
@@ -1348,13 +1556,13 @@ func waitForNewTerminal(lister *platformTerminalLister, existing map[uint32]stru
 					newIDs = append(newIDs, w.WindowID)
 				}
 			}
-			if len(newIDs) >= 1 {
+			if len(newIDs) >= want {
 				return newIDs, nil
 			}
 		}
 
 		if time.Now().After(deadline) {
-			return newIDs, fmt.Errorf("timeout waiting for new terminal (%d seen after %s)", len(newIDs), timeout)
+			return newIDs, fmt.Errorf("timeout waiting for new terminals (%d/%d seen after %s)", len(newIDs), want, timeout)
 		}
 		<-ticker.C
 	}
@@ -1411,14 +1619,20 @@ func runWorkspaceRename(args []string) int {
 	}
 
 	// Rename live tmux sessions first (can fail, easier to rollback)
-	tmux := agent.NewTmuxMultiplexer()
+	tmux := terminalMultiplexer()
 	for i, term := range cfg.Terminals {
-		oldSession := agent.SessionName(oldName, term.SlotIndex)
-		newSession := agent.SessionName(newName, term.SlotIndex)
+		oldSession := strings.TrimSpace(term.SessionName)
+		if oldSession == "" {
+			oldSession = agent.SessionName(oldName, term.SlotIndex)
+		}
+		newSession := retargetSession(oldSession, newName, term.SlotIndex)
+		if newSession == oldSession {
+			continue
+		}
 
 		if exists, _ := tmux.HasSession(oldSession); exists {
 			if err := tmux.RenameSession(oldSession, newSession); err != nil {
-				fmt.Fprintf(os.Stderr, "warning: failed to rename tmux session %s: %v\n", oldSession, err)
+				warnf("failed to rename tmux session %s: %v", oldSession, err)
 			}
 		}
 		cfg.Terminals[i].SessionName = newSession
@@ -1435,7 +1649,13 @@ func runWorkspaceRename(args []string) int {
 
 	// Delete old config file
 	if err := os.Remove(oldPath); err != nil {
-		fmt.Fprintf(os.Stderr, "warning: failed to remove old config: %v\n", err)
+		warnf("failed to remove old config: %v", err)
+	}
+	if err := workspace.RenameHistory(oldName, newName); err != nil {
+		warnf("%v", err)
+	}
+	if err := mcp.MoveWorkspaceArtifacts(oldName, newName); err != nil {
+		warnf("failed to move agent artifacts: %v", err)
 	}
 
 	// Update runtime state if this workspace is active
@@ -1451,3 +1671,84 @@ func runWorkspaceRename(args []string) int {
 	fmt.Printf("Renamed workspace %q to %q\n", oldName, newName)
 	return 0
 }
+
+func runWorkspaceRestore(args []string) int {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	path := fs.String("path", "", "Config file path (default: ~/.config/termtile/config.yaml)")
+	snapshot := fs.String("snapshot", "", "Snapshot to restore (default: list snapshots)")
+	noSync := fs.Bool("no-sync", false, "Skip the project's push_on_workspace_save sync")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: termtile workspace restore <name> [--snapshot TS] [--no-sync]")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Without --snapshot, lists the snapshots kept for <name>, newest first.")
+		fmt.Fprintln(os.Stderr, "With --snapshot, replaces the saved workspace with that snapshot; the")
+		fmt.Fprintln(os.Stderr, "replaced save becomes a snapshot itself. Snapshots are kept by")
+		fmt.Fprintln(os.Stderr, "'workspace save' when workspace_history_depth is set.")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Flags:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return 2
+	}
+	name := fs.Arg(0)
+	// Accept flags after the name too.
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	if *snapshot == "" {
+		snapshots, err := workspace.ListSnapshots(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if len(snapshots) == 0 {
+			fmt.Fprintf(os.Stderr, "workspace %q has no snapshots (set workspace_history_depth to keep them)\n", name)
+			return 1
+		}
+		for _, s := range snapshots {
+			fmt.Printf("- %s\n", s)
+		}
+		return 0
+	}
+
+	var res *config.LoadResult
+	var err error
+	if *path == "" {
+		res, err = config.LoadWithSources()
+	} else {
+		res, err = config.LoadFromPath(*path)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if _, err := workspace.RestoreSnapshot(name, *snapshot, res.Config.WorkspaceHistoryDepth); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if !*noSync {
+		if err := autoSyncProject(name, "push"); err != nil {
+			warnf("project sync push failed: %v", err)
+		}
+	}
+	fmt.Printf("restored workspace %q from snapshot %s\n", name, *snapshot)
+	return 0
+}
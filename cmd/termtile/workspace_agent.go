@@ -0,0 +1,231 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/1broseidon/termtile/internal/agent"
+	"github.com/1broseidon/termtile/internal/config"
+	"github.com/1broseidon/termtile/internal/workspace"
+)
+
+// agentSessionOps is what workspace enable-agent/disable-agent need from the
+// multiplexer.
+type agentSessionOps interface {
+	HasSession(session string) (bool, error)
+	// CreateDetached starts session in the background with cwd as its
+	// working directory.
+	CreateDetached(session, cwd string) error
+	KillSession(session string) error
+}
+
+// openAgentSessions returns the multiplexer for enable-agent/disable-agent;
+// tests swap it for a fake.
+var openAgentSessions = func(cfg *config.Config) (agentSessionOps, error) {
+	mgr, err := agent.NewConfigManager(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if mgr.Name() != "tmux" {
+		return nil, fmt.Errorf("agent mode toggling requires tmux (multiplexer is %s)", mgr.Name())
+	}
+	if err := mgr.Initialize(); err != nil {
+		warnf("failed to initialize multiplexer config: %v", err)
+	}
+	return &tmuxAgentSessions{Multiplexer: mgr.Multiplexer(), sessionCommand: mgr.SessionCommand}, nil
+}
+
+type tmuxAgentSessions struct {
+	agent.Multiplexer
+	sessionCommand func(session string) string
+}
+
+func (t *tmuxAgentSessions) CreateDetached(session, cwd string) error {
+	args, err := splitCommand(t.sessionCommand(session))
+	if err != nil {
+		return fmt.Errorf("failed to parse multiplexer command: %w", err)
+	}
+	args = append(args, "-d", "-c", cwd)
+	out, err := exec.Command(args[0], args[1:]...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %w (%s)", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// agentModeChange reports what toggling agent mode did to a workspace.
+type agentModeChange struct {
+	// Active is true when the workspace is open on a desktop; sessions are
+	// only created for active workspaces.
+	Active  bool
+	Created []string
+	Reused  []string
+	Killed  []string
+	// Failed lists per-slot session errors; the flag is flipped regardless.
+	Failed []string
+}
+
+// setWorkspaceAgentMode flips agent mode for name in its saved config and,
+// when it is active, in the desktop registry. Enabling an active workspace
+// starts a detached session for every slot that has none, so later reads and
+// sends have a target and the next load attaches to it; the terminal windows
+// already open stay as they are. Disabling kills the slots' sessions.
+func setWorkspaceAgentMode(name string, enable bool, ops agentSessionOps) (agentModeChange, error) {
+	var change agentModeChange
+	cfg, err := workspace.Read(name)
+	if err != nil {
+		return change, fmt.Errorf("workspace %q not found: %w", name, err)
+	}
+	info, activeErr := workspace.GetWorkspaceByName(name)
+	change.Active = activeErr == nil
+
+	saved := make(map[int]workspace.TerminalConfig, len(cfg.Terminals))
+	for _, term := range cfg.Terminals {
+		saved[term.SlotIndex] = term
+	}
+	sessionFor := func(slot int) string {
+		if s := strings.TrimSpace(saved[slot].SessionName); s != "" {
+			return s
+		}
+		return agent.SessionName(name, slot)
+	}
+
+	var slots []int
+	if change.Active {
+		for slot := 0; slot < info.TerminalCount; slot++ {
+			slots = append(slots, slot)
+		}
+	} else if !enable {
+		for slot := range saved {
+			slots = append(slots, slot)
+		}
+		sort.Ints(slots)
+	}
+
+	var agentSlots []int
+	for _, slot := range slots {
+		session := sessionFor(slot)
+		exists, err := ops.HasSession(session)
+		if err != nil {
+			change.Failed = append(change.Failed, fmt.Sprintf("slot %d: %v", slot, err))
+			continue
+		}
+		switch {
+		case enable && exists:
+			change.Reused = append(change.Reused, session)
+			agentSlots = append(agentSlots, slot)
+		case enable:
+			cwd := strings.TrimSpace(saved[slot].Cwd)
+			if cwd == "" {
+				cwd, _ = os.UserHomeDir()
+			}
+			if err := ops.CreateDetached(session, cwd); err != nil {
+				change.Failed = append(change.Failed, fmt.Sprintf("slot %d: %v", slot, err))
+				continue
+			}
+			change.Created = append(change.Created, session)
+			agentSlots = append(agentSlots, slot)
+		case exists:
+			if err := ops.KillSession(session); err != nil {
+				change.Failed = append(change.Failed, fmt.Sprintf("slot %d: %v", slot, err))
+				continue
+			}
+			change.Killed = append(change.Killed, session)
+		}
+	}
+
+	cfg.AgentMode = enable
+	if err := workspace.Write(cfg); err != nil {
+		return change, err
+	}
+	if change.Active {
+		if err := workspace.SetWorkspaceAgentMode(info.Desktop, enable, agentSlots); err != nil {
+			return change, err
+		}
+	}
+	return change, nil
+}
+
+func runWorkspaceAgentMode(args []string, enable bool) int {
+	cmdName := "disable-agent"
+	if enable {
+		cmdName = "enable-agent"
+	}
+	fs := flag.NewFlagSet(cmdName, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	path := fs.String("path", "", "Config file path")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: termtile workspace %s [flags] <name>\n", cmdName)
+		fmt.Fprintln(os.Stderr, "")
+		if enable {
+			fmt.Fprintln(os.Stderr, "Turns on agent mode for an existing workspace. If it is open, each slot")
+			fmt.Fprintln(os.Stderr, "without a tmux session gets a detached one in its saved cwd. Open")
+			fmt.Fprintln(os.Stderr, "terminal windows are not wrapped; `workspace load` respawns them")
+			fmt.Fprintln(os.Stderr, "attached to their sessions. New terminals get sessions automatically.")
+		} else {
+			fmt.Fprintln(os.Stderr, "Turns off agent mode for a workspace and kills its slots' tmux sessions.")
+			fmt.Fprintln(os.Stderr, "Terminals attached to those sessions lose them.")
+		}
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Flags:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	name := fs.Arg(0)
+
+	var res *config.LoadResult
+	var err error
+	if *path == "" {
+		res, err = config.LoadWithSources()
+	} else {
+		res, err = config.LoadFromPath(*path)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	ops, err := openAgentSessions(res.Config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	change, err := setWorkspaceAgentMode(name, enable, ops)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	for _, msg := range change.Failed {
+		warnf("%s", msg)
+	}
+
+	switch {
+	case enable && change.Active:
+		fmt.Printf("Enabled agent mode for workspace %q: created %d session(s), reused %d\n", name, len(change.Created), len(change.Reused))
+		if len(change.Created) > 0 {
+			fmt.Printf("Open terminals are not attached; run 'termtile workspace load %s' to respawn them in their sessions\n", name)
+		}
+	case enable:
+		fmt.Printf("Enabled agent mode for workspace %q; sessions are created when it is next loaded\n", name)
+	default:
+		fmt.Printf("Disabled agent mode for workspace %q: killed %d session(s)\n", name, len(change.Killed))
+	}
+	if len(change.Failed) > 0 {
+		return 1
+	}
+	return 0
+}
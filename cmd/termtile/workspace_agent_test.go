@@ -0,0 +1,175 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/1broseidon/termtile/internal/agent"
+	"github.com/1broseidon/termtile/internal/workspace"
+)
+
+// fakeAgentSessions tracks live sessions by name and the cwd they were
+// created in.
+type fakeAgentSessions struct {
+	sessions  map[string]string
+	createErr map[string]error
+}
+
+func (f *fakeAgentSessions) HasSession(session string) (bool, error) {
+	_, ok := f.sessions[session]
+	return ok, nil
+}
+
+func (f *fakeAgentSessions) CreateDetached(session, cwd string) error {
+	if err := f.createErr[session]; err != nil {
+		return err
+	}
+	f.sessions[session] = cwd
+	return nil
+}
+
+func (f *fakeAgentSessions) KillSession(session string) error {
+	delete(f.sessions, session)
+	return nil
+}
+
+func writeAgentModeWorkspace(t *testing.T, agentMode bool) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	if err := workspace.Write(&workspace.WorkspaceConfig{
+		Name:      "dev",
+		Layout:    "grid",
+		AgentMode: agentMode,
+		Terminals: []workspace.TerminalConfig{
+			{WMClass: "kitty", Cwd: "/src/web", SlotIndex: 0},
+			{WMClass: "kitty", Cwd: "/src/api", SlotIndex: 1},
+		},
+	}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+}
+
+func TestSetWorkspaceAgentMode_EnableActiveCreatesSessions(t *testing.T) {
+	writeAgentModeWorkspace(t, false)
+	// Slot 2 was added after the last save; slot 1 already has a session.
+	if err := workspace.SetActiveWorkspace("dev", 3, false, 0, nil); err != nil {
+		t.Fatalf("SetActiveWorkspace: %v", err)
+	}
+	ops := &fakeAgentSessions{sessions: map[string]string{agent.SessionName("dev", 1): "/elsewhere"}}
+
+	change, err := setWorkspaceAgentMode("dev", true, ops)
+	if err != nil {
+		t.Fatalf("setWorkspaceAgentMode: %v", err)
+	}
+	if !change.Active {
+		t.Fatal("Active = false, want true")
+	}
+	wantCreated := []string{agent.SessionName("dev", 0), agent.SessionName("dev", 2)}
+	if !reflect.DeepEqual(change.Created, wantCreated) {
+		t.Fatalf("Created = %v, want %v", change.Created, wantCreated)
+	}
+	if want := []string{agent.SessionName("dev", 1)}; !reflect.DeepEqual(change.Reused, want) {
+		t.Fatalf("Reused = %v, want %v", change.Reused, want)
+	}
+	if cwd := ops.sessions[agent.SessionName("dev", 0)]; cwd != "/src/web" {
+		t.Fatalf("slot 0 session cwd = %q, want /src/web", cwd)
+	}
+
+	cfg, err := workspace.Read("dev")
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if !cfg.AgentMode {
+		t.Fatal("saved config AgentMode = false, want true")
+	}
+	info, err := workspace.GetWorkspaceByName("dev")
+	if err != nil {
+		t.Fatalf("GetWorkspaceByName: %v", err)
+	}
+	if !info.AgentMode || !reflect.DeepEqual(info.AgentSlots, []int{0, 1, 2}) || info.TerminalCount != 3 {
+		t.Fatalf("registry = %+v, want agent mode with slots [0 1 2] and 3 terminals", info)
+	}
+}
+
+func TestSetWorkspaceAgentMode_EnableSkipsFailedSlots(t *testing.T) {
+	writeAgentModeWorkspace(t, false)
+	if err := workspace.SetActiveWorkspace("dev", 2, false, 0, nil); err != nil {
+		t.Fatalf("SetActiveWorkspace: %v", err)
+	}
+	ops := &fakeAgentSessions{
+		sessions:  map[string]string{},
+		createErr: map[string]error{agent.SessionName("dev", 0): errors.New("no server")},
+	}
+
+	change, err := setWorkspaceAgentMode("dev", true, ops)
+	if err != nil {
+		t.Fatalf("setWorkspaceAgentMode: %v", err)
+	}
+	if len(change.Failed) != 1 {
+		t.Fatalf("Failed = %v, want one slot 0 failure", change.Failed)
+	}
+	info, _ := workspace.GetWorkspaceByName("dev")
+	if !info.AgentMode || !reflect.DeepEqual(info.AgentSlots, []int{1}) {
+		t.Fatalf("registry = %+v, want agent mode with slots [1]", info)
+	}
+}
+
+func TestSetWorkspaceAgentMode_EnableInactiveOnlyMarksConfig(t *testing.T) {
+	writeAgentModeWorkspace(t, false)
+	ops := &fakeAgentSessions{sessions: map[string]string{}}
+
+	change, err := setWorkspaceAgentMode("dev", true, ops)
+	if err != nil {
+		t.Fatalf("setWorkspaceAgentMode: %v", err)
+	}
+	if change.Active || len(change.Created) != 0 || len(ops.sessions) != 0 {
+		t.Fatalf("change = %+v sessions = %v, want no sessions for an inactive workspace", change, ops.sessions)
+	}
+	cfg, _ := workspace.Read("dev")
+	if !cfg.AgentMode {
+		t.Fatal("saved config AgentMode = false, want true")
+	}
+}
+
+func TestSetWorkspaceAgentMode_DisableKillsSessions(t *testing.T) {
+	writeAgentModeWorkspace(t, true)
+	if err := workspace.SetActiveWorkspace("dev", 2, true, 0, []int{0, 1}); err != nil {
+		t.Fatalf("SetActiveWorkspace: %v", err)
+	}
+	other := agent.SessionName("other", 0)
+	ops := &fakeAgentSessions{sessions: map[string]string{
+		agent.SessionName("dev", 0): "",
+		agent.SessionName("dev", 1): "",
+		other:                       "",
+	}}
+
+	change, err := setWorkspaceAgentMode("dev", false, ops)
+	if err != nil {
+		t.Fatalf("setWorkspaceAgentMode: %v", err)
+	}
+	if len(change.Killed) != 2 {
+		t.Fatalf("Killed = %v, want both dev sessions", change.Killed)
+	}
+	if _, ok := ops.sessions[other]; !ok || len(ops.sessions) != 1 {
+		t.Fatalf("sessions = %v, want only %s left", ops.sessions, other)
+	}
+
+	cfg, _ := workspace.Read("dev")
+	if cfg.AgentMode {
+		t.Fatal("saved config AgentMode = true, want false")
+	}
+	info, _ := workspace.GetWorkspaceByName("dev")
+	if info.AgentMode || len(info.AgentSlots) != 0 || info.TerminalCount != 2 {
+		t.Fatalf("registry = %+v, want agent mode off with no slots and 2 terminals", info)
+	}
+}
+
+func TestSetWorkspaceAgentMode_UnknownWorkspace(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	if _, err := setWorkspaceAgentMode("missing", true, &fakeAgentSessions{}); err == nil {
+		t.Fatal("expected error for unknown workspace")
+	}
+}
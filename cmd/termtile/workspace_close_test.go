@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/1broseidon/termtile/internal/workspace"
+)
+
+func TestWorkspaceDelete_DryRunKeepsFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := workspace.Write(&workspace.WorkspaceConfig{Name: "dev", Layout: "grid"}); err != nil {
+		t.Fatal(err)
+	}
+	paths, err := workspace.DeleteTargets("dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rc := runWorkspace([]string{"delete", "--dry-run", "dev"}); rc != 0 {
+		t.Fatalf("delete --dry-run rc=%d, want 0", rc)
+	}
+	if _, err := os.Stat(paths[0]); err != nil {
+		t.Fatalf("delete --dry-run removed the workspace: %v", err)
+	}
+
+	var out bytes.Buffer
+	printDeleteDryRun(&out, paths)
+	if got := out.String(); got != "would delete "+paths[0]+"\n" {
+		t.Fatalf("dry-run output = %q", got)
+	}
+
+	if rc := runWorkspace([]string{"delete", "--dry-run", "missing"}); rc != 1 {
+		t.Fatalf("delete --dry-run of a missing workspace rc=%d, want 1", rc)
+	}
+}
+
+func TestPrintCloseDryRun(t *testing.T) {
+	ws := workspace.WorkspaceInfo{Name: "dev", Desktop: 2, AgentMode: true}
+	windows := []workspace.TerminalWindow{
+		{WindowID: 10, WMClass: "kitty", Title: "termtile-dev-0", PID: 100},
+		{WindowID: 11, WMClass: "kitty", Title: "termtile-dev-1", PID: 101},
+	}
+	slots := []workspace.SlotInfo{
+		{WindowID: 11, SessionName: "termtile-dev-1", SlotIndex: 1, Desktop: 2},
+		{WindowID: 10, SessionName: "termtile-dev-0", SlotIndex: 0, Desktop: 2},
+	}
+
+	var out bytes.Buffer
+	printCloseDryRun(&out, ws, windows, slots)
+	want := []string{
+		`would close window 10 (pid 100, kitty) "termtile-dev-0"`,
+		`would close window 11 (pid 101, kitty) "termtile-dev-1"`,
+		"would orphan session termtile-dev-0 (slot 0); the daemon reconciler kills it once its window is gone",
+		"would orphan session termtile-dev-1 (slot 1); the daemon reconciler kills it once its window is gone",
+		`would clear workspace "dev" from desktop 2`,
+	}
+	if got := out.String(); got != strings.Join(want, "\n")+"\n" {
+		t.Fatalf("dry-run output:\n%s\nwant:\n%s", got, strings.Join(want, "\n"))
+	}
+}
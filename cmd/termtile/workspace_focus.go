@@ -0,0 +1,118 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/1broseidon/termtile/internal/platform"
+	"github.com/1broseidon/termtile/internal/workspace"
+)
+
+// desktopFocuser is the part of the platform backend workspace focus needs.
+type desktopFocuser interface {
+	SwitchDesktop(desktop int) error
+	Focus(windowID platform.WindowID) error
+}
+
+// openDesktopFocuser connects to the display; tests swap it for a fake.
+var openDesktopFocuser = func() (desktopFocuser, func(), error) {
+	backend, err := platform.NewLinuxBackendFromDisplay()
+	if err != nil {
+		return nil, nil, err
+	}
+	return backend, backend.Disconnect, nil
+}
+
+// workspaceFocusTarget is where a registered workspace lives.
+type workspaceFocusTarget struct {
+	Desktop int
+	// Slot and WindowID are the lowest registered slot; WindowID is 0 when
+	// the registry has no window for the workspace's desktop.
+	Slot     int
+	WindowID platform.WindowID
+}
+
+// resolveWorkspaceFocus looks up the desktop hosting workspace name and the
+// window in its first slot. It fails if the workspace is not registered.
+func resolveWorkspaceFocus(name string) (workspaceFocusTarget, error) {
+	info, err := workspace.GetWorkspaceByName(name)
+	if err != nil {
+		return workspaceFocusTarget{}, fmt.Errorf("workspace %q is not active on any desktop", name)
+	}
+
+	target := workspaceFocusTarget{Desktop: info.Desktop, Slot: -1}
+	slots, err := workspace.GetSlotsByDesktop(info.Desktop)
+	if err != nil {
+		return workspaceFocusTarget{}, err
+	}
+	for _, slot := range slots {
+		if slot.WindowID != 0 {
+			target.Slot = slot.SlotIndex
+			target.WindowID = platform.WindowID(slot.WindowID)
+			break
+		}
+	}
+	return target, nil
+}
+
+// focusWorkspace switches to target's desktop, then raises and focuses its
+// first terminal when there is one.
+func focusWorkspace(f desktopFocuser, target workspaceFocusTarget) error {
+	if err := f.SwitchDesktop(target.Desktop); err != nil {
+		return fmt.Errorf("failed to switch to desktop %d: %w", target.Desktop, err)
+	}
+	if target.WindowID == 0 {
+		return nil
+	}
+	if err := f.Focus(target.WindowID); err != nil {
+		return fmt.Errorf("failed to focus window 0x%x: %w", uint32(target.WindowID), err)
+	}
+	return nil
+}
+
+func runWorkspaceFocus(args []string) int {
+	fs := flag.NewFlagSet("focus", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: termtile workspace focus <name>")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Switches to the desktop hosting an active workspace and focuses its first terminal.")
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	name := fs.Arg(0)
+
+	target, err := resolveWorkspaceFocus(name)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	focuser, closeFn, err := openDesktopFocuser()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer closeFn()
+
+	if err := focusWorkspace(focuser, target); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if target.WindowID == 0 {
+		fmt.Printf("Switched to workspace %s on desktop %d (no terminal to focus)\n", name, target.Desktop)
+		return 0
+	}
+	fmt.Printf("Focused workspace %s on desktop %d (slot %d, window 0x%x)\n", name, target.Desktop, target.Slot, uint32(target.WindowID))
+	return 0
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/1broseidon/termtile/internal/platform"
+	"github.com/1broseidon/termtile/internal/workspace"
+)
+
+type fakeDesktopFocuser struct {
+	calls     []string
+	switchErr error
+}
+
+func (f *fakeDesktopFocuser) SwitchDesktop(desktop int) error {
+	f.calls = append(f.calls, fmt.Sprintf("switch %d", desktop))
+	return f.switchErr
+}
+
+func (f *fakeDesktopFocuser) Focus(windowID platform.WindowID) error {
+	f.calls = append(f.calls, fmt.Sprintf("focus 0x%x", uint32(windowID)))
+	return nil
+}
+
+func TestResolveWorkspaceFocus(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	if err := workspace.SetActiveWorkspace("dev", 2, false, 2, nil); err != nil {
+		t.Fatalf("SetActiveWorkspace: %v", err)
+	}
+	if err := workspace.SetActiveWorkspace("ops", 1, false, 0, nil); err != nil {
+		t.Fatalf("SetActiveWorkspace: %v", err)
+	}
+	// Slot 1 is registered first; the lowest slot still wins.
+	if err := workspace.SetSlotInfo(0x2200002, 1, "", 2); err != nil {
+		t.Fatalf("SetSlotInfo: %v", err)
+	}
+	if err := workspace.SetSlotInfo(0x2200001, 0, "", 2); err != nil {
+		t.Fatalf("SetSlotInfo: %v", err)
+	}
+	if err := workspace.SetSlotInfo(0x1100001, 0, "", 0); err != nil {
+		t.Fatalf("SetSlotInfo: %v", err)
+	}
+
+	target, err := resolveWorkspaceFocus("dev")
+	if err != nil {
+		t.Fatalf("resolveWorkspaceFocus: %v", err)
+	}
+	if target.Desktop != 2 || target.Slot != 0 || target.WindowID != 0x2200001 {
+		t.Fatalf("target = %+v, want desktop 2 slot 0 window 0x2200001", target)
+	}
+
+	if _, err := resolveWorkspaceFocus("missing"); err == nil {
+		t.Fatal("expected an error for an unregistered workspace")
+	}
+}
+
+func TestResolveWorkspaceFocus_NoSlots(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	if err := workspace.SetActiveWorkspace("dev", 0, false, 3, nil); err != nil {
+		t.Fatalf("SetActiveWorkspace: %v", err)
+	}
+	target, err := resolveWorkspaceFocus("dev")
+	if err != nil {
+		t.Fatalf("resolveWorkspaceFocus: %v", err)
+	}
+	if target.Desktop != 3 || target.WindowID != 0 {
+		t.Fatalf("target = %+v, want desktop 3 and no window", target)
+	}
+}
+
+func TestFocusWorkspace(t *testing.T) {
+	f := &fakeDesktopFocuser{}
+	if err := focusWorkspace(f, workspaceFocusTarget{Desktop: 2, Slot: 0, WindowID: 0x2200001}); err != nil {
+		t.Fatalf("focusWorkspace: %v", err)
+	}
+	if want := []string{"switch 2", "focus 0x2200001"}; !slices.Equal(f.calls, want) {
+		t.Fatalf("calls = %v, want %v", f.calls, want)
+	}
+
+	// Without a window only the desktop switch happens.
+	f = &fakeDesktopFocuser{}
+	if err := focusWorkspace(f, workspaceFocusTarget{Desktop: 1, Slot: -1}); err != nil {
+		t.Fatalf("focusWorkspace: %v", err)
+	}
+	if want := []string{"switch 1"}; !slices.Equal(f.calls, want) {
+		t.Fatalf("calls = %v, want %v", f.calls, want)
+	}
+
+	// A failed switch does not go on to focus.
+	f = &fakeDesktopFocuser{switchErr: errors.New("no EWMH")}
+	if err := focusWorkspace(f, workspaceFocusTarget{Desktop: 1, WindowID: 0x10}); err == nil {
+		t.Fatal("expected switch error")
+	}
+	if want := []string{"switch 1"}; !slices.Equal(f.calls, want) {
+		t.Fatalf("calls = %v, want %v", f.calls, want)
+	}
+}
+
+func TestRunWorkspaceFocus_UnregisteredFails(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	opened := false
+	orig := openDesktopFocuser
+	openDesktopFocuser = func() (desktopFocuser, func(), error) {
+		opened = true
+		return &fakeDesktopFocuser{}, func() {}, nil
+	}
+	t.Cleanup(func() { openDesktopFocuser = orig })
+
+	if rc := runWorkspaceFocus([]string{"missing"}); rc != 1 {
+		t.Fatalf("exit = %d, want 1", rc)
+	}
+	if opened {
+		t.Fatal("display opened for an unregistered workspace")
+	}
+	if rc := runWorkspaceFocus(nil); rc != 2 {
+		t.Fatalf("exit without name = %d, want 2", rc)
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/1broseidon/termtile/internal/agent"
+	"github.com/1broseidon/termtile/internal/mcp"
+	"github.com/1broseidon/termtile/internal/workspace"
+)
+
+// savedWorkspace is a saved workspace file considered by workspace prune.
+type savedWorkspace struct {
+	Name    string
+	ModTime time.Time
+}
+
+// selectPruneCandidates returns the saved workspaces prune may delete: those
+// not open on any desktop and, when olderThan is positive, last saved more
+// than olderThan before now. The _previous auto-save is always kept.
+func selectPruneCandidates(saved []savedWorkspace, active map[int]workspace.WorkspaceInfo, olderThan time.Duration, now time.Time) []savedWorkspace {
+	open := make(map[string]struct{}, len(active))
+	for _, ws := range active {
+		open[ws.Name] = struct{}{}
+	}
+
+	var out []savedWorkspace
+	for _, ws := range saved {
+		if ws.Name == "_previous" {
+			continue
+		}
+		if _, ok := open[ws.Name]; ok {
+			continue
+		}
+		if olderThan > 0 && now.Sub(ws.ModTime) < olderThan {
+			continue
+		}
+		out = append(out, ws)
+	}
+	return out
+}
+
+// parsePruneAge parses --older-than: a Go duration ("36h") or a whole
+// number of days ("30d").
+func parsePruneAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid --older-than %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid --older-than %q", s)
+	}
+	return d, nil
+}
+
+// orphanedWorkspaceSessions returns the live multiplexer sessions of a saved
+// workspace's slots that no open workspace tracks.
+func orphanedWorkspaceSessions(name string, mux agent.Multiplexer) []string {
+	cfg, err := workspace.Read(name)
+	if err != nil || !mux.Available() {
+		return nil
+	}
+	var sessions []string
+	for _, term := range cfg.Terminals {
+		session := strings.TrimSpace(term.SessionName)
+		if session == "" {
+			session = agent.SessionName(name, term.SlotIndex)
+		}
+		if workspace.HasSessionInRegistry(session) {
+			continue
+		}
+		if ok, err := mux.HasSession(session); err == nil && ok {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions
+}
+
+func runWorkspacePrune(args []string) int {
+	fs := flag.NewFlagSet("prune", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	dryRun := fs.Bool("dry-run", false, "List what prune would remove without removing it")
+	yes := fs.Bool("yes", false, "Prune every candidate without asking")
+	olderThan := fs.String("older-than", "", "Only prune workspaces last saved longer ago than this (e.g. 72h, 30d)")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: termtile workspace prune [--dry-run] [--yes] [--older-than AGE]")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Offers to delete saved workspaces that are not open on any desktop,")
+		fmt.Fprintln(os.Stderr, "along with their history, orphaned tmux sessions and agent artifacts.")
+		fmt.Fprintln(os.Stderr, "Each one is confirmed unless --yes is given. _previous is kept.")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Flags:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(os.Stderr, "workspace prune takes no arguments")
+		fs.Usage()
+		return 2
+	}
+	age, err := parsePruneAge(*olderThan)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	names, err := workspace.List()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	saved := make([]savedWorkspace, 0, len(names))
+	for _, name := range names {
+		info, err := os.Stat(workspace.ConfigPath(name))
+		if err != nil {
+			continue
+		}
+		saved = append(saved, savedWorkspace{Name: name, ModTime: info.ModTime()})
+	}
+	active, err := workspace.GetAllWorkspaces()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	candidates := selectPruneCandidates(saved, active, age, time.Now())
+	if len(candidates) == 0 {
+		fmt.Println("No stale workspaces to prune.")
+		return 0
+	}
+
+	mux := terminalMultiplexer()
+	if *dryRun {
+		for _, ws := range candidates {
+			printPruneDryRun(os.Stdout, ws, orphanedWorkspaceSessions(ws.Name, mux))
+		}
+		return 0
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	failed := false
+	for _, ws := range candidates {
+		sessions := orphanedWorkspaceSessions(ws.Name, mux)
+		if !*yes && !confirmPrune(in, os.Stdout, ws, sessions) {
+			fmt.Printf("Kept workspace %q\n", ws.Name)
+			continue
+		}
+		for _, session := range sessions {
+			if err := mux.KillSession(session); err != nil {
+				warnf("failed to kill session %s: %v", session, err)
+			}
+		}
+		if err := workspace.Delete(ws.Name); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			failed = true
+			continue
+		}
+		if err := mcp.CleanupWorkspaceArtifacts(ws.Name); err != nil {
+			warnf("failed to remove artifacts for %q: %v", ws.Name, err)
+		}
+		fmt.Printf("Pruned workspace %q (last saved %s)\n", ws.Name, ws.ModTime.Local().Format(time.RFC3339))
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// confirmPrune describes what pruning ws removes and asks whether to go
+// ahead. Anything but "y" or "yes", including end of input, keeps it.
+func confirmPrune(in *bufio.Reader, w io.Writer, ws savedWorkspace, sessions []string) bool {
+	printPruneDryRun(w, ws, sessions)
+	fmt.Fprintf(w, "Prune workspace %q? [y/N] ", ws.Name)
+	line, _ := in.ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	}
+	if line == "" {
+		fmt.Fprintln(w)
+	}
+	return false
+}
+
+// printPruneDryRun lists what workspace prune would remove for ws.
+func printPruneDryRun(w io.Writer, ws savedWorkspace, sessions []string) {
+	fmt.Fprintf(w, "would prune workspace %q (last saved %s)\n", ws.Name, ws.ModTime.Local().Format(time.RFC3339))
+	paths, _ := workspace.DeleteTargets(ws.Name)
+	for _, path := range paths {
+		fmt.Fprintf(w, "  would delete %s\n", path)
+	}
+	for _, session := range sessions {
+		fmt.Fprintf(w, "  would kill session %s\n", session)
+	}
+	if dir, err := mcp.GetWorkspaceArtifactDir(ws.Name); err == nil {
+		if _, err := os.Stat(dir); err == nil {
+			fmt.Fprintf(w, "  would remove artifacts %s\n", dir)
+		}
+	}
+}
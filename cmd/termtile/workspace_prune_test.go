@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1broseidon/termtile/internal/workspace"
+)
+
+func TestSelectPruneCandidates(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	saved := []savedWorkspace{
+		{Name: "_previous", ModTime: now.Add(-90 * 24 * time.Hour)},
+		{Name: "open", ModTime: now.Add(-90 * 24 * time.Hour)},
+		{Name: "old", ModTime: now.Add(-40 * 24 * time.Hour)},
+		{Name: "recent", ModTime: now.Add(-2 * time.Hour)},
+	}
+	active := map[int]workspace.WorkspaceInfo{
+		1: {Name: "open", Desktop: 1},
+	}
+
+	names := func(ws []savedWorkspace) []string {
+		var out []string
+		for _, w := range ws {
+			out = append(out, w.Name)
+		}
+		return out
+	}
+
+	if got := names(selectPruneCandidates(saved, active, 0, now)); !reflect.DeepEqual(got, []string{"old", "recent"}) {
+		t.Fatalf("without age filter = %v, want [old recent]", got)
+	}
+	if got := names(selectPruneCandidates(saved, active, 30*24*time.Hour, now)); !reflect.DeepEqual(got, []string{"old"}) {
+		t.Fatalf("older than 30d = %v, want [old]", got)
+	}
+	if got := selectPruneCandidates(saved, active, 365*24*time.Hour, now); len(got) != 0 {
+		t.Fatalf("older than 365d = %v, want none", got)
+	}
+}
+
+func TestParsePruneAge(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "", want: 0},
+		{in: "30d", want: 30 * 24 * time.Hour},
+		{in: "36h", want: 36 * time.Hour},
+		{in: "xd", wantErr: true},
+		{in: "-1h", wantErr: true},
+		{in: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parsePruneAge(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("parsePruneAge(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Fatalf("parsePruneAge(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestConfirmPrune(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ws := savedWorkspace{Name: "old", ModTime: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	for input, want := range map[string]bool{
+		"y\n":   true,
+		"YES\n": true,
+		"n\n":   false,
+		"\n":    false,
+		"":      false, // end of input keeps the workspace
+	} {
+		var out bytes.Buffer
+		got := confirmPrune(bufio.NewReader(strings.NewReader(input)), &out, ws, []string{"termtile-old-0"})
+		if got != want {
+			t.Errorf("confirmPrune(%q) = %v, want %v", input, got, want)
+		}
+		if !strings.Contains(out.String(), "would kill session termtile-old-0") ||
+			!strings.Contains(out.String(), `Prune workspace "old"? [y/N]`) {
+			t.Errorf("confirmPrune(%q) output = %q", input, out.String())
+		}
+	}
+}
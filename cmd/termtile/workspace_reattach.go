@@ -0,0 +1,237 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/1broseidon/termtile/internal/agent"
+	"github.com/1broseidon/termtile/internal/config"
+	"github.com/1broseidon/termtile/internal/ipc"
+	"github.com/1broseidon/termtile/internal/mcp"
+	"github.com/1broseidon/termtile/internal/platform"
+	"github.com/1broseidon/termtile/internal/tiling"
+	"github.com/1broseidon/termtile/internal/workspace"
+)
+
+// sessionClients returns the number of clients attached to each live
+// session of mux; tests swap it for a fake.
+var sessionClients = func(mux agent.Multiplexer) (map[string]int, error) {
+	counter, ok := mux.(agent.ClientCounter)
+	if !ok {
+		return nil, fmt.Errorf("%s cannot report attached clients", mux.Name())
+	}
+	return counter.SessionClients()
+}
+
+// reattachPlan sorts the slots of a workspace by the state of their
+// session: Attach slots have a live session with no client and get a new
+// window, Busy slots already have a client, Missing slots have no session.
+type reattachPlan struct {
+	Attach  []int
+	Busy    []int
+	Missing []int
+}
+
+func planReattach(name string, slotCount int, clients map[string]int) reattachPlan {
+	var plan reattachPlan
+	for slot := 0; slot < slotCount; slot++ {
+		n, ok := clients[mcp.SlotSessionName(name, slot)]
+		switch {
+		case !ok:
+			plan.Missing = append(plan.Missing, slot)
+		case n > 0:
+			plan.Busy = append(plan.Busy, slot)
+		default:
+			plan.Attach = append(plan.Attach, slot)
+		}
+	}
+	return plan
+}
+
+// reattachCommand is the command a new terminal runs to join the existing
+// session of a slot; under tmux it attaches rather than create-or-attach.
+func reattachCommand(mux agent.Multiplexer, configPath, name string, slot int) string {
+	return workspace.AttachCommand(mux, configPath, mcp.SlotSessionName(name, slot))
+}
+
+// slotCwd returns the saved working directory of slot, or the home
+// directory when the saved workspace does not record one.
+func slotCwd(saved *workspace.WorkspaceConfig, slot int) string {
+	if saved != nil {
+		for _, term := range saved.Terminals {
+			if term.SlotIndex == slot && term.Cwd != "" {
+				return term.Cwd
+			}
+		}
+	}
+	home, _ := os.UserHomeDir()
+	return home
+}
+
+func runWorkspaceReattach(args []string) int {
+	fs := flag.NewFlagSet("reattach", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: termtile workspace reattach [--timeout N] <name>")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Opens a terminal window for every multiplexer session of an open agent-mode")
+		fmt.Fprintln(os.Stderr, "workspace that has no client attached, attaching to the existing session")
+		fmt.Fprintln(os.Stderr, "instead of creating one, then re-tiles. Run it from the workspace's desktop.")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Flags:")
+		fs.PrintDefaults()
+	}
+	timeout := fs.Int("timeout", 10, "Seconds to wait for the new windows to appear")
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "workspace reattach requires <name>")
+		fs.Usage()
+		return 2
+	}
+	name := fs.Arg(0)
+
+	capturedDesktop, err := platform.GetCurrentDesktopStandalone()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to detect current desktop: %v\n", err)
+		return 1
+	}
+
+	res, err := config.LoadWithSources()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	wsInfo, err := workspace.GetWorkspaceByName(name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "workspace %q is not active on any desktop; use 'termtile workspace load %s'\n", name, name)
+		return 1
+	}
+	if !wsInfo.AgentMode {
+		fmt.Fprintf(os.Stderr, "workspace %q is not in agent mode; it has no sessions to reattach\n", name)
+		return 1
+	}
+	if wsInfo.Desktop != capturedDesktop {
+		fmt.Fprintf(os.Stderr, "error: workspace %q is on desktop %d, but you were on desktop %d\n",
+			wsInfo.Name, wsInfo.Desktop, capturedDesktop)
+		fmt.Fprintf(os.Stderr, "hint: switch to desktop %d first\n", wsInfo.Desktop)
+		return 1
+	}
+
+	configMgr, err := agent.NewConfigManager(res.Config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to initialize multiplexer: %v\n", err)
+		return 1
+	}
+	mux := configMgr.Multiplexer()
+
+	clients, err := sessionClients(mux)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	plan := planReattach(wsInfo.Name, wsInfo.TerminalCount, clients)
+	for _, slot := range plan.Busy {
+		fmt.Printf("slot %d: %s already has a client attached; skipping\n", slot, mcp.SlotSessionName(wsInfo.Name, slot))
+	}
+	for _, slot := range plan.Missing {
+		warnf("slot %d: session %s does not exist", slot, mcp.SlotSessionName(wsInfo.Name, slot))
+	}
+	if len(plan.Attach) == 0 {
+		if len(plan.Busy) > 0 {
+			fmt.Printf("No detached sessions to reattach for workspace %q\n", wsInfo.Name)
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "workspace %q has no live %s sessions\n", wsInfo.Name, mux.Name())
+		return 1
+	}
+
+	// The saved config only supplies the terminal class, cwds and layout.
+	saved, _ := workspace.Read(wsInfo.Name)
+	termClass := ""
+	if saved != nil && len(saved.Terminals) > 0 {
+		termClass = saved.Terminals[0].WMClass
+	}
+	if termClass == "" {
+		termClass = res.Config.ResolveTerminal()
+		if termClass == "" {
+			fmt.Fprintln(os.Stderr, "no terminal class configured; set terminal_classes in config")
+			return 1
+		}
+	}
+
+	backend, err := platform.NewLinuxBackendFromDisplay()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer backend.Disconnect()
+
+	lister := newTerminalLister(backend, res.Config)
+	applier := &ipcLayoutApplier{client: ipc.NewClient()}
+	if err := applier.client.Ping(); err != nil {
+		fmt.Fprintln(os.Stderr, "daemon not running:", err)
+		return 1
+	}
+
+	before, err := lister.ListTerminals()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	existing := make(map[uint32]struct{}, len(before))
+	for _, w := range before {
+		existing[w.WindowID] = struct{}{}
+	}
+
+	configPath := configMgr.GetConfigPath()
+	spawned := 0
+	for _, slot := range plan.Attach {
+		term := workspace.TerminalConfig{
+			WMClass:   termClass,
+			Cwd:       slotCwd(saved, slot),
+			SlotIndex: slot,
+		}
+		cmd := reattachCommand(mux, configPath, wsInfo.Name, slot)
+		if err := spawnTerminalWithCommand(term, res.Config.TerminalSpawnCommands, cmd, res.Config.SpawnEnvList(termClass)); err != nil {
+			fmt.Fprintf(os.Stderr, "slot %d: %v\n", slot, err)
+			break
+		}
+		spawned++
+	}
+	if spawned == 0 {
+		return 1
+	}
+
+	if _, err := waitForNewTerminals(lister, existing, spawned, time.Duration(*timeout)*time.Second); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if !skipAutoTile(wsInfo.Name) {
+		layoutName := ""
+		if saved != nil {
+			layoutName = saved.Layout
+		}
+		if status, err := applier.client.GetStatus(); err == nil && status.ActiveLayout != "" {
+			layoutName = status.ActiveLayout
+		}
+		if err := applier.ApplyLayout(layoutName, true); err != nil && !errors.Is(err, tiling.ErrNoTerminals) {
+			warnf("failed to re-tile: %v", err)
+		}
+	}
+
+	fmt.Printf("Reattached %d terminal(s) to workspace %q\n", spawned, wsInfo.Name)
+	if spawned < len(plan.Attach) {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/1broseidon/termtile/internal/agent"
+	"github.com/1broseidon/termtile/internal/workspace"
+)
+
+func TestPlanReattach(t *testing.T) {
+	clients := map[string]int{
+		"termtile-dev-0":   0,
+		"termtile-dev-1":   1,
+		"termtile-dev-3":   0,
+		"termtile-dev-9":   0, // beyond the registered slot count
+		"termtile-other-2": 0,
+	}
+	plan := planReattach("dev", 4, clients)
+	want := reattachPlan{Attach: []int{0, 3}, Busy: []int{1}, Missing: []int{2}}
+	if !reflect.DeepEqual(plan, want) {
+		t.Fatalf("planReattach = %+v, want %+v", plan, want)
+	}
+	// One window is spawned per detached session.
+	if got := len(plan.Attach); got != 2 {
+		t.Fatalf("windows to spawn = %d, want 2", got)
+	}
+}
+
+func TestReattachCommand(t *testing.T) {
+	tests := []struct {
+		configPath string
+		want       []string
+	}{
+		{configPath: "", want: []string{"tmux", "attach", "-t", "termtile-dev-2"}},
+		{configPath: "/cfg/tmux.conf", want: []string{"tmux", "-f", "/cfg/tmux.conf", "attach", "-t", "termtile-dev-2"}},
+	}
+	for _, tt := range tests {
+		cmd := reattachCommand(agent.NewTmuxMultiplexer(), tt.configPath, "dev", 2)
+		got, err := splitCommand(cmd)
+		if err != nil {
+			t.Fatalf("splitCommand(%q): %v", cmd, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("reattachCommand(%q) = %q, want %q", tt.configPath, got, tt.want)
+		}
+	}
+
+	term := workspace.TerminalConfig{WMClass: "kitty", Cwd: "/work", SlotIndex: 2}
+	templates := map[string]string{"kitty": "kitty --directory {{dir}} {{cmd}}"}
+	spawn, err := workspace.TerminalSpawnCmd(term, templates, false, reattachCommand(agent.NewTmuxMultiplexer(), "", "dev", 2), nil)
+	if err != nil {
+		t.Fatalf("TerminalSpawnCmd: %v", err)
+	}
+	want := []string{"kitty", "--directory", "/work", "tmux", "attach", "-t", "termtile-dev-2"}
+	if !reflect.DeepEqual(spawn.Args, want) {
+		t.Fatalf("spawn args = %q, want %q", spawn.Args, want)
+	}
+	if slices.Contains(spawn.Args, "new-session") {
+		t.Fatalf("spawn args %q create a session instead of attaching", spawn.Args)
+	}
+
+	screen := agent.NewScreenMultiplexer(agent.WithConfigPath(filepath.Join(t.TempDir(), "missing-screenrc")))
+	if got, want := reattachCommand(screen, "", "dev", 2), "screen -D -R -S termtile-dev-2"; got != want {
+		t.Fatalf("screen reattachCommand = %q, want %q", got, want)
+	}
+}
+
+func TestSlotCwd(t *testing.T) {
+	t.Setenv("HOME", "/home/test")
+	saved := &workspace.WorkspaceConfig{Terminals: []workspace.TerminalConfig{
+		{SlotIndex: 0, Cwd: "/src/a"},
+		{SlotIndex: 1},
+	}}
+	if got := slotCwd(saved, 0); got != "/src/a" {
+		t.Fatalf("slotCwd(0) = %q, want /src/a", got)
+	}
+	if got := slotCwd(saved, 1); got != "/home/test" {
+		t.Fatalf("slotCwd(1) = %q, want home", got)
+	}
+	if got := slotCwd(nil, 0); got != "/home/test" {
+		t.Fatalf("slotCwd(nil) = %q, want home", got)
+	}
+}
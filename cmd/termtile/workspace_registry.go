@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/1broseidon/termtile/internal/workspace"
+)
+
+func runWorkspaceRegistry(args []string) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		fmt.Fprintln(os.Stderr, "Usage:")
+		fmt.Fprintln(os.Stderr, "  termtile workspace registry export [--output <file>]  Dump the desktop registry as JSON")
+		fmt.Fprintln(os.Stderr, "  termtile workspace registry import <file|->            Restore the desktop registry from JSON")
+		return 2
+	}
+
+	switch args[0] {
+	case "export":
+		return runWorkspaceRegistryExport(args[1:])
+	case "import":
+		return runWorkspaceRegistryImport(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown workspace registry subcommand: %s\n", args[0])
+		return 2
+	}
+}
+
+func runWorkspaceRegistryExport(args []string) int {
+	fs := flag.NewFlagSet("registry export", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	output := fs.String("output", "", "Write to this file instead of stdout")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: termtile workspace registry export [--output <file>]")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Dumps the desktop-to-workspace registry as JSON.")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Flags:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.OpenFile(*output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := workspace.ExportRegistry(w); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if *output != "" {
+		fmt.Printf("Exported workspace registry to %s\n", *output)
+	}
+	return 0
+}
+
+func runWorkspaceRegistryImport(args []string) int {
+	fs := flag.NewFlagSet("registry import", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: termtile workspace registry import <file|->")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Replaces the desktop-to-workspace registry with a previous export.")
+		fmt.Fprintln(os.Stderr, "The file is validated first; the current registry is kept if it is rejected.")
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	var r io.Reader = os.Stdin
+	if path := fs.Arg(0); path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer f.Close()
+		r = f
+	}
+
+	n, err := workspace.ImportRegistry(r)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Printf("Imported %d workspace(s) into the registry\n", n)
+	return 0
+}
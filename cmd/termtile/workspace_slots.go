@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/1broseidon/termtile/internal/config"
+	"github.com/1broseidon/termtile/internal/tiling"
+	"github.com/1broseidon/termtile/internal/workspace"
+)
+
+// defaultNewWorkspaceTerminals is the terminal count workspace new uses
+// without -n when the layout grows with the window count.
+const defaultNewWorkspaceTerminals = 3
+
+// newWorkspaceTerminalCount returns how many terminals workspace new opens
+// without -n: the capacity of layoutName, so a fixed 2x2 grid gets 4, or
+// defaultNewWorkspaceTerminals for layouts without a cap or that are unknown.
+func newWorkspaceTerminalCount(cfg *config.Config, layoutName string) int {
+	layout, err := cfg.GetLayout(layoutName)
+	if err != nil {
+		return defaultNewWorkspaceTerminals
+	}
+	if capacity := tiling.LayoutCapacity(layout); capacity > 0 {
+		return capacity
+	}
+	return defaultNewWorkspaceTerminals
+}
+
+// terminalCountFlag is workspace new's -n. It records whether -n was given,
+// so newWorkspaceTerminalCount only applies when it was not.
+type terminalCountFlag struct {
+	n   int
+	set bool
+}
+
+func (f *terminalCountFlag) String() string {
+	if f == nil || !f.set {
+		return ""
+	}
+	return strconv.Itoa(f.n)
+}
+
+func (f *terminalCountFlag) Set(value string) error {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n < 0 {
+		return fmt.Errorf("expected a non-negative terminal count, got %q", value)
+	}
+	f.n = n
+	f.set = true
+	return nil
+}
+
+// slotMapFlag collects SLOT:VALUE flags for workspace new. With list set,
+// one flag value may hold several comma-separated pairs.
+type slotMapFlag struct {
+	values map[int]string
+	list   bool
+}
+
+func (f *slotMapFlag) String() string {
+	if f == nil || len(f.values) == 0 {
+		return ""
+	}
+	slots := make([]int, 0, len(f.values))
+	for slot := range f.values {
+		slots = append(slots, slot)
+	}
+	sort.Ints(slots)
+	pairs := make([]string, 0, len(slots))
+	for _, slot := range slots {
+		pairs = append(pairs, fmt.Sprintf("%d:%s", slot, f.values[slot]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (f *slotMapFlag) Set(value string) error {
+	entries := []string{value}
+	if f.list {
+		entries = strings.Split(value, ",")
+	}
+	for _, entry := range entries {
+		slotStr, val, ok := strings.Cut(entry, ":")
+		slot, err := strconv.Atoi(strings.TrimSpace(slotStr))
+		val = strings.TrimSpace(val)
+		if !ok || err != nil || slot < 0 || val == "" {
+			return fmt.Errorf("expected SLOT:VALUE with a non-negative slot, got %q", entry)
+		}
+		if _, dup := f.values[slot]; dup {
+			return fmt.Errorf("slot %d given more than once", slot)
+		}
+		f.values[slot] = val
+	}
+	return nil
+}
+
+// buildNewWorkspaceTerminals builds the terminals for workspace new. Every
+// slot starts in workDir unless slotCwd names a directory for it; slotCmd
+// gives the command a slot runs at startup. Per-slot directories expand a
+// leading ~ and resolve relative to workDir.
+func buildNewWorkspaceTerminals(n int, termClass, workDir string, slotCwd, slotCmd map[int]string) ([]workspace.TerminalConfig, error) {
+	for _, m := range []struct {
+		flag   string
+		values map[int]string
+	}{{"--slot-cwd", slotCwd}, {"--slot-cmd", slotCmd}} {
+		for slot := range m.values {
+			if slot >= n {
+				return nil, fmt.Errorf("%s slot %d is out of range for %d terminal(s)", m.flag, slot, n)
+			}
+		}
+	}
+
+	terms := make([]workspace.TerminalConfig, n)
+	for i := range terms {
+		cwd := workDir
+		if dir, ok := slotCwd[i]; ok {
+			expanded, err := expandSlotDir(dir, workDir)
+			if err != nil {
+				return nil, err
+			}
+			cwd = expanded
+		}
+		terms[i] = workspace.TerminalConfig{
+			WMClass:   termClass,
+			Cwd:       cwd,
+			SlotIndex: i,
+		}
+		if command, ok := slotCmd[i]; ok {
+			argv, err := splitCommand(command)
+			if err != nil {
+				return nil, fmt.Errorf("--slot-cmd slot %d: %w", i, err)
+			}
+			terms[i].Cmd = argv
+		}
+	}
+	return terms, nil
+}
+
+// expandSlotDir expands a leading ~ in dir and makes it absolute relative
+// to base.
+func expandSlotDir(dir, base string) (string, error) {
+	if dir == "~" || strings.HasPrefix(dir, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to expand %q: %w", dir, err)
+		}
+		dir = filepath.Join(home, strings.TrimPrefix(dir, "~"))
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(base, dir)
+	}
+	return filepath.Clean(dir), nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/1broseidon/termtile/internal/config"
+)
+
+func TestSlotMapFlag_Parse(t *testing.T) {
+	cwd := slotMapFlag{values: map[int]string{}, list: true}
+	if err := cwd.Set("0:~/a, 1:~/b"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := cwd.Set("3:src"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	want := map[int]string{0: "~/a", 1: "~/b", 3: "src"}
+	if !reflect.DeepEqual(cwd.values, want) {
+		t.Fatalf("values = %v, want %v", cwd.values, want)
+	}
+	if got := cwd.String(); got != "0:~/a,1:~/b,3:src" {
+		t.Fatalf("String() = %q", got)
+	}
+
+	// Commands are not split on commas.
+	cmd := slotMapFlag{values: map[int]string{}}
+	if err := cmd.Set("0:sh -c 'echo a,b'"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if cmd.values[0] != "sh -c 'echo a,b'" {
+		t.Fatalf("cmd value = %q", cmd.values[0])
+	}
+
+	for _, bad := range []string{"~/a", "x:~/a", "-1:~/a", "2:", "0:~/c"} {
+		if err := cwd.Set(bad); err == nil {
+			t.Errorf("Set(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestTerminalCountFlag(t *testing.T) {
+	var count terminalCountFlag
+	if count.set || count.String() != "" {
+		t.Fatalf("unset flag = %+v", count)
+	}
+	if err := count.Set("0"); err != nil || !count.set || count.n != 0 {
+		t.Fatalf("Set(0) = %v, flag %+v", err, count)
+	}
+	for _, bad := range []string{"-1", "x", ""} {
+		if err := new(terminalCountFlag).Set(bad); err == nil {
+			t.Errorf("Set(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestBuildNewWorkspaceTerminals_PerSlot(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	terms, err := buildNewWorkspaceTerminals(3, "kitty", "/work",
+		map[int]string{0: "~/editor", 2: "api"},
+		map[int]string{0: "nvim .", 1: "htop -d 5"},
+	)
+	if err != nil {
+		t.Fatalf("buildNewWorkspaceTerminals: %v", err)
+	}
+	if len(terms) != 3 {
+		t.Fatalf("got %d terminals, want 3", len(terms))
+	}
+
+	wantCwd := []string{filepath.Join(home, "editor"), "/work", "/work/api"}
+	wantCmd := [][]string{{"nvim", "."}, {"htop", "-d", "5"}, nil}
+	for i, term := range terms {
+		if term.SlotIndex != i || term.WMClass != "kitty" {
+			t.Errorf("slot %d = %+v", i, term)
+		}
+		if term.Cwd != wantCwd[i] {
+			t.Errorf("slot %d cwd = %q, want %q", i, term.Cwd, wantCwd[i])
+		}
+		if !reflect.DeepEqual(term.Cmd, wantCmd[i]) {
+			t.Errorf("slot %d cmd = %q, want %q", i, term.Cmd, wantCmd[i])
+		}
+	}
+}
+
+func TestBuildNewWorkspaceTerminals_SlotOutOfRange(t *testing.T) {
+	_, err := buildNewWorkspaceTerminals(2, "kitty", "/work", map[int]string{2: "/tmp"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "--slot-cwd slot 2") {
+		t.Fatalf("err = %v, want out-of-range --slot-cwd error", err)
+	}
+	_, err = buildNewWorkspaceTerminals(2, "kitty", "/work", nil, map[int]string{5: "nvim"})
+	if err == nil || !strings.Contains(err.Error(), "--slot-cmd slot 5") {
+		t.Fatalf("err = %v, want out-of-range --slot-cmd error", err)
+	}
+}
+
+func TestNewWorkspaceTerminalCount(t *testing.T) {
+	full := config.TileRegion{Type: config.RegionFull}
+	cfg := config.DefaultConfig()
+	cfg.Layouts = map[string]config.Layout{
+		"grid2x2": {Mode: config.LayoutModeFixed, TileRegion: full, FixedGrid: config.FixedGrid{Rows: 2, Cols: 2}},
+		"grid3x2": {Mode: config.LayoutModeFixed, TileRegion: full, FixedGrid: config.FixedGrid{Rows: 2, Cols: 3}, Aliases: []string{"six"}},
+		"master":  {Mode: config.LayoutModeMasterStack, TileRegion: full, MasterStack: config.MasterStack{MasterWidthPercent: 60, MaxStackRows: 2, MaxStackCols: 1}},
+		"auto":    {Mode: config.LayoutModeAuto, TileRegion: full},
+		"cols":    {Mode: config.LayoutModeVertical, TileRegion: full},
+	}
+
+	tests := []struct {
+		layout string
+		want   int
+	}{
+		{layout: "grid2x2", want: 4},
+		{layout: "six", want: 6},
+		{layout: "master", want: 3},
+		{layout: "auto", want: defaultNewWorkspaceTerminals},
+		{layout: "cols", want: defaultNewWorkspaceTerminals},
+		{layout: "missing", want: defaultNewWorkspaceTerminals},
+	}
+	for _, tt := range tests {
+		if got := newWorkspaceTerminalCount(cfg, tt.layout); got != tt.want {
+			t.Errorf("newWorkspaceTerminalCount(%q) = %d, want %d", tt.layout, got, tt.want)
+		}
+	}
+}
@@ -6,9 +6,43 @@ import (
 	"unicode"
 )
 
+// sessionPrefix starts every derived session name.
+const sessionPrefix = "termtile-"
+
+// maxSessionNameLen bounds custom session names passed to ValidateSessionName.
+const maxSessionNameLen = 64
+
 // SessionName returns the tmux session name for a workspace and slot.
 func SessionName(workspaceName string, slot int) string {
-	return fmt.Sprintf("termtile-%s-%d", sanitizeSessionComponent(workspaceName), slot)
+	return fmt.Sprintf("%s%s-%d", sessionPrefix, sanitizeSessionComponent(workspaceName), slot)
+}
+
+// IsDerivedSessionName reports whether name follows the SessionName scheme
+// rather than being a custom name chosen at spawn time.
+func IsDerivedSessionName(name string) bool {
+	return strings.HasPrefix(name, sessionPrefix)
+}
+
+// ValidateSessionName checks a custom session name requested at spawn time.
+// Names may use letters, digits, '-' and '_' only ('.' and ':' separate tmux
+// target components) and must not start with the prefix reserved for derived
+// names.
+func ValidateSessionName(name string) error {
+	if name == "" {
+		return fmt.Errorf("session name is empty")
+	}
+	if len(name) > maxSessionNameLen {
+		return fmt.Errorf("session name %q is longer than %d characters", name, maxSessionNameLen)
+	}
+	for _, r := range name {
+		if !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_') {
+			return fmt.Errorf("session name %q contains invalid character %q (use letters, digits, '-' or '_')", name, r)
+		}
+	}
+	if IsDerivedSessionName(name) {
+		return fmt.Errorf("session name %q uses the reserved %q prefix", name, sessionPrefix)
+	}
+	return nil
 }
 
 // TargetForSession returns the tmux target for a session (session:window.pane).
@@ -19,22 +53,38 @@ func TargetForSession(session string) string {
 // WorkspaceInfo contains the information needed to resolve an agent session.
 // This is passed in from the workspace package to avoid import cycles.
 type WorkspaceInfo struct {
-	Name       string
-	AgentMode  bool
-	AgentSlots []int
+	Name          string
+	AgentMode     bool
+	AgentSlots    []int
+	TerminalCount int
+}
+
+// ResolveSlot maps a relative slot index onto the workspace's terminal count:
+// -1 is the last slot, -2 the one before it, and so on. Non-negative slots are
+// returned unchanged.
+func ResolveSlot(slot, terminalCount int) (int, error) {
+	if slot >= 0 {
+		return slot, nil
+	}
+	idx := terminalCount + slot
+	if idx < 0 {
+		return -1, fmt.Errorf("slot %d out of range (workspace has %d terminals)", slot, terminalCount)
+	}
+	return idx, nil
 }
 
 // ResolveSession resolves the tmux session name for a given workspace and slot.
 // If workspaceOverride is provided, it uses that workspace name directly.
 // Otherwise, it uses the provided workspace info (typically from the current desktop).
+// Negative slots are resolved relative to the end of the workspace via ResolveSlot;
+// with a workspaceOverride the caller must resolve them first.
 func ResolveSession(workspaceOverride string, slot int, wsInfo *WorkspaceInfo) (string, error) {
-	if slot < 0 {
-		return "", fmt.Errorf("--slot is required")
-	}
-
 	// If explicit workspace provided, use it directly
 	workspaceOverride = strings.TrimSpace(workspaceOverride)
 	if workspaceOverride != "" {
+		if slot < 0 {
+			return "", fmt.Errorf("relative slot %d cannot be resolved without workspace %q's terminal count", slot, workspaceOverride)
+		}
 		return SessionName(workspaceOverride, slot), nil
 	}
 
@@ -49,6 +99,11 @@ func ResolveSession(workspaceOverride string, slot int, wsInfo *WorkspaceInfo) (
 		return "", fmt.Errorf("workspace %q is not an agent-mode workspace", wsInfo.Name)
 	}
 
+	slot, err := ResolveSlot(slot, wsInfo.TerminalCount)
+	if err != nil {
+		return "", err
+	}
+
 	// Verify slot is valid for this workspace
 	validSlot := false
 	for _, s := range wsInfo.AgentSlots {
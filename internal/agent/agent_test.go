@@ -0,0 +1,101 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveSlot(t *testing.T) {
+	tests := []struct {
+		slot, count, want int
+		wantErr           bool
+	}{
+		{slot: 0, count: 3, want: 0},
+		{slot: 2, count: 3, want: 2},
+		{slot: 7, count: 3, want: 7}, // positive indices are not range-checked here
+		{slot: -1, count: 3, want: 2},
+		{slot: -2, count: 3, want: 1},
+		{slot: -3, count: 3, want: 0},
+		{slot: -4, count: 3, wantErr: true},
+		{slot: -1, count: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ResolveSlot(tt.slot, tt.count)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ResolveSlot(%d, %d) = %d, want error", tt.slot, tt.count, got)
+			} else if !strings.Contains(err.Error(), "out of range") {
+				t.Errorf("ResolveSlot(%d, %d) error = %q, want out of range", tt.slot, tt.count, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ResolveSlot(%d, %d) error = %v", tt.slot, tt.count, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ResolveSlot(%d, %d) = %d, want %d", tt.slot, tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestResolveSession_NegativeSlot(t *testing.T) {
+	ws := &WorkspaceInfo{
+		Name:          "dev",
+		AgentMode:     true,
+		AgentSlots:    []int{0, 1, 2},
+		TerminalCount: 3,
+	}
+
+	got, err := ResolveSession("", -1, ws)
+	if err != nil {
+		t.Fatalf("ResolveSession(-1) error = %v", err)
+	}
+	if want := SessionName("dev", 2); got != want {
+		t.Fatalf("ResolveSession(-1) = %q, want %q", got, want)
+	}
+
+	if _, err := ResolveSession("", -4, ws); err == nil {
+		t.Fatal("ResolveSession(-4) expected out-of-range error")
+	}
+	if _, err := ResolveSession("dev", -1, nil); err == nil {
+		t.Fatal("ResolveSession with override and negative slot expected error")
+	}
+}
+
+func TestValidateSessionName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr string
+	}{
+		{name: "my-agent"},
+		{name: "build_runner2"},
+		{name: "", wantErr: "empty"},
+		{name: strings.Repeat("a", 65), wantErr: "longer than"},
+		{name: "has space", wantErr: "invalid character"},
+		{name: "dotted.name", wantErr: "invalid character"},
+		{name: "sess:0", wantErr: "invalid character"},
+		{name: "termtile-work-0", wantErr: "reserved"},
+	}
+	for _, tt := range tests {
+		err := ValidateSessionName(tt.name)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("ValidateSessionName(%q) error = %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("ValidateSessionName(%q) error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestIsDerivedSessionName(t *testing.T) {
+	if !IsDerivedSessionName(SessionName("work", 3)) {
+		t.Errorf("IsDerivedSessionName(%q) = false, want true", SessionName("work", 3))
+	}
+	if IsDerivedSessionName("my-agent") {
+		t.Error("IsDerivedSessionName(\"my-agent\") = true, want false")
+	}
+}
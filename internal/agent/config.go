@@ -36,6 +36,18 @@ func NewConfigManager(cfg *config.Config) (*ConfigManager, error) {
 	}, nil
 }
 
+// MultiplexerFor returns the multiplexer selected by agent_mode.multiplexer.
+// When the configured multiplexer cannot be resolved (invalid or not
+// installed) it falls back to tmux, so callers keep reporting the usual
+// "not available" errors from the returned implementation.
+func MultiplexerFor(cfg *config.Config) Multiplexer {
+	cm, err := NewConfigManager(cfg)
+	if err != nil {
+		return NewTmuxMultiplexer()
+	}
+	return cm.Multiplexer()
+}
+
 // Multiplexer returns the configured multiplexer
 func (cm *ConfigManager) Multiplexer() Multiplexer {
 	return cm.multiplexer
@@ -25,12 +25,10 @@ func AutoDetect(opts ...MultiplexerOption) (Multiplexer, error) {
 		return tmux, nil
 	}
 
-	// Screen support is deferred to backlog
-	// When implemented, it would be checked here:
-	// screen := NewScreenMultiplexer(opts...)
-	// if screen.Available() {
-	//     return screen, nil
-	// }
+	screen := NewScreenMultiplexer(opts...)
+	if screen.Available() {
+		return screen, nil
+	}
 
 	return nil, ErrMultiplexerNotAvailable
 }
@@ -50,11 +48,14 @@ func GetMultiplexer(mtype MultiplexerType, opts ...MultiplexerOption) (Multiplex
 		return tmux, nil
 
 	case MultiplexerScreen:
-		// Screen support deferred to backlog
-		return nil, fmt.Errorf("screen multiplexer not yet implemented (use tmux)")
+		screen := NewScreenMultiplexer(opts...)
+		if !screen.Available() {
+			return nil, ErrScreenNotAvailable
+		}
+		return screen, nil
 
 	default:
-		return nil, fmt.Errorf("unknown multiplexer type: %s (supported: auto, tmux)", mtype)
+		return nil, fmt.Errorf("unknown multiplexer type: %s (supported: auto, tmux, screen)", mtype)
 	}
 }
 
@@ -109,7 +110,9 @@ func ListAvailable() []string {
 		available = append(available, "tmux")
 	}
 
-	// Screen check would go here when implemented
+	if NewScreenMultiplexer().Available() {
+		available = append(available, "screen")
+	}
 
 	return available
 }
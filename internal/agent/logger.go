@@ -8,6 +8,7 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 )
 
 // LogLevel defines the logging verbosity.
@@ -35,6 +36,7 @@ const (
 	ActionWaitIdle       ActionType = "WAIT-IDLE"
 	ActionListAgents     ActionType = "LIST-AGENTS"
 	ActionMoveTerminal   ActionType = "MOVE-TERMINAL"
+	ActionWarning        ActionType = "WARNING"
 )
 
 // actionLevel returns the log level for an action type.
@@ -44,6 +46,8 @@ func actionLevel(action ActionType) LogLevel {
 		return LevelDebug
 	case ActionAddTerminal, ActionRemoveTerminal, ActionMoveTerminal, ActionWorkspaceNew, ActionWorkspaceClose, ActionSpawnAgent, ActionKillAgent:
 		return LevelInfo
+	case ActionWarning:
+		return LevelWarn
 	default:
 		return LevelInfo
 	}
@@ -252,6 +256,20 @@ func ParseLogLevel(s string) LogLevel {
 	}
 }
 
+// TruncateContent caps logged content at maxBytes, cutting on a UTF-8
+// boundary and appending " (truncated)" when anything was dropped. A
+// maxBytes <= 0 leaves s whole.
+func TruncateContent(s string, maxBytes int) string {
+	if maxBytes <= 0 || len(s) <= maxBytes {
+		return s
+	}
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + " (truncated)"
+}
+
 // Truncate returns a preview of a string, truncating if necessary.
 func Truncate(s string, maxLen int) string {
 	if maxLen <= 0 || len(s) <= maxLen {
@@ -0,0 +1,201 @@
+package agent
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// logTimeLayout is the timestamp format written by Logger.Log.
+const logTimeLayout = "2006-01-02 15:04:05"
+
+// knownActions lists every ActionType the logger writes.
+var knownActions = []ActionType{
+	ActionSend,
+	ActionRead,
+	ActionAddTerminal,
+	ActionRemoveTerminal,
+	ActionWorkspaceNew,
+	ActionWorkspaceClose,
+	ActionSpawnAgent,
+	ActionKillAgent,
+	ActionWaitIdle,
+	ActionListAgents,
+	ActionMoveTerminal,
+	ActionWarning,
+}
+
+// LogEntry is one parsed line of the agent action log.
+type LogEntry struct {
+	Time      time.Time
+	Action    ActionType
+	Workspace string
+	// Slot is -1 for entries not tied to a slot.
+	Slot    int
+	Details map[string]string
+}
+
+// LogFilter selects entries returned by ReadLog. Zero values match
+// everything.
+type LogFilter struct {
+	Workspace string
+	// Slot restricts entries to one slot when non-nil.
+	Slot   *int
+	Action ActionType
+	// Limit keeps only the most recent matching entries when > 0.
+	Limit int
+}
+
+func (f LogFilter) matches(e LogEntry) bool {
+	if f.Workspace != "" && e.Workspace != f.Workspace {
+		return false
+	}
+	if f.Slot != nil && e.Slot != *f.Slot {
+		return false
+	}
+	if f.Action != "" && e.Action != f.Action {
+		return false
+	}
+	return true
+}
+
+// ParseActionType converts a user-supplied action name such as "spawn_agent"
+// or "SPAWN-AGENT" to its ActionType.
+func ParseActionType(s string) (ActionType, error) {
+	name := ActionType(strings.ReplaceAll(strings.ToUpper(strings.TrimSpace(s)), "_", "-"))
+	for _, action := range knownActions {
+		if action == name {
+			return action, nil
+		}
+	}
+	names := make([]string, len(knownActions))
+	for i, action := range knownActions {
+		names[i] = string(action)
+	}
+	return "", fmt.Errorf("unknown action %q (valid: %s)", s, strings.Join(names, ", "))
+}
+
+// ReadLog reads the agent action log at path and returns the entries matching
+// filter, oldest first. Lines that are not log entries are skipped. A missing
+// log file yields no entries. Rotated files (path.1, ...) are not read.
+func ReadLog(path string, filter LogFilter) ([]LogEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []LogEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		entry, ok := ParseLogLine(scanner.Text())
+		if !ok || !filter.matches(entry) {
+			continue
+		}
+		entries = append(entries, entry)
+		if filter.Limit > 0 && len(entries) > 2*filter.Limit {
+			entries = append(entries[:0], entries[len(entries)-filter.Limit:]...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read log file %s: %w", path, err)
+	}
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[len(entries)-filter.Limit:]
+	}
+	return entries, nil
+}
+
+// ParseLogLine parses a line written by Logger.Log. It reports false if the
+// line is not a log entry.
+func ParseLogLine(line string) (LogEntry, bool) {
+	line = strings.TrimRight(line, "\r\n")
+	if len(line) < len(logTimeLayout)+3 {
+		return LogEntry{}, false
+	}
+	ts, err := time.ParseInLocation(logTimeLayout, line[:len(logTimeLayout)], time.Local)
+	if err != nil {
+		return LogEntry{}, false
+	}
+	rest := line[len(logTimeLayout):]
+	if !strings.HasPrefix(rest, " [") {
+		return LogEntry{}, false
+	}
+	end := strings.IndexByte(rest, ']')
+	if end < 0 {
+		return LogEntry{}, false
+	}
+
+	entry := LogEntry{
+		Time:   ts,
+		Action: ActionType(rest[2:end]),
+		Slot:   -1,
+	}
+	fields, ok := parseLogFields(rest[end+1:])
+	if !ok {
+		return LogEntry{}, false
+	}
+	for key, value := range fields {
+		switch key {
+		case "workspace":
+			entry.Workspace = value
+		case "slot":
+			slot, err := strconv.Atoi(value)
+			if err != nil {
+				return LogEntry{}, false
+			}
+			entry.Slot = slot
+		default:
+			if entry.Details == nil {
+				entry.Details = make(map[string]string)
+			}
+			entry.Details[key] = value
+		}
+	}
+	return entry, true
+}
+
+// parseLogFields parses the space-separated key=value pairs that follow the
+// action. Quoted values are unquoted.
+func parseLogFields(s string) (map[string]string, bool) {
+	fields := make(map[string]string)
+	for {
+		s = strings.TrimLeft(s, " ")
+		if s == "" {
+			return fields, true
+		}
+		eq := strings.IndexByte(s, '=')
+		if eq <= 0 || strings.ContainsRune(s[:eq], ' ') {
+			return nil, false
+		}
+		key := s[:eq]
+		s = s[eq+1:]
+
+		if strings.HasPrefix(s, `"`) {
+			quoted, err := strconv.QuotedPrefix(s)
+			if err != nil {
+				return nil, false
+			}
+			value, err := strconv.Unquote(quoted)
+			if err != nil {
+				return nil, false
+			}
+			fields[key] = value
+			s = s[len(quoted):]
+			continue
+		}
+		end := strings.IndexByte(s, ' ')
+		if end < 0 {
+			end = len(s)
+		}
+		fields[key] = s[:end]
+		s = s[end:]
+	}
+}
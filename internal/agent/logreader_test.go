@@ -0,0 +1,106 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const syntheticLog = `2026-01-02 10:00:00 [WORKSPACE-NEW] workspace=dev terminals=3
+2026-01-02 10:00:01 [SPAWN-AGENT] workspace=dev slot=0 agent_type="claude" task_preview="fix \"bug\" now"
+2026-01-02 10:00:02 [SPAWN-AGENT] workspace=dev slot=1 agent_type="codex"
+not a log line
+2026-01-02 10:00:03 [SEND] workspace=dev slot=0 text_preview="hello world"
+2026-01-02 10:00:04 [SPAWN-AGENT] workspace=other slot=0 agent_type="claude"
+2026-01-02 10:00:05 [KILL-AGENT] workspace=dev slot=0
+`
+
+func writeSyntheticLog(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "agent-actions.log")
+	if err := os.WriteFile(path, []byte(syntheticLog), 0600); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+	return path
+}
+
+func TestParseLogLine(t *testing.T) {
+	entry, ok := ParseLogLine(`2026-01-02 10:00:01 [SPAWN-AGENT] workspace=dev slot=0 agent_type="claude" task_preview="fix \"bug\" now"`)
+	if !ok {
+		t.Fatal("ParseLogLine() ok = false")
+	}
+	if entry.Action != ActionSpawnAgent || entry.Workspace != "dev" || entry.Slot != 0 {
+		t.Fatalf("entry = %+v", entry)
+	}
+	if entry.Details["agent_type"] != "claude" || entry.Details["task_preview"] != `fix "bug" now` {
+		t.Fatalf("details = %v", entry.Details)
+	}
+	if entry.Time.Format(logTimeLayout) != "2026-01-02 10:00:01" {
+		t.Fatalf("time = %v", entry.Time)
+	}
+
+	entry, ok = ParseLogLine("2026-01-02 10:00:00 [WORKSPACE-NEW] workspace=dev terminals=3")
+	if !ok || entry.Slot != -1 || entry.Details["terminals"] != "3" {
+		t.Fatalf("workspace entry = %+v ok=%v, want slot -1 and terminals=3", entry, ok)
+	}
+
+	for _, line := range []string{"", "not a log line", "2026-01-02 10:00:00 SEND", `2026-01-02 10:00:00 [SEND] slot=x`, `2026-01-02 10:00:00 [SEND] text="open`} {
+		if _, ok := ParseLogLine(line); ok {
+			t.Errorf("ParseLogLine(%q) ok = true, want false", line)
+		}
+	}
+}
+
+func TestReadLog_Filters(t *testing.T) {
+	path := writeSyntheticLog(t)
+	slot0 := 0
+
+	tests := []struct {
+		name   string
+		filter LogFilter
+		want   []ActionType
+	}{
+		{"workspace", LogFilter{Workspace: "dev"}, []ActionType{ActionWorkspaceNew, ActionSpawnAgent, ActionSpawnAgent, ActionSend, ActionKillAgent}},
+		{"slot", LogFilter{Workspace: "dev", Slot: &slot0}, []ActionType{ActionSpawnAgent, ActionSend, ActionKillAgent}},
+		{"action", LogFilter{Workspace: "dev", Action: ActionSpawnAgent}, []ActionType{ActionSpawnAgent, ActionSpawnAgent}},
+		{"limit keeps most recent", LogFilter{Workspace: "dev", Limit: 2}, []ActionType{ActionSend, ActionKillAgent}},
+		{"all workspaces", LogFilter{Action: ActionSpawnAgent, Limit: 10}, []ActionType{ActionSpawnAgent, ActionSpawnAgent, ActionSpawnAgent}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := ReadLog(path, tt.filter)
+			if err != nil {
+				t.Fatalf("ReadLog() error = %v", err)
+			}
+			if len(entries) != len(tt.want) {
+				t.Fatalf("ReadLog() = %d entries %+v, want %v", len(entries), entries, tt.want)
+			}
+			for i, e := range entries {
+				if e.Action != tt.want[i] {
+					t.Fatalf("entry %d action = %s, want %s", i, e.Action, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReadLog_MissingFile(t *testing.T) {
+	entries, err := ReadLog(filepath.Join(t.TempDir(), "missing.log"), LogFilter{})
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("ReadLog(missing) = %v, %v; want no entries and no error", entries, err)
+	}
+}
+
+func TestParseActionType(t *testing.T) {
+	for _, in := range []string{"spawn_agent", "SPAWN-AGENT", " Spawn-Agent "} {
+		if got, err := ParseActionType(in); err != nil || got != ActionSpawnAgent {
+			t.Errorf("ParseActionType(%q) = %q, %v; want SPAWN-AGENT", in, got, err)
+		}
+	}
+	if got, err := ParseActionType("warning"); err != nil || got != ActionWarning {
+		t.Errorf("ParseActionType(warning) = %q, %v; want WARNING", got, err)
+	}
+	if _, err := ParseActionType("explode"); err == nil {
+		t.Fatal("ParseActionType(explode) error = nil")
+	}
+}
@@ -2,11 +2,13 @@ package agent
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
 // ErrMultiplexerNotAvailable is returned when no multiplexer is installed
-var ErrMultiplexerNotAvailable = errors.New("no terminal multiplexer available (install tmux)")
+var ErrMultiplexerNotAvailable = errors.New("no terminal multiplexer available (install tmux or screen)")
 
 // Multiplexer defines the interface for terminal multiplexer implementations.
 // This abstraction allows termtile to work with tmux, screen, zellij, etc.
@@ -28,12 +30,22 @@ type Multiplexer interface {
 	SendKeys(session, text string) error
 
 	// CapturePane captures the last N lines of output from the session
-	// If lines is 0, captures the visible pane content
+	// If lines is 0, captures the visible pane content; if negative, the
+	// whole scrollback history
 	CapturePane(session string, lines int) (string, error)
 
 	// WaitFor polls the session output until pattern is found or timeout
 	WaitFor(session, pattern string, timeout time.Duration, lines int) (string, error)
 
+	// ListSessions returns the names of all live sessions
+	ListSessions() ([]string, error)
+
+	// RenameSession renames an existing session
+	RenameSession(oldName, newName string) error
+
+	// KillSession terminates a session and everything running in it
+	KillSession(session string) error
+
 	// SessionCommand returns the command to attach to an existing session
 	// or create it if it doesn't exist (e.g., "tmux new-session -A -s <name>")
 	SessionCommand(session string) string
@@ -47,6 +59,30 @@ type Multiplexer interface {
 	DefaultConfig() string
 }
 
+// Paster is implemented by multiplexers that can paste text verbatim, without
+// the key-name interpretation SendKeys input may get.
+type Paster interface {
+	PasteText(session, text string, enter bool) error
+}
+
+// StatusReporter is implemented by multiplexers that can report what a
+// session is running.
+type StatusReporter interface {
+	GetSessionStatus(session string) (SessionStatus, error)
+}
+
+// DetachedStarter is implemented by multiplexers that can start a session
+// running the default shell with no terminal attached.
+type DetachedStarter interface {
+	StartDetached(session, cwd string, env []string) error
+}
+
+// ClientCounter is implemented by multiplexers that can report how many
+// clients are attached to each live session.
+type ClientCounter interface {
+	SessionClients() (map[string]int, error)
+}
+
 // MultiplexerOption configures multiplexer behavior
 type MultiplexerOption func(*multiplexerOptions)
 
@@ -68,3 +104,30 @@ func applyOptions(opts []MultiplexerOption) *multiplexerOptions {
 	}
 	return o
 }
+
+// waitForOutput polls capture until its output contains pattern or timeout
+// elapses. It backs the WaitFor implementations of every multiplexer.
+func waitForOutput(capture func() (string, error), pattern string, timeout time.Duration) (string, error) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return "", fmt.Errorf("--wait-for pattern is required")
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		out, err := capture()
+		if err != nil {
+			return "", err
+		}
+		if strings.Contains(out, pattern) {
+			return out, nil
+		}
+		if time.Now().After(deadline) {
+			return out, fmt.Errorf("timeout waiting for %q in slot output after %s", pattern, timeout)
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+}
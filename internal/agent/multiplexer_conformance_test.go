@@ -0,0 +1,168 @@
+//go:build !windows
+
+package agent
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// muxHarness pairs a Multiplexer with a way to start a detached session
+// running a shell, which is outside the interface (sessions are normally
+// created by the terminal running SessionCommand).
+type muxHarness struct {
+	mux   Multiplexer
+	start func(t *testing.T, session string)
+}
+
+func tmuxHarness(t *testing.T) muxHarness {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not installed")
+	}
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+	t.Cleanup(func() { _ = exec.Command("tmux", "kill-server").Run() })
+
+	return muxHarness{
+		mux: NewTmuxMultiplexer(),
+		start: func(t *testing.T, session string) {
+			if out, err := exec.Command("tmux", "new-session", "-d", "-s", session, "-x", "120", "-y", "40", "sh").CombinedOutput(); err != nil {
+				t.Fatalf("tmux new-session: %v (%s)", err, out)
+			}
+		},
+	}
+}
+
+func screenHarness(t *testing.T) muxHarness {
+	if _, err := exec.LookPath("screen"); err != nil {
+		t.Skip("screen not installed")
+	}
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SCREENDIR", dir)
+	m := NewScreenMultiplexer()
+	t.Cleanup(func() {
+		names, _ := m.ListSessions()
+		for _, name := range names {
+			_ = m.KillSession(name)
+		}
+	})
+
+	return muxHarness{
+		mux: m,
+		start: func(t *testing.T, session string) {
+			if out, err := exec.Command("screen", "-dmS", session, "sh").CombinedOutput(); err != nil {
+				t.Fatalf("screen -dmS: %v (%s)", err, out)
+			}
+		},
+	}
+}
+
+func fakeScreenHarness(t *testing.T) muxHarness {
+	m, f := newFakeScreenMultiplexer()
+	return muxHarness{
+		mux:   m,
+		start: func(t *testing.T, session string) { f.start(session) },
+	}
+}
+
+// TestMultiplexerConformance runs the same lifecycle against every
+// implementation: real binaries when installed, plus the screen CLI fake.
+func TestMultiplexerConformance(t *testing.T) {
+	cases := []struct {
+		name  string
+		setup func(t *testing.T) muxHarness
+	}{
+		{"tmux", tmuxHarness},
+		{"screen", screenHarness},
+		{"screen-fake", fakeScreenHarness},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			runMultiplexerConformance(t, tc.setup(t))
+		})
+	}
+}
+
+func runMultiplexerConformance(t *testing.T, h muxHarness) {
+	m := h.mux
+	const session = "termtile-conformance"
+	const renamed = "termtile-conformance-renamed"
+	const marker = "termtile^conformance"
+
+	if !m.Available() {
+		t.Fatalf("%s: Available() = false", m.Name())
+	}
+	if ok, err := m.HasSession(session); err != nil || ok {
+		t.Fatalf("HasSession(before start) = %v, %v; want false, nil", ok, err)
+	}
+
+	h.start(t, session)
+	waitForSessionState(t, m, session, true)
+	names, err := m.ListSessions()
+	if err != nil {
+		t.Fatalf("ListSessions() error = %v", err)
+	}
+	if !containsString(names, session) {
+		t.Fatalf("ListSessions() = %v, want %q", names, session)
+	}
+
+	if err := m.SendKeys(session, "echo '"+marker+"'"); err != nil {
+		t.Fatalf("SendKeys() error = %v", err)
+	}
+	if _, err := m.WaitFor(session, marker, 5*time.Second, 50); err != nil {
+		t.Fatalf("WaitFor() error = %v", err)
+	}
+	out, err := m.CapturePane(session, 0)
+	if err != nil {
+		t.Fatalf("CapturePane() error = %v", err)
+	}
+	if !strings.Contains(out, marker) {
+		t.Fatalf("CapturePane() = %q, want %q", out, marker)
+	}
+
+	if err := m.RenameSession(session, renamed); err != nil {
+		t.Fatalf("RenameSession() error = %v", err)
+	}
+	waitForSessionState(t, m, renamed, true)
+	waitForSessionState(t, m, session, false)
+
+	if err := m.KillSession(renamed); err != nil {
+		t.Fatalf("KillSession() error = %v", err)
+	}
+	waitForSessionState(t, m, renamed, false)
+}
+
+// waitForSessionState polls HasSession, since some multiplexers apply
+// commands asynchronously.
+func waitForSessionState(t *testing.T, m Multiplexer, session string, want bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		ok, err := m.HasSession(session)
+		if err != nil {
+			t.Fatalf("HasSession(%q) error = %v", session, err)
+		}
+		if ok == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("HasSession(%q) = %v, want %v", session, ok, want)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
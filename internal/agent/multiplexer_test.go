@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -134,13 +135,18 @@ func TestGetMultiplexer_UnknownType(t *testing.T) {
 }
 
 func TestGetMultiplexer_Screen(t *testing.T) {
-	// Screen is deferred - should return error
-	_, err := GetMultiplexer(MultiplexerScreen)
-	if err == nil {
-		t.Error("GetMultiplexer(screen) should return 'not implemented' error")
+	m, err := GetMultiplexer(MultiplexerScreen)
+	if !NewScreenMultiplexer().Available() {
+		if !errors.Is(err, ErrScreenNotAvailable) {
+			t.Errorf("GetMultiplexer(screen) error = %v, want ErrScreenNotAvailable", err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("GetMultiplexer(screen) error = %v", err)
 	}
-	if !strings.Contains(err.Error(), "not yet implemented") {
-		t.Errorf("GetMultiplexer(screen) error = %v, want 'not yet implemented'", err)
+	if m.Name() != "screen" {
+		t.Errorf("GetMultiplexer(screen).Name() = %q, want screen", m.Name())
 	}
 }
 
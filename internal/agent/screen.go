@@ -0,0 +1,372 @@
+package agent
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed templates/screenrc.tmpl
+var defaultScreenConfig string
+
+// ErrScreenNotAvailable is returned when GNU screen is not installed
+var ErrScreenNotAvailable = errors.New("screen is not available in PATH")
+
+// screenHardcopyTimeout bounds how long CapturePane waits for the screen
+// server to write a hardcopy; -X commands are processed asynchronously.
+const screenHardcopyTimeout = 2 * time.Second
+
+// ScreenMultiplexer implements the Multiplexer interface for GNU screen.
+// Sessions are addressed by name; window 0 of the session plays the role of
+// tmux's first pane.
+type ScreenMultiplexer struct {
+	configPath string
+
+	// Seams for tests; default to exec.LookPath and running the screen binary.
+	lookPath func(file string) (string, error)
+	run      func(args ...string) ([]byte, error)
+}
+
+// NewScreenMultiplexer creates a new screen multiplexer instance
+func NewScreenMultiplexer(opts ...MultiplexerOption) *ScreenMultiplexer {
+	o := applyOptions(opts)
+	return &ScreenMultiplexer{
+		configPath: o.configPath,
+		lookPath:   exec.LookPath,
+		run: func(args ...string) ([]byte, error) {
+			return exec.Command("screen", args...).CombinedOutput()
+		},
+	}
+}
+
+// Name returns "screen"
+func (s *ScreenMultiplexer) Name() string {
+	return "screen"
+}
+
+// Available returns true if screen is installed
+func (s *ScreenMultiplexer) Available() bool {
+	_, err := s.lookPath("screen")
+	return err == nil
+}
+
+// screenSession is one entry of `screen -ls`.
+type screenSession struct {
+	ID       string // "<pid>.<name>", unambiguous for -S
+	Name     string
+	Attached bool
+}
+
+// parseScreenList extracts sessions from `screen -ls` output. Session lines
+// are tab-indented and start with "<pid>.<name>".
+func parseScreenList(out string) []screenSession {
+	var sessions []screenSession
+	for _, line := range strings.Split(out, "\n") {
+		if !strings.HasPrefix(line, "\t") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		id := fields[0]
+		dot := strings.IndexByte(id, '.')
+		if dot <= 0 || dot == len(id)-1 {
+			continue
+		}
+		if _, err := strconv.Atoi(id[:dot]); err != nil {
+			continue
+		}
+		attached := strings.Contains(line, "(Attached)") || strings.Contains(line, "(Multi, attached)")
+		sessions = append(sessions, screenSession{ID: id, Name: id[dot+1:], Attached: attached})
+	}
+	return sessions
+}
+
+func (s *ScreenMultiplexer) list() ([]screenSession, error) {
+	out, err := s.run("-ls")
+	if err != nil {
+		// screen -ls exits non-zero both with and without sessions, so only
+		// failures to run the binary at all are errors.
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("screen -ls failed: %w", err)
+		}
+	}
+	return parseScreenList(string(out)), nil
+}
+
+// resolve returns the "<pid>.<name>" ID for session. Plain names are matched
+// exactly here because screen's own -S lookup is a prefix match.
+func (s *ScreenMultiplexer) resolve(session string) (string, error) {
+	sessions, err := s.list()
+	if err != nil {
+		return "", err
+	}
+	for _, sess := range sessions {
+		if sess.Name == session {
+			return sess.ID, nil
+		}
+	}
+	return "", fmt.Errorf("screen session %q not found", session)
+}
+
+// command runs a screen -X command against window 0 of session.
+func (s *ScreenMultiplexer) command(session string, args ...string) error {
+	id, err := s.resolve(session)
+	if err != nil {
+		return err
+	}
+	full := append([]string{"-S", id, "-p", "0", "-X"}, args...)
+	if out, err := s.run(full...); err != nil {
+		return fmt.Errorf("screen %s failed: %w (%s)", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// ListSessions returns all screen session names.
+func (s *ScreenMultiplexer) ListSessions() ([]string, error) {
+	if !s.Available() {
+		return nil, ErrScreenNotAvailable
+	}
+	sessions, err := s.list()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, sess := range sessions {
+		names = append(names, sess.Name)
+	}
+	return names, nil
+}
+
+// SessionClients returns 1 for each attached screen session and 0 for each
+// detached one; screen -ls does not report how many clients share a session.
+func (s *ScreenMultiplexer) SessionClients() (map[string]int, error) {
+	if !s.Available() {
+		return nil, ErrScreenNotAvailable
+	}
+	sessions, err := s.list()
+	if err != nil {
+		return nil, err
+	}
+	clients := make(map[string]int, len(sessions))
+	for _, sess := range sessions {
+		n := 0
+		if sess.Attached {
+			n = 1
+		}
+		clients[sess.Name] = n
+	}
+	return clients, nil
+}
+
+// HasSession checks if a screen session exists
+func (s *ScreenMultiplexer) HasSession(session string) (bool, error) {
+	if !s.Available() {
+		return false, ErrScreenNotAvailable
+	}
+	sessions, err := s.list()
+	if err != nil {
+		return false, err
+	}
+	for _, sess := range sessions {
+		if sess.Name == session {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CreateSession returns the command that creates-or-attaches to a session
+func (s *ScreenMultiplexer) CreateSession(session string) (string, error) {
+	if !s.Available() {
+		return "", ErrScreenNotAvailable
+	}
+	return s.SessionCommand(session), nil
+}
+
+// StartDetached starts a detached screen session in cwd. env holds extra
+// KEY=VALUE pairs for the session's shell.
+func (s *ScreenMultiplexer) StartDetached(session, cwd string, env []string) error {
+	if !s.Available() {
+		return ErrScreenNotAvailable
+	}
+	args := []string{"-dmS", session}
+	if configPath := s.ConfigPath(); configPath != "" {
+		if _, err := os.Stat(configPath); err == nil {
+			args = append([]string{"-c", configPath}, args...)
+		}
+	}
+	cmd := exec.Command("screen", args...)
+	cmd.Dir = cwd
+	cmd.Env = append(os.Environ(), env...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("screen -dmS failed: %w (%s)", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// screenStuffEscape escapes text for the screen `stuff` command, which
+// otherwise treats backslashes, carets and dollar signs as escapes, control
+// characters and variable references.
+func screenStuffEscape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch r {
+		case '\\', '^', '$':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// SendKeys sends text followed by Enter to a screen session
+func (s *ScreenMultiplexer) SendKeys(session, text string) error {
+	if !s.Available() {
+		return ErrScreenNotAvailable
+	}
+	if err := s.command(session, "stuff", screenStuffEscape(text)); err != nil {
+		return err
+	}
+	time.Sleep(sendKeysDelay(text))
+	return s.command(session, "stuff", "^M")
+}
+
+// PasteText stuffs text into window 0 of a screen session verbatim; screen's
+// escape characters are quoted so nothing is interpreted. Enter is only sent
+// when enter is true.
+func (s *ScreenMultiplexer) PasteText(session, text string, enter bool) error {
+	if !s.Available() {
+		return ErrScreenNotAvailable
+	}
+	if err := s.command(session, "stuff", screenStuffEscape(text)); err != nil {
+		return err
+	}
+	if !enter {
+		return nil
+	}
+	return s.command(session, "stuff", "^M")
+}
+
+// CapturePane captures output from window 0 of a screen session via
+// hardcopy. With lines > 0 the scrollback is included and trimmed to the
+// last N lines, and with lines < 0 all of it is returned; otherwise only the
+// visible screen is.
+func (s *ScreenMultiplexer) CapturePane(session string, lines int) (string, error) {
+	if !s.Available() {
+		return "", ErrScreenNotAvailable
+	}
+	dir, err := os.MkdirTemp("", "termtile-screen-")
+	if err != nil {
+		return "", fmt.Errorf("screen hardcopy failed: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "hardcopy")
+
+	args := []string{"hardcopy"}
+	if lines != 0 {
+		args = append(args, "-h")
+	}
+	if err := s.command(session, append(args, path)...); err != nil {
+		return "", err
+	}
+
+	deadline := time.Now().Add(screenHardcopyTimeout)
+	for {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			return tailLines(string(data), lines), nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("screen hardcopy failed: %w", err)
+		}
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("screen hardcopy failed: no output after %s", screenHardcopyTimeout)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// tailLines drops trailing blank lines (hardcopy pads to the window height)
+// and keeps the last n lines; n <= 0 keeps everything.
+func tailLines(out string, n int) string {
+	all := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	for len(all) > 0 && strings.TrimSpace(all[len(all)-1]) == "" {
+		all = all[:len(all)-1]
+	}
+	if n > 0 && len(all) > n {
+		all = all[len(all)-n:]
+	}
+	if len(all) == 0 {
+		return ""
+	}
+	return strings.Join(all, "\n") + "\n"
+}
+
+// WaitFor polls session output until pattern is found or timeout
+func (s *ScreenMultiplexer) WaitFor(session, pattern string, timeout time.Duration, lines int) (string, error) {
+	if !s.Available() {
+		return "", ErrScreenNotAvailable
+	}
+	return waitForOutput(func() (string, error) {
+		return s.CapturePane(session, lines)
+	}, pattern, timeout)
+}
+
+// RenameSession renames a screen session.
+func (s *ScreenMultiplexer) RenameSession(oldName, newName string) error {
+	if !s.Available() {
+		return ErrScreenNotAvailable
+	}
+	return s.command(oldName, "sessionname", newName)
+}
+
+// KillSession kills a screen session by name.
+func (s *ScreenMultiplexer) KillSession(session string) error {
+	if !s.Available() {
+		return ErrScreenNotAvailable
+	}
+	return s.command(session, "quit")
+}
+
+// SessionCommand returns the screen command to create-or-attach to a session.
+// -D -R detaches the session elsewhere if needed and creates it when missing.
+func (s *ScreenMultiplexer) SessionCommand(session string) string {
+	configPath := s.ConfigPath()
+	if configPath != "" {
+		if _, err := os.Stat(configPath); err == nil {
+			return fmt.Sprintf("screen -c %s -D -R -S %s", configPath, session)
+		}
+	}
+	return fmt.Sprintf("screen -D -R -S %s", session)
+}
+
+// ConfigPath returns the path for termtile's screenrc
+func (s *ScreenMultiplexer) ConfigPath() string {
+	if s.configPath != "" {
+		return s.configPath
+	}
+	configDir := os.Getenv("XDG_CONFIG_HOME")
+	if configDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		configDir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configDir, "termtile", "screenrc")
+}
+
+// DefaultConfig returns the default screenrc optimized for agent workflows
+// The config is embedded from templates/screenrc.tmpl
+func (s *ScreenMultiplexer) DefaultConfig() string {
+	return defaultScreenConfig
+}
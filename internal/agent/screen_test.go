@@ -0,0 +1,216 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeScreen emulates the subset of the screen CLI used by ScreenMultiplexer:
+// -ls and -S <pid.name> -p 0 -X {stuff,hardcopy,sessionname,quit}.
+type fakeScreen struct {
+	nextPID  int
+	sessions []*fakeScreenSession
+}
+
+type fakeScreenSession struct {
+	pid  int
+	name string
+	out  strings.Builder
+}
+
+func newFakeScreenMultiplexer() (*ScreenMultiplexer, *fakeScreen) {
+	f := &fakeScreen{nextPID: 1000}
+	m := NewScreenMultiplexer()
+	m.lookPath = func(string) (string, error) { return "/fake/screen", nil }
+	m.run = f.run
+	return m, f
+}
+
+func (f *fakeScreen) start(name string) {
+	f.nextPID++
+	f.sessions = append(f.sessions, &fakeScreenSession{pid: f.nextPID, name: name})
+}
+
+func (f *fakeScreen) run(args ...string) ([]byte, error) {
+	if len(args) == 1 && args[0] == "-ls" {
+		if len(f.sessions) == 0 {
+			return []byte("No Sockets found in /run/screen/S-test.\n\n"), nil
+		}
+		var b strings.Builder
+		b.WriteString("There are screens on:\n")
+		for _, s := range f.sessions {
+			fmt.Fprintf(&b, "\t%d.%s\t(Detached)\n", s.pid, s.name)
+		}
+		fmt.Fprintf(&b, "%d Sockets in /run/screen/S-test.\n\n", len(f.sessions))
+		return []byte(b.String()), nil
+	}
+
+	if len(args) < 6 || args[0] != "-S" || args[2] != "-p" || args[4] != "-X" {
+		return nil, fmt.Errorf("fake screen: unexpected args %q", args)
+	}
+	idx := -1
+	for i, s := range f.sessions {
+		if fmt.Sprintf("%d.%s", s.pid, s.name) == args[1] {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return []byte("No screen session found.\n"), errors.New("exit status 1")
+	}
+	sess := f.sessions[idx]
+
+	cmd := args[5:]
+	switch cmd[0] {
+	case "stuff":
+		sess.out.WriteString(fakeScreenUnstuff(cmd[1]))
+	case "hardcopy":
+		// Pad like screen does so callers must trim trailing blank lines.
+		if err := os.WriteFile(cmd[len(cmd)-1], []byte(sess.out.String()+"\n\n\n"), 0o600); err != nil {
+			return nil, err
+		}
+	case "sessionname":
+		sess.name = cmd[1]
+	case "quit":
+		f.sessions = append(f.sessions[:idx], f.sessions[idx+1:]...)
+	default:
+		return nil, fmt.Errorf("fake screen: unexpected command %q", cmd[0])
+	}
+	return nil, nil
+}
+
+// fakeScreenUnstuff reverses screenStuffEscape and maps ^M to a newline.
+func fakeScreenUnstuff(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			i++
+			b.WriteByte(s[i])
+		case s[i] == '^' && i+1 < len(s) && s[i+1] == 'M':
+			i++
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+func TestScreenMultiplexer_Interface(t *testing.T) {
+	var _ Multiplexer = (*ScreenMultiplexer)(nil)
+}
+
+func TestParseScreenList(t *testing.T) {
+	out := "There are screens on:\n" +
+		"\t4242.termtile-ws-0\t(10/17/2026 09:00:00 AM)\t(Attached)\n" +
+		"\t4243.termtile-ws-10\t(Detached)\n" +
+		"\tnot-a-session\t(Detached)\n" +
+		"2 Sockets in /run/screen/S-user.\n"
+	want := []screenSession{
+		{ID: "4242.termtile-ws-0", Name: "termtile-ws-0", Attached: true},
+		{ID: "4243.termtile-ws-10", Name: "termtile-ws-10"},
+	}
+	if got := parseScreenList(out); !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseScreenList() = %+v, want %+v", got, want)
+	}
+	if got := parseScreenList("No Sockets found in /run/screen/S-user.\n"); len(got) != 0 {
+		t.Fatalf("parseScreenList(no sockets) = %+v, want empty", got)
+	}
+}
+
+func TestScreenStuffEscape(t *testing.T) {
+	got := screenStuffEscape(`echo ^C $HOME \n`)
+	want := `echo \^C \$HOME \\n`
+	if got != want {
+		t.Fatalf("screenStuffEscape() = %q, want %q", got, want)
+	}
+}
+
+func TestTailLines(t *testing.T) {
+	in := "one\ntwo\nthree\n\n   \n"
+	if got := tailLines(in, 0); got != "one\ntwo\nthree\n" {
+		t.Errorf("tailLines(0) = %q", got)
+	}
+	if got := tailLines(in, 2); got != "two\nthree\n" {
+		t.Errorf("tailLines(2) = %q", got)
+	}
+	if got := tailLines("\n\n", 5); got != "" {
+		t.Errorf("tailLines(blank) = %q, want empty", got)
+	}
+}
+
+func TestScreenMultiplexer_ExactSessionMatch(t *testing.T) {
+	m, f := newFakeScreenMultiplexer()
+	f.start("ws-10")
+	f.start("ws-1")
+
+	if err := m.KillSession("ws-1"); err != nil {
+		t.Fatalf("KillSession(ws-1) error = %v", err)
+	}
+	names, err := m.ListSessions()
+	if err != nil {
+		t.Fatalf("ListSessions() error = %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{"ws-10"}) {
+		t.Fatalf("ListSessions() = %v, want [ws-10]", names)
+	}
+	if err := m.SendKeys("ws-1", "echo"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("SendKeys(killed) error = %v, want not found", err)
+	}
+}
+
+func TestScreenMultiplexer_PasteText(t *testing.T) {
+	m, f := newFakeScreenMultiplexer()
+	f.start("ws-0")
+
+	if err := m.PasteText("ws-0", "a $b\\c", false); err != nil {
+		t.Fatalf("PasteText() error = %v", err)
+	}
+	if got := f.sessions[0].out.String(); got != "a $b\\c" {
+		t.Errorf("after PasteText(enter=false) output = %q", got)
+	}
+	if err := m.PasteText("ws-0", " done", true); err != nil {
+		t.Fatalf("PasteText() error = %v", err)
+	}
+	if got := f.sessions[0].out.String(); got != "a $b\\c done\n" {
+		t.Errorf("after PasteText(enter=true) output = %q", got)
+	}
+
+	var _ Paster = m
+}
+
+func TestScreenMultiplexer_NotAvailable(t *testing.T) {
+	m := NewScreenMultiplexer()
+	m.lookPath = func(string) (string, error) { return "", errors.New("not found") }
+	if _, err := m.HasSession("x"); !errors.Is(err, ErrScreenNotAvailable) {
+		t.Errorf("HasSession() error = %v, want ErrScreenNotAvailable", err)
+	}
+	if err := m.SendKeys("x", "y"); !errors.Is(err, ErrScreenNotAvailable) {
+		t.Errorf("SendKeys() error = %v, want ErrScreenNotAvailable", err)
+	}
+}
+
+func TestScreenMultiplexer_SessionCommand(t *testing.T) {
+	m := NewScreenMultiplexer(WithConfigPath("/nonexistent/screenrc"))
+	if got := m.SessionCommand("ws-0"); got != "screen -D -R -S ws-0" {
+		t.Errorf("SessionCommand() = %q", got)
+	}
+
+	configPath := filepath.Join(t.TempDir(), "screenrc")
+	if _, err := EnsureConfig(NewScreenMultiplexer(WithConfigPath(configPath))); err != nil {
+		t.Fatalf("EnsureConfig() error = %v", err)
+	}
+	m = NewScreenMultiplexer(WithConfigPath(configPath))
+	want := "screen -c " + configPath + " -D -R -S ws-0"
+	if got := m.SessionCommand("ws-0"); got != want {
+		t.Errorf("SessionCommand() = %q, want %q", got, want)
+	}
+	if !strings.Contains(m.DefaultConfig(), "defscrollback 50000") {
+		t.Error("DefaultConfig() missing defscrollback")
+	}
+}
@@ -58,7 +58,7 @@ func (t *TmuxMultiplexer) HasSession(session string) (bool, error) {
 	if !t.Available() {
 		return false, ErrTmuxNotAvailable
 	}
-	cmd := exec.Command("tmux", "has-session", "-t", session)
+	cmd := exec.Command("tmux", "has-session", "-t", exactSessionTarget(session))
 	if err := cmd.Run(); err != nil {
 		var exitErr *exec.ExitError
 		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
@@ -149,21 +149,60 @@ func (t *TmuxMultiplexer) SendKeys(session, text string) error {
 		return fmt.Errorf("tmux send-keys failed: %w (%s)", err, strings.TrimSpace(string(out)))
 	}
 
-	// Delay to allow terminal to process text before Enter
-	// Scale based on text length: AI CLI tools convert long pastes to
-	// '[pasted X chars]' format which takes time to process
-	// Base: 50ms, add 1ms per 100 chars for long text, cap at 500ms
+	time.Sleep(sendKeysDelay(text))
+
+	// Send Enter separately
+	cmd = exec.Command("tmux", "send-keys", "-t", target, "Enter")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("tmux send-keys (Enter) failed: %w (%s)", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// sendKeysDelay is how long to wait between typing text and pressing Enter,
+// giving the terminal time to process the text first.
+// Scale based on text length: AI CLI tools convert long pastes to
+// '[pasted X chars]' format which takes time to process
+// Base: 50ms, add 1ms per 100 chars for long text, cap at 500ms
+func sendKeysDelay(text string) time.Duration {
 	delay := 50 * time.Millisecond
 	if len(text) > 500 {
-		extraDelay := time.Duration(len(text)/100) * time.Millisecond
-		delay += extraDelay
+		delay += time.Duration(len(text)/100) * time.Millisecond
 		if delay > 500*time.Millisecond {
 			delay = 500 * time.Millisecond
 		}
 	}
-	time.Sleep(delay)
+	return delay
+}
 
-	// Send Enter separately
+// pasteBufferName is the tmux buffer used to stage text for PasteText.
+const pasteBufferName = "termtile-paste"
+
+// PasteText pastes text into a tmux session verbatim. The text is staged in a
+// tmux buffer via stdin rather than passed as a send-keys argument, so large or
+// multi-line input is neither size-limited nor interpreted as key names.
+// Enter is only sent when enter is true.
+func (t *TmuxMultiplexer) PasteText(session, text string, enter bool) error {
+	if !t.Available() {
+		return ErrTmuxNotAvailable
+	}
+	target := t.targetForSession(session)
+
+	cmd := exec.Command("tmux", "load-buffer", "-b", pasteBufferName, "-")
+	cmd.Stdin = strings.NewReader(text)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("tmux load-buffer failed: %w (%s)", err, strings.TrimSpace(string(out)))
+	}
+
+	// -p uses bracketed paste when the application asked for it; -d drops the buffer.
+	cmd = exec.Command("tmux", "paste-buffer", "-d", "-p", "-b", pasteBufferName, "-t", target)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("tmux paste-buffer failed: %w (%s)", err, strings.TrimSpace(string(out)))
+	}
+
+	if !enter {
+		return nil
+	}
 	cmd = exec.Command("tmux", "send-keys", "-t", target, "Enter")
 	if out, err := cmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("tmux send-keys (Enter) failed: %w (%s)", err, strings.TrimSpace(string(out)))
@@ -173,13 +212,28 @@ func (t *TmuxMultiplexer) SendKeys(session, text string) error {
 
 // CapturePane captures output from a tmux pane
 func (t *TmuxMultiplexer) CapturePane(session string, lines int) (string, error) {
+	return t.capturePane(session, lines, false)
+}
+
+// CapturePaneJoined captures like CapturePane but joins lines tmux wrapped at
+// the pane width back into one, as read_from_agent does.
+func (t *TmuxMultiplexer) CapturePaneJoined(session string, lines int) (string, error) {
+	return t.capturePane(session, lines, true)
+}
+
+func (t *TmuxMultiplexer) capturePane(session string, lines int, join bool) (string, error) {
 	if !t.Available() {
 		return "", ErrTmuxNotAvailable
 	}
 	target := t.targetForSession(session)
 	args := []string{"capture-pane", "-p", "-t", target}
+	if join {
+		args = append(args, "-J")
+	}
 	if lines > 0 {
 		args = append(args, "-S", fmt.Sprintf("-%d", lines))
+	} else if lines < 0 {
+		args = append(args, "-S", "-")
 	}
 	cmd := exec.Command("tmux", args...)
 	var stdout bytes.Buffer
@@ -201,28 +255,19 @@ func (t *TmuxMultiplexer) WaitFor(session, pattern string, timeout time.Duration
 	if !t.Available() {
 		return "", ErrTmuxNotAvailable
 	}
-	pattern = strings.TrimSpace(pattern)
-	if pattern == "" {
-		return "", fmt.Errorf("--wait-for pattern is required")
-	}
-	if timeout <= 0 {
-		timeout = 10 * time.Second
-	}
+	return waitForOutput(func() (string, error) {
+		return t.CapturePane(session, lines)
+	}, pattern, timeout)
+}
 
-	deadline := time.Now().Add(timeout)
-	for {
-		out, err := t.CapturePane(session, lines)
-		if err != nil {
-			return "", err
-		}
-		if strings.Contains(out, pattern) {
-			return out, nil
-		}
-		if time.Now().After(deadline) {
-			return out, fmt.Errorf("timeout waiting for %q in slot output after %s", pattern, timeout)
-		}
-		time.Sleep(250 * time.Millisecond)
+// WaitForJoined waits like WaitFor but polls with CapturePaneJoined.
+func (t *TmuxMultiplexer) WaitForJoined(session, pattern string, timeout time.Duration, lines int) (string, error) {
+	if !t.Available() {
+		return "", ErrTmuxNotAvailable
 	}
+	return waitForOutput(func() (string, error) {
+		return t.CapturePaneJoined(session, lines)
+	}, pattern, timeout)
 }
 
 // SessionCommand returns the tmux command to create-or-attach to a session
@@ -269,12 +314,18 @@ func (t *TmuxMultiplexer) targetForSession(session string) string {
 	return session + ":0.0"
 }
 
+// exactSessionTarget returns a target that only matches session by its full
+// name; a bare name also matches sessions it is a prefix of (ws-1 vs ws-10).
+func exactSessionTarget(session string) string {
+	return "=" + session
+}
+
 // RenameSession renames a tmux session.
 func (t *TmuxMultiplexer) RenameSession(oldName, newName string) error {
 	if !t.Available() {
 		return ErrTmuxNotAvailable
 	}
-	cmd := exec.Command("tmux", "rename-session", "-t", oldName, newName)
+	cmd := exec.Command("tmux", "rename-session", "-t", exactSessionTarget(oldName), newName)
 	if out, err := cmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("tmux rename-session failed: %s: %w", strings.TrimSpace(string(out)), err)
 	}
@@ -307,12 +358,46 @@ func (t *TmuxMultiplexer) ListSessions() ([]string, error) {
 	return sessions, nil
 }
 
+// SessionClients returns the number of clients attached to each live tmux
+// session. A tmux server that is not running has no sessions.
+func (t *TmuxMultiplexer) SessionClients() (map[string]int, error) {
+	if !t.Available() {
+		return nil, ErrTmuxNotAvailable
+	}
+	out, err := exec.Command("tmux", "list-sessions", "-F", "#{session_name} #{session_attached}").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && strings.Contains(string(exitErr.Stderr), "no server running") {
+			return map[string]int{}, nil
+		}
+		return nil, fmt.Errorf("tmux list-sessions failed: %w", err)
+	}
+	return parseSessionClients(string(out)), nil
+}
+
+// parseSessionClients parses "name attached" lines from tmux list-sessions.
+func parseSessionClients(out string) map[string]int {
+	clients := make(map[string]int)
+	for _, line := range strings.Split(out, "\n") {
+		idx := strings.LastIndexByte(line, ' ')
+		if idx <= 0 {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(line[idx+1:]))
+		if err != nil {
+			continue
+		}
+		clients[line[:idx]] = n
+	}
+	return clients
+}
+
 // KillSession kills a tmux session by name.
 func (t *TmuxMultiplexer) KillSession(name string) error {
 	if !t.Available() {
 		return ErrTmuxNotAvailable
 	}
-	cmd := exec.Command("tmux", "kill-session", "-t", name)
+	cmd := exec.Command("tmux", "kill-session", "-t", exactSessionTarget(name))
 	if out, err := cmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("tmux kill-session failed: %s: %w", strings.TrimSpace(string(out)), err)
 	}
@@ -347,6 +432,11 @@ func SendKeys(session, text string) error {
 	return defaultTmux.SendKeys(session, text)
 }
 
+// PasteText pastes text verbatim into a tmux session (backward compat)
+func PasteText(session, text string, enter bool) error {
+	return defaultTmux.PasteText(session, text, enter)
+}
+
 // CapturePane captures tmux pane output (backward compat)
 func CapturePane(session string, lines int) (string, error) {
 	return defaultTmux.CapturePane(session, lines)
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -37,6 +38,7 @@ case "$cmd" in
       if [ "$1" = "-t" ]; then
         shift
         session="${1:-}"
+        session="${session#=}"
         break
       fi
       shift
@@ -270,6 +272,56 @@ func TestSendKeys(t *testing.T) {
 	}
 }
 
+func TestPasteText(t *testing.T) {
+	t.Run("tmux missing", func(t *testing.T) {
+		setupNoTmux(t)
+		if err := PasteText("s", "hello", false); !errors.Is(err, ErrTmuxNotAvailable) {
+			t.Fatalf("PasteText() err=%v, want %v", err, ErrTmuxNotAvailable)
+		}
+	})
+
+	cases := []struct {
+		name    string
+		enter   bool
+		wantLog []string
+	}{
+		{
+			name:  "no enter",
+			enter: false,
+			wantLog: []string{
+				"load-buffer -b termtile-paste -",
+				"paste-buffer -d -p -b termtile-paste -t s:0.0",
+			},
+		},
+		{
+			name:  "with enter",
+			enter: true,
+			wantLog: []string{
+				"load-buffer -b termtile-paste -",
+				"paste-buffer -d -p -b termtile-paste -t s:0.0",
+				"send-keys -t s:0.0 Enter",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, logPath := setupStubTmux(t)
+			if err := PasteText("s", "line one\nline two", tc.enter); err != nil {
+				t.Fatalf("PasteText() err=%v", err)
+			}
+			got := readLogLines(t, logPath)
+			if len(got) != len(tc.wantLog) {
+				t.Fatalf("tmux log lines=%d, want %d (%v)", len(got), len(tc.wantLog), got)
+			}
+			for i := range tc.wantLog {
+				if got[i] != tc.wantLog[i] {
+					t.Fatalf("tmux log[%d]=%q, want %q", i, got[i], tc.wantLog[i])
+				}
+			}
+		})
+	}
+}
+
 func TestCapturePane(t *testing.T) {
 	cases := []struct {
 		name           string
@@ -295,6 +347,16 @@ func TestCapturePane(t *testing.T) {
 			wantErr:        false,
 			wantLogContain: "capture-pane -p -t s:0.0 -S -5",
 		},
+		{
+			name:           "success with full history",
+			withStub:       true,
+			session:        "s",
+			lines:          -1,
+			out:            "pane\n",
+			want:           "pane\n",
+			wantErr:        false,
+			wantLogContain: "capture-pane -p -t s:0.0 -S -",
+		},
 		{
 			name:         "capture-pane error with stderr",
 			withStub:     true,
@@ -443,3 +505,11 @@ func TestWaitFor(t *testing.T) {
 	}
 }
 
+
+func TestParseSessionClients(t *testing.T) {
+	got := parseSessionClients("termtile-dev-0 0\ntermtile-dev-1 2\nmy session 1\nbroken\n\n")
+	want := map[string]int{"termtile-dev-0": 0, "termtile-dev-1": 2, "my session": 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseSessionClients = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,79 @@
+// Package clipboard reads the system clipboard through whichever command-line
+// helper is installed (wl-paste on Wayland, xclip or xsel on X11).
+package clipboard
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Reader returns the current clipboard contents.
+type Reader interface {
+	// Name identifies the backing tool (e.g. "wl-paste", "xclip").
+	Name() string
+	Read() (string, error)
+}
+
+// commandReader runs an external helper and returns its stdout.
+type commandReader struct {
+	name string
+	argv []string
+}
+
+func (r *commandReader) Name() string { return r.name }
+
+func (r *commandReader) Read() (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(r.argv[0], r.argv[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s failed: %w (%s)", r.name, err, msg)
+		}
+		return "", fmt.Errorf("%s failed: %w", r.name, err)
+	}
+	return stdout.String(), nil
+}
+
+// candidate describes a clipboard helper and the session type it needs.
+type candidate struct {
+	name    string
+	argv    []string
+	wayland bool
+}
+
+var candidates = []candidate{
+	{name: "wl-paste", argv: []string{"wl-paste", "--no-newline"}, wayland: true},
+	{name: "xclip", argv: []string{"xclip", "-selection", "clipboard", "-o"}},
+	{name: "xsel", argv: []string{"xsel", "--clipboard", "--output"}},
+}
+
+// Test seams.
+var (
+	execLookPath = exec.LookPath
+	getenv       = os.Getenv
+)
+
+// Detect picks a clipboard reader for the current session. wl-paste is only
+// considered when WAYLAND_DISPLAY is set; otherwise the X11 helpers are tried
+// in order.
+func Detect() (Reader, error) {
+	wayland := getenv("WAYLAND_DISPLAY") != ""
+
+	var tried []string
+	for _, c := range candidates {
+		if c.wayland && !wayland {
+			continue
+		}
+		tried = append(tried, c.name)
+		if _, err := execLookPath(c.argv[0]); err != nil {
+			continue
+		}
+		return &commandReader{name: c.name, argv: c.argv}, nil
+	}
+	return nil, fmt.Errorf("no clipboard tool found in PATH (looked for: %s)", strings.Join(tried, ", "))
+}
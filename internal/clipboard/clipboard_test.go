@@ -0,0 +1,65 @@
+package clipboard
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func stubEnv(t *testing.T, env map[string]string, installed ...string) {
+	t.Helper()
+	origLookPath, origGetenv := execLookPath, getenv
+	t.Cleanup(func() {
+		execLookPath, getenv = origLookPath, origGetenv
+	})
+
+	have := make(map[string]bool, len(installed))
+	for _, name := range installed {
+		have[name] = true
+	}
+	execLookPath = func(name string) (string, error) {
+		if have[name] {
+			return "/usr/bin/" + name, nil
+		}
+		return "", errors.New("not found")
+	}
+	getenv = func(key string) string { return env[key] }
+}
+
+func TestDetect_SelectsReader(t *testing.T) {
+	cases := []struct {
+		name      string
+		env       map[string]string
+		installed []string
+		want      string
+	}{
+		{name: "wayland prefers wl-paste", env: map[string]string{"WAYLAND_DISPLAY": "wayland-0"}, installed: []string{"wl-paste", "xclip"}, want: "wl-paste"},
+		{name: "wayland falls back to xclip", env: map[string]string{"WAYLAND_DISPLAY": "wayland-0"}, installed: []string{"xclip"}, want: "xclip"},
+		{name: "x11 ignores wl-paste", env: map[string]string{"DISPLAY": ":0"}, installed: []string{"wl-paste", "xclip"}, want: "xclip"},
+		{name: "x11 falls back to xsel", env: map[string]string{"DISPLAY": ":0"}, installed: []string{"xsel"}, want: "xsel"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			stubEnv(t, tc.env, tc.installed...)
+			r, err := Detect()
+			if err != nil {
+				t.Fatalf("Detect: %v", err)
+			}
+			if r.Name() != tc.want {
+				t.Fatalf("reader=%q, want %q", r.Name(), tc.want)
+			}
+		})
+	}
+}
+
+func TestDetect_NoToolInstalled(t *testing.T) {
+	stubEnv(t, map[string]string{"DISPLAY": ":0"})
+	_, err := Detect()
+	if err == nil {
+		t.Fatalf("expected error when no clipboard tool is installed")
+	}
+	if !strings.Contains(err.Error(), "xclip, xsel") || strings.Contains(err.Error(), "wl-paste") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -2,10 +2,15 @@ package config
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"sort"
 	"strings"
+	"time"
+	"unicode"
 
 	"gopkg.in/yaml.v3"
 )
@@ -41,6 +46,31 @@ const (
 	RegionCustom     RegionType = "custom"
 )
 
+// TileOrder defines the order slots fill a layout's grid.
+type TileOrder string
+
+const (
+	TileOrderRow           TileOrder = "row"            // Left to right, then down (default).
+	TileOrderRowReverse    TileOrder = "row-reverse"    // Right to left, then down.
+	TileOrderColumn        TileOrder = "column"         // Top to bottom, then right.
+	TileOrderColumnReverse TileOrder = "column-reverse" // Bottom to top, then right.
+)
+
+// IsColumn reports whether o fills the grid a column at a time.
+func (o TileOrder) IsColumn() bool {
+	return o == TileOrderColumn || o == TileOrderColumnReverse
+}
+
+// RemainderBias defines where an auto grid puts the windows left over when
+// the count does not fill every row.
+type RemainderBias string
+
+const (
+	RemainderBiasLastRow  RemainderBias = "last-row"  // Partial last row, aligned to the fill order (default).
+	RemainderBiasFirstRow RemainderBias = "first-row" // Partial first row, aligned to the fill order.
+	RemainderBiasBalanced RemainderBias = "balanced"  // Rows differ by at most one window; short rows are centered.
+)
+
 // TileRegion defines where to tile windows.
 type TileRegion struct {
 	Type          RegionType `yaml:"type"`
@@ -72,6 +102,20 @@ type Layout struct {
 	MaxTerminalWidth  int         `yaml:"max_terminal_width"`  // 0 = unlimited
 	MaxTerminalHeight int         `yaml:"max_terminal_height"` // 0 = unlimited
 	FlexibleLastRow   bool        `yaml:"flexible_last_row"`   // Last row windows expand to fill width (auto mode only)
+	GapSize           *int        `yaml:"gap_size,omitempty"`  // nil = use global gap_size
+	Aliases           []string    `yaml:"aliases,omitempty"`   // Short names accepted wherever a layout name is
+	Order             TileOrder   `yaml:"order,omitempty"`     // Slot fill order; "" = row
+	// RemainderBias places the partial rows of an auto grid filled in a row
+	// order; "" = last-row.
+	RemainderBias RemainderBias `yaml:"remainder_bias,omitempty"`
+	// ReservedRegion pins one slot to an edge strip of the tile region; the
+	// other slots tile in the rest of it. nil = no reserved slot.
+	ReservedRegion *TileRegion `yaml:"reserved_region,omitempty"`
+	ReservedSlot   int         `yaml:"reserved_slot,omitempty"` // Slot placed in ReservedRegion
+
+	// SingleWindowNoGap drops the gap, including the one at the region's
+	// edges, when exactly one terminal is tiled.
+	SingleWindowNoGap bool `yaml:"single_window_no_gap,omitempty"`
 }
 
 // AgentMode configures the agent/multiplexer integration
@@ -91,8 +135,114 @@ type AgentMode struct {
 	// workspaces, since slot 0 is typically the orchestrating agent.
 	// Default: true
 	ProtectSlotZero *bool `yaml:"protect_slot_zero"`
+
+	// IdlePollMs is how often wait_for_idle and depends_on waits poll.
+	// Default: 2000 (0 uses the default)
+	IdlePollMs int `yaml:"idle_poll_ms,omitempty"`
+
+	// DefaultIdleTimeoutS is the wait_for_idle timeout when the call
+	// passes none. Default: 120 (0 uses the default)
+	DefaultIdleTimeoutS int `yaml:"default_idle_timeout_s,omitempty"`
+
+	// DefaultDepTimeoutS is the depends_on timeout when the call passes
+	// none. Default: 300 (0 uses the default)
+	DefaultDepTimeoutS int `yaml:"default_dep_timeout_s,omitempty"`
+
+	// WindowSpawnTimeoutS is how long spawn_agent waits for a new terminal's
+	// tmux session to appear. Default: 15 (0 uses the default)
+	WindowSpawnTimeoutS int `yaml:"window_spawn_timeout_s,omitempty"`
+
+	// WindowSettleMs is how long spawn_agent waits after the session appears
+	// before looking up the terminal window. Default: 500 (0 uses the default)
+	WindowSettleMs int `yaml:"window_settle_ms,omitempty"`
+
+	// ReuseDisplayConnection makes the MCP server keep one X11 connection
+	// for the active-window and focus checks around window spawns instead
+	// of opening a new one per call.
+	// Default: false
+	ReuseDisplayConnection *bool `yaml:"reuse_display_connection"`
+
+	// CaptureEscapes passes -e to tmux capture-pane for read_from_agent so
+	// colour and attribute escape sequences are kept in raw output.
+	// Default: false
+	CaptureEscapes *bool `yaml:"capture_escapes"`
+
+	// MaxConcurrentSpawns caps how many spawn_agent calls per workspace
+	// may be starting agents at once; further calls queue until one
+	// finishes starting. Default: 0 (unlimited)
+	MaxConcurrentSpawns int `yaml:"max_concurrent_spawns,omitempty"`
+
+	// MaxInlineTaskBytes is the largest task spawn_agent puts on the agent's
+	// command line (prompt_as_arg) or pipes into it (pipe_task); larger
+	// tasks are typed in with send-keys. Default: 32768 (0 uses the default)
+	MaxInlineTaskBytes int `yaml:"max_inline_task_bytes,omitempty"`
+
+	// OnCompleteCommand is a shell command the MCP server runs when it
+	// first sees a slot go idle after a task, with TERMTILE_WORKSPACE,
+	// TERMTILE_SLOT and TERMTILE_AGENT set. Empty disables it.
+	OnCompleteCommand string `yaml:"on_complete_command,omitempty"`
+
+	// TmuxSessionOptions are tmux session options (e.g. history-limit,
+	// mouse) set with set-option on each session spawn_agent creates.
+	TmuxSessionOptions map[string]string `yaml:"tmux_session_options,omitempty"`
 }
 
+// Agent-mode wait defaults used when the corresponding setting is unset.
+const (
+	DefaultIdlePollMs                = 2000
+	DefaultIdleTimeoutSeconds        = 120
+	DefaultDepTimeoutSeconds         = 300
+	DefaultMaxInlineTaskBytes        = 32 * 1024
+	DefaultWindowSpawnTimeoutSeconds = 15
+	DefaultWindowSettleMs            = 500
+)
+
+// DefaultLogMaxContentBytes is the logging.max_content_bytes used when it is
+// unset.
+const DefaultLogMaxContentBytes = 16 * 1024
+
+// DefaultIdleLineMaxLen is the idle-prompt line length limit used when an
+// agent does not set idle_line_max_len.
+const DefaultIdleLineMaxLen = 40
+
+// Retile targets select which monitors an apply-and-tile touches.
+const (
+	RetileTargetCurrentMonitor = "current_monitor"
+	RetileTargetAllMonitors    = "all_monitors"
+)
+
+// Focus-after-tile modes select which window gets focus once tiling finishes.
+const (
+	FocusAfterTileKeep   = "keep"   // Leave focus wherever tiling left it.
+	FocusAfterTileFirst  = "first"  // Focus the window tiled into slot 0.
+	FocusAfterTileActive = "active" // Refocus the window that was active before tiling.
+)
+
+// Move mode hint positions place the key legend overlay on the monitor.
+const (
+	HintPositionAuto   = "auto"   // First corner clear of the selected terminal.
+	HintPositionTop    = "top"    // Top edge, horizontally centered.
+	HintPositionBottom = "bottom" // Bottom edge, horizontally centered.
+	HintPositionCenter = "center" // Center of the monitor.
+)
+
+// Allowed values for enum settings. Validate and Schema both read these, so
+// a new value only needs adding here.
+var (
+	layoutModes     = []string{string(LayoutModeAuto), string(LayoutModeFixed), string(LayoutModeVertical), string(LayoutModeHorizontal), string(LayoutModeMasterStack)}
+	tileOrders      = []string{string(TileOrderRow), string(TileOrderRowReverse), string(TileOrderColumn), string(TileOrderColumnReverse)}
+	remainderBiases = []string{string(RemainderBiasLastRow), string(RemainderBiasFirstRow), string(RemainderBiasBalanced)}
+	regionTypes     = []string{string(RegionFull), string(RegionLeftHalf), string(RegionRightHalf), string(RegionTopHalf), string(RegionBottomHalf), string(RegionCustom)}
+	paletteBackends = []string{"auto", "rofi", "fuzzel", "dmenu", "wofi"}
+	logLevels       = []string{"debug", "info", "warning", "error"}
+	terminalSorts   = []string{"position", "window_id", "client_list", "active_first", "title", "pid"}
+	retileTargets   = []string{RetileTargetCurrentMonitor, RetileTargetAllMonitors}
+	focusAfterTiles = []string{FocusAfterTileKeep, FocusAfterTileFirst, FocusAfterTileActive}
+	hintPositions   = []string{HintPositionAuto, HintPositionTop, HintPositionBottom, HintPositionCenter}
+	multiplexers    = []string{"auto", "tmux", "screen"}
+	spawnModes      = []string{"pane", "window", "detached"}
+)
+
 const (
 	DefaultMaxTerminalsPerWorkspace = 10
 	DefaultMaxWorkspaces            = 5
@@ -110,6 +260,114 @@ type Limits struct {
 	WorkspaceOverrides       map[string]WorkspaceLimit `yaml:"workspace_overrides,omitempty"`
 }
 
+// DefaultReconcilerIntervalSeconds is the time between reconciler passes when
+// reconciler.interval_seconds is unset.
+const DefaultReconcilerIntervalSeconds = 10
+
+// ReconcilerConfig tunes the daemon's state reconciler.
+type ReconcilerConfig struct {
+	// IntervalSeconds is the time between reconciliation passes.
+	// Default: 10 (0 uses the default)
+	IntervalSeconds int `yaml:"interval_seconds,omitempty"`
+
+	// RunOnStart runs one pass as soon as the daemon starts, clearing
+	// slots left behind by a previous daemon.
+	// Default: true
+	RunOnStart *bool `yaml:"run_on_start,omitempty"`
+
+	// OrphanGraceSeconds is how long a slot or session must stay orphaned
+	// before a pass cleans it up, so entries of a workspace that is still
+	// being created are not removed.
+	// Default: 0 (clean up on the first pass that finds it)
+	OrphanGraceSeconds int `yaml:"orphan_grace_seconds,omitempty"`
+}
+
+// GetInterval returns the time between reconciliation passes.
+func (r *ReconcilerConfig) GetInterval() time.Duration {
+	if r == nil || r.IntervalSeconds <= 0 {
+		return DefaultReconcilerIntervalSeconds * time.Second
+	}
+	return time.Duration(r.IntervalSeconds) * time.Second
+}
+
+// GetRunOnStart returns the effective value, defaulting to true.
+func (r *ReconcilerConfig) GetRunOnStart() bool {
+	if r == nil || r.RunOnStart == nil {
+		return true
+	}
+	return *r.RunOnStart
+}
+
+// GetOrphanGrace returns how long an entry must stay orphaned before cleanup.
+func (r *ReconcilerConfig) GetOrphanGrace() time.Duration {
+	if r == nil || r.OrphanGraceSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(r.OrphanGraceSeconds) * time.Second
+}
+
+// DefaultMoveModeAnnounceCommand is used when move_mode_announce is enabled
+// and move_mode_announce_command is empty; it speaks through speech-dispatcher.
+const DefaultMoveModeAnnounceCommand = "spd-say"
+
+// MoveModeConfig holds nested move mode settings.
+type MoveModeConfig struct {
+	Hints MoveModeHints `yaml:"hints,omitempty"`
+	// Keys remaps the selecting-phase action keys, keyed by action (delete,
+	// insert, append). Each value is a single printable character; actions
+	// left out keep d, n and a.
+	Keys map[string]string `yaml:"keys,omitempty"`
+}
+
+// Move mode actions that move_mode.keys can remap.
+const (
+	MoveModeActionDelete = "delete" // Close the selected terminal.
+	MoveModeActionInsert = "insert" // Open a terminal after the selected one.
+	MoveModeActionAppend = "append" // Open a terminal at the end.
+)
+
+// moveModeActions lists the remappable actions in legend order, and
+// defaultMoveModeKeys their built-in keys.
+var (
+	moveModeActions     = []string{MoveModeActionDelete, MoveModeActionInsert, MoveModeActionAppend}
+	defaultMoveModeKeys = map[string]string{MoveModeActionDelete: "d", MoveModeActionInsert: "n", MoveModeActionAppend: "a"}
+)
+
+// ActionKeys returns the key for every move mode action: the built-in
+// defaults overlaid with Keys, lowercased.
+func (m MoveModeConfig) ActionKeys() map[string]string {
+	keys := make(map[string]string, len(defaultMoveModeKeys))
+	for action, key := range defaultMoveModeKeys {
+		keys[action] = key
+	}
+	for action, key := range m.Keys {
+		if _, ok := keys[action]; ok {
+			keys[action] = strings.ToLower(strings.TrimSpace(key))
+		}
+	}
+	return keys
+}
+
+// ParseMoveModeKey parses a move_mode.keys value: exactly one printable,
+// non-space ASCII character. Letters are returned lowercased.
+func ParseMoveModeKey(key string) (rune, error) {
+	key = strings.TrimSpace(key)
+	if len(key) != 1 || key[0] < '!' || key[0] > '~' {
+		return 0, fmt.Errorf("key %q must be a single printable character", key)
+	}
+	return unicode.ToLower(rune(key[0])), nil
+}
+
+// MoveModeHints customizes the move mode key legend overlay. Empty line lists
+// keep the built-in text for that phase.
+type MoveModeHints struct {
+	// Position is one of auto, top, bottom or center.
+	Position      string   `yaml:"position,omitempty"`
+	Selecting     []string `yaml:"selecting,omitempty"`
+	Move          []string `yaml:"move,omitempty"`
+	ConfirmDelete []string `yaml:"confirm_delete,omitempty"`
+}
+
 // LoggingConfig configures agent action logging.
 type LoggingConfig struct {
 	// Enabled turns agent action logging on/off
@@ -126,6 +384,9 @@ type LoggingConfig struct {
 	IncludeContent bool `yaml:"include_content,omitempty"`
 	// PreviewLength is the number of characters to preview in log (default: 50)
 	PreviewLength int `yaml:"preview_length,omitempty"`
+	// MaxContentBytes caps the full content IncludeContent logs per entry;
+	// longer content is cut and marked "(truncated)" (default: 16384)
+	MaxContentBytes int `yaml:"max_content_bytes,omitempty"`
 }
 
 // GetManageMultiplexerConfig returns the effective value, defaulting to true
@@ -146,6 +407,125 @@ func (a *AgentMode) GetProtectSlotZero() bool {
 	return *a.ProtectSlotZero
 }
 
+// GetReuseDisplayConnection returns the effective value, defaulting to false.
+func (a *AgentMode) GetReuseDisplayConnection() bool {
+	if a == nil || a.ReuseDisplayConnection == nil {
+		return false
+	}
+	return *a.ReuseDisplayConnection
+}
+
+// GetCaptureEscapes returns the effective value, defaulting to false.
+func (a *AgentMode) GetCaptureEscapes() bool {
+	if a == nil || a.CaptureEscapes == nil {
+		return false
+	}
+	return *a.CaptureEscapes
+}
+
+// GetMaxConcurrentSpawns returns the per-workspace spawn limit; 0 means
+// unlimited.
+func (a *AgentMode) GetMaxConcurrentSpawns() int {
+	if a == nil || a.MaxConcurrentSpawns <= 0 {
+		return 0
+	}
+	return a.MaxConcurrentSpawns
+}
+
+// GetMaxInlineTaskBytes returns the largest task size spawn_agent passes
+// inline to the agent command.
+func (a *AgentMode) GetMaxInlineTaskBytes() int {
+	if a == nil || a.MaxInlineTaskBytes <= 0 {
+		return DefaultMaxInlineTaskBytes
+	}
+	return a.MaxInlineTaskBytes
+}
+
+// GetTmuxSessionOptions returns the tmux options set on new agent sessions.
+func (a *AgentMode) GetTmuxSessionOptions() map[string]string {
+	if a == nil {
+		return nil
+	}
+	return a.TmuxSessionOptions
+}
+
+// GetIdlePollInterval returns how often idle and dependency waits poll.
+func (a *AgentMode) GetIdlePollInterval() time.Duration {
+	if a == nil || a.IdlePollMs <= 0 {
+		return DefaultIdlePollMs * time.Millisecond
+	}
+	return time.Duration(a.IdlePollMs) * time.Millisecond
+}
+
+// GetDefaultIdleTimeout returns the wait_for_idle timeout used when a call
+// does not pass one.
+func (a *AgentMode) GetDefaultIdleTimeout() time.Duration {
+	if a == nil || a.DefaultIdleTimeoutS <= 0 {
+		return DefaultIdleTimeoutSeconds * time.Second
+	}
+	return time.Duration(a.DefaultIdleTimeoutS) * time.Second
+}
+
+// GetDefaultDepTimeout returns the depends_on timeout used when a call does
+// not pass one.
+func (a *AgentMode) GetDefaultDepTimeout() time.Duration {
+	if a == nil || a.DefaultDepTimeoutS <= 0 {
+		return DefaultDepTimeoutSeconds * time.Second
+	}
+	return time.Duration(a.DefaultDepTimeoutS) * time.Second
+}
+
+// GetWindowSpawnTimeout returns how long spawn_agent waits for a new
+// terminal's session to appear.
+func (a *AgentMode) GetWindowSpawnTimeout() time.Duration {
+	if a == nil || a.WindowSpawnTimeoutS <= 0 {
+		return DefaultWindowSpawnTimeoutSeconds * time.Second
+	}
+	return time.Duration(a.WindowSpawnTimeoutS) * time.Second
+}
+
+// GetWindowSettle returns how long spawn_agent waits after the session
+// appears before looking up the terminal window.
+func (a *AgentMode) GetWindowSettle() time.Duration {
+	if a == nil || a.WindowSettleMs <= 0 {
+		return DefaultWindowSettleMs * time.Millisecond
+	}
+	return time.Duration(a.WindowSettleMs) * time.Millisecond
+}
+
+// GetIdlePatterns returns idle_pattern followed by idle_patterns, skipping
+// empty entries. Any of them marks the agent idle.
+func (a AgentConfig) GetIdlePatterns() []string {
+	var out []string
+	if a.IdlePattern != "" {
+		out = append(out, a.IdlePattern)
+	}
+	for _, p := range a.IdlePatterns {
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// GetClearInputBeforeSend reports whether automated input to the agent is
+// preceded by clearing the input line.
+func (a AgentConfig) GetClearInputBeforeSend() bool {
+	if a.ClearInputBeforeSend == nil {
+		return true
+	}
+	return *a.ClearInputBeforeSend
+}
+
+// GetIdleLineMaxLen returns the length a line must stay under to count as
+// the agent's idle prompt.
+func (a AgentConfig) GetIdleLineMaxLen() int {
+	if a.IdleLineMaxLen <= 0 {
+		return DefaultIdleLineMaxLen
+	}
+	return a.IdleLineMaxLen
+}
+
 // AgentHooks configures termtile's 3 abstract hook points for an agent.
 // Each field is a shell command that termtile injects into the agent's
 // native hook system (e.g., Claude Code --settings, Gemini env vars).
@@ -157,22 +537,29 @@ type AgentHooks struct {
 
 // AgentConfig describes a CLI agent that can be spawned via MCP.
 type AgentConfig struct {
-	Command       string            `yaml:"command"`
-	Args          []string          `yaml:"args,omitempty"`
-	ReadyPattern  string            `yaml:"ready_pattern,omitempty"`
-	IdlePattern   string            `yaml:"idle_pattern,omitempty"`
-	OutputMode    string            `yaml:"output_mode,omitempty"` // "hooks" (default), "tags", or "terminal"
-	Hooks         AgentHooks        `yaml:"hooks,omitempty"`
-	Description   string            `yaml:"description,omitempty"`
-	Env           map[string]string `yaml:"env,omitempty"`
-	PromptAsArg   bool              `yaml:"prompt_as_arg,omitempty"`
-	PromptFlag    string            `yaml:"prompt_flag,omitempty"`    // flag to pass task (e.g. "-i" for gemini); empty = positional arg
-	SpawnMode     string            `yaml:"spawn_mode,omitempty"`     // "pane" (default) or "window"
-	ResponseFence bool              `yaml:"response_fence,omitempty"` // prepend task with fence instructions for structured output parsing
-	PipeTask      bool              `yaml:"pipe_task,omitempty"`      // pipe task via stdin instead of appending as arg or sending via send-keys
-	Models        []string          `yaml:"models,omitempty"`
-	DefaultModel  string            `yaml:"default_model,omitempty"`
-	ModelFlag     string            `yaml:"model_flag,omitempty"`
+	Command        string            `yaml:"command"`
+	Args           []string          `yaml:"args,omitempty"`
+	ReadyPattern   string            `yaml:"ready_pattern,omitempty"`
+	IdlePattern    string            `yaml:"idle_pattern,omitempty"`
+	IdlePatterns   []string          `yaml:"idle_patterns,omitempty"`     // extra idle prompts; any match (or idle_pattern) means idle
+	IdleLineMaxLen int               `yaml:"idle_line_max_len,omitempty"` // idle prompt lines must be shorter than this; 0 = 40
+	OutputMode     string            `yaml:"output_mode,omitempty"`       // "hooks" (default), "tags", "terminal", or "both" (hooks plus fence)
+	Hooks          AgentHooks        `yaml:"hooks,omitempty"`
+	Description    string            `yaml:"description,omitempty"`
+	Env            map[string]string `yaml:"env,omitempty"`
+	PromptAsArg    bool              `yaml:"prompt_as_arg,omitempty"`
+	PromptFlag     string            `yaml:"prompt_flag,omitempty"`    // flag to pass task (e.g. "-i" for gemini); empty = positional arg
+	SpawnMode      string            `yaml:"spawn_mode,omitempty"`     // "pane" (default), "window" or "detached"
+	ResponseFence  bool              `yaml:"response_fence,omitempty"` // prepend task with fence instructions for structured output parsing
+	PipeTask       bool              `yaml:"pipe_task,omitempty"`      // pipe task via stdin instead of appending as arg or sending via send-keys
+	Models         []string          `yaml:"models,omitempty"`
+	DefaultModel   string            `yaml:"default_model,omitempty"`
+	ModelFlag      string            `yaml:"model_flag,omitempty"`
+	PostSpawnKeys  []string          `yaml:"post_spawn_keys,omitempty"` // inputs sent in order once the agent is ready, before a send-keys task
+
+	// ClearInputBeforeSend sends Escape and Ctrl-U before each automated
+	// input to clear anything partially typed. Default: true
+	ClearInputBeforeSend *bool `yaml:"clear_input_before_send,omitempty"`
 
 	// Hook delivery configuration (data-driven, replaces hardcoded per-agent logic).
 	HookDelivery     string                 `yaml:"hook_delivery,omitempty"`      // "cli_flag", "project_file", "none"
@@ -315,33 +702,51 @@ func DefaultProjectWorkspaceConfig() ProjectWorkspaceConfig {
 
 // Config holds the application configuration.
 type Config struct {
-	Hotkey                   string                  `yaml:"hotkey"`
-	CycleLayoutHotkey        string                  `yaml:"cycle_layout_hotkey"`
-	CycleLayoutReverseHotkey string                  `yaml:"cycle_layout_reverse_hotkey"`
-	UndoHotkey               string                  `yaml:"undo_hotkey"`
-	MoveModeHotkey           string                  `yaml:"move_mode_hotkey"`
-	TerminalAddHotkey        string                  `yaml:"terminal_add_hotkey"`
-	MoveModeTimeout          int                     `yaml:"move_mode_timeout"`
-	PaletteHotkey            string                  `yaml:"palette_hotkey"`
-	PaletteBackend           string                  `yaml:"palette_backend"`
-	PaletteFuzzyMatching     bool                    `yaml:"palette_fuzzy_matching"`
-	Display                  string                  `yaml:"display,omitempty"`
-	XAuthority               string                  `yaml:"xauthority,omitempty"`
-	PreferredTerminal        string                  `yaml:"preferred_terminal,omitempty"`
-	TerminalSpawnCommands    map[string]string       `yaml:"terminal_spawn_commands"`
-	GapSize                  int                     `yaml:"gap_size"`
-	ScreenPadding            Margins                 `yaml:"screen_padding"`
-	DefaultLayout            string                  `yaml:"default_layout"`
-	Layouts                  map[string]Layout       `yaml:"layouts"`
-	TerminalClasses          TerminalClassList       `yaml:"terminal_classes"`
-	TerminalSort             string                  `yaml:"terminal_sort"`
-	LogLevel                 string                  `yaml:"log_level"`
-	TerminalMargins          map[string]Margins      `yaml:"terminal_margins"`
-	AgentMode                AgentMode               `yaml:"agent_mode"`
-	Limits                   Limits                  `yaml:"limits,omitempty"`
-	Logging                  LoggingConfig           `yaml:"logging,omitempty"`
-	Agents                   map[string]AgentConfig  `yaml:"agents,omitempty"`
-	ProjectWorkspace         *ProjectWorkspaceConfig `yaml:"-"`
+	Hotkey                   string                       `yaml:"hotkey"`
+	CycleLayoutHotkey        string                       `yaml:"cycle_layout_hotkey"`
+	CycleLayoutReverseHotkey string                       `yaml:"cycle_layout_reverse_hotkey"`
+	UndoHotkey               string                       `yaml:"undo_hotkey"`
+	MonocleHotkey            string                       `yaml:"monocle_hotkey"`
+	MoveModeHotkey           string                       `yaml:"move_mode_hotkey"`
+	TerminalAddHotkey        string                       `yaml:"terminal_add_hotkey"`
+	MoveModeTimeout          int                          `yaml:"move_mode_timeout"`
+	MoveModeRestoreFocus     bool                         `yaml:"move_mode_restore_focus"`
+	MoveModeAllWindows       bool                         `yaml:"move_mode_all_windows"`
+	MoveModeAnnounce         bool                         `yaml:"move_mode_announce"`         // describe each move mode step through MoveModeAnnounceCommand
+	MoveModeAnnounceCommand  string                       `yaml:"move_mode_announce_command"` // run with the description as the last argument; empty = spd-say
+	MoveMode                 MoveModeConfig               `yaml:"move_mode,omitempty"`
+	PaletteHotkey            string                       `yaml:"palette_hotkey"`
+	LayoutHotkeys            map[string]string            `yaml:"layout_hotkeys,omitempty"` // layout name -> hotkey that applies it
+	PaletteBackend           string                       `yaml:"palette_backend"`
+	PaletteFuzzyMatching     bool                         `yaml:"palette_fuzzy_matching"`
+	Display                  string                       `yaml:"display,omitempty"`
+	XAuthority               string                       `yaml:"xauthority,omitempty"`
+	PreferredTerminal        string                       `yaml:"preferred_terminal,omitempty"`
+	TerminalSpawnCommands    map[string]string            `yaml:"terminal_spawn_commands"`
+	TerminalSpawnEnv         map[string]map[string]string `yaml:"terminal_spawn_env,omitempty"` // terminal class -> env for the spawned terminal process
+	GapSize                  int                          `yaml:"gap_size"`
+	MinTileWidth             int                          `yaml:"min_tile_width,omitempty"`  // 0 = no minimum
+	MinTileHeight            int                          `yaml:"min_tile_height,omitempty"` // 0 = no minimum
+	ScreenPadding            Margins                      `yaml:"screen_padding"`
+	MonitorPadding           map[string]Margins           `yaml:"monitor_padding,omitempty"` // per-monitor screen_padding, keyed by output name
+	DefaultLayout            string                       `yaml:"default_layout"`
+	PersistActiveLayout      bool                         `yaml:"persist_active_layout"` // the daemon restores the last active layout on startup
+	Layouts                  map[string]Layout            `yaml:"layouts"`
+	TerminalClasses          TerminalClassList            `yaml:"terminal_classes"`
+	TerminalSort             string                       `yaml:"terminal_sort"`
+	RetileTarget             string                       `yaml:"retile_target"`
+	FocusAfterTile           string                       `yaml:"focus_after_tile"`
+	TileIgnoresNonTerminals  bool                         `yaml:"tile_ignores_non_terminals"` // false = tiling also places the focused non-terminal window
+	LogLevel                 string                       `yaml:"log_level"`
+	TerminalMargins          map[string]Margins           `yaml:"terminal_margins"`
+	AgentMode                AgentMode                    `yaml:"agent_mode"`
+	Limits                   Limits                       `yaml:"limits,omitempty"`
+	WorkspaceHistoryDepth    int                          `yaml:"workspace_history_depth"` // Previous saves kept per workspace; 0 = none
+	AutosavePrevious         bool                         `yaml:"autosave_previous"`       // workspace load saves the replaced terminals to _previous
+	Reconciler               ReconcilerConfig             `yaml:"reconciler,omitempty"`
+	Logging                  LoggingConfig                `yaml:"logging,omitempty"`
+	Agents                   map[string]AgentConfig       `yaml:"agents,omitempty"`
+	ProjectWorkspace         *ProjectWorkspaceConfig      `yaml:"-"`
 }
 
 func DefaultConfig() *Config {
@@ -350,8 +755,11 @@ func DefaultConfig() *Config {
 		MoveModeHotkey:    "Mod4-Mod1-r", // Super+Alt+R for "relocate"
 		TerminalAddHotkey: "Mod4-Mod1-n", // Super+Alt+N for new terminal in active workspace
 		MoveModeTimeout:   10,            // 10 seconds default timeout
-		PaletteHotkey:     "Mod4-Mod1-g", // Super+Alt+G for palette
-		PaletteBackend:    "auto",
+		MoveMode: MoveModeConfig{
+			Hints: MoveModeHints{Position: HintPositionAuto},
+		},
+		PaletteHotkey:  "Mod4-Mod1-g", // Super+Alt+G for palette
+		PaletteBackend: "auto",
 		// Disabled by default to preserve existing match behavior.
 		PaletteFuzzyMatching: false,
 		TerminalSpawnCommands: map[string]string{
@@ -375,8 +783,14 @@ func DefaultConfig() *Config {
 		Layouts:         BuiltinLayouts(),
 		TerminalClasses: defaultTerminalClasses(),
 		TerminalSort:    "position",
-		LogLevel:        "info",
-		TerminalMargins: make(map[string]Margins),
+		RetileTarget:    RetileTargetCurrentMonitor,
+		FocusAfterTile:  FocusAfterTileKeep,
+		// workspace load keeps the terminals it replaces as _previous.
+		AutosavePrevious: true,
+		// Tiling only places terminals, whatever window has focus.
+		TileIgnoresNonTerminals: true,
+		LogLevel:                "info",
+		TerminalMargins:         make(map[string]Margins),
 		AgentMode: AgentMode{
 			Multiplexer: "auto", // Auto-detect: tmux > screen
 			// ManageMultiplexerConfig defaults to true via getter
@@ -549,6 +963,9 @@ func (c *Config) GetLoggingConfig() LoggingConfig {
 	if cfg.PreviewLength == 0 {
 		cfg.PreviewLength = 50
 	}
+	if cfg.MaxContentBytes == 0 {
+		cfg.MaxContentBytes = DefaultLogMaxContentBytes
+	}
 	if cfg.Level == "" {
 		cfg.Level = "info"
 	}
@@ -590,7 +1007,7 @@ func layoutsForSave(layouts map[string]Layout) map[string]Layout {
 	builtin := BuiltinLayouts()
 	out := make(map[string]Layout)
 	for name, layout := range layouts {
-		if base, ok := builtin[name]; ok && base == layout {
+		if base, ok := builtin[name]; ok && reflect.DeepEqual(base, layout) {
 			continue
 		}
 		out[name] = layout
@@ -625,12 +1042,23 @@ func (c *Config) GetMargins(terminalClass string) Margins {
 	return Margins{}
 }
 
+// GetScreenPadding returns the padding for the named monitor, falling back
+// to screen_padding when monitor_padding has no entry for it.
+func (c *Config) GetScreenPadding(monitor string) Margins {
+	if padding, ok := c.MonitorPadding[monitor]; ok {
+		return padding
+	}
+	return c.ScreenPadding
+}
+
 // GetLayout retrieves a layout by name with validation.
 func (c *Config) GetLayout(name string) (*Layout, error) {
-	layout, ok := c.Layouts[name]
+	resolved, ok := c.ResolveLayoutName(name)
 	if !ok {
 		return nil, fmt.Errorf("layout %q not found", name)
 	}
+	name = resolved
+	layout := c.Layouts[name]
 
 	if err := validateLayout(&layout); err != nil {
 		return nil, fmt.Errorf("invalid layout %q: %w", name, err)
@@ -639,6 +1067,54 @@ func (c *Config) GetLayout(name string) (*Layout, error) {
 	return &layout, nil
 }
 
+// ResolveLayoutName maps a layout name or alias to the layout's primary name.
+func (c *Config) ResolveLayoutName(name string) (string, bool) {
+	if _, ok := c.Layouts[name]; ok {
+		return name, true
+	}
+	for layoutName, layout := range c.Layouts {
+		for _, alias := range layout.Aliases {
+			if alias == name {
+				return layoutName, true
+			}
+		}
+	}
+	return "", false
+}
+
+// RetileAllMonitors reports whether retiling should cover every monitor
+// instead of only the active one.
+func (c *Config) RetileAllMonitors() bool {
+	return c.RetileTarget == RetileTargetAllMonitors
+}
+
+// LayoutGapSize returns the gap size to use when tiling with layout,
+// preferring the layout's own gap_size over the global value.
+func (c *Config) LayoutGapSize(layout *Layout) int {
+	if layout != nil && layout.GapSize != nil {
+		return *layout.GapSize
+	}
+	return c.GapSize
+}
+
+// DeleteLayout removes a user layout by name or alias and returns the
+// primary name it removed. Built-in layouts and the default_layout cannot
+// be deleted.
+func (c *Config) DeleteLayout(name string) (string, error) {
+	resolved, ok := c.ResolveLayoutName(name)
+	if !ok {
+		return "", fmt.Errorf("layout %q not found", name)
+	}
+	if _, builtin := BuiltinLayouts()[resolved]; builtin {
+		return "", fmt.Errorf("layout %q is built in and cannot be deleted", resolved)
+	}
+	if resolved == c.DefaultLayout {
+		return "", fmt.Errorf("layout %q is the default_layout; set another default first", resolved)
+	}
+	delete(c.Layouts, resolved)
+	return resolved, nil
+}
+
 // GetDefaultLayout retrieves the default layout.
 func (c *Config) GetDefaultLayout() (*Layout, error) {
 	return c.GetLayout(c.DefaultLayout)
@@ -649,10 +1125,25 @@ func (c *Config) Validate() error {
 	if c.Hotkey == "" {
 		return &ValidationError{Path: "hotkey", Err: fmt.Errorf("hotkey is required")}
 	}
-	switch c.PaletteBackend {
-	case "auto", "rofi", "fuzzel", "dmenu", "wofi":
-	default:
-		return &ValidationError{Path: "palette_backend", Err: fmt.Errorf("palette_backend must be one of: auto, rofi, fuzzel, dmenu, wofi")}
+	for _, hk := range []struct{ path, value string }{
+		{"hotkey", c.Hotkey},
+		{"cycle_layout_hotkey", c.CycleLayoutHotkey},
+		{"cycle_layout_reverse_hotkey", c.CycleLayoutReverseHotkey},
+		{"undo_hotkey", c.UndoHotkey},
+		{"monocle_hotkey", c.MonocleHotkey},
+		{"move_mode_hotkey", c.MoveModeHotkey},
+		{"terminal_add_hotkey", c.TerminalAddHotkey},
+		{"palette_hotkey", c.PaletteHotkey},
+	} {
+		if hk.value == "" {
+			continue
+		}
+		if _, err := ParseHotkey(hk.value); err != nil {
+			return &ValidationError{Path: hk.path, Err: err}
+		}
+	}
+	if !slices.Contains(paletteBackends, c.PaletteBackend) {
+		return &ValidationError{Path: "palette_backend", Err: fmt.Errorf("palette_backend must be one of: %s", strings.Join(paletteBackends, ", "))}
 	}
 	if c.TerminalSpawnCommands == nil {
 		return &ValidationError{Path: "terminal_spawn_commands", Err: fmt.Errorf("terminal_spawn_commands must not be null")}
@@ -665,25 +1156,108 @@ func (c *Config) Validate() error {
 			return &ValidationError{Path: "terminal_spawn_commands." + class, Err: fmt.Errorf("spawn command must not be empty")}
 		}
 	}
+	for class, env := range c.TerminalSpawnEnv {
+		if strings.TrimSpace(class) == "" {
+			return &ValidationError{Path: "terminal_spawn_env", Err: fmt.Errorf("terminal_spawn_env contains an empty class name")}
+		}
+		for key := range env {
+			if strings.TrimSpace(key) == "" || strings.Contains(key, "=") {
+				return &ValidationError{Path: "terminal_spawn_env." + class, Err: fmt.Errorf("invalid environment variable name %q", key)}
+			}
+		}
+	}
 	if c.GapSize < 0 {
 		return &ValidationError{Path: "gap_size", Err: fmt.Errorf("gap_size must be >= 0")}
 	}
+	if c.MinTileWidth < 0 {
+		return &ValidationError{Path: "min_tile_width", Err: fmt.Errorf("min_tile_width must be >= 0")}
+	}
+	if c.MinTileHeight < 0 {
+		return &ValidationError{Path: "min_tile_height", Err: fmt.Errorf("min_tile_height must be >= 0")}
+	}
+	if c.WorkspaceHistoryDepth < 0 {
+		return &ValidationError{Path: "workspace_history_depth", Err: fmt.Errorf("workspace_history_depth must be >= 0")}
+	}
 	if c.ScreenPadding.Top < 0 || c.ScreenPadding.Bottom < 0 || c.ScreenPadding.Left < 0 || c.ScreenPadding.Right < 0 {
 		return &ValidationError{Path: "screen_padding", Err: fmt.Errorf("screen_padding values must be >= 0")}
 	}
+	for monitor, p := range c.MonitorPadding {
+		if p.Top < 0 || p.Bottom < 0 || p.Left < 0 || p.Right < 0 {
+			return &ValidationError{Path: "monitor_padding." + monitor, Err: fmt.Errorf("monitor_padding values must be >= 0")}
+		}
+	}
 	if len(c.TerminalClasses) == 0 {
 		return &ValidationError{Path: "terminal_classes", Err: fmt.Errorf("terminal_classes must not be empty")}
 	}
 	if c.TerminalMargins == nil {
 		return &ValidationError{Path: "terminal_margins", Err: fmt.Errorf("terminal_margins must not be null")}
 	}
-	if c.LogLevel != "debug" && c.LogLevel != "info" && c.LogLevel != "warning" && c.LogLevel != "error" {
-		return &ValidationError{Path: "log_level", Err: fmt.Errorf("log_level must be one of: debug, info, warning, error")}
+	if !slices.Contains(logLevels, c.LogLevel) {
+		return &ValidationError{Path: "log_level", Err: fmt.Errorf("log_level must be one of: %s", strings.Join(logLevels, ", "))}
+	}
+	if !slices.Contains(terminalSorts, c.TerminalSort) {
+		return &ValidationError{Path: "terminal_sort", Err: fmt.Errorf("terminal_sort must be one of: %s", strings.Join(terminalSorts, ", "))}
+	}
+	if !slices.Contains(retileTargets, c.RetileTarget) {
+		return &ValidationError{Path: "retile_target", Err: fmt.Errorf("retile_target must be one of: %s", strings.Join(retileTargets, ", "))}
+	}
+	if !slices.Contains(hintPositions, c.MoveMode.Hints.Position) {
+		return &ValidationError{Path: "move_mode.hints.position", Err: fmt.Errorf("position must be one of: %s", strings.Join(hintPositions, ", "))}
+	}
+	for _, action := range slices.Sorted(maps.Keys(c.MoveMode.Keys)) {
+		key := c.MoveMode.Keys[action]
+		path := "move_mode.keys." + action
+		if !slices.Contains(moveModeActions, action) {
+			return &ValidationError{Path: path, Err: fmt.Errorf("unknown action %q; must be one of: %s", action, strings.Join(moveModeActions, ", "))}
+		}
+		if _, err := ParseMoveModeKey(key); err != nil {
+			return &ValidationError{Path: path, Err: err}
+		}
+	}
+	keyOwners := make(map[string]string, len(moveModeActions))
+	actionKeys := c.MoveMode.ActionKeys()
+	for _, action := range moveModeActions {
+		key := actionKeys[action]
+		if other, ok := keyOwners[key]; ok {
+			return &ValidationError{Path: "move_mode.keys", Err: fmt.Errorf("%s and %s both use key %q", other, action, key)}
+		}
+		keyOwners[key] = action
+	}
+
+	if !slices.Contains(focusAfterTiles, c.FocusAfterTile) {
+		return &ValidationError{Path: "focus_after_tile", Err: fmt.Errorf("focus_after_tile must be one of: %s", strings.Join(focusAfterTiles, ", "))}
+	}
+	if c.AgentMode.Multiplexer != "" && !slices.Contains(multiplexers, c.AgentMode.Multiplexer) {
+		return &ValidationError{Path: "agent_mode.multiplexer", Err: fmt.Errorf("multiplexer must be one of: %s", strings.Join(multiplexers, ", "))}
+	}
+	if c.AgentMode.IdlePollMs < 0 {
+		return &ValidationError{Path: "agent_mode.idle_poll_ms", Err: fmt.Errorf("idle_poll_ms must be >= 0")}
+	}
+	if c.AgentMode.DefaultIdleTimeoutS < 0 {
+		return &ValidationError{Path: "agent_mode.default_idle_timeout_s", Err: fmt.Errorf("default_idle_timeout_s must be >= 0")}
+	}
+	if c.AgentMode.DefaultDepTimeoutS < 0 {
+		return &ValidationError{Path: "agent_mode.default_dep_timeout_s", Err: fmt.Errorf("default_dep_timeout_s must be >= 0")}
+	}
+	if c.AgentMode.WindowSpawnTimeoutS < 0 {
+		return &ValidationError{Path: "agent_mode.window_spawn_timeout_s", Err: fmt.Errorf("window_spawn_timeout_s must be >= 0")}
+	}
+	if c.AgentMode.WindowSettleMs < 0 {
+		return &ValidationError{Path: "agent_mode.window_settle_ms", Err: fmt.Errorf("window_settle_ms must be >= 0")}
+	}
+	if c.AgentMode.MaxConcurrentSpawns < 0 {
+		return &ValidationError{Path: "agent_mode.max_concurrent_spawns", Err: fmt.Errorf("max_concurrent_spawns must be >= 0")}
+	}
+	if c.AgentMode.MaxInlineTaskBytes < 0 {
+		return &ValidationError{Path: "agent_mode.max_inline_task_bytes", Err: fmt.Errorf("max_inline_task_bytes must be >= 0")}
+	}
+	for name := range c.AgentMode.TmuxSessionOptions {
+		if strings.TrimSpace(name) == "" || strings.ContainsAny(name, " \t") {
+			return &ValidationError{Path: "agent_mode.tmux_session_options", Err: fmt.Errorf("invalid tmux option name %q", name)}
+		}
 	}
-	switch c.TerminalSort {
-	case "position", "window_id", "client_list", "active_first":
-	default:
-		return &ValidationError{Path: "terminal_sort", Err: fmt.Errorf("terminal_sort must be one of: position, window_id, client_list, active_first")}
+	if c.Logging.MaxContentBytes < 0 {
+		return &ValidationError{Path: "logging.max_content_bytes", Err: fmt.Errorf("max_content_bytes must be >= 0")}
 	}
 	if c.Limits.MaxTerminalsPerWorkspace < 0 {
 		return &ValidationError{Path: "limits.max_terminals_per_workspace", Err: fmt.Errorf("max_terminals_per_workspace must be >= 0")}
@@ -699,6 +1273,12 @@ func (c *Config) Validate() error {
 			return &ValidationError{Path: "limits.workspace_overrides." + name + ".max_terminals", Err: fmt.Errorf("max_terminals must be >= 0")}
 		}
 	}
+	if c.Reconciler.IntervalSeconds < 0 {
+		return &ValidationError{Path: "reconciler.interval_seconds", Err: fmt.Errorf("interval_seconds must be >= 0")}
+	}
+	if c.Reconciler.OrphanGraceSeconds < 0 {
+		return &ValidationError{Path: "reconciler.orphan_grace_seconds", Err: fmt.Errorf("orphan_grace_seconds must be >= 0")}
+	}
 
 	if len(c.Layouts) == 0 {
 		return &ValidationError{Path: "layouts", Err: fmt.Errorf("layouts must not be empty")}
@@ -716,17 +1296,24 @@ func (c *Config) Validate() error {
 			return &ValidationError{Path: "layouts." + name, Err: err}
 		}
 	}
-
-	if warnings := c.validationWarnings(); len(warnings) > 0 {
-		for _, w := range warnings {
-			fmt.Fprintln(os.Stderr, "warning:", w)
+	if err := validateLayoutAliases(c.Layouts); err != nil {
+		return err
+	}
+	for _, name := range sortedKeys(c.LayoutHotkeys) {
+		path := "layout_hotkeys." + name
+		if _, ok := c.ResolveLayoutName(name); !ok {
+			return &ValidationError{Path: path, Err: fmt.Errorf("layout %q not found in layouts", name)}
+		}
+		if _, err := ParseHotkey(c.LayoutHotkeys[name]); err != nil {
+			return &ValidationError{Path: path, Err: err}
 		}
 	}
 
 	return nil
 }
 
-func (c *Config) validationWarnings() []string {
+// Warnings reports settings that pass Validate but are probably mistakes.
+func (c *Config) Warnings() []string {
 	if c == nil {
 		return nil
 	}
@@ -752,65 +1339,165 @@ func (c *Config) validationWarnings() []string {
 	return warnings
 }
 
-// validateLayout checks if a layout configuration is valid.
+// validateLayoutAliases rejects aliases that are empty, shadow a layout name,
+// or are claimed by more than one layout.
+func validateLayoutAliases(layouts map[string]Layout) error {
+	owners := make(map[string]string)
+	for _, name := range sortedKeys(layouts) {
+		for i, alias := range layouts[name].Aliases {
+			path := fmt.Sprintf("layouts.%s.aliases[%d]", name, i)
+			if strings.TrimSpace(alias) == "" {
+				return &ValidationError{Path: path, Err: fmt.Errorf("alias must not be empty")}
+			}
+			if _, ok := layouts[alias]; ok {
+				return &ValidationError{Path: path, Err: fmt.Errorf("alias %q collides with layout %q", alias, alias)}
+			}
+			if owner, ok := owners[alias]; ok {
+				return &ValidationError{Path: path, Err: fmt.Errorf("alias %q is already used by layout %q", alias, owner)}
+			}
+			owners[alias] = name
+		}
+	}
+	return nil
+}
+
+// ValidateLayout checks if a layout configuration is valid.
+func ValidateLayout(layout *Layout) error {
+	return validateLayout(layout)
+}
+
+// LayoutFieldError is a layout validation failure naming the field that
+// failed, relative to the layout (e.g. "master_stack.max_stack_rows"). Its
+// message is Err's, so wrapping it leaves error text unchanged.
+type LayoutFieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *LayoutFieldError) Error() string { return e.Err.Error() }
+
+func (e *LayoutFieldError) Unwrap() error { return e.Err }
+
+func layoutFieldError(field string, format string, args ...any) error {
+	return &LayoutFieldError{Field: field, Err: fmt.Errorf(format, args...)}
+}
+
+// validateLayout checks if a layout configuration is valid. Failures are
+// *LayoutFieldError.
 func validateLayout(layout *Layout) error {
-	switch layout.Mode {
-	case LayoutModeAuto, LayoutModeFixed, LayoutModeVertical, LayoutModeHorizontal, LayoutModeMasterStack:
-	default:
-		return fmt.Errorf("invalid mode %q", layout.Mode)
+	if !slices.Contains(layoutModes, string(layout.Mode)) {
+		return layoutFieldError("mode", "invalid mode %q", layout.Mode)
+	}
+
+	if layout.Order != "" && !slices.Contains(tileOrders, string(layout.Order)) {
+		return layoutFieldError("order", "invalid order %q", layout.Order)
+	}
+
+	if layout.RemainderBias != "" && !slices.Contains(remainderBiases, string(layout.RemainderBias)) {
+		return layoutFieldError("remainder_bias", "invalid remainder_bias %q", layout.RemainderBias)
 	}
 
 	if layout.Mode == LayoutModeFixed {
-		if layout.FixedGrid.Rows <= 0 || layout.FixedGrid.Cols <= 0 {
-			return fmt.Errorf("fixed mode requires rows and cols to be positive")
+		if layout.FixedGrid.Rows <= 0 {
+			return layoutFieldError("fixed_grid.rows", "fixed mode requires rows and cols to be positive")
+		}
+		if layout.FixedGrid.Cols <= 0 {
+			return layoutFieldError("fixed_grid.cols", "fixed mode requires rows and cols to be positive")
 		}
 	}
 
 	if layout.Mode == LayoutModeMasterStack {
 		if layout.MasterStack.MasterWidthPercent < 10 || layout.MasterStack.MasterWidthPercent > 90 {
-			return fmt.Errorf("master_stack.master_width_percent must be between 10 and 90")
+			return layoutFieldError("master_stack.master_width_percent", "master_stack.master_width_percent must be between 10 and 90")
 		}
 		if layout.MasterStack.MaxStackRows < 1 {
-			return fmt.Errorf("master_stack.max_stack_rows must be >= 1")
+			return layoutFieldError("master_stack.max_stack_rows", "master_stack.max_stack_rows must be >= 1")
 		}
 		if layout.MasterStack.MaxStackCols < 1 {
-			return fmt.Errorf("master_stack.max_stack_cols must be >= 1")
+			return layoutFieldError("master_stack.max_stack_cols", "master_stack.max_stack_cols must be >= 1")
 		}
 	}
 
-	if layout.MaxTerminalWidth < 0 || layout.MaxTerminalHeight < 0 {
-		return fmt.Errorf("max_terminal_width/height must be >= 0")
+	if layout.MaxTerminalWidth < 0 {
+		return layoutFieldError("max_terminal_width", "max_terminal_width/height must be >= 0")
+	}
+	if layout.MaxTerminalHeight < 0 {
+		return layoutFieldError("max_terminal_height", "max_terminal_width/height must be >= 0")
 	}
 
-	switch layout.TileRegion.Type {
-	case RegionFull, RegionLeftHalf, RegionRightHalf, RegionTopHalf, RegionBottomHalf:
-		// ok
-	case RegionCustom:
-		if layout.TileRegion.XPercent < 0 || layout.TileRegion.XPercent > 100 {
-			return fmt.Errorf("x_percent must be between 0 and 100")
-		}
-		if layout.TileRegion.YPercent < 0 || layout.TileRegion.YPercent > 100 {
-			return fmt.Errorf("y_percent must be between 0 and 100")
-		}
-		if layout.TileRegion.WidthPercent <= 0 || layout.TileRegion.WidthPercent > 100 {
-			return fmt.Errorf("width_percent must be between 1 and 100")
-		}
-		if layout.TileRegion.HeightPercent <= 0 || layout.TileRegion.HeightPercent > 100 {
-			return fmt.Errorf("height_percent must be between 1 and 100")
-		}
-		if layout.TileRegion.XPercent+layout.TileRegion.WidthPercent > 100 {
-			return fmt.Errorf("x_percent + width_percent must be <= 100")
+	if layout.GapSize != nil && *layout.GapSize < 0 {
+		return layoutFieldError("gap_size", "gap_size must be >= 0")
+	}
+
+	if field, err := validateTileRegion(layout.TileRegion); err != nil {
+		return &LayoutFieldError{Field: "tile_region." + field, Err: err}
+	}
+
+	if layout.ReservedSlot < 0 {
+		return layoutFieldError("reserved_slot", "reserved_slot must be >= 0")
+	}
+	if layout.ReservedRegion != nil {
+		if field, err := validateTileRegion(*layout.ReservedRegion); err != nil {
+			return layoutFieldError("reserved_region."+field, "reserved_region: %w", err)
 		}
-		if layout.TileRegion.YPercent+layout.TileRegion.HeightPercent > 100 {
-			return fmt.Errorf("y_percent + height_percent must be <= 100")
+		if !layout.ReservedRegion.IsEdgeStrip() {
+			return layoutFieldError("reserved_region", "reserved_region must be a half preset or a custom strip spanning the full width or height along one edge")
 		}
-	default:
-		return fmt.Errorf("invalid region type %q", layout.TileRegion.Type)
 	}
 
 	return nil
 }
 
+// validateTileRegion checks region and, on failure, also returns the
+// region field at fault.
+func validateTileRegion(region TileRegion) (string, error) {
+	if !slices.Contains(regionTypes, string(region.Type)) {
+		return "type", fmt.Errorf("invalid region type %q", region.Type)
+	}
+	if region.Type != RegionCustom {
+		return "", nil
+	}
+	if region.XPercent < 0 || region.XPercent > 100 {
+		return "x_percent", fmt.Errorf("x_percent must be between 0 and 100")
+	}
+	if region.YPercent < 0 || region.YPercent > 100 {
+		return "y_percent", fmt.Errorf("y_percent must be between 0 and 100")
+	}
+	if region.WidthPercent <= 0 || region.WidthPercent > 100 {
+		return "width_percent", fmt.Errorf("width_percent must be between 1 and 100")
+	}
+	if region.HeightPercent <= 0 || region.HeightPercent > 100 {
+		return "height_percent", fmt.Errorf("height_percent must be between 1 and 100")
+	}
+	if region.XPercent+region.WidthPercent > 100 {
+		return "width_percent", fmt.Errorf("x_percent + width_percent must be <= 100")
+	}
+	if region.YPercent+region.HeightPercent > 100 {
+		return "height_percent", fmt.Errorf("y_percent + height_percent must be <= 100")
+	}
+	return "", nil
+}
+
+// IsEdgeStrip reports whether the region spans the full width or height
+// along one edge without covering everything, so the rest of the area is
+// itself a rectangle.
+func (r TileRegion) IsEdgeStrip() bool {
+	switch r.Type {
+	case RegionLeftHalf, RegionRightHalf, RegionTopHalf, RegionBottomHalf:
+		return true
+	case RegionCustom:
+		fullWidth := r.XPercent == 0 && r.WidthPercent == 100
+		fullHeight := r.YPercent == 0 && r.HeightPercent == 100
+		if fullWidth && r.HeightPercent < 100 {
+			return r.YPercent == 0 || r.YPercent+r.HeightPercent == 100
+		}
+		if fullHeight && r.WidthPercent < 100 {
+			return r.XPercent == 0 || r.XPercent+r.WidthPercent == 100
+		}
+	}
+	return false
+}
+
 func defaultTerminalClasses() TerminalClassList {
 	return TerminalClassList{
 		{Class: "Alacritty"},
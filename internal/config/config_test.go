@@ -1,12 +1,17 @@
 package config
 
 import (
+	"bytes"
 	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestDefaultConfig_ValidAndHasBuiltinLayouts(t *testing.T) {
@@ -72,6 +77,120 @@ agents:
 	}
 }
 
+func TestLoadFromPath_AgentPostSpawnKeys(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := `
+agents:
+  claude:
+    command: claude
+    post_spawn_keys: ["/model opus", "/init"]
+`
+	if err := os.WriteFile(path, []byte(strings.TrimSpace(data)+"\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	claude := res.Config.Agents["claude"]
+	if got := strings.Join(claude.PostSpawnKeys, ","); got != "/model opus,/init" {
+		t.Fatalf("post_spawn_keys = %q", got)
+	}
+	// Unset fields still come from the builtin claude agent.
+	if claude.IdlePattern == "" {
+		t.Fatal("idle_pattern was not carried over from the builtin agent")
+	}
+}
+
+func TestLoadFromPath_AgentClearInputBeforeSend(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := `
+agents:
+  claude:
+    command: claude
+    clear_input_before_send: false
+`
+	if err := os.WriteFile(path, []byte(strings.TrimSpace(data)+"\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if res.Config.Agents["claude"].GetClearInputBeforeSend() {
+		t.Fatal("claude clear_input_before_send = true, want false")
+	}
+	for name, agent := range res.Config.Agents {
+		if name != "claude" && !agent.GetClearInputBeforeSend() {
+			t.Fatalf("%s clear_input_before_send = false, want default true", name)
+		}
+	}
+}
+
+func TestLoadFromPath_AgentIdleLineMaxLen(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := `
+agents:
+  aider:
+    command: aider
+    idle_pattern: ">"
+    idle_line_max_len: 80
+  claude:
+    command: claude
+`
+	if err := os.WriteFile(path, []byte(strings.TrimSpace(data)+"\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := res.Config.Agents["aider"].GetIdleLineMaxLen(); got != 80 {
+		t.Fatalf("aider idle_line_max_len = %d, want 80", got)
+	}
+	if got := res.Config.Agents["claude"].GetIdleLineMaxLen(); got != DefaultIdleLineMaxLen {
+		t.Fatalf("claude idle_line_max_len = %d, want default %d", got, DefaultIdleLineMaxLen)
+	}
+}
+
+func TestLoadFromPath_AgentIdlePatterns(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := `
+agents:
+  claude:
+    idle_patterns: [">>>", "(plan)"]
+  gemini:
+    idle_pattern: "$"
+    idle_patterns: ["#"]
+`
+	if err := os.WriteFile(path, []byte(strings.TrimSpace(data)+"\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	// Setting idle_patterns alone replaces the builtin idle_pattern.
+	if got := strings.Join(res.Config.Agents["claude"].GetIdlePatterns(), ","); got != ">>>,(plan)" {
+		t.Fatalf("claude idle patterns = %q", got)
+	}
+	if got := strings.Join(res.Config.Agents["gemini"].GetIdlePatterns(), ","); got != "$,#" {
+		t.Fatalf("gemini idle patterns = %q", got)
+	}
+	// Untouched agents keep their builtin pattern.
+	if got := res.Config.Agents["codex"].GetIdlePatterns(); len(got) != 1 || got[0] != "\u203a" {
+		t.Fatalf("codex idle patterns = %q", got)
+	}
+}
+
 func TestLoadFromPath_PaletteFuzzyMatching(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "config.yaml")
@@ -289,6 +408,53 @@ terminal_classes:
 	}
 }
 
+func TestLoadFromPath_TerminalClassesInstance(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := `
+terminal_classes:
+  - Alacritty
+  - class: kitty
+    instance: scratchpad
+`
+	if err := os.WriteFile(path, []byte(strings.TrimSpace(data)+"\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	want := TerminalClassList{{Class: "Alacritty"}, {Class: "kitty", Instance: "scratchpad"}}
+	if !reflect.DeepEqual(res.Config.TerminalClasses, want) {
+		t.Fatalf("terminal classes = %#v, want %#v", res.Config.TerminalClasses, want)
+	}
+
+	out, err := yaml.Marshal(res.Config.TerminalClasses)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var roundTrip TerminalClassList
+	if err := yaml.Unmarshal(out, &roundTrip); err != nil {
+		t.Fatalf("unmarshal %q: %v", out, err)
+	}
+	if !reflect.DeepEqual(roundTrip, want) {
+		t.Fatalf("round trip = %#v, want %#v", roundTrip, want)
+	}
+
+	for _, bad := range []string{
+		"terminal_classes:\n  - class: kitty\n    instance: \"\"\n",
+		"terminal_classes:\n  - class: kitty\n    instance: [a]\n",
+	} {
+		if err := os.WriteFile(path, []byte(bad), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if _, err := LoadFromPath(path); err == nil || !strings.Contains(err.Error(), "instance") {
+			t.Fatalf("LoadFromPath(%q) error = %v, want an instance error", bad, err)
+		}
+	}
+}
+
 func TestResolveTerminal_PrefDefaultEnvSystemPriorityOrder(t *testing.T) {
 	origLookPath := execLookPath
 	origSysDetect := detectSystemTerminal
@@ -498,6 +664,197 @@ func TestLoadFromPath_ProtectSlotZeroFromYAML(t *testing.T) {
 	}
 }
 
+func TestLoadFromPath_MultiplexerFromYAML(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := "agent_mode:\n  multiplexer: screen\n  manage_multiplexer_config: false\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if res.Config.AgentMode.Multiplexer != "screen" {
+		t.Fatalf("multiplexer = %q, want screen", res.Config.AgentMode.Multiplexer)
+	}
+	if res.Config.AgentMode.GetManageMultiplexerConfig() {
+		t.Fatal("expected manage_multiplexer_config to be false from YAML")
+	}
+	val, src, err := Explain(res, "agent_mode.multiplexer")
+	if err != nil || val != "screen" || src.Kind != SourceFile {
+		t.Fatalf("explain agent_mode.multiplexer = %#v, %#v, %v", val, src, err)
+	}
+
+	if err := os.WriteFile(path, []byte("agent_mode:\n  multiplexer: zellij\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err = LoadFromPath(path)
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.Path != "agent_mode.multiplexer" {
+		t.Fatalf("err = %v, want agent_mode.multiplexer validation error", err)
+	}
+}
+
+func TestLoadFromPath_AgentModeWaitSettings(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("# empty\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	am := res.Config.AgentMode
+	if am.GetIdlePollInterval() != 2*time.Second || am.GetDefaultIdleTimeout() != 120*time.Second || am.GetDefaultDepTimeout() != 300*time.Second {
+		t.Fatalf("defaults = %s/%s/%s", am.GetIdlePollInterval(), am.GetDefaultIdleTimeout(), am.GetDefaultDepTimeout())
+	}
+
+	data := "agent_mode:\n  idle_poll_ms: 500\n  default_idle_timeout_s: 600\n  default_dep_timeout_s: 900\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err = LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	am = res.Config.AgentMode
+	if am.GetIdlePollInterval() != 500*time.Millisecond || am.GetDefaultIdleTimeout() != 600*time.Second || am.GetDefaultDepTimeout() != 900*time.Second {
+		t.Fatalf("configured = %s/%s/%s", am.GetIdlePollInterval(), am.GetDefaultIdleTimeout(), am.GetDefaultDepTimeout())
+	}
+	if val, _, err := Explain(res, "agent_mode.default_dep_timeout_s"); err != nil || val != 900 {
+		t.Fatalf("explain default_dep_timeout_s = %#v, %v", val, err)
+	}
+
+	if err := os.WriteFile(path, []byte("agent_mode:\n  idle_poll_ms: -1\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err = LoadFromPath(path)
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.Path != "agent_mode.idle_poll_ms" {
+		t.Fatalf("err = %v, want agent_mode.idle_poll_ms validation error", err)
+	}
+}
+
+func TestLoadFromPath_AgentModeMaxConcurrentSpawns(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("agent_mode:\n  max_concurrent_spawns: 3\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := res.Config.AgentMode.GetMaxConcurrentSpawns(); got != 3 {
+		t.Fatalf("max_concurrent_spawns = %d, want 3", got)
+	}
+
+	if err := os.WriteFile(path, []byte("agent_mode:\n  max_concurrent_spawns: -1\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err = LoadFromPath(path)
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.Path != "agent_mode.max_concurrent_spawns" {
+		t.Fatalf("err = %v, want agent_mode.max_concurrent_spawns validation error", err)
+	}
+}
+
+func TestLoadFromPath_AgentModeReuseDisplayConnection(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("# empty\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if res.Config.AgentMode.GetReuseDisplayConnection() {
+		t.Fatal("reuse_display_connection should default to false")
+	}
+
+	if err := os.WriteFile(path, []byte("agent_mode:\n  reuse_display_connection: true\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err = LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !res.Config.AgentMode.GetReuseDisplayConnection() {
+		t.Fatal("reuse_display_connection = false, want true")
+	}
+	if val, _, err := Explain(res, "agent_mode.reuse_display_connection"); err != nil || val != true {
+		t.Fatalf("explain reuse_display_connection = %#v, %v", val, err)
+	}
+}
+
+func TestLoadFromPath_AgentModeCaptureEscapes(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("# empty\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if res.Config.AgentMode.GetCaptureEscapes() {
+		t.Fatal("capture_escapes should default to false")
+	}
+
+	if err := os.WriteFile(path, []byte("agent_mode:\n  capture_escapes: true\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err = LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !res.Config.AgentMode.GetCaptureEscapes() {
+		t.Fatal("capture_escapes = false, want true")
+	}
+	if val, _, err := Explain(res, "agent_mode.capture_escapes"); err != nil || val != true {
+		t.Fatalf("explain capture_escapes = %#v, %v", val, err)
+	}
+}
+
+func TestLoadFromPath_LoggingMaxContentBytes(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("# empty\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := res.Config.GetLoggingConfig().MaxContentBytes; got != DefaultLogMaxContentBytes {
+		t.Fatalf("default max_content_bytes = %d, want %d", got, DefaultLogMaxContentBytes)
+	}
+
+	if err := os.WriteFile(path, []byte("logging:\n  max_content_bytes: 2048\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err = LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := res.Config.GetLoggingConfig().MaxContentBytes; got != 2048 {
+		t.Fatalf("max_content_bytes = %d, want 2048", got)
+	}
+
+	if err := os.WriteFile(path, []byte("logging:\n  max_content_bytes: -1\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err = LoadFromPath(path)
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.Path != "logging.max_content_bytes" {
+		t.Fatalf("expected logging.max_content_bytes validation error, got %v", err)
+	}
+}
+
 func TestLoadFromPath_ProtectSlotZeroDefaultTrue(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "config.yaml")
@@ -850,3 +1207,1170 @@ agents:
 		t.Errorf("expected gemini HookDelivery=project_file, got %q", gemini.HookDelivery)
 	}
 }
+
+func TestLoadFromPath_LayoutGapSizeOverridesGlobal(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := `
+gap_size: 4
+layouts:
+  roomy:
+    inherits: "builtin:master-stack"
+    gap_size: 16
+  tight:
+    inherits: "builtin:grid"
+    gap_size: 0
+`
+	if err := os.WriteFile(path, []byte(strings.TrimSpace(data)+"\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	roomy := res.Config.Layouts["roomy"]
+	if got := res.Config.LayoutGapSize(&roomy); got != 16 {
+		t.Fatalf("expected roomy gap 16, got %d", got)
+	}
+	tight := res.Config.Layouts["tight"]
+	if got := res.Config.LayoutGapSize(&tight); got != 0 {
+		t.Fatalf("expected explicit zero gap to override global, got %d", got)
+	}
+	grid := res.Config.Layouts["grid"]
+	if got := res.Config.LayoutGapSize(&grid); got != 4 {
+		t.Fatalf("expected grid to fall back to global gap 4, got %d", got)
+	}
+
+	val, _, err := Explain(res, "layouts.roomy.gap_size")
+	if err != nil {
+		t.Fatalf("explain: %v", err)
+	}
+	if val != 16 {
+		t.Fatalf("expected explain value 16, got %#v", val)
+	}
+}
+
+func TestLoadFromPath_LayoutGapSizeRejectsNegative(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := `
+layouts:
+  bad:
+    inherits: "builtin:grid"
+    gap_size: -2
+`
+	if err := os.WriteFile(path, []byte(strings.TrimSpace(data)+"\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	_, err := LoadFromPath(path)
+	if err == nil {
+		t.Fatalf("expected negative gap_size to be rejected")
+	}
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.Path != "layouts.bad" {
+		t.Fatalf("expected validation error at layouts.bad, got %v", err)
+	}
+}
+
+func TestLoadFromPath_MinTileSize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("min_tile_width: 640\nmin_tile_height: 360\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if res.Config.MinTileWidth != 640 || res.Config.MinTileHeight != 360 {
+		t.Fatalf("min tile = %dx%d, want 640x360", res.Config.MinTileWidth, res.Config.MinTileHeight)
+	}
+
+	if err := os.WriteFile(path, []byte("min_tile_height: -1\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err = LoadFromPath(path)
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.Path != "min_tile_height" {
+		t.Fatalf("expected min_tile_height validation error, got %v", err)
+	}
+}
+
+func TestLoadFromPath_LayoutOrder(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := `
+layouts:
+  columns:
+    inherits: "builtin:grid"
+    order: "column"
+`
+	if err := os.WriteFile(path, []byte(strings.TrimSpace(data)+"\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := res.Config.Layouts["columns"].Order; got != TileOrderColumn {
+		t.Fatalf("order = %q, want %q", got, TileOrderColumn)
+	}
+	if got := res.Config.Layouts["grid"].Order; got != "" {
+		t.Fatalf("builtin grid order = %q, want default", got)
+	}
+
+	if err := os.WriteFile(path, []byte("layouts:\n  bad:\n    inherits: \"builtin:grid\"\n    order: \"diagonal\"\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err = LoadFromPath(path)
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.Path != "layouts.bad" {
+		t.Fatalf("expected validation error at layouts.bad, got %v", err)
+	}
+}
+
+func TestLoadFromPath_LayoutRemainderBias(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := `
+layouts:
+  balanced:
+    inherits: "builtin:grid"
+    remainder_bias: "balanced"
+`
+	if err := os.WriteFile(path, []byte(strings.TrimSpace(data)+"\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := res.Config.Layouts["balanced"].RemainderBias; got != RemainderBiasBalanced {
+		t.Fatalf("remainder_bias = %q, want %q", got, RemainderBiasBalanced)
+	}
+	if got := res.Config.Layouts["grid"].RemainderBias; got != "" {
+		t.Fatalf("builtin grid remainder_bias = %q, want default", got)
+	}
+
+	if err := os.WriteFile(path, []byte("layouts:\n  bad:\n    inherits: \"builtin:grid\"\n    remainder_bias: \"middle\"\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err = LoadFromPath(path)
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.Path != "layouts.bad" {
+		t.Fatalf("expected validation error at layouts.bad, got %v", err)
+	}
+}
+
+func TestValidateLayout_FieldErrors(t *testing.T) {
+	full := TileRegion{Type: RegionFull}
+	tests := []struct {
+		layout Layout
+		field  string
+	}{
+		{Layout{Mode: "spiral", TileRegion: full}, "mode"},
+		{Layout{Mode: LayoutModeFixed, TileRegion: full, FixedGrid: FixedGrid{Rows: 2}}, "fixed_grid.cols"},
+		{Layout{Mode: LayoutModeAuto, TileRegion: full, MaxTerminalHeight: -1}, "max_terminal_height"},
+		{Layout{Mode: LayoutModeAuto, TileRegion: TileRegion{Type: "quarter"}}, "tile_region.type"},
+		{Layout{Mode: LayoutModeAuto, TileRegion: full, ReservedRegion: &TileRegion{Type: RegionCustom, YPercent: 120, WidthPercent: 100, HeightPercent: 10}}, "reserved_region.y_percent"},
+		{Layout{Mode: LayoutModeAuto, TileRegion: full, ReservedRegion: &TileRegion{Type: RegionFull}}, "reserved_region"},
+	}
+	for _, tt := range tests {
+		err := ValidateLayout(&tt.layout)
+		var fieldErr *LayoutFieldError
+		if !errors.As(err, &fieldErr) {
+			t.Fatalf("%s: err = %v, want *LayoutFieldError", tt.field, err)
+		}
+		if fieldErr.Field != tt.field {
+			t.Fatalf("field = %q, want %q (err: %v)", fieldErr.Field, tt.field, err)
+		}
+	}
+}
+
+func TestLoadFromPath_LayoutSingleWindowNoGap(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := `
+layouts:
+  solo:
+    inherits: "builtin:grid"
+    single_window_no_gap: true
+`
+	if err := os.WriteFile(path, []byte(strings.TrimSpace(data)+"\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !res.Config.Layouts["solo"].SingleWindowNoGap {
+		t.Fatal("solo single_window_no_gap = false, want true")
+	}
+	if res.Config.Layouts["grid"].SingleWindowNoGap {
+		t.Fatal("builtin grid single_window_no_gap = true, want false")
+	}
+}
+
+func TestLoadFromPath_LayoutReservedRegion(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := `
+layouts:
+  docked:
+    inherits: "builtin:grid"
+    reserved_region:
+      type: "custom"
+      y_percent: 75
+      height_percent: 25
+    reserved_slot: 1
+`
+	if err := os.WriteFile(path, []byte(strings.TrimSpace(data)+"\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	layout := res.Config.Layouts["docked"]
+	want := TileRegion{Type: RegionCustom, YPercent: 75, WidthPercent: 100, HeightPercent: 25}
+	if layout.ReservedRegion == nil || *layout.ReservedRegion != want {
+		t.Fatalf("reserved_region = %+v, want %+v", layout.ReservedRegion, want)
+	}
+	if layout.ReservedSlot != 1 {
+		t.Fatalf("reserved_slot = %d, want 1", layout.ReservedSlot)
+	}
+	if res.Config.Layouts["grid"].ReservedRegion != nil {
+		t.Fatal("builtin grid picked up the reserved region")
+	}
+
+	for _, region := range []string{
+		"{type: full}",
+		"{type: custom, x_percent: 10, y_percent: 10, width_percent: 50, height_percent: 50}",
+	} {
+		data := "layouts:\n  bad:\n    inherits: builtin:grid\n    reserved_region: " + region + "\n"
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		_, err := LoadFromPath(path)
+		var vErr *ValidationError
+		if !errors.As(err, &vErr) || vErr.Path != "layouts.bad" {
+			t.Fatalf("reserved_region %s: err = %v, want validation error at layouts.bad", region, err)
+		}
+	}
+}
+
+func TestLoadFromPath_WorkspaceHistoryDepth(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("workspace_history_depth: 5\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if res.Config.WorkspaceHistoryDepth != 5 {
+		t.Fatalf("workspace_history_depth = %d, want 5", res.Config.WorkspaceHistoryDepth)
+	}
+
+	if err := os.WriteFile(path, []byte("workspace_history_depth: -1\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err = LoadFromPath(path)
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.Path != "workspace_history_depth" {
+		t.Fatalf("err = %v, want workspace_history_depth validation error", err)
+	}
+}
+
+func TestLoadFromPath_AutosavePrevious(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("gap_size: 4\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !res.Config.AutosavePrevious {
+		t.Fatal("autosave_previous should default to true")
+	}
+
+	if err := os.WriteFile(path, []byte("autosave_previous: false\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err = LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if res.Config.AutosavePrevious {
+		t.Fatal("autosave_previous: false was not applied")
+	}
+	if v, _, err := Explain(res, "autosave_previous"); err != nil || v != false {
+		t.Fatalf("Explain(autosave_previous) = %v, %v", v, err)
+	}
+}
+
+func TestLoadFromPath_PersistActiveLayout(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("gap_size: 4\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if res.Config.PersistActiveLayout {
+		t.Fatal("persist_active_layout should default to false")
+	}
+
+	if err := os.WriteFile(path, []byte("persist_active_layout: true\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err = LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !res.Config.PersistActiveLayout {
+		t.Fatal("persist_active_layout: true was not applied")
+	}
+	if v, _, err := Explain(res, "persist_active_layout"); err != nil || v != true {
+		t.Fatalf("Explain(persist_active_layout) = %v, %v", v, err)
+	}
+}
+
+func TestLoadFromPath_TileIgnoresNonTerminals(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("gap_size: 4\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !res.Config.TileIgnoresNonTerminals {
+		t.Fatal("tile_ignores_non_terminals should default to true")
+	}
+
+	if err := os.WriteFile(path, []byte("tile_ignores_non_terminals: false\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err = LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if res.Config.TileIgnoresNonTerminals {
+		t.Fatal("tile_ignores_non_terminals: false was not applied")
+	}
+	if v, _, err := Explain(res, "tile_ignores_non_terminals"); err != nil || v != false {
+		t.Fatalf("Explain(tile_ignores_non_terminals) = %v, %v", v, err)
+	}
+}
+
+func TestLoadFromPath_MonitorPadding(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := `
+screen_padding: {top: 10, bottom: 10, left: 0, right: 0}
+monitor_padding:
+  eDP-1: {top: 40}
+`
+	if err := os.WriteFile(path, []byte(strings.TrimSpace(data)+"\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	// Unset sides of a monitor entry keep the global padding.
+	if got, want := res.Config.GetScreenPadding("eDP-1"), (Margins{Top: 40, Bottom: 10}); got != want {
+		t.Fatalf("eDP-1 padding = %+v, want %+v", got, want)
+	}
+	if got, want := res.Config.GetScreenPadding("HDMI-1"), (Margins{Top: 10, Bottom: 10}); got != want {
+		t.Fatalf("HDMI-1 padding = %+v, want %+v", got, want)
+	}
+	if v, _, err := Explain(res, "monitor_padding.eDP-1.top"); err != nil || v != 40 {
+		t.Fatalf("Explain(monitor_padding.eDP-1.top) = %v, %v", v, err)
+	}
+
+	if err := os.WriteFile(path, []byte("monitor_padding:\n  eDP-1: {left: -1}\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err = LoadFromPath(path)
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.Path != "monitor_padding.eDP-1" {
+		t.Fatalf("err = %v, want monitor_padding.eDP-1 validation error", err)
+	}
+}
+
+func TestLoadFromPath_FocusAfterTile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("focus_after_tile: first\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("LoadFromPath: %v", err)
+	}
+	if res.Config.FocusAfterTile != FocusAfterTileFirst {
+		t.Fatalf("focus_after_tile=%q, want %q", res.Config.FocusAfterTile, FocusAfterTileFirst)
+	}
+
+	if err := os.WriteFile(path, []byte("focus_after_tile: last\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err = LoadFromPath(path)
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.Path != "focus_after_tile" {
+		t.Fatalf("expected validation error at focus_after_tile, got %v", err)
+	}
+}
+
+func TestLoadFromPath_MoveModeHints(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := `
+move_mode:
+  hints:
+    position: Bottom
+    move: ["pick a slot", "Esc cancel"]
+`
+	if err := os.WriteFile(path, []byte(strings.TrimSpace(data)+"\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("LoadFromPath: %v", err)
+	}
+	hints := res.Config.MoveMode.Hints
+	if hints.Position != HintPositionBottom {
+		t.Fatalf("position=%q, want %q", hints.Position, HintPositionBottom)
+	}
+	if len(hints.Move) != 2 || hints.Move[0] != "pick a slot" || hints.Selecting != nil {
+		t.Fatalf("hints=%+v, want configured move lines only", hints)
+	}
+
+	if err := os.WriteFile(path, []byte("move_mode:\n  hints:\n    position: left\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err = LoadFromPath(path)
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.Path != "move_mode.hints.position" {
+		t.Fatalf("expected validation error at move_mode.hints.position, got %v", err)
+	}
+}
+
+func TestLoadFromPath_MoveModeKeys(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := `
+move_mode:
+  keys:
+    delete: X
+    append: ";"
+`
+	if err := os.WriteFile(path, []byte(strings.TrimSpace(data)+"\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("LoadFromPath: %v", err)
+	}
+	keys := res.Config.MoveMode.ActionKeys()
+	want := map[string]string{"delete": "x", "insert": "n", "append": ";"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Fatalf("ActionKeys()=%v, want %v", keys, want)
+	}
+	if v, _, err := Explain(res, "move_mode.keys.delete"); err != nil || v != "x" {
+		t.Fatalf("Explain(move_mode.keys.delete) = %v, %v", v, err)
+	}
+
+	tests := []struct {
+		name string
+		data string
+		path string
+	}{
+		{name: "conflict with default", data: "move_mode:\n  keys:\n    delete: n\n", path: "move_mode.keys"},
+		{name: "conflict ignoring case", data: "move_mode:\n  keys:\n    insert: q\n    append: Q\n", path: "move_mode.keys"},
+		{name: "unknown action", data: "move_mode:\n  keys:\n    swap: s\n", path: "move_mode.keys.swap"},
+		{name: "multi-character key", data: "move_mode:\n  keys:\n    delete: del\n", path: "move_mode.keys.delete"},
+		{name: "empty key", data: "move_mode:\n  keys:\n    delete: \"\"\n", path: "move_mode.keys.delete"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(path, []byte(tt.data), 0644); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			_, err := LoadFromPath(path)
+			var vErr *ValidationError
+			if !errors.As(err, &vErr) || vErr.Path != tt.path {
+				t.Fatalf("expected validation error at %s, got %v", tt.path, err)
+			}
+		})
+	}
+}
+
+func TestLoadFromPath_MoveModeAnnounce(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := `
+move_mode_announce: true
+move_mode_announce_command: "  notify-send -t 1500  "
+`
+	if err := os.WriteFile(path, []byte(strings.TrimSpace(data)+"\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("LoadFromPath: %v", err)
+	}
+	if !res.Config.MoveModeAnnounce || res.Config.MoveModeAnnounceCommand != "notify-send -t 1500" {
+		t.Fatalf("announce=%v command=%q, want enabled notify-send -t 1500", res.Config.MoveModeAnnounce, res.Config.MoveModeAnnounceCommand)
+	}
+	if DefaultConfig().MoveModeAnnounce {
+		t.Fatal("move_mode_announce should default to false")
+	}
+}
+
+func TestLoadFromPath_Reconciler(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+
+	if err := os.WriteFile(path, []byte("hotkey: \"Mod4-t\"\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("LoadFromPath: %v", err)
+	}
+	if got := res.Config.Reconciler.GetInterval(); got != DefaultReconcilerIntervalSeconds*time.Second {
+		t.Fatalf("default interval=%v", got)
+	}
+	if !res.Config.Reconciler.GetRunOnStart() {
+		t.Fatal("run_on_start should default to true")
+	}
+	if got := res.Config.Reconciler.GetOrphanGrace(); got != 0 {
+		t.Fatalf("default orphan grace=%v, want 0", got)
+	}
+
+	data := "reconciler:\n  interval_seconds: 60\n  run_on_start: false\n  orphan_grace_seconds: 15\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err = LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("LoadFromPath: %v", err)
+	}
+	if got := res.Config.Reconciler.GetInterval(); got != time.Minute {
+		t.Fatalf("interval=%v, want 1m", got)
+	}
+	if res.Config.Reconciler.GetRunOnStart() {
+		t.Fatal("run_on_start=false was ignored")
+	}
+	if got := res.Config.Reconciler.GetOrphanGrace(); got != 15*time.Second {
+		t.Fatalf("orphan grace=%v, want 15s", got)
+	}
+	if val, src, err := Explain(res, "reconciler.interval_seconds"); err != nil || val != 60 || src.Kind != SourceFile {
+		t.Fatalf("Explain(reconciler.interval_seconds) = %v, %+v, %v", val, src, err)
+	}
+
+	if err := os.WriteFile(path, []byte("reconciler:\n  interval_seconds: -1\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err = LoadFromPath(path)
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.Path != "reconciler.interval_seconds" {
+		t.Fatalf("expected validation error at reconciler.interval_seconds, got %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("reconciler:\n  orphan_grace_seconds: -5\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err = LoadFromPath(path)
+	if !errors.As(err, &vErr) || vErr.Path != "reconciler.orphan_grace_seconds" {
+		t.Fatalf("expected validation error at reconciler.orphan_grace_seconds, got %v", err)
+	}
+}
+
+func TestLoadFromPath_LayoutAliases(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := `
+layouts:
+  grid:
+    aliases: ["g"]
+  wide:
+    inherits: "builtin:master-stack"
+    aliases: ["w", "ms"]
+`
+	if err := os.WriteFile(path, []byte(strings.TrimSpace(data)+"\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	cfg := res.Config
+
+	for alias, want := range map[string]string{"g": "grid", "grid": "grid", "ms": "wide", "w": "wide"} {
+		if got, ok := cfg.ResolveLayoutName(alias); !ok || got != want {
+			t.Fatalf("ResolveLayoutName(%q)=%q,%v, want %q", alias, got, ok, want)
+		}
+	}
+	layout, err := cfg.GetLayout("ms")
+	if err != nil {
+		t.Fatalf("GetLayout(ms): %v", err)
+	}
+	if layout.Mode != LayoutModeMasterStack {
+		t.Fatalf("GetLayout(ms).Mode=%q, want master-stack", layout.Mode)
+	}
+	if _, err := cfg.GetLayout("nope"); err == nil {
+		t.Fatal("expected unknown layout error")
+	}
+	if _, ok := cfg.ResolveLayoutName("nope"); ok {
+		t.Fatal("ResolveLayoutName(nope) reported a match")
+	}
+}
+
+func TestConfig_SaveRoundTrips(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg := DefaultConfig()
+	cfg.MoveModeHotkey = "Mod4-Mod1-m"
+	cfg.MoveModeTimeout = 25
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	res, err := LoadFromPath(filepath.Join(home, ".config", "termtile", "config.yaml"))
+	if err != nil {
+		t.Fatalf("saved config does not load: %v", err)
+	}
+	if res.Config.MoveModeHotkey != "Mod4-Mod1-m" || res.Config.MoveModeTimeout != 25 {
+		t.Fatalf("move mode settings not kept: hotkey=%q timeout=%d", res.Config.MoveModeHotkey, res.Config.MoveModeTimeout)
+	}
+}
+
+func TestConfig_DeleteLayout(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Layouts["wide"] = Layout{Mode: LayoutModeAuto, Aliases: []string{"w"}}
+	cfg.Layouts["home"] = Layout{Mode: LayoutModeAuto}
+	cfg.DefaultLayout = "home"
+
+	for name, want := range map[string]string{
+		"grid":    "built in",
+		"home":    "default_layout",
+		"missing": "not found",
+	} {
+		if _, err := cfg.DeleteLayout(name); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("DeleteLayout(%q) err=%v, want %q", name, err, want)
+		}
+	}
+
+	got, err := cfg.DeleteLayout("w")
+	if err != nil || got != "wide" {
+		t.Fatalf("DeleteLayout(w) = %q, %v; want wide", got, err)
+	}
+	if _, ok := cfg.Layouts["wide"]; ok {
+		t.Fatal("wide still present after delete")
+	}
+}
+
+func TestLoadFromPath_LayoutAliasCollisions(t *testing.T) {
+	cases := []struct {
+		name     string
+		data     string
+		wantPath string
+	}{
+		{
+			name: "alias shared by two layouts",
+			data: `
+layouts:
+  a:
+    inherits: "builtin:grid"
+    aliases: ["x"]
+  b:
+    inherits: "builtin:grid"
+    aliases: ["x"]
+`,
+			wantPath: "layouts.b.aliases[0]",
+		},
+		{
+			name: "alias shadows a layout name",
+			data: `
+layouts:
+  a:
+    inherits: "builtin:grid"
+    aliases: ["grid"]
+`,
+			wantPath: "layouts.a.aliases[0]",
+		},
+		{
+			name: "empty alias",
+			data: `
+layouts:
+  a:
+    inherits: "builtin:grid"
+    aliases: ["ok", " "]
+`,
+			wantPath: "layouts.a.aliases[1]",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(strings.TrimSpace(tc.data)+"\n"), 0644); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			_, err := LoadFromPath(path)
+			var vErr *ValidationError
+			if !errors.As(err, &vErr) || vErr.Path != tc.wantPath {
+				t.Fatalf("expected validation error at %s, got %v", tc.wantPath, err)
+			}
+		})
+	}
+}
+
+func TestParseLayout(t *testing.T) {
+	layout, err := ParseLayout([]byte(`
+inherits: "builtin:master-stack"
+master_stack:
+  master_width_percent: 60
+gap_size: 4
+`))
+	if err != nil {
+		t.Fatalf("ParseLayout: %v", err)
+	}
+	if layout.Mode != LayoutModeMasterStack || layout.MasterStack.MasterWidthPercent != 60 {
+		t.Fatalf("layout = %+v, want master-stack at 60%%", layout)
+	}
+	if base := BuiltinLayouts()["master-stack"]; layout.MasterStack.MaxStackRows != base.MasterStack.MaxStackRows {
+		t.Fatalf("max_stack_rows = %d, want inherited %d", layout.MasterStack.MaxStackRows, base.MasterStack.MaxStackRows)
+	}
+	if layout.GapSize == nil || *layout.GapSize != 4 {
+		t.Fatalf("gap_size = %v, want 4", layout.GapSize)
+	}
+
+	layout, err = ParseLayout([]byte("mode: vertical\n"))
+	if err != nil {
+		t.Fatalf("ParseLayout(no inherits): %v", err)
+	}
+	if layout.Mode != LayoutModeVertical || layout.TileRegion.Type != RegionFull {
+		t.Fatalf("layout = %+v, want vertical over full region", layout)
+	}
+}
+
+func TestParseLayout_Rejects(t *testing.T) {
+	cases := map[string]string{
+		"unknown field":   "mode: vertical\nbogus: 1\n",
+		"invalid mode":    "mode: spiral\n",
+		"fixed no grid":   "mode: fixed\nfixed_grid:\n  rows: 0\n  cols: 2\n",
+		"unknown inherit": "inherits: builtin:nope\n",
+	}
+	for name, data := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseLayout([]byte(data)); err == nil {
+				t.Fatalf("ParseLayout(%q) error = nil", data)
+			}
+		})
+	}
+
+	_, err := ParseLayout([]byte("mode: spiral\n"))
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.Path != "layout" {
+		t.Fatalf("expected validation error at layout, got %v", err)
+	}
+	_, err = ParseLayout([]byte("inherits: grid\n"))
+	if !errors.As(err, &vErr) || vErr.Path != "layout.inherits" {
+		t.Fatalf("expected validation error at layout.inherits, got %v", err)
+	}
+}
+
+func TestParseHotkey(t *testing.T) {
+	valid := map[string][]string{
+		"Mod4-Mod1-t":             {"Mod4-Mod1-t"},
+		"Mod4-space g":            {"Mod4-space", "g"},
+		"  Mod4-space   Shift-g ": {"Mod4-space", "Shift-g"},
+	}
+	for in, want := range valid {
+		got, err := ParseHotkey(in)
+		if err != nil {
+			t.Errorf("ParseHotkey(%q) error = %v", in, err)
+			continue
+		}
+		if strings.Join(got, "|") != strings.Join(want, "|") {
+			t.Errorf("ParseHotkey(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	for _, in := range []string{"", "Mod4-", "Mod4-Shift", "Mod4-a-b", "Mod4-space g x"} {
+		if _, err := ParseHotkey(in); err == nil {
+			t.Errorf("ParseHotkey(%q) error = nil", in)
+		}
+	}
+}
+
+func TestLoadFromPath_LayoutHotkeys(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	write := func(data string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(strings.TrimSpace(data)+"\n"), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	write(`
+layout_hotkeys:
+  grid: "Mod4-space g"
+undo_hotkey: "Mod4-space u"
+`)
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("LoadFromPath: %v", err)
+	}
+	if got := res.Config.LayoutHotkeys["grid"]; got != "Mod4-space g" {
+		t.Fatalf("layout_hotkeys.grid = %q", got)
+	}
+	if val, src, err := Explain(res, "layout_hotkeys.grid"); err != nil || val != "Mod4-space g" || src.Kind != SourceFile {
+		t.Fatalf("Explain(layout_hotkeys.grid) = %v, %+v, %v", val, src, err)
+	}
+
+	for data, wantPath := range map[string]string{
+		"layout_hotkeys:\n  nope: \"Mod4-g\"\n":     "layout_hotkeys.nope",
+		"layout_hotkeys:\n  grid: \"Mod4-a b c\"\n": "layout_hotkeys.grid",
+		"undo_hotkey: \"Mod4-\"\n":                  "undo_hotkey",
+	} {
+		write(data)
+		_, err := LoadFromPath(path)
+		var vErr *ValidationError
+		if !errors.As(err, &vErr) || vErr.Path != wantPath {
+			t.Fatalf("LoadFromPath(%q) error = %v, want validation error at %s", data, err, wantPath)
+		}
+	}
+}
+
+func TestLoadFromPath_ReturnsWarnings(t *testing.T) {
+	var out bytes.Buffer
+	SetWarningOutput(&out)
+	t.Cleanup(func() { SetWarningOutput(nil) })
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := `
+preferred_terminal: no-such-terminal
+terminal_classes:
+  - class: kitty
+    default: true
+  - class: alacritty
+    default: true
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(res.Warnings) != 2 {
+		t.Fatalf("warnings = %q, want 2", res.Warnings)
+	}
+	if !strings.Contains(res.Warnings[0], "preferred_terminal") || !strings.Contains(res.Warnings[1], "default: true") {
+		t.Fatalf("unexpected warnings %q", res.Warnings)
+	}
+	if got := strings.Count(out.String(), "warning:"); got != 2 {
+		t.Fatalf("printed %d warnings, want 2:\n%s", got, out.String())
+	}
+
+	out.Reset()
+	if err := res.Config.Validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("Validate printed warnings:\n%s", out.String())
+	}
+}
+
+func TestLoadFromPath_LayoutExtends(t *testing.T) {
+	data := `
+layouts:
+  wide:
+    inherits: "builtin:master-stack"
+    aliases: ["w"]
+    gap_size: 6
+    master_stack:
+      master_width_percent: 70
+  wide-capped:
+    extends: wide
+    max_terminal_width: 1200
+  wide-narrow:
+    extends: wide-capped
+    master_stack:
+      master_width_percent: 55
+  grid-tall:
+    extends: grid
+    max_terminal_height: 900
+`
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(strings.TrimSpace(data)+"\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	wide := res.Config.Layouts["wide"]
+	capped := res.Config.Layouts["wide-capped"]
+	if capped.Mode != LayoutModeMasterStack || capped.MasterStack.MasterWidthPercent != 70 {
+		t.Fatalf("wide-capped did not inherit from wide: %+v", capped)
+	}
+	if capped.GapSize == nil || *capped.GapSize != 6 || capped.MaxTerminalWidth != 1200 {
+		t.Fatalf("wide-capped gap/max width = %v/%d, want 6/1200", capped.GapSize, capped.MaxTerminalWidth)
+	}
+	if len(capped.Aliases) != 0 {
+		t.Fatalf("aliases were inherited: %v", capped.Aliases)
+	}
+	if wide.MaxTerminalWidth != 0 {
+		t.Fatalf("extending layout changed its base: %+v", wide)
+	}
+
+	narrow := res.Config.Layouts["wide-narrow"]
+	if narrow.MasterStack.MasterWidthPercent != 55 || narrow.MaxTerminalWidth != 1200 {
+		t.Fatalf("wide-narrow = %+v, want override 55 and inherited max width 1200", narrow)
+	}
+	if res.LayoutBases["wide-narrow"] != "master-stack" {
+		t.Fatalf("wide-narrow base = %q, want master-stack", res.LayoutBases["wide-narrow"])
+	}
+
+	tall := res.Config.Layouts["grid-tall"]
+	if tall.Mode != res.Config.Layouts["grid"].Mode || tall.MaxTerminalHeight != 900 {
+		t.Fatalf("grid-tall = %+v, want builtin grid with max height 900", tall)
+	}
+}
+
+func TestLoadFromPath_LayoutExtendsRejected(t *testing.T) {
+	cases := []struct {
+		name     string
+		data     string
+		wantPath string
+		wantErr  string
+	}{
+		{
+			name: "cycle",
+			data: `
+layouts:
+  a:
+    extends: b
+  b:
+    extends: c
+  c:
+    extends: a
+`,
+			wantPath: "layouts.a.extends",
+			wantErr:  "a -> b -> c -> a",
+		},
+		{
+			name: "self",
+			data: `
+layouts:
+  a:
+    extends: a
+`,
+			wantPath: "layouts.a.extends",
+			wantErr:  "a -> a",
+		},
+		{
+			name: "unknown base",
+			data: `
+layouts:
+  a:
+    extends: missing
+`,
+			wantPath: "layouts.a.extends",
+			wantErr:  "unknown layout",
+		},
+		{
+			name: "extends with inherits",
+			data: `
+layouts:
+  a:
+    extends: grid
+    inherits: "builtin:columns"
+`,
+			wantPath: "layouts.a.extends",
+			wantErr:  "not both",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(strings.TrimSpace(tc.data)+"\n"), 0644); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			_, err := LoadFromPath(path)
+			var vErr *ValidationError
+			if !errors.As(err, &vErr) {
+				t.Fatalf("err = %v, want ValidationError", err)
+			}
+			if vErr.Path != tc.wantPath || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("err = %v (path %q), want %q at %q", err, vErr.Path, tc.wantErr, tc.wantPath)
+			}
+		})
+	}
+}
+
+func TestLoadFromPath_AgentModeMaxInlineTaskBytes(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("# empty\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := res.Config.AgentMode.GetMaxInlineTaskBytes(); got != DefaultMaxInlineTaskBytes {
+		t.Fatalf("default max_inline_task_bytes = %d, want %d", got, DefaultMaxInlineTaskBytes)
+	}
+
+	if err := os.WriteFile(path, []byte("agent_mode:\n  max_inline_task_bytes: 4096\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err = LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := res.Config.AgentMode.GetMaxInlineTaskBytes(); got != 4096 {
+		t.Fatalf("max_inline_task_bytes = %d, want 4096", got)
+	}
+
+	if err := os.WriteFile(path, []byte("agent_mode:\n  max_inline_task_bytes: -1\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err = LoadFromPath(path)
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.Path != "agent_mode.max_inline_task_bytes" {
+		t.Fatalf("err = %v, want agent_mode.max_inline_task_bytes validation error", err)
+	}
+}
+
+func TestLoadFromPath_TerminalSpawnEnv(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := "terminal_spawn_env:\n  kitty:\n    GDK_BACKEND: x11\n    KITTY_DISABLE_WAYLAND: \"1\"\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := res.Config.SpawnEnv("Kitty")["GDK_BACKEND"]; got != "x11" {
+		t.Fatalf("SpawnEnv(Kitty)[GDK_BACKEND] = %q, want x11", got)
+	}
+	want := []string{"GDK_BACKEND=x11", "KITTY_DISABLE_WAYLAND=1"}
+	if got := res.Config.SpawnEnvList("kitty"); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("SpawnEnvList = %v, want %v", got, want)
+	}
+	if got := res.Config.SpawnEnvList("alacritty"); got != nil {
+		t.Fatalf("SpawnEnvList(alacritty) = %v, want nil", got)
+	}
+
+	if err := os.WriteFile(path, []byte("terminal_spawn_env:\n  kitty:\n    \"A=B\": x\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err = LoadFromPath(path)
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.Path != "terminal_spawn_env.kitty" {
+		t.Fatalf("err = %v, want terminal_spawn_env.kitty validation error", err)
+	}
+}
+
+func TestBuildEffectiveConfig_CopiesTerminalSpawnEnv(t *testing.T) {
+	raw := RawConfig{TerminalSpawnEnv: map[string]map[string]string{"kitty": {"GDK_BACKEND": "x11"}}}
+	cfg, _, err := BuildEffectiveConfig(raw)
+	if err != nil {
+		t.Fatalf("BuildEffectiveConfig: %v", err)
+	}
+	raw.TerminalSpawnEnv["kitty"]["GDK_BACKEND"] = "wayland"
+	if got := cfg.SpawnEnv("kitty")["GDK_BACKEND"]; got != "x11" {
+		t.Fatalf("SpawnEnv(kitty)[GDK_BACKEND] = %q after editing the raw config, want x11", got)
+	}
+}
+
+func TestLoadFromPath_AgentModeWindowSpawnWait(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("# empty\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	am := res.Config.AgentMode
+	if am.GetWindowSpawnTimeout() != 15*time.Second || am.GetWindowSettle() != 500*time.Millisecond {
+		t.Fatalf("defaults = %s/%s", am.GetWindowSpawnTimeout(), am.GetWindowSettle())
+	}
+
+	data := "agent_mode:\n  window_spawn_timeout_s: 40\n  window_settle_ms: 1200\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err = LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	am = res.Config.AgentMode
+	if am.GetWindowSpawnTimeout() != 40*time.Second || am.GetWindowSettle() != 1200*time.Millisecond {
+		t.Fatalf("configured = %s/%s", am.GetWindowSpawnTimeout(), am.GetWindowSettle())
+	}
+	if val, _, err := Explain(res, "agent_mode.window_settle_ms"); err != nil || val != 1200 {
+		t.Fatalf("explain window_settle_ms = %#v, %v", val, err)
+	}
+
+	if err := os.WriteFile(path, []byte("agent_mode:\n  window_spawn_timeout_s: -1\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err = LoadFromPath(path)
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.Path != "agent_mode.window_spawn_timeout_s" {
+		t.Fatalf("err = %v, want agent_mode.window_spawn_timeout_s validation error", err)
+	}
+}
+
+func TestLoadFromPath_AgentModeTmuxSessionOptions(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := "agent_mode:\n  tmux_session_options:\n    history-limit: \"50000\"\n    mouse: \"on\"\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	res, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	opts := res.Config.AgentMode.GetTmuxSessionOptions()
+	if len(opts) != 2 || opts["history-limit"] != "50000" || opts["mouse"] != "on" {
+		t.Fatalf("tmux_session_options = %v", opts)
+	}
+
+	if err := os.WriteFile(path, []byte("agent_mode:\n  tmux_session_options:\n    \"bad name\": x\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err = LoadFromPath(path)
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.Path != "agent_mode.tmux_session_options" {
+		t.Fatalf("err = %v, want tmux_session_options validation error", err)
+	}
+}
@@ -70,8 +70,18 @@ func cloneAgentConfig(cfg AgentConfig) AgentConfig {
 			out.Env[k] = v
 		}
 	}
+	if cfg.IdlePatterns != nil {
+		out.IdlePatterns = append([]string(nil), cfg.IdlePatterns...)
+	}
 	if cfg.Models != nil {
 		out.Models = append([]string(nil), cfg.Models...)
 	}
+	if cfg.PostSpawnKeys != nil {
+		out.PostSpawnKeys = append([]string(nil), cfg.PostSpawnKeys...)
+	}
+	if cfg.ClearInputBeforeSend != nil {
+		clear := *cfg.ClearInputBeforeSend
+		out.ClearInputBeforeSend = &clear
+	}
 	return out
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 )
@@ -44,9 +45,18 @@ func BuildEffectiveConfig(raw RawConfig) (*Config, map[string]string, error) {
 	if raw.UndoHotkey != nil {
 		cfg.UndoHotkey = *raw.UndoHotkey
 	}
+	if raw.MonocleHotkey != nil {
+		cfg.MonocleHotkey = *raw.MonocleHotkey
+	}
+	if raw.MoveModeHotkey != nil {
+		cfg.MoveModeHotkey = *raw.MoveModeHotkey
+	}
 	if raw.TerminalAddHotkey != nil {
 		cfg.TerminalAddHotkey = *raw.TerminalAddHotkey
 	}
+	if raw.MoveModeTimeout != nil {
+		cfg.MoveModeTimeout = *raw.MoveModeTimeout
+	}
 	if raw.PaletteHotkey != nil {
 		cfg.PaletteHotkey = *raw.PaletteHotkey
 	}
@@ -56,6 +66,39 @@ func BuildEffectiveConfig(raw RawConfig) (*Config, map[string]string, error) {
 	if raw.PaletteFuzzyMatching != nil {
 		cfg.PaletteFuzzyMatching = *raw.PaletteFuzzyMatching
 	}
+	if raw.MoveModeRestoreFocus != nil {
+		cfg.MoveModeRestoreFocus = *raw.MoveModeRestoreFocus
+	}
+	if raw.MoveModeAllWindows != nil {
+		cfg.MoveModeAllWindows = *raw.MoveModeAllWindows
+	}
+	if raw.MoveModeAnnounce != nil {
+		cfg.MoveModeAnnounce = *raw.MoveModeAnnounce
+	}
+	if raw.MoveModeAnnounceCommand != nil {
+		cfg.MoveModeAnnounceCommand = strings.TrimSpace(*raw.MoveModeAnnounceCommand)
+	}
+	if raw.MoveMode != nil && raw.MoveMode.Hints != nil {
+		hints := raw.MoveMode.Hints
+		if hints.Position != nil {
+			cfg.MoveMode.Hints.Position = strings.ToLower(strings.TrimSpace(*hints.Position))
+		}
+		if hints.Selecting != nil {
+			cfg.MoveMode.Hints.Selecting = append([]string(nil), hints.Selecting...)
+		}
+		if hints.Move != nil {
+			cfg.MoveMode.Hints.Move = append([]string(nil), hints.Move...)
+		}
+		if hints.ConfirmDelete != nil {
+			cfg.MoveMode.Hints.ConfirmDelete = append([]string(nil), hints.ConfirmDelete...)
+		}
+	}
+	if raw.MoveMode != nil && raw.MoveMode.Keys != nil {
+		cfg.MoveMode.Keys = make(map[string]string, len(raw.MoveMode.Keys))
+		for action, key := range raw.MoveMode.Keys {
+			cfg.MoveMode.Keys[action] = key
+		}
+	}
 	if raw.Display != nil {
 		cfg.Display = *raw.Display
 	}
@@ -65,6 +108,14 @@ func BuildEffectiveConfig(raw RawConfig) (*Config, map[string]string, error) {
 	if raw.PreferredTerminal != nil {
 		cfg.PreferredTerminal = *raw.PreferredTerminal
 	}
+	if raw.LayoutHotkeys != nil {
+		if cfg.LayoutHotkeys == nil {
+			cfg.LayoutHotkeys = make(map[string]string, len(raw.LayoutHotkeys))
+		}
+		for layout, hotkey := range raw.LayoutHotkeys {
+			cfg.LayoutHotkeys[layout] = hotkey
+		}
+	}
 	if raw.TerminalSpawnCommands != nil {
 		if cfg.TerminalSpawnCommands == nil {
 			cfg.TerminalSpawnCommands = make(map[string]string, len(raw.TerminalSpawnCommands))
@@ -73,9 +124,23 @@ func BuildEffectiveConfig(raw RawConfig) (*Config, map[string]string, error) {
 			cfg.TerminalSpawnCommands[class] = cmd
 		}
 	}
+	if raw.TerminalSpawnEnv != nil {
+		if cfg.TerminalSpawnEnv == nil {
+			cfg.TerminalSpawnEnv = make(map[string]map[string]string, len(raw.TerminalSpawnEnv))
+		}
+		for class, env := range raw.TerminalSpawnEnv {
+			cfg.TerminalSpawnEnv[class] = mergeStringMap(nil, env)
+		}
+	}
 	if raw.GapSize != nil {
 		cfg.GapSize = *raw.GapSize
 	}
+	if raw.MinTileWidth != nil {
+		cfg.MinTileWidth = *raw.MinTileWidth
+	}
+	if raw.MinTileHeight != nil {
+		cfg.MinTileHeight = *raw.MinTileHeight
+	}
 	if raw.ScreenPadding != nil {
 		if raw.ScreenPadding.Top != nil {
 			cfg.ScreenPadding.Top = *raw.ScreenPadding.Top
@@ -90,12 +155,42 @@ func BuildEffectiveConfig(raw RawConfig) (*Config, map[string]string, error) {
 			cfg.ScreenPadding.Right = *raw.ScreenPadding.Right
 		}
 	}
+	if raw.MonitorPadding != nil {
+		// Sides a monitor entry leaves unset keep the global screen_padding.
+		cfg.MonitorPadding = make(map[string]Margins, len(raw.MonitorPadding))
+		for monitor, padding := range raw.MonitorPadding {
+			cfg.MonitorPadding[monitor] = Margins{
+				Top:    derefInt(padding.Top, cfg.ScreenPadding.Top),
+				Bottom: derefInt(padding.Bottom, cfg.ScreenPadding.Bottom),
+				Left:   derefInt(padding.Left, cfg.ScreenPadding.Left),
+				Right:  derefInt(padding.Right, cfg.ScreenPadding.Right),
+			}
+		}
+	}
 	if raw.TerminalClasses != nil {
 		cfg.TerminalClasses = raw.TerminalClasses
 	}
 	if raw.TerminalSort != nil {
 		cfg.TerminalSort = *raw.TerminalSort
 	}
+	if raw.WorkspaceHistoryDepth != nil {
+		cfg.WorkspaceHistoryDepth = *raw.WorkspaceHistoryDepth
+	}
+	if raw.AutosavePrevious != nil {
+		cfg.AutosavePrevious = *raw.AutosavePrevious
+	}
+	if raw.RetileTarget != nil {
+		cfg.RetileTarget = *raw.RetileTarget
+	}
+	if raw.FocusAfterTile != nil {
+		cfg.FocusAfterTile = *raw.FocusAfterTile
+	}
+	if raw.TileIgnoresNonTerminals != nil {
+		cfg.TileIgnoresNonTerminals = *raw.TileIgnoresNonTerminals
+	}
+	if raw.PersistActiveLayout != nil {
+		cfg.PersistActiveLayout = *raw.PersistActiveLayout
+	}
 	if raw.LogLevel != nil {
 		cfg.LogLevel = *raw.LogLevel
 	}
@@ -133,6 +228,18 @@ func BuildEffectiveConfig(raw RawConfig) (*Config, map[string]string, error) {
 	}
 	applyLimitDefaults(&cfg.Limits)
 
+	if raw.Reconciler != nil {
+		if raw.Reconciler.IntervalSeconds != nil {
+			cfg.Reconciler.IntervalSeconds = *raw.Reconciler.IntervalSeconds
+		}
+		if raw.Reconciler.RunOnStart != nil {
+			cfg.Reconciler.RunOnStart = raw.Reconciler.RunOnStart
+		}
+		if raw.Reconciler.OrphanGraceSeconds != nil {
+			cfg.Reconciler.OrphanGraceSeconds = *raw.Reconciler.OrphanGraceSeconds
+		}
+	}
+
 	if raw.Logging != nil {
 		if raw.Logging.Enabled != nil {
 			cfg.Logging.Enabled = *raw.Logging.Enabled
@@ -155,12 +262,57 @@ func BuildEffectiveConfig(raw RawConfig) (*Config, map[string]string, error) {
 		if raw.Logging.PreviewLength != nil {
 			cfg.Logging.PreviewLength = *raw.Logging.PreviewLength
 		}
+		if raw.Logging.MaxContentBytes != nil {
+			cfg.Logging.MaxContentBytes = *raw.Logging.MaxContentBytes
+		}
 	}
 
 	if raw.AgentMode != nil {
+		if raw.AgentMode.Multiplexer != nil {
+			cfg.AgentMode.Multiplexer = *raw.AgentMode.Multiplexer
+		}
+		if raw.AgentMode.ManageMultiplexerConfig != nil {
+			cfg.AgentMode.ManageMultiplexerConfig = raw.AgentMode.ManageMultiplexerConfig
+		}
 		if raw.AgentMode.ProtectSlotZero != nil {
 			cfg.AgentMode.ProtectSlotZero = raw.AgentMode.ProtectSlotZero
 		}
+		if raw.AgentMode.IdlePollMs != nil {
+			cfg.AgentMode.IdlePollMs = *raw.AgentMode.IdlePollMs
+		}
+		if raw.AgentMode.DefaultIdleTimeoutS != nil {
+			cfg.AgentMode.DefaultIdleTimeoutS = *raw.AgentMode.DefaultIdleTimeoutS
+		}
+		if raw.AgentMode.DefaultDepTimeoutS != nil {
+			cfg.AgentMode.DefaultDepTimeoutS = *raw.AgentMode.DefaultDepTimeoutS
+		}
+		if raw.AgentMode.WindowSpawnTimeoutS != nil {
+			cfg.AgentMode.WindowSpawnTimeoutS = *raw.AgentMode.WindowSpawnTimeoutS
+		}
+		if raw.AgentMode.WindowSettleMs != nil {
+			cfg.AgentMode.WindowSettleMs = *raw.AgentMode.WindowSettleMs
+		}
+		if raw.AgentMode.ReuseDisplayConnection != nil {
+			cfg.AgentMode.ReuseDisplayConnection = raw.AgentMode.ReuseDisplayConnection
+		}
+		if raw.AgentMode.CaptureEscapes != nil {
+			cfg.AgentMode.CaptureEscapes = raw.AgentMode.CaptureEscapes
+		}
+		if raw.AgentMode.MaxConcurrentSpawns != nil {
+			cfg.AgentMode.MaxConcurrentSpawns = *raw.AgentMode.MaxConcurrentSpawns
+		}
+		if raw.AgentMode.MaxInlineTaskBytes != nil {
+			cfg.AgentMode.MaxInlineTaskBytes = *raw.AgentMode.MaxInlineTaskBytes
+		}
+		if raw.AgentMode.OnCompleteCommand != nil {
+			cfg.AgentMode.OnCompleteCommand = *raw.AgentMode.OnCompleteCommand
+		}
+		if raw.AgentMode.TmuxSessionOptions != nil {
+			cfg.AgentMode.TmuxSessionOptions = make(map[string]string, len(raw.AgentMode.TmuxSessionOptions))
+			for name, value := range raw.AgentMode.TmuxSessionOptions {
+				cfg.AgentMode.TmuxSessionOptions[name] = value
+			}
+		}
 	}
 
 	if raw.Agents != nil {
@@ -169,11 +321,13 @@ func BuildEffectiveConfig(raw RawConfig) (*Config, map[string]string, error) {
 		}
 		for name, rawAgentCfg := range raw.Agents {
 			agentCfg := AgentConfig{
-				Command:       rawAgentCfg.Command,
-				Args:          rawAgentCfg.Args,
-				ReadyPattern:  rawAgentCfg.ReadyPattern,
-				IdlePattern:   rawAgentCfg.IdlePattern,
-				OutputMode:    rawAgentCfg.OutputMode,
+				Command:        rawAgentCfg.Command,
+				Args:           rawAgentCfg.Args,
+				ReadyPattern:   rawAgentCfg.ReadyPattern,
+				IdlePattern:    rawAgentCfg.IdlePattern,
+				IdlePatterns:   rawAgentCfg.IdlePatterns,
+				IdleLineMaxLen: rawAgentCfg.IdleLineMaxLen,
+				OutputMode:     rawAgentCfg.OutputMode,
 				Hooks: AgentHooks{
 					OnStart: rawAgentCfg.Hooks.OnStart,
 					OnCheck: rawAgentCfg.Hooks.OnCheck,
@@ -189,6 +343,9 @@ func BuildEffectiveConfig(raw RawConfig) (*Config, map[string]string, error) {
 				Models:        rawAgentCfg.Models,
 				DefaultModel:  rawAgentCfg.DefaultModel,
 				ModelFlag:     rawAgentCfg.ModelFlag,
+				PostSpawnKeys: rawAgentCfg.PostSpawnKeys,
+
+				ClearInputBeforeSend: rawAgentCfg.ClearInputBeforeSend,
 
 				HookDelivery:     rawAgentCfg.HookDelivery,
 				HookSettingsFlag: rawAgentCfg.HookSettingsFlag,
@@ -203,8 +360,12 @@ func BuildEffectiveConfig(raw RawConfig) (*Config, map[string]string, error) {
 			}
 			if base, ok := cfg.Agents[name]; ok {
 				// Merge: carry forward default fields the user didn't set.
-				if agentCfg.IdlePattern == "" {
+				if agentCfg.IdlePattern == "" && len(agentCfg.IdlePatterns) == 0 {
 					agentCfg.IdlePattern = base.IdlePattern
+					agentCfg.IdlePatterns = base.IdlePatterns
+				}
+				if agentCfg.IdleLineMaxLen == 0 {
+					agentCfg.IdleLineMaxLen = base.IdleLineMaxLen
 				}
 				if agentCfg.OutputMode == "" {
 					agentCfg.OutputMode = base.OutputMode
@@ -236,6 +397,12 @@ func BuildEffectiveConfig(raw RawConfig) (*Config, map[string]string, error) {
 				if agentCfg.ModelFlag == "" {
 					agentCfg.ModelFlag = base.ModelFlag
 				}
+				if len(agentCfg.PostSpawnKeys) == 0 {
+					agentCfg.PostSpawnKeys = base.PostSpawnKeys
+				}
+				if agentCfg.ClearInputBeforeSend == nil {
+					agentCfg.ClearInputBeforeSend = base.ClearInputBeforeSend
+				}
 				if agentCfg.HookDelivery == "" {
 					agentCfg.HookDelivery = base.HookDelivery
 				}
@@ -599,14 +766,12 @@ func validateProjectAgentSpawnMode(path string, mode string) error {
 	if strings.TrimSpace(mode) == "" {
 		return nil
 	}
-	switch mode {
-	case "pane", "window":
+	if slices.Contains(spawnModes, mode) {
 		return nil
-	default:
-		return &ValidationError{
-			Path: path,
-			Err:  fmt.Errorf("spawn_mode must be one of: pane, window"),
-		}
+	}
+	return &ValidationError{
+		Path: path,
+		Err:  fmt.Errorf("spawn_mode must be one of: %s", strings.Join(spawnModes, ", ")),
 	}
 }
 
@@ -638,23 +803,77 @@ func applyLayouts(cfg *Config, raw RawConfig) (map[string]string, error) {
 		layoutBases[name] = name
 	}
 
-	// Apply user layout patches.
-	for name, patch := range raw.Layouts {
-		baseName, baseLayout, err := selectLayoutBase(name, patch, builtin)
-		if err != nil {
-			return nil, err
+	// Apply user layout patches. A layout that extends another user layout
+	// is resolved after its base; chain holds the layouts being resolved so
+	// a cycle is reported instead of recursing forever.
+	resolved := make(map[string]bool, len(raw.Layouts))
+	var chain []string
+	var resolve func(name string) error
+	resolve = func(name string) error {
+		if resolved[name] {
+			return nil
+		}
+		for i, pending := range chain {
+			if pending == name {
+				cycle := append(append([]string(nil), chain[i:]...), name)
+				return &ValidationError{
+					Path: "layouts." + name + ".extends",
+					Err:  fmt.Errorf("extends cycle: %s", strings.Join(cycle, " -> ")),
+				}
+			}
+		}
+
+		patch := raw.Layouts[name]
+		ref := ""
+		if patch.Extends != nil {
+			ref = strings.TrimSpace(*patch.Extends)
+		}
+		var baseName string
+		var baseLayout Layout
+		if ref != "" {
+			path := "layouts." + name + ".extends"
+			if patch.Inherits != nil && strings.TrimSpace(*patch.Inherits) != "" {
+				return &ValidationError{Path: path, Err: fmt.Errorf("set either extends or inherits, not both")}
+			}
+			if _, ok := raw.Layouts[ref]; ok {
+				chain = append(chain, name)
+				err := resolve(ref)
+				chain = chain[:len(chain)-1]
+				if err != nil {
+					return err
+				}
+			} else if _, ok := builtin[ref]; !ok {
+				return &ValidationError{Path: path, Err: fmt.Errorf("unknown layout %q", ref)}
+			}
+			baseName = layoutBases[ref]
+			baseLayout = cfg.Layouts[ref]
+			// Aliases name a single layout and are never inherited.
+			baseLayout.Aliases = nil
+		} else {
+			var err error
+			baseName, baseLayout, err = selectLayoutBase(name, patch, builtin)
+			if err != nil {
+				return err
+			}
 		}
 
 		merged, err := mergeLayoutPatch(baseLayout, patch)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		if err := validateLayout(&merged); err != nil {
-			return nil, &ValidationError{Path: "layouts." + name, Err: err}
+			return &ValidationError{Path: "layouts." + name, Err: err}
 		}
 
 		cfg.Layouts[name] = merged
 		layoutBases[name] = baseName
+		resolved[name] = true
+		return nil
+	}
+	for _, name := range sortedKeys(raw.Layouts) {
+		if err := resolve(name); err != nil {
+			return nil, err
+		}
 	}
 
 	// Ensure deterministic iteration for callers that sort keys.
@@ -754,6 +973,9 @@ func mergeLayoutPatch(base Layout, patch RawLayout) (Layout, error) {
 			out.MasterStack.MaxStackCols = *patch.MasterStack.MaxStackCols
 		}
 	}
+	if patch.Aliases != nil {
+		out.Aliases = append([]string(nil), patch.Aliases...)
+	}
 	if patch.MaxTerminalWidth != nil {
 		out.MaxTerminalWidth = *patch.MaxTerminalWidth
 	}
@@ -763,6 +985,52 @@ func mergeLayoutPatch(base Layout, patch RawLayout) (Layout, error) {
 	if patch.FlexibleLastRow != nil {
 		out.FlexibleLastRow = *patch.FlexibleLastRow
 	}
+	if patch.SingleWindowNoGap != nil {
+		out.SingleWindowNoGap = *patch.SingleWindowNoGap
+	}
+	if patch.GapSize != nil {
+		gap := *patch.GapSize
+		out.GapSize = &gap
+	}
+	if patch.Order != nil {
+		out.Order = *patch.Order
+	}
+	if patch.RemainderBias != nil {
+		out.RemainderBias = *patch.RemainderBias
+	}
+	if patch.ReservedRegion != nil {
+		var region TileRegion
+		if out.ReservedRegion != nil {
+			region = *out.ReservedRegion
+		}
+		if patch.ReservedRegion.Type != nil {
+			region.Type = *patch.ReservedRegion.Type
+		}
+		if patch.ReservedRegion.XPercent != nil {
+			region.XPercent = *patch.ReservedRegion.XPercent
+		}
+		if patch.ReservedRegion.YPercent != nil {
+			region.YPercent = *patch.ReservedRegion.YPercent
+		}
+		if patch.ReservedRegion.WidthPercent != nil {
+			region.WidthPercent = *patch.ReservedRegion.WidthPercent
+		}
+		if patch.ReservedRegion.HeightPercent != nil {
+			region.HeightPercent = *patch.ReservedRegion.HeightPercent
+		}
+		if region.Type == RegionCustom {
+			if region.WidthPercent == 0 {
+				region.WidthPercent = 100
+			}
+			if region.HeightPercent == 0 {
+				region.HeightPercent = 100
+			}
+		}
+		out.ReservedRegion = &region
+	}
+	if patch.ReservedSlot != nil {
+		out.ReservedSlot = *patch.ReservedSlot
+	}
 
 	return out, nil
 }
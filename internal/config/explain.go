@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 // Explain returns the effective value at the given YAML-like path and its source.
@@ -12,6 +13,8 @@ import (
 //	hotkey
 //	terminal_add_hotkey
 //	palette_hotkey
+//	layout_hotkeys
+//	layout_hotkeys.<layout>
 //	palette_backend
 //	display
 //	xauthority
@@ -21,16 +24,56 @@ import (
 //	limits.max_workspaces
 //	limits.max_terminals_total
 //	terminal_spawn_commands
+//	terminal_spawn_env
 //	gap_size
+//	min_tile_width
+//	min_tile_height
 //	screen_padding.top
 //	default_layout
+//	persist_active_layout
 //	terminal_classes
 //	terminal_sort
+//	workspace_history_depth
+//	autosave_previous
+//	retile_target
+//	focus_after_tile
+//	tile_ignores_non_terminals
+//	move_mode_restore_focus
+//	move_mode_all_windows
+//	move_mode_announce
+//	move_mode_announce_command
+//	move_mode.hints.position
+//	move_mode.hints.selecting
+//	move_mode.hints.move
+//	move_mode.hints.confirm_delete
+//	move_mode.keys
 //	log_level
+//	agent_mode.multiplexer
+//	agent_mode.manage_multiplexer_config
+//	agent_mode.protect_slot_zero
+//	agent_mode.idle_poll_ms
+//	agent_mode.default_idle_timeout_s
+//	agent_mode.default_dep_timeout_s
+//	agent_mode.window_spawn_timeout_s
+//	agent_mode.window_settle_ms
+//	agent_mode.reuse_display_connection
+//	agent_mode.capture_escapes
+//	agent_mode.max_concurrent_spawns
+//	agent_mode.max_inline_task_bytes
+//	agent_mode.on_complete_command
+//	agent_mode.tmux_session_options
+//	reconciler.interval_seconds
+//	reconciler.run_on_start
+//	reconciler.orphan_grace_seconds
 //	terminal_margins.<WM_CLASS>.top
+//	monitor_padding.<monitor>.top
 //	layouts.<name>.mode
 //	layouts.<name>.tile_region.type
 //	layouts.<name>.fixed_grid.rows
+//	layouts.<name>.order
+//	layouts.<name>.reserved_slot
+//	layouts.<name>.gap_size
+//	layouts.<name>.aliases
 func Explain(res *LoadResult, path string) (any, Source, error) {
 	if res == nil || res.Config == nil {
 		return nil, Source{}, fmt.Errorf("no config loaded")
@@ -96,6 +139,11 @@ func lookupValue(cfg *Config, path string) (any, error) {
 			return nil, fmt.Errorf("unknown path: %s", path)
 		}
 		return cfg.UndoHotkey, nil
+	case "monocle_hotkey":
+		if len(parts) != 1 {
+			return nil, fmt.Errorf("unknown path: %s", path)
+		}
+		return cfg.MonocleHotkey, nil
 	case "terminal_add_hotkey":
 		if len(parts) != 1 {
 			return nil, fmt.Errorf("unknown path: %s", path)
@@ -106,6 +154,18 @@ func lookupValue(cfg *Config, path string) (any, error) {
 			return nil, fmt.Errorf("unknown path: %s", path)
 		}
 		return cfg.PaletteHotkey, nil
+	case "layout_hotkeys":
+		if len(parts) == 1 {
+			return cfg.LayoutHotkeys, nil
+		}
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("unknown path: %s", path)
+		}
+		hotkey, ok := cfg.LayoutHotkeys[parts[1]]
+		if !ok {
+			return nil, fmt.Errorf("unknown layout_hotkeys entry %q", parts[1])
+		}
+		return hotkey, nil
 	case "palette_backend":
 		if len(parts) != 1 {
 			return nil, fmt.Errorf("unknown path: %s", path)
@@ -144,11 +204,43 @@ func lookupValue(cfg *Config, path string) (any, error) {
 			return nil, fmt.Errorf("unknown terminal_spawn_commands entry %q", class)
 		}
 		return cmd, nil
+	case "terminal_spawn_env":
+		if len(parts) == 1 {
+			return cfg.TerminalSpawnEnv, nil
+		}
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("unknown path: %s", path)
+		}
+		env, ok := cfg.TerminalSpawnEnv[parts[1]]
+		if !ok {
+			return nil, fmt.Errorf("unknown terminal_spawn_env entry %q", parts[1])
+		}
+		return env, nil
 	case "gap_size":
 		if len(parts) != 1 {
 			return nil, fmt.Errorf("unknown path: %s", path)
 		}
 		return cfg.GapSize, nil
+	case "min_tile_width":
+		if len(parts) != 1 {
+			return nil, fmt.Errorf("unknown path: %s", path)
+		}
+		return cfg.MinTileWidth, nil
+	case "min_tile_height":
+		if len(parts) != 1 {
+			return nil, fmt.Errorf("unknown path: %s", path)
+		}
+		return cfg.MinTileHeight, nil
+	case "workspace_history_depth":
+		if len(parts) != 1 {
+			return nil, fmt.Errorf("unknown path: %s", path)
+		}
+		return cfg.WorkspaceHistoryDepth, nil
+	case "autosave_previous":
+		if len(parts) != 1 {
+			return nil, fmt.Errorf("unknown path: %s", path)
+		}
+		return cfg.AutosavePrevious, nil
 	case "screen_padding":
 		if len(parts) == 1 {
 			return cfg.ScreenPadding, nil
@@ -173,6 +265,11 @@ func lookupValue(cfg *Config, path string) (any, error) {
 			return nil, fmt.Errorf("unknown path: %s", path)
 		}
 		return cfg.DefaultLayout, nil
+	case "persist_active_layout":
+		if len(parts) != 1 {
+			return nil, fmt.Errorf("unknown path: %s", path)
+		}
+		return cfg.PersistActiveLayout, nil
 	case "terminal_classes":
 		if len(parts) != 1 {
 			return nil, fmt.Errorf("unknown path: %s", path)
@@ -183,11 +280,117 @@ func lookupValue(cfg *Config, path string) (any, error) {
 			return nil, fmt.Errorf("unknown path: %s", path)
 		}
 		return cfg.TerminalSort, nil
+	case "retile_target":
+		if len(parts) != 1 {
+			return nil, fmt.Errorf("unknown path: %s", path)
+		}
+		return cfg.RetileTarget, nil
+	case "move_mode_restore_focus":
+		if len(parts) != 1 {
+			return nil, fmt.Errorf("unknown path: %s", path)
+		}
+		return cfg.MoveModeRestoreFocus, nil
+	case "move_mode_all_windows":
+		if len(parts) != 1 {
+			return nil, fmt.Errorf("unknown path: %s", path)
+		}
+		return cfg.MoveModeAllWindows, nil
+	case "move_mode_announce":
+		if len(parts) != 1 {
+			return nil, fmt.Errorf("unknown path: %s", path)
+		}
+		return cfg.MoveModeAnnounce, nil
+	case "move_mode_announce_command":
+		if len(parts) != 1 {
+			return nil, fmt.Errorf("unknown path: %s", path)
+		}
+		return cfg.MoveModeAnnounceCommand, nil
+	case "move_mode":
+		if len(parts) == 1 {
+			return cfg.MoveMode, nil
+		}
+		if parts[1] == "keys" {
+			switch len(parts) {
+			case 2:
+				return cfg.MoveMode.ActionKeys(), nil
+			case 3:
+				if key, ok := cfg.MoveMode.ActionKeys()[parts[2]]; ok {
+					return key, nil
+				}
+			}
+			return nil, fmt.Errorf("unknown path: %s", path)
+		}
+		if parts[1] != "hints" {
+			return nil, fmt.Errorf("unknown path: %s", path)
+		}
+		if len(parts) == 2 {
+			return cfg.MoveMode.Hints, nil
+		}
+		if len(parts) == 3 {
+			switch parts[2] {
+			case "position":
+				return cfg.MoveMode.Hints.Position, nil
+			case "selecting":
+				return cfg.MoveMode.Hints.Selecting, nil
+			case "move":
+				return cfg.MoveMode.Hints.Move, nil
+			case "confirm_delete":
+				return cfg.MoveMode.Hints.ConfirmDelete, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown path: %s", path)
+	case "focus_after_tile":
+		if len(parts) != 1 {
+			return nil, fmt.Errorf("unknown path: %s", path)
+		}
+		return cfg.FocusAfterTile, nil
+	case "tile_ignores_non_terminals":
+		if len(parts) != 1 {
+			return nil, fmt.Errorf("unknown path: %s", path)
+		}
+		return cfg.TileIgnoresNonTerminals, nil
 	case "log_level":
 		if len(parts) != 1 {
 			return nil, fmt.Errorf("unknown path: %s", path)
 		}
 		return cfg.LogLevel, nil
+	case "agent_mode":
+		if len(parts) == 1 {
+			return cfg.AgentMode, nil
+		}
+		if len(parts) == 2 {
+			switch parts[1] {
+			case "multiplexer":
+				return cfg.AgentMode.Multiplexer, nil
+			case "manage_multiplexer_config":
+				return cfg.AgentMode.GetManageMultiplexerConfig(), nil
+			case "protect_slot_zero":
+				return cfg.AgentMode.GetProtectSlotZero(), nil
+			case "idle_poll_ms":
+				return int(cfg.AgentMode.GetIdlePollInterval() / time.Millisecond), nil
+			case "default_idle_timeout_s":
+				return int(cfg.AgentMode.GetDefaultIdleTimeout() / time.Second), nil
+			case "default_dep_timeout_s":
+				return int(cfg.AgentMode.GetDefaultDepTimeout() / time.Second), nil
+			case "window_spawn_timeout_s":
+				return int(cfg.AgentMode.GetWindowSpawnTimeout() / time.Second), nil
+			case "window_settle_ms":
+				return int(cfg.AgentMode.GetWindowSettle() / time.Millisecond), nil
+			case "reuse_display_connection":
+				return cfg.AgentMode.GetReuseDisplayConnection(), nil
+			case "capture_escapes":
+				return cfg.AgentMode.GetCaptureEscapes(), nil
+			case "max_concurrent_spawns":
+				return cfg.AgentMode.GetMaxConcurrentSpawns(), nil
+			case "max_inline_task_bytes":
+				return cfg.AgentMode.GetMaxInlineTaskBytes(), nil
+			case "on_complete_command":
+				return cfg.AgentMode.OnCompleteCommand, nil
+			case "tmux_session_options":
+				return cfg.AgentMode.TmuxSessionOptions, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown path: %s", path)
 	case "limits":
 		if len(parts) == 1 {
 			return cfg.Limits, nil
@@ -220,6 +423,46 @@ func lookupValue(cfg *Config, path string) (any, error) {
 			}
 		}
 		return nil, fmt.Errorf("unknown path: %s", path)
+	case "reconciler":
+		if len(parts) == 1 {
+			return cfg.Reconciler, nil
+		}
+		if len(parts) == 2 {
+			switch parts[1] {
+			case "interval_seconds":
+				return int(cfg.Reconciler.GetInterval() / time.Second), nil
+			case "run_on_start":
+				return cfg.Reconciler.GetRunOnStart(), nil
+			case "orphan_grace_seconds":
+				return int(cfg.Reconciler.GetOrphanGrace() / time.Second), nil
+			}
+		}
+		return nil, fmt.Errorf("unknown path: %s", path)
+	case "monitor_padding":
+		if len(parts) == 1 {
+			return cfg.MonitorPadding, nil
+		}
+		padding, ok := cfg.MonitorPadding[parts[1]]
+		if !ok {
+			return nil, fmt.Errorf("unknown monitor_padding entry %q", parts[1])
+		}
+		if len(parts) == 2 {
+			return padding, nil
+		}
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("unknown path: %s", path)
+		}
+		switch parts[2] {
+		case "top":
+			return padding.Top, nil
+		case "bottom":
+			return padding.Bottom, nil
+		case "left":
+			return padding.Left, nil
+		case "right":
+			return padding.Right, nil
+		}
+		return nil, fmt.Errorf("unknown path: %s", path)
 	case "terminal_margins":
 		if len(parts) == 1 {
 			return cfg.TerminalMargins, nil
@@ -307,6 +550,26 @@ func lookupValue(cfg *Config, path string) (any, error) {
 			default:
 				return nil, fmt.Errorf("unknown path: %s", path)
 			}
+		case "reserved_region":
+			if len(parts) != 3 {
+				return nil, fmt.Errorf("unknown path: %s", path)
+			}
+			return layout.ReservedRegion, nil
+		case "order":
+			if len(parts) != 3 {
+				return nil, fmt.Errorf("unknown path: %s", path)
+			}
+			return layout.Order, nil
+		case "remainder_bias":
+			if len(parts) != 3 {
+				return nil, fmt.Errorf("unknown path: %s", path)
+			}
+			return layout.RemainderBias, nil
+		case "reserved_slot":
+			if len(parts) != 3 {
+				return nil, fmt.Errorf("unknown path: %s", path)
+			}
+			return layout.ReservedSlot, nil
 		case "max_terminal_width":
 			if len(parts) != 3 {
 				return nil, fmt.Errorf("unknown path: %s", path)
@@ -317,6 +580,16 @@ func lookupValue(cfg *Config, path string) (any, error) {
 				return nil, fmt.Errorf("unknown path: %s", path)
 			}
 			return layout.MaxTerminalHeight, nil
+		case "gap_size":
+			if len(parts) != 3 {
+				return nil, fmt.Errorf("unknown path: %s", path)
+			}
+			return cfg.LayoutGapSize(&layout), nil
+		case "aliases":
+			if len(parts) != 3 {
+				return nil, fmt.Errorf("unknown path: %s", path)
+			}
+			return layout.Aliases, nil
 		default:
 			return nil, fmt.Errorf("unknown path: %s", path)
 		}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/1broseidon/termtile/internal/hotkeys/chord"
+)
+
+// ParseHotkey splits a hotkey into its steps. A hotkey is either a single
+// chord such as "Mod4-Mod1-t", or a prefix chord followed by a second chord
+// separated by whitespace, such as "Mod4-space g": press the prefix, release
+// it, then press the second key.
+func ParseHotkey(hotkey string) ([]string, error) {
+	steps := strings.Fields(hotkey)
+	switch len(steps) {
+	case 0:
+		return nil, fmt.Errorf("hotkey is empty")
+	case 1, 2:
+	default:
+		return nil, fmt.Errorf("hotkey %q has %d steps; at most a prefix and one key are supported", hotkey, len(steps))
+	}
+	for _, step := range steps {
+		if err := validateHotkeyChord(step); err != nil {
+			return nil, fmt.Errorf("hotkey %q: %w", hotkey, err)
+		}
+	}
+	return steps, nil
+}
+
+func validateHotkeyChord(step string) error {
+	hasKey := false
+	for _, part := range strings.Split(step, "-") {
+		if part == "" {
+			return fmt.Errorf("chord %q has an empty part", step)
+		}
+		if !chord.IsModifier(part) {
+			if hasKey {
+				return fmt.Errorf("chord %q has more than one key", step)
+			}
+			hasKey = true
+		}
+	}
+	if !hasKey {
+		return fmt.Errorf("chord %q has no key", step)
+	}
+	return nil
+}
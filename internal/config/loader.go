@@ -2,6 +2,7 @@ package config
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -33,6 +34,20 @@ type LoadResult struct {
 	Sources     map[string]Source // YAML-path -> last writer source (file only)
 	LayoutBases map[string]string // layout name -> builtin base name
 	Files       []string          // all loaded files, in load order
+	Warnings    []string          // non-fatal findings from Config.Warnings
+}
+
+// warningOutput receives load warnings; set it with SetWarningOutput.
+var warningOutput io.Writer = os.Stderr
+
+// SetWarningOutput redirects the warnings printed while loading config.
+// Each warning is written as a single "warning: ..." line. A nil writer
+// restores stderr.
+func SetWarningOutput(w io.Writer) {
+	if w == nil {
+		w = os.Stderr
+	}
+	warningOutput = w
 }
 
 const (
@@ -89,6 +104,24 @@ func LoadFromPathWithProject(path string, projectRoot string) (*LoadResult, erro
 	return loadFromPath(path, projectRoot)
 }
 
+// LoadProjectWorkspace loads only the project-scoped settings from
+// projectRoot/.termtile/workspace.yaml and local.yaml, merged over the project
+// defaults. It returns nil when neither file exists.
+func LoadProjectWorkspace(projectRoot string) (*ProjectWorkspaceConfig, error) {
+	raw, sources, _, err := loadRawProjectWorkspaceMerged(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, nil
+	}
+	cfg, err := buildEffectiveProjectWorkspaceConfig(*raw)
+	if err != nil {
+		return nil, attachSourceContext(err, sources)
+	}
+	return &cfg, nil
+}
+
 func loadFromPath(path string, projectRoot string) (*LoadResult, error) {
 	raw := RawConfig{}
 	sources := map[string]Source{}
@@ -131,12 +164,17 @@ func loadFromPath(path string, projectRoot string) (*LoadResult, error) {
 	if err := cfg.Validate(); err != nil {
 		return nil, attachSourceContext(err, sources)
 	}
+	warnings := cfg.Warnings()
+	for _, w := range warnings {
+		fmt.Fprintln(warningOutput, "warning:", w)
+	}
 
 	return &LoadResult{
 		Config:      cfg,
 		Sources:     sources,
 		LayoutBases: layoutBases,
 		Files:       files,
+		Warnings:    warnings,
 	}, nil
 }
 
@@ -291,6 +329,57 @@ func loadRawMerged(path string, seen map[string]struct{}, stack []string) (RawCo
 	return merged, mergedSources, files, nil
 }
 
+// ParseLayout parses a single layout definition, as it would appear under
+// layouts.<name> in a config file, and validates it. The layout may use
+// "inherits: builtin:<name>"; without it, unset fields come from the default
+// builtin layout.
+func ParseLayout(data []byte) (Layout, error) {
+	var patch RawLayout
+	if err := decodeStrictYAML(data, &patch); err != nil {
+		return Layout{}, fmt.Errorf("failed to parse layout: %w", err)
+	}
+
+	if patch.Extends != nil {
+		return Layout{}, &ValidationError{
+			Path: "layout.extends",
+			Err:  fmt.Errorf("extends needs the layouts of a config; use inherits: builtin:<name>"),
+		}
+	}
+
+	builtin := BuiltinLayouts()
+	var base Layout
+	if patch.Inherits != nil {
+		// selectLayoutBase prefers a builtin of the same name; an ad-hoc
+		// layout has no name, so only an explicit inherits applies.
+		_, inherited, err := selectLayoutBase("", patch, builtin)
+		if err != nil {
+			return Layout{}, relabelLayoutError(err)
+		}
+		base = inherited
+	} else {
+		base = builtin[DefaultBuiltinLayout]
+	}
+
+	layout, err := mergeLayoutPatch(base, patch)
+	if err != nil {
+		return Layout{}, err
+	}
+	if err := ValidateLayout(&layout); err != nil {
+		return Layout{}, &ValidationError{Path: "layout", Err: err}
+	}
+	return layout, nil
+}
+
+// relabelLayoutError rewrites a ValidationError path produced for an unnamed
+// layout ("layouts..inherits") to refer to the standalone layout.
+func relabelLayoutError(err error) error {
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		return &ValidationError{Path: "layout" + strings.TrimPrefix(verr.Path, "layouts."), Err: verr.Err}
+	}
+	return err
+}
+
 func decodeStrictYAML(data []byte, out any) error {
 	dec := yaml.NewDecoder(bytes.NewReader(data))
 	dec.KnownFields(true)
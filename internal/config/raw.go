@@ -72,6 +72,7 @@ type RawMasterStack struct {
 
 type RawLayout struct {
 	Inherits          *string         `yaml:"inherits"`
+	Extends           *string         `yaml:"extends"`
 	Mode              *LayoutMode     `yaml:"mode"`
 	TileRegion        *RawTileRegion  `yaml:"tile_region"`
 	FixedGrid         *RawFixedGrid   `yaml:"fixed_grid"`
@@ -79,12 +80,25 @@ type RawLayout struct {
 	MaxTerminalWidth  *int            `yaml:"max_terminal_width"`
 	MaxTerminalHeight *int            `yaml:"max_terminal_height"`
 	FlexibleLastRow   *bool           `yaml:"flexible_last_row"`
+	GapSize           *int            `yaml:"gap_size"`
+	Aliases           []string        `yaml:"aliases"`
+	Order             *TileOrder      `yaml:"order"`
+	RemainderBias     *RemainderBias  `yaml:"remainder_bias"`
+	ReservedRegion    *RawTileRegion  `yaml:"reserved_region"`
+	ReservedSlot      *int            `yaml:"reserved_slot"`
+	SingleWindowNoGap *bool           `yaml:"single_window_no_gap"`
 }
 
 type RawWorkspaceLimit struct {
 	MaxTerminals *int `yaml:"max_terminals"`
 }
 
+type RawReconcilerConfig struct {
+	IntervalSeconds    *int  `yaml:"interval_seconds"`
+	RunOnStart         *bool `yaml:"run_on_start"`
+	OrphanGraceSeconds *int  `yaml:"orphan_grace_seconds"`
+}
+
 type RawLimits struct {
 	MaxTerminalsPerWorkspace *int                         `yaml:"max_terminals_per_workspace"`
 	MaxWorkspaces            *int                         `yaml:"max_workspaces"`
@@ -92,18 +106,45 @@ type RawLimits struct {
 	WorkspaceOverrides       map[string]RawWorkspaceLimit `yaml:"workspace_overrides"`
 }
 
+type RawMoveModeHints struct {
+	Position      *string  `yaml:"position"`
+	Selecting     []string `yaml:"selecting"`
+	Move          []string `yaml:"move"`
+	ConfirmDelete []string `yaml:"confirm_delete"`
+}
+
+type RawMoveModeConfig struct {
+	Hints *RawMoveModeHints `yaml:"hints"`
+	Keys  map[string]string `yaml:"keys"`
+}
+
 type RawLoggingConfig struct {
-	Enabled        *bool   `yaml:"enabled"`
-	Level          *string `yaml:"level"`
-	File           *string `yaml:"file"`
-	MaxSizeMB      *int    `yaml:"max_size_mb"`
-	MaxFiles       *int    `yaml:"max_files"`
-	IncludeContent *bool   `yaml:"include_content"`
-	PreviewLength  *int    `yaml:"preview_length"`
+	Enabled         *bool   `yaml:"enabled"`
+	Level           *string `yaml:"level"`
+	File            *string `yaml:"file"`
+	MaxSizeMB       *int    `yaml:"max_size_mb"`
+	MaxFiles        *int    `yaml:"max_files"`
+	IncludeContent  *bool   `yaml:"include_content"`
+	PreviewLength   *int    `yaml:"preview_length"`
+	MaxContentBytes *int    `yaml:"max_content_bytes"`
 }
 
 type RawAgentMode struct {
-	ProtectSlotZero *bool `yaml:"protect_slot_zero"`
+	Multiplexer             *string `yaml:"multiplexer"`
+	ManageMultiplexerConfig *bool   `yaml:"manage_multiplexer_config"`
+	ProtectSlotZero         *bool   `yaml:"protect_slot_zero"`
+	IdlePollMs              *int    `yaml:"idle_poll_ms"`
+	DefaultIdleTimeoutS     *int    `yaml:"default_idle_timeout_s"`
+	DefaultDepTimeoutS      *int    `yaml:"default_dep_timeout_s"`
+	WindowSpawnTimeoutS     *int    `yaml:"window_spawn_timeout_s"`
+	WindowSettleMs          *int    `yaml:"window_settle_ms"`
+	ReuseDisplayConnection  *bool   `yaml:"reuse_display_connection"`
+	CaptureEscapes          *bool   `yaml:"capture_escapes"`
+	MaxConcurrentSpawns     *int    `yaml:"max_concurrent_spawns"`
+	MaxInlineTaskBytes      *int    `yaml:"max_inline_task_bytes"`
+	OnCompleteCommand       *string `yaml:"on_complete_command"`
+
+	TmuxSessionOptions map[string]string `yaml:"tmux_session_options"`
 }
 
 type RawAgentHooks struct {
@@ -113,22 +154,27 @@ type RawAgentHooks struct {
 }
 
 type RawAgentConfig struct {
-	Command       string            `yaml:"command"`
-	Args          []string          `yaml:"args"`
-	ReadyPattern  string            `yaml:"ready_pattern"`
-	IdlePattern   string            `yaml:"idle_pattern"`
-	OutputMode    string            `yaml:"output_mode"`
-	Hooks         RawAgentHooks     `yaml:"hooks"`
-	Description   string            `yaml:"description"`
-	Env           map[string]string `yaml:"env"`
-	PromptAsArg   bool              `yaml:"prompt_as_arg"`
-	PromptFlag    string            `yaml:"prompt_flag"`
-	SpawnMode     string            `yaml:"spawn_mode"`
-	ResponseFence bool              `yaml:"response_fence"`
-	PipeTask      bool              `yaml:"pipe_task"`
-	Models        []string          `yaml:"models"`
-	DefaultModel  string            `yaml:"default_model"`
-	ModelFlag     string            `yaml:"model_flag"`
+	Command        string            `yaml:"command"`
+	Args           []string          `yaml:"args"`
+	ReadyPattern   string            `yaml:"ready_pattern"`
+	IdlePattern    string            `yaml:"idle_pattern"`
+	IdlePatterns   []string          `yaml:"idle_patterns"`
+	IdleLineMaxLen int               `yaml:"idle_line_max_len"`
+	OutputMode     string            `yaml:"output_mode"`
+	Hooks          RawAgentHooks     `yaml:"hooks"`
+	Description    string            `yaml:"description"`
+	Env            map[string]string `yaml:"env"`
+	PromptAsArg    bool              `yaml:"prompt_as_arg"`
+	PromptFlag     string            `yaml:"prompt_flag"`
+	SpawnMode      string            `yaml:"spawn_mode"`
+	ResponseFence  bool              `yaml:"response_fence"`
+	PipeTask       bool              `yaml:"pipe_task"`
+	Models         []string          `yaml:"models"`
+	DefaultModel   string            `yaml:"default_model"`
+	ModelFlag      string            `yaml:"model_flag"`
+	PostSpawnKeys  []string          `yaml:"post_spawn_keys"`
+
+	ClearInputBeforeSend *bool `yaml:"clear_input_before_send"`
 
 	HookDelivery      string                 `yaml:"hook_delivery"`
 	HookSettingsFlag  string                 `yaml:"hook_settings_flag"`
@@ -206,32 +252,52 @@ type RawProjectWorkspaceConfig struct {
 }
 
 type RawConfig struct {
-	Include                  IncludeList                `yaml:"include"`
-	Hotkey                   *string                    `yaml:"hotkey"`
-	CycleLayoutHotkey        *string                    `yaml:"cycle_layout_hotkey"`
-	CycleLayoutReverseHotkey *string                    `yaml:"cycle_layout_reverse_hotkey"`
-	UndoHotkey               *string                    `yaml:"undo_hotkey"`
-	TerminalAddHotkey        *string                    `yaml:"terminal_add_hotkey"`
-	PaletteHotkey            *string                    `yaml:"palette_hotkey"`
-	PaletteBackend           *string                    `yaml:"palette_backend"`
-	PaletteFuzzyMatching     *bool                      `yaml:"palette_fuzzy_matching"`
-	Display                  *string                    `yaml:"display"`
-	XAuthority               *string                    `yaml:"xauthority"`
-	PreferredTerminal        *string                    `yaml:"preferred_terminal"`
-	TerminalSpawnCommands    map[string]string          `yaml:"terminal_spawn_commands"`
-	GapSize                  *int                       `yaml:"gap_size"`
-	ScreenPadding            *RawMargins                `yaml:"screen_padding"`
-	DefaultLayout            *string                    `yaml:"default_layout"`
-	Layouts                  map[string]RawLayout       `yaml:"layouts"`
-	TerminalClasses          TerminalClassList          `yaml:"terminal_classes"`
-	TerminalSort             *string                    `yaml:"terminal_sort"`
-	LogLevel                 *string                    `yaml:"log_level"`
-	TerminalMargins          map[string]RawMargins      `yaml:"terminal_margins"`
-	AgentMode                *RawAgentMode              `yaml:"agent_mode"`
-	Limits                   *RawLimits                 `yaml:"limits"`
-	Logging                  *RawLoggingConfig          `yaml:"logging"`
-	Agents                   map[string]RawAgentConfig  `yaml:"agents"`
-	ProjectWorkspace         *RawProjectWorkspaceConfig `yaml:"-"`
+	Include                  IncludeList                  `yaml:"include"`
+	Hotkey                   *string                      `yaml:"hotkey"`
+	CycleLayoutHotkey        *string                      `yaml:"cycle_layout_hotkey"`
+	CycleLayoutReverseHotkey *string                      `yaml:"cycle_layout_reverse_hotkey"`
+	UndoHotkey               *string                      `yaml:"undo_hotkey"`
+	MonocleHotkey            *string                      `yaml:"monocle_hotkey"`
+	MoveModeHotkey           *string                      `yaml:"move_mode_hotkey"`
+	TerminalAddHotkey        *string                      `yaml:"terminal_add_hotkey"`
+	MoveModeTimeout          *int                         `yaml:"move_mode_timeout"`
+	PaletteHotkey            *string                      `yaml:"palette_hotkey"`
+	LayoutHotkeys            map[string]string            `yaml:"layout_hotkeys"`
+	PaletteBackend           *string                      `yaml:"palette_backend"`
+	PaletteFuzzyMatching     *bool                        `yaml:"palette_fuzzy_matching"`
+	MoveModeRestoreFocus     *bool                        `yaml:"move_mode_restore_focus"`
+	MoveModeAllWindows       *bool                        `yaml:"move_mode_all_windows"`
+	MoveModeAnnounce         *bool                        `yaml:"move_mode_announce"`
+	MoveModeAnnounceCommand  *string                      `yaml:"move_mode_announce_command"`
+	MoveMode                 *RawMoveModeConfig           `yaml:"move_mode"`
+	Display                  *string                      `yaml:"display"`
+	XAuthority               *string                      `yaml:"xauthority"`
+	PreferredTerminal        *string                      `yaml:"preferred_terminal"`
+	TerminalSpawnCommands    map[string]string            `yaml:"terminal_spawn_commands"`
+	TerminalSpawnEnv         map[string]map[string]string `yaml:"terminal_spawn_env"`
+	GapSize                  *int                         `yaml:"gap_size"`
+	MinTileWidth             *int                         `yaml:"min_tile_width"`
+	MinTileHeight            *int                         `yaml:"min_tile_height"`
+	ScreenPadding            *RawMargins                  `yaml:"screen_padding"`
+	MonitorPadding           map[string]RawMargins        `yaml:"monitor_padding"`
+	DefaultLayout            *string                      `yaml:"default_layout"`
+	PersistActiveLayout      *bool                        `yaml:"persist_active_layout"`
+	Layouts                  map[string]RawLayout         `yaml:"layouts"`
+	TerminalClasses          TerminalClassList            `yaml:"terminal_classes"`
+	TerminalSort             *string                      `yaml:"terminal_sort"`
+	RetileTarget             *string                      `yaml:"retile_target"`
+	FocusAfterTile           *string                      `yaml:"focus_after_tile"`
+	TileIgnoresNonTerminals  *bool                        `yaml:"tile_ignores_non_terminals"`
+	LogLevel                 *string                      `yaml:"log_level"`
+	TerminalMargins          map[string]RawMargins        `yaml:"terminal_margins"`
+	AgentMode                *RawAgentMode                `yaml:"agent_mode"`
+	Limits                   *RawLimits                   `yaml:"limits"`
+	WorkspaceHistoryDepth    *int                         `yaml:"workspace_history_depth"`
+	AutosavePrevious         *bool                        `yaml:"autosave_previous"`
+	Reconciler               *RawReconcilerConfig         `yaml:"reconciler"`
+	Logging                  *RawLoggingConfig            `yaml:"logging"`
+	Agents                   map[string]RawAgentConfig    `yaml:"agents"`
+	ProjectWorkspace         *RawProjectWorkspaceConfig   `yaml:"-"`
 }
 
 func (c RawConfig) merge(overlay RawConfig) RawConfig {
@@ -249,9 +315,18 @@ func (c RawConfig) merge(overlay RawConfig) RawConfig {
 	if overlay.UndoHotkey != nil {
 		out.UndoHotkey = overlay.UndoHotkey
 	}
+	if overlay.MonocleHotkey != nil {
+		out.MonocleHotkey = overlay.MonocleHotkey
+	}
+	if overlay.MoveModeHotkey != nil {
+		out.MoveModeHotkey = overlay.MoveModeHotkey
+	}
 	if overlay.TerminalAddHotkey != nil {
 		out.TerminalAddHotkey = overlay.TerminalAddHotkey
 	}
+	if overlay.MoveModeTimeout != nil {
+		out.MoveModeTimeout = overlay.MoveModeTimeout
+	}
 	if overlay.PaletteHotkey != nil {
 		out.PaletteHotkey = overlay.PaletteHotkey
 	}
@@ -261,6 +336,18 @@ func (c RawConfig) merge(overlay RawConfig) RawConfig {
 	if overlay.PaletteFuzzyMatching != nil {
 		out.PaletteFuzzyMatching = overlay.PaletteFuzzyMatching
 	}
+	if overlay.MoveModeRestoreFocus != nil {
+		out.MoveModeRestoreFocus = overlay.MoveModeRestoreFocus
+	}
+	if overlay.MoveModeAllWindows != nil {
+		out.MoveModeAllWindows = overlay.MoveModeAllWindows
+	}
+	if overlay.MoveModeAnnounce != nil {
+		out.MoveModeAnnounce = overlay.MoveModeAnnounce
+	}
+	if overlay.MoveModeAnnounceCommand != nil {
+		out.MoveModeAnnounceCommand = overlay.MoveModeAnnounceCommand
+	}
 	if overlay.Display != nil {
 		out.Display = overlay.Display
 	}
@@ -270,6 +357,14 @@ func (c RawConfig) merge(overlay RawConfig) RawConfig {
 	if overlay.PreferredTerminal != nil {
 		out.PreferredTerminal = overlay.PreferredTerminal
 	}
+	if overlay.LayoutHotkeys != nil {
+		if out.LayoutHotkeys == nil {
+			out.LayoutHotkeys = make(map[string]string, len(overlay.LayoutHotkeys))
+		}
+		for layout, hotkey := range overlay.LayoutHotkeys {
+			out.LayoutHotkeys[layout] = hotkey
+		}
+	}
 	if overlay.TerminalSpawnCommands != nil {
 		if out.TerminalSpawnCommands == nil {
 			out.TerminalSpawnCommands = make(map[string]string, len(overlay.TerminalSpawnCommands))
@@ -278,9 +373,30 @@ func (c RawConfig) merge(overlay RawConfig) RawConfig {
 			out.TerminalSpawnCommands[class] = cmd
 		}
 	}
+	if overlay.TerminalSpawnEnv != nil {
+		if out.TerminalSpawnEnv == nil {
+			out.TerminalSpawnEnv = make(map[string]map[string]string, len(overlay.TerminalSpawnEnv))
+		}
+		for class, env := range overlay.TerminalSpawnEnv {
+			merged := make(map[string]string, len(out.TerminalSpawnEnv[class])+len(env))
+			for k, v := range out.TerminalSpawnEnv[class] {
+				merged[k] = v
+			}
+			for k, v := range env {
+				merged[k] = v
+			}
+			out.TerminalSpawnEnv[class] = merged
+		}
+	}
 	if overlay.GapSize != nil {
 		out.GapSize = overlay.GapSize
 	}
+	if overlay.MinTileWidth != nil {
+		out.MinTileWidth = overlay.MinTileWidth
+	}
+	if overlay.MinTileHeight != nil {
+		out.MinTileHeight = overlay.MinTileHeight
+	}
 	if overlay.ScreenPadding != nil {
 		if out.ScreenPadding == nil {
 			out.ScreenPadding = &RawMargins{}
@@ -301,6 +417,9 @@ func (c RawConfig) merge(overlay RawConfig) RawConfig {
 	if overlay.DefaultLayout != nil {
 		out.DefaultLayout = overlay.DefaultLayout
 	}
+	if overlay.PersistActiveLayout != nil {
+		out.PersistActiveLayout = overlay.PersistActiveLayout
+	}
 
 	if overlay.Layouts != nil {
 		if out.Layouts == nil {
@@ -322,10 +441,39 @@ func (c RawConfig) merge(overlay RawConfig) RawConfig {
 	if overlay.TerminalSort != nil {
 		out.TerminalSort = overlay.TerminalSort
 	}
+	if overlay.WorkspaceHistoryDepth != nil {
+		out.WorkspaceHistoryDepth = overlay.WorkspaceHistoryDepth
+	}
+	if overlay.AutosavePrevious != nil {
+		out.AutosavePrevious = overlay.AutosavePrevious
+	}
+	if overlay.RetileTarget != nil {
+		out.RetileTarget = overlay.RetileTarget
+	}
+	if overlay.FocusAfterTile != nil {
+		out.FocusAfterTile = overlay.FocusAfterTile
+	}
+	if overlay.TileIgnoresNonTerminals != nil {
+		out.TileIgnoresNonTerminals = overlay.TileIgnoresNonTerminals
+	}
 	if overlay.LogLevel != nil {
 		out.LogLevel = overlay.LogLevel
 	}
 
+	if overlay.MonitorPadding != nil {
+		if out.MonitorPadding == nil {
+			out.MonitorPadding = make(map[string]RawMargins, len(overlay.MonitorPadding))
+		}
+		for monitor, padding := range overlay.MonitorPadding {
+			base, ok := out.MonitorPadding[monitor]
+			if !ok {
+				out.MonitorPadding[monitor] = padding
+				continue
+			}
+			out.MonitorPadding[monitor] = mergeRawMargins(base, padding)
+		}
+	}
+
 	if overlay.TerminalMargins != nil {
 		if out.TerminalMargins == nil {
 			out.TerminalMargins = make(map[string]RawMargins, len(overlay.TerminalMargins))
@@ -368,6 +516,52 @@ func (c RawConfig) merge(overlay RawConfig) RawConfig {
 		}
 	}
 
+	if overlay.Reconciler != nil {
+		if out.Reconciler == nil {
+			out.Reconciler = &RawReconcilerConfig{}
+		}
+		if overlay.Reconciler.IntervalSeconds != nil {
+			out.Reconciler.IntervalSeconds = overlay.Reconciler.IntervalSeconds
+		}
+		if overlay.Reconciler.RunOnStart != nil {
+			out.Reconciler.RunOnStart = overlay.Reconciler.RunOnStart
+		}
+		if overlay.Reconciler.OrphanGraceSeconds != nil {
+			out.Reconciler.OrphanGraceSeconds = overlay.Reconciler.OrphanGraceSeconds
+		}
+	}
+
+	if overlay.MoveMode != nil {
+		if out.MoveMode == nil {
+			out.MoveMode = &RawMoveModeConfig{}
+		}
+		if hints := overlay.MoveMode.Hints; hints != nil {
+			if out.MoveMode.Hints == nil {
+				out.MoveMode.Hints = &RawMoveModeHints{}
+			}
+			if hints.Position != nil {
+				out.MoveMode.Hints.Position = hints.Position
+			}
+			if hints.Selecting != nil {
+				out.MoveMode.Hints.Selecting = hints.Selecting
+			}
+			if hints.Move != nil {
+				out.MoveMode.Hints.Move = hints.Move
+			}
+			if hints.ConfirmDelete != nil {
+				out.MoveMode.Hints.ConfirmDelete = hints.ConfirmDelete
+			}
+		}
+		if overlay.MoveMode.Keys != nil {
+			if out.MoveMode.Keys == nil {
+				out.MoveMode.Keys = make(map[string]string, len(overlay.MoveMode.Keys))
+			}
+			for action, key := range overlay.MoveMode.Keys {
+				out.MoveMode.Keys[action] = key
+			}
+		}
+	}
+
 	if overlay.Logging != nil {
 		if out.Logging == nil {
 			out.Logging = &RawLoggingConfig{}
@@ -393,15 +587,62 @@ func (c RawConfig) merge(overlay RawConfig) RawConfig {
 		if overlay.Logging.PreviewLength != nil {
 			out.Logging.PreviewLength = overlay.Logging.PreviewLength
 		}
+		if overlay.Logging.MaxContentBytes != nil {
+			out.Logging.MaxContentBytes = overlay.Logging.MaxContentBytes
+		}
 	}
 
 	if overlay.AgentMode != nil {
 		if out.AgentMode == nil {
 			out.AgentMode = &RawAgentMode{}
 		}
+		if overlay.AgentMode.Multiplexer != nil {
+			out.AgentMode.Multiplexer = overlay.AgentMode.Multiplexer
+		}
+		if overlay.AgentMode.ManageMultiplexerConfig != nil {
+			out.AgentMode.ManageMultiplexerConfig = overlay.AgentMode.ManageMultiplexerConfig
+		}
 		if overlay.AgentMode.ProtectSlotZero != nil {
 			out.AgentMode.ProtectSlotZero = overlay.AgentMode.ProtectSlotZero
 		}
+		if overlay.AgentMode.IdlePollMs != nil {
+			out.AgentMode.IdlePollMs = overlay.AgentMode.IdlePollMs
+		}
+		if overlay.AgentMode.DefaultIdleTimeoutS != nil {
+			out.AgentMode.DefaultIdleTimeoutS = overlay.AgentMode.DefaultIdleTimeoutS
+		}
+		if overlay.AgentMode.DefaultDepTimeoutS != nil {
+			out.AgentMode.DefaultDepTimeoutS = overlay.AgentMode.DefaultDepTimeoutS
+		}
+		if overlay.AgentMode.WindowSpawnTimeoutS != nil {
+			out.AgentMode.WindowSpawnTimeoutS = overlay.AgentMode.WindowSpawnTimeoutS
+		}
+		if overlay.AgentMode.WindowSettleMs != nil {
+			out.AgentMode.WindowSettleMs = overlay.AgentMode.WindowSettleMs
+		}
+		if overlay.AgentMode.ReuseDisplayConnection != nil {
+			out.AgentMode.ReuseDisplayConnection = overlay.AgentMode.ReuseDisplayConnection
+		}
+		if overlay.AgentMode.CaptureEscapes != nil {
+			out.AgentMode.CaptureEscapes = overlay.AgentMode.CaptureEscapes
+		}
+		if overlay.AgentMode.MaxConcurrentSpawns != nil {
+			out.AgentMode.MaxConcurrentSpawns = overlay.AgentMode.MaxConcurrentSpawns
+		}
+		if overlay.AgentMode.MaxInlineTaskBytes != nil {
+			out.AgentMode.MaxInlineTaskBytes = overlay.AgentMode.MaxInlineTaskBytes
+		}
+		if overlay.AgentMode.OnCompleteCommand != nil {
+			out.AgentMode.OnCompleteCommand = overlay.AgentMode.OnCompleteCommand
+		}
+		if overlay.AgentMode.TmuxSessionOptions != nil {
+			if out.AgentMode.TmuxSessionOptions == nil {
+				out.AgentMode.TmuxSessionOptions = make(map[string]string, len(overlay.AgentMode.TmuxSessionOptions))
+			}
+			for name, value := range overlay.AgentMode.TmuxSessionOptions {
+				out.AgentMode.TmuxSessionOptions[name] = value
+			}
+		}
 	}
 
 	if overlay.Agents != nil {
@@ -414,8 +655,13 @@ func (c RawConfig) merge(overlay RawConfig) RawConfig {
 				// This lets users partially override an agent (e.g. just
 				// change args) without losing idle_pattern, output_mode,
 				// response_fence, etc.
-				if agent.IdlePattern == "" {
+				// Setting either idle field replaces the base detection.
+				if agent.IdlePattern == "" && len(agent.IdlePatterns) == 0 {
 					agent.IdlePattern = base.IdlePattern
+					agent.IdlePatterns = base.IdlePatterns
+				}
+				if agent.IdleLineMaxLen == 0 {
+					agent.IdleLineMaxLen = base.IdleLineMaxLen
 				}
 				if agent.OutputMode == "" {
 					agent.OutputMode = base.OutputMode
@@ -450,6 +696,12 @@ func (c RawConfig) merge(overlay RawConfig) RawConfig {
 				if agent.ModelFlag == "" {
 					agent.ModelFlag = base.ModelFlag
 				}
+				if len(agent.PostSpawnKeys) == 0 {
+					agent.PostSpawnKeys = base.PostSpawnKeys
+				}
+				if agent.ClearInputBeforeSend == nil {
+					agent.ClearInputBeforeSend = base.ClearInputBeforeSend
+				}
 				if agent.HookDelivery == "" {
 					agent.HookDelivery = base.HookDelivery
 				}
@@ -565,6 +817,9 @@ func mergeRawLayout(base RawLayout, overlay RawLayout) RawLayout {
 	if overlay.Inherits != nil {
 		out.Inherits = overlay.Inherits
 	}
+	if overlay.Extends != nil {
+		out.Extends = overlay.Extends
+	}
 	if overlay.Mode != nil {
 		out.Mode = overlay.Mode
 	}
@@ -598,6 +853,31 @@ func mergeRawLayout(base RawLayout, overlay RawLayout) RawLayout {
 	if overlay.FlexibleLastRow != nil {
 		out.FlexibleLastRow = overlay.FlexibleLastRow
 	}
+	if overlay.SingleWindowNoGap != nil {
+		out.SingleWindowNoGap = overlay.SingleWindowNoGap
+	}
+	if overlay.GapSize != nil {
+		out.GapSize = overlay.GapSize
+	}
+	if overlay.Aliases != nil {
+		out.Aliases = overlay.Aliases
+	}
+	if overlay.Order != nil {
+		out.Order = overlay.Order
+	}
+	if overlay.RemainderBias != nil {
+		out.RemainderBias = overlay.RemainderBias
+	}
+	if overlay.ReservedRegion != nil {
+		if out.ReservedRegion == nil {
+			out.ReservedRegion = &RawTileRegion{}
+		}
+		merged := mergeRawTileRegion(*out.ReservedRegion, *overlay.ReservedRegion)
+		out.ReservedRegion = &merged
+	}
+	if overlay.ReservedSlot != nil {
+		out.ReservedSlot = overlay.ReservedSlot
+	}
 	return out
 }
 
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// SchemaURL is the JSON Schema dialect Schema declares.
+const SchemaURL = "https://json-schema.org/draft/2020-12/schema"
+
+// schemaEnums maps a config path to the values Validate accepts there. Map
+// keys and list items appear as "*" and "[]" in the path. Values match
+// exactly, as in Validate; an empty multiplexer means auto.
+var schemaEnums = map[string][]string{
+	"palette_backend":                paletteBackends,
+	"log_level":                      logLevels,
+	"terminal_sort":                  terminalSorts,
+	"retile_target":                  retileTargets,
+	"focus_after_tile":               focusAfterTiles,
+	"move_mode.hints.position":       hintPositions,
+	"agent_mode.multiplexer":         append([]string{""}, multiplexers...),
+	"layouts.*.mode":                 layoutModes,
+	"layouts.*.order":                tileOrders,
+	"layouts.*.remainder_bias":       remainderBiases,
+	"layouts.*.tile_region.type":     regionTypes,
+	"layouts.*.reserved_region.type": regionTypes,
+}
+
+// schemaRequired maps a config path to the keys the loader rejects the
+// mapping without.
+var schemaRequired = map[string][]string{
+	"terminal_classes[]": {"class"},
+}
+
+// Schema returns a JSON Schema describing the config file. It is built from
+// the yaml tags on RawConfig, so every key the loader accepts is listed and
+// unknown keys are rejected, as the loader does. Enum values come from the
+// same lists Validate checks.
+func Schema() map[string]any {
+	s := schemaFor(reflect.TypeOf(RawConfig{}), "")
+	s["$schema"] = SchemaURL
+	s["title"] = "termtile config"
+	return s
+}
+
+var (
+	includeListType       = reflect.TypeOf(IncludeList{})
+	terminalClassListType = reflect.TypeOf(TerminalClassList{})
+)
+
+func schemaFor(t reflect.Type, path string) map[string]any {
+	switch t {
+	case includeListType:
+		return map[string]any{
+			"oneOf": []any{
+				map[string]any{"type": "string"},
+				map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			},
+		}
+	case terminalClassListType:
+		return map[string]any{
+			"type": "array",
+			"items": map[string]any{
+				"oneOf": []any{
+					map[string]any{"type": "string"},
+					schemaFor(reflect.TypeOf(TerminalClass{}), path+"[]"),
+				},
+			},
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaFor(t.Elem(), path)
+	case reflect.Struct:
+		props := make(map[string]any, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			props[name] = schemaFor(field.Type, joinSchemaPath(path, name))
+		}
+		out := map[string]any{
+			"type":                 "object",
+			"properties":           props,
+			"additionalProperties": false,
+		}
+		if required, ok := schemaRequired[path]; ok {
+			out["required"] = required
+		}
+		return out
+	case reflect.Map:
+		return map[string]any{
+			"type":                 "object",
+			"additionalProperties": schemaFor(t.Elem(), joinSchemaPath(path, "*")),
+		}
+	case reflect.Slice:
+		return map[string]any{
+			"type":  "array",
+			"items": schemaFor(t.Elem(), path+"[]"),
+		}
+	case reflect.String:
+		out := map[string]any{"type": "string"}
+		if values, ok := schemaEnums[path]; ok {
+			out["enum"] = values
+		}
+		return out
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	default:
+		// interface{} values (agent hook templates) take any YAML.
+		return map[string]any{}
+	}
+}
+
+func joinSchemaPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// schemaNode walks Schema output along properties (and "*" for map values,
+// "[]" for list items).
+func schemaNode(t *testing.T, schema map[string]any, path ...string) map[string]any {
+	t.Helper()
+	node := schema
+	for _, key := range path {
+		var next any
+		switch key {
+		case "*":
+			next = node["additionalProperties"]
+		case "[]":
+			next = node["items"]
+		default:
+			props, _ := node["properties"].(map[string]any)
+			next = props[key]
+		}
+		m, ok := next.(map[string]any)
+		if !ok {
+			t.Fatalf("schema has no %v (stopped at %q)", path, key)
+		}
+		node = m
+	}
+	return node
+}
+
+func TestSchema_Enums(t *testing.T) {
+	schema := Schema()
+	tests := []struct {
+		path []string
+		want []string
+	}{
+		{[]string{"layouts", "*", "mode"}, []string{"auto", "fixed", "vertical", "horizontal", "master-stack"}},
+		{[]string{"layouts", "*", "tile_region", "type"}, []string{"full", "left-half", "custom"}},
+		{[]string{"layouts", "*", "reserved_region", "type"}, []string{"top-half"}},
+		{[]string{"palette_backend"}, []string{"auto", "rofi", "fuzzel", "dmenu", "wofi"}},
+		{[]string{"terminal_sort"}, []string{"position", "pid"}},
+		{[]string{"agent_mode", "multiplexer"}, []string{"", "tmux", "screen"}},
+		{[]string{"move_mode", "hints", "position"}, []string{"center"}},
+	}
+	for _, tt := range tests {
+		node := schemaNode(t, schema, tt.path...)
+		enum, _ := node["enum"].([]string)
+		for _, value := range tt.want {
+			if !slices.Contains(enum, value) {
+				t.Errorf("%v enum = %v, missing %q", tt.path, enum, value)
+			}
+		}
+	}
+}
+
+func TestSchema_RequiredAndStrict(t *testing.T) {
+	schema := Schema()
+	if schema["$schema"] != SchemaURL {
+		t.Fatalf("$schema = %v", schema["$schema"])
+	}
+	if schema["additionalProperties"] != false {
+		t.Fatal("top level should reject unknown keys like the loader")
+	}
+
+	items := schemaNode(t, schema, "terminal_classes", "[]")
+	oneOf, _ := items["oneOf"].([]any)
+	if len(oneOf) != 2 {
+		t.Fatalf("terminal_classes items = %v, want string or mapping", items)
+	}
+	mapping := oneOf[1].(map[string]any)
+	if required, _ := mapping["required"].([]string); !slices.Equal(required, []string{"class"}) {
+		t.Fatalf("terminal_classes mapping required = %v, want [class]", mapping["required"])
+	}
+
+	if _, err := json.Marshal(schema); err != nil {
+		t.Fatalf("schema does not encode as JSON: %v", err)
+	}
+}
+
+// TestSchema_CoversDefaultConfig checks every key `config print --defaults`
+// emits is in the schema, so a new setting cannot be left out.
+func TestSchema_CoversDefaultConfig(t *testing.T) {
+	data, err := yaml.Marshal(DefaultConfig())
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	var walk func(node map[string]any, value any, path string)
+	walk = func(node map[string]any, value any, path string) {
+		if len(node) == 0 {
+			return // free-form value
+		}
+		switch v := value.(type) {
+		case map[string]any:
+			props, _ := node["properties"].(map[string]any)
+			extra, _ := node["additionalProperties"].(map[string]any)
+			for key, child := range v {
+				childNode, ok := props[key].(map[string]any)
+				if !ok {
+					childNode = extra
+				}
+				if childNode == nil {
+					t.Errorf("schema is missing %s%s", path, key)
+					continue
+				}
+				walk(childNode, child, path+key+".")
+			}
+		case []any:
+			items, _ := node["items"].(map[string]any)
+			if oneOf, ok := items["oneOf"].([]any); ok {
+				items = oneOf[len(oneOf)-1].(map[string]any)
+			}
+			for _, child := range v {
+				walk(items, child, path+"[].")
+			}
+		}
+	}
+	walk(Schema(), doc, "")
+}
@@ -5,14 +5,18 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
 type TerminalClass struct {
-	Class   string `yaml:"class"`
-	Default bool   `yaml:"default,omitempty"`
+	Class string `yaml:"class"`
+	// Instance, when set, limits detection to windows whose WM_CLASS
+	// instance matches (case-insensitive).
+	Instance string `yaml:"instance,omitempty"`
+	Default  bool   `yaml:"default,omitempty"`
 }
 
 // TerminalClassList supports either:
@@ -27,6 +31,8 @@ type TerminalClass struct {
 //	  - class: kitty
 //	    default: true
 //	  - class: Alacritty
+//	  - class: kitty
+//	    instance: scratchpad
 type TerminalClassList []TerminalClass
 
 func (l *TerminalClassList) UnmarshalYAML(value *yaml.Node) error {
@@ -87,6 +93,14 @@ func decodeTerminalClassMapping(node *yaml.Node) (TerminalClass, error) {
 			if tc.Class == "" {
 				return TerminalClass{}, fmt.Errorf("terminal_classes[].class must not be empty")
 			}
+		case "instance":
+			if val.Kind != yaml.ScalarNode || val.Tag != "!!str" {
+				return TerminalClass{}, fmt.Errorf("terminal_classes[].instance must be a string")
+			}
+			tc.Instance = strings.TrimSpace(val.Value)
+			if tc.Instance == "" {
+				return TerminalClass{}, fmt.Errorf("terminal_classes[].instance must not be empty")
+			}
 		case "default":
 			var b bool
 			if err := val.Decode(&b); err != nil {
@@ -106,14 +120,14 @@ func decodeTerminalClassMapping(node *yaml.Node) (TerminalClass, error) {
 }
 
 func (l TerminalClassList) MarshalYAML() (any, error) {
-	hasDefault := false
+	plain := true
 	for _, tc := range l {
-		if tc.Default {
-			hasDefault = true
+		if tc.Default || tc.Instance != "" {
+			plain = false
 			break
 		}
 	}
-	if !hasDefault {
+	if plain {
 		out := make([]string, 0, len(l))
 		for _, tc := range l {
 			out = append(out, tc.Class)
@@ -335,6 +349,39 @@ func (c *Config) canSpawnTerminal(class string) bool {
 	return true
 }
 
+// SpawnEnv returns the terminal_spawn_env entry for class, matched like
+// terminal_spawn_commands (exact, then case-insensitive). It returns nil
+// when the class has none.
+func (c *Config) SpawnEnv(class string) map[string]string {
+	if c == nil || len(c.TerminalSpawnEnv) == 0 {
+		return nil
+	}
+	if env, ok := c.TerminalSpawnEnv[class]; ok {
+		return env
+	}
+	for k, env := range c.TerminalSpawnEnv {
+		if strings.EqualFold(k, class) {
+			return env
+		}
+	}
+	return nil
+}
+
+// SpawnEnvList returns SpawnEnv(class) as sorted KEY=VALUE pairs for
+// appending to exec.Cmd.Env.
+func (c *Config) SpawnEnvList(class string) []string {
+	env := c.SpawnEnv(class)
+	if len(env) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(env))
+	for k, v := range env {
+		out = append(out, k+"="+v)
+	}
+	sort.Strings(out)
+	return out
+}
+
 func lookupSpawnTemplate(templates map[string]string, class string) (string, bool) {
 	if templates == nil {
 		return "", false
@@ -0,0 +1,145 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// layoutState is the file written when persist_active_layout is set.
+type layoutState struct {
+	ActiveLayout string `json:"active_layout"`
+}
+
+// layoutStatePath returns the file the active layout is persisted to.
+func layoutStatePath() (string, error) {
+	if xdg := strings.TrimSpace(os.Getenv("XDG_STATE_HOME")); xdg != "" {
+		return filepath.Join(xdg, "termtile", "active-layout.json"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil || strings.TrimSpace(home) == "" {
+		return "", fmt.Errorf("failed to resolve state directory: home directory is not set")
+	}
+	return filepath.Join(home, ".local", "state", "termtile", "active-layout.json"), nil
+}
+
+// LoadActiveLayout returns the persisted active layout name, or "" when
+// none has been saved yet.
+func LoadActiveLayout() (string, error) {
+	path, err := layoutStatePath()
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read layout state: %w", err)
+	}
+	var state layoutState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return "", fmt.Errorf("failed to parse layout state %s: %w", path, err)
+	}
+	return state.ActiveLayout, nil
+}
+
+// SaveActiveLayout persists name as the active layout.
+func SaveActiveLayout(name string) error {
+	path, err := layoutStatePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(layoutState{ActiveLayout: name}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write layout state: %w", err)
+	}
+	return nil
+}
+
+// LayoutPersister saves the active layout while persist_active_layout is
+// set. Save only queues the name and Run writes it, so the tiler, which
+// reports layout changes while holding its lock, never waits on the file.
+type LayoutPersister struct {
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	enabled bool
+	// pending holds the latest unsaved layout name; a newer name replaces
+	// one Run has not picked up yet.
+	pending chan string
+}
+
+// NewLayoutPersister creates a persister that saves layouts when enabled.
+func NewLayoutPersister(enabled bool, logger *slog.Logger) *LayoutPersister {
+	return &LayoutPersister{
+		logger:  logger,
+		enabled: enabled,
+		pending: make(chan string, 1),
+	}
+}
+
+// SetEnabled applies a reloaded persist_active_layout. Turning it on saves
+// current, the layout in use now, rather than leaving whatever an earlier
+// run saved.
+func (p *LayoutPersister) SetEnabled(enabled bool, current string) {
+	p.mu.Lock()
+	wasEnabled := p.enabled
+	p.enabled = enabled
+	p.mu.Unlock()
+	if enabled && !wasEnabled {
+		p.Save(current)
+	}
+}
+
+// Save queues name to be written by Run. It does nothing while persistence
+// is off.
+func (p *LayoutPersister) Save(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.enabled {
+		return
+	}
+	select {
+	case <-p.pending:
+	default:
+	}
+	p.pending <- name
+}
+
+// Run writes queued layouts until ctx is cancelled, then writes the one
+// still queued, if any.
+func (p *LayoutPersister) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			select {
+			case name := <-p.pending:
+				p.write(name)
+			default:
+			}
+			return
+		case name := <-p.pending:
+			p.write(name)
+		}
+	}
+}
+
+func (p *LayoutPersister) write(name string) {
+	if err := SaveActiveLayout(name); err != nil {
+		p.logger.Warn("layout persister: failed to save active layout", "layout", name, "error", err)
+	}
+}
@@ -0,0 +1,112 @@
+package daemon
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testPersister(enabled bool) *LayoutPersister {
+	return NewLayoutPersister(enabled, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+// runPersister runs p until the test ends, flushing what is still queued.
+func runPersister(t *testing.T, p *LayoutPersister) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		p.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+func waitForSavedLayout(t *testing.T, want string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		got, err := LoadActiveLayout()
+		if err == nil && got == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("saved layout = %q, %v; want %q", got, err, want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestLoadActiveLayout(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	if got, err := LoadActiveLayout(); err != nil || got != "" {
+		t.Fatalf("nothing saved: %q, %v; want empty", got, err)
+	}
+	if err := SaveActiveLayout("columns"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := LoadActiveLayout(); err != nil || got != "columns" {
+		t.Fatalf("saved layout = %q, %v; want columns", got, err)
+	}
+
+	path, err := layoutStatePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadActiveLayout(); err == nil {
+		t.Fatal("corrupt state: err = nil")
+	}
+}
+
+func TestLayoutPersister_SavesLatestLayout(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	p := testPersister(true)
+	// Save only queues; nothing is written until Run picks it up.
+	p.Save("grid")
+	p.Save("columns")
+	if got, _ := LoadActiveLayout(); got != "" {
+		t.Fatalf("saved layout before Run = %q, want nothing written", got)
+	}
+
+	runPersister(t, p)
+	waitForSavedLayout(t, "columns")
+}
+
+func TestLayoutPersister_DisabledWritesNothing(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", dir)
+
+	p := testPersister(false)
+	p.Save("columns")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p.Run(ctx)
+
+	if _, err := os.Stat(filepath.Join(dir, "termtile", "active-layout.json")); !os.IsNotExist(err) {
+		t.Fatalf("state file exists with persist_active_layout off (stat err %v)", err)
+	}
+}
+
+func TestLayoutPersister_EnablingSavesCurrentLayout(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	if err := SaveActiveLayout("grid"); err != nil {
+		t.Fatal(err)
+	}
+
+	p := testPersister(false)
+	runPersister(t, p)
+	p.SetEnabled(true, "columns")
+	waitForSavedLayout(t, "columns")
+}
@@ -11,23 +11,70 @@ import (
 	"github.com/1broseidon/termtile/internal/workspace"
 )
 
+// defaultReconcileInterval is the time between passes when
+// ReconcilerConfig.Interval is unset.
+const defaultReconcileInterval = 10 * time.Second
+
 // WindowLister is a function that returns current terminal window IDs.
 type WindowLister func() ([]uint32, error)
 
 // ReconcilerConfig holds configuration for the reconciler.
 type ReconcilerConfig struct {
+	// Interval is the time between passes; zero uses
+	// defaultReconcileInterval.
 	Interval        time.Duration
 	CleanupOrphaned bool
-	Logger          *slog.Logger
+	// RunOnStart makes Run perform one pass before waiting for the first tick.
+	RunOnStart bool
+	// OrphanGrace is how long a slot or session must stay orphaned before a
+	// pass cleans it up; zero cleans up on the first pass that finds it.
+	OrphanGrace time.Duration
+	Logger      *slog.Logger
 }
 
 // Reconciler periodically checks for state drift and corrects it.
 type Reconciler struct {
 	interval        time.Duration
 	cleanupOrphaned bool
+	runOnStart      bool
 	sync            *StateSynchronizer
 	listWindows     WindowLister
 	logger          *slog.Logger
+
+	orphanGrace      time.Duration
+	orphanedWindows  orphanTracker[uint32]
+	orphanedSessions orphanTracker[string]
+
+	// pass runs one reconciliation; tests replace it to count passes.
+	pass func()
+	// now is the clock used for orphan grace periods.
+	now func() time.Time
+}
+
+// orphanTracker remembers when each orphaned entry was first seen, so the
+// reconciler only cleans up entries that stayed orphaned for the grace period.
+type orphanTracker[K comparable] struct {
+	firstSeen map[K]time.Time
+}
+
+// due records the entries orphaned in this pass and returns those orphaned
+// for at least grace. Entries missing from orphaned are forgotten, so one
+// that is tracked again starts over if it is orphaned later.
+func (t *orphanTracker[K]) due(orphaned []K, grace time.Duration, now time.Time) []K {
+	seen := make(map[K]time.Time, len(orphaned))
+	var out []K
+	for _, key := range orphaned {
+		first, ok := t.firstSeen[key]
+		if !ok {
+			first = now
+		}
+		seen[key] = first
+		if now.Sub(first) >= grace {
+			out = append(out, key)
+		}
+	}
+	t.firstSeen = seen
+	return out
 }
 
 // NewReconciler creates a new reconciler with the given configuration.
@@ -35,32 +82,55 @@ type Reconciler struct {
 func NewReconciler(cfg ReconcilerConfig, sync *StateSynchronizer, listWindows WindowLister) *Reconciler {
 	interval := cfg.Interval
 	if interval <= 0 {
-		interval = 10 * time.Second
+		interval = defaultReconcileInterval
 	}
 
-	return &Reconciler{
+	r := &Reconciler{
 		interval:        interval,
 		cleanupOrphaned: cfg.CleanupOrphaned,
+		runOnStart:      cfg.RunOnStart,
 		sync:            sync,
 		listWindows:     listWindows,
 		logger:          cfg.Logger,
+		orphanGrace:     cfg.OrphanGrace,
+		now:             time.Now,
 	}
+	r.pass = r.reconcile
+	return r
 }
 
 // Run starts the reconciliation loop. Blocks until context is cancelled.
 func (r *Reconciler) Run(ctx context.Context) {
+	r.startupPass()
+	r.loop(ctx)
+}
+
+// Start runs the startup pass, if enabled, before returning and then keeps
+// reconciling in the background until ctx is cancelled. The daemon uses it
+// so IPC clients never see state left over from a previous lifecycle.
+func (r *Reconciler) Start(ctx context.Context) {
+	r.startupPass()
+	go r.loop(ctx)
+}
+
+func (r *Reconciler) startupPass() {
+	r.logger.Info("reconciler started", "interval", r.interval, "run_on_start", r.runOnStart, "orphan_grace", r.orphanGrace)
+	if r.runOnStart {
+		r.pass()
+	}
+}
+
+func (r *Reconciler) loop(ctx context.Context) {
 	ticker := time.NewTicker(r.interval)
 	defer ticker.Stop()
 
-	r.logger.Info("reconciler started", "interval", r.interval)
-
 	for {
 		select {
 		case <-ctx.Done():
 			r.logger.Info("reconciler stopped")
 			return
 		case <-ticker.C:
-			r.reconcile()
+			r.pass()
 		}
 	}
 }
@@ -81,10 +151,12 @@ func (r *Reconciler) reconcile() {
 		return
 	}
 
+	now := r.now()
 	if len(expected) == 0 {
+		r.orphanedWindows.due(nil, r.orphanGrace, now)
 		// No slots tracked, check for orphaned sessions
 		if r.cleanupOrphaned {
-			r.sync.CleanupOrphanedSessions()
+			r.cleanupOrphanedSessions(now)
 		}
 		return
 	}
@@ -110,8 +182,12 @@ func (r *Reconciler) reconcile() {
 		}
 	}
 
-	// Clean up orphaned slots
-	for _, windowID := range orphanedWindows {
+	// Clean up slots orphaned for the whole grace period
+	due := r.orphanedWindows.due(orphanedWindows, r.orphanGrace, now)
+	if waiting := len(orphanedWindows) - len(due); waiting > 0 {
+		r.logger.Debug("reconciler: orphaned slots within grace period", "count", waiting)
+	}
+	for _, windowID := range due {
 		slot := expected[windowID]
 		r.logger.Info("reconciler: orphaned slot detected",
 			"window_id", windowID,
@@ -122,15 +198,26 @@ func (r *Reconciler) reconcile() {
 
 	// Clean up orphaned tmux sessions
 	if r.cleanupOrphaned {
-		if err := r.sync.CleanupOrphanedSessions(); err != nil {
-			r.logger.Warn("reconciler: failed to cleanup orphaned sessions", "error", err)
-		}
+		r.cleanupOrphanedSessions(now)
+	}
+}
+
+// cleanupOrphanedSessions kills the sessions orphaned for the whole grace
+// period.
+func (r *Reconciler) cleanupOrphanedSessions(now time.Time) {
+	sessions, err := r.sync.OrphanedSessions()
+	if err != nil {
+		r.logger.Warn("reconciler: failed to cleanup orphaned sessions", "error", err)
+		return
+	}
+	for _, session := range r.orphanedSessions.due(sessions, r.orphanGrace, now) {
+		r.sync.KillOrphanedSession(session)
 	}
 }
 
 // ReconcileNow triggers an immediate reconciliation pass.
 func (r *Reconciler) ReconcileNow() {
-	r.reconcile()
+	r.pass()
 }
 
 // WindowListerFromBackend creates a reconciler WindowLister from a platform backend.
@@ -0,0 +1,208 @@
+package daemon
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/1broseidon/termtile/internal/agent"
+	"github.com/1broseidon/termtile/internal/config"
+	"github.com/1broseidon/termtile/internal/workspace"
+)
+
+// countingReconciler builds a reconciler from the reconciler config block
+// whose passes are reported on the returned channel instead of touching the
+// registry.
+func countingReconciler(rc config.ReconcilerConfig) (*Reconciler, <-chan struct{}) {
+	r := NewReconciler(ReconcilerConfig{
+		Interval:   rc.GetInterval(),
+		RunOnStart: rc.GetRunOnStart(),
+		Logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}, nil, nil)
+	passes := make(chan struct{}, 16)
+	r.pass = func() { passes <- struct{}{} }
+	return r, passes
+}
+
+func TestNewReconciler_IntervalFromConfig(t *testing.T) {
+	r, _ := countingReconciler(config.ReconcilerConfig{IntervalSeconds: 45})
+	if r.interval != 45*time.Second {
+		t.Fatalf("interval = %v, want 45s", r.interval)
+	}
+
+	r, _ = countingReconciler(config.ReconcilerConfig{})
+	if want := defaultReconcileInterval; r.interval != want {
+		t.Fatalf("interval = %v, want default %v", r.interval, want)
+	}
+}
+
+func TestReconcilerRun_StartupPass(t *testing.T) {
+	off := false
+	for _, tt := range []struct {
+		name string
+		rc   config.ReconcilerConfig
+		want bool
+	}{
+		{name: "default", rc: config.ReconcilerConfig{IntervalSeconds: 3600}, want: true},
+		{name: "disabled", rc: config.ReconcilerConfig{IntervalSeconds: 3600, RunOnStart: &off}, want: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r, passes := countingReconciler(tt.rc)
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+			go func() {
+				r.Run(ctx)
+				close(done)
+			}()
+
+			select {
+			case <-passes:
+				if !tt.want {
+					t.Fatal("reconciler ran a startup pass with run_on_start disabled")
+				}
+			case <-time.After(100 * time.Millisecond):
+				if tt.want {
+					t.Fatal("reconciler did not run a startup pass")
+				}
+			}
+			cancel()
+			<-done
+		})
+	}
+}
+
+func TestReconcilerStart_StartupPassIsSynchronous(t *testing.T) {
+	r, passes := countingReconciler(config.ReconcilerConfig{IntervalSeconds: 3600})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r.Start(ctx)
+	select {
+	case <-passes:
+	default:
+		t.Fatal("Start returned before the startup pass ran")
+	}
+}
+
+func TestReconcilerRun_TicksAtInterval(t *testing.T) {
+	r, passes := countingReconciler(config.ReconcilerConfig{})
+	r.interval = 10 * time.Millisecond
+	r.runOnStart = false
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Run(ctx)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-passes:
+		case <-time.After(time.Second):
+			t.Fatalf("got %d passes, want a pass every tick", i)
+		}
+	}
+}
+
+func TestReconcile_OrphanGraceKeepsYoungSlots(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	// Window 1 is live; window 2 is tracked but its window is gone.
+	for _, wid := range []uint32{1, 2} {
+		if err := workspace.SetSlotInfo(wid, int(wid)-1, "", 0); err != nil {
+			t.Fatalf("SetSlotInfo(%d): %v", wid, err)
+		}
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	r := NewReconciler(ReconcilerConfig{
+		OrphanGrace: 30 * time.Second,
+		Logger:      logger,
+	}, NewStateSynchronizer(nil, logger), func() ([]uint32, error) {
+		return []uint32{1}, nil
+	})
+	start := time.Unix(1_700_000_000, 0)
+	now := start
+	r.now = func() time.Time { return now }
+
+	r.ReconcileNow()
+	if _, ok := workspace.GetSlotByWindowID(2); !ok {
+		t.Fatal("slot orphaned for less than the grace period was removed")
+	}
+
+	now = start.Add(29 * time.Second)
+	r.ReconcileNow()
+	if _, ok := workspace.GetSlotByWindowID(2); !ok {
+		t.Fatal("slot removed before the grace period elapsed")
+	}
+
+	now = start.Add(30 * time.Second)
+	r.ReconcileNow()
+	if _, ok := workspace.GetSlotByWindowID(2); ok {
+		t.Fatal("slot orphaned for the whole grace period was not removed")
+	}
+	if _, ok := workspace.GetSlotByWindowID(1); !ok {
+		t.Fatal("live slot was removed")
+	}
+}
+
+// sessionMux is a multiplexer with a fixed session list that records kills.
+type sessionMux struct {
+	agent.Multiplexer
+	sessions []string
+	killed   []string
+}
+
+func (m *sessionMux) ListSessions() ([]string, error) { return m.sessions, nil }
+
+func (m *sessionMux) KillSession(session string) error {
+	m.killed = append(m.killed, session)
+	return nil
+}
+
+func TestReconcile_KeepsDetachedSessions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	if err := workspace.SetSlotInfo(1, 0, "termtile-ws-0", 0); err != nil {
+		t.Fatalf("SetSlotInfo: %v", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	mux := &sessionMux{sessions: []string{"termtile-ws-0", "termtile-ws-1", "termtile-ws-2"}}
+	sync := NewStateSynchronizer(mux, logger)
+	sync.KeepSessions(func(session string) bool { return session == "termtile-ws-1" })
+	r := NewReconciler(ReconcilerConfig{
+		CleanupOrphaned: true,
+		Logger:          logger,
+	}, sync, func() ([]uint32, error) {
+		return []uint32{1}, nil
+	})
+
+	r.ReconcileNow()
+	if len(mux.killed) != 1 || mux.killed[0] != "termtile-ws-2" {
+		t.Fatalf("killed = %v, want only the unkept orphan termtile-ws-2", mux.killed)
+	}
+}
+
+func TestOrphanTracker_ForgetsEntriesNoLongerOrphaned(t *testing.T) {
+	var tr orphanTracker[string]
+	start := time.Unix(1_700_000_000, 0)
+	grace := 10 * time.Second
+
+	if due := tr.due([]string{"a"}, grace, start); len(due) != 0 {
+		t.Fatalf("due = %v on first sighting, want none", due)
+	}
+	// "a" is tracked again for one pass, so its clock restarts.
+	tr.due(nil, grace, start.Add(5*time.Second))
+	if due := tr.due([]string{"a"}, grace, start.Add(12*time.Second)); len(due) != 0 {
+		t.Fatalf("due = %v after re-orphaning, want none", due)
+	}
+	if due := tr.due([]string{"a"}, grace, start.Add(22*time.Second)); len(due) != 1 || due[0] != "a" {
+		t.Fatalf("due = %v, want [a]", due)
+	}
+
+	var immediate orphanTracker[string]
+	if due := immediate.due([]string{"b"}, 0, start); len(due) != 1 {
+		t.Fatalf("due = %v with no grace, want [b]", due)
+	}
+}
@@ -12,18 +12,28 @@ import (
 
 // StateSynchronizer handles cleanup when windows close or state drifts.
 type StateSynchronizer struct {
-	tmux   *agent.TmuxMultiplexer
+	mux    agent.Multiplexer
 	logger *slog.Logger
+	// keepSession reports sessions that live without a registry slot by
+	// design; OrphanedSessions never returns them.
+	keepSession func(session string) bool
 }
 
-// NewStateSynchronizer creates a new state synchronizer.
-func NewStateSynchronizer(logger *slog.Logger) *StateSynchronizer {
+// NewStateSynchronizer creates a new state synchronizer that manages
+// sessions through mux.
+func NewStateSynchronizer(mux agent.Multiplexer, logger *slog.Logger) *StateSynchronizer {
 	return &StateSynchronizer{
-		tmux:   agent.NewTmuxMultiplexer(),
+		mux:    mux,
 		logger: logger,
 	}
 }
 
+// KeepSessions makes OrphanedSessions skip the sessions keep reports true
+// for, such as detached agent sessions, which have no window.
+func (s *StateSynchronizer) KeepSessions(keep func(session string) bool) {
+	s.keepSession = keep
+}
+
 // HandleWindowClosed is called when a tracked window is destroyed.
 // It cleans up the orphaned tmux session and updates the registry.
 func (s *StateSynchronizer) HandleWindowClosed(windowID uint32) {
@@ -40,8 +50,8 @@ func (s *StateSynchronizer) HandleWindowClosed(windowID uint32) {
 
 	// Kill orphaned tmux session if it exists
 	if slot.SessionName != "" {
-		if exists, _ := s.tmux.HasSession(slot.SessionName); exists {
-			if err := s.tmux.KillSession(slot.SessionName); err != nil {
+		if exists, _ := s.mux.HasSession(slot.SessionName); exists {
+			if err := s.mux.KillSession(slot.SessionName); err != nil {
 				s.logger.Warn("failed to kill session",
 					"session", slot.SessionName,
 					"error", err)
@@ -108,6 +118,10 @@ func (s *StateSynchronizer) RenumberSlots(desktop int) error {
 
 		oldName := slot.SessionName
 		newName := agent.SessionName(wsInfo.Name, newIndex)
+		if oldName != "" && !agent.IsDerivedSessionName(oldName) {
+			// Custom session names are not tied to the slot index.
+			newName = oldName
+		}
 
 		s.logger.Debug("renumbering slot",
 			"window_id", slot.WindowID,
@@ -118,8 +132,8 @@ func (s *StateSynchronizer) RenumberSlots(desktop int) error {
 
 		// Rename tmux session if it exists
 		if oldName != "" && oldName != newName {
-			if exists, _ := s.tmux.HasSession(oldName); exists {
-				if err := s.tmux.RenameSession(oldName, newName); err != nil {
+			if exists, _ := s.mux.HasSession(oldName); exists {
+				if err := s.mux.RenameSession(oldName, newName); err != nil {
 					return fmt.Errorf("rename session %s -> %s: %w", oldName, newName, err)
 				}
 			}
@@ -134,41 +148,48 @@ func (s *StateSynchronizer) RenumberSlots(desktop int) error {
 	return nil
 }
 
-// CleanupOrphanedSessions removes tmux sessions that don't have corresponding windows.
-// Only performs cleanup if there are slots registered - otherwise we have no tracking
-// data and would incorrectly kill all sessions.
-func (s *StateSynchronizer) CleanupOrphanedSessions() error {
+// OrphanedSessions returns the termtile sessions that don't have
+// corresponding windows. It returns none while no slots are registered -
+// otherwise we have no tracking data and would report every session.
+func (s *StateSynchronizer) OrphanedSessions() ([]string, error) {
 	// Check if we have any slots registered - if not, skip cleanup
 	// since we don't have tracking data yet
 	allSlots, err := workspace.GetAllSlots()
 	if err != nil {
-		return fmt.Errorf("get slots: %w", err)
+		return nil, fmt.Errorf("get slots: %w", err)
 	}
 	if len(allSlots) == 0 {
 		// No slots tracked, skip orphan cleanup to avoid killing valid sessions
-		return nil
+		return nil, nil
 	}
 
-	sessions, err := s.tmux.ListSessions()
+	sessions, err := s.mux.ListSessions()
 	if err != nil {
-		return fmt.Errorf("list sessions: %w", err)
+		return nil, fmt.Errorf("list sessions: %w", err)
 	}
 
+	var orphaned []string
 	for _, session := range sessions {
 		// Only process termtile sessions
 		if !strings.HasPrefix(session, "termtile-") {
 			continue
 		}
-
+		if s.keepSession != nil && s.keepSession(session) {
+			continue
+		}
 		if !workspace.HasSessionInRegistry(session) {
-			s.logger.Info("killing orphaned tmux session", "session", session)
-			if err := s.tmux.KillSession(session); err != nil {
-				s.logger.Warn("failed to kill orphaned session",
-					"session", session,
-					"error", err)
-			}
+			orphaned = append(orphaned, session)
 		}
 	}
+	return orphaned, nil
+}
 
-	return nil
+// KillOrphanedSession kills a session OrphanedSessions returned.
+func (s *StateSynchronizer) KillOrphanedSession(session string) {
+	s.logger.Info("killing orphaned tmux session", "session", session)
+	if err := s.mux.KillSession(session); err != nil {
+		s.logger.Warn("failed to kill orphaned session",
+			"session", session,
+			"error", err)
+	}
 }
@@ -0,0 +1,17 @@
+// Package chord holds the key-chord vocabulary shared by hotkey validation
+// in internal/config and registration in internal/hotkeys.
+package chord
+
+import "strings"
+
+// modifiers are the modifier names keybind.ParseString accepts.
+var modifiers = map[string]bool{
+	"shift": true, "lock": true, "control": true, "any": true,
+	"mod1": true, "mod2": true, "mod3": true, "mod4": true, "mod5": true,
+}
+
+// IsModifier reports whether name (case-insensitive) is a chord modifier
+// such as "Mod4" or "shift".
+func IsModifier(name string) bool {
+	return modifiers[strings.ToLower(name)]
+}
@@ -0,0 +1,19 @@
+package chord
+
+import "testing"
+
+func TestIsModifier(t *testing.T) {
+	for name, want := range map[string]bool{
+		"Mod4":    true,
+		"shift":   true,
+		"CONTROL": true,
+		"any":     true,
+		"t":       false,
+		"super":   false,
+		"":        false,
+	} {
+		if got := IsModifier(name); got != want {
+			t.Errorf("IsModifier(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -1,12 +1,19 @@
 package hotkeys
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/1broseidon/termtile/internal/config"
+	"github.com/1broseidon/termtile/internal/hotkeys/chord"
 	"github.com/1broseidon/termtile/internal/movemode"
 	"github.com/1broseidon/termtile/internal/platform"
+	"github.com/1broseidon/termtile/internal/tiling"
 	"github.com/BurntSushi/xgb/xproto"
 	"github.com/BurntSushi/xgbutil"
 	"github.com/BurntSushi/xgbutil/keybind"
@@ -30,6 +37,56 @@ type Handler struct {
 	root     xproto.Window
 	tiler    Tiler
 	moveMode *movemode.Mode
+
+	mu sync.Mutex
+	// bindings maps a normalized key sequence (one chord, or a prefix chord
+	// and a key separated by a space) to the action bound to it.
+	bindings  map[string]binding
+	conflicts []*ConflictError
+
+	// prefixes maps a normalized prefix chord to the sequences started by it.
+	prefixes map[string]*prefixBinding
+	// pending is the prefix awaiting its second key, if any.
+	pending      *prefixBinding
+	pendingTimer *time.Timer
+	// pendingGen numbers each begun sequence, so a timeout posted for an
+	// earlier one cannot end a newer one.
+	pendingGen uint32
+	// sequenceTimeout bounds the wait for the second key; zero means
+	// DefaultSequenceTimeout.
+	sequenceTimeout time.Duration
+
+	// grabWindow receives key events while a sequence awaits its second key.
+	grabWindow xproto.Window
+	// timeoutAtom types the timeout ClientMessage sent to grabWindow.
+	timeoutAtom xproto.Atom
+
+	// Seams for tests; default to X11 passive and active keyboard grabs,
+	// and to posting sequence timeouts onto the X event loop.
+	connect       func(keySequence string, callback func()) error
+	grabNextKey   func() error
+	ungrabNextKey func()
+	postTimeout   func(gen uint32)
+}
+
+type binding struct {
+	action      string
+	keySequence string
+}
+
+// ConflictError reports a chord that is already bound to another termtile
+// action.
+type ConflictError struct {
+	Action      string
+	KeySequence string
+	// BoundAction and BoundKeySequence describe the existing binding, which
+	// may spell the same chord differently (e.g. "control-Mod4-t").
+	BoundAction      string
+	BoundKeySequence string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s %q conflicts with %s %q", e.Action, e.KeySequence, e.BoundAction, e.BoundKeySequence)
 }
 
 var ignoreModsOnce sync.Once
@@ -47,18 +104,23 @@ func NewHandler(backend platform.Backend, tiler Tiler) *Handler {
 		configureIgnoreMods(xu)
 	})
 
-	return &Handler{
+	h := &Handler{
 		xu:    xu,
 		root:  root,
 		tiler: tiler,
 	}
+	h.connect = h.connectX11
+	h.grabNextKey = h.grabKeyboardX11
+	h.ungrabNextKey = h.ungrabKeyboardX11
+	h.postTimeout = h.postTimeoutX11
+	return h
 }
 
 // Register registers the tiling hotkey.
 func (h *Handler) Register(keySequence string) error {
-	return h.RegisterFunc(keySequence, func() {
+	return h.RegisterFunc("hotkey", keySequence, func() {
 		log.Println("Tiling hotkey triggered!")
-		if err := h.tiler.TileCurrentMonitor(); err != nil {
+		if err := h.tiler.TileCurrentMonitor(); err != nil && !errors.Is(err, tiling.ErrNoTerminals) {
 			log.Printf("Tiling failed: %v", err)
 		}
 	})
@@ -79,7 +141,7 @@ func (h *Handler) RegisterMoveMode(keySequence string) error {
 
 	// Register only the toggle hotkey - navigation keys are handled
 	// via keyboard grab when move mode is active
-	if err := h.RegisterFunc(keySequence, func() {
+	if err := h.RegisterFunc("move_mode_hotkey", keySequence, func() {
 		if h.moveMode.IsActive() {
 			h.moveMode.Exit()
 		} else {
@@ -94,13 +156,107 @@ func (h *Handler) RegisterMoveMode(keySequence string) error {
 	return nil
 }
 
-// RegisterFunc registers an arbitrary hotkey callback.
-func (h *Handler) RegisterFunc(keySequence string, callback func()) error {
+// RegisterFunc registers an arbitrary hotkey callback for action, which
+// names the binding in conflict reports (typically its config key).
+// keySequence is a chord ("Mod4-Mod1-t") or a prefix chord and a key
+// ("Mod4-space g"). It returns a *ConflictError if the sequence is already
+// bound to another action, or if it is a chord that is also used as a prefix
+// (or vice versa).
+func (h *Handler) RegisterFunc(action, keySequence string, callback func()) error {
+	steps, err := config.ParseHotkey(keySequence)
+	if err != nil {
+		return err
+	}
+	chords := make([]string, len(steps))
+	for i, step := range steps {
+		chords[i] = normalizeChord(step)
+	}
+	key := strings.Join(chords, " ")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if existing, ok := h.conflictLocked(chords); ok {
+		err := &ConflictError{
+			Action:           action,
+			KeySequence:      keySequence,
+			BoundAction:      existing.action,
+			BoundKeySequence: existing.keySequence,
+		}
+		h.conflicts = append(h.conflicts, err)
+		return err
+	}
+
+	b := binding{action: action, keySequence: keySequence}
+	if len(chords) == 1 {
+		if err := h.connect(keySequence, callback); err != nil {
+			return err
+		}
+	} else if err := h.addSequenceLocked(steps[0], chords, b, callback); err != nil {
+		return err
+	}
+	if h.bindings == nil {
+		h.bindings = make(map[string]binding)
+	}
+	h.bindings[key] = b
+	return nil
+}
+
+// conflictLocked returns the existing binding that chords would clash with.
+func (h *Handler) conflictLocked(chords []string) (binding, bool) {
+	if existing, ok := h.bindings[strings.Join(chords, " ")]; ok {
+		return existing, true
+	}
+	if len(chords) == 1 {
+		// A plain chord cannot also start a sequence.
+		if prefix, ok := h.prefixes[chords[0]]; ok {
+			return prefix.first, true
+		}
+		return binding{}, false
+	}
+	existing, ok := h.bindings[chords[0]]
+	return existing, ok
+}
+
+// Conflicts returns the conflicts detected by registrations so far, in the
+// order they were attempted.
+func (h *Handler) Conflicts() []*ConflictError {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]*ConflictError(nil), h.conflicts...)
+}
+
+func (h *Handler) connectX11(keySequence string, callback func()) error {
 	return keybind.KeyPressFun(func(xu *xgbutil.XUtil, ev xevent.KeyPressEvent) {
 		callback()
 	}).Connect(h.xu, h.root, keySequence, true)
 }
 
+// normalizeChord canonicalizes a key sequence such as "Mod4-Shift-T" the
+// way keybind.ParseString reads it: modifiers are case-insensitive and
+// order-independent, and only the first key counts. Keys are lowercased,
+// since keysyms differing only in case share a keycode.
+func normalizeChord(keySequence string) string {
+	seen := make(map[string]bool)
+	var mods []string
+	key := ""
+	for _, part := range strings.Split(strings.TrimSpace(keySequence), "-") {
+		lower := strings.ToLower(part)
+		if chord.IsModifier(lower) {
+			if !seen[lower] {
+				seen[lower] = true
+				mods = append(mods, lower)
+			}
+			continue
+		}
+		if key == "" {
+			key = lower
+		}
+	}
+	sort.Strings(mods)
+	return strings.Join(append(mods, key), "-")
+}
+
 func configureIgnoreMods(xu *xgbutil.XUtil) {
 	// Always ignore CapsLock.
 	caps := uint16(xproto.ModMaskLock)
@@ -0,0 +1,259 @@
+package hotkeys
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// newTestHandler returns a Handler whose X11 grabs are recorded instead of
+// sent to a server.
+func newTestHandler(grabbed *[]string) *Handler {
+	return &Handler{
+		connect: func(keySequence string, _ func()) error {
+			*grabbed = append(*grabbed, keySequence)
+			return nil
+		},
+	}
+}
+
+func TestNormalizeChord(t *testing.T) {
+	cases := map[string]string{
+		"Mod4-t":               "mod4-t",
+		"Shift-Mod4-T":         "mod4-shift-t",
+		"mod4-shift-t":         "mod4-shift-t",
+		"Mod4-Mod4-Control-t":  "control-mod4-t",
+		"Mod4-Return":          "mod4-return",
+		"F12":                  "f12",
+		"Mod1-Shift-Tab-extra": "mod1-shift-tab",
+	}
+	for in, want := range cases {
+		if got := normalizeChord(in); got != want {
+			t.Errorf("normalizeChord(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRegisterFunc_DetectsConflicts(t *testing.T) {
+	var grabbed []string
+	h := newTestHandler(&grabbed)
+
+	if err := h.RegisterFunc("hotkey", "Mod4-Mod1-t", func() {}); err != nil {
+		t.Fatalf("RegisterFunc(hotkey) error = %v", err)
+	}
+	if err := h.RegisterFunc("undo_hotkey", "Mod4-Mod1-u", func() {}); err != nil {
+		t.Fatalf("RegisterFunc(undo_hotkey) error = %v", err)
+	}
+
+	// Same chord spelled differently.
+	err := h.RegisterFunc("palette_hotkey", "mod1-MOD4-T", func() {})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("RegisterFunc(palette_hotkey) error = %v, want *ConflictError", err)
+	}
+	want := ConflictError{
+		Action:           "palette_hotkey",
+		KeySequence:      "mod1-MOD4-T",
+		BoundAction:      "hotkey",
+		BoundKeySequence: "Mod4-Mod1-t",
+	}
+	if *conflict != want {
+		t.Fatalf("conflict = %+v, want %+v", *conflict, want)
+	}
+
+	if err := h.RegisterFunc("cycle_layout_hotkey", "Mod4-Mod1-u", func() {}); err == nil {
+		t.Fatal("RegisterFunc(cycle_layout_hotkey) error = nil, want conflict with undo_hotkey")
+	}
+
+	conflicts := h.Conflicts()
+	if len(conflicts) != 2 {
+		t.Fatalf("Conflicts() = %v, want 2 entries", conflicts)
+	}
+	if conflicts[0].Action != "palette_hotkey" || conflicts[1].BoundAction != "undo_hotkey" {
+		t.Fatalf("Conflicts() = %v, want palette_hotkey then cycle_layout_hotkey", conflicts)
+	}
+
+	// Conflicting chords are never grabbed, so the first binding keeps working.
+	if len(grabbed) != 2 {
+		t.Fatalf("grabbed %v, want only the two non-conflicting chords", grabbed)
+	}
+}
+
+func TestRegisterFunc_FailedGrabDoesNotBind(t *testing.T) {
+	fail := true
+	h := &Handler{
+		connect: func(string, func()) error {
+			if fail {
+				return errors.New("grab failed")
+			}
+			return nil
+		},
+	}
+
+	if err := h.RegisterFunc("hotkey", "Mod4-t", func() {}); err == nil {
+		t.Fatal("RegisterFunc() error = nil, want grab failure")
+	}
+	fail = false
+	if err := h.RegisterFunc("undo_hotkey", "Mod4-t", func() {}); err != nil {
+		t.Fatalf("RegisterFunc() after failed grab error = %v, want nil", err)
+	}
+	if len(h.Conflicts()) != 0 {
+		t.Fatalf("Conflicts() = %v, want none", h.Conflicts())
+	}
+}
+
+// sequenceHandler returns a test Handler that records prefix callbacks by
+// key sequence so tests can simulate pressing them.
+func sequenceHandler(pressed map[string]func(), grabs *int) *Handler {
+	return &Handler{
+		connect: func(keySequence string, callback func()) error {
+			pressed[keySequence] = callback
+			return nil
+		},
+		grabNextKey:   func() error { *grabs++; return nil },
+		ungrabNextKey: func() { *grabs-- },
+		postTimeout:   func(uint32) {},
+	}
+}
+
+func TestRegisterFunc_SequenceDispatch(t *testing.T) {
+	pressed := map[string]func(){}
+	grabs := 0
+	h := sequenceHandler(pressed, &grabs)
+
+	var fired []string
+	for _, tc := range []struct{ action, seq string }{
+		{"layout_hotkeys.grid", "Mod4-space g"},
+		{"layout_hotkeys.columns", "Mod4-space Shift-c"},
+	} {
+		action := tc.action
+		if err := h.RegisterFunc(action, tc.seq, func() { fired = append(fired, action) }); err != nil {
+			t.Fatalf("RegisterFunc(%q) error = %v", tc.seq, err)
+		}
+	}
+	if len(pressed) != 1 || pressed["Mod4-space"] == nil {
+		t.Fatalf("grabbed %v, want the shared prefix Mod4-space once", pressed)
+	}
+
+	// The second key alone does nothing without the prefix.
+	h.sequenceKeyPressed("g")
+	if len(fired) != 0 {
+		t.Fatalf("fired %v without prefix", fired)
+	}
+
+	pressed["Mod4-space"]()
+	if grabs != 1 {
+		t.Fatalf("grabs = %d after prefix, want 1", grabs)
+	}
+	// Modifier presses are ignored, and a still-held prefix modifier is
+	// accepted with the second key.
+	h.sequenceKeyPressed("Super_L")
+	h.sequenceKeyPressed("Mod4-g")
+	if len(fired) != 1 || fired[0] != "layout_hotkeys.grid" || grabs != 0 {
+		t.Fatalf("fired %v grabs %d, want grid once and keyboard released", fired, grabs)
+	}
+
+	pressed["Mod4-space"]()
+	h.sequenceKeyPressed("Shift-c")
+	if len(fired) != 2 || fired[1] != "layout_hotkeys.columns" {
+		t.Fatalf("fired %v, want columns second", fired)
+	}
+
+	// An unbound key ends the sequence without dispatching.
+	pressed["Mod4-space"]()
+	h.sequenceKeyPressed("x")
+	h.sequenceKeyPressed("g")
+	if len(fired) != 2 || grabs != 0 {
+		t.Fatalf("fired %v grabs %d, want nothing after unbound key", fired, grabs)
+	}
+}
+
+func TestRegisterFunc_SequenceTimeout(t *testing.T) {
+	pressed := map[string]func(){}
+	grabs := 0
+	h := sequenceHandler(pressed, &grabs)
+	h.sequenceTimeout = 20 * time.Millisecond
+	posted := make(chan uint32, 2)
+	h.postTimeout = func(gen uint32) { posted <- gen }
+
+	fired := 0
+	if err := h.RegisterFunc("layout_hotkeys.grid", "Mod4-space g", func() { fired++ }); err != nil {
+		t.Fatalf("RegisterFunc error = %v", err)
+	}
+
+	pressed["Mod4-space"]()
+	var gen uint32
+	select {
+	case gen = <-posted:
+	case <-time.After(2 * time.Second):
+		t.Fatal("sequence timeout was never posted")
+	}
+	// The timer only posts; the keyboard stays grabbed until the event loop
+	// handles the timeout.
+	h.mu.Lock()
+	pending := h.pending
+	h.mu.Unlock()
+	if pending == nil || grabs != 1 {
+		t.Fatalf("pending=%v grabs=%d after the timer fired, want still pending and grabbed", pending, grabs)
+	}
+
+	h.expireSequence(gen)
+	if h.pending != nil || grabs != 0 {
+		t.Fatalf("pending=%v grabs=%d after expiry, want released", h.pending, grabs)
+	}
+	h.sequenceKeyPressed("g")
+	if fired != 0 {
+		t.Fatalf("fired %d times after timeout, want 0", fired)
+	}
+}
+
+func TestRegisterFunc_StaleSequenceTimeoutIgnored(t *testing.T) {
+	pressed := map[string]func(){}
+	grabs := 0
+	h := sequenceHandler(pressed, &grabs)
+
+	fired := 0
+	if err := h.RegisterFunc("layout_hotkeys.grid", "Mod4-space g", func() { fired++ }); err != nil {
+		t.Fatalf("RegisterFunc error = %v", err)
+	}
+
+	pressed["Mod4-space"]()
+	stale := h.pendingGen
+	h.sequenceKeyPressed("x")
+	pressed["Mod4-space"]()
+
+	// A timeout posted for the first sequence arrives after the second began.
+	h.expireSequence(stale)
+	h.sequenceKeyPressed("g")
+	if fired != 1 {
+		t.Fatalf("fired %d times, want the second sequence to survive the stale timeout", fired)
+	}
+}
+
+func TestRegisterFunc_SequencePrefixConflicts(t *testing.T) {
+	pressed := map[string]func(){}
+	grabs := 0
+	h := sequenceHandler(pressed, &grabs)
+
+	if err := h.RegisterFunc("layout_hotkeys.grid", "Mod4-space g", func() {}); err != nil {
+		t.Fatalf("RegisterFunc(sequence) error = %v", err)
+	}
+	var conflict *ConflictError
+	if err := h.RegisterFunc("undo_hotkey", "mod4-Space", func() {}); !errors.As(err, &conflict) || conflict.BoundAction != "layout_hotkeys.grid" {
+		t.Fatalf("RegisterFunc(chord = prefix) error = %v, want conflict with layout_hotkeys.grid", err)
+	}
+	if err := h.RegisterFunc("palette_hotkey", "Mod4-space G", func() {}); !errors.As(err, &conflict) {
+		t.Fatalf("RegisterFunc(duplicate sequence) error = %v, want conflict", err)
+	}
+
+	if err := h.RegisterFunc("hotkey", "Mod4-Mod1-t", func() {}); err != nil {
+		t.Fatalf("RegisterFunc(hotkey) error = %v", err)
+	}
+	if err := h.RegisterFunc("layout_hotkeys.columns", "Mod1-mod4-T c", func() {}); !errors.As(err, &conflict) || conflict.BoundAction != "hotkey" {
+		t.Fatalf("RegisterFunc(prefix = chord) error = %v, want conflict with hotkey", err)
+	}
+
+	if err := h.RegisterFunc("undo_hotkey", "Mod4-space g x", func() {}); err == nil {
+		t.Fatal("RegisterFunc(three steps) error = nil")
+	}
+}
@@ -0,0 +1,293 @@
+package hotkeys
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/xgb/xproto"
+	"github.com/BurntSushi/xgbutil"
+	"github.com/BurntSushi/xgbutil/keybind"
+	"github.com/BurntSushi/xgbutil/xevent"
+	"github.com/BurntSushi/xgbutil/xprop"
+)
+
+// DefaultSequenceTimeout is how long a pressed prefix waits for its second
+// key before the sequence is abandoned.
+const DefaultSequenceTimeout = 1500 * time.Millisecond
+
+// sequenceModMask selects the modifiers that distinguish the second key of a
+// sequence; lock-style modifiers (CapsLock, NumLock) are ignored.
+const sequenceModMask = xproto.ModMaskShift | xproto.ModMaskControl |
+	xproto.ModMask1 | xproto.ModMask4 | xproto.ModMask5
+
+// sequenceTimeoutAtom types the ClientMessage that carries a sequence
+// timeout from its timer onto the X event loop.
+const sequenceTimeoutAtom = "_TERMTILE_SEQUENCE_TIMEOUT"
+
+// prefixBinding holds the sequences that share one prefix chord.
+type prefixBinding struct {
+	keySequence string
+	// mods are the prefix's modifiers, which the user may still be holding
+	// when pressing the second key.
+	mods map[string]bool
+	keys map[string]func()
+	// first is the first sequence registered under this prefix, reported
+	// when a plain chord conflicts with the prefix.
+	first binding
+}
+
+// addSequenceLocked registers the second step of a two-step sequence,
+// grabbing the prefix chord the first time it is used.
+func (h *Handler) addSequenceLocked(prefixSequence string, chords []string, b binding, callback func()) error {
+	prefix, ok := h.prefixes[chords[0]]
+	if !ok {
+		mods, _ := splitChord(chords[0])
+		prefix = &prefixBinding{
+			keySequence: prefixSequence,
+			mods:        make(map[string]bool, len(mods)),
+			keys:        make(map[string]func()),
+			first:       b,
+		}
+		for _, mod := range mods {
+			prefix.mods[mod] = true
+		}
+		if err := h.connect(prefixSequence, func() { h.beginSequence(prefix) }); err != nil {
+			return err
+		}
+		if h.prefixes == nil {
+			h.prefixes = make(map[string]*prefixBinding)
+		}
+		h.prefixes[chords[0]] = prefix
+	}
+	prefix.keys[chords[1]] = callback
+	return nil
+}
+
+// beginSequence is called when a prefix chord is pressed. It waits for the
+// next key press until the sequence timeout expires. The timer only posts
+// the timeout; the keyboard is released on the X event loop.
+func (h *Handler) beginSequence(prefix *prefixBinding) {
+	h.mu.Lock()
+	h.stopPendingLocked()
+	h.pending = prefix
+	h.pendingGen++
+	gen := h.pendingGen
+	timeout := h.sequenceTimeout
+	if timeout <= 0 {
+		timeout = DefaultSequenceTimeout
+	}
+	h.pendingTimer = time.AfterFunc(timeout, func() { h.postTimeout(gen) })
+	h.mu.Unlock()
+
+	if h.grabNextKey != nil {
+		if err := h.grabNextKey(); err != nil {
+			log.Printf("Hotkey sequence %s: failed to grab keyboard: %v", prefix.keySequence, err)
+			h.expireSequence(gen)
+		}
+	}
+}
+
+// sequenceKeyPressed dispatches the second key of a pending sequence. chord
+// is the pressed key with its modifiers, e.g. "mod4-g". Presses of bare
+// modifier keys are ignored so the user can release or re-press them.
+func (h *Handler) sequenceKeyPressed(chord string) {
+	chord = normalizeChord(chord)
+	mods, key := splitChord(chord)
+	if isModifierKeyName(key) {
+		return
+	}
+
+	h.mu.Lock()
+	prefix := h.pending
+	if prefix == nil {
+		h.mu.Unlock()
+		return
+	}
+	h.stopPendingLocked()
+	h.pending = nil
+	callback, ok := prefix.keys[chord]
+	if !ok {
+		// Accept the bare key while prefix modifiers are still held.
+		held := true
+		for _, mod := range mods {
+			held = held && prefix.mods[mod]
+		}
+		if held {
+			callback, ok = prefix.keys[key]
+		}
+	}
+	h.mu.Unlock()
+
+	if h.ungrabNextKey != nil {
+		h.ungrabNextKey()
+	}
+	if !ok {
+		log.Printf("Hotkey sequence %s: no binding for %q", prefix.keySequence, chord)
+		return
+	}
+	callback()
+}
+
+// expireSequence abandons the sequence begun as gen if it is still waiting
+// for its second key. It must run on the X event loop, which owns the
+// keyboard grab; a timeout delivered after a newer sequence began is ignored.
+func (h *Handler) expireSequence(gen uint32) {
+	h.mu.Lock()
+	prefix := h.pending
+	if prefix == nil || h.pendingGen != gen {
+		h.mu.Unlock()
+		return
+	}
+	h.stopPendingLocked()
+	h.pending = nil
+	h.mu.Unlock()
+
+	if h.ungrabNextKey != nil {
+		h.ungrabNextKey()
+	}
+	log.Printf("Hotkey sequence %s: timed out waiting for second key", prefix.keySequence)
+}
+
+func (h *Handler) stopPendingLocked() {
+	if h.pendingTimer != nil {
+		h.pendingTimer.Stop()
+		h.pendingTimer = nil
+	}
+}
+
+// splitChord splits a normalized chord into its modifiers and key.
+func splitChord(chord string) ([]string, string) {
+	parts := strings.Split(chord, "-")
+	return parts[:len(parts)-1], parts[len(parts)-1]
+}
+
+// isModifierKeyName reports whether key (lowercased keysym name) is itself a
+// modifier key such as Super_L.
+func isModifierKeyName(key string) bool {
+	for _, prefix := range []string{"shift_", "control_", "alt_", "meta_", "super_", "hyper_"} {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	switch key {
+	case "caps_lock", "num_lock", "shift_lock", "iso_level3_shift", "iso_level5_shift", "mode_switch":
+		return true
+	}
+	return false
+}
+
+// grabKeyboardX11 actively grabs the keyboard so the key following a prefix
+// is delivered to termtile instead of the focused window.
+func (h *Handler) grabKeyboardX11() error {
+	if h.xu == nil {
+		return fmt.Errorf("no X connection")
+	}
+	conn := h.xu.Conn()
+
+	grab := func() (*xproto.GrabKeyboardReply, error) {
+		return xproto.GrabKeyboard(conn, false, h.root, xproto.TimeCurrentTime,
+			xproto.GrabModeAsync, xproto.GrabModeAsync).Reply()
+	}
+	reply, err := grab()
+	if err != nil {
+		return err
+	}
+	// The passive grab for the prefix may still be active; take it over.
+	if reply.Status == xproto.GrabStatusAlreadyGrabbed {
+		xproto.UngrabKeyboard(conn, xproto.TimeCurrentTime)
+		if reply, err = grab(); err != nil {
+			return err
+		}
+	}
+	if reply.Status != xproto.GrabStatusSuccess {
+		return fmt.Errorf("keyboard grab failed with status %d", reply.Status)
+	}
+
+	if err := h.ensureGrabWindow(); err != nil {
+		xproto.UngrabKeyboard(conn, xproto.TimeCurrentTime)
+		return err
+	}
+	// Route key presses to the sequence handler rather than the global
+	// hotkey callbacks on the root window.
+	xevent.RedirectKeyEvents(h.xu, h.grabWindow)
+	xevent.KeyPressFun(func(xu *xgbutil.XUtil, ev xevent.KeyPressEvent) {
+		key := keybind.KeysymToStr(keybind.KeysymGet(xu, ev.Detail, 0))
+		if mods := keybind.ModifierString(ev.State & sequenceModMask); mods != "" {
+			key = mods + "-" + key
+		}
+		h.sequenceKeyPressed(key)
+	}).Connect(h.xu, h.grabWindow)
+	xevent.ClientMessageFun(func(xu *xgbutil.XUtil, ev xevent.ClientMessageEvent) {
+		if ev.Type == h.timeoutAtom {
+			h.expireSequence(ev.Data.Data32[0])
+		}
+	}).Connect(h.xu, h.grabWindow)
+	return nil
+}
+
+// postTimeoutX11 sends the timeout of sequence gen to the grab window, so
+// expireSequence runs on the X event loop rather than the timer goroutine.
+func (h *Handler) postTimeoutX11(gen uint32) {
+	h.mu.Lock()
+	win, atom := h.grabWindow, h.timeoutAtom
+	h.mu.Unlock()
+	if h.xu == nil || win == 0 {
+		return
+	}
+	ev := xproto.ClientMessageEvent{
+		Format: 32,
+		Window: win,
+		Type:   atom,
+		Data:   xproto.ClientMessageDataUnionData32New([]uint32{gen, 0, 0, 0, 0}),
+	}
+	// With no event mask the event goes to the window's creator: us.
+	if err := xproto.SendEventChecked(h.xu.Conn(), false, win, xproto.EventMaskNoEvent, string(ev.Bytes())).Check(); err != nil {
+		log.Printf("Hotkey sequence: failed to post timeout: %v", err)
+	}
+}
+
+func (h *Handler) ungrabKeyboardX11() {
+	if h.xu == nil {
+		return
+	}
+	xproto.UngrabKeyboard(h.xu.Conn(), xproto.TimeCurrentTime)
+	xevent.RedirectKeyEvents(h.xu, 0)
+	if h.grabWindow != 0 {
+		xevent.Detach(h.xu, h.grabWindow)
+	}
+}
+
+// ensureGrabWindow creates the InputOnly window that receives key events
+// while a sequence is pending.
+func (h *Handler) ensureGrabWindow() error {
+	if h.grabWindow != 0 {
+		return nil
+	}
+	atom, err := xprop.Atm(h.xu, sequenceTimeoutAtom)
+	if err != nil {
+		return err
+	}
+	conn := h.xu.Conn()
+	wid, err := xproto.NewWindowId(conn)
+	if err != nil {
+		return err
+	}
+	err = xproto.CreateWindowChecked(
+		conn, 0, wid, h.root,
+		0, 0, 1, 1, 0,
+		xproto.WindowClassInputOnly,
+		xproto.Visualid(0),
+		xproto.CwEventMask,
+		[]uint32{uint32(xproto.EventMaskKeyPress)},
+	).Check()
+	if err != nil {
+		return err
+	}
+	xproto.MapWindow(conn, wid)
+	h.mu.Lock()
+	h.grabWindow, h.timeoutAtom = wid, atom
+	h.mu.Unlock()
+	return nil
+}
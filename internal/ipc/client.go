@@ -3,13 +3,27 @@ package ipc
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"time"
 
+	"github.com/1broseidon/termtile/internal/config"
 	"github.com/1broseidon/termtile/internal/runtimepath"
+	"github.com/1broseidon/termtile/internal/tiling"
 )
 
+// LayoutValidationError is returned when the daemon rejects a request
+// because its layout failed validation. Its message matches other daemon
+// errors; the embedded detail says which field failed.
+type LayoutValidationError struct {
+	ValidationDetail
+	err error
+}
+
+func (e *LayoutValidationError) Error() string { return "daemon error: " + e.err.Error() }
+
 // Client handles IPC communication with the daemon
 type Client struct {
 	socketPath string
@@ -69,6 +83,15 @@ func (c *Client) sendRequest(req *Request) (*Response, error) {
 
 	// Check for error response
 	if resp.Status == "ERROR" {
+		if resp.Code == ErrorCodeNoTerminals {
+			return nil, tiling.ErrNoTerminals
+		}
+		if resp.Code == ErrorCodeInvalidLayout && resp.Validation != nil {
+			return nil, &LayoutValidationError{
+				ValidationDetail: *resp.Validation,
+				err:              errors.New(resp.Error),
+			}
+		}
 		return nil, fmt.Errorf("daemon error: %s", resp.Error)
 	}
 
@@ -95,6 +118,41 @@ func (c *Client) Undo() error {
 	return err
 }
 
+// Shutdown sends a SHUTDOWN command, asking the daemon to exit cleanly.
+func (c *Client) Shutdown() error {
+	req := &Request{
+		Command: CommandShutdown,
+	}
+
+	_, err := c.sendRequest(req)
+	return err
+}
+
+// ExplainValue explains a config path against the daemon's live config.
+func (c *Client) ExplainValue(path string) (*ExplainValueData, error) {
+	payload, err := json.Marshal(ExplainValuePayload{Path: path})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal explain payload: %w", err)
+	}
+
+	req := &Request{
+		Command: CommandExplainValue,
+		Payload: payload,
+	}
+
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var data ExplainValueData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse explain data: %w", err)
+	}
+
+	return &data, nil
+}
+
 // GetStatus retrieves daemon status
 func (c *Client) GetStatus() (*StatusData, error) {
 	req := &Request{
@@ -114,6 +172,77 @@ func (c *Client) GetStatus() (*StatusData, error) {
 	return &status, nil
 }
 
+// GetMetrics retrieves the daemon's tiling counters
+func (c *Client) GetMetrics() (*MetricsData, error) {
+	req := &Request{
+		Command: CommandGetMetrics,
+	}
+
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var metrics MetricsData
+	if err := json.Unmarshal(resp.Data, &metrics); err != nil {
+		return nil, fmt.Errorf("failed to parse metrics data: %w", err)
+	}
+
+	return &metrics, nil
+}
+
+// SubscribeEvents streams daemon events to fn, one call per event, until fn
+// returns an error (which is returned), the daemon ends the stream, or the
+// connection fails. Only the subscription itself is subject to the client
+// timeout; the stream may stay idle indefinitely.
+func (c *Client) SubscribeEvents(fn func(Event) error) error {
+	conn, err := net.DialTimeout("unix", c.socketPath, c.timeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to daemon: %w (is the daemon running?)", err)
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(c.timeout))
+	reqData, err := json.Marshal(&Request{Command: CommandSubscribeEvents})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	if _, err := conn.Write(append(reqData, '\n')); err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	respData, err := reader.ReadBytes('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	var resp Response
+	if err := json.Unmarshal(respData, &resp); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if resp.Status == "ERROR" {
+		return fmt.Errorf("daemon error: %s", resp.Error)
+	}
+
+	conn.SetDeadline(time.Time{})
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return fmt.Errorf("daemon closed the event stream")
+			}
+			return fmt.Errorf("failed to read event: %w", err)
+		}
+		var ev Event
+		if err := json.Unmarshal(line, &ev); err != nil {
+			return fmt.Errorf("failed to parse event: %w", err)
+		}
+		if err := fn(ev); err != nil {
+			return err
+		}
+	}
+}
+
 // GetMonitors retrieves monitor information
 func (c *Client) GetMonitors() (*MonitorsData, error) {
 	req := &Request{
@@ -152,6 +281,26 @@ func (c *Client) PreviewLayout(layoutName string, durationSeconds int) error {
 	return err
 }
 
+// PreviewLayoutDefinition temporarily applies an inline layout for preview
+// without adding it to the daemon's config
+func (c *Client) PreviewLayoutDefinition(layout config.Layout, durationSeconds int) error {
+	payload, err := json.Marshal(PreviewLayoutDefinitionPayload{
+		Layout:          layout,
+		DurationSeconds: durationSeconds,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal preview payload: %w", err)
+	}
+
+	req := &Request{
+		Command: CommandPreviewLayoutDefinition,
+		Payload: payload,
+	}
+
+	_, err = c.sendRequest(req)
+	return err
+}
+
 // ListLayouts retrieves available layouts and current selection.
 func (c *Client) ListLayouts() (*LayoutsData, error) {
 	req := &Request{
@@ -190,6 +339,27 @@ func (c *Client) ApplyLayout(layoutName string, tileNow bool) error {
 	return err
 }
 
+// ApplyLayoutOnMonitors sets the daemon's active layout and tiles either the
+// active monitor or every monitor, regardless of the configured retile_target.
+func (c *Client) ApplyLayoutOnMonitors(layoutName string, allMonitors bool) error {
+	payload, err := json.Marshal(ApplyLayoutPayload{
+		LayoutName:  layoutName,
+		TileNow:     true,
+		AllMonitors: &allMonitors,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal apply payload: %w", err)
+	}
+
+	req := &Request{
+		Command: CommandApplyLayout,
+		Payload: payload,
+	}
+
+	_, err = c.sendRequest(req)
+	return err
+}
+
 // ApplyLayoutWithOrder sets the daemon's active layout and tiles with a specific window order.
 // This is used by workspace load to ensure windows end up in the correct slots.
 func (c *Client) ApplyLayoutWithOrder(layoutName string, windowOrder []uint32) error {
@@ -0,0 +1,145 @@
+package ipc
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/1broseidon/termtile/internal/tiling"
+)
+
+// subscriberQueueSize bounds the events waiting for one subscriber; when a
+// client falls this far behind, newer events are dropped for it.
+const subscriberQueueSize = 64
+
+// eventWriteTimeout bounds how long one event write may block on a client.
+const eventWriteTimeout = 5 * time.Second
+
+// subscriber is one SUBSCRIBE_EVENTS connection.
+type subscriber struct {
+	events    chan Event
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (sub *subscriber) close() {
+	sub.closeOnce.Do(func() { close(sub.done) })
+}
+
+// Publish sends ev to every subscriber without blocking. Time is filled in
+// when empty.
+func (s *Server) Publish(ev Event) {
+	if ev.Time == "" {
+		ev.Time = time.Now().Format(time.RFC3339Nano)
+	}
+
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	for sub := range s.subscribers {
+		select {
+		case sub.events <- ev:
+		default:
+			log.Printf("IPC: event subscriber is behind, dropping %s event", ev.Type)
+		}
+	}
+}
+
+// PublishTilingEvent publishes a tiler event; pass it to
+// tiling.Tiler.SetEventHandler.
+func (s *Server) PublishTilingEvent(ev tiling.Event) {
+	switch ev.Kind {
+	case tiling.EventTiled:
+		out := Event{Type: EventTile, Layout: ev.Layout, Terminals: ev.Terminals}
+		if ev.MonitorID >= 0 {
+			monitor := ev.MonitorID
+			out.Monitor = &monitor
+		}
+		s.Publish(out)
+	case tiling.EventLayoutChanged:
+		s.Publish(Event{Type: EventLayoutChanged, Layout: ev.Layout})
+	}
+}
+
+// SubscriberCount returns the number of connected event subscribers, so
+// publishers can skip work nobody is listening for.
+func (s *Server) SubscriberCount() int {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	return len(s.subscribers)
+}
+
+func (s *Server) subscribe() *subscriber {
+	sub := &subscriber{
+		events: make(chan Event, subscriberQueueSize),
+		done:   make(chan struct{}),
+	}
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	if s.subscribers == nil {
+		s.subscribers = make(map[*subscriber]struct{})
+	}
+	s.subscribers[sub] = struct{}{}
+	return sub
+}
+
+func (s *Server) unsubscribe(sub *subscriber) {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	delete(s.subscribers, sub)
+	sub.close()
+}
+
+// closeSubscribers ends every event stream; used on Stop.
+func (s *Server) closeSubscribers() {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	for sub := range s.subscribers {
+		sub.close()
+	}
+}
+
+// serveEvents answers SUBSCRIBE_EVENTS and then streams events on conn
+// until the client disconnects, a write fails, or the server stops.
+func (s *Server) serveEvents(conn net.Conn, reader *bufio.Reader) {
+	sub := s.subscribe()
+	defer s.unsubscribe(sub)
+
+	resp, _ := NewOKResponse(nil)
+	if err := writeLine(conn, resp); err != nil {
+		log.Printf("Failed to send response: %v", err)
+		return
+	}
+
+	// The client sends nothing more; a read returning means it hung up.
+	go func() {
+		_, _ = io.Copy(io.Discard, reader)
+		sub.close()
+	}()
+
+	for {
+		select {
+		case ev := <-sub.events:
+			if err := writeLine(conn, ev); err != nil {
+				log.Printf("IPC: dropping event subscriber: %v", err)
+				return
+			}
+		case <-sub.done:
+			return
+		}
+	}
+}
+
+// writeLine writes v as one line of JSON.
+func writeLine(conn net.Conn, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	conn.SetWriteDeadline(time.Now().Add(eventWriteTimeout))
+	_, err = conn.Write(append(data, '\n'))
+	return err
+}
@@ -3,20 +3,35 @@ package ipc
 import (
 	"encoding/json"
 	"fmt"
+
+	"github.com/1broseidon/termtile/internal/config"
 )
 
 // CommandType represents different IPC command types
 type CommandType string
 
 const (
-	CommandReload           CommandType = "RELOAD"
-	CommandGetStatus        CommandType = "GET_STATUS"
-	CommandGetMonitors      CommandType = "GET_MONITORS"
-	CommandPreviewLayout    CommandType = "PREVIEW_LAYOUT"
-	CommandListLayouts      CommandType = "LIST_LAYOUTS"
-	CommandApplyLayout      CommandType = "APPLY_LAYOUT"
-	CommandSetDefaultLayout CommandType = "SET_DEFAULT_LAYOUT"
-	CommandUndo             CommandType = "UNDO"
+	CommandReload        CommandType = "RELOAD"
+	CommandGetStatus     CommandType = "GET_STATUS"
+	CommandGetMonitors   CommandType = "GET_MONITORS"
+	CommandPreviewLayout CommandType = "PREVIEW_LAYOUT"
+	// CommandPreviewLayoutDefinition previews a layout sent inline rather
+	// than one looked up by name in the daemon's config.
+	CommandPreviewLayoutDefinition CommandType = "PREVIEW_LAYOUT_DEFINITION"
+	CommandListLayouts             CommandType = "LIST_LAYOUTS"
+	CommandApplyLayout             CommandType = "APPLY_LAYOUT"
+	CommandSetDefaultLayout        CommandType = "SET_DEFAULT_LAYOUT"
+	CommandUndo                    CommandType = "UNDO"
+	// CommandShutdown asks the daemon to exit as it does on SIGTERM.
+	CommandShutdown CommandType = "SHUTDOWN"
+	// CommandExplainValue runs config.Explain against the daemon's live
+	// config.
+	CommandExplainValue CommandType = "EXPLAIN_VALUE"
+	// CommandGetMetrics returns the tiler's performance counters.
+	CommandGetMetrics CommandType = "GET_METRICS"
+	// CommandSubscribeEvents keeps the connection open after the OK
+	// response and streams one Event per line until either side closes it.
+	CommandSubscribeEvents CommandType = "SUBSCRIBE_EVENTS"
 )
 
 // Request represents an IPC request from client to server
@@ -30,6 +45,27 @@ type Response struct {
 	Status string          `json:"status"` // "OK" or "ERROR"
 	Data   json.RawMessage `json:"data,omitempty"`
 	Error  string          `json:"error,omitempty"`
+	// Code identifies errors clients may want to handle specially.
+	Code string `json:"code,omitempty"`
+	// Validation is set with ErrorCodeInvalidLayout.
+	Validation *ValidationDetail `json:"validation,omitempty"`
+}
+
+const (
+	// ErrorCodeNoTerminals marks a tiling request that found no terminals.
+	ErrorCodeNoTerminals = "NO_TERMINALS"
+	// ErrorCodeInvalidLayout marks a request whose layout failed
+	// validation; Response.Validation says which field.
+	ErrorCodeInvalidLayout = "INVALID_LAYOUT"
+)
+
+// ValidationDetail describes the layout field that failed validation.
+type ValidationDetail struct {
+	// Layout is the layout name as requested; empty for inline definitions.
+	Layout string `json:"layout,omitempty"`
+	// Field is relative to the layout, e.g. "master_stack.max_stack_rows".
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
 
 // StatusData represents the data returned by GET_STATUS
@@ -40,6 +76,54 @@ type StatusData struct {
 	DaemonRunning bool   `json:"daemon_running"`
 }
 
+// MetricsData represents the data returned by GET_METRICS
+type MetricsData struct {
+	TilesTotal         uint64 `json:"tiles_total"`
+	LastTileDurationMs int64  `json:"last_tile_duration_ms"`
+	LastTileTerminals  int    `json:"last_tile_terminals"`
+	// LastTiledAt is RFC 3339; empty until the first tile.
+	LastTiledAt string `json:"last_tiled_at,omitempty"`
+}
+
+// EventType identifies a daemon event.
+type EventType string
+
+const (
+	// EventTile follows a successful tile of one monitor or all of them.
+	EventTile EventType = "tile"
+	// EventLayoutChanged follows a change of the active layout.
+	EventLayoutChanged EventType = "layout_changed"
+	// EventWorkspaceActiveChanged follows a change of the workspace on the
+	// current desktop, including switching to a desktop without one.
+	EventWorkspaceActiveChanged EventType = "workspace_active_changed"
+	// EventMoveCompleted follows a move-mode move or swap.
+	EventMoveCompleted EventType = "move_completed"
+)
+
+// Event is one line of the SUBSCRIBE_EVENTS stream. Fields that do not
+// apply to Type are omitted.
+type Event struct {
+	Type EventType `json:"type"`
+	// Time is RFC 3339 with nanoseconds.
+	Time string `json:"time"`
+	// Layout is the active layout for tile and layout_changed.
+	Layout string `json:"layout,omitempty"`
+	// Monitor is the monitor ID for tile and move_completed; omitted when
+	// a tile covered every monitor.
+	Monitor *int `json:"monitor,omitempty"`
+	// Terminals is how many terminals a tile placed.
+	Terminals int `json:"terminals,omitempty"`
+	// Workspace is the active workspace for workspace_active_changed;
+	// empty when the desktop has none.
+	Workspace string `json:"workspace,omitempty"`
+	// Desktop is the current desktop for workspace_active_changed.
+	Desktop *int `json:"desktop,omitempty"`
+	// SourceSlot, TargetSlot and Swap describe a move_completed.
+	SourceSlot *int `json:"source_slot,omitempty"`
+	TargetSlot *int `json:"target_slot,omitempty"`
+	Swap       bool `json:"swap,omitempty"`
+}
+
 // MonitorInfo represents information about a single monitor
 type MonitorInfo struct {
 	ID     int    `json:"id"`
@@ -61,21 +145,44 @@ type PreviewLayoutPayload struct {
 	DurationSeconds int    `json:"duration_seconds"`
 }
 
+// PreviewLayoutDefinitionPayload represents the payload for
+// PREVIEW_LAYOUT_DEFINITION command
+type PreviewLayoutDefinitionPayload struct {
+	Layout          config.Layout `json:"layout"`
+	DurationSeconds int           `json:"duration_seconds"`
+}
+
 type LayoutsData struct {
-	Layouts       []string `json:"layouts"`
-	DefaultLayout string   `json:"default_layout"`
-	ActiveLayout  string   `json:"active_layout"`
+	Layouts       []string                 `json:"layouts"`
+	DefaultLayout string                   `json:"default_layout"`
+	ActiveLayout  string                   `json:"active_layout"`
+	Definitions   map[string]config.Layout `json:"definitions,omitempty"` // layouts by name, as the daemon tiles them
 }
 
 type ApplyLayoutPayload struct {
 	LayoutName  string   `json:"layout_name"`
 	TileNow     bool     `json:"tile_now,omitempty"`
 	WindowOrder []uint32 `json:"window_order,omitempty"` // If set, use this window order instead of sorting
+	AllMonitors *bool    `json:"all_monitors,omitempty"` // nil = use retile_target from config
 }
 
 type SetDefaultLayoutPayload struct {
-	LayoutName string `json:"layout_name"`
-	TileNow    bool   `json:"tile_now,omitempty"`
+	LayoutName  string `json:"layout_name"`
+	TileNow     bool   `json:"tile_now,omitempty"`
+	AllMonitors *bool  `json:"all_monitors,omitempty"` // nil = use retile_target from config
+}
+
+// ExplainValuePayload represents the payload for EXPLAIN_VALUE command
+type ExplainValuePayload struct {
+	Path string `json:"path"`
+}
+
+// ExplainValueData represents the data returned by EXPLAIN_VALUE
+type ExplainValueData struct {
+	Path   string        `json:"path"`
+	Source config.Source `json:"source"`
+	// Value is the effective value encoded as YAML, as config explain prints it.
+	Value string `json:"value"`
 }
 
 // NewOKResponse creates a successful response with optional data
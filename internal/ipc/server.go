@@ -3,6 +3,7 @@ package ipc
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -16,20 +17,34 @@ import (
 	"github.com/1broseidon/termtile/internal/platform"
 	"github.com/1broseidon/termtile/internal/runtimepath"
 	"github.com/1broseidon/termtile/internal/tiling"
+	"gopkg.in/yaml.v3"
 )
 
 // Server handles IPC requests from clients
 type Server struct {
-	socketPath   string
-	listener     net.Listener
-	cfg          *config.Config
-	cfgMu        sync.RWMutex
+	socketPath string
+	listener   net.Listener
+	cfg        *config.Config
+	cfgMu      sync.RWMutex
+	// loadRes carries cfg's per-path sources for EXPLAIN_VALUE; nil when cfg
+	// was set without them.
+	loadRes      *config.LoadResult
 	tiler        *tiling.Tiler
 	backend      platform.Backend
 	startTime    time.Time
 	reloadChan   chan struct{}
 	shuttingDown bool
 	shutdownMu   sync.Mutex
+
+	// shutdownRequested is set by a SHUTDOWN command; shutdownChan is closed
+	// once its response has been sent.
+	shutdownRequested bool
+	shutdownChan      chan struct{}
+	shutdownOnce      sync.Once
+
+	// subscribers holds the open SUBSCRIBE_EVENTS connections.
+	subMu       sync.Mutex
+	subscribers map[*subscriber]struct{}
 }
 
 // NewServer creates a new IPC server
@@ -43,12 +58,13 @@ func NewServer(cfg *config.Config, tiler *tiling.Tiler, backend platform.Backend
 	os.Remove(socketPath)
 
 	return &Server{
-		socketPath: socketPath,
-		cfg:        cfg,
-		tiler:      tiler,
-		backend:    backend,
-		startTime:  time.Now(),
-		reloadChan: reloadChan,
+		socketPath:   socketPath,
+		cfg:          cfg,
+		tiler:        tiler,
+		backend:      backend,
+		startTime:    time.Now(),
+		reloadChan:   reloadChan,
+		shutdownChan: make(chan struct{}),
 	}, nil
 }
 
@@ -112,6 +128,12 @@ func (s *Server) handleConnection(conn net.Conn) {
 		return
 	}
 
+	// An event subscription keeps the connection for the stream.
+	if req.Command == CommandSubscribeEvents {
+		s.serveEvents(conn, reader)
+		return
+	}
+
 	// Handle command
 	resp := s.handleCommand(req)
 
@@ -126,6 +148,12 @@ func (s *Server) handleConnection(conn net.Conn) {
 	if _, err := conn.Write(respData); err != nil {
 		log.Printf("Failed to send response: %v", err)
 	}
+
+	// Signal shutdown only after the client has its response, so the daemon
+	// exiting cannot cut the reply short.
+	if s.ShutdownPending() {
+		s.shutdownOnce.Do(func() { close(s.shutdownChan) })
+	}
 }
 
 // handleCommand processes an IPC command and returns a response
@@ -139,6 +167,8 @@ func (s *Server) handleCommand(req *Request) *Response {
 		return s.handleGetMonitors()
 	case CommandPreviewLayout:
 		return s.handlePreviewLayout(req.Payload)
+	case CommandPreviewLayoutDefinition:
+		return s.handlePreviewLayoutDefinition(req.Payload)
 	case CommandListLayouts:
 		return s.handleListLayouts()
 	case CommandApplyLayout:
@@ -147,6 +177,12 @@ func (s *Server) handleCommand(req *Request) *Response {
 		return s.handleSetDefaultLayout(req.Payload)
 	case CommandUndo:
 		return s.handleUndo()
+	case CommandShutdown:
+		return s.handleShutdown()
+	case CommandExplainValue:
+		return s.handleExplainValue(req.Payload)
+	case CommandGetMetrics:
+		return s.handleGetMetrics()
 	default:
 		return NewErrorResponse(fmt.Sprintf("Unknown command: %s", req.Command))
 	}
@@ -157,15 +193,13 @@ func (s *Server) handleReload() *Response {
 	log.Println("IPC: Received RELOAD command")
 
 	// Load new config
-	newCfg, err := config.Load()
+	res, err := config.LoadWithSources()
 	if err != nil {
 		return NewErrorResponse(fmt.Sprintf("Failed to reload config: %v", err))
 	}
 
 	// Update config atomically
-	s.cfgMu.Lock()
-	s.cfg = newCfg
-	s.cfgMu.Unlock()
+	s.UpdateLoadResult(res)
 
 	// Notify the main daemon via channel (non-blocking)
 	select {
@@ -199,6 +233,22 @@ func (s *Server) handleGetStatus() *Response {
 	return resp
 }
 
+// handleGetMetrics returns the tiler's performance counters
+func (s *Server) handleGetMetrics() *Response {
+	m := s.tiler.Metrics()
+	data := MetricsData{
+		TilesTotal:         m.TilesTotal,
+		LastTileDurationMs: m.LastDuration.Milliseconds(),
+		LastTileTerminals:  m.LastTerminals,
+	}
+	if !m.LastTiledAt.IsZero() {
+		data.LastTiledAt = m.LastTiledAt.Format(time.RFC3339)
+	}
+
+	resp, _ := NewOKResponse(data)
+	return resp
+}
+
 // handleGetMonitors returns information about all monitors
 func (s *Server) handleGetMonitors() *Response {
 	displays, err := s.backend.Displays()
@@ -240,29 +290,58 @@ func (s *Server) handlePreviewLayout(payload json.RawMessage) *Response {
 	}
 	s.cfgMu.RUnlock()
 
-	duration := time.Duration(previewReq.DurationSeconds) * time.Second
-	if duration <= 0 {
-		duration = 3 * time.Second
-	}
-	if duration > 60*time.Second {
-		duration = 60 * time.Second
-	}
+	duration := PreviewDuration(previewReq.DurationSeconds)
 
 	log.Printf("IPC: Preview layout '%s' for %s", layoutName, duration)
 
 	if err := s.tiler.PreviewLayout(layoutName, duration); err != nil {
-		return NewErrorResponse(fmt.Sprintf("Failed to preview layout: %v", err))
+		return layoutErrorResponse("Failed to preview layout", layoutName, err)
 	}
 
 	resp, _ := NewOKResponse(nil)
 	return resp
 }
 
+// handlePreviewLayoutDefinition temporarily applies an inline layout that is
+// not part of the config
+func (s *Server) handlePreviewLayoutDefinition(payload json.RawMessage) *Response {
+	var previewReq PreviewLayoutDefinitionPayload
+	if err := json.Unmarshal(payload, &previewReq); err != nil {
+		return NewErrorResponse(fmt.Sprintf("Invalid preview payload: %v", err))
+	}
+
+	duration := PreviewDuration(previewReq.DurationSeconds)
+
+	log.Printf("IPC: Preview %s layout definition for %s", previewReq.Layout.Mode, duration)
+
+	if err := s.tiler.PreviewLayoutDefinition(previewReq.Layout, duration); err != nil {
+		return layoutErrorResponse("Failed to preview layout", "", err)
+	}
+
+	resp, _ := NewOKResponse(nil)
+	return resp
+}
+
+// PreviewDuration converts a requested preview length to a duration,
+// defaulting to 3s and capping at 60s.
+func PreviewDuration(seconds int) time.Duration {
+	duration := time.Duration(seconds) * time.Second
+	if duration <= 0 {
+		duration = 3 * time.Second
+	}
+	if duration > 60*time.Second {
+		duration = 60 * time.Second
+	}
+	return duration
+}
+
 func (s *Server) handleListLayouts() *Response {
 	s.cfgMu.RLock()
 	layoutNames := make([]string, 0, len(s.cfg.Layouts))
-	for name := range s.cfg.Layouts {
+	definitions := make(map[string]config.Layout, len(s.cfg.Layouts))
+	for name, l := range s.cfg.Layouts {
 		layoutNames = append(layoutNames, name)
+		definitions[name] = l
 	}
 	defaultLayout := s.cfg.DefaultLayout
 	s.cfgMu.RUnlock()
@@ -273,6 +352,7 @@ func (s *Server) handleListLayouts() *Response {
 		Layouts:       layoutNames,
 		DefaultLayout: defaultLayout,
 		ActiveLayout:  s.tiler.GetActiveLayoutName(),
+		Definitions:   definitions,
 	}
 
 	resp, _ := NewOKResponse(data)
@@ -289,7 +369,7 @@ func (s *Server) handleApplyLayout(payload json.RawMessage) *Response {
 	}
 
 	if err := s.tiler.SetActiveLayout(req.LayoutName); err != nil {
-		return NewErrorResponse(fmt.Sprintf("Failed to set active layout: %v", err))
+		return layoutErrorResponse("Failed to set active layout", req.LayoutName, err)
 	}
 
 	if req.TileNow {
@@ -298,10 +378,10 @@ func (s *Server) handleApplyLayout(payload json.RawMessage) *Response {
 			// Use provided window order instead of sorting by position
 			err = s.tiler.TileWithOrder(req.WindowOrder)
 		} else {
-			err = s.tiler.TileCurrentMonitor()
+			err = s.retile(req.AllMonitors)
 		}
 		if err != nil {
-			return NewErrorResponse(fmt.Sprintf("Failed to tile with active layout: %v", err))
+			return tileErrorResponse("Failed to tile with active layout", err)
 		}
 	}
 
@@ -319,21 +399,22 @@ func (s *Server) handleSetDefaultLayout(payload json.RawMessage) *Response {
 	}
 
 	s.cfgMu.Lock()
-	if _, ok := s.cfg.Layouts[req.LayoutName]; !ok {
+	layoutName, ok := s.cfg.ResolveLayoutName(req.LayoutName)
+	if !ok {
 		s.cfgMu.Unlock()
 		return NewErrorResponse(fmt.Sprintf("Unknown layout: %s", req.LayoutName))
 	}
-	s.cfg.DefaultLayout = req.LayoutName
+	s.cfg.DefaultLayout = layoutName
 	err := s.cfg.Save()
 	s.cfgMu.Unlock()
 	if err != nil {
 		return NewErrorResponse(fmt.Sprintf("Failed to save config: %v", err))
 	}
 
-	_ = s.tiler.SetActiveLayout(req.LayoutName)
+	_ = s.tiler.SetActiveLayout(layoutName)
 	if req.TileNow {
-		if err := s.tiler.TileCurrentMonitor(); err != nil {
-			return NewErrorResponse(fmt.Sprintf("Failed to tile with default layout: %v", err))
+		if err := s.retile(req.AllMonitors); err != nil {
+			return tileErrorResponse("Failed to tile with default layout", err)
 		}
 	}
 
@@ -341,6 +422,51 @@ func (s *Server) handleSetDefaultLayout(payload json.RawMessage) *Response {
 	return resp
 }
 
+// tileErrorResponse reports a tiling failure, tagging the no-terminals case
+// so clients can tell it apart from real failures.
+func tileErrorResponse(prefix string, err error) *Response {
+	if errors.Is(err, tiling.ErrNoTerminals) {
+		resp := NewErrorResponse(err.Error())
+		resp.Code = ErrorCodeNoTerminals
+		return resp
+	}
+	return layoutErrorResponse(prefix, "", err)
+}
+
+// layoutErrorResponse reports a failure to use a layout. When the layout
+// failed validation the response also carries the field at fault.
+func layoutErrorResponse(prefix, layoutName string, err error) *Response {
+	resp := NewErrorResponse(fmt.Sprintf("%s: %v", prefix, err))
+	var fieldErr *config.LayoutFieldError
+	if errors.As(err, &fieldErr) {
+		resp.Code = ErrorCodeInvalidLayout
+		resp.Validation = &ValidationDetail{
+			Layout:  layoutName,
+			Field:   fieldErr.Field,
+			Message: fieldErr.Err.Error(),
+		}
+	}
+	return resp
+}
+
+// retile tiles the active monitor or every monitor. An explicit request
+// overrides the configured retile_target.
+func (s *Server) retile(allMonitors *bool) error {
+	all := false
+	if allMonitors != nil {
+		all = *allMonitors
+	} else {
+		s.cfgMu.RLock()
+		all = s.cfg.RetileAllMonitors()
+		s.cfgMu.RUnlock()
+	}
+
+	if all {
+		return s.tiler.TileAllMonitors()
+	}
+	return s.tiler.TileCurrentMonitor()
+}
+
 func (s *Server) handleUndo() *Response {
 	if err := s.tiler.UndoCurrentMonitor(); err != nil {
 		return NewErrorResponse(fmt.Sprintf("Failed to undo: %v", err))
@@ -350,6 +476,62 @@ func (s *Server) handleUndo() *Response {
 	return resp
 }
 
+// handleExplainValue explains a config path against the live config.
+func (s *Server) handleExplainValue(payload json.RawMessage) *Response {
+	var p ExplainValuePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return NewErrorResponse(fmt.Sprintf("Invalid payload: %v", err))
+	}
+
+	s.cfgMu.RLock()
+	res := s.loadRes
+	if res == nil {
+		res = &config.LoadResult{Config: s.cfg}
+	}
+	s.cfgMu.RUnlock()
+
+	value, src, err := config.Explain(res, p.Path)
+	if err != nil {
+		return NewErrorResponse(err.Error())
+	}
+	out, err := yaml.Marshal(value)
+	if err != nil {
+		return NewErrorResponse(fmt.Sprintf("Failed to encode value: %v", err))
+	}
+
+	resp, err := NewOKResponse(ExplainValueData{Path: p.Path, Source: src, Value: string(out)})
+	if err != nil {
+		return NewErrorResponse(err.Error())
+	}
+	return resp
+}
+
+// handleShutdown marks the daemon for shutdown. The daemon is notified via
+// ShutdownRequested once the response is written.
+func (s *Server) handleShutdown() *Response {
+	log.Println("IPC: Received SHUTDOWN command")
+
+	s.shutdownMu.Lock()
+	s.shutdownRequested = true
+	s.shutdownMu.Unlock()
+
+	resp, _ := NewOKResponse(nil)
+	return resp
+}
+
+// ShutdownPending reports whether a client has sent a SHUTDOWN command.
+func (s *Server) ShutdownPending() bool {
+	s.shutdownMu.Lock()
+	defer s.shutdownMu.Unlock()
+	return s.shutdownRequested
+}
+
+// ShutdownRequested returns a channel that is closed after a SHUTDOWN
+// command has been answered.
+func (s *Server) ShutdownRequested() <-chan struct{} {
+	return s.shutdownChan
+}
+
 // sendError sends an error response
 func (s *Server) sendError(conn net.Conn, errMsg string) {
 	resp := NewErrorResponse(errMsg)
@@ -367,6 +549,7 @@ func (s *Server) Stop() {
 	if s.listener != nil {
 		s.listener.Close()
 	}
+	s.closeSubscribers()
 	os.Remove(s.socketPath)
 }
 
@@ -377,9 +560,20 @@ func (s *Server) GetConfig() *config.Config {
 	return s.cfg
 }
 
-// UpdateConfig updates the config (thread-safe)
+// UpdateConfig updates the config (thread-safe). Its sources are unknown, so
+// EXPLAIN_VALUE reports defaults until UpdateLoadResult is called.
 func (s *Server) UpdateConfig(cfg *config.Config) {
 	s.cfgMu.Lock()
 	defer s.cfgMu.Unlock()
 	s.cfg = cfg
+	s.loadRes = nil
+}
+
+// UpdateLoadResult updates the config together with its sources
+// (thread-safe).
+func (s *Server) UpdateLoadResult(res *config.LoadResult) {
+	s.cfgMu.Lock()
+	defer s.cfgMu.Unlock()
+	s.cfg = res.Config
+	s.loadRes = res
 }
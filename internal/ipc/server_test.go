@@ -0,0 +1,292 @@
+package ipc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1broseidon/termtile/internal/config"
+	"github.com/1broseidon/termtile/internal/platform"
+	"github.com/1broseidon/termtile/internal/terminals"
+	"github.com/1broseidon/termtile/internal/tiling"
+)
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	s, err := NewServer(nil, nil, nil, make(chan struct{}, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestHandleShutdown_SetsShutdownFlag(t *testing.T) {
+	s := newTestServer(t)
+
+	if resp := s.handleCommand(&Request{Command: CommandType("BOGUS")}); resp.Status != "ERROR" {
+		t.Fatalf("unknown command status = %q, want ERROR", resp.Status)
+	}
+	if s.ShutdownPending() {
+		t.Fatal("shutdown pending before SHUTDOWN")
+	}
+
+	resp := s.handleCommand(&Request{Command: CommandShutdown})
+	if resp.Status != "OK" {
+		t.Fatalf("SHUTDOWN status = %q (%s), want OK", resp.Status, resp.Error)
+	}
+	if !s.ShutdownPending() {
+		t.Fatal("SHUTDOWN did not set the shutdown flag")
+	}
+	select {
+	case <-s.ShutdownRequested():
+		t.Fatal("shutdown signalled before the response was sent")
+	default:
+	}
+}
+
+func TestClientShutdown_SignalsDaemonAfterResponse(t *testing.T) {
+	s := newTestServer(t)
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	if err := NewClient().Shutdown(); err != nil {
+		t.Fatalf("Shutdown() err=%v", err)
+	}
+	select {
+	case <-s.ShutdownRequested():
+	case <-time.After(2 * time.Second):
+		t.Fatal("ShutdownRequested not closed after SHUTDOWN")
+	}
+	if !s.ShutdownPending() {
+		t.Fatal("shutdown flag not set")
+	}
+}
+
+func TestClientExplainValue_ReturnsLiveValueAndSource(t *testing.T) {
+	s := newTestServer(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("gap_size: 12\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	res, err := config.LoadFromPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.UpdateLoadResult(res)
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+	client := NewClient()
+
+	data, err := client.ExplainValue("gap_size")
+	if err != nil {
+		t.Fatalf("ExplainValue() err=%v", err)
+	}
+	if data.Value != "12\n" {
+		t.Fatalf("value=%q, want %q", data.Value, "12\n")
+	}
+	if data.Source.Kind != config.SourceFile || data.Source.File != path || data.Source.Line != 1 {
+		t.Fatalf("source=%+v, want %s line 1", data.Source, path)
+	}
+
+	// An in-memory update without sources reports the live value as default.
+	cfg := config.DefaultConfig()
+	cfg.GapSize = 20
+	s.UpdateConfig(cfg)
+	data, err = client.ExplainValue("gap_size")
+	if err != nil {
+		t.Fatalf("ExplainValue() err=%v", err)
+	}
+	if data.Value != "20\n" || data.Source.Kind != config.SourceDefault {
+		t.Fatalf("got value=%q source=%+v, want 20 from defaults", data.Value, data.Source)
+	}
+
+	if _, err := client.ExplainValue("no_such_key"); err == nil || !strings.Contains(err.Error(), "unknown path") {
+		t.Fatalf("ExplainValue(no_such_key) err=%v, want unknown path", err)
+	}
+}
+
+func TestClientGetMetrics_BeforeFirstTile(t *testing.T) {
+	s := newTestServer(t)
+	s.tiler = tiling.NewTiler(nil, nil, config.DefaultConfig())
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	data, err := NewClient().GetMetrics()
+	if err != nil {
+		t.Fatalf("GetMetrics() err=%v", err)
+	}
+	if *data != (MetricsData{}) {
+		t.Fatalf("metrics=%+v, want zero values before the first tile", *data)
+	}
+}
+
+func TestClientListLayouts_IncludesDefinitions(t *testing.T) {
+	s := newTestServer(t)
+	cfg := config.DefaultConfig()
+	s.cfg = cfg
+	s.tiler = tiling.NewTiler(nil, nil, cfg)
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	data, err := NewClient().ListLayouts()
+	if err != nil {
+		t.Fatalf("ListLayouts() err=%v", err)
+	}
+	if len(data.Definitions) != len(data.Layouts) {
+		t.Fatalf("definitions=%d layouts=%d, want one definition per layout", len(data.Definitions), len(data.Layouts))
+	}
+	for _, name := range data.Layouts {
+		got, ok := data.Definitions[name]
+		if !ok {
+			t.Fatalf("no definition for %q", name)
+		}
+		if want := cfg.Layouts[name]; got.Mode != want.Mode || got.FixedGrid != want.FixedGrid || got.MasterStack != want.MasterStack {
+			t.Fatalf("definition %q = %+v, want %+v", name, got, want)
+		}
+	}
+}
+
+func TestClientApplyLayout_ReportsValidationField(t *testing.T) {
+	s := newTestServer(t)
+	cfg := config.DefaultConfig()
+	// Config loading would reject this; set it directly as a stale or
+	// hand-edited in-memory config might hold it.
+	cfg.Layouts["broken"] = config.Layout{
+		Mode:        config.LayoutModeMasterStack,
+		TileRegion:  config.TileRegion{Type: config.RegionFull},
+		MasterStack: config.MasterStack{MasterWidthPercent: 50, MaxStackRows: 0, MaxStackCols: 1},
+	}
+	s.tiler = tiling.NewTiler(nil, nil, cfg)
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+	client := NewClient()
+
+	err := client.ApplyLayout("broken", false)
+	var invalid *LayoutValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("ApplyLayout(broken) err=%v, want *LayoutValidationError", err)
+	}
+	if invalid.Layout != "broken" || invalid.Field != "master_stack.max_stack_rows" || invalid.Message != "master_stack.max_stack_rows must be >= 1" {
+		t.Fatalf("detail=%+v", invalid.ValidationDetail)
+	}
+	if !strings.HasPrefix(err.Error(), "daemon error: Failed to set active layout: ") {
+		t.Fatalf("message=%q, want the usual daemon error text", err.Error())
+	}
+
+	// Inline definitions report the field with no layout name.
+	err = client.PreviewLayoutDefinition(config.Layout{
+		Mode:       config.LayoutModeAuto,
+		TileRegion: config.TileRegion{Type: config.RegionCustom, XPercent: 60, WidthPercent: 50, HeightPercent: 100},
+	}, 1)
+	if !errors.As(err, &invalid) {
+		t.Fatalf("PreviewLayoutDefinition err=%v, want *LayoutValidationError", err)
+	}
+	if invalid.Layout != "" || invalid.Field != "tile_region.width_percent" {
+		t.Fatalf("detail=%+v", invalid.ValidationDetail)
+	}
+
+	// Failures that are not validation keep the plain error.
+	err = client.ApplyLayout("missing", false)
+	if err == nil || errors.As(err, &invalid) {
+		t.Fatalf("ApplyLayout(missing) err=%v, want a plain error", err)
+	}
+}
+
+// eventsBackend is a single-monitor platform.Backend for event tests.
+type eventsBackend struct {
+	windows []platform.Window
+}
+
+func (b *eventsBackend) Displays() ([]platform.Display, error) {
+	d, _ := b.ActiveDisplay()
+	return []platform.Display{d}, nil
+}
+func (b *eventsBackend) ActiveDisplay() (platform.Display, error) {
+	return platform.Display{ID: 0, Name: "main", Bounds: platform.Rect{Width: 1920, Height: 1080}}, nil
+}
+func (b *eventsBackend) ActiveWindow() (platform.WindowID, error) { return 0, nil }
+func (b *eventsBackend) ListWindowsOnDisplay(int) ([]platform.Window, error) {
+	return b.windows, nil
+}
+func (b *eventsBackend) MoveResize(platform.WindowID, platform.Rect) error { return nil }
+func (b *eventsBackend) MoveResizeBatch(moves []platform.WindowRect) error {
+	return platform.MoveResizeEach(b, moves)
+}
+func (b *eventsBackend) Minimize(platform.WindowID) error { return nil }
+func (b *eventsBackend) Focus(platform.WindowID) error    { return nil }
+func (b *eventsBackend) Close(platform.WindowID) error    { return nil }
+
+func TestClientSubscribeEvents_ReceivesTileEvent(t *testing.T) {
+	s := newTestServer(t)
+	backend := &eventsBackend{windows: []platform.Window{
+		{ID: 1, AppID: "kitty", Bounds: platform.Rect{X: 0, Y: 0, Width: 400, Height: 300}},
+		{ID: 2, AppID: "kitty", Bounds: platform.Rect{X: 500, Y: 0, Width: 400, Height: 300}},
+	}}
+	cfg := config.DefaultConfig()
+	tiler := tiling.NewTiler(backend, terminals.NewDetector([]string{"kitty"}), cfg)
+	tiler.SetEventHandler(s.PublishTilingEvent)
+	s.tiler = tiler
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	events := make(chan Event, 4)
+	streamErr := make(chan error, 1)
+	go func() {
+		streamErr <- NewClient().SubscribeEvents(func(ev Event) error {
+			events <- ev
+			return nil
+		})
+	}()
+	deadline := time.Now().Add(2 * time.Second)
+	for s.SubscriberCount() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("subscriber never registered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if err := tiler.TileCurrentMonitor(); err != nil {
+		t.Fatalf("TileCurrentMonitor: %v", err)
+	}
+	select {
+	case ev := <-events:
+		if ev.Type != EventTile || ev.Layout != cfg.DefaultLayout || ev.Terminals != 2 || ev.Monitor == nil || *ev.Monitor != 0 {
+			t.Fatalf("event = %+v, want tile of 2 terminals on monitor 0 with %s", ev, cfg.DefaultLayout)
+		}
+		if _, err := time.Parse(time.RFC3339Nano, ev.Time); err != nil {
+			t.Fatalf("event time %q: %v", ev.Time, err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event after TileCurrentMonitor")
+	}
+
+	// Stopping the server ends the stream.
+	s.Stop()
+	select {
+	case err := <-streamErr:
+		if err == nil || !strings.Contains(err.Error(), "closed the event stream") {
+			t.Fatalf("stream err = %v, want closed by daemon", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream still open after Stop")
+	}
+	if n := s.SubscriberCount(); n != 0 {
+		t.Fatalf("SubscriberCount after Stop = %d, want 0", n)
+	}
+}
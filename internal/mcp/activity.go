@@ -0,0 +1,46 @@
+package mcp
+
+import (
+	"os"
+	"time"
+)
+
+// agentActivity reports, at now, how long an agent has been running and
+// when it last produced output. Agents recovered at startup have no
+// in-memory spawn time, so metaSpawnedAt from the slot's agent meta is used
+// instead. Output is the later of the activity the server observed and the
+// pipe file's modification time. Zero results mean unknown.
+func agentActivity(ta trackedAgent, metaSpawnedAt, pipeModTime, now time.Time) (uptime time.Duration, lastActivity time.Time) {
+	spawnedAt := ta.spawnedAt
+	if spawnedAt.IsZero() {
+		spawnedAt = metaSpawnedAt
+	}
+	if !spawnedAt.IsZero() && now.After(spawnedAt) {
+		uptime = now.Sub(spawnedAt)
+	}
+	lastActivity = ta.lastActivity
+	if pipeModTime.After(lastActivity) {
+		lastActivity = pipeModTime
+	}
+	return uptime, lastActivity
+}
+
+// pipeFileModTime returns the modification time of a pipe-pane output file,
+// or zero when it does not exist.
+func pipeFileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// SlotActivity returns when the agent in a workspace slot was spawned and
+// when its pipe-pane output last changed, for callers outside the MCP
+// server. Either is zero when unknown.
+func SlotActivity(workspace string, slot int) (spawnedAt, lastActivity time.Time) {
+	if meta, err := readAgentMeta(workspace, slot); err == nil {
+		spawnedAt = meta.SpawnedAt
+	}
+	return spawnedAt, pipeFileModTime(pipeFilePath(workspace, slot))
+}
@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/1broseidon/termtile/internal/config"
+)
+
+func TestAgentActivity(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	spawned := now.Add(-90 * time.Minute)
+	observed := now.Add(-2 * time.Minute)
+	pipeMod := now.Add(-30 * time.Second)
+
+	tests := []struct {
+		name       string
+		ta         trackedAgent
+		meta, pipe time.Time
+		wantUptime time.Duration
+		wantLast   time.Time
+	}{
+		{
+			name:       "tracked spawn time",
+			ta:         trackedAgent{spawnedAt: spawned, lastActivity: observed},
+			wantUptime: 90 * time.Minute,
+			wantLast:   observed,
+		},
+		{
+			name:       "meta spawn time for recovered agent",
+			ta:         trackedAgent{},
+			meta:       now.Add(-time.Hour),
+			wantUptime: time.Hour,
+		},
+		{
+			name:       "newer pipe mtime wins",
+			ta:         trackedAgent{spawnedAt: spawned, lastActivity: observed},
+			pipe:       pipeMod,
+			wantUptime: 90 * time.Minute,
+			wantLast:   pipeMod,
+		},
+		{
+			name:       "older pipe mtime ignored",
+			ta:         trackedAgent{spawnedAt: spawned, lastActivity: pipeMod},
+			pipe:       observed,
+			wantUptime: 90 * time.Minute,
+			wantLast:   pipeMod,
+		},
+		{
+			name: "unknown",
+			ta:   trackedAgent{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uptime, last := agentActivity(tt.ta, tt.meta, tt.pipe, now)
+			if uptime != tt.wantUptime {
+				t.Errorf("uptime = %v, want %v", uptime, tt.wantUptime)
+			}
+			if !last.Equal(tt.wantLast) {
+				t.Errorf("lastActivity = %v, want %v", last, tt.wantLast)
+			}
+		})
+	}
+}
+
+func TestTrackedAgentRecordsSpawnAndActivity(t *testing.T) {
+	s := &Server{
+		config:        config.DefaultConfig(),
+		tracked:       make(map[string]map[int]trackedAgent),
+		nextSlot:      make(map[string]int),
+		readSnapshots: make(map[string]map[int]string),
+	}
+	before := time.Now()
+	s.allocateSlot("ws", "claude", "%5", "pane", false)
+
+	ta := s.tracked["ws"][0]
+	if ta.spawnedAt.Before(before) {
+		t.Fatalf("spawnedAt = %v, want >= %v", ta.spawnedAt, before)
+	}
+	if !ta.lastActivity.IsZero() {
+		t.Fatalf("lastActivity = %v before any output, want zero", ta.lastActivity)
+	}
+
+	// The first read only sets the baseline; an unchanged capture is not output.
+	s.setReadSnapshot("ws", 0, "prompt>")
+	s.setReadSnapshot("ws", 0, "prompt>")
+	if got := s.tracked["ws"][0].lastActivity; !got.IsZero() {
+		t.Fatalf("lastActivity = %v after unchanged captures, want zero", got)
+	}
+	s.setReadSnapshot("ws", 0, "prompt> working")
+	if got := s.tracked["ws"][0].lastActivity; got.IsZero() {
+		t.Fatal("changed capture did not record activity")
+	}
+
+	s.tracked["ws"][0] = trackedAgent{lastPipeSize: 10}
+	s.updateLastPipeSize("ws", 0, 10, 0)
+	if got := s.tracked["ws"][0].lastActivity; !got.IsZero() {
+		t.Fatalf("lastActivity = %v after unchanged pipe size, want zero", got)
+	}
+	s.updateLastPipeSize("ws", 0, 42, 0)
+	if got := s.tracked["ws"][0].lastActivity; got.IsZero() {
+		t.Fatal("pipe growth did not record activity")
+	}
+}
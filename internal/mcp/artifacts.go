@@ -1,6 +1,8 @@
 package mcp
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,10 +14,12 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
 	artifactFileName = "output.json"
+	endedFileName    = "ended.json"
 )
 
 type hookArtifactPayload struct {
@@ -34,6 +38,32 @@ func parseHookArtifactPayload(data []byte) (hookArtifactPayload, error) {
 	return payload, nil
 }
 
+// artifactCursor identifies an artifact output by content so get_artifact can
+// return only what was appended since a prior fetch.
+func artifactCursor(output string) string {
+	sum := sha256.Sum256([]byte(output))
+	return hex.EncodeToString(sum[:8])
+}
+
+// artifactMark is the per-slot record of the last get_artifact response.
+type artifactMark struct {
+	cursor string
+	length int
+}
+
+// artifactDelta returns the part of output appended after the fetch that
+// produced since. It fails when since is not the slot's last cursor or the
+// previously returned prefix has since been rewritten.
+func artifactDelta(mark artifactMark, since, output string) (string, bool) {
+	if mark.cursor == "" || mark.cursor != since || mark.length > len(output) {
+		return "", false
+	}
+	if artifactCursor(output[:mark.length]) != since {
+		return "", false
+	}
+	return output[mark.length:], true
+}
+
 func readArtifactOutputField(workspace string, slot int) (string, error) {
 	data, err := ReadArtifact(workspace, slot)
 	if err != nil {
@@ -123,21 +153,91 @@ func CleanupArtifact(workspace string, slot int) error {
 	return os.RemoveAll(artifactDir)
 }
 
-// CleanStaleOutput removes only the output.json artifact file from a
-// workspace+slot directory, preserving context.md and checkpoint.json
-// which may have been placed by the orchestrator for the next spawn.
-func CleanStaleOutput(workspace string, slot int) error {
-	path, err := artifactFilePath(workspace, slot)
+// GetWorkspaceArtifactDir returns the directory holding every slot's
+// artifacts for workspace: {base}/artifacts/{workspace}.
+func GetWorkspaceArtifactDir(workspace string) (string, error) {
+	baseDir, err := artifactBaseDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(baseDir, normalizeArtifactWorkspace(workspace)), nil
+}
+
+// CleanupWorkspaceArtifacts removes the artifacts of every slot of
+// workspace. It is safe to call even if the directory does not exist.
+func CleanupWorkspaceArtifacts(workspace string) error {
+	dir, err := GetWorkspaceArtifactDir(workspace)
 	if err != nil {
 		return err
 	}
-	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+	return os.RemoveAll(dir)
+}
+
+// CleanStaleOutput removes the output.json artifact file and any ended
+// record from a workspace+slot directory, preserving context.md and
+// checkpoint.json which may have been placed by the orchestrator for the
+// next spawn.
+func CleanStaleOutput(workspace string, slot int) error {
+	artifactDir, err := GetArtifactDir(workspace, slot)
+	if err != nil {
 		return err
 	}
+	for _, name := range []string{artifactFileName, endedFileName} {
+		if err := os.Remove(filepath.Join(artifactDir, name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
 	return nil
 }
 
-func moveArtifactDir(srcWorkspace string, srcSlot int, dstWorkspace string, dstSlot int) error {
+// EndedOutput is the last output of a killed agent. kill_agent keeps it in
+// the slot's artifact directory so reads still work once the tmux target is
+// gone; the next spawn into the slot removes it.
+type EndedOutput struct {
+	SessionName string    `json:"session_name"`
+	Output      string    `json:"output"`
+	EndedAt     time.Time `json:"ended_at"`
+}
+
+// Tail returns at most the last lines lines of the saved output.
+func (e EndedOutput) Tail(lines int) string {
+	return tailOutputLines(e.Output, lines)
+}
+
+func writeEndedOutput(workspace string, slot int, ended EndedOutput) error {
+	artifactDir, err := EnsureArtifactDir(workspace, slot)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(ended)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(artifactDir, endedFileName), data, 0o644)
+}
+
+// ReadEndedOutput reads the ended record kill_agent left for workspace+slot.
+// The error satisfies os.IsNotExist when the slot has none.
+func ReadEndedOutput(workspace string, slot int) (EndedOutput, error) {
+	artifactDir, err := GetArtifactDir(workspace, slot)
+	if err != nil {
+		return EndedOutput{}, err
+	}
+	data, err := os.ReadFile(filepath.Join(artifactDir, endedFileName))
+	if err != nil {
+		return EndedOutput{}, err
+	}
+	var ended EndedOutput
+	if err := json.Unmarshal(data, &ended); err != nil {
+		return EndedOutput{}, fmt.Errorf("invalid ended record for workspace %q slot %d: %w", workspace, slot, err)
+	}
+	return ended, nil
+}
+
+// MoveArtifactDir moves a slot's artifact directory to another slot,
+// replacing whatever the destination held. Exported so CLI slot shifts keep
+// the agent meta with the session it describes.
+func MoveArtifactDir(srcWorkspace string, srcSlot int, dstWorkspace string, dstSlot int) error {
 	srcDir, err := GetArtifactDir(srcWorkspace, srcSlot)
 	if err != nil {
 		return err
@@ -169,6 +269,70 @@ func moveArtifactDir(srcWorkspace string, srcSlot int, dstWorkspace string, dstS
 	return os.RemoveAll(srcDir)
 }
 
+// SwapArtifactDirs exchanges the artifact directories of two slots in a
+// workspace. Either slot may have no directory.
+func SwapArtifactDirs(workspace string, a, b int) error {
+	dirA, err := GetArtifactDir(workspace, a)
+	if err != nil {
+		return err
+	}
+	dirB, err := GetArtifactDir(workspace, b)
+	if err != nil {
+		return err
+	}
+	tmpDir := dirA + ".swap"
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return err
+	}
+	if err := os.Rename(dirA, tmpDir); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := MoveArtifactDir(workspace, b, workspace, a); err != nil {
+		// Put slot a back so a failed swap leaves both slots as they were.
+		if restoreErr := os.Rename(tmpDir, dirA); restoreErr != nil && !os.IsNotExist(restoreErr) {
+			return fmt.Errorf("%w (restoring slot %d: %v)", err, a, restoreErr)
+		}
+		return err
+	}
+	if err := os.Rename(tmpDir, dirB); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// MoveWorkspaceArtifacts moves every slot's artifacts from one workspace to
+// another, replacing whatever the destination held. Exported so workspace
+// rename keeps the agent meta, and with it any session_name override, under
+// the workspace's new name.
+func MoveWorkspaceArtifacts(srcWorkspace, dstWorkspace string) error {
+	srcDir, err := GetWorkspaceArtifactDir(srcWorkspace)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(srcDir); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	dstDir, err := GetWorkspaceArtifactDir(dstWorkspace)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dstDir); err != nil {
+		return err
+	}
+	if err := os.Rename(srcDir, dstDir); err == nil {
+		return nil
+	}
+
+	if err := copyArtifactDir(srcDir, dstDir); err != nil {
+		return err
+	}
+	return os.RemoveAll(srcDir)
+}
+
 func copyArtifactDir(srcDir, dstDir string) error {
 	return filepath.WalkDir(srcDir, func(path string, entry fs.DirEntry, walkErr error) error {
 		if walkErr != nil {
@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/1broseidon/termtile/internal/config"
 )
 
 func writeHookArtifactForTest(t *testing.T, workspace string, slot int, output string) {
@@ -127,8 +129,8 @@ func TestMoveArtifactDirMovesDirectory(t *testing.T) {
 	t.Setenv("XDG_DATA_HOME", base)
 	writeHookArtifactForTest(t, "ws", 3, "artifact-3")
 
-	if err := moveArtifactDir("ws", 3, "ws", 2); err != nil {
-		t.Fatalf("moveArtifactDir: %v", err)
+	if err := MoveArtifactDir("ws", 3, "ws", 2); err != nil {
+		t.Fatalf("MoveArtifactDir: %v", err)
 	}
 
 	output, err := readArtifactOutputField("ws", 2)
@@ -142,3 +144,100 @@ func TestMoveArtifactDirMovesDirectory(t *testing.T) {
 		t.Fatalf("expected slot 3 artifact missing after move, err=%v", err)
 	}
 }
+
+func TestSwapArtifactDirs(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	writeHookArtifactForTest(t, "ws", 0, "artifact-0")
+	writeHookArtifactForTest(t, "ws", 1, "artifact-1")
+
+	if err := SwapArtifactDirs("ws", 0, 1); err != nil {
+		t.Fatalf("SwapArtifactDirs: %v", err)
+	}
+	for slot, want := range map[int]string{0: "artifact-1", 1: "artifact-0"} {
+		if got, err := readArtifactOutputField("ws", slot); err != nil || got != want {
+			t.Fatalf("slot %d output = %q, %v; want %q", slot, got, err, want)
+		}
+	}
+
+	// Swapping with an empty slot moves the directory across.
+	if err := SwapArtifactDirs("ws", 1, 4); err != nil {
+		t.Fatalf("SwapArtifactDirs(empty): %v", err)
+	}
+	if got, err := readArtifactOutputField("ws", 4); err != nil || got != "artifact-0" {
+		t.Fatalf("slot 4 output = %q, %v; want artifact-0", got, err)
+	}
+	if _, err := ReadArtifact("ws", 1); !os.IsNotExist(err) {
+		t.Fatalf("expected slot 1 empty after swap with empty slot, err=%v", err)
+	}
+}
+
+func TestMoveWorkspaceArtifactsKeepsSessionName(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	if err := writeAgentMeta("old", 2, agentMeta{AgentType: "claude", SessionName: "reviewer"}); err != nil {
+		t.Fatalf("writeAgentMeta: %v", err)
+	}
+
+	if err := MoveWorkspaceArtifacts("old", "new"); err != nil {
+		t.Fatalf("MoveWorkspaceArtifacts: %v", err)
+	}
+	if got := SlotSessionName("new", 2); got != "reviewer" {
+		t.Fatalf("SlotSessionName(new, 2) = %q, want reviewer", got)
+	}
+	if _, err := readAgentMeta("old", 2); !os.IsNotExist(err) {
+		t.Fatalf("expected old workspace meta gone after move, err=%v", err)
+	}
+
+	// A workspace without artifacts is not an error.
+	if err := MoveWorkspaceArtifacts("missing", "other"); err != nil {
+		t.Fatalf("MoveWorkspaceArtifacts(missing): %v", err)
+	}
+}
+
+func TestHandleGetArtifact_SinceReturnsAppendedOutput(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	s := &Server{
+		config:  config.DefaultConfig(),
+		tracked: make(map[string]map[int]trackedAgent),
+	}
+	write := func(output string) { writeHookArtifactForTest(t, DefaultWorkspace, 2, output) }
+	get := func(since string) GetArtifactOutput {
+		t.Helper()
+		_, out, err := s.handleGetArtifact(nil, nil, GetArtifactArgs{Slot: 2, Workspace: DefaultWorkspace, Since: since})
+		if err != nil {
+			t.Fatalf("handleGetArtifact: %v", err)
+		}
+		return out
+	}
+
+	write("step 1\n")
+	first := get("")
+	if first.Output != "step 1\n" || first.Incremental || first.Cursor == "" {
+		t.Fatalf("first fetch = %+v", first)
+	}
+
+	write("step 1\nstep 2\n")
+	second := get(first.Cursor)
+	if second.Output != "step 2\n" || !second.Incremental || second.Warning != "" {
+		t.Fatalf("incremental fetch = %+v, want only appended output", second)
+	}
+	if second.Cursor == first.Cursor || second.OriginalBytes != len("step 1\nstep 2\n") {
+		t.Fatalf("incremental fetch cursor/bytes = %+v", second)
+	}
+
+	// Nothing new since the last fetch.
+	if third := get(second.Cursor); third.Output != "" || !third.Incremental {
+		t.Fatalf("unchanged fetch = %+v, want empty increment", third)
+	}
+
+	// A rewritten artifact no longer extends the cursor's prefix.
+	write("replaced\n")
+	if fourth := get(second.Cursor); fourth.Output != "replaced\n" || fourth.Incremental || fourth.Warning == "" {
+		t.Fatalf("rewritten fetch = %+v, want full output with warning", fourth)
+	}
+
+	// Killing the slot forgets its cursor.
+	s.removeTracked(DefaultWorkspace, 2)
+	if fifth := get(artifactCursor("replaced\n")); fifth.Incremental {
+		t.Fatalf("fetch after untrack = %+v, want full output", fifth)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/1broseidon/termtile/internal/config"
 )
 
 func TestDepWaitForDependenciesImmediate(t *testing.T) {
@@ -127,3 +129,67 @@ func TestDepWaitForDependenciesTimeout(t *testing.T) {
 		t.Fatalf("expected to wait close to timeout, returned in %s", time.Since(start))
 	}
 }
+
+func TestDepWaitForDependenciesUsesConfigDefaults(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.AgentMode.IdlePollMs = 10
+	cfg.AgentMode.DefaultDepTimeoutS = 1
+	polls := 0
+	s := &Server{
+		config: cfg,
+		tracked: map[string]map[int]trackedAgent{
+			"ws": {0: {agentType: "a", tmuxTarget: "t0"}},
+		},
+		nextSlot:       map[string]int{},
+		targetExistsFn: func(string) bool { return true },
+		idleCheckFn: func(string, string, string, int) bool {
+			polls++
+			return false
+		},
+	}
+
+	// No explicit timeout: the configured 1s applies instead of 300s.
+	err := s.waitForDependencies("ws", []int{0}, 0)
+	if err == nil || !strings.Contains(err.Error(), "after 1s") {
+		t.Fatalf("expected timeout after configured 1s, got %v", err)
+	}
+	// A 10ms poll over 1s checks far more often than the 2s default would.
+	if polls < 20 {
+		t.Fatalf("expected configured poll interval to be used, got %d checks", polls)
+	}
+}
+
+func TestHandleWaitForIdleUsesConfigTimeout(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.AgentMode.IdlePollMs = 10
+	cfg.AgentMode.DefaultIdleTimeoutS = 1
+	s := &Server{
+		config: cfg,
+		tracked: map[string]map[int]trackedAgent{
+			"ws": {1: {agentType: "a", tmuxTarget: "t1"}},
+		},
+	}
+
+	start := time.Now()
+	_, out, err := s.handleWaitForIdle(nil, nil, WaitForIdleInput{Slot: 1, Workspace: "ws"})
+	if err != nil {
+		t.Fatalf("handleWaitForIdle: %v", err)
+	}
+	if out.IsIdle {
+		t.Fatalf("expected not idle without an artifact, got %+v", out)
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond || elapsed > 5*time.Second {
+		t.Fatalf("expected ~1s configured timeout, took %s", elapsed)
+	}
+
+	// An explicit timeout still overrides the configured default.
+	cfg.AgentMode.DefaultIdleTimeoutS = 60
+	start = time.Now()
+	if _, _, err := s.handleWaitForIdle(nil, nil, WaitForIdleInput{Slot: 1, Workspace: "ws", Timeout: 1}); err != nil {
+		t.Fatalf("handleWaitForIdle: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("explicit timeout ignored, took %s", elapsed)
+	}
+}
@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"syscall"
+
+	"github.com/1broseidon/termtile/internal/platform"
+)
+
+// displayHandle is the part of the window-system backend spawns use to
+// check and restore focus.
+type displayHandle interface {
+	ActiveWindow() (platform.WindowID, error)
+	Focus(windowID platform.WindowID) error
+	Disconnect()
+}
+
+// dialDisplay opens a new window-system connection.
+var dialDisplay = func() (displayHandle, error) {
+	return platform.NewLinuxBackendFromDisplay()
+}
+
+// displayConn lazily opens one display connection and shares it between
+// calls (agent_mode.reuse_display_connection). A call that fails because the
+// connection broke drops it and is retried once on a fresh one, so a broken
+// connection never outlives the call that found it. Other errors, such as an
+// X request the server rejected, are returned with the connection kept.
+type displayConn struct {
+	mu     sync.Mutex
+	handle displayHandle
+}
+
+// do runs fn against the shared connection, connecting first if needed.
+func (c *displayConn) do(fn func(displayHandle) error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if c.handle == nil {
+			if c.handle, err = dialDisplay(); err != nil {
+				c.handle = nil
+				return err
+			}
+		}
+		if err = fn(c.handle); err == nil || !isDisplayConnError(err) {
+			return err
+		}
+		c.handle.Disconnect()
+		c.handle = nil
+	}
+	return err
+}
+
+// isDisplayConnError reports whether err means the display connection itself
+// is broken rather than that one request failed.
+func isDisplayConnError(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.As(err, &netErr)
+}
+
+// close disconnects the shared connection, if one is open.
+func (c *displayConn) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.handle != nil {
+		c.handle.Disconnect()
+		c.handle = nil
+	}
+}
+
+// withDisplay runs fn against the shared connection when
+// reuse_display_connection is on, and against a connection opened for this
+// call otherwise.
+func (s *Server) withDisplay(fn func(displayHandle) error) error {
+	if s != nil && s.display != nil {
+		return s.display.do(fn)
+	}
+	handle, err := dialDisplay()
+	if err != nil {
+		return err
+	}
+	defer handle.Disconnect()
+	return fn(handle)
+}
+
+// activeWindowID returns the focused window, if any.
+func (s *Server) activeWindowID() (uint32, bool) {
+	var active platform.WindowID
+	err := s.withDisplay(func(h displayHandle) error {
+		var err error
+		active, err = h.ActiveWindow()
+		return err
+	})
+	if err != nil || active == 0 {
+		return 0, false
+	}
+	return uint32(active), true
+}
+
+// focusWindow gives windowID input focus.
+func (s *Server) focusWindow(windowID uint32) error {
+	return s.withDisplay(func(h displayHandle) error {
+		return h.Focus(platform.WindowID(windowID))
+	})
+}
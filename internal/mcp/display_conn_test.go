@@ -0,0 +1,161 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+
+	"github.com/1broseidon/termtile/internal/platform"
+)
+
+type fakeDisplay struct {
+	id           int
+	active       platform.WindowID
+	activeErr    error
+	focused      []platform.WindowID
+	disconnected bool
+}
+
+func (f *fakeDisplay) ActiveWindow() (platform.WindowID, error) {
+	return f.active, f.activeErr
+}
+
+func (f *fakeDisplay) Focus(windowID platform.WindowID) error {
+	f.focused = append(f.focused, windowID)
+	return nil
+}
+
+func (f *fakeDisplay) Disconnect() { f.disconnected = true }
+
+// stubDialDisplay replaces dialDisplay with one that hands out fakes built
+// by next and records them in order.
+func stubDialDisplay(t *testing.T, next func(n int) (*fakeDisplay, error)) *[]*fakeDisplay {
+	t.Helper()
+	var dialed []*fakeDisplay
+	orig := dialDisplay
+	dialDisplay = func() (displayHandle, error) {
+		d, err := next(len(dialed))
+		if err != nil {
+			return nil, err
+		}
+		dialed = append(dialed, d)
+		return d, nil
+	}
+	t.Cleanup(func() { dialDisplay = orig })
+	return &dialed
+}
+
+func TestDisplayConn_ReusesConnection(t *testing.T) {
+	dialed := stubDialDisplay(t, func(n int) (*fakeDisplay, error) {
+		return &fakeDisplay{id: n, active: 7}, nil
+	})
+	s := &Server{display: &displayConn{}}
+
+	for i := 0; i < 3; i++ {
+		if id, ok := s.activeWindowID(); !ok || id != 7 {
+			t.Fatalf("activeWindowID() = %d, %v; want 7, true", id, ok)
+		}
+	}
+	if err := s.focusWindow(9); err != nil {
+		t.Fatalf("focusWindow: %v", err)
+	}
+	if len(*dialed) != 1 {
+		t.Fatalf("dialed %d connections, want 1", len(*dialed))
+	}
+	if got := (*dialed)[0].focused; len(got) != 1 || got[0] != 9 {
+		t.Fatalf("focused = %v, want [9]", got)
+	}
+
+	s.Close()
+	if !(*dialed)[0].disconnected {
+		t.Fatal("Close did not disconnect the shared connection")
+	}
+}
+
+func TestDisplayConn_ReconnectsOnError(t *testing.T) {
+	dialed := stubDialDisplay(t, func(n int) (*fakeDisplay, error) {
+		return &fakeDisplay{id: n, active: 7}, nil
+	})
+	s := &Server{display: &displayConn{}}
+
+	if _, ok := s.activeWindowID(); !ok {
+		t.Fatal("first activeWindowID failed")
+	}
+	(*dialed)[0].activeErr = fmt.Errorf("read: %w", syscall.ECONNRESET)
+
+	if id, ok := s.activeWindowID(); !ok || id != 7 {
+		t.Fatalf("activeWindowID() after error = %d, %v; want retry on a new connection", id, ok)
+	}
+	if len(*dialed) != 2 {
+		t.Fatalf("dialed %d connections, want 2", len(*dialed))
+	}
+	if !(*dialed)[0].disconnected {
+		t.Fatal("broken connection was not disconnected")
+	}
+	if _, ok := s.activeWindowID(); !ok || len(*dialed) != 2 {
+		t.Fatalf("expected the new connection to be reused, dialed %d", len(*dialed))
+	}
+}
+
+func TestDisplayConn_KeepsConnectionOnRequestError(t *testing.T) {
+	dialed := stubDialDisplay(t, func(n int) (*fakeDisplay, error) {
+		return &fakeDisplay{id: n, active: 7}, nil
+	})
+	s := &Server{display: &displayConn{}}
+
+	if _, ok := s.activeWindowID(); !ok {
+		t.Fatal("first activeWindowID failed")
+	}
+	(*dialed)[0].activeErr = errors.New("property _NET_ACTIVE_WINDOW not found")
+
+	if _, ok := s.activeWindowID(); ok {
+		t.Fatal("expected activeWindowID to report the request error")
+	}
+	if len(*dialed) != 1 {
+		t.Fatalf("dialed %d connections, want the request error not to reconnect", len(*dialed))
+	}
+	if (*dialed)[0].disconnected {
+		t.Fatal("connection was dropped after a request error")
+	}
+}
+
+func TestDisplayConn_DialFailureIsNotCached(t *testing.T) {
+	available := false
+	dialed := stubDialDisplay(t, func(n int) (*fakeDisplay, error) {
+		if !available {
+			return nil, errors.New("no display")
+		}
+		return &fakeDisplay{id: n, active: 7}, nil
+	})
+	s := &Server{display: &displayConn{}}
+
+	if _, ok := s.activeWindowID(); ok {
+		t.Fatal("expected activeWindowID to fail while the display is unavailable")
+	}
+	available = true
+	if id, ok := s.activeWindowID(); !ok || id != 7 {
+		t.Fatalf("activeWindowID() = %d, %v; want a fresh dial to succeed", id, ok)
+	}
+	if len(*dialed) != 1 {
+		t.Fatalf("dialed %d connections, want 1", len(*dialed))
+	}
+}
+
+func TestDisplayConn_DisabledDialsPerCall(t *testing.T) {
+	dialed := stubDialDisplay(t, func(n int) (*fakeDisplay, error) {
+		return &fakeDisplay{id: n, active: 7}, nil
+	})
+	s := &Server{}
+
+	s.activeWindowID()
+	s.activeWindowID()
+	if len(*dialed) != 2 {
+		t.Fatalf("dialed %d connections, want one per call", len(*dialed))
+	}
+	for _, d := range *dialed {
+		if !d.disconnected {
+			t.Fatalf("connection %d left open", d.id)
+		}
+	}
+}
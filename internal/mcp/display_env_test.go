@@ -169,3 +169,21 @@ func stubDetectFns(
 		detectDisplayFromSocketFn = origSocket
 	}
 }
+
+func TestWindowSpawnCmd_TerminalThenAgentEnv(t *testing.T) {
+	cmd := windowSpawnCmd(
+		[]string{"kitty", "-e", "tmux"},
+		map[string]string{"GDK_BACKEND": "x11", "SHARED": "terminal"},
+		map[string]string{"SHARED": "agent"},
+	)
+	env := map[string]int{}
+	for _, kv := range cmd.Env {
+		env[kv]++
+	}
+	if env["GDK_BACKEND=x11"] != 1 {
+		t.Fatalf("cmd.Env missing terminal_spawn_env entry: %v", cmd.Env)
+	}
+	if env["SHARED=agent"] != 1 || env["SHARED=terminal"] != 0 {
+		t.Fatalf("agent env should override terminal env: %v", cmd.Env)
+	}
+}
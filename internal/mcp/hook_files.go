@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/1broseidon/termtile/internal/agent"
 	"github.com/1broseidon/termtile/internal/config"
@@ -21,17 +22,24 @@ const (
 
 // agentMeta is written to the artifact dir at spawn time so the hook CLI
 // can look up agent-specific config (hook_output template, response field, etc.).
+// Model, cwd and spawn time let list_agents describe agents recovered by
+// reconcile, whose in-memory type is "unknown".
 type agentMeta struct {
-	AgentType string `json:"agent_type"`
+	AgentType string    `json:"agent_type"`
+	Model     string    `json:"model,omitempty"`
+	Cwd       string    `json:"cwd,omitempty"`
+	SpawnedAt time.Time `json:"spawned_at"`
+	SpawnMode string    `json:"spawn_mode,omitempty"`
+	// SessionName is set when spawn_agent was given a session_name override.
+	SessionName string `json:"session_name,omitempty"`
 }
 
-// writeAgentMeta persists the agent type to the artifact directory.
-func writeAgentMeta(workspace string, slot int, agentType string) error {
+// writeAgentMeta persists the agent metadata to the artifact directory.
+func writeAgentMeta(workspace string, slot int, meta agentMeta) error {
 	artifactDir, err := EnsureArtifactDir(workspace, slot)
 	if err != nil {
 		return fmt.Errorf("failed to ensure artifact dir for agent meta: %w", err)
 	}
-	meta := agentMeta{AgentType: agentType}
 	data, err := json.Marshal(meta)
 	if err != nil {
 		return fmt.Errorf("failed to marshal agent meta: %w", err)
@@ -46,19 +54,112 @@ func writeAgentMeta(workspace string, slot int, agentType string) error {
 // ReadAgentMeta reads the agent type from the artifact directory.
 // Exported so the hook CLI (cmd/termtile) can use it.
 func ReadAgentMeta(workspace string, slot int) (string, error) {
-	artifactDir, err := GetArtifactDir(workspace, slot)
+	meta, err := readAgentMeta(workspace, slot)
 	if err != nil {
 		return "", err
 	}
+	return meta.AgentType, nil
+}
+
+// readAgentMeta reads the full agent metadata from the artifact directory.
+// Files written before model/cwd/spawned_at existed decode with those zero.
+func readAgentMeta(workspace string, slot int) (agentMeta, error) {
+	artifactDir, err := GetArtifactDir(workspace, slot)
+	if err != nil {
+		return agentMeta{}, err
+	}
 	data, err := os.ReadFile(filepath.Join(artifactDir, agentMetaFileName))
 	if err != nil {
-		return "", err
+		return agentMeta{}, err
 	}
 	var meta agentMeta
 	if err := json.Unmarshal(data, &meta); err != nil {
-		return "", err
+		return agentMeta{}, err
+	}
+	return meta, nil
+}
+
+// SlotSessionName returns the session name recorded for a slot at spawn
+// time, falling back to the derived name. Exported so the CLI resolves
+// session_name overrides the same way.
+func SlotSessionName(workspace string, slot int) string {
+	if meta, err := readAgentMeta(workspace, slot); err == nil && meta.SessionName != "" {
+		return meta.SessionName
+	}
+	return agent.SessionName(workspace, slot)
+}
+
+// IsDetachedSession reports whether session belongs to a detached
+// spawn_agent slot, going by the agent meta recorded at spawn time. Detached
+// sessions have no window, so the daemon must not treat them as orphaned.
+func IsDetachedSession(session string) bool {
+	workspace, slot, ok := parseDerivedSessionName(session)
+	if !ok {
+		return false
+	}
+	meta, err := readAgentMeta(workspace, slot)
+	return err == nil && meta.SpawnMode == "detached"
+}
+
+// customSession locates the slot a custom session name was spawned into.
+type customSession struct {
+	workspace string
+	slot      int
+}
+
+// customSessionSlots maps the session_name overrides recorded in agent meta
+// to their workspace and slot, since those names do not encode either.
+func customSessionSlots() map[string]customSession {
+	out := make(map[string]customSession)
+	baseDir, err := artifactBaseDir()
+	if err != nil {
+		return out
+	}
+	workspaceDirs, err := os.ReadDir(baseDir)
+	if err != nil {
+		return out
+	}
+	for _, wsDir := range workspaceDirs {
+		if !wsDir.IsDir() {
+			continue
+		}
+		slotDirs, err := os.ReadDir(filepath.Join(baseDir, wsDir.Name()))
+		if err != nil {
+			continue
+		}
+		for _, slotDir := range slotDirs {
+			slot := parseSlotIndex(slotDir.Name())
+			if !slotDir.IsDir() || slot < 0 {
+				continue
+			}
+			meta, err := readAgentMeta(wsDir.Name(), slot)
+			if err != nil || meta.SessionName == "" {
+				continue
+			}
+			out[meta.SessionName] = customSession{workspace: wsDir.Name(), slot: slot}
+		}
+	}
+	return out
+}
+
+// applyAgentMeta fills the persisted spawn details into info. The meta type
+// only replaces the in-memory type when reconcile left it as "unknown"; meta
+// for a different agent type is stale (left by an earlier occupant of the
+// slot) and is ignored.
+func applyAgentMeta(info *AgentInfo, meta agentMeta) {
+	switch {
+	case info.AgentType == "" || info.AgentType == "unknown":
+		if meta.AgentType != "" {
+			info.AgentType = meta.AgentType
+		}
+	case meta.AgentType != "" && meta.AgentType != info.AgentType:
+		return
+	}
+	info.Model = meta.Model
+	info.Cwd = meta.Cwd
+	if !meta.SpawnedAt.IsZero() {
+		info.SpawnedAt = meta.SpawnedAt.UTC().Format(time.RFC3339)
 	}
-	return meta.AgentType, nil
 }
 
 // writeTaskContext writes the task to context.md in the artifact directory so
@@ -253,17 +354,16 @@ func reconcileHookFileState(liveSessions map[string]bool) {
 			}
 
 			// Check if the tmux session is still alive.
-			slotName := slotDir.Name()
-			sessionName := agent.SessionName(workspace, parseSlotIndex(slotName))
+			slot := parseSlotIndex(slotDir.Name())
+			if slot < 0 {
+				continue
+			}
+			sessionName := SlotSessionName(workspace, slot)
 			if liveSessions[sessionName] {
 				continue
 			}
 
 			// Session is dead — restore the project file.
-			slot := parseSlotIndex(slotName)
-			if slot < 0 {
-				continue
-			}
 			log.Printf("reconcile: restoring hook file state for workspace %q slot %d (session %q is dead)", workspace, slot, sessionName)
 			if err := restoreProjectFileHooks(workspace, slot); err != nil {
 				log.Printf("reconcile: failed to restore hook file state for workspace %q slot %d: %v", workspace, slot, err)
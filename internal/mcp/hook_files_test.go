@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/1broseidon/termtile/internal/config"
 )
@@ -258,3 +259,72 @@ func TestParseSlotIndex(t *testing.T) {
 		}
 	}
 }
+
+func TestAgentMeta_WriteReadEnriched(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	spawnedAt := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+	want := agentMeta{
+		AgentType: "claude",
+		Model:     "sonnet",
+		Cwd:       "/home/user/project",
+		SpawnedAt: spawnedAt,
+	}
+	if err := writeAgentMeta("meta-ws", 2, want); err != nil {
+		t.Fatalf("writeAgentMeta: %v", err)
+	}
+
+	got, err := readAgentMeta("meta-ws", 2)
+	if err != nil {
+		t.Fatalf("readAgentMeta: %v", err)
+	}
+	if got.AgentType != want.AgentType || got.Model != want.Model || got.Cwd != want.Cwd || !got.SpawnedAt.Equal(spawnedAt) {
+		t.Fatalf("readAgentMeta = %+v, want %+v", got, want)
+	}
+
+	agentType, err := ReadAgentMeta("meta-ws", 2)
+	if err != nil || agentType != "claude" {
+		t.Fatalf("ReadAgentMeta = %q, %v; want claude", agentType, err)
+	}
+}
+
+func TestAgentMeta_ReadLegacyFile(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	dir, err := EnsureArtifactDir("meta-ws", 0)
+	if err != nil {
+		t.Fatalf("EnsureArtifactDir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, agentMetaFileName), []byte(`{"agent_type":"codex"}`), 0644); err != nil {
+		t.Fatalf("write legacy meta: %v", err)
+	}
+
+	got, err := readAgentMeta("meta-ws", 0)
+	if err != nil {
+		t.Fatalf("readAgentMeta: %v", err)
+	}
+	if got.AgentType != "codex" || got.Model != "" || got.Cwd != "" || !got.SpawnedAt.IsZero() {
+		t.Fatalf("legacy meta = %+v", got)
+	}
+}
+
+func TestApplyAgentMeta(t *testing.T) {
+	meta := agentMeta{
+		AgentType: "claude",
+		Model:     "opus",
+		Cwd:       "/src",
+		SpawnedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	info := AgentInfo{AgentType: "unknown"}
+	applyAgentMeta(&info, meta)
+	if info.AgentType != "claude" || info.Model != "opus" || info.Cwd != "/src" || info.SpawnedAt != "2025-01-02T03:04:05Z" {
+		t.Fatalf("reconciled info = %+v", info)
+	}
+
+	stale := AgentInfo{AgentType: "codex"}
+	applyAgentMeta(&stale, meta)
+	if stale.AgentType != "codex" || stale.Model != "" || stale.Cwd != "" || stale.SpawnedAt != "" {
+		t.Fatalf("stale meta should be ignored, got %+v", stale)
+	}
+}
@@ -16,15 +16,18 @@ const (
 	defaultOnEnd   = "termtile hook emit --auto"
 )
 
+// outputModeUsesHooks reports whether an output_mode installs native hooks:
+// "hooks" (the default) and "both".
+func outputModeUsesHooks(mode string) bool {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	return mode == "" || mode == "hooks" || mode == "both"
+}
+
 // resolveHooks returns the effective hooks for an agent, applying defaults
-// when output_mode is "hooks" and no explicit command is set.
+// when output_mode installs hooks and no explicit command is set.
 func resolveHooks(agentCfg config.AgentConfig) config.AgentHooks {
 	h := agentCfg.Hooks
-	mode := strings.ToLower(strings.TrimSpace(agentCfg.OutputMode))
-	if mode == "" {
-		mode = "hooks"
-	}
-	if mode != "hooks" {
+	if !outputModeUsesHooks(agentCfg.OutputMode) {
 		return h
 	}
 	if h.OnStart == "" {
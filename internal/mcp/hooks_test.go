@@ -358,3 +358,32 @@ func TestResolveHooks_CustomOverride(t *testing.T) {
 		t.Errorf("expected default on_check, got %q", hooks.OnCheck)
 	}
 }
+
+func TestOutputModeBoth_InstallsHooksAndFence(t *testing.T) {
+	hooks := resolveHooks(config.AgentConfig{OutputMode: "both"})
+	if hooks.OnStart != defaultOnStart || hooks.OnEnd != defaultOnEnd {
+		t.Fatalf("both mode hooks = %+v, want defaults", hooks)
+	}
+
+	cases := []struct {
+		mode          string
+		responseFence bool
+		wantHooks     bool
+		wantFence     bool
+	}{
+		{mode: "", responseFence: true, wantHooks: true, wantFence: false},
+		{mode: "hooks", responseFence: true, wantHooks: true, wantFence: false},
+		{mode: "tags", responseFence: true, wantHooks: false, wantFence: true},
+		{mode: "tags", responseFence: false, wantHooks: false, wantFence: false},
+		{mode: "both", responseFence: false, wantHooks: true, wantFence: true},
+		{mode: " Both ", responseFence: true, wantHooks: true, wantFence: true},
+	}
+	for _, tc := range cases {
+		if got := outputModeUsesHooks(tc.mode); got != tc.wantHooks {
+			t.Errorf("outputModeUsesHooks(%q) = %v, want %v", tc.mode, got, tc.wantHooks)
+		}
+		if got := outputModeUsesFence(tc.mode, tc.responseFence); got != tc.wantFence {
+			t.Errorf("outputModeUsesFence(%q, %v) = %v, want %v", tc.mode, tc.responseFence, got, tc.wantFence)
+		}
+	}
+}
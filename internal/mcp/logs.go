@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/1broseidon/termtile/internal/agent"
+)
+
+const (
+	// defaultLogsLimit is used when get_logs does not pass a limit.
+	defaultLogsLimit = 50
+	// maxLogsLimit bounds get_logs payloads.
+	maxLogsLimit = 500
+)
+
+// normalizeLogsLimit returns a bounded entry count for get_logs.
+func normalizeLogsLimit(limit int) int {
+	if limit <= 0 {
+		return defaultLogsLimit
+	}
+	if limit > maxLogsLimit {
+		return maxLogsLimit
+	}
+	return limit
+}
+
+func (s *Server) handleGetLogs(_ context.Context, _ *mcpsdk.CallToolRequest, args GetLogsInput) (*mcpsdk.CallToolResult, GetLogsOutput, error) {
+	workspaceName, err := resolveWorkspaceForRead(args.Workspace, args.SourceWorkspace, "get_logs")
+	if err != nil {
+		return nil, GetLogsOutput{}, err
+	}
+
+	logCfg := s.config.GetLoggingConfig()
+	if !logCfg.Enabled {
+		return nil, GetLogsOutput{}, fmt.Errorf("agent action logging is disabled; set logging.enabled: true in config")
+	}
+
+	filter := agent.LogFilter{
+		Workspace: workspaceName,
+		Slot:      args.Slot,
+		Limit:     normalizeLogsLimit(args.Limit),
+	}
+	if args.Action != "" {
+		action, err := agent.ParseActionType(args.Action)
+		if err != nil {
+			return nil, GetLogsOutput{}, err
+		}
+		filter.Action = action
+	}
+
+	entries, err := agent.ReadLog(logCfg.File, filter)
+	if err != nil {
+		return nil, GetLogsOutput{}, err
+	}
+
+	out := GetLogsOutput{
+		Workspace: workspaceName,
+		LogFile:   logCfg.File,
+		Entries:   make([]LogEntryInfo, 0, len(entries)),
+	}
+	for _, e := range entries {
+		info := LogEntryInfo{
+			Time:    e.Time.Format(time.RFC3339),
+			Action:  string(e.Action),
+			Details: e.Details,
+		}
+		if e.Slot >= 0 {
+			slot := e.Slot
+			info.Slot = &slot
+		}
+		out.Entries = append(out.Entries, info)
+	}
+	return nil, out, nil
+}
@@ -0,0 +1,118 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/1broseidon/termtile/internal/config"
+)
+
+func logsTestServer(t *testing.T, enabled bool) *Server {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "agent-actions.log")
+	content := strings.Join([]string{
+		`2026-01-02 10:00:00 [WORKSPACE-NEW] workspace=dev terminals=2`,
+		`2026-01-02 10:00:01 [SPAWN-AGENT] workspace=dev slot=0 agent_type="claude"`,
+		`2026-01-02 10:00:02 [SEND] workspace=dev slot=0 text_preview="hi"`,
+		`2026-01-02 10:00:03 [SPAWN-AGENT] workspace=dev slot=1 agent_type="codex"`,
+		`2026-01-02 10:00:04 [SPAWN-AGENT] workspace=other slot=0 agent_type="claude"`,
+	}, "\n") + "\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+	cfg := config.DefaultConfig()
+	cfg.Logging.Enabled = enabled
+	cfg.Logging.File = path
+	return &Server{config: cfg}
+}
+
+func TestHandleGetLogs_Filters(t *testing.T) {
+	s := logsTestServer(t, true)
+	slot0 := 0
+
+	_, out, err := s.handleGetLogs(nil, nil, GetLogsInput{Workspace: "dev"})
+	if err != nil {
+		t.Fatalf("handleGetLogs: %v", err)
+	}
+	if out.Workspace != "dev" || len(out.Entries) != 4 {
+		t.Fatalf("output = %+v, want 4 dev entries", out)
+	}
+	if out.Entries[0].Action != "WORKSPACE-NEW" || out.Entries[0].Slot != nil {
+		t.Fatalf("first entry = %+v, want workspace-level WORKSPACE-NEW without slot", out.Entries[0])
+	}
+
+	_, out, err = s.handleGetLogs(nil, nil, GetLogsInput{Workspace: "dev", Slot: &slot0})
+	if err != nil {
+		t.Fatalf("handleGetLogs slot: %v", err)
+	}
+	if len(out.Entries) != 2 || *out.Entries[1].Slot != 0 || out.Entries[1].Details["text_preview"] != "hi" {
+		t.Fatalf("slot 0 entries = %+v", out.Entries)
+	}
+
+	_, out, err = s.handleGetLogs(nil, nil, GetLogsInput{Workspace: "dev", Action: "spawn_agent", Limit: 1})
+	if err != nil {
+		t.Fatalf("handleGetLogs action: %v", err)
+	}
+	if len(out.Entries) != 1 || out.Entries[0].Details["agent_type"] != "codex" {
+		t.Fatalf("action+limit entries = %+v, want only the latest dev spawn", out.Entries)
+	}
+}
+
+func TestHandleGetLogs_Errors(t *testing.T) {
+	s := logsTestServer(t, true)
+	if _, _, err := s.handleGetLogs(nil, nil, GetLogsInput{Workspace: "dev", Action: "explode"}); err == nil || !strings.Contains(err.Error(), "unknown action") {
+		t.Fatalf("unknown action error = %v", err)
+	}
+
+	s = logsTestServer(t, false)
+	if _, _, err := s.handleGetLogs(nil, nil, GetLogsInput{Workspace: "dev"}); err == nil || !strings.Contains(err.Error(), "disabled") {
+		t.Fatalf("disabled logging error = %v", err)
+	}
+}
+
+func TestNormalizeLogsLimit(t *testing.T) {
+	for in, want := range map[int]int{0: defaultLogsLimit, -3: defaultLogsLimit, 7: 7, maxLogsLimit + 1: maxLogsLimit} {
+		if got := normalizeLogsLimit(in); got != want {
+			t.Errorf("normalizeLogsLimit(%d) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestAddTextDetails_MaxContentBytes(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Logging.IncludeContent = true
+	cfg.Logging.MaxContentBytes = 10
+	s := &Server{config: cfg}
+
+	atCap := strings.Repeat("a", 10)
+	details := map[string]interface{}{}
+	s.addTextDetails(details, atCap)
+	if details["text"] != atCap {
+		t.Fatalf("text at the cap = %q, want it whole", details["text"])
+	}
+
+	details = map[string]interface{}{}
+	s.addOutputDetails(details, atCap+"b")
+	if got := details["output"]; got != atCap+" (truncated)" {
+		t.Fatalf("output over the cap = %q", got)
+	}
+	if details["output_length"] != 11 {
+		t.Fatalf("output_length = %v, want full length 11", details["output_length"])
+	}
+
+	// A multi-byte rune straddling the cap is dropped rather than split.
+	details = map[string]interface{}{}
+	s.addTextDetails(details, strings.Repeat("a", 9)+"é")
+	if got := details["text"]; got != strings.Repeat("a", 9)+" (truncated)" {
+		t.Fatalf("text = %q, want cut before the rune", got)
+	}
+
+	cfg.Logging.IncludeContent = false
+	details = map[string]interface{}{}
+	s.addTextDetails(details, atCap+"b")
+	if _, ok := details["text"]; ok {
+		t.Fatal("content logged with include_content off")
+	}
+}
@@ -0,0 +1,197 @@
+package mcp
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// completeClaimFileName marks a slot's completion as already notified. Every
+// MCP server process watches the slots it reconciled, so the claim is kept
+// in the slot's artifact directory where all of them see it.
+const completeClaimFileName = "complete.claim"
+
+// notifyComplete runs agent_mode.on_complete_command the first time a slot
+// is seen idle after a task. Later idle checks, in this or another server
+// process, are ignored until resetComplete re-arms the slot. It reports
+// whether the command was started.
+func (s *Server) notifyComplete(workspace string, slot int) bool {
+	mode := s.agentModeConfig()
+	if mode == nil || strings.TrimSpace(mode.OnCompleteCommand) == "" {
+		return false
+	}
+	command := mode.OnCompleteCommand
+
+	s.mu.Lock()
+	ta, ok := s.tracked[workspace][slot]
+	if !ok || ta.completed {
+		s.mu.Unlock()
+		return false
+	}
+	ta.completed = true
+	s.tracked[workspace][slot] = ta
+	s.mu.Unlock()
+
+	if !claimComplete(workspace, slot) {
+		return false
+	}
+
+	env := []string{
+		"TERMTILE_WORKSPACE=" + workspace,
+		"TERMTILE_SLOT=" + strconv.Itoa(slot),
+		"TERMTILE_AGENT=" + ta.agentType,
+	}
+	run := s.runCompleteFn
+	if run == nil {
+		run = startCompleteCommand
+	}
+	if err := run(command, env); err != nil {
+		log.Printf("Warning: on_complete_command failed for %s slot %d: %v", workspace, slot, err)
+		return false
+	}
+	return true
+}
+
+// resetComplete re-arms on_complete_command for a slot that was sent new work.
+func (s *Server) resetComplete(workspace string, slot int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ws := s.tracked[workspace]
+	if ws == nil {
+		return
+	}
+	ta, ok := ws[slot]
+	if !ok {
+		return
+	}
+	ta.completed = false
+	ws[slot] = ta
+	releaseComplete(workspace, slot)
+}
+
+// claimComplete creates the slot's completion claim and reports whether
+// this process created it. A claim that cannot be written does not stop the
+// notification, since only a second server would be left to send it.
+func claimComplete(workspace string, slot int) bool {
+	artifactDir, err := EnsureArtifactDir(workspace, slot)
+	if err != nil {
+		return true
+	}
+	f, err := os.OpenFile(filepath.Join(artifactDir, completeClaimFileName), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return !os.IsExist(err)
+	}
+	_ = f.Close()
+	return true
+}
+
+// releaseComplete removes the slot's completion claim so its next
+// completion notifies again.
+func releaseComplete(workspace string, slot int) {
+	artifactDir, err := GetArtifactDir(workspace, slot)
+	if err != nil {
+		return
+	}
+	if err := os.Remove(filepath.Join(artifactDir, completeClaimFileName)); err != nil && !os.IsNotExist(err) {
+		log.Printf("Warning: failed to clear completion claim for %s slot %d: %v", workspace, slot, err)
+	}
+}
+
+// startCompleteCommand starts command through sh without waiting for it, so
+// a slow notifier never delays the tool call that saw the agent finish.
+func startCompleteCommand(command string, env []string) error {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Env = append(os.Environ(), env...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
+
+// watchedSlot identifies a tracked slot for watchComplete.
+type watchedSlot struct {
+	workspace string
+	slot      int
+}
+
+// watchComplete polls the tracked slots every agent_mode.idle_poll_ms so
+// on_complete_command also fires when no wait_for_idle or depends_on wait
+// is running. It returns when ctx is done.
+func (s *Server) watchComplete(ctx context.Context) {
+	ticker := time.NewTicker(s.idlePollInterval())
+	defer ticker.Stop()
+
+	busy := make(map[watchedSlot]bool)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkComplete(busy)
+		}
+	}
+}
+
+// checkComplete runs one watchComplete pass. A slot notifies when it turns
+// idle after being seen busy, so an agent idling at its prompt since spawn
+// does not fire; seeing it busy re-arms it, which also covers work typed
+// into the terminal directly. busy carries state between passes.
+func (s *Server) checkComplete(busy map[watchedSlot]bool) {
+	checkIdle := s.idleCheckFn
+	if checkIdle == nil {
+		checkIdle = s.checkIdle
+	}
+	targetExists := s.targetExistsFn
+	if targetExists == nil {
+		targetExists = s.slotTargetExists
+	}
+
+	type candidate struct {
+		key       watchedSlot
+		target    string
+		agentType string
+	}
+	s.mu.Lock()
+	var candidates []candidate
+	for workspace, slots := range s.tracked {
+		for slot, ta := range slots {
+			candidates = append(candidates, candidate{
+				key:       watchedSlot{workspace: workspace, slot: slot},
+				target:    ta.tmuxTarget,
+				agentType: ta.agentType,
+			})
+		}
+	}
+	s.mu.Unlock()
+
+	live := make(map[watchedSlot]bool, len(candidates))
+	for _, c := range candidates {
+		if strings.TrimSpace(c.target) == "" || !targetExists(c.target) {
+			continue
+		}
+		live[c.key] = true
+		if !checkIdle(c.target, c.agentType, c.key.workspace, c.key.slot) {
+			if !busy[c.key] {
+				s.resetComplete(c.key.workspace, c.key.slot)
+				busy[c.key] = true
+			}
+			continue
+		}
+		if busy[c.key] {
+			delete(busy, c.key)
+			s.notifyComplete(c.key.workspace, c.key.slot)
+		}
+	}
+	for key := range busy {
+		if !live[key] {
+			delete(busy, key)
+		}
+	}
+}
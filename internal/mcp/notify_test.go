@@ -0,0 +1,202 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1broseidon/termtile/internal/config"
+)
+
+func TestNotifyComplete_OncePerIdleTransition(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.AgentMode.OnCompleteCommand = "notify-send done"
+
+	var calls [][]string
+	s := &Server{
+		config: cfg,
+		tracked: map[string]map[int]trackedAgent{
+			"ws": {1: {agentType: "claude", tmuxTarget: "t1"}},
+		},
+		nextSlot:        map[string]int{},
+		depPollInterval: 5 * time.Millisecond,
+		targetExistsFn:  func(string) bool { return true },
+		idleCheckFn:     func(string, string, string, int) bool { return true },
+		runCompleteFn: func(command string, env []string) error {
+			if command != "notify-send done" {
+				t.Errorf("command = %q", command)
+			}
+			calls = append(calls, env)
+			return nil
+		},
+	}
+
+	// Repeated idle checks while the agent stays idle fire once.
+	for i := 0; i < 3; i++ {
+		if err := s.waitForDependencies("ws", []int{1}, 1); err != nil {
+			t.Fatalf("waitForDependencies: %v", err)
+		}
+	}
+	if len(calls) != 1 {
+		t.Fatalf("fired %d times while idle, want 1", len(calls))
+	}
+	want := []string{"TERMTILE_WORKSPACE=ws", "TERMTILE_SLOT=1", "TERMTILE_AGENT=claude"}
+	if strings.Join(calls[0], " ") != strings.Join(want, " ") {
+		t.Fatalf("env = %v, want %v", calls[0], want)
+	}
+
+	// New work re-arms the slot for its next completion.
+	s.resetComplete("ws", 1)
+	if !s.notifyComplete("ws", 1) {
+		t.Fatal("expected notify after reset")
+	}
+	if s.notifyComplete("ws", 1) {
+		t.Fatal("expected no repeat notify while idle")
+	}
+	if len(calls) != 2 {
+		t.Fatalf("fired %d times over two tasks, want 2", len(calls))
+	}
+}
+
+func TestCheckComplete_FiresOnBusyToIdle(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.AgentMode.OnCompleteCommand = "notify-send done"
+
+	idle := map[int]bool{0: true, 1: false}
+	exists := true
+	var fired []string
+	s := &Server{
+		config: cfg,
+		tracked: map[string]map[int]trackedAgent{
+			"ws": {0: {agentType: "claude", tmuxTarget: "t0"}, 1: {agentType: "codex", tmuxTarget: "t1"}},
+		},
+		targetExistsFn: func(string) bool { return exists },
+		idleCheckFn:    func(_, _, _ string, slot int) bool { return idle[slot] },
+		runCompleteFn: func(_ string, env []string) error {
+			fired = append(fired, env[1])
+			return nil
+		},
+	}
+	busy := make(map[watchedSlot]bool)
+
+	// Slot 0 has been idle since spawn and slot 1 is working: nothing fires.
+	s.checkComplete(busy)
+	if len(fired) != 0 {
+		t.Fatalf("fired %v before any task finished", fired)
+	}
+
+	// Slot 1 finishes and fires once, however long it stays idle.
+	idle[1] = true
+	s.checkComplete(busy)
+	s.checkComplete(busy)
+	if strings.Join(fired, ",") != "TERMTILE_SLOT=1" {
+		t.Fatalf("fired %v, want slot 1 once", fired)
+	}
+
+	// Work seen later re-arms the slot without send_to_agent.
+	idle[1] = false
+	s.checkComplete(busy)
+	idle[1] = true
+	s.checkComplete(busy)
+	if len(fired) != 2 {
+		t.Fatalf("fired %d times over two tasks, want 2", len(fired))
+	}
+
+	// A vanished session is forgotten rather than notified.
+	idle[1] = false
+	s.checkComplete(busy)
+	exists = false
+	s.checkComplete(busy)
+	if len(busy) != 0 || len(fired) != 2 {
+		t.Fatalf("busy=%v fired=%v after session vanished", busy, fired)
+	}
+}
+
+func TestNotifyComplete_ClaimSharedAcrossServers(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.AgentMode.OnCompleteCommand = "notify-send done"
+
+	fired := 0
+	newServer := func() *Server {
+		return &Server{
+			config: cfg,
+			tracked: map[string]map[int]trackedAgent{
+				"ws": {1: {agentType: "claude", tmuxTarget: "t1"}},
+			},
+			runCompleteFn: func(string, []string) error {
+				fired++
+				return nil
+			},
+		}
+	}
+	a, b := newServer(), newServer()
+
+	// Both servers see the same completion; only the first notifies.
+	if !a.notifyComplete("ws", 1) {
+		t.Fatal("expected first server to notify")
+	}
+	if b.notifyComplete("ws", 1) {
+		t.Fatal("expected second server to skip a claimed completion")
+	}
+
+	// New work seen by either server re-arms the slot for both.
+	b.resetComplete("ws", 1)
+	a.resetComplete("ws", 1)
+	if !b.notifyComplete("ws", 1) || a.notifyComplete("ws", 1) {
+		t.Fatal("expected exactly one server to notify the next completion")
+	}
+	if fired != 2 {
+		t.Fatalf("fired %d times over two completions, want 2", fired)
+	}
+}
+
+func TestNotifyComplete_DisabledOrUntracked(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	fired := 0
+	s := &Server{
+		config: config.DefaultConfig(),
+		tracked: map[string]map[int]trackedAgent{
+			"ws": {1: {agentType: "claude", tmuxTarget: "t1"}},
+		},
+		runCompleteFn: func(string, []string) error {
+			fired++
+			return nil
+		},
+	}
+	if s.notifyComplete("ws", 1) {
+		t.Fatal("expected no notify without on_complete_command")
+	}
+	s.config.AgentMode.OnCompleteCommand = "true"
+	if s.notifyComplete("ws", 2) {
+		t.Fatal("expected no notify for an untracked slot")
+	}
+	if fired != 0 {
+		t.Fatalf("fired %d times, want 0", fired)
+	}
+}
+
+func TestStartCompleteCommand_PassesEnv(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "notified")
+	command := `printf '%s %s %s' "$TERMTILE_WORKSPACE" "$TERMTILE_SLOT" "$TERMTILE_AGENT" > ` + out
+	env := []string{"TERMTILE_WORKSPACE=ws", "TERMTILE_SLOT=3", "TERMTILE_AGENT=codex"}
+	if err := startCompleteCommand(command, env); err != nil {
+		t.Fatalf("startCompleteCommand: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, err := os.ReadFile(out)
+		if err == nil && string(data) == "ws 3 codex" {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("command output = %q (err %v), want %q", data, err, "ws 3 codex")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -1,35 +1,52 @@
 package mcp
 
 import (
+	"regexp"
 	"strings"
 	"unicode"
 )
 
 const (
-	// defaultReadLines is used when read_from_agent does not pass a lines value.
+	// defaultReadLines is used when read_from_agent does not pass a lines value
+	// and no project mcp.read.default_lines applies.
 	defaultReadLines = 50
-	// maxReadLines prevents very large read_from_agent payloads from blowing up context.
+	// maxReadLines prevents very large read_from_agent payloads from blowing up
+	// context; project mcp.read.max_lines can lower it.
 	maxReadLines = 100
 	// fenceReadCaptureLines expands capture window for fenced responses without using full scrollback.
 	fenceReadCaptureLines = 400
 )
 
-// normalizeReadLines returns a bounded read line count for read_from_agent.
-func normalizeReadLines(lines int) int {
+// normalizeReadLines returns a bounded read line count for read_from_agent,
+// using defaultLines when lines is unset and capping at maxLines.
+func normalizeReadLines(lines, defaultLines, maxLines int) int {
 	if lines <= 0 {
-		return defaultReadLines
+		return defaultLines
 	}
-	if lines > maxReadLines {
-		return maxReadLines
+	if lines > maxLines {
+		return maxLines
 	}
 	return lines
 }
 
+// escapeSequenceRe matches the CSI, OSC and two-byte escape sequences tmux
+// emits for capture-pane -e.
+var escapeSequenceRe = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b[@-Z\\-_]`)
+
+// stripEscapeSequences removes terminal escape sequences from s. Output
+// captured without -e has none, so it is returned unchanged.
+func stripEscapeSequences(s string) string {
+	if !strings.ContainsRune(s, '\x1b') {
+		return s
+	}
+	return escapeSequenceRe.ReplaceAllString(s, "")
+}
+
 // cleanOutput processes raw tmux capture-pane text by removing TUI chrome,
 // collapsing excessive blank lines, and trimming leading/trailing whitespace.
-// tmux capture-pane -p already strips ANSI escapes, so no regex stripping needed.
+// Escape sequences kept by agent_mode.capture_escapes are stripped first.
 func cleanOutput(raw string) string {
-	lines := strings.Split(raw, "\n")
+	lines := strings.Split(stripEscapeSequences(raw), "\n")
 	var out []string
 	blankCount := 0
 
@@ -103,6 +120,17 @@ const (
 		fenceOpen + " and " + fenceClose + " tags. Do not include any other text outside these tags in your final response.\n\n"
 )
 
+// outputModeUsesFence reports whether tasks for an agent are wrapped in
+// fence tags: always in output_mode "both", and with response_fence in any
+// mode other than "hooks".
+func outputModeUsesFence(mode string, responseFence bool) bool {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode == "both" {
+		return true
+	}
+	return responseFence && mode != "" && mode != "hooks"
+}
+
 // wrapTaskWithFence prepends the fence instruction to the task text.
 func wrapTaskWithFence(task string) string {
 	return fenceInstruction + task
@@ -291,6 +319,24 @@ func stripControlChars(line string) string {
 	return b.String()
 }
 
+// LastResponse returns the content of the agent's most recent fenced
+// response in raw pane text, using the same extraction as read_from_agent
+// and wait_for_idle. ok is false when no complete response is present.
+func LastResponse(raw string) (string, bool) {
+	return lastResponseContent(raw)
+}
+
+// ProcessReadOutput applies read_from_agent's output handling to raw pane
+// text: with clean it drops TUI chrome, escape sequences and control
+// characters, then it keeps the last lines lines.
+func ProcessReadOutput(raw string, clean bool, lines int) string {
+	output := raw
+	if clean {
+		output = cleanOutput(output)
+	}
+	return tailOutputLines(output, lines)
+}
+
 // tailOutputLines returns at most the last maxLines lines from text.
 func tailOutputLines(text string, maxLines int) string {
 	if maxLines <= 0 || strings.TrimSpace(text) == "" {
@@ -1,7 +1,10 @@
 package mcp
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/1broseidon/termtile/internal/config"
 )
 
 func TestCleanOutput(t *testing.T) {
@@ -128,6 +131,52 @@ func TestStripControlChars(t *testing.T) {
 	}
 }
 
+func TestStripEscapeSequences(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain text", "hello world", "hello world"},
+		{"SGR colour", "\x1b[1;32mok\x1b[0m done", "ok done"},
+		{"OSC title with BEL", "\x1b]0;title\x07prompt", "prompt"},
+		{"OSC hyperlink with ST", "\x1b]8;;http://x\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"two-byte escape", "a\x1bMb", "ab"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := stripEscapeSequences(tt.input)
+			if got != tt.want {
+				t.Errorf("stripEscapeSequences(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessReadOutput_Escapes(t *testing.T) {
+	raw := "\x1b[31merror:\x1b[0m build failed\n\x1b[2m$ \x1b[0m"
+
+	if got := ProcessReadOutput(raw, true, 10); got != "error: build failed\n$ " {
+		t.Fatalf("clean output = %q", got)
+	}
+	if got := ProcessReadOutput(raw, false, 10); got != raw {
+		t.Fatalf("raw output = %q, want escapes kept", got)
+	}
+}
+
+func TestTmuxCaptureArgs(t *testing.T) {
+	plain := tmuxCaptureArgs("sess:0.0", 50, false)
+	if got, want := strings.Join(plain, " "), "capture-pane -p -J -t sess:0.0 -S -50"; got != want {
+		t.Fatalf("plain args = %q, want %q", got, want)
+	}
+
+	escaped := tmuxCaptureArgs("sess:0.0", 0, true)
+	if got, want := strings.Join(escaped, " "), "capture-pane -p -J -e -t sess:0.0 -S -"; got != want {
+		t.Fatalf("escape args = %q, want %q", got, want)
+	}
+}
+
 func TestLastNonEmptyLine(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -489,6 +538,7 @@ func TestContainsIdlePattern(t *testing.T) {
 		name    string
 		text    string
 		pattern string
+		maxLen  int // 0 = config.DefaultIdleLineMaxLen
 		want    bool
 	}{
 		{
@@ -533,11 +583,59 @@ func TestContainsIdlePattern(t *testing.T) {
 			pattern: "\u203a",
 			want:    true,
 		},
+		{
+			name:    "prompt line at default limit",
+			text:    "> " + strings.Repeat("x", 38) + "\n",
+			pattern: ">",
+			want:    false,
+		},
+		{
+			name:    "prompt just under configured limit",
+			text:    "> " + strings.Repeat("x", 57) + "\n",
+			pattern: ">",
+			maxLen:  60,
+			want:    true,
+		},
+		{
+			name:    "prompt at configured limit",
+			text:    "> " + strings.Repeat("x", 58) + "\n",
+			pattern: ">",
+			maxLen:  60,
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maxLen := tt.maxLen
+			if maxLen == 0 {
+				maxLen = config.DefaultIdleLineMaxLen
+			}
+			got := containsIdlePattern(tt.text, []string{tt.pattern}, maxLen)
+			if got != tt.want {
+				t.Errorf("containsIdlePattern() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainsIdlePattern_AnyOf(t *testing.T) {
+	patterns := []string{"\u276f", ">>>", "(plan)"}
+	tests := []struct {
+		name string
+		text string
+		want bool
+	}{
+		{name: "first pattern", text: "done\n❯ \n", want: true},
+		{name: "second pattern", text: "done\n>>> \n", want: true},
+		{name: "third pattern", text: "done\n(plan) \n", want: true},
+		{name: "no pattern", text: "Working...\n", want: false},
+		{name: "pattern only in long hint", text: ">>> " + strings.Repeat("x", 60) + "\n", want: false},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := containsIdlePattern(tt.text, tt.pattern)
+			got := containsIdlePattern(tt.text, patterns, config.DefaultIdleLineMaxLen)
 			if got != tt.want {
 				t.Errorf("containsIdlePattern() = %v, want %v", got, tt.want)
 			}
@@ -558,7 +656,7 @@ func TestNormalizeReadLines(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := normalizeReadLines(tt.input)
+			got := normalizeReadLines(tt.input, defaultReadLines, maxReadLines)
 			if got != tt.want {
 				t.Fatalf("normalizeReadLines(%d) = %d, want %d", tt.input, got, tt.want)
 			}
@@ -616,3 +714,74 @@ func TestOutputDelta(t *testing.T) {
 		})
 	}
 }
+
+func TestInlineTaskDelivery_SizeBoundary(t *testing.T) {
+	const limit = 16
+	atLimit := strings.Repeat("x", limit)
+	overLimit := atLimit + "x"
+
+	promptCfg := config.AgentConfig{PromptAsArg: true, PipeTask: true}
+	pipeCfg := config.AgentConfig{PipeTask: true}
+
+	cases := []struct {
+		name       string
+		cfg        config.AgentConfig
+		hasTask    bool
+		task       string
+		wantPrompt bool
+		wantPipe   bool
+	}{
+		{"prompt at limit", promptCfg, true, atLimit, true, false},
+		{"prompt over limit", promptCfg, true, overLimit, false, false},
+		{"pipe at limit", pipeCfg, true, atLimit, false, true},
+		{"pipe over limit", pipeCfg, true, overLimit, false, false},
+		{"no task", promptCfg, false, "", false, false},
+		{"send-keys agent", config.AgentConfig{}, true, "short", false, false},
+	}
+	for _, tc := range cases {
+		prompt, pipe := inlineTaskDelivery(tc.cfg, tc.hasTask, tc.task, limit)
+		if prompt != tc.wantPrompt || pipe != tc.wantPipe {
+			t.Errorf("%s: got prompt=%v pipe=%v, want prompt=%v pipe=%v", tc.name, prompt, pipe, tc.wantPrompt, tc.wantPipe)
+		}
+	}
+
+	// The configured limit replaces the 32KB default.
+	var mode config.AgentMode
+	big := strings.Repeat("x", config.DefaultMaxInlineTaskBytes)
+	if prompt, _ := inlineTaskDelivery(promptCfg, true, big, mode.GetMaxInlineTaskBytes()); !prompt {
+		t.Fatal("expected a task at the default limit to stay inline")
+	}
+	mode.MaxInlineTaskBytes = 1024
+	if prompt, _ := inlineTaskDelivery(promptCfg, true, big, mode.GetMaxInlineTaskBytes()); prompt {
+		t.Fatal("expected max_inline_task_bytes to move the task to send-keys")
+	}
+}
+
+func TestLastResponse_ReturnsLastOfMultipleFencedBlocks(t *testing.T) {
+	raw := strings.Join([]string{
+		"$ claude",
+		"> Respond inside [termtile-response] and [/termtile-response] tags.",
+		"[termtile-response]",
+		"first answer",
+		"[/termtile-response]",
+		"> follow-up",
+		"[termtile-response]",
+		"second answer",
+		"spans two lines",
+		"[/termtile-response]",
+		"> ",
+	}, "\n")
+	got, ok := LastResponse(raw)
+	if !ok {
+		t.Fatal("LastResponse() ok = false, want true")
+	}
+	if want := "second answer\nspans two lines"; got != want {
+		t.Fatalf("LastResponse() = %q, want %q", got, want)
+	}
+
+	// An unclosed block is still being written; the last complete one wins.
+	got, ok = LastResponse(raw + "\n[termtile-response]\npartial")
+	if !ok || got != "second answer\nspans two lines" {
+		t.Fatalf("LastResponse() with unclosed block = %q, %v", got, ok)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -53,32 +54,38 @@ func pipeFileSize(filepath string) int64 {
 	return info.Size()
 }
 
-// cleanStalePipeFiles removes /tmp/termtile-pipe-*.raw files that don't belong
-// to any currently tracked agent.
-func cleanStalePipeFiles(tracked map[string]map[int]trackedAgent) {
-	pattern := filepath.Join(os.TempDir(), "termtile-pipe-*.raw")
-	matches, err := filepath.Glob(pattern)
-	if err != nil || len(matches) == 0 {
-		return
-	}
-
-	// Build set of active pipe file paths.
-	active := make(map[string]struct{})
-	for ws, slots := range tracked {
-		for slot, ta := range slots {
-			if ta.pipeFilePath != "" {
-				active[ta.pipeFilePath] = struct{}{}
-			} else {
-				// Include the expected path even if not yet set,
-				// to avoid removing files for slots being set up.
-				active[pipeFilePath(ws, slot)] = struct{}{}
-			}
-		}
+// sweepOrphanPipeFiles removes termtile-pipe-*.raw files in dir whose tmux
+// session is not in liveSessions, whether or not the agent is tracked. Files
+// whose name does not parse as a workspace and slot are left alone, as are
+// pane-mode slots, which run in the user's own session rather than a slot
+// session. It returns the removed paths.
+func sweepOrphanPipeFiles(dir string, liveSessions map[string]bool) []string {
+	matches, err := filepath.Glob(filepath.Join(dir, "termtile-pipe-*.raw"))
+	if err != nil {
+		return nil
 	}
 
+	var removed []string
 	for _, path := range matches {
-		if _, ok := active[path]; !ok {
-			_ = os.Remove(path)
+		name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), "termtile-pipe-"), ".raw")
+		lastDash := strings.LastIndex(name, "-")
+		if lastDash <= 0 {
+			continue
+		}
+		slot, err := strconv.Atoi(name[lastDash+1:])
+		if err != nil || slot < 0 {
+			continue
+		}
+		workspace := name[:lastDash]
+		if liveSessions[SlotSessionName(workspace, slot)] {
+			continue
+		}
+		if meta, err := readAgentMeta(workspace, slot); err == nil && meta.SpawnMode == "pane" {
+			continue
+		}
+		if err := os.Remove(path); err == nil {
+			removed = append(removed, path)
 		}
 	}
+	return removed
 }
@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/1broseidon/termtile/internal/agent"
 )
 
 func TestPipeFilePath(t *testing.T) {
@@ -104,43 +106,46 @@ func TestPipeFileSize_Missing(t *testing.T) {
 	}
 }
 
-func TestCleanStalePipeFiles(t *testing.T) {
+func TestSweepOrphanPipeFiles(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
 	dir := t.TempDir()
-
-	// Override TempDir for this test by creating files in actual TempDir.
-	// We test the logic by creating real files matching the pattern.
-	active := filepath.Join(dir, "termtile-pipe-ws-0.raw")
-	stale := filepath.Join(dir, "termtile-pipe-ws-1.raw")
-
-	for _, path := range []string{active, stale} {
-		if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+	if err := writeAgentMeta("ws", 5, agentMeta{AgentType: "claude", SpawnMode: "pane"}); err != nil {
+		t.Fatalf("writeAgentMeta: %v", err)
+	}
+
+	names := []string{
+		"termtile-pipe-ws-0.raw",         // live
+		"termtile-pipe-ws-1.raw",         // dead
+		"termtile-pipe-my-project-2.raw", // live, dashed workspace name
+		"termtile-pipe-untracked-0.raw",  // dead, never tracked
+		"termtile-pipe-not-a-slot-x.raw", // unparseable, kept
+		"termtile-pipe-my.project-4.raw", // live under the sanitized session name
+		"termtile-pipe-ws-5.raw",         // pane mode, kept without a slot session
+		"unrelated.raw",                  // not a pipe file
+	}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("data"), 0644); err != nil {
 			t.Fatalf("write: %v", err)
 		}
 	}
 
-	// Simulate tracked map with only slot 0 active.
-	tracked := map[string]map[int]trackedAgent{
-		"ws": {
-			0: {pipeFilePath: active},
-		},
+	live := map[string]bool{
+		agent.SessionName("ws", 0):         true,
+		agent.SessionName("my-project", 2): true,
+		agent.SessionName("my.project", 4): true,
+		"termtile-other-9":                 true,
 	}
-
-	// cleanStalePipeFiles uses os.TempDir() for glob pattern, so we test
-	// the file matching logic manually here.
-	activeSet := make(map[string]struct{})
-	for _, slots := range tracked {
-		for _, ta := range slots {
-			if ta.pipeFilePath != "" {
-				activeSet[ta.pipeFilePath] = struct{}{}
-			}
-		}
+	removed := sweepOrphanPipeFiles(dir, live)
+	if len(removed) != 2 {
+		t.Fatalf("removed %v, want the 2 dead pipe files", removed)
 	}
 
-	// Stale file should not be in active set.
-	if _, ok := activeSet[stale]; ok {
-		t.Fatal("stale file should not be in active set")
-	}
-	if _, ok := activeSet[active]; !ok {
-		t.Fatal("active file should be in active set")
+	for _, name := range names {
+		_, err := os.Stat(filepath.Join(dir, name))
+		exists := err == nil
+		wantGone := name == "termtile-pipe-ws-1.raw" || name == "termtile-pipe-untracked-0.raw"
+		if exists == wantGone {
+			t.Errorf("%s exists=%v, want exists=%v", name, exists, !wantGone)
+		}
 	}
 }
@@ -3,9 +3,12 @@ package mcp
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"os"
 	"os/exec"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -16,6 +19,7 @@ import (
 	"github.com/1broseidon/termtile/internal/agent"
 	"github.com/1broseidon/termtile/internal/config"
 	"github.com/1broseidon/termtile/internal/ipc"
+	"github.com/1broseidon/termtile/internal/tiling"
 	workspacepkg "github.com/1broseidon/termtile/internal/workspace"
 )
 
@@ -29,37 +33,114 @@ const (
 type trackedAgent struct {
 	agentType      string
 	tmuxTarget     string // pane ID ("%5") or session target ("termtile-ws-0:0.0")
-	spawnMode      string // "pane" or "window"
+	spawnMode      string // "pane", "window" or "detached"
 	responseFence  bool   // true if fence instructions were prepended to the task
 	fencePairCount int    // baseline count of standalone close tags at last task send
 	pipeFilePath   string // path to pipe-pane output file; empty = not active
 	lastPipeSize   int64  // last stat'd file size for cheap change detection
+	lastPipeTags   int    // close tags counted when lastPipeSize was recorded
+	completed      bool   // on_complete_command already ran for the current task
+
+	spawnedAt    time.Time // when the slot was tracked; zero for agents recovered at startup
+	lastActivity time.Time // last observed output: pipe file growth or a changed read capture
 }
 
 // Server is the MCP server for termtile agent orchestration.
 type Server struct {
 	mcpServer   *mcpsdk.Server
 	config      *config.Config
-	multiplexer *agent.TmuxMultiplexer
-	logger      *agent.Logger
+	multiplexer agent.Multiplexer
+	// sessionMux is multiplexer when agent_mode.multiplexer selects one
+	// other than tmux (GNU screen); while it is nil the slot helpers drive
+	// tmux targets directly. Such multiplexers address whole sessions, so a
+	// slot target "<session>:0.0" is reduced to its session name, and pane
+	// spawns and pipe-pane capture are not available.
+	sessionMux agent.Multiplexer
+	logger     *agent.Logger
 
 	mu       sync.Mutex
 	tracked  map[string]map[int]trackedAgent // workspace -> slot -> info
 	nextSlot map[string]int                  // legacy; slot allocation now uses lowest free tracked slot
 	// readSnapshots stores the most recent read_from_agent output per workspace/slot.
 	readSnapshots map[string]map[int]string // workspace -> slot -> output snapshot
+	// artifactMarks records the last get_artifact cursor per workspace/slot.
+	artifactMarks map[string]map[int]artifactMark
 
 	// Dependency waiting hooks (primarily for tests).
-	idleCheckFn     func(target, agentType, workspace string, slot int) bool
-	targetExistsFn  func(target string) bool
+	idleCheckFn    func(target, agentType, workspace string, slot int) bool
+	targetExistsFn func(target string) bool
+	serverAliveFn  func() bool
+	// sessionExistsFn replaces tmux has-session while a spawned window
+	// starts (primarily for tests).
+	sessionExistsFn func(session string) bool
+	// depPollInterval paces depends_on and wait_for_idle polling
+	// (agent_mode.idle_poll_ms).
 	depPollInterval time.Duration
+	// display is the shared X11 connection for spawn focus checks; nil
+	// unless agent_mode.reuse_display_connection is set.
+	display *displayConn
+	// spawnsActive counts each workspace's spawns holding a slot under
+	// agent_mode.max_concurrent_spawns; spawnFreed is closed to wake
+	// waiting spawns when one is released.
+	spawnsActive map[string]int
+	spawnFreed   chan struct{}
+	// spawnAgentFn replaces spawnAgentWithDependencies (primarily for
+	// tests).
+	spawnAgentFn func(workspaceName, agentType, cwd, agentCmd, spawnMode, sessionName string, responseFence bool, agentCfg config.AgentConfig, dependsOn []int, dependsOnTimeout int, preCommandFn func(string, int) error) (string, int, error)
+	// runCompleteFn starts agent_mode.on_complete_command (primarily for
+	// tests); nil uses startCompleteCommand.
+	runCompleteFn func(command string, env []string) error
+	// renameSessionFn replaces multiplexer.RenameSession when slots are
+	// compacted (primarily for tests).
+	renameSessionFn func(oldName, newName string) error
+	// retileFn replaces the daemon re-tile in triggerRetile (primarily for
+	// tests); nil uses retileViaDaemon.
+	retileFn func(defaultLayout string)
+	// sendKeysFn replaces slotSendKeys in send_to_agent (primarily for
+	// tests).
+	sendKeysFn func(target, text string) error
+	// sendInputFn replaces sendAgentInput for spawn-time input (primarily
+	// for tests).
+	sendInputFn func(target, text string, clearInput bool) error
+	// clearInputFn replaces slotClearInputLine in sendAgentInput
+	// (primarily for tests).
+	clearInputFn func(target string) error
+}
+
+// agentModeConfig returns the agent_mode settings, or nil when the server
+// has no config (getters on a nil *AgentMode return defaults).
+func (s *Server) agentModeConfig() *config.AgentMode {
+	if s == nil || s.config == nil {
+		return nil
+	}
+	return &s.config.AgentMode
+}
+
+// idlePollInterval returns the poll interval for idle and dependency waits.
+func (s *Server) idlePollInterval() time.Duration {
+	if s.depPollInterval > 0 {
+		return s.depPollInterval
+	}
+	return s.agentModeConfig().GetIdlePollInterval()
 }
 
-// NewServer creates a new MCP server backed by tmux.
+// captureEscapes reports whether read_from_agent captures keep escape
+// sequences (agent_mode.capture_escapes).
+func (s *Server) captureEscapes() bool {
+	return s.agentModeConfig().GetCaptureEscapes()
+}
+
+// NewServer creates a new MCP server backed by the multiplexer
+// agent_mode.multiplexer selects. Under tmux the tools drive panes directly;
+// other multiplexers are driven through the server's sessionMux.
 func NewServer(cfg *config.Config) (*Server, error) {
-	mux := agent.NewTmuxMultiplexer()
+	mux := agent.MultiplexerFor(cfg)
 	if !mux.Available() {
-		return nil, fmt.Errorf("tmux is required for MCP server but not found in PATH")
+		return nil, fmt.Errorf("%s is required for MCP server but not found in PATH", mux.Name())
+	}
+	var sessionMux agent.Multiplexer
+	if mux.Name() != "tmux" {
+		sessionMux = mux
 	}
 
 	logCfg := cfg.GetLoggingConfig()
@@ -84,12 +165,15 @@ func NewServer(cfg *config.Config) (*Server, error) {
 	s := &Server{
 		config:          cfg,
 		multiplexer:     mux,
+		sessionMux:      sessionMux,
 		logger:          logger,
 		tracked:         make(map[string]map[int]trackedAgent),
 		nextSlot:        make(map[string]int),
 		readSnapshots:   make(map[string]map[int]string),
-		targetExistsFn:  tmuxTargetExists,
-		depPollInterval: 2 * time.Second,
+		depPollInterval: cfg.AgentMode.GetIdlePollInterval(),
+	}
+	if cfg.AgentMode.GetReuseDisplayConnection() {
+		s.display = &displayConn{}
 	}
 	s.idleCheckFn = s.checkIdle
 	s.reconcile()
@@ -106,14 +190,27 @@ func NewServer(cfg *config.Config) (*Server, error) {
 	return s, nil
 }
 
-// Run starts the MCP server on stdio transport, blocking until done.
+// Run starts the MCP server on stdio transport, blocking until done. When
+// agent_mode.on_complete_command is set it also watches the tracked slots
+// for finished tasks.
 func (s *Server) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if mode := s.agentModeConfig(); mode != nil && strings.TrimSpace(mode.OnCompleteCommand) != "" {
+		go s.watchComplete(ctx)
+	}
 	return s.mcpServer.Run(ctx, &mcpsdk.StdioTransport{})
 }
 
 // Close releases server resources.
 func (s *Server) Close() error {
-	if s == nil || s.logger == nil {
+	if s == nil {
+		return nil
+	}
+	if s.display != nil {
+		s.display.close()
+	}
+	if s.logger == nil {
 		return nil
 	}
 	return s.logger.Close()
@@ -122,8 +219,8 @@ func (s *Server) Close() error {
 func (s *Server) registerTools() {
 	mcpsdk.AddTool(s.mcpServer, &mcpsdk.Tool{
 		Name:        "spawn_agent",
-		Description: "Spawn a new AI agent in a terminal slot. The agent type must be configured in termtile's agents config. Uses the active workspace by default; pass workspace explicitly when no active workspace is available. Optionally wait for other slots to become idle first via depends_on (polling every 2s up to depends_on_timeout, default 300s). Returns the slot number for future reference.",
-	}, s.handleSpawnAgent)
+		Description: "Spawn a new AI agent in a terminal slot. The agent type must be configured in termtile's agents config; when agent_type is omitted the workspace's default_agent is used. Uses the active workspace by default; pass workspace explicitly when no active workspace is available. Optionally wait for other slots to become idle first via depends_on (polling every agent_mode.idle_poll_ms, default 2s, up to depends_on_timeout, default agent_mode.default_dep_timeout_s or 300s). Returns the slot number for future reference. On failure the result is an error whose structured output has error and a machine-readable reason (e.g. unknown_agent, no_terminal, template_missing, tmux_timeout).",
+	}, s.spawnAgentTool)
 
 	mcpsdk.AddTool(s.mcpServer, &mcpsdk.Tool{
 		Name:        "send_to_agent",
@@ -132,7 +229,7 @@ func (s *Server) registerTools() {
 
 	mcpsdk.AddTool(s.mcpServer, &mcpsdk.Tool{
 		Name:        "read_from_agent",
-		Description: "Read the current terminal output from an agent's slot. Returns a bounded tail window (default 50 lines, max 100). Optionally wait for a specific text pattern or return only output since the previous read via since_last.",
+		Description: "Read the current terminal output from an agent's slot. Returns a bounded tail window (default 50 lines, max 100). Optionally wait for a specific text pattern or return only output since the previous read via since_last. After kill_agent, returns the final output saved at kill with ended set.",
 	}, s.handleReadFromAgent)
 
 	mcpsdk.AddTool(s.mcpServer, &mcpsdk.Tool{
@@ -142,23 +239,28 @@ func (s *Server) registerTools() {
 
 	mcpsdk.AddTool(s.mcpServer, &mcpsdk.Tool{
 		Name:        "get_artifact",
-		Description: "Fetch the last captured output artifact for a workspace slot from output.json on disk.",
+		Description: "Fetch the last captured output artifact for a workspace slot from output.json on disk. Pass the cursor from a previous response as since to get only output appended after that fetch.",
 	}, s.handleGetArtifact)
 
 	mcpsdk.AddTool(s.mcpServer, &mcpsdk.Tool{
 		Name:        "list_agents",
-		Description: "List all running agents in a workspace with their status (idle/busy, current command).",
+		Description: "List all running agents in a workspace with their status (idle/busy, current command, uptime, last activity).",
 	}, s.handleListAgents)
 
 	mcpsdk.AddTool(s.mcpServer, &mcpsdk.Tool{
 		Name:        "kill_agent",
-		Description: "Kill an agent running in a specific terminal slot by destroying its tmux session.",
+		Description: "Kill an agent running in a specific terminal slot by destroying its tmux session. Its final output stays readable via read_from_agent until the slot is reused.",
 	}, s.handleKillAgent)
 
 	mcpsdk.AddTool(s.mcpServer, &mcpsdk.Tool{
 		Name:        "move_terminal",
 		Description: "Move a terminal from one workspace to another. Moves the X11 window to the target desktop, renames the tmux session, and updates workspace state.",
 	}, s.handleMoveTerminal)
+
+	mcpsdk.AddTool(s.mcpServer, &mcpsdk.Tool{
+		Name:        "get_logs",
+		Description: "Return recent entries from the agent action log (logging.file) for a workspace, oldest first. Filter by slot and action (e.g. spawn_agent); limit keeps the most recent entries (default 50, max 500). Requires logging.enabled.",
+	}, s.handleGetLogs)
 }
 
 func (s *Server) waitForDependencies(workspace string, slots []int, timeoutSeconds int) error {
@@ -185,13 +287,10 @@ func (s *Server) waitForDependencies(workspace string, slots []int, timeoutSecon
 
 	timeout := time.Duration(timeoutSeconds) * time.Second
 	if timeout <= 0 {
-		timeout = 300 * time.Second
+		timeout = s.agentModeConfig().GetDefaultDepTimeout()
 	}
 
-	poll := s.depPollInterval
-	if poll <= 0 {
-		poll = 2 * time.Second
-	}
+	poll := s.idlePollInterval()
 
 	checkIdle := s.idleCheckFn
 	if checkIdle == nil {
@@ -199,7 +298,7 @@ func (s *Server) waitForDependencies(workspace string, slots []int, timeoutSecon
 	}
 	targetExists := s.targetExistsFn
 	if targetExists == nil {
-		targetExists = tmuxTargetExists
+		targetExists = s.slotTargetExists
 	}
 
 	checkAll := func() (bool, error) {
@@ -219,6 +318,7 @@ func (s *Server) waitForDependencies(workspace string, slots []int, timeoutSecon
 			if !checkIdle(target, agentType, workspace, slot) {
 				return false, nil
 			}
+			s.notifyComplete(workspace, slot)
 		}
 		return true, nil
 	}
@@ -252,11 +352,10 @@ func (s *Server) waitForDependencies(workspace string, slots []int, timeoutSecon
 // reconcile rebuilds startup tracking state from existing termtile tmux sessions
 // and cleans stale workspace registry entries whose tmux sessions no longer exist.
 func (s *Server) reconcile() {
-	out, err := exec.Command("tmux", "list-sessions", "-F", "#{session_name}").Output()
+	sessionNames, err := s.listSlotSessions()
 	if err != nil {
 		return
 	}
-	sessionNames := strings.Split(strings.TrimSpace(string(out)), "\n")
 	s.reconcileSessionNames(sessionNames)
 
 	// Build a set of live tmux session names for quick lookup.
@@ -268,6 +367,12 @@ func (s *Server) reconcile() {
 	// Restore any orphaned project file hook injections.
 	reconcileHookFileState(liveSessions)
 
+	// Remove pipe files left by sessions that no longer exist, including
+	// ones for workspaces that were not reconstructed into tracking.
+	if removed := sweepOrphanPipeFiles(os.TempDir(), liveSessions); len(removed) > 0 {
+		log.Printf("reconcile: removed %d orphaned pipe file(s)", len(removed))
+	}
+
 	// Clean workspace registry entries whose tmux sessions are gone.
 	allSlots, err := workspacepkg.GetAllSlots()
 	if err != nil {
@@ -309,34 +414,63 @@ func (s *Server) workspaceCwd(workspace string) string {
 		bestTarget = agent.TargetForSession(agent.SessionName(workspace, 0))
 	}
 
-	out, err := exec.Command("tmux", "display-message", "-t", bestTarget, "-p", "#{pane_current_path}").Output()
+	return s.slotCurrentPath(bestTarget)
+}
+
+// paneCurrentPath returns the current working directory of a tmux target, or
+// empty string if tmux cannot report it.
+func paneCurrentPath(target string) string {
+	out, err := exec.Command("tmux", "display-message", "-t", target, "-p", "#{pane_current_path}").Output()
 	if err != nil {
 		return ""
 	}
 	return strings.TrimSpace(string(out))
 }
 
+// parseDerivedSessionName splits a termtile-<workspace>-<slot> session name.
+func parseDerivedSessionName(sessionName string) (string, int, bool) {
+	if !agent.IsDerivedSessionName(sessionName) {
+		return "", 0, false
+	}
+	trimmed := strings.TrimPrefix(sessionName, "termtile-")
+	lastDash := strings.LastIndex(trimmed, "-")
+	if lastDash <= 0 || lastDash == len(trimmed)-1 {
+		return "", 0, false
+	}
+	slot, err := strconv.Atoi(trimmed[lastDash+1:])
+	if err != nil || slot < 0 {
+		return "", 0, false
+	}
+	return trimmed[:lastDash], slot, true
+}
+
 // reconcileSessionNames applies reconcile logic over a provided session list.
+// Sessions spawned with a session_name override are matched through the
+// agent meta recorded at spawn time.
 func (s *Server) reconcileSessionNames(sessionNames []string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	var custom map[string]customSession
 	for _, sessionName := range sessionNames {
 		sessionName = strings.TrimSpace(sessionName)
-		if !strings.HasPrefix(sessionName, "termtile-") {
-			continue
-		}
-
-		trimmed := strings.TrimPrefix(sessionName, "termtile-")
-		lastDash := strings.LastIndex(trimmed, "-")
-		if lastDash <= 0 || lastDash == len(trimmed)-1 {
+		if sessionName == "" {
 			continue
 		}
 
-		workspace := trimmed[:lastDash]
-		slot, err := strconv.Atoi(trimmed[lastDash+1:])
-		if err != nil || slot < 0 {
-			continue
+		workspace, slot, ok := parseDerivedSessionName(sessionName)
+		if !ok {
+			if agent.IsDerivedSessionName(sessionName) {
+				continue
+			}
+			if custom == nil {
+				custom = customSessionSlots()
+			}
+			cs, found := custom[sessionName]
+			if !found {
+				continue
+			}
+			workspace, slot = cs.workspace, cs.slot
 		}
 		isInRegistry := workspacepkg.HasSessionInRegistry(sessionName)
 
@@ -349,16 +483,18 @@ func (s *Server) reconcileSessionNames(sessionNames []string) {
 		if s.tracked[workspace] == nil {
 			s.tracked[workspace] = make(map[int]trackedAgent)
 		}
+		// Orphans are assumed to be window-mode unless the spawn meta says
+		// the session was started detached.
+		spawnMode := "window"
+		if meta, err := readAgentMeta(workspace, slot); err == nil && meta.SpawnMode == "detached" {
+			spawnMode = "detached"
+		}
 		s.tracked[workspace][slot] = trackedAgent{
 			agentType:  "unknown",
 			tmuxTarget: agent.TargetForSession(sessionName),
-			spawnMode:  "window",
+			spawnMode:  spawnMode,
 		}
-
 	}
-
-	// Clean up stale pipe files from previous runs.
-	cleanStalePipeFiles(s.tracked)
 }
 
 // resolveSpawnMode determines the spawn mode from the request and agent config.
@@ -370,8 +506,9 @@ func resolveSpawnMode(window *bool, agentSpawnMode string) string {
 		}
 		return "pane"
 	}
-	if agentSpawnMode == "window" {
-		return "window"
+	switch agentSpawnMode {
+	case "window", "detached":
+		return agentSpawnMode
 	}
 	return "pane"
 }
@@ -385,24 +522,110 @@ func (s *Server) anyPaneModeTarget(workspace string) string {
 
 	ws := s.tracked[workspace]
 	for slot, ta := range ws {
-		if ta.spawnMode == "window" {
+		if ta.spawnMode == "window" || ta.spawnMode == "detached" {
 			continue
 		}
-		if tmuxTargetExists(ta.tmuxTarget) {
+		if s.targetExists(ta.tmuxTarget) {
 			return ta.tmuxTarget
 		}
 		// Target was killed externally — prune it.
-		delete(ws, slot)
-		s.clearReadSnapshot(workspace, slot)
+		s.untrackLocked(workspace, slot)
 	}
 	return ""
 }
 
+// errTmuxServerDown is returned when a tmux operation fails because the tmux
+// server itself has exited, taking every agent session with it.
+var errTmuxServerDown = errors.New("tmux server not running; sessions lost")
+
 // tmuxTargetExists checks whether a tmux target (pane ID or session) is still alive.
 func tmuxTargetExists(target string) bool {
 	return exec.Command("tmux", "display-message", "-t", target, "-p", "").Run() == nil
 }
 
+// tmuxServerRunning reports whether a tmux server is reachable.
+func tmuxServerRunning() bool {
+	return exec.Command("tmux", "list-sessions").Run() == nil
+}
+
+func (s *Server) targetExists(target string) bool {
+	if s.targetExistsFn != nil {
+		return s.targetExistsFn(target)
+	}
+	return s.slotTargetExists(target)
+}
+
+// checkTmuxServer returns errTmuxServerDown when the tmux server is gone. All
+// tracked slots are dropped in that case since none of their sessions survive.
+func (s *Server) checkTmuxServer() error {
+	alive := s.serverAliveFn
+	if alive == nil {
+		alive = s.slotServerRunning
+	}
+	if alive() {
+		return nil
+	}
+
+	s.mu.Lock()
+	for workspace, slots := range s.tracked {
+		for slot := range slots {
+			s.untrackLocked(workspace, slot)
+		}
+	}
+	s.mu.Unlock()
+	return errTmuxServerDown
+}
+
+// tmuxError replaces err with errTmuxServerDown when a failed tmux operation
+// was caused by the server having exited.
+func (s *Server) tmuxError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if downErr := s.checkTmuxServer(); downErr != nil {
+		return downErr
+	}
+	return err
+}
+
+// pruneDeadSlots drops tracked slots in workspace whose tmux targets no longer
+// exist and returns the pruned slot numbers in ascending order.
+func (s *Server) pruneDeadSlots(workspace string) []int {
+	tracked := s.getTracked(workspace)
+
+	var dead []int
+	for slot, ta := range tracked {
+		if !s.targetExists(ta.tmuxTarget) {
+			dead = append(dead, slot)
+		}
+	}
+	if len(dead) == 0 {
+		return nil
+	}
+	sort.Ints(dead)
+
+	s.mu.Lock()
+	for _, slot := range dead {
+		s.untrackLocked(workspace, slot)
+	}
+	s.mu.Unlock()
+	return dead
+}
+
+// untrackLocked removes a slot, its read snapshot and its artifact cursor.
+// Caller must hold s.mu.
+func (s *Server) untrackLocked(workspace string, slot int) {
+	if ws := s.tracked[workspace]; ws != nil {
+		delete(ws, slot)
+	}
+	if rs := s.readSnapshots[workspace]; rs != nil {
+		delete(rs, slot)
+	}
+	if marks := s.artifactMarks[workspace]; marks != nil {
+		delete(marks, slot)
+	}
+}
+
 // findAttachedSession returns the name of the most recently active attached
 // tmux session, or empty string if none found.
 func findAttachedSession() string {
@@ -475,6 +698,7 @@ func (s *Server) trackSlotLocked(workspace string, slot int, agentType, tmuxTarg
 		spawnMode:      spawnMode,
 		responseFence:  responseFence,
 		fencePairCount: 0,
+		spawnedAt:      time.Now(),
 	}
 }
 
@@ -498,12 +722,7 @@ func (s *Server) updateTmuxTarget(workspace string, slot int, tmuxTarget string)
 // removeTracked removes a slot from the tracking map.
 func (s *Server) removeTracked(workspace string, slot int) {
 	s.mu.Lock()
-	if ws, ok := s.tracked[workspace]; ok {
-		delete(ws, slot)
-	}
-	if rs, ok := s.readSnapshots[workspace]; ok {
-		delete(rs, slot)
-	}
+	s.untrackLocked(workspace, slot)
 	s.mu.Unlock()
 }
 
@@ -556,9 +775,40 @@ func (s *Server) setReadSnapshot(workspace string, slot int, output string) {
 	if s.readSnapshots[workspace] == nil {
 		s.readSnapshots[workspace] = make(map[int]string)
 	}
+	if prev, ok := s.readSnapshots[workspace][slot]; ok && prev != output {
+		s.markActivityLocked(workspace, slot)
+	}
 	s.readSnapshots[workspace][slot] = output
 }
 
+// markActivityLocked records that a tracked slot produced output just now.
+func (s *Server) markActivityLocked(workspace string, slot int) {
+	ta, ok := s.tracked[workspace][slot]
+	if !ok {
+		return
+	}
+	ta.lastActivity = time.Now()
+	s.tracked[workspace][slot] = ta
+}
+
+func (s *Server) getArtifactMark(workspace string, slot int) artifactMark {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.artifactMarks[workspace][slot]
+}
+
+func (s *Server) setArtifactMark(workspace string, slot int, mark artifactMark) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.artifactMarks == nil {
+		s.artifactMarks = make(map[string]map[int]artifactMark)
+	}
+	if s.artifactMarks[workspace] == nil {
+		s.artifactMarks[workspace] = make(map[int]artifactMark)
+	}
+	s.artifactMarks[workspace][slot] = mark
+}
+
 func (s *Server) clearReadSnapshot(workspace string, slot int) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -601,7 +851,7 @@ func shellQuote(s string) string {
 //	    read and count close tags against the baseline.
 //	Tier 0b (capture-pane fallback): Existing close-tag counting via capture-pane,
 //	    used when no pipe file is active or pipe read fails.
-//	Tier 1: Content-based detection via IdlePattern.
+//	Tier 1: Content-based detection via idle_pattern / idle_patterns.
 //	Tier 2: Process-based fallback (pane child process check).
 func (s *Server) checkIdle(target, agentType, workspace string, slot int) bool {
 	hasFence, baselineCount := s.getFenceState(workspace, slot)
@@ -612,13 +862,14 @@ func (s *Server) checkIdle(target, agentType, workspace string, slot int) bool {
 		if pipePath != "" {
 			currentSize := pipeFileSize(pipePath)
 			if currentSize <= lastSize {
-				// File size unchanged — agent still working.
-				return false
+				// File size unchanged — answer from the last count, so
+				// repeated checks agree however many callers poll.
+				return s.pipeCloseTags(workspace, slot) > baselineCount
 			}
 			// Size changed — read and count close tags.
 			count, size, err := countCloseTagsInPipeFile(pipePath)
 			if err == nil {
-				s.updateLastPipeSize(workspace, slot, size)
+				s.updateLastPipeSize(workspace, slot, size, count)
 				if count > baselineCount {
 					return true
 				}
@@ -629,7 +880,7 @@ func (s *Server) checkIdle(target, agentType, workspace string, slot int) bool {
 		}
 
 		// Tier 0b: capture-pane fallback for fence detection.
-		out, err := tmuxCapturePane(target, 30)
+		out, err := s.slotCapturePane(target, 30)
 		if err != nil {
 			return false
 		}
@@ -644,17 +895,23 @@ func (s *Server) checkIdle(target, agentType, workspace string, slot int) bool {
 	}
 
 	// No fence — use capture-pane for Tier 1/2.
-	out, err := tmuxCapturePane(target, 30)
+	out, err := s.slotCapturePane(target, 30)
 	if err != nil {
 		return false
 	}
 
-	// Tier 1: content-based detection via IdlePattern.
-	if agentCfg, ok := s.config.Agents[agentType]; ok && agentCfg.IdlePattern != "" {
-		return containsIdlePattern(out, agentCfg.IdlePattern)
+	// Tier 1: content-based detection via the agent's idle patterns.
+	if agentCfg, ok := s.config.Agents[agentType]; ok {
+		if patterns := agentCfg.GetIdlePatterns(); len(patterns) > 0 {
+			return containsIdlePattern(out, patterns, agentCfg.GetIdleLineMaxLen())
+		}
 	}
 
-	// Tier 2: process-based detection for shell agents.
+	// Tier 2: process-based detection for shell agents. Only tmux reports
+	// the pane's process.
+	if s.sessionMux != nil {
+		return false
+	}
 	cmd := exec.Command("tmux", "display-message", "-t", target, "-p", "#{pane_pid}")
 	pidOut, err := cmd.Output()
 	if err != nil {
@@ -685,12 +942,13 @@ func lastNonEmptyLine(text string) string {
 	return ""
 }
 
-// containsIdlePattern scans the last few non-empty lines of text for the
-// idle pattern. The pattern must appear at the START of a short line
-// (under 40 chars) to avoid false positives from hint/help text that
-// contains the same character (e.g. codex shows "› Use /skills..." while
-// actively working, but the actual idle prompt is just "›" on its own).
-func containsIdlePattern(text, pattern string) bool {
+// containsIdlePattern scans the last few non-empty lines of text for any of
+// the idle patterns. A pattern must appear at the START of a short line
+// (under maxLen bytes, the agent's idle_line_max_len) to avoid false
+// positives from hint/help text that contains the same character (e.g.
+// codex shows "› Use /skills..." while actively working, but the actual
+// idle prompt is just "›" on its own).
+func containsIdlePattern(text string, patterns []string, maxLen int) bool {
 	lines := strings.Split(text, "\n")
 	checked := 0
 	for i := len(lines) - 1; i >= 0 && checked < 5; i-- {
@@ -701,8 +959,12 @@ func containsIdlePattern(text, pattern string) bool {
 		// The idle prompt is typically just the pattern character (possibly
 		// followed by a short user-typed prefix). Hint lines are long
 		// sentences that happen to start with the same character.
-		if strings.HasPrefix(trimmed, pattern) && len(trimmed) < 40 {
-			return true
+		if len(trimmed) < maxLen {
+			for _, pattern := range patterns {
+				if strings.HasPrefix(trimmed, pattern) {
+					return true
+				}
+			}
 		}
 		checked++
 	}
@@ -775,7 +1037,8 @@ func (s *Server) getPipeState(workspace string, slot int) (filePath string, last
 	return ta.pipeFilePath, ta.lastPipeSize
 }
 
-// setPipeState sets the pipe file path for a tracked slot and resets lastPipeSize to 0.
+// setPipeState sets the pipe file path for a tracked slot and resets
+// lastPipeSize and lastPipeTags to 0.
 func (s *Server) setPipeState(workspace string, slot int, filePath string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -790,11 +1053,13 @@ func (s *Server) setPipeState(workspace string, slot int, filePath string) {
 	}
 	ta.pipeFilePath = filePath
 	ta.lastPipeSize = 0
+	ta.lastPipeTags = 0
 	ws[slot] = ta
 }
 
-// updateLastPipeSize updates the last recorded pipe file size for a tracked slot.
-func (s *Server) updateLastPipeSize(workspace string, slot int, size int64) {
+// updateLastPipeSize records the pipe file size for a tracked slot and the
+// close tags counted at that size.
+func (s *Server) updateLastPipeSize(workspace string, slot int, size int64, closeTags int) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -806,23 +1071,53 @@ func (s *Server) updateLastPipeSize(workspace string, slot int, size int64) {
 	if !ok {
 		return
 	}
+	if size > ta.lastPipeSize {
+		ta.lastActivity = time.Now()
+	}
 	ta.lastPipeSize = size
+	ta.lastPipeTags = closeTags
 	ws[slot] = ta
 }
 
+// pipeCloseTags returns the close tags counted at the last recorded pipe
+// file size for a tracked slot.
+func (s *Server) pipeCloseTags(workspace string, slot int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tracked[workspace][slot].lastPipeTags
+}
+
 // triggerRetile asks the termtile daemon to re-tile all terminal windows using
-// the currently active layout. This is best-effort: if the daemon is not
-// running the error is logged and silently ignored.
-func (s *Server) triggerRetile() {
+// the currently active layout, unless one of the given workspaces has
+// auto_tile disabled. This is best-effort: if the daemon is not running the
+// error is logged and silently ignored. Having no terminals to tile is not an
+// error and is ignored quietly.
+func (s *Server) triggerRetile(workspaces ...string) {
+	for _, name := range workspaces {
+		if !workspacepkg.AutoTileEnabled(name) {
+			log.Printf("auto-tile: skipped, workspace %q has auto_tile disabled", name)
+			return
+		}
+	}
+	retile := s.retileFn
+	if retile == nil {
+		retile = retileViaDaemon
+	}
+	retile(s.config.DefaultLayout)
+}
+
+// retileViaDaemon applies the daemon's active layout (or defaultLayout when
+// the daemon reports none) and tiles.
+func retileViaDaemon(defaultLayout string) {
 	client := ipc.NewClient()
 
 	// Determine layout: prefer daemon's active layout, fall back to config default.
-	layoutName := s.config.DefaultLayout
+	layoutName := defaultLayout
 	if status, err := client.GetStatus(); err == nil && status.ActiveLayout != "" {
 		layoutName = status.ActiveLayout
 	}
 
-	if err := client.ApplyLayout(layoutName, true); err != nil {
+	if err := client.ApplyLayout(layoutName, true); err != nil && !errors.Is(err, tiling.ErrNoTerminals) {
 		log.Printf("auto-tile: failed to re-tile (%s): %v", layoutName, err)
 	}
 }
@@ -878,18 +1173,28 @@ func tmuxClearInputLine(target string) error {
 	return nil
 }
 
-// tmuxCapturePane captures output from a specific tmux target.
-// If lines > 0, captures the last N lines. If lines <= 0, captures the
-// full scrollback history (using -S -). The -J flag joins wrapped lines
-// so that fence tags split across visual lines are reassembled.
-func tmuxCapturePane(target string, lines int) (string, error) {
-	args := []string{"capture-pane", "-p", "-J", "-t", target}
+// tmuxCaptureArgs builds the capture-pane arguments for target. If lines > 0,
+// captures the last N lines; otherwise the full scrollback history (-S -).
+// The -J flag joins wrapped lines so that fence tags split across visual
+// lines are reassembled. With escapes, -e keeps colour and attribute escape
+// sequences in the output.
+func tmuxCaptureArgs(target string, lines int, escapes bool) []string {
+	args := []string{"capture-pane", "-p", "-J"}
+	if escapes {
+		args = append(args, "-e")
+	}
+	args = append(args, "-t", target)
 	if lines > 0 {
 		args = append(args, "-S", fmt.Sprintf("-%d", lines))
 	} else {
 		args = append(args, "-S", "-")
 	}
-	cmd := exec.Command("tmux", args...)
+	return args
+}
+
+// tmuxCapture runs capture-pane for target; see tmuxCaptureArgs.
+func tmuxCapture(target string, lines int, escapes bool) (string, error) {
+	cmd := exec.Command("tmux", tmuxCaptureArgs(target, lines, escapes)...)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -904,7 +1209,9 @@ func tmuxCapturePane(target string, lines int) (string, error) {
 }
 
 // tmuxWaitFor polls a tmux target's output until pattern is found or timeout.
-func tmuxWaitFor(target, pattern string, timeout time.Duration, lines int) (string, error) {
+// With escapes the output keeps escape sequences, but pattern is matched
+// against the text with them stripped.
+func tmuxWaitFor(target, pattern string, timeout time.Duration, lines int, escapes bool) (string, error) {
 	if strings.TrimSpace(pattern) == "" {
 		return "", fmt.Errorf("pattern is required")
 	}
@@ -914,11 +1221,11 @@ func tmuxWaitFor(target, pattern string, timeout time.Duration, lines int) (stri
 
 	deadline := time.Now().Add(timeout)
 	for {
-		out, err := tmuxCapturePane(target, lines)
+		out, err := tmuxCapture(target, lines, escapes)
 		if err != nil {
 			return "", err
 		}
-		if strings.Contains(out, pattern) {
+		if strings.Contains(stripEscapeSequences(out), pattern) {
 			return out, nil
 		}
 		if time.Now().After(deadline) {
@@ -1,7 +1,10 @@
 package mcp
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -54,6 +57,9 @@ func TestResolveSpawnMode(t *testing.T) {
 		{"true window overrides empty config", boolPtr(true), "", "window"},
 		{"false window overrides window config", boolPtr(false), "window", "pane"},
 		{"false window, empty config → pane", boolPtr(false), "", "pane"},
+		{"nil window, detached config → detached", nil, "detached", "detached"},
+		{"true window overrides detached config", boolPtr(true), "detached", "window"},
+		{"nil window, unknown config → pane", nil, "bogus", "pane"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -125,6 +131,58 @@ func TestGetSpawnMode(t *testing.T) {
 	}
 }
 
+func TestHandleSendToAgent_NoFenceSkipsWrapButKeepsBaseline(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	cfg := config.DefaultConfig()
+	cfg.Agents["plain"] = config.AgentConfig{Command: "plain-agent", OutputMode: "tags", ResponseFence: true}
+	s := &Server{
+		config:   cfg,
+		tracked:  make(map[string]map[int]trackedAgent),
+		nextSlot: make(map[string]int),
+	}
+	s.allocateSlot("ws", "plain", "%7", "window", true)
+
+	// Two responses are already in the pipe capture.
+	pipePath := filepath.Join(t.TempDir(), "pipe.raw")
+	earlier := "[termtile-response]\none\n" + fenceClose + "\n[termtile-response]\ntwo\n" + fenceClose + "\n"
+	if err := os.WriteFile(pipePath, []byte(earlier), 0644); err != nil {
+		t.Fatalf("write pipe file: %v", err)
+	}
+	s.setPipeState("ws", 0, pipePath)
+
+	var sent []string
+	s.sendKeysFn = func(target, text string) error {
+		sent = append(sent, text)
+		return nil
+	}
+
+	if _, _, err := s.handleSendToAgent(nil, nil, SendToAgentInput{Slot: 0, Text: "ls -la", Workspace: "ws", NoFence: true}); err != nil {
+		t.Fatalf("send no_fence: %v", err)
+	}
+	if len(sent) != 1 || sent[0] != "ls -la" {
+		t.Fatalf("sent = %q, want raw text", sent)
+	}
+	if hasFence, baseline := s.getFenceState("ws", 0); hasFence || baseline != 2 {
+		t.Fatalf("fence state = (%v, %d), want (false, 2)", hasFence, baseline)
+	}
+	if _, size := s.getPipeState("ws", 0); size != int64(len(earlier)) {
+		t.Fatalf("last pipe size = %d, want %d", size, len(earlier))
+	}
+
+	// The next fenced send wraps again and keeps counting from the baseline.
+	if _, _, err := s.handleSendToAgent(nil, nil, SendToAgentInput{Slot: 0, Text: "summarize", Workspace: "ws"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if len(sent) != 2 || sent[1] != wrapTaskWithFence("summarize") {
+		t.Fatalf("sent = %q, want fenced text", sent)
+	}
+	if hasFence, baseline := s.getFenceState("ws", 0); !hasFence || baseline != 2 {
+		t.Fatalf("fence state = (%v, %d), want (true, 2)", hasFence, baseline)
+	}
+}
+
 func TestUpdateFenceState(t *testing.T) {
 	s := &Server{
 		config:   config.DefaultConfig(),
@@ -204,7 +262,7 @@ func TestPipeStateAccessors(t *testing.T) {
 	}
 
 	// Update last pipe size.
-	s.updateLastPipeSize("ws", slot, 1024)
+	s.updateLastPipeSize("ws", slot, 1024, 0)
 	_, size = s.getPipeState("ws", slot)
 	if size != 1024 {
 		t.Fatalf("pipe size after update = %d, want 1024", size)
@@ -212,7 +270,7 @@ func TestPipeStateAccessors(t *testing.T) {
 
 	// Non-existent slot/workspace should be no-ops.
 	s.setPipeState("ws", 999, "/tmp/ignored.raw")
-	s.updateLastPipeSize("ws", 999, 500)
+	s.updateLastPipeSize("ws", 999, 500, 0)
 	s.setPipeState("nonexistent", 0, "/tmp/ignored.raw")
 	path2, size2 := s.getPipeState("nonexistent", 0)
 	if path2 != "" || size2 != 0 {
@@ -220,6 +278,36 @@ func TestPipeStateAccessors(t *testing.T) {
 	}
 }
 
+func TestCheckIdle_PipeResultStableWithoutNewOutput(t *testing.T) {
+	s := &Server{
+		config:   config.DefaultConfig(),
+		tracked:  make(map[string]map[int]trackedAgent),
+		nextSlot: make(map[string]int),
+	}
+	slot := s.allocateSlot("ws", "codex", "termtile-ws-0:0.0", "window", true)
+
+	pipePath := filepath.Join(t.TempDir(), "pipe.raw")
+	if err := os.WriteFile(pipePath, []byte("working\n"), 0644); err != nil {
+		t.Fatalf("write pipe file: %v", err)
+	}
+	s.setPipeState("ws", slot, pipePath)
+	s.updateFenceState("ws", slot, true, 0)
+
+	if s.checkIdle("termtile-ws-0:0.0", "codex", "ws", slot) {
+		t.Fatal("idle before any response")
+	}
+	response := "working\n[termtile-response]\ndone\n" + fenceClose + "\n"
+	if err := os.WriteFile(pipePath, []byte(response), 0644); err != nil {
+		t.Fatalf("write pipe file: %v", err)
+	}
+	// Every poller sees the response, not just the first one to check.
+	for i := 0; i < 3; i++ {
+		if !s.checkIdle("termtile-ws-0:0.0", "codex", "ws", slot) {
+			t.Fatalf("check %d: not idle after the response", i)
+		}
+	}
+}
+
 func TestMoveTerminalTracking(t *testing.T) {
 	s := &Server{
 		config:      config.DefaultConfig(),
@@ -406,6 +494,139 @@ func TestHandleKillAgent_CleansArtifactDirectory(t *testing.T) {
 	}
 }
 
+func TestHandleReadFromAgent_ProjectReadLines(t *testing.T) {
+	cfg := config.DefaultConfig()
+	allow := false
+	cfg.AgentMode.ProtectSlotZero = &allow
+	s := &Server{
+		config:        cfg,
+		tracked:       make(map[string]map[int]trackedAgent),
+		nextSlot:      make(map[string]int),
+		readSnapshots: make(map[string]map[int]string),
+	}
+
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+
+	slot := s.allocateSlot(DefaultWorkspace, "codex", "%42", "pane", false)
+	dir, err := EnsureArtifactDir(DefaultWorkspace, slot)
+	if err != nil {
+		t.Fatalf("EnsureArtifactDir: %v", err)
+	}
+	var lines []string
+	for i := 1; i <= 120; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	payload, _ := json.Marshal(map[string]string{"status": "complete", "output": strings.Join(lines, "\n")})
+	if err := os.WriteFile(filepath.Join(dir, "output.json"), payload, 0o644); err != nil {
+		t.Fatalf("failed to write artifact file: %v", err)
+	}
+	if _, _, err := s.handleKillAgent(nil, nil, KillAgentInput{Slot: slot, Workspace: DefaultWorkspace}); err != nil {
+		t.Fatalf("handleKillAgent: %v", err)
+	}
+
+	countLines := func(args ReadFromAgentInput) int {
+		t.Helper()
+		_, out, err := s.handleReadFromAgent(nil, nil, args)
+		if err != nil {
+			t.Fatalf("handleReadFromAgent: %v", err)
+		}
+		return len(strings.Split(strings.TrimSpace(out.Output), "\n"))
+	}
+
+	// Outside a project the built-in 50/100 apply.
+	t.Chdir(t.TempDir())
+	if got := countLines(ReadFromAgentInput{Slot: slot, Workspace: DefaultWorkspace}); got != defaultReadLines {
+		t.Fatalf("default read = %d lines, want %d", got, defaultReadLines)
+	}
+	if got := countLines(ReadFromAgentInput{Slot: slot, Workspace: DefaultWorkspace, Lines: 500}); got != maxReadLines {
+		t.Fatalf("capped read = %d lines, want %d", got, maxReadLines)
+	}
+
+	root := t.TempDir()
+	writeProjectWorkspaceFile(t, root, DefaultWorkspace)
+	local := "mcp:\n  read:\n    default_lines: 5\n    max_lines: 8\n"
+	if err := os.WriteFile(filepath.Join(root, ".termtile", "local.yaml"), []byte(local), 0o644); err != nil {
+		t.Fatalf("WriteFile local.yaml: %v", err)
+	}
+	t.Chdir(root)
+
+	if got := countLines(ReadFromAgentInput{Slot: slot, Workspace: DefaultWorkspace}); got != 5 {
+		t.Fatalf("project default read = %d lines, want 5", got)
+	}
+	if got := countLines(ReadFromAgentInput{Slot: slot, Workspace: DefaultWorkspace, Lines: 50}); got != 8 {
+		t.Fatalf("project capped read = %d lines, want 8", got)
+	}
+	if got := countLines(ReadFromAgentInput{Slot: slot, Workspace: DefaultWorkspace, Lines: 3}); got != 3 {
+		t.Fatalf("explicit read = %d lines, want 3", got)
+	}
+}
+
+func TestHandleReadFromAgent_AfterKillReturnsEndedOutput(t *testing.T) {
+	cfg := config.DefaultConfig()
+	allow := false
+	cfg.AgentMode.ProtectSlotZero = &allow
+
+	s := &Server{
+		config:        cfg,
+		tracked:       make(map[string]map[int]trackedAgent),
+		nextSlot:      make(map[string]int),
+		readSnapshots: make(map[string]map[int]string),
+	}
+
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+
+	slot := s.allocateSlot(DefaultWorkspace, "codex", "%42", "pane", false)
+	dir, err := EnsureArtifactDir(DefaultWorkspace, slot)
+	if err != nil {
+		t.Fatalf("EnsureArtifactDir: %v", err)
+	}
+	payload := []byte(`{"status":"complete","output":"step one\nfinal answer"}`)
+	if err := os.WriteFile(filepath.Join(dir, "output.json"), payload, 0o644); err != nil {
+		t.Fatalf("failed to write artifact file: %v", err)
+	}
+
+	if _, _, err := s.handleKillAgent(nil, nil, KillAgentInput{Slot: slot, Workspace: DefaultWorkspace}); err != nil {
+		t.Fatalf("handleKillAgent: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "output.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected output.json to be removed, stat err=%v", err)
+	}
+
+	_, out, err := s.handleReadFromAgent(nil, nil, ReadFromAgentInput{Slot: slot, Workspace: DefaultWorkspace})
+	if err != nil {
+		t.Fatalf("handleReadFromAgent after kill: %v", err)
+	}
+	if !out.Ended {
+		t.Fatal("expected Ended=true")
+	}
+	if out.SessionName != "%42" {
+		t.Fatalf("SessionName = %q, want %%42", out.SessionName)
+	}
+	if !strings.Contains(out.Output, "final answer") {
+		t.Fatalf("Output = %q, want final answer", out.Output)
+	}
+
+	_, out, err = s.handleReadFromAgent(nil, nil, ReadFromAgentInput{Slot: slot, Workspace: DefaultWorkspace, Pattern: "final answer"})
+	if err != nil {
+		t.Fatalf("handleReadFromAgent with pattern: %v", err)
+	}
+	if out.Found == nil || !*out.Found {
+		t.Fatalf("Found = %v, want true", out.Found)
+	}
+
+	// The next spawn into the slot drops the record.
+	if err := CleanStaleOutput(DefaultWorkspace, slot); err != nil {
+		t.Fatalf("CleanStaleOutput: %v", err)
+	}
+	if _, _, err := s.handleReadFromAgent(nil, nil, ReadFromAgentInput{Slot: slot, Workspace: DefaultWorkspace}); err == nil {
+		t.Fatal("expected error reading a reused slot with no ended record")
+	}
+}
+
 // containsAll checks if s contains all the given substrings.
 func containsAll(s string, subs ...string) bool {
 	for _, sub := range subs {
@@ -481,12 +702,17 @@ func TestTrackSpecificSlot_Collision(t *testing.T) {
 }
 
 func TestCompactWindowSlots_ShiftsTrackingState(t *testing.T) {
+	var renames []string
 	s := &Server{
 		config:        config.DefaultConfig(),
 		multiplexer:   agent.NewTmuxMultiplexer(),
 		tracked:       make(map[string]map[int]trackedAgent),
 		nextSlot:      make(map[string]int),
 		readSnapshots: make(map[string]map[int]string),
+		renameSessionFn: func(oldName, newName string) error {
+			renames = append(renames, oldName+"->"+newName)
+			return nil
+		},
 	}
 	base := t.TempDir()
 	t.Setenv("XDG_DATA_HOME", base)
@@ -498,6 +724,8 @@ func TestCompactWindowSlots_ShiftsTrackingState(t *testing.T) {
 	writeHookArtifactForTest(t, "ws", 3, "artifact-3")
 	s.setReadSnapshot("ws", 2, "snap-2")
 	s.setReadSnapshot("ws", 3, "snap-3")
+	s.setArtifactMark("ws", 2, artifactMark{cursor: "c2", length: 2})
+	s.setArtifactMark("ws", 3, artifactMark{cursor: "c3", length: 3})
 
 	if err := s.compactWindowSlots("ws", 1); err != nil {
 		t.Fatalf("compactWindowSlots: %v", err)
@@ -524,4 +752,530 @@ func TestCompactWindowSlots_ShiftsTrackingState(t *testing.T) {
 	if got := s.getReadSnapshot("ws", 2); got != "snap-3" {
 		t.Fatalf("read snapshot slot 2 = %q, want snap-3", got)
 	}
+	if got := s.getArtifactMark("ws", 1); got.cursor != "c2" {
+		t.Fatalf("artifact mark slot 1 = %+v, want c2", got)
+	}
+	if got := s.getArtifactMark("ws", 2); got.cursor != "c3" {
+		t.Fatalf("artifact mark slot 2 = %+v, want c3", got)
+	}
+	if got := s.getArtifactMark("ws", 3); got != (artifactMark{}) {
+		t.Fatalf("artifact mark slot 3 = %+v, want none", got)
+	}
+	want := []string{"termtile-ws-2->termtile-ws-1", "termtile-ws-3->termtile-ws-2"}
+	if strings.Join(renames, ",") != strings.Join(want, ",") {
+		t.Fatalf("renames = %v, want %v (lowest slot first)", renames, want)
+	}
+}
+
+func TestCompactWindowSlots_AggregatesRenameFailures(t *testing.T) {
+	var renames []string
+	s := &Server{
+		config:        config.DefaultConfig(),
+		tracked:       make(map[string]map[int]trackedAgent),
+		nextSlot:      make(map[string]int),
+		readSnapshots: make(map[string]map[int]string),
+		renameSessionFn: func(oldName, newName string) error {
+			renames = append(renames, oldName)
+			if oldName == "termtile-ws-3" {
+				return nil
+			}
+			return fmt.Errorf("can't find session: %s", oldName)
+		},
+	}
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	_ = s.trackSpecificSlot("ws", 0, "claude", "termtile-ws-0:0.0", "window", false)
+	_ = s.trackSpecificSlot("ws", 2, "codex", "termtile-ws-2:0.0", "window", false)
+	_ = s.trackSpecificSlot("ws", 3, "gemini", "termtile-ws-3:0.0", "window", false)
+	_ = s.trackSpecificSlot("ws", 4, "claude", "termtile-ws-4:0.0", "window", false)
+
+	err := s.compactWindowSlots("ws", 1)
+	if err == nil {
+		t.Fatal("compactWindowSlots returned nil, want aggregated rename error")
+	}
+	msg := err.Error()
+	for _, want := range []string{
+		`slot 2 -> 1: rename session "termtile-ws-2" -> "termtile-ws-1"`,
+		`slot 4 -> 3: rename session "termtile-ws-4" -> "termtile-ws-3"`,
+	} {
+		if !strings.Contains(msg, want) {
+			t.Fatalf("error %q missing %q", msg, want)
+		}
+	}
+	if strings.Contains(msg, "slot 3 -> 2") {
+		t.Fatalf("error %q reports the successful rename", msg)
+	}
+
+	// Best effort: every rename is attempted and tracking is still shifted.
+	if len(renames) != 3 {
+		t.Fatalf("renames attempted = %v, want all 3", renames)
+	}
+	for slot, want := range map[int]string{1: "termtile-ws-1:0.0", 2: "termtile-ws-2:0.0", 3: "termtile-ws-3:0.0"} {
+		if target, ok := s.getTmuxTarget("ws", slot); !ok || target != want {
+			t.Fatalf("slot %d target = %q (ok=%v), want %s", slot, target, ok, want)
+		}
+	}
+	if _, ok := s.getTmuxTarget("ws", 4); ok {
+		t.Fatal("slot 4 should not exist after compaction")
+	}
+	if !strings.Contains(compactWarning(err), "tracking may be inconsistent") {
+		t.Fatalf("compactWarning = %q", compactWarning(err))
+	}
+}
+
+func TestHandleListAgents_TmuxServerDown(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	s := &Server{
+		config: config.DefaultConfig(),
+		tracked: map[string]map[int]trackedAgent{
+			"ws":    {0: {agentType: "claude", tmuxTarget: "%1"}, 1: {agentType: "codex", tmuxTarget: "%2"}},
+			"other": {3: {agentType: "claude", tmuxTarget: "%3"}},
+		},
+		readSnapshots:  map[string]map[int]string{"ws": {0: "old output"}},
+		targetExistsFn: func(string) bool { return false },
+		serverAliveFn:  func() bool { return false },
+	}
+
+	_, out, err := s.handleListAgents(nil, nil, ListAgentsInput{Workspace: "ws"})
+	if err != nil {
+		t.Fatalf("handleListAgents: %v", err)
+	}
+	if len(out.Agents) != 2 || out.Agents[0].Exists || out.Agents[1].Exists {
+		t.Fatalf("expected the workspace's agents reported with exists=false, got %+v", out.Agents)
+	}
+	if n := len(s.getTracked("ws")) + len(s.getTracked("other")); n != 0 {
+		t.Fatalf("expected every tracked slot to be pruned, %d remain", n)
+	}
+	if len(s.readSnapshots["ws"]) != 0 {
+		t.Fatalf("expected read snapshots to be cleared, got %v", s.readSnapshots["ws"])
+	}
+
+	_, out, err = s.handleListAgents(nil, nil, ListAgentsInput{Workspace: "ws"})
+	if err != nil || len(out.Agents) != 0 {
+		t.Fatalf("second handleListAgents = %+v, %v; want empty list", out.Agents, err)
+	}
+}
+
+func TestHandleListAgents_PrunesVanishedSessions(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	s := &Server{
+		config: config.DefaultConfig(),
+		tracked: map[string]map[int]trackedAgent{
+			"ws": {0: {agentType: "claude", tmuxTarget: "%1"}, 2: {agentType: "codex", tmuxTarget: "%2"}},
+		},
+		readSnapshots:  map[string]map[int]string{},
+		targetExistsFn: func(string) bool { return false },
+		serverAliveFn:  func() bool { return true },
+	}
+
+	_, out, err := s.handleListAgents(nil, nil, ListAgentsInput{Workspace: "ws"})
+	if err != nil {
+		t.Fatalf("handleListAgents: %v", err)
+	}
+	if len(out.Agents) != 2 || out.Agents[0].Exists || out.Agents[1].Exists {
+		t.Fatalf("expected both agents reported missing once, got %+v", out.Agents)
+	}
+	if n := len(s.getTracked("ws")); n != 0 {
+		t.Fatalf("expected vanished slots to be pruned, %d remain", n)
+	}
+
+	_, out, err = s.handleListAgents(nil, nil, ListAgentsInput{Workspace: "ws"})
+	if err != nil {
+		t.Fatalf("second handleListAgents: %v", err)
+	}
+	if len(out.Agents) != 0 {
+		t.Fatalf("expected pruned agents to disappear, got %+v", out.Agents)
+	}
+}
+
+func TestPruneDeadSlots(t *testing.T) {
+	s := &Server{
+		tracked: map[string]map[int]trackedAgent{
+			"ws": {
+				0: {agentType: "a", tmuxTarget: "live"},
+				1: {agentType: "b", tmuxTarget: "dead"},
+				4: {agentType: "c", tmuxTarget: "dead"},
+			},
+		},
+		readSnapshots:  map[string]map[int]string{"ws": {1: "x", 0: "y"}},
+		targetExistsFn: func(target string) bool { return target == "live" },
+	}
+
+	dead := s.pruneDeadSlots("ws")
+	if len(dead) != 2 || dead[0] != 1 || dead[1] != 4 {
+		t.Fatalf("pruned=%v, want [1 4]", dead)
+	}
+	tracked := s.getTracked("ws")
+	if _, ok := tracked[0]; !ok || len(tracked) != 1 {
+		t.Fatalf("tracked=%v, want only slot 0", tracked)
+	}
+	if _, ok := s.readSnapshots["ws"][1]; ok {
+		t.Fatal("expected read snapshot for pruned slot to be cleared")
+	}
+	if got := s.nextAvailableSlotLocked("ws"); got != 1 {
+		t.Fatalf("next slot=%d, want freed slot 1", got)
+	}
+}
+
+func TestAnyPaneModeTarget_PrunesDeadTargets(t *testing.T) {
+	s := &Server{
+		tracked: map[string]map[int]trackedAgent{
+			"ws": {
+				1: {agentType: "a", tmuxTarget: "%1", spawnMode: "pane"},
+				2: {agentType: "b", tmuxTarget: "%2", spawnMode: "pane"},
+			},
+		},
+		readSnapshots:  map[string]map[int]string{"ws": {1: "x"}},
+		targetExistsFn: func(string) bool { return false },
+	}
+
+	if got := s.anyPaneModeTarget("ws"); got != "" {
+		t.Fatalf("anyPaneModeTarget=%q, want empty", got)
+	}
+	if n := len(s.getTracked("ws")); n != 0 {
+		t.Fatalf("expected dead pane targets pruned, %d remain", n)
+	}
+}
+
+func TestDetachedSessionArgs(t *testing.T) {
+	got := detachedSessionArgs("termtile-ws-2", "/tmp/proj", map[string]string{"B": "2", "A": "1"})
+	want := []string{"new-session", "-d", "-s", "termtile-ws-2", "-c", "/tmp/proj", "-e", "A=1", "-e", "B=2"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("args=%v, want %v", got, want)
+	}
+}
+
+// isolatedTmux points tmux at a private socket directory so the test never
+// touches the user's tmux server.
+func isolatedTmux(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not installed")
+	}
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+	t.Cleanup(func() { _ = exec.Command("tmux", "kill-server").Run() })
+}
+
+func TestReconcile_NoServerSweepsPipeFiles(t *testing.T) {
+	isolatedTmux(t)
+	tmpDir := t.TempDir()
+	t.Setenv("TMPDIR", tmpDir)
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	pipe := filepath.Join(tmpDir, "termtile-pipe-ws-1.raw")
+	if err := os.WriteFile(pipe, []byte("output"), 0o644); err != nil {
+		t.Fatalf("write pipe file: %v", err)
+	}
+	s := &Server{
+		config:   config.DefaultConfig(),
+		tracked:  make(map[string]map[int]trackedAgent),
+		nextSlot: make(map[string]int),
+	}
+
+	s.reconcile()
+	if _, err := os.Stat(pipe); !os.IsNotExist(err) {
+		t.Fatalf("pipe file kept with no tmux server running, stat err=%v", err)
+	}
+}
+
+func TestSpawnDetached_CreatesSessionAndKillCleansUp(t *testing.T) {
+	isolatedTmux(t)
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	cfg := config.DefaultConfig()
+	allow := false
+	cfg.AgentMode.ProtectSlotZero = &allow
+	s := &Server{
+		config:   cfg,
+		tracked:  make(map[string]map[int]trackedAgent),
+		nextSlot: make(map[string]int),
+	}
+
+	target, slot, err := s.spawnDetached(DefaultWorkspace, "claude", t.TempDir(), "", false, config.AgentConfig{Env: map[string]string{"TERMTILE_TEST_VAR": "x"}})
+	if err != nil {
+		t.Fatalf("spawnDetached: %v", err)
+	}
+	sessionName := agent.SessionName(DefaultWorkspace, slot)
+	if target != agent.TargetForSession(sessionName) {
+		t.Fatalf("target=%q, want session target for %q", target, sessionName)
+	}
+	if err := exec.Command("tmux", "has-session", "-t", sessionName).Run(); err != nil {
+		t.Fatalf("detached session %q not running: %v", sessionName, err)
+	}
+	if out, err := exec.Command("tmux", "show-environment", "-t", sessionName, "TERMTILE_TEST_VAR").Output(); err != nil || strings.TrimSpace(string(out)) != "TERMTILE_TEST_VAR=x" {
+		t.Fatalf("session env=%q err=%v, want agent env", out, err)
+	}
+	if got := s.getSpawnMode(DefaultWorkspace, slot); got != "detached" {
+		t.Fatalf("spawn mode=%q, want detached", got)
+	}
+
+	_, out, err := s.handleKillAgent(nil, nil, KillAgentInput{Slot: slot, Workspace: DefaultWorkspace})
+	if err != nil {
+		t.Fatalf("handleKillAgent: %v", err)
+	}
+	if !out.Killed {
+		t.Fatal("expected Killed=true")
+	}
+	if exec.Command("tmux", "has-session", "-t", sessionName).Run() == nil {
+		t.Fatalf("detached session %q still running after kill", sessionName)
+	}
+	if _, ok := s.getTmuxTarget(DefaultWorkspace, slot); ok {
+		t.Fatal("slot still tracked after kill")
+	}
+}
+
+func TestApplySessionOptions_ReportsRejectedOption(t *testing.T) {
+	isolatedTmux(t)
+	if out, err := exec.Command("tmux", "new-session", "-d", "-s", "opts").CombinedOutput(); err != nil {
+		t.Fatalf("new-session: %v (%s)", err, out)
+	}
+
+	err := applySessionOptions("opts", t.TempDir(), map[string]string{"bogus-option": "x", "mouse": "on"})
+	if err == nil || !strings.Contains(err.Error(), "bogus-option") {
+		t.Fatalf("err = %v, want the rejected bogus-option reported", err)
+	}
+	// The rejected option does not stop the ones after it.
+	out, err := exec.Command("tmux", "show-options", "-v", "-t", "opts", "mouse").Output()
+	if err != nil {
+		t.Fatalf("show-options mouse: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "on" {
+		t.Fatalf("mouse = %q, want on", got)
+	}
+
+	if err := applySessionOptions("opts", t.TempDir(), nil); err != nil {
+		t.Fatalf("no options: err = %v", err)
+	}
+}
+
+func TestSpawnDetached_AppliesTmuxSessionOptions(t *testing.T) {
+	isolatedTmux(t)
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	cfg := config.DefaultConfig()
+	cfg.AgentMode.TmuxSessionOptions = map[string]string{"mouse": "on", "status": "off", "history-limit": "12345"}
+	s := &Server{
+		config:   cfg,
+		tracked:  make(map[string]map[int]trackedAgent),
+		nextSlot: make(map[string]int),
+	}
+
+	target, slot, err := s.spawnDetached(DefaultWorkspace, "claude", t.TempDir(), "", false, config.AgentConfig{})
+	if err != nil {
+		t.Fatalf("spawnDetached: %v", err)
+	}
+	sessionName := agent.SessionName(DefaultWorkspace, slot)
+	for name, want := range cfg.AgentMode.TmuxSessionOptions {
+		out, err := exec.Command("tmux", "show-options", "-v", "-t", sessionName, name).Output()
+		if err != nil {
+			t.Fatalf("show-options %s: %v", name, err)
+		}
+		if got := strings.TrimSpace(string(out)); got != want {
+			t.Fatalf("session option %s = %q, want %q", name, got, want)
+		}
+	}
+	// history-limit must reach the pane the agent runs in, not just the
+	// session's option table.
+	out, err := exec.Command("tmux", "display-message", "-p", "-t", target, "#{history_limit}").Output()
+	if err != nil {
+		t.Fatalf("display-message %s: %v", target, err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "12345" {
+		t.Fatalf("pane history_limit = %q, want 12345", got)
+	}
+}
+
+func TestSpawnDetached_KeepsSessionWhenOptionRejected(t *testing.T) {
+	isolatedTmux(t)
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	cfg := config.DefaultConfig()
+	cfg.AgentMode.TmuxSessionOptions = map[string]string{"bogus-option": "x"}
+	s := &Server{
+		config:   cfg,
+		tracked:  make(map[string]map[int]trackedAgent),
+		nextSlot: make(map[string]int),
+	}
+
+	_, slot, err := s.spawnDetached(DefaultWorkspace, "claude", t.TempDir(), "", false, config.AgentConfig{})
+	if err != nil {
+		t.Fatalf("spawnDetached: %v", err)
+	}
+	if _, ok := s.getTmuxTarget(DefaultWorkspace, slot); !ok {
+		t.Fatal("slot no longer tracked after a rejected option")
+	}
+	if !s.sessionExists(agent.SessionName(DefaultWorkspace, slot)) {
+		t.Fatal("session missing after a rejected option")
+	}
+}
+
+func TestIsDetachedSession(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	if err := writeAgentMeta("bg", 1, agentMeta{AgentType: "codex", SpawnMode: "detached"}); err != nil {
+		t.Fatalf("writeAgentMeta: %v", err)
+	}
+	if err := writeAgentMeta("bg", 2, agentMeta{AgentType: "codex", SpawnMode: "window"}); err != nil {
+		t.Fatalf("writeAgentMeta: %v", err)
+	}
+
+	for session, want := range map[string]bool{
+		"termtile-bg-1": true,
+		"termtile-bg-2": false,
+		"termtile-bg-3": false, // no meta
+		"bg-1":          false,
+	} {
+		if got := IsDetachedSession(session); got != want {
+			t.Errorf("IsDetachedSession(%q) = %v, want %v", session, got, want)
+		}
+	}
+}
+
+func TestReconcile_RestoresDetachedModeFromMeta(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	if err := writeAgentMeta("bg", 1, agentMeta{AgentType: "codex", SpawnMode: "detached"}); err != nil {
+		t.Fatalf("writeAgentMeta: %v", err)
+	}
+
+	s := &Server{
+		config:   config.DefaultConfig(),
+		tracked:  make(map[string]map[int]trackedAgent),
+		nextSlot: make(map[string]int),
+	}
+	s.reconcileSessionNames([]string{"termtile-bg-1", "termtile-bg-2"})
+
+	if got := s.tracked["bg"][1].spawnMode; got != "detached" {
+		t.Fatalf("slot 1 spawnMode=%q, want detached", got)
+	}
+	if got := s.tracked["bg"][2].spawnMode; got != "window" {
+		t.Fatalf("slot 2 spawnMode=%q, want window", got)
+	}
+}
+
+func TestReconcile_RecoversCustomSessionNameFromMeta(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	if err := writeAgentMeta("bg", 3, agentMeta{AgentType: "codex", SpawnMode: "detached", SessionName: "my-agent"}); err != nil {
+		t.Fatalf("writeAgentMeta: %v", err)
+	}
+
+	s := &Server{
+		config:   config.DefaultConfig(),
+		tracked:  make(map[string]map[int]trackedAgent),
+		nextSlot: make(map[string]int),
+	}
+	s.reconcileSessionNames([]string{"my-agent", "unrelated-session"})
+
+	ta, ok := s.tracked["bg"][3]
+	if !ok {
+		t.Fatalf("tracked = %v, want bg slot 3", s.tracked)
+	}
+	if ta.tmuxTarget != "my-agent:0.0" || ta.spawnMode != "detached" {
+		t.Fatalf("slot 3 = %+v, want my-agent:0.0 detached", ta)
+	}
+	if got := len(s.tracked); got != 1 {
+		t.Fatalf("tracked workspaces len = %d, want 1", got)
+	}
+}
+
+func TestCompactWindowSlots_KeepsCustomSessionName(t *testing.T) {
+	var renames []string
+	s := &Server{
+		config:        config.DefaultConfig(),
+		tracked:       make(map[string]map[int]trackedAgent),
+		nextSlot:      make(map[string]int),
+		readSnapshots: make(map[string]map[int]string),
+		renameSessionFn: func(oldName, newName string) error {
+			renames = append(renames, oldName+"->"+newName)
+			return nil
+		},
+	}
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	_ = s.trackSpecificSlot("ws", 0, "claude", "termtile-ws-0:0.0", "window", false)
+	_ = s.trackSpecificSlot("ws", 2, "codex", "my-agent:0.0", "window", false)
+	_ = s.trackSpecificSlot("ws", 3, "gemini", "termtile-ws-3:0.0", "window", false)
+
+	if err := s.compactWindowSlots("ws", 1); err != nil {
+		t.Fatalf("compactWindowSlots: %v", err)
+	}
+	if target, ok := s.getTmuxTarget("ws", 1); !ok || target != "my-agent:0.0" {
+		t.Fatalf("slot 1 target = %q (ok=%v), want my-agent:0.0", target, ok)
+	}
+	if target, ok := s.getTmuxTarget("ws", 2); !ok || target != "termtile-ws-2:0.0" {
+		t.Fatalf("slot 2 target = %q (ok=%v), want termtile-ws-2:0.0", target, ok)
+	}
+	if want := "termtile-ws-3->termtile-ws-2"; strings.Join(renames, ",") != want {
+		t.Fatalf("renames = %v, want %s", renames, want)
+	}
+}
+
+func TestHandleWaitForIdle_BothModeFallsBackToFence(t *testing.T) {
+	isolatedTmux(t)
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	cfg := config.DefaultConfig()
+	cfg.AgentMode.IdlePollMs = 20
+	cfg.Agents = map[string]config.AgentConfig{
+		"dual":  {Command: "dual", OutputMode: "both"},
+		"hooky": {Command: "hooky", OutputMode: "hooks"},
+	}
+	s := &Server{
+		config:   cfg,
+		tracked:  make(map[string]map[int]trackedAgent),
+		nextSlot: make(map[string]int),
+	}
+
+	s.tracked["ws"] = map[int]trackedAgent{}
+	for slot, agentType := range map[int]string{1: "dual", 2: "hooky"} {
+		session := agent.SessionName("ws", slot)
+		script := "printf '[termtile-response]\\nfenced answer\\n[/termtile-response]\\n'; sleep 30"
+		if out, err := exec.Command("tmux", "new-session", "-d", "-s", session, script).CombinedOutput(); err != nil {
+			t.Fatalf("new-session: %v (%s)", err, out)
+		}
+		s.tracked["ws"][slot] = trackedAgent{
+			agentType:     agentType,
+			tmuxTarget:    agent.TargetForSession(session),
+			responseFence: true,
+		}
+	}
+
+	// Without a hook artifact, "both" accepts the fenced response.
+	_, out, err := s.handleWaitForIdle(nil, nil, WaitForIdleInput{Slot: 1, Workspace: "ws", Timeout: 5})
+	if err != nil {
+		t.Fatalf("handleWaitForIdle: %v", err)
+	}
+	if !out.IsIdle || strings.TrimSpace(out.Output) != "fenced answer" {
+		t.Fatalf("both mode = %+v, want idle with the fenced answer", out)
+	}
+
+	// A hooks-only agent ignores the fence and times out.
+	_, out, err = s.handleWaitForIdle(nil, nil, WaitForIdleInput{Slot: 2, Workspace: "ws", Timeout: 1})
+	if err != nil {
+		t.Fatalf("handleWaitForIdle: %v", err)
+	}
+	if out.IsIdle {
+		t.Fatalf("hooks mode = %+v, want not idle without an artifact", out)
+	}
+
+	// The hook artifact is preferred over the fence when both are ready.
+	dir, err := EnsureArtifactDir("ws", 1)
+	if err != nil {
+		t.Fatalf("EnsureArtifactDir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "output.json"), []byte(`{"status":"complete","output":"hook answer"}`), 0o644); err != nil {
+		t.Fatalf("write artifact: %v", err)
+	}
+	_, out, err = s.handleWaitForIdle(nil, nil, WaitForIdleInput{Slot: 1, Workspace: "ws", Timeout: 5})
+	if err != nil {
+		t.Fatalf("handleWaitForIdle: %v", err)
+	}
+	if !out.IsIdle || out.Output != "hook answer" {
+		t.Fatalf("both mode with artifact = %+v, want the hook answer", out)
+	}
 }
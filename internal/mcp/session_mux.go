@@ -0,0 +1,155 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/1broseidon/termtile/internal/agent"
+)
+
+// targetSession returns the session part of a "<session>:0.0" slot target.
+func targetSession(target string) string {
+	session, _, _ := strings.Cut(target, ":")
+	return session
+}
+
+// slotSendKeys sends text followed by Enter to a slot target.
+func (s *Server) slotSendKeys(target, text string) error {
+	if s.sessionMux != nil {
+		return s.sessionMux.SendKeys(targetSession(target), text)
+	}
+	return tmuxSendKeys(target, text)
+}
+
+// slotClearInputLine best-effort clears partially typed input in a slot; see
+// tmuxClearInputLine. Escape and Ctrl-U are pasted where the multiplexer
+// can paste without sending Enter.
+func (s *Server) slotClearInputLine(target string) error {
+	if s.sessionMux != nil {
+		paster, ok := s.sessionMux.(agent.Paster)
+		if !ok {
+			return nil
+		}
+		return paster.PasteText(targetSession(target), "\x1b\x15", false)
+	}
+	return tmuxClearInputLine(target)
+}
+
+// slotCapture captures output from a slot target; see tmuxCaptureArgs.
+// Escape sequences are only kept under tmux.
+func (s *Server) slotCapture(target string, lines int, escapes bool) (string, error) {
+	if s.sessionMux != nil {
+		return s.sessionMux.CapturePane(targetSession(target), lines)
+	}
+	return tmuxCapture(target, lines, escapes)
+}
+
+// slotCapturePane captures plain-text output from a slot target.
+func (s *Server) slotCapturePane(target string, lines int) (string, error) {
+	return s.slotCapture(target, lines, false)
+}
+
+// slotWaitFor polls a slot target's output until pattern is found or
+// timeout; see tmuxWaitFor.
+func (s *Server) slotWaitFor(target, pattern string, timeout time.Duration, lines int, escapes bool) (string, error) {
+	if s.sessionMux != nil {
+		return s.sessionMux.WaitFor(targetSession(target), pattern, timeout, lines)
+	}
+	return tmuxWaitFor(target, pattern, timeout, lines, escapes)
+}
+
+// slotTargetExists reports whether a slot target is still alive.
+func (s *Server) slotTargetExists(target string) bool {
+	if s.sessionMux != nil {
+		ok, err := s.sessionMux.HasSession(targetSession(target))
+		return err == nil && ok
+	}
+	return tmuxTargetExists(target)
+}
+
+// slotServerRunning reports whether the multiplexer's sessions are
+// reachable. Screen sessions each run their own server, so only tmux can be
+// down as a whole.
+func (s *Server) slotServerRunning() bool {
+	if s.sessionMux != nil {
+		return true
+	}
+	return tmuxServerRunning()
+}
+
+// slotSessionExists reports whether a session named name is running.
+func (s *Server) slotSessionExists(name string) bool {
+	if s.sessionMux != nil {
+		ok, err := s.sessionMux.HasSession(name)
+		return err == nil && ok
+	}
+	return exec.Command("tmux", "has-session", "-t", "="+name).Run() == nil
+}
+
+// slotCurrentPath returns the working directory of a slot target, or empty
+// string when the multiplexer cannot report it.
+func (s *Server) slotCurrentPath(target string) string {
+	if s.sessionMux != nil {
+		return ""
+	}
+	return paneCurrentPath(target)
+}
+
+// slotCurrentCommand returns the command running in a slot target, or empty
+// string when the multiplexer cannot report it.
+func (s *Server) slotCurrentCommand(target string) string {
+	if s.sessionMux != nil {
+		reporter, ok := s.sessionMux.(agent.StatusReporter)
+		if !ok {
+			return ""
+		}
+		status, err := reporter.GetSessionStatus(targetSession(target))
+		if err != nil {
+			return ""
+		}
+		return status.CurrentCommand
+	}
+	out, err := exec.Command("tmux", "display-message", "-t", target, "-p", "#{pane_current_command}").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// listSlotSessions returns the names of all live sessions. A tmux server
+// that is not running has no sessions.
+func (s *Server) listSlotSessions() ([]string, error) {
+	if s.sessionMux != nil {
+		return s.sessionMux.ListSessions()
+	}
+	out, err := exec.Command("tmux", "list-sessions", "-F", "#{session_name}").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && tmuxNoServer(string(exitErr.Stderr)) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return strings.Split(strings.TrimSpace(string(out)), "\n"), nil
+}
+
+// tmuxNoServer reports whether tmux stderr says no server is running,
+// either because it never started or its socket is gone.
+func tmuxNoServer(stderr string) bool {
+	return strings.Contains(stderr, "no server running") || strings.Contains(stderr, "error connecting to")
+}
+
+// killSlotSession kills the session behind a window or detached slot
+// target.
+func (s *Server) killSlotSession(target string) error {
+	if s.sessionMux != nil {
+		return s.sessionMux.KillSession(targetSession(target))
+	}
+	if out, err := exec.Command("tmux", "kill-session", "-t", targetSession(target)).CombinedOutput(); err != nil {
+		return fmt.Errorf("tmux kill-session failed: %w (%s)", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/1broseidon/termtile/internal/agent"
+	"github.com/1broseidon/termtile/internal/config"
+	workspacepkg "github.com/1broseidon/termtile/internal/workspace"
+)
+
+// fakeSessionMux records the session-level calls the slot helpers make.
+type fakeSessionMux struct {
+	agent.Multiplexer
+	sessions map[string]string // session -> captured output
+	sent     []string
+}
+
+func (f *fakeSessionMux) Name() string { return "screen" }
+
+func (f *fakeSessionMux) HasSession(session string) (bool, error) {
+	_, ok := f.sessions[session]
+	return ok, nil
+}
+
+func (f *fakeSessionMux) SendKeys(session, text string) error {
+	f.sent = append(f.sent, session+": "+text)
+	return nil
+}
+
+func (f *fakeSessionMux) CapturePane(session string, lines int) (string, error) {
+	return f.sessions[session], nil
+}
+
+func (f *fakeSessionMux) WaitFor(session, pattern string, timeout time.Duration, lines int) (string, error) {
+	return f.sessions[session], nil
+}
+
+func TestSlotHelpers_RouteThroughSessionMux(t *testing.T) {
+	mux := &fakeSessionMux{sessions: map[string]string{"termtile-ws-0": "ready\n"}}
+	s := &Server{sessionMux: mux}
+
+	if !s.slotTargetExists("termtile-ws-0:0.0") || s.slotTargetExists("termtile-ws-1:0.0") {
+		t.Fatal("slotTargetExists did not check the target's session")
+	}
+	if !s.slotSessionExists("termtile-ws-0") {
+		t.Fatal("slotSessionExists(termtile-ws-0) = false")
+	}
+	if err := s.slotSendKeys("termtile-ws-0:0.0", "hello"); err != nil {
+		t.Fatalf("slotSendKeys: %v", err)
+	}
+	if len(mux.sent) != 1 || mux.sent[0] != "termtile-ws-0: hello" {
+		t.Fatalf("sent = %v, want hello to termtile-ws-0", mux.sent)
+	}
+	if out, err := s.slotCapture("termtile-ws-0:0.0", 50, true); err != nil || out != "ready\n" {
+		t.Fatalf("slotCapture = %q, %v", out, err)
+	}
+	if out, err := s.slotWaitFor("termtile-ws-0:0.0", "ready", time.Second, 50, false); err != nil || out != "ready\n" {
+		t.Fatalf("slotWaitFor = %q, %v", out, err)
+	}
+	if !s.slotServerRunning() {
+		t.Fatal("slotServerRunning = false under a session multiplexer")
+	}
+}
+
+func TestHandleSpawnAgent_SessionMuxSpawnsWindowForPane(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	if err := workspacepkg.SetActiveWorkspace("ws-screen", 1, true, 0, []int{0}); err != nil {
+		t.Fatalf("SetActiveWorkspace: %v", err)
+	}
+	cfg := config.DefaultConfig()
+	cfg.Agents["plain"] = config.AgentConfig{Command: "plain-agent", SpawnMode: "pane"}
+	s := &Server{config: cfg, sessionMux: &fakeSessionMux{}, tracked: make(map[string]map[int]trackedAgent), nextSlot: make(map[string]int)}
+
+	_, plan, err := s.handleSpawnAgent(nil, nil, SpawnAgentInput{AgentType: "plain", Workspace: "ws-screen", DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if plan.SpawnMode != "window" {
+		t.Fatalf("spawn_mode = %q, want window under screen", plan.SpawnMode)
+	}
+}
+
+func TestHandleSendToAgent_RoutesThroughSessionMux(t *testing.T) {
+	mux := &fakeSessionMux{sessions: map[string]string{"termtile-ws-0": "$ \n"}}
+	s := &Server{
+		config:     config.DefaultConfig(),
+		sessionMux: mux,
+		tracked:    make(map[string]map[int]trackedAgent),
+		nextSlot:   make(map[string]int),
+	}
+	s.allocateSlot("ws", "plain", "termtile-ws-0:0.0", "window", false)
+
+	if _, _, err := s.handleSendToAgent(nil, nil, SendToAgentInput{Slot: 0, Text: "ls -la", Workspace: "ws"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if len(mux.sent) != 1 || mux.sent[0] != "termtile-ws-0: ls -la" {
+		t.Fatalf("sent = %v, want ls -la to termtile-ws-0 through the session multiplexer", mux.sent)
+	}
+}
@@ -1,27 +1,143 @@
 package mcp
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"os/exec"
 	"strings"
 
 	"github.com/1broseidon/termtile/internal/config"
 )
 
+// Reason codes spawn_agent reports in SpawnAgentOutput.Reason when a spawn
+// fails, so callers can branch without parsing the message.
+const (
+	SpawnReasonNoAgentType          = "no_agent_type"
+	SpawnReasonUnknownAgent         = "unknown_agent"
+	SpawnReasonWorkspaceNotFound    = "workspace_not_found"
+	SpawnReasonDependsOnFailed      = "depends_on_failed"
+	SpawnReasonQueueCancelled       = "queue_cancelled"
+	SpawnReasonNoTmuxSession        = "no_tmux_session"
+	SpawnReasonNoTerminal           = "no_terminal"
+	SpawnReasonTemplateMissing      = "template_missing"
+	SpawnReasonTemplateInvalid      = "template_invalid"
+	SpawnReasonTerminalNotInstalled = "terminal_not_installed"
+	SpawnReasonNoDisplay            = "no_display"
+	SpawnReasonRegistryFailed       = "registry_failed"
+	SpawnReasonLaunchFailed         = "launch_failed"
+	SpawnReasonTmuxFailed           = "tmux_failed"
+	SpawnReasonTmuxTimeout          = "tmux_timeout"
+	SpawnReasonSessionNameInvalid   = "session_name_invalid"
+	SpawnReasonSessionNameInUse     = "session_name_in_use"
+	// SpawnReasonFailed covers errors without a more specific code.
+	SpawnReasonFailed = "spawn_failed"
+)
+
+// SpawnError is a spawn_agent failure tagged with one of the SpawnReason
+// codes. Its message is the wrapped error's.
+type SpawnError struct {
+	Reason string
+	Err    error
+}
+
+func (e *SpawnError) Error() string { return e.Err.Error() }
+
+func (e *SpawnError) Unwrap() error { return e.Err }
+
+// spawnError tags err with reason; a nil err stays nil and an err that
+// already carries a reason keeps it.
+func spawnError(reason string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var se *SpawnError
+	if errors.As(err, &se) {
+		return err
+	}
+	return &SpawnError{Reason: reason, Err: err}
+}
+
+// SpawnReason returns the reason code carried by err, or SpawnReasonFailed
+// when it has none.
+func SpawnReason(err error) string {
+	var se *SpawnError
+	if errors.As(err, &se) {
+		return se.Reason
+	}
+	return SpawnReasonFailed
+}
+
+// acquireSpawnSlot blocks until workspaceName has room for another spawn
+// under agent_mode.max_concurrent_spawns, or ctx is done. The caller must
+// call release once its agent has started. The limit is read on every
+// attempt, so a changed max_concurrent_spawns applies to the next spawn and
+// to those already waiting; 0 means no limit.
+func (s *Server) acquireSpawnSlot(ctx context.Context, workspaceName string) (release func(), err error) {
+	var done <-chan struct{}
+	if ctx != nil {
+		done = ctx.Done()
+	}
+	for {
+		limit := s.agentModeConfig().GetMaxConcurrentSpawns()
+
+		s.mu.Lock()
+		if limit <= 0 || s.spawnsActive[workspaceName] < limit {
+			if s.spawnsActive == nil {
+				s.spawnsActive = make(map[string]int)
+			}
+			s.spawnsActive[workspaceName]++
+			s.mu.Unlock()
+			return func() { s.releaseSpawnSlot(workspaceName) }, nil
+		}
+		if s.spawnFreed == nil {
+			s.spawnFreed = make(chan struct{})
+		}
+		freed := s.spawnFreed
+		s.mu.Unlock()
+
+		select {
+		case <-freed:
+		case <-done:
+			return nil, spawnError(SpawnReasonQueueCancelled, fmt.Errorf("waiting for a spawn slot in workspace %q: %w", workspaceName, ctx.Err()))
+		}
+	}
+}
+
+// releaseSpawnSlot gives back a slot taken by acquireSpawnSlot and wakes
+// the spawns waiting for one.
+func (s *Server) releaseSpawnSlot(workspaceName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.spawnsActive[workspaceName]--; s.spawnsActive[workspaceName] <= 0 {
+		delete(s.spawnsActive, workspaceName)
+	}
+	if s.spawnFreed != nil {
+		close(s.spawnFreed)
+		s.spawnFreed = nil
+	}
+}
+
 // spawnAgentWithDependencies waits for depends_on slots (if provided) then
 // spawns the agent exactly as current behavior. The optional preCommandFn is
 // called after the window/session is created but before the agent command is
-// sent (used for project_file hook injection).
-func (s *Server) spawnAgentWithDependencies(workspaceName, agentType, cwd, agentCmd, spawnMode string, responseFence bool, agentCfg config.AgentConfig, dependsOn []int, dependsOnTimeout int, preCommandFn func(string, int) error) (string, int, error) {
+// sent (used for project_file hook injection). A non-empty sessionName
+// replaces the derived tmux session name in window and detached modes.
+func (s *Server) spawnAgentWithDependencies(workspaceName, agentType, cwd, agentCmd, spawnMode, sessionName string, responseFence bool, agentCfg config.AgentConfig, dependsOn []int, dependsOnTimeout int, preCommandFn func(string, int) error) (string, int, error) {
 	if len(dependsOn) > 0 {
 		if err := s.waitForDependencies(workspaceName, dependsOn, dependsOnTimeout); err != nil {
-			return "", 0, err
+			return "", 0, spawnError(SpawnReasonDependsOnFailed, err)
 		}
 	}
 
-	if spawnMode == "window" {
-		// Window mode: spawn a shell session, then send the agent command.
-		target, slot, err := s.spawnWindow(workspaceName, agentType, cwd, responseFence, agentCfg)
+	if spawnMode == "window" || spawnMode == "detached" {
+		// Window/detached mode: spawn a shell session, then send the agent command.
+		spawnSession := s.spawnWindow
+		if spawnMode == "detached" {
+			spawnSession = s.spawnDetached
+		}
+		target, slot, err := spawnSession(workspaceName, agentType, cwd, sessionName, responseFence, agentCfg)
 		if err != nil {
 			return "", 0, err
 		}
@@ -36,7 +152,7 @@ func (s *Server) spawnAgentWithDependencies(workspaceName, agentType, cwd, agent
 				log.Printf("Warning: preCommandFn failed for workspace %q slot %d: %v", workspaceName, slot, err)
 			}
 		}
-		s.waitForShellAndSend(target, agentCmd)
+		s.waitForShellAndSend(target, agentCmd, agentCfg.GetClearInputBeforeSend())
 		return target, slot, nil
 	}
 
@@ -52,6 +168,26 @@ func (s *Server) spawnAgentWithDependencies(workspaceName, agentType, cwd, agent
 	return target, slot, nil
 }
 
+// lookPath resolves executables; tests replace it.
+var lookPath = exec.LookPath
+
+// checkSpawnTemplateBinary fails fast when the terminal named by the first
+// token of a spawn template is not installed, instead of waiting for a window
+// that can never appear.
+func checkSpawnTemplateBinary(template, termClass string) error {
+	argv, err := splitCommand(template)
+	if err != nil {
+		return spawnError(SpawnReasonTemplateInvalid, fmt.Errorf("invalid spawn template for class %q: %w", termClass, err))
+	}
+	if len(argv) == 0 {
+		return spawnError(SpawnReasonTemplateInvalid, fmt.Errorf("spawn template for class %q is empty", termClass))
+	}
+	if _, err := lookPath(argv[0]); err != nil {
+		return spawnError(SpawnReasonTerminalNotInstalled, fmt.Errorf("terminal %q not installed (spawn template for class %q)", argv[0], termClass))
+	}
+	return nil
+}
+
 // renderSpawnTemplate fills {{dir}} and {{cmd}} placeholders in a terminal
 // spawn template and returns an exec-ready argv.
 // Duplicated from internal/workspace/load.go (unexported there).
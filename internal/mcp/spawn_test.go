@@ -1,6 +1,17 @@
 package mcp
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1broseidon/termtile/internal/config"
+	workspacepkg "github.com/1broseidon/termtile/internal/workspace"
+)
 
 func TestRenderSpawnTemplate(t *testing.T) {
 	tests := []struct {
@@ -148,3 +159,813 @@ func TestLookupSpawnTemplate(t *testing.T) {
 		t.Error("expected not found for nil map")
 	}
 }
+
+func TestCheckSpawnTemplateBinary(t *testing.T) {
+	orig := lookPath
+	t.Cleanup(func() { lookPath = orig })
+	lookPath = func(file string) (string, error) {
+		if file == "ghostty" {
+			return "/usr/bin/ghostty", nil
+		}
+		return "", exec.ErrNotFound
+	}
+
+	if err := checkSpawnTemplateBinary("ghostty --working-directory={{dir}} -e {{cmd}}", "com.mitchellh.ghostty"); err != nil {
+		t.Fatalf("installed terminal: %v", err)
+	}
+
+	err := checkSpawnTemplateBinary("'wezterm' start --cwd {{dir}} -- {{cmd}}", "org.wezfurlong.wezterm")
+	want := `terminal "wezterm" not installed (spawn template for class "org.wezfurlong.wezterm")`
+	if err == nil || err.Error() != want {
+		t.Fatalf("missing terminal error = %v, want %q", err, want)
+	}
+
+	if err := checkSpawnTemplateBinary("   ", "kitty"); err == nil {
+		t.Fatal("empty template error = nil")
+	}
+}
+
+func TestSpawnWindow_MissingTerminalFailsBeforeRegistry(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	t.Setenv("DISPLAY", "")
+
+	orig := lookPath
+	t.Cleanup(func() { lookPath = orig })
+	lookPath = func(string) (string, error) { return "", exec.ErrNotFound }
+
+	if err := workspacepkg.Write(&workspacepkg.WorkspaceConfig{
+		Name:      "dev",
+		Terminals: []workspacepkg.TerminalConfig{{WMClass: "kitty", SlotIndex: 0}},
+	}); err != nil {
+		t.Fatalf("workspace.Write: %v", err)
+	}
+	if err := workspacepkg.SetActiveWorkspace("dev", 1, true, 0, []int{0}); err != nil {
+		t.Fatalf("SetActiveWorkspace: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.TerminalSpawnCommands = map[string]string{"kitty": "kitty --directory {{dir}} {{cmd}}"}
+	s := &Server{
+		config:        cfg,
+		tracked:       make(map[string]map[int]trackedAgent),
+		nextSlot:      make(map[string]int),
+		readSnapshots: make(map[string]map[int]string),
+	}
+
+	start := time.Now()
+	_, _, err := s.spawnWindow("dev", "claude", "/tmp", "", false, config.AgentConfig{})
+	if err == nil || !strings.Contains(err.Error(), `terminal "kitty" not installed`) {
+		t.Fatalf("spawnWindow error = %v, want missing terminal", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("spawnWindow took %v, want immediate failure", elapsed)
+	}
+
+	info, err := workspacepkg.GetWorkspaceByName("dev")
+	if err != nil {
+		t.Fatalf("GetWorkspaceByName: %v", err)
+	}
+	if info.TerminalCount != 1 {
+		t.Fatalf("registry terminal count = %d, want unchanged 1", info.TerminalCount)
+	}
+	if len(s.getTracked("dev")) != 0 {
+		t.Fatalf("tracked slots = %v, want none", s.getTracked("dev"))
+	}
+}
+
+func TestSendPostSpawnInputs_OrderedBeforeTask(t *testing.T) {
+	type sent struct{ target, text string }
+	var got []sent
+	origPause := postSpawnKeyPause
+	postSpawnKeyPause = 0
+	t.Cleanup(func() { postSpawnKeyPause = origPause })
+	s := &Server{}
+	s.sendInputFn = func(target, text string, clearInput bool) error {
+		got = append(got, sent{target, text})
+		return nil
+	}
+
+	s.sendPostSpawnInputs("ws:1.0", []string{"/model opus", "  ", "/init"}, "fix the bug", true)
+	want := []sent{{"ws:1.0", "/model opus"}, {"ws:1.0", "/init"}, {"ws:1.0", "fix the bug"}}
+	if len(got) != len(want) {
+		t.Fatalf("sent %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("sent %+v, want %+v", got, want)
+		}
+	}
+
+	// Without a task only the keys go out.
+	got = nil
+	s.sendPostSpawnInputs("ws:1.0", []string{"/init"}, "", true)
+	if len(got) != 1 || got[0].text != "/init" {
+		t.Fatalf("sent %+v, want only /init", got)
+	}
+}
+
+func TestSendPostSpawnInputs_SkipsClearWhenDisabled(t *testing.T) {
+	var clears []bool
+	origPause := postSpawnKeyPause
+	postSpawnKeyPause = 0
+	t.Cleanup(func() { postSpawnKeyPause = origPause })
+	s := &Server{}
+	s.sendInputFn = func(target, text string, clearInput bool) error {
+		clears = append(clears, clearInput)
+		return nil
+	}
+
+	off := false
+	agentCfg := config.AgentConfig{PostSpawnKeys: []string{"/init"}, ClearInputBeforeSend: &off}
+	s.sendPostSpawnInputs("ws:1.0", agentCfg.PostSpawnKeys, "fix the bug", agentCfg.GetClearInputBeforeSend())
+	if len(clears) != 2 || clears[0] || clears[1] {
+		t.Fatalf("clear flags = %v, want both inputs sent without clearing", clears)
+	}
+
+	clears = nil
+	s.sendPostSpawnInputs("ws:1.0", nil, "fix the bug", config.AgentConfig{}.GetClearInputBeforeSend())
+	if len(clears) != 1 || !clears[0] {
+		t.Fatalf("clear flags = %v, want the default to clear", clears)
+	}
+}
+
+func TestSendAgentInput_ClearInputBeforeSend(t *testing.T) {
+	// Point tmux at an empty socket dir so send-keys fails fast instead of
+	// reaching a user server.
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+	cleared := 0
+	s := &Server{}
+	s.clearInputFn = func(target string) error {
+		cleared++
+		return nil
+	}
+
+	_ = s.sendAgentInput("termtile-none:0.0", "hello", false)
+	if cleared != 0 {
+		t.Fatalf("cleared %d times with clearing disabled, want 0", cleared)
+	}
+	_ = s.sendAgentInput("termtile-none:0.0", "hello", true)
+	if cleared != 1 {
+		t.Fatalf("cleared %d times with clearing enabled, want 1", cleared)
+	}
+}
+
+func TestAcquireSpawnSlot_BlocksBeyondLimit(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.AgentMode.MaxConcurrentSpawns = 2
+	s := &Server{config: cfg}
+	ctx := context.Background()
+
+	var releases []func()
+	for i := 0; i < 2; i++ {
+		release, err := s.acquireSpawnSlot(ctx, "ws")
+		if err != nil {
+			t.Fatalf("acquire %d: %v", i, err)
+		}
+		releases = append(releases, release)
+	}
+
+	acquired := make(chan func(), 1)
+	go func() {
+		release, err := s.acquireSpawnSlot(ctx, "ws")
+		if err != nil {
+			t.Errorf("queued acquire: %v", err)
+			return
+		}
+		acquired <- release
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("third spawn started while two were in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Other workspaces have their own limit.
+	other, err := s.acquireSpawnSlot(ctx, "other")
+	if err != nil {
+		t.Fatalf("acquire in other workspace: %v", err)
+	}
+	other()
+
+	releases[0]()
+	select {
+	case release := <-acquired:
+		release()
+	case <-time.After(time.Second):
+		t.Fatal("queued spawn did not start after a slot was released")
+	}
+	releases[1]()
+}
+
+func TestAcquireSpawnSlot_CancelledWhileQueued(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.AgentMode.MaxConcurrentSpawns = 1
+	s := &Server{config: cfg}
+
+	release, err := s.acquireSpawnSlot(context.Background(), "ws")
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := s.acquireSpawnSlot(ctx, "ws"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+}
+
+func TestAcquireSpawnSlot_ReadsLimitPerSpawn(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.AgentMode.MaxConcurrentSpawns = 1
+	s := &Server{config: cfg}
+	ctx := context.Background()
+
+	first, err := s.acquireSpawnSlot(ctx, "ws")
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	// A reloaded, higher limit applies to the next spawn.
+	raised := config.DefaultConfig()
+	raised.AgentMode.MaxConcurrentSpawns = 2
+	s.config = raised
+	second, err := s.acquireSpawnSlot(ctx, "ws")
+	if err != nil {
+		t.Fatalf("acquire under raised limit: %v", err)
+	}
+
+	// Lowered again, a release below the new limit is needed first.
+	s.config = cfg
+	acquired := make(chan func(), 1)
+	go func() {
+		release, err := s.acquireSpawnSlot(ctx, "ws")
+		if err != nil {
+			t.Errorf("queued acquire: %v", err)
+			return
+		}
+		acquired <- release
+	}()
+	first()
+	select {
+	case <-acquired:
+		t.Fatal("spawn started with one in flight under a limit of 1")
+	case <-time.After(50 * time.Millisecond):
+	}
+	second()
+	select {
+	case release := <-acquired:
+		release()
+	case <-time.After(time.Second):
+		t.Fatal("queued spawn did not start after both slots were released")
+	}
+}
+
+func TestAcquireSpawnSlot_UnlimitedByDefault(t *testing.T) {
+	s := &Server{config: config.DefaultConfig()}
+	for i := 0; i < 10; i++ {
+		if _, err := s.acquireSpawnSlot(nil, "ws"); err != nil {
+			t.Fatalf("acquire %d: %v", i, err)
+		}
+	}
+}
+
+func TestWaitForWindowSession_UsesConfiguredTimeout(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.AgentMode.WindowSpawnTimeoutS = 1
+	checks := 0
+	s := &Server{config: cfg, sessionExistsFn: func(session string) bool {
+		checks++
+		return false
+	}}
+
+	start := time.Now()
+	err := s.waitForWindowSession("termtile-ws-1")
+	elapsed := time.Since(start)
+	if got := SpawnReason(err); got != SpawnReasonTmuxTimeout {
+		t.Fatalf("reason = %q (err %v), want %q", got, err, SpawnReasonTmuxTimeout)
+	}
+	// The 15s default would still be polling; the 1s deadline must stop it.
+	if elapsed < time.Second || elapsed > 5*time.Second {
+		t.Fatalf("gave up after %s, want about 1s", elapsed)
+	}
+	if checks < 2 {
+		t.Fatalf("checks = %d, want polling until the deadline", checks)
+	}
+	if !strings.Contains(err.Error(), "after 1s") {
+		t.Fatalf("err = %v, want the configured timeout in the message", err)
+	}
+}
+
+func TestWaitForWindowSession_ReturnsOnceSessionAppears(t *testing.T) {
+	checks := 0
+	s := &Server{config: config.DefaultConfig(), sessionExistsFn: func(session string) bool {
+		checks++
+		return checks == 3
+	}}
+	if err := s.waitForWindowSession("termtile-ws-1"); err != nil {
+		t.Fatalf("waitForWindowSession: %v", err)
+	}
+	if checks != 3 {
+		t.Fatalf("checks = %d, want 3", checks)
+	}
+}
+
+func TestHandleSpawnAgent_DryRunMatchesSpawnCommand(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	// The real spawn's follow-up tmux calls must not reach a user server.
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+
+	if err := workspacepkg.SetActiveWorkspace("ws-dry", 1, true, 0, []int{0}); err != nil {
+		t.Fatalf("SetActiveWorkspace: %v", err)
+	}
+	if err := workspacepkg.Write(&workspacepkg.WorkspaceConfig{
+		Name:   "ws-dry",
+		Layout: "grid",
+		Env:    map[string]string{"REGION": "eu"},
+	}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	claude := cfg.Agents["claude"]
+	claude.Command = "fake-claude"
+	claude.OutputMode = "both"
+	claude.ResponseFence = true
+	claude.Env = map[string]string{"API_KEY": "k"}
+	cfg.Agents["claude"] = claude
+
+	var spawned []string
+	s := &Server{
+		config:         cfg,
+		tracked:        make(map[string]map[int]trackedAgent),
+		nextSlot:       make(map[string]int),
+		targetExistsFn: func(string) bool { return true },
+	}
+	s.spawnAgentFn = func(workspaceName, agentType, cwd, agentCmd, spawnMode, sessionName string, responseFence bool, agentCfg config.AgentConfig, _ []int, _ int, _ func(string, int) error) (string, int, error) {
+		spawned = append(spawned, agentCmd)
+		spawned = append(spawned, sortedEnv(agentCfg.Env)...)
+		return "%999", s.allocateSlot(workspaceName, agentType, "%999", spawnMode, responseFence), nil
+	}
+	input := SpawnAgentInput{AgentType: "claude", Workspace: "ws-dry", Task: "fix the build"}
+
+	dry := input
+	dry.DryRun = true
+	_, plan, err := s.handleSpawnAgent(nil, nil, dry)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if len(spawned) != 0 || len(s.getTracked("ws-dry")) != 0 {
+		t.Fatalf("dry run spawned %v / tracked %v", spawned, s.getTracked("ws-dry"))
+	}
+	if !plan.DryRun || plan.TaskDelivery != "prompt_as_arg" || plan.Slot != 0 {
+		t.Fatalf("plan = %+v", plan)
+	}
+	if !strings.HasPrefix(plan.Command, "fake-claude ") ||
+		!strings.Contains(plan.Command, "--settings ") ||
+		!strings.Contains(plan.Command, "[termtile-response]") {
+		t.Fatalf("command missing model/hook/fence parts: %s", plan.Command)
+	}
+	wantEnv := []string{"API_KEY=k", "REGION=eu"}
+	if strings.Join(plan.Env, " ") != strings.Join(wantEnv, " ") {
+		t.Fatalf("env = %v, want %v", plan.Env, wantEnv)
+	}
+
+	// A real spawn runs exactly the command and env the dry run reported.
+	if _, out, err := s.handleSpawnAgent(nil, nil, input); err != nil {
+		t.Fatalf("spawn: %v", err)
+	} else if out.Slot != plan.Slot {
+		t.Fatalf("spawned slot %d, dry run predicted %d", out.Slot, plan.Slot)
+	}
+	want := append([]string{plan.Command}, plan.Env...)
+	if strings.Join(spawned, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("spawned:\n%v\nwant:\n%v", spawned, want)
+	}
+}
+
+func TestHandleSpawnAgent_DryRunIncludesTerminalSpawnEnv(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	if err := workspacepkg.SetActiveWorkspace("ws-dry", 1, true, 0, []int{0}); err != nil {
+		t.Fatalf("SetActiveWorkspace: %v", err)
+	}
+	if err := workspacepkg.Write(&workspacepkg.WorkspaceConfig{
+		Name:      "ws-dry",
+		Layout:    "grid",
+		Terminals: []workspacepkg.TerminalConfig{{WMClass: "kitty"}},
+	}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	cfg := config.DefaultConfig()
+	cfg.TerminalSpawnEnv = map[string]map[string]string{"kitty": {"KITTY_THEME": "dark", "API_KEY": "term"}}
+	cfg.Agents["plain"] = config.AgentConfig{Command: "plain-agent", SpawnMode: "window", Env: map[string]string{"API_KEY": "k"}}
+	s := &Server{config: cfg, tracked: make(map[string]map[int]trackedAgent), nextSlot: make(map[string]int)}
+
+	_, plan, err := s.handleSpawnAgent(nil, nil, SpawnAgentInput{AgentType: "plain", Workspace: "ws-dry", DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	want := []string{"API_KEY=k", "KITTY_THEME=dark"}
+	if strings.Join(plan.Env, " ") != strings.Join(want, " ") {
+		t.Fatalf("env = %v, want %v", plan.Env, want)
+	}
+}
+
+func TestHandleSpawnAgent_DryRunSendKeysTask(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	if err := workspacepkg.SetActiveWorkspace("ws-dry", 1, true, 0, []int{0}); err != nil {
+		t.Fatalf("SetActiveWorkspace: %v", err)
+	}
+	cfg := config.DefaultConfig()
+	cfg.Agents["plain"] = config.AgentConfig{Command: "plain-agent", OutputMode: "tags", ResponseFence: true}
+	s := &Server{config: cfg, tracked: make(map[string]map[int]trackedAgent), nextSlot: make(map[string]int)}
+
+	_, plan, err := s.handleSpawnAgent(nil, nil, SpawnAgentInput{AgentType: "plain", Workspace: "ws-dry", Task: "hello", DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if plan.Command != "plain-agent" || plan.TaskDelivery != "send_keys" {
+		t.Fatalf("plan = %+v", plan)
+	}
+	if plan.SendKeysTask != wrapTaskWithFence("hello") {
+		t.Fatalf("send_keys_task = %q, want fenced task", plan.SendKeysTask)
+	}
+}
+
+func TestHandleSpawnAgent_NoFenceSendsRawTask(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	if err := workspacepkg.SetActiveWorkspace("ws-dry", 1, true, 0, []int{0}); err != nil {
+		t.Fatalf("SetActiveWorkspace: %v", err)
+	}
+	cfg := config.DefaultConfig()
+	cfg.Agents["plain"] = config.AgentConfig{Command: "plain-agent", OutputMode: "tags", ResponseFence: true}
+	s := &Server{config: cfg, tracked: make(map[string]map[int]trackedAgent), nextSlot: make(map[string]int)}
+
+	_, plan, err := s.handleSpawnAgent(nil, nil, SpawnAgentInput{AgentType: "plain", Workspace: "ws-dry", Task: "hello", DryRun: true, NoFence: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if plan.SendKeysTask != "hello" {
+		t.Fatalf("send_keys_task = %q, want raw task", plan.SendKeysTask)
+	}
+}
+
+func TestSpawnReason(t *testing.T) {
+	tagged := spawnError(SpawnReasonTmuxTimeout, errors.New("timeout waiting for tmux session"))
+	if got := SpawnReason(fmt.Errorf("spawn: %w", tagged)); got != SpawnReasonTmuxTimeout {
+		t.Fatalf("wrapped reason = %q, want %q", got, SpawnReasonTmuxTimeout)
+	}
+	// Re-tagging keeps the innermost, most specific reason.
+	if got := SpawnReason(spawnError(SpawnReasonDependsOnFailed, tagged)); got != SpawnReasonTmuxTimeout {
+		t.Fatalf("re-tagged reason = %q, want %q", got, SpawnReasonTmuxTimeout)
+	}
+	if got := SpawnReason(errors.New("boom")); got != SpawnReasonFailed {
+		t.Fatalf("untagged reason = %q, want %q", got, SpawnReasonFailed)
+	}
+	if spawnError(SpawnReasonFailed, nil) != nil {
+		t.Fatal("spawnError(nil) != nil")
+	}
+}
+
+func TestHandleSpawnAgent_FailureReasons(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	if err := workspacepkg.SetActiveWorkspace("ws-fail", 1, true, 0, []int{0}); err != nil {
+		t.Fatalf("SetActiveWorkspace: %v", err)
+	}
+	s := &Server{
+		config:   config.DefaultConfig(),
+		tracked:  make(map[string]map[int]trackedAgent),
+		nextSlot: make(map[string]int),
+	}
+
+	tests := []struct {
+		name  string
+		input SpawnAgentInput
+		want  string
+	}{
+		{"no agent type", SpawnAgentInput{Workspace: "ws-fail"}, SpawnReasonNoAgentType},
+		{"unknown agent", SpawnAgentInput{AgentType: "ghost", Workspace: "ws-fail"}, SpawnReasonUnknownAgent},
+		{"unregistered workspace", SpawnAgentInput{AgentType: "claude", Workspace: "ws-missing"}, SpawnReasonWorkspaceNotFound},
+		{"bad depends_on", SpawnAgentInput{AgentType: "claude", Workspace: "ws-fail", DependsOn: []int{-1}}, SpawnReasonDependsOnFailed},
+	}
+	for _, tt := range tests {
+		_, _, err := s.handleSpawnAgent(context.Background(), nil, tt.input)
+		if err == nil {
+			t.Fatalf("%s: expected error", tt.name)
+		}
+		if got := SpawnReason(err); got != tt.want {
+			t.Fatalf("%s: reason = %q, want %q (err: %v)", tt.name, got, tt.want, err)
+		}
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.AgentMode.MaxConcurrentSpawns = 1
+	q := &Server{config: cfg}
+	release, err := q.acquireSpawnSlot(context.Background(), "ws")
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	defer release()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := q.acquireSpawnSlot(ctx, "ws"); SpawnReason(err) != SpawnReasonQueueCancelled {
+		t.Fatalf("queued spawn reason = %q, want %q (err: %v)", SpawnReason(err), SpawnReasonQueueCancelled, err)
+	}
+}
+
+func TestSpawnWindow_FailureReasons(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	t.Setenv("DISPLAY", "")
+	t.Setenv("TERMINAL", "")
+
+	origLook, origSession, origSocket := lookPath, detectSessionX11EnvFn, detectDisplayFromSocketFn
+	t.Cleanup(func() {
+		lookPath, detectSessionX11EnvFn, detectDisplayFromSocketFn = origLook, origSession, origSocket
+	})
+	detectSessionX11EnvFn = func() (string, string) { return "", "" }
+	detectDisplayFromSocketFn = func(string) string { return "" }
+
+	if err := workspacepkg.SetActiveWorkspace("dev", 1, true, 0, []int{0}); err != nil {
+		t.Fatalf("SetActiveWorkspace: %v", err)
+	}
+	newServer := func(cfg *config.Config) *Server {
+		return &Server{
+			config:        cfg,
+			tracked:       make(map[string]map[int]trackedAgent),
+			nextSlot:      make(map[string]int),
+			readSnapshots: make(map[string]map[int]string),
+		}
+	}
+	spawn := func(cfg *config.Config) error {
+		_, _, err := newServer(cfg).spawnWindow("dev", "claude", "/tmp", "", false, config.AgentConfig{})
+		return err
+	}
+
+	// No saved terminal and nothing the config can resolve.
+	cfg := config.DefaultConfig()
+	cfg.PreferredTerminal = ""
+	cfg.TerminalClasses = nil
+	cfg.TerminalSpawnCommands = nil
+	if err := spawn(cfg); SpawnReason(err) != SpawnReasonNoTerminal {
+		t.Fatalf("reason = %q, want %q (err: %v)", SpawnReason(err), SpawnReasonNoTerminal, err)
+	}
+
+	// The saved terminal class has no spawn template.
+	if err := workspacepkg.Write(&workspacepkg.WorkspaceConfig{
+		Name:      "dev",
+		Terminals: []workspacepkg.TerminalConfig{{WMClass: "kitty", SlotIndex: 0}},
+	}); err != nil {
+		t.Fatalf("workspace.Write: %v", err)
+	}
+	cfg = config.DefaultConfig()
+	cfg.TerminalSpawnCommands = map[string]string{}
+	if err := spawn(cfg); SpawnReason(err) != SpawnReasonTemplateMissing {
+		t.Fatalf("reason = %q, want %q (err: %v)", SpawnReason(err), SpawnReasonTemplateMissing, err)
+	}
+
+	cfg.TerminalSpawnCommands = map[string]string{"kitty": "kitty --directory {{dir}} {{cmd}}"}
+	lookPath = func(string) (string, error) { return "", exec.ErrNotFound }
+	if err := spawn(cfg); SpawnReason(err) != SpawnReasonTerminalNotInstalled {
+		t.Fatalf("reason = %q, want %q (err: %v)", SpawnReason(err), SpawnReasonTerminalNotInstalled, err)
+	}
+
+	lookPath = func(file string) (string, error) { return "/usr/bin/" + file, nil }
+	cfg.Display = ""
+	if err := spawn(cfg); SpawnReason(err) != SpawnReasonNoDisplay {
+		t.Fatalf("reason = %q, want %q (err: %v)", SpawnReason(err), SpawnReasonNoDisplay, err)
+	}
+	info, err := workspacepkg.GetWorkspaceByName("dev")
+	if err != nil {
+		t.Fatalf("GetWorkspaceByName: %v", err)
+	}
+	if info.TerminalCount != 1 {
+		t.Fatalf("registry terminal count = %d, want rolled back to 1", info.TerminalCount)
+	}
+}
+
+func TestSpawnAgentTool_StructuredFailure(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	if err := workspacepkg.SetActiveWorkspace("ws-fail", 1, true, 0, []int{0}); err != nil {
+		t.Fatalf("SetActiveWorkspace: %v", err)
+	}
+	s := &Server{
+		config:   config.DefaultConfig(),
+		tracked:  make(map[string]map[int]trackedAgent),
+		nextSlot: make(map[string]int),
+	}
+	var spawnErr error
+	s.spawnAgentFn = func(string, string, string, string, string, string, bool, config.AgentConfig, []int, int, func(string, int) error) (string, int, error) {
+		return "", 0, spawnErr
+	}
+	input := SpawnAgentInput{AgentType: "claude", Workspace: "ws-fail", Window: boolPtr(false)}
+
+	spawnErr = spawnError(SpawnReasonTmuxTimeout, errors.New(`timeout waiting for tmux session "x" to appear`))
+	res, out, err := s.spawnAgentTool(context.Background(), nil, input)
+	if err != nil {
+		t.Fatalf("spawnAgentTool returned protocol error %v", err)
+	}
+	if res == nil || !res.IsError {
+		t.Fatalf("result = %+v, want an error result", res)
+	}
+	if out.Reason != SpawnReasonTmuxTimeout || !strings.Contains(out.Error, "timeout waiting") || out.AgentType != "claude" {
+		t.Fatalf("output = %+v", out)
+	}
+
+	spawnErr = errors.New("something odd")
+	if _, out, _ = s.spawnAgentTool(context.Background(), nil, input); out.Reason != SpawnReasonFailed {
+		t.Fatalf("untagged reason = %q, want %q", out.Reason, SpawnReasonFailed)
+	}
+}
+
+func TestHandleSpawnAgent_SessionNameOverride(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	if err := workspacepkg.SetActiveWorkspace("ws-name", 1, true, 0, []int{0}); err != nil {
+		t.Fatalf("SetActiveWorkspace: %v", err)
+	}
+	s := &Server{
+		config:          config.DefaultConfig(),
+		tracked:         make(map[string]map[int]trackedAgent),
+		nextSlot:        make(map[string]int),
+		sessionExistsFn: func(string) bool { return false },
+	}
+	var gotSession string
+	s.spawnAgentFn = func(workspaceName, agentType, cwd, agentCmd, spawnMode, sessionName string, responseFence bool, _ config.AgentConfig, _ []int, _ int, _ func(string, int) error) (string, int, error) {
+		gotSession = sessionName
+		target := sessionName + ":0.0"
+		return target, s.allocateSlot(workspaceName, agentType, target, spawnMode, responseFence), nil
+	}
+
+	input := SpawnAgentInput{AgentType: "claude", Workspace: "ws-name", Cwd: "/tmp", Window: boolPtr(true), SessionName: " my-agent "}
+	_, out, err := s.handleSpawnAgent(nil, nil, input)
+	if err != nil {
+		t.Fatalf("spawn: %v", err)
+	}
+	if gotSession != "my-agent" || out.SessionName != "my-agent:0.0" {
+		t.Fatalf("spawn used session %q, output %q; want my-agent", gotSession, out.SessionName)
+	}
+	if meta, err := readAgentMeta("ws-name", out.Slot); err != nil || meta.SessionName != "my-agent" {
+		t.Fatalf("meta = %+v (err=%v), want session_name my-agent", meta, err)
+	}
+	if got := SlotSessionName("ws-name", out.Slot); got != "my-agent" {
+		t.Fatalf("slotSessionName = %q, want my-agent", got)
+	}
+
+	// Without an override the spawn path derives the name.
+	gotSession = "unset"
+	input.SessionName = ""
+	if _, _, err := s.handleSpawnAgent(nil, nil, input); err != nil {
+		t.Fatalf("spawn without override: %v", err)
+	}
+	if gotSession != "" {
+		t.Fatalf("spawn got session %q, want empty (derived)", gotSession)
+	}
+}
+
+func TestHandleSpawnAgent_SkipsPostSpawnKeysForInlineTask(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	if err := workspacepkg.SetActiveWorkspace("ws-keys", 1, true, 0, []int{0}); err != nil {
+		t.Fatalf("SetActiveWorkspace: %v", err)
+	}
+	cfg := config.DefaultConfig()
+	cfg.Agents["inline"] = config.AgentConfig{
+		Command:       "inline-agent",
+		PromptAsArg:   true,
+		OutputMode:    "terminal",
+		PostSpawnKeys: []string{"/init"},
+	}
+	s := &Server{
+		config:          cfg,
+		tracked:         make(map[string]map[int]trackedAgent),
+		nextSlot:        make(map[string]int),
+		sessionExistsFn: func(string) bool { return false },
+	}
+	var gotCmd string
+	s.spawnAgentFn = func(workspaceName, agentType, cwd, agentCmd, spawnMode, sessionName string, responseFence bool, _ config.AgentConfig, _ []int, _ int, _ func(string, int) error) (string, int, error) {
+		gotCmd = agentCmd
+		return "ws-keys:0.0", s.allocateSlot(workspaceName, agentType, "ws-keys:0.0", spawnMode, responseFence), nil
+	}
+	var sent []string
+	s.sendInputFn = func(target, text string, clearInput bool) error {
+		sent = append(sent, text)
+		return nil
+	}
+
+	input := SpawnAgentInput{AgentType: "inline", Workspace: "ws-keys", Cwd: "/tmp", Window: boolPtr(true), Task: "fix the bug"}
+	if _, _, err := s.handleSpawnAgent(nil, nil, input); err != nil {
+		t.Fatalf("spawn: %v", err)
+	}
+	if !strings.Contains(gotCmd, "fix the bug") {
+		t.Fatalf("agent command = %q, want the task as an argument", gotCmd)
+	}
+	if len(sent) != 0 {
+		t.Fatalf("sent %q after an inline task, want post_spawn_keys skipped", sent)
+	}
+}
+
+func TestHandleSpawnAgent_SessionNameValidation(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	if err := workspacepkg.SetActiveWorkspace("ws-name", 1, true, 0, []int{0}); err != nil {
+		t.Fatalf("SetActiveWorkspace: %v", err)
+	}
+	if err := workspacepkg.SetSlotInfo(42, 0, "registered", 1); err != nil {
+		t.Fatalf("SetSlotInfo: %v", err)
+	}
+	s := &Server{
+		config:          config.DefaultConfig(),
+		tracked:         make(map[string]map[int]trackedAgent),
+		nextSlot:        make(map[string]int),
+		sessionExistsFn: func(name string) bool { return name == "live" },
+	}
+	_ = s.trackSpecificSlot("other", 1, "codex", "tracked:0.0", "detached", false)
+	spawns := 0
+	s.spawnAgentFn = func(string, string, string, string, string, string, bool, config.AgentConfig, []int, int, func(string, int) error) (string, int, error) {
+		spawns++
+		return "", 0, nil
+	}
+
+	tests := []struct {
+		name   string
+		window bool
+		reason string
+	}{
+		{name: "tracked", window: true, reason: SpawnReasonSessionNameInUse},
+		{name: "registered", window: true, reason: SpawnReasonSessionNameInUse},
+		{name: "live", window: true, reason: SpawnReasonSessionNameInUse},
+		{name: "termtile-ws-name-5", window: true, reason: SpawnReasonSessionNameInvalid},
+		{name: "bad.name", window: true, reason: SpawnReasonSessionNameInvalid},
+		{name: "fresh", window: false, reason: SpawnReasonSessionNameInvalid},
+	}
+	for _, tt := range tests {
+		input := SpawnAgentInput{AgentType: "claude", Workspace: "ws-name", Window: boolPtr(tt.window), SessionName: tt.name}
+		_, out, err := s.spawnAgentTool(context.Background(), nil, input)
+		if err != nil {
+			t.Fatalf("%s: protocol error %v", tt.name, err)
+		}
+		if out.Reason != tt.reason {
+			t.Errorf("%s: reason = %q (%s), want %q", tt.name, out.Reason, out.Error, tt.reason)
+		}
+	}
+	if spawns != 0 {
+		t.Fatalf("rejected names reached the spawn path %d times", spawns)
+	}
+}
+
+func TestTriggerRetile_SkipsWorkspaceWithAutoTileDisabled(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	off := false
+	if err := workspacepkg.Write(&workspacepkg.WorkspaceConfig{Name: "manual", AutoTile: &off}); err != nil {
+		t.Fatalf("workspace.Write: %v", err)
+	}
+	if err := workspacepkg.Write(&workspacepkg.WorkspaceConfig{Name: "tiled"}); err != nil {
+		t.Fatalf("workspace.Write: %v", err)
+	}
+
+	retiles := 0
+	s := &Server{
+		config:   config.DefaultConfig(),
+		retileFn: func(string) { retiles++ },
+	}
+
+	s.triggerRetile("tiled")
+	if retiles != 1 {
+		t.Fatalf("auto_tile default: retiles = %d, want 1", retiles)
+	}
+	s.triggerRetile("manual")
+	if retiles != 1 {
+		t.Fatalf("auto_tile false: retiles = %d, want no retile", retiles)
+	}
+	s.triggerRetile("tiled", "manual")
+	if retiles != 1 {
+		t.Fatalf("move into auto_tile false workspace: retiles = %d, want no retile", retiles)
+	}
+	s.triggerRetile("unsaved")
+	if retiles != 2 {
+		t.Fatalf("unsaved workspace: retiles = %d, want 2", retiles)
+	}
+}
@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -20,37 +21,54 @@ import (
 	workspacepkg "github.com/1broseidon/termtile/internal/workspace"
 )
 
-func (s *Server) logTextOptions() (includeContent bool, previewLen int) {
+func (s *Server) logTextOptions() (includeContent bool, previewLen, maxContent int) {
 	previewLen = 50
 	if s == nil || s.config == nil {
-		return false, previewLen
+		return false, previewLen, 0
 	}
 	logCfg := s.config.GetLoggingConfig()
 	if logCfg.PreviewLength > 0 {
 		previewLen = logCfg.PreviewLength
 	}
-	return logCfg.IncludeContent, previewLen
+	return logCfg.IncludeContent, previewLen, logCfg.MaxContentBytes
 }
 
 func (s *Server) addTextDetails(details map[string]interface{}, text string) {
-	includeContent, previewLen := s.logTextOptions()
+	includeContent, previewLen, maxContent := s.logTextOptions()
 	details["text_length"] = len(text)
 	details["text_preview"] = agent.Truncate(text, previewLen)
 	if includeContent {
-		details["text"] = text
+		details["text"] = agent.TruncateContent(text, maxContent)
 	}
 }
 
 func (s *Server) addOutputDetails(details map[string]interface{}, output string) {
-	includeContent, previewLen := s.logTextOptions()
+	includeContent, previewLen, maxContent := s.logTextOptions()
 	details["output_length"] = len(output)
 	details["output_preview"] = agent.Truncate(output, previewLen)
 	if includeContent {
-		details["output"] = output
+		details["output"] = agent.TruncateContent(output, maxContent)
 	}
 }
 
-func (s *Server) handleSpawnAgent(_ context.Context, _ *mcpsdk.CallToolRequest, args SpawnAgentInput) (*mcpsdk.CallToolResult, SpawnAgentOutput, error) {
+func (s *Server) handleSpawnAgent(ctx context.Context, _ *mcpsdk.CallToolRequest, args SpawnAgentInput) (*mcpsdk.CallToolResult, SpawnAgentOutput, error) {
+	if strings.TrimSpace(args.AgentType) == "" {
+		workspaceName, err := resolveWorkspaceForSpawn(args.Workspace, args.SourceWorkspace)
+		if err != nil {
+			err = spawnError(SpawnReasonWorkspaceNotFound, err)
+		} else {
+			args.AgentType, err = workspaceDefaultAgent(workspaceName)
+		}
+		if err != nil {
+			if s.logger != nil {
+				s.logger.Log(agent.ActionSpawnAgent, DefaultWorkspace, -1, map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
+			return nil, SpawnAgentOutput{}, err
+		}
+	}
+
 	agentCfg, ok := s.config.Agents[args.AgentType]
 	if !ok {
 		available := make([]string, 0, len(s.config.Agents))
@@ -69,10 +87,15 @@ func (s *Server) handleSpawnAgent(_ context.Context, _ *mcpsdk.CallToolRequest,
 				"error":           "unknown_agent_type",
 			})
 		}
-		return nil, SpawnAgentOutput{}, fmt.Errorf("unknown agent type %q; available: %v", args.AgentType, available)
+		return nil, SpawnAgentOutput{}, spawnError(SpawnReasonUnknownAgent, fmt.Errorf("unknown agent type %q; available: %v", args.AgentType, available))
 	}
 
 	spawnMode := resolveSpawnMode(args.Window, agentCfg.SpawnMode)
+	if spawnMode == "pane" && s.sessionMux != nil {
+		// Panes are split from an attached tmux session; other
+		// multiplexers open a window instead.
+		spawnMode = "window"
+	}
 	workspaceName, err := resolveWorkspaceForSpawn(args.Workspace, args.SourceWorkspace)
 	if err != nil {
 		if s.logger != nil {
@@ -82,12 +105,29 @@ func (s *Server) handleSpawnAgent(_ context.Context, _ *mcpsdk.CallToolRequest,
 				"error":      err.Error(),
 			})
 		}
-		return nil, SpawnAgentOutput{}, err
+		return nil, SpawnAgentOutput{}, spawnError(SpawnReasonWorkspaceNotFound, err)
+	}
+
+	sessionName := strings.TrimSpace(args.SessionName)
+	if sessionName != "" {
+		if err := s.checkSessionNameOverride(sessionName, spawnMode); err != nil {
+			if s.logger != nil {
+				s.logger.Log(agent.ActionSpawnAgent, workspaceName, -1, map[string]interface{}{
+					"agent_type": args.AgentType,
+					"spawn_mode": spawnMode,
+					"error":      err.Error(),
+					"reason":     SpawnReason(err),
+				})
+			}
+			return nil, SpawnAgentOutput{}, err
+		}
 	}
 
+	agentCfg.Env = workspaceSpawnEnv(workspaceName, agentCfg.Env)
+
 	// If depends_on is set, wait now so we can substitute slot artifacts into the
 	// task prompt BEFORE spawning (needed for prompt_as_arg agents).
-	if len(args.DependsOn) > 0 {
+	if len(args.DependsOn) > 0 && !args.DryRun {
 		if err := s.waitForDependencies(workspaceName, args.DependsOn, args.DependsOnTimeout); err != nil {
 			if s.logger != nil {
 				details := map[string]interface{}{
@@ -100,7 +140,7 @@ func (s *Server) handleSpawnAgent(_ context.Context, _ *mcpsdk.CallToolRequest,
 				}
 				s.logger.Log(agent.ActionSpawnAgent, workspaceName, -1, details)
 			}
-			return nil, SpawnAgentOutput{}, err
+			return nil, SpawnAgentOutput{}, spawnError(SpawnReasonDependsOnFailed, err)
 		}
 	}
 
@@ -113,7 +153,8 @@ func (s *Server) handleSpawnAgent(_ context.Context, _ *mcpsdk.CallToolRequest,
 	// Determine the task text to send to the agent.
 	// When response_fence is enabled (and hooks are NOT active), prepend
 	// structured output instructions. Hooks capture output via the
-	// transcript, so fence tags are unnecessary noise.
+	// transcript, so fence tags are unnecessary noise — except in "both"
+	// mode, where the fence is a fallback for flaky hooks.
 	taskTemplate := args.Task
 	if taskTemplate != "" && len(args.DependsOn) > 0 {
 		expanded, missing := substituteSlotOutputTemplates(taskTemplate, workspaceName, args.DependsOn)
@@ -122,7 +163,14 @@ func (s *Server) handleSpawnAgent(_ context.Context, _ *mcpsdk.CallToolRequest,
 			log.Printf("Warning: missing artifacts for workspace %q dependency slots %v", workspaceName, missing)
 		}
 	}
-	responseFence := agentCfg.ResponseFence && taskTemplate != "" && outputMode != "hooks"
+	responseFence := taskTemplate != "" && outputModeUsesFence(outputMode, agentCfg.ResponseFence)
+	// no_fence sends this task raw. The pipe-pane capture still starts so a
+	// later fenced send_to_agent gets a reliable close-tag baseline. Only
+	// tmux has pipe-pane; other multiplexers fall back to captures.
+	capturePipe := responseFence && s.sessionMux == nil
+	if args.NoFence {
+		responseFence = false
+	}
 	taskToSend := taskTemplate
 	if taskTemplate != "" && responseFence {
 		taskToSend = wrapTaskWithFence(taskTemplate)
@@ -149,10 +197,10 @@ func (s *Server) handleSpawnAgent(_ context.Context, _ *mcpsdk.CallToolRequest,
 		cmdParts = append(cmdParts, modelFlag, shellQuote(selectedModel))
 	}
 
-	// Inject native hook settings when output_mode is hooks.
+	// Inject native hook settings when output_mode is hooks or both.
 	needsFileWriteInstructions := false
 	var preSpawnProjectFileHook func(ws string, sl int) error
-	if outputMode == "hooks" {
+	if outputModeUsesHooks(outputMode) {
 		hooks := resolveHooks(agentCfg)
 		settings := renderHookSettings(agentCfg, hooks)
 		delivery := strings.ToLower(strings.TrimSpace(agentCfg.HookDelivery))
@@ -206,10 +254,7 @@ func (s *Server) handleSpawnAgent(_ context.Context, _ *mcpsdk.CallToolRequest,
 	// as a CLI argument instead of sending it via tmux send-keys later.
 	// When PromptFlag is set (e.g. "-i" for gemini), use it as the flag
 	// name instead of appending as a bare positional arg.
-	promptInCmd := agentCfg.PromptAsArg && taskTemplate != ""
-	if promptInCmd && len(taskToSend) > 32*1024 {
-		promptInCmd = false
-	}
+	promptInCmd, pipeInCmd := inlineTaskDelivery(agentCfg, taskTemplate != "", taskToSend, s.agentModeConfig().GetMaxInlineTaskBytes())
 	if promptInCmd {
 		if pf := strings.TrimSpace(agentCfg.PromptFlag); pf != "" {
 			cmdParts = append(cmdParts, pf, shellQuote(taskToSend))
@@ -222,22 +267,87 @@ func (s *Server) handleSpawnAgent(_ context.Context, _ *mcpsdk.CallToolRequest,
 	// stdin: printf '%s\n' 'TASK' | cmd args...
 	// This avoids interactive prompt issues (e.g. fence instructions
 	// leaking into aider/cecli's prompt).
-	pipeInCmd := agentCfg.PipeTask && taskTemplate != "" && !promptInCmd
-	if pipeInCmd && len(taskToSend) > 32*1024 {
-		pipeInCmd = false
-	}
-
 	agentCmd := strings.Join(cmdParts, " ")
 	if pipeInCmd {
 		agentCmd = fmt.Sprintf("printf '%%s\\n' %s | %s", shellQuote(taskToSend), agentCmd)
 	}
 
-	tmuxTarget, slot, err := s.spawnAgentWithDependencies(
+	if args.DryRun {
+		out := SpawnAgentOutput{
+			Slot:      s.peekNextSlot(workspaceName),
+			AgentType: args.AgentType,
+			Workspace: workspaceName,
+			SpawnMode: spawnMode,
+			DryRun:    true,
+			Command:   agentCmd,
+			Env:       sortedEnv(agentCfg.Env),
+		}
+		if spawnMode == "window" {
+			termClass, _ := s.windowTerminalClass(workspaceName)
+			out.Env = sortedEnv(windowSpawnEnv(s.config.SpawnEnv(termClass), agentCfg.Env))
+		}
+		if sessionName != "" {
+			out.SessionName = agent.TargetForSession(sessionName)
+		}
+		switch {
+		case taskTemplate == "":
+		case promptInCmd:
+			out.TaskDelivery = "prompt_as_arg"
+		case pipeInCmd:
+			out.TaskDelivery = "pipe_task"
+		default:
+			out.TaskDelivery = "send_keys"
+			out.SendKeysTask = taskToSend
+			if needsFileWriteInstructions {
+				out.SendKeysTask += fileWriteInstructions(workspaceName, out.Slot)
+			}
+		}
+		if s.logger != nil {
+			details := map[string]interface{}{
+				"agent_type": args.AgentType,
+				"spawn_mode": spawnMode,
+				"dry_run":    true,
+				"has_task":   taskTemplate != "",
+			}
+			s.addTextDetails(details, taskTemplate)
+			s.logger.Log(agent.ActionSpawnAgent, workspaceName, -1, details)
+		}
+		return nil, out, nil
+	}
+
+	// Queue behind other spawns in this workspace when
+	// agent_mode.max_concurrent_spawns is set. The slot is held until the
+	// agent has started and received its task.
+	release, err := s.acquireSpawnSlot(ctx, workspaceName)
+	if err != nil {
+		if s.logger != nil {
+			s.logger.Log(agent.ActionSpawnAgent, workspaceName, -1, map[string]interface{}{
+				"agent_type": args.AgentType,
+				"spawn_mode": spawnMode,
+				"error":      err.Error(),
+			})
+		}
+		return nil, SpawnAgentOutput{}, err
+	}
+	defer release()
+
+	// Free slots whose sessions vanished (e.g. after a tmux server crash) so
+	// slot allocation can reuse them.
+	if dead := s.pruneDeadSlots(workspaceName); len(dead) > 0 {
+		log.Printf("spawn_agent: pruned dead slots %v in workspace %q", dead, workspaceName)
+	}
+
+	spawn := s.spawnAgentWithDependencies
+	if s.spawnAgentFn != nil {
+		spawn = s.spawnAgentFn
+	}
+	tmuxTarget, slot, err := spawn(
 		workspaceName,
 		args.AgentType,
 		args.Cwd,
 		agentCmd,
 		spawnMode,
+		sessionName,
 		responseFence,
 		agentCfg,
 		nil,
@@ -257,28 +367,56 @@ func (s *Server) handleSpawnAgent(_ context.Context, _ *mcpsdk.CallToolRequest,
 			} else {
 				details["error"] = "spawn_failed"
 			}
+			details["reason"] = SpawnReason(err)
 			s.logger.Log(agent.ActionSpawnAgent, workspaceName, -1, details)
 		}
 		return nil, SpawnAgentOutput{}, err
 	}
 
-	// Write agent metadata to artifact dir so the hook CLI can look up config.
-	if err := writeAgentMeta(workspaceName, slot, args.AgentType); err != nil {
+	// Write agent metadata to artifact dir so the hook CLI can look up config
+	// and list_agents can describe the agent after a reconcile.
+	metaCwd := strings.TrimSpace(args.Cwd)
+	if metaCwd == "" {
+		metaCwd = s.slotCurrentPath(tmuxTarget)
+	}
+	meta := agentMeta{
+		AgentType:   args.AgentType,
+		Model:       selectedModel,
+		Cwd:         metaCwd,
+		SpawnedAt:   time.Now().UTC(),
+		SpawnMode:   spawnMode,
+		SessionName: sessionName,
+	}
+	if err := writeAgentMeta(workspaceName, slot, meta); err != nil {
 		log.Printf("Warning: failed to write agent meta for slot %d: %v", slot, err)
 	}
 
+	// post_spawn_keys must reach the agent before its task. A task passed
+	// as a CLI argument or piped is already running by the time the agent
+	// is ready, so the keys would interrupt it; skip them.
+	if (promptInCmd || pipeInCmd) && len(agentCfg.PostSpawnKeys) > 0 {
+		log.Printf("Warning: agent %q received its task in the command; skipping post_spawn_keys", args.AgentType)
+		agentCfg.PostSpawnKeys = nil
+	}
+
 	// If a task is provided and wasn't passed as a CLI argument or piped,
-	// wait until the agent is ready then send via tmux send-keys.
-	if taskTemplate != "" && !promptInCmd && !pipeInCmd {
-		// For non-hook agents in hooks mode, append file-write instructions
-		// now that we know the slot number.
-		if needsFileWriteInstructions {
-			if instr := fileWriteInstructions(workspaceName, slot); instr != "" {
-				taskToSend += instr
+	// wait until the agent is ready then send via tmux send-keys. Agents
+	// with post_spawn_keys also wait for readiness, even without a task.
+	sendTask := taskTemplate != "" && !promptInCmd && !pipeInCmd
+	if sendTask || len(agentCfg.PostSpawnKeys) > 0 {
+		task := ""
+		if sendTask {
+			// For non-hook agents in hooks mode, append file-write
+			// instructions now that we know the slot number.
+			if needsFileWriteInstructions {
+				if instr := fileWriteInstructions(workspaceName, slot); instr != "" {
+					taskToSend += instr
+				}
+				needsFileWriteInstructions = false
 			}
-			needsFileWriteInstructions = false
+			task = taskToSend
 		}
-		s.waitAndSendTask(tmuxTarget, args.AgentType, taskToSend, agentCfg)
+		s.waitAndSendTask(tmuxTarget, args.AgentType, task, agentCfg)
 	}
 
 	// For prompt_as_arg or piped agents without native hooks, send the
@@ -288,7 +426,7 @@ func (s *Server) handleSpawnAgent(_ context.Context, _ *mcpsdk.CallToolRequest,
 			go func() {
 				// Brief delay for the agent to start processing the initial task.
 				time.Sleep(3 * time.Second)
-				if err := tmuxSendKeys(tmuxTarget, instr); err != nil {
+				if err := s.slotSendKeys(tmuxTarget, instr); err != nil {
 					log.Printf("Warning: failed to send file-write instructions to slot %d: %v", slot, err)
 				}
 			}()
@@ -297,7 +435,7 @@ func (s *Server) handleSpawnAgent(_ context.Context, _ *mcpsdk.CallToolRequest,
 
 	// Activate pipe-pane for fence-enabled agents to capture the raw byte
 	// stream for reliable idle detection (avoids TUI artifacts in capture-pane).
-	if responseFence {
+	if capturePipe {
 		pipePath := pipeFilePath(workspaceName, slot)
 		if f, err := os.Create(pipePath); err == nil {
 			f.Close()
@@ -312,8 +450,8 @@ func (s *Server) handleSpawnAgent(_ context.Context, _ *mcpsdk.CallToolRequest,
 			// a real response.
 			time.Sleep(3 * time.Second)
 			if count, size, err := countCloseTagsInPipeFile(pipePath); err == nil {
-				s.updateFenceState(workspaceName, slot, true, count)
-				s.updateLastPipeSize(workspaceName, slot, size)
+				s.updateFenceState(workspaceName, slot, responseFence, count)
+				s.updateLastPipeSize(workspaceName, slot, size, count)
 			}
 		}
 	}
@@ -347,6 +485,25 @@ func (s *Server) handleSpawnAgent(_ context.Context, _ *mcpsdk.CallToolRequest,
 	}, nil
 }
 
+// spawnAgentTool is the spawn_agent handler. Failures are returned as error
+// results whose structured output carries the message and its reason code,
+// rather than as plain error text.
+func (s *Server) spawnAgentTool(ctx context.Context, req *mcpsdk.CallToolRequest, args SpawnAgentInput) (*mcpsdk.CallToolResult, SpawnAgentOutput, error) {
+	res, out, err := s.handleSpawnAgent(ctx, req, args)
+	if err == nil {
+		return res, out, nil
+	}
+	failed := &mcpsdk.CallToolResult{
+		IsError: true,
+		Content: []mcpsdk.Content{&mcpsdk.TextContent{Text: err.Error()}},
+	}
+	return failed, SpawnAgentOutput{
+		AgentType: args.AgentType,
+		Error:     err.Error(),
+		Reason:    SpawnReason(err),
+	}, nil
+}
+
 // spawnPane creates a new tmux pane (existing behavior).
 func (s *Server) spawnPane(workspace, agentType, fullCmd, cwd string, responseFence bool, agentCfg config.AgentConfig) (string, int, error) {
 	// Determine where to create the pane.
@@ -360,7 +517,7 @@ func (s *Server) spawnPane(workspace, agentType, fullCmd, cwd string, responseFe
 	} else {
 		targetSession := findAttachedSession()
 		if targetSession == "" {
-			return "", 0, fmt.Errorf("no attached tmux session found; please open a tmux terminal first")
+			return "", 0, spawnError(SpawnReasonNoTmuxSession, fmt.Errorf("no attached tmux session found; please open a tmux terminal first"))
 		}
 		splitTarget = targetSession
 	}
@@ -380,12 +537,12 @@ func (s *Server) spawnPane(workspace, agentType, fullCmd, cwd string, responseFe
 	}
 	out, err := cmd.CombinedOutput()
 	if err != nil {
-		return "", 0, fmt.Errorf("failed to create tmux pane: %w (%s)", err, strings.TrimSpace(string(out)))
+		return "", 0, spawnError(SpawnReasonTmuxFailed, fmt.Errorf("failed to create tmux pane: %w (%s)", err, strings.TrimSpace(string(out))))
 	}
 
 	tmuxTarget := strings.TrimSpace(string(out))
 	if tmuxTarget == "" {
-		return "", 0, fmt.Errorf("tmux did not return a pane ID")
+		return "", 0, spawnError(SpawnReasonTmuxFailed, fmt.Errorf("tmux did not return a pane ID"))
 	}
 
 	// Rebalance the layout so all panes are visible.
@@ -395,34 +552,95 @@ func (s *Server) spawnPane(workspace, agentType, fullCmd, cwd string, responseFe
 	return tmuxTarget, slot, nil
 }
 
+// sessionExists reports whether a session named name is running.
+func (s *Server) sessionExists(name string) bool {
+	if s.sessionExistsFn != nil {
+		return s.sessionExistsFn(name)
+	}
+	return s.slotSessionExists(name)
+}
+
+// spawnSessionName returns custom when set, otherwise the derived session
+// name for workspace and slot.
+func spawnSessionName(custom, workspace string, slot int) string {
+	if custom != "" {
+		return custom
+	}
+	return agent.SessionName(workspace, slot)
+}
+
+// checkSessionNameOverride validates a spawn_agent session_name and rejects
+// names already used by a tracked agent, a workspace registry slot or a
+// running tmux session.
+func (s *Server) checkSessionNameOverride(name, spawnMode string) error {
+	if spawnMode == "pane" {
+		return spawnError(SpawnReasonSessionNameInvalid, fmt.Errorf("session_name is not supported for pane spawns; use window or detached mode"))
+	}
+	if err := agent.ValidateSessionName(name); err != nil {
+		return spawnError(SpawnReasonSessionNameInvalid, err)
+	}
+
+	s.mu.Lock()
+	for ws, slots := range s.tracked {
+		for slot, ta := range slots {
+			if session, _, _ := strings.Cut(ta.tmuxTarget, ":"); session == name {
+				s.mu.Unlock()
+				return spawnError(SpawnReasonSessionNameInUse, fmt.Errorf("session name %q is already used by workspace %q slot %d", name, ws, slot))
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if workspacepkg.HasSessionInRegistry(name) {
+		return spawnError(SpawnReasonSessionNameInUse, fmt.Errorf("session name %q is already in the workspace registry", name))
+	}
+	if s.sessionExists(name) {
+		return spawnError(SpawnReasonSessionNameInUse, fmt.Errorf("tmux session %q already exists", name))
+	}
+	return nil
+}
+
+// windowSessionPollInterval paces the has-session checks while a spawned
+// terminal starts.
+const windowSessionPollInterval = 250 * time.Millisecond
+
+// waitForWindowSession polls until sessionName exists, failing with
+// SpawnReasonTmuxTimeout after agent_mode.window_spawn_timeout_s.
+func (s *Server) waitForWindowSession(sessionName string) error {
+	timeout := s.agentModeConfig().GetWindowSpawnTimeout()
+	deadline := time.Now().Add(timeout)
+	for {
+		if s.sessionExists(sessionName) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return spawnError(SpawnReasonTmuxTimeout, fmt.Errorf("timeout waiting for tmux session %q to appear after %s", sessionName, timeout))
+		}
+		time.Sleep(windowSessionPollInterval)
+	}
+}
+
 // spawnWindow creates a new terminal window with a tmux session running the
 // user's default shell. The agent command is NOT baked into the tmux session
 // command — it is sent via send-keys afterward so that shell init files
 // (.zshrc, .bashrc) are sourced and tool paths (proto, nvm, etc.) are available.
-func (s *Server) spawnWindow(workspace, agentType, cwd string, responseFence bool, agentCfg config.AgentConfig) (string, int, error) {
-	previousFocusID, _ := getActiveWindowID()
+func (s *Server) spawnWindow(workspace, agentType, cwd, customSession string, responseFence bool, agentCfg config.AgentConfig) (string, int, error) {
+	previousFocusID, _ := s.activeWindowID()
 
 	// Resolve which terminal emulator to use.
 	// Prefer the terminal class from the workspace config (matches what the
 	// workspace was saved with), falling back to the global config.
-	termClass := ""
-	savedCwd := ""
-	if savedWs, err := workspacepkg.Read(workspace); err == nil && len(savedWs.Terminals) > 0 {
-		termClass = savedWs.Terminals[0].WMClass
-		if c := strings.TrimSpace(savedWs.Terminals[0].Cwd); c != "" {
-			savedCwd = c
-		}
-	}
-	if termClass == "" {
-		termClass = s.config.ResolveTerminal()
-	}
+	termClass, savedCwd := s.windowTerminalClass(workspace)
 	if termClass == "" {
-		return "", 0, fmt.Errorf("no terminal emulator found; configure preferred_terminal or install a supported terminal")
+		return "", 0, spawnError(SpawnReasonNoTerminal, fmt.Errorf("no terminal emulator found; configure preferred_terminal or install a supported terminal"))
 	}
 
 	spawnTemplate, ok := lookupSpawnTemplate(s.config.TerminalSpawnCommands, termClass)
 	if !ok {
-		return "", 0, fmt.Errorf("no spawn template for terminal class %q; add it to terminal_spawn_commands", termClass)
+		return "", 0, spawnError(SpawnReasonTemplateMissing, fmt.Errorf("no spawn template for terminal class %q; add it to terminal_spawn_commands", termClass))
+	}
+	if err := checkSpawnTemplateBinary(spawnTemplate, termClass); err != nil {
+		return "", 0, err
 	}
 
 	slot := -1
@@ -432,21 +650,21 @@ func (s *Server) spawnWindow(workspace, agentType, cwd string, responseFence boo
 		registryDesktop = wsInfo.Desktop
 		addedSlot, addErr := workspacepkg.AddTerminalToWorkspace(wsInfo.Desktop, true)
 		if addErr != nil {
-			return "", 0, fmt.Errorf("failed to update workspace terminal registry for %q: %w", workspace, addErr)
+			return "", 0, spawnError(SpawnReasonRegistryFailed, fmt.Errorf("failed to update workspace terminal registry for %q: %w", workspace, addErr))
 		}
 		slot = addedSlot
 		registrySlot = addedSlot
 		if err := s.trackSpecificSlot(workspace, slot, agentType, "", "window", responseFence); err != nil {
 			_ = workspacepkg.RemoveTerminalFromWorkspace(wsInfo.Desktop, addedSlot)
-			return "", 0, fmt.Errorf("failed to track slot %d for workspace %q: %w", slot, workspace, err)
+			return "", 0, spawnError(SpawnReasonRegistryFailed, fmt.Errorf("failed to track slot %d for workspace %q: %w", slot, workspace, err))
 		}
 	} else if workspace != DefaultWorkspace {
-		return "", 0, fmt.Errorf("workspace %q not found in registry: %w", workspace, err)
+		return "", 0, spawnError(SpawnReasonWorkspaceNotFound, fmt.Errorf("workspace %q not found in registry: %w", workspace, err))
 	} else {
 		slot = s.allocateSlot(workspace, agentType, "", "window", responseFence)
 	}
 
-	sessionName := agent.SessionName(workspace, slot)
+	sessionName := spawnSessionName(customSession, workspace, slot)
 	sessionTarget := agent.TargetForSession(sessionName)
 	s.updateTmuxTarget(workspace, slot, sessionTarget)
 	success := false
@@ -475,53 +693,51 @@ func (s *Server) spawnWindow(workspace, agentType, cwd string, responseFence boo
 		}
 	}
 
-	// Build the tmux command that will run inside the terminal window.
-	// Start with the default shell so that init files are sourced.
+	// Build the multiplexer command that will run inside the terminal
+	// window. Start with the default shell so that init files are sourced.
 	tmuxCmd := fmt.Sprintf("tmux new-session -s %s -c %s",
 		shellQuote(sessionName), shellQuote(cwd))
+	if s.sessionMux != nil {
+		// The terminal starts in cwd, and the session's shell with it.
+		tmuxCmd = s.sessionMux.SessionCommand(sessionName)
+	}
 
 	// Render the terminal spawn template with the tmux command.
 	argv, err := renderSpawnTemplate(spawnTemplate, cwd, tmuxCmd)
 	if err != nil {
-		return "", 0, fmt.Errorf("failed to render spawn template: %w", err)
+		return "", 0, spawnError(SpawnReasonTemplateInvalid, fmt.Errorf("failed to render spawn template: %w", err))
 	}
 	if len(argv) == 0 {
-		return "", 0, fmt.Errorf("spawn template produced empty command")
+		return "", 0, spawnError(SpawnReasonTemplateInvalid, fmt.Errorf("spawn template produced empty command"))
 	}
 
 	// Set environment variables (including DISPLAY/XAUTHORITY for window mode).
-	cmd := exec.Command(argv[0], argv[1:]...)
-	cmd.Env = cmd.Environ()
-	for k, v := range agentCfg.Env {
-		cmd.Env = upsertEnv(cmd.Env, k, v)
-	}
+	cmd := windowSpawnCmd(argv, s.config.SpawnEnv(termClass), agentCfg.Env)
 	if err := ensureWindowSpawnEnv(cmd, s.config); err != nil {
-		return "", 0, err
+		return "", 0, spawnError(SpawnReasonNoDisplay, err)
 	}
 
 	// Fire and forget — the terminal window process runs independently.
 	if err := cmd.Start(); err != nil {
-		return "", 0, fmt.Errorf("failed to spawn terminal window: %w", err)
+		return "", 0, spawnError(SpawnReasonLaunchFailed, fmt.Errorf("failed to spawn terminal window: %w", err))
 	}
 
 	// Poll for the tmux session to appear (the terminal window needs time to start).
-	deadline := time.Now().Add(15 * time.Second)
-	for {
-		if exec.Command("tmux", "has-session", "-t", sessionName).Run() == nil {
-			break
-		}
-		if time.Now().After(deadline) {
-			return "", 0, fmt.Errorf("timeout waiting for tmux session %q to appear", sessionName)
-		}
-		time.Sleep(250 * time.Millisecond)
+	if err := s.waitForWindowSession(sessionName); err != nil {
+		return "", 0, err
 	}
 	success = true
+	if s.sessionMux == nil {
+		if err := applySessionOptions(sessionName, cwd, s.agentModeConfig().GetTmuxSessionOptions()); err != nil {
+			log.Printf("Warning: failed to set tmux options on %s: %v", sessionName, err)
+		}
+	}
 
 	// Give the terminal window time to appear as an X11 window, then
 	// correct its desktop if the user switched desktops since the workspace
 	// was created. This fixes the bug where resolveWorkspaceName() resolves
 	// based on the currently visible desktop instead of the workspace's desktop.
-	time.Sleep(500 * time.Millisecond)
+	time.Sleep(s.agentModeConfig().GetWindowSettle())
 	spawnedWindowID, _ := platform.FindWindowByTitleStandalone(sessionName)
 	if registryDesktop >= 0 {
 		currentDesktop, err := platform.GetCurrentDesktopStandalone()
@@ -537,35 +753,117 @@ func (s *Server) spawnWindow(workspace, agentType, cwd string, responseFence boo
 	// Best-effort focus restoration: if the spawned terminal took focus,
 	// return focus to the previously active window so caller typing is not hijacked.
 	if previousFocusID != 0 && previousFocusID != spawnedWindowID && spawnedWindowID != 0 {
-		if currentFocusID, ok := getActiveWindowID(); ok && currentFocusID == spawnedWindowID {
-			if err := platform.FocusWindowStandalone(previousFocusID); err != nil {
+		if currentFocusID, ok := s.activeWindowID(); ok && currentFocusID == spawnedWindowID {
+			if err := s.focusWindow(previousFocusID); err != nil {
 				log.Printf("Warning: failed to restore focus to window %d: %v", previousFocusID, err)
 			}
 		}
 	}
-	s.triggerRetile()
+	s.triggerRetile(workspace)
+
+	return sessionTarget, slot, nil
+}
+
+// spawnDetached creates a detached tmux session running the user's default
+// shell, with no terminal window. Like spawnWindow, the agent command is sent
+// afterward so shell init files are sourced. Detached slots are not added to
+// the workspace registry since there is no window to tile.
+func (s *Server) spawnDetached(workspace, agentType, cwd, customSession string, responseFence bool, agentCfg config.AgentConfig) (string, int, error) {
+	slot := s.allocateSlot(workspace, agentType, "", "detached", responseFence)
+	sessionName := spawnSessionName(customSession, workspace, slot)
+	sessionTarget := agent.TargetForSession(sessionName)
+	s.updateTmuxTarget(workspace, slot, sessionTarget)
+
+	if cwd == "" {
+		cwd = resolveProjectRoot()
+	}
+	if cwd == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			cwd = home
+		} else {
+			cwd = "/"
+		}
+	}
 
+	if s.sessionMux != nil {
+		starter, ok := s.sessionMux.(agent.DetachedStarter)
+		if !ok {
+			s.removeTracked(workspace, slot)
+			return "", 0, spawnError(SpawnReasonTmuxFailed, fmt.Errorf("%s cannot start detached sessions", s.sessionMux.Name()))
+		}
+		if err := starter.StartDetached(sessionName, cwd, sortedEnv(agentCfg.Env)); err != nil {
+			s.removeTracked(workspace, slot)
+			return "", 0, spawnError(SpawnReasonTmuxFailed, fmt.Errorf("failed to create detached %s session: %w", s.sessionMux.Name(), err))
+		}
+		return sessionTarget, slot, nil
+	}
+
+	out, err := exec.Command("tmux", detachedSessionArgs(sessionName, cwd, agentCfg.Env)...).CombinedOutput()
+	if err != nil {
+		s.removeTracked(workspace, slot)
+		return "", 0, spawnError(SpawnReasonTmuxFailed, fmt.Errorf("failed to create detached tmux session: %w (%s)", err, strings.TrimSpace(string(out))))
+	}
+	// A rejected option leaves the session with tmux's default for it.
+	if err := applySessionOptions(sessionName, cwd, s.agentModeConfig().GetTmuxSessionOptions()); err != nil {
+		log.Printf("Warning: failed to set tmux options on %s: %v", sessionName, err)
+	}
 	return sessionTarget, slot, nil
 }
 
+// sortedEnv returns env as KEY=VALUE pairs sorted by key.
+func sortedEnv(env map[string]string) []string {
+	if len(env) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]string, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, k+"="+env[k])
+	}
+	return out
+}
+
+// detachedSessionArgs builds the tmux new-session arguments for a detached
+// agent. Agent env is passed with -e so it reaches the session even when the
+// tmux server is already running.
+func detachedSessionArgs(sessionName, cwd string, env map[string]string) []string {
+	args := []string{"new-session", "-d", "-s", sessionName, "-c", cwd}
+	for _, kv := range sortedEnv(env) {
+		args = append(args, "-e", kv)
+	}
+	return args
+}
+
+// Timing for waitForShellAndSend: shellReadyTimeout bounds the wait for a new
+// window's prompt to stop changing, after which the agent command is typed
+// anyway, and shellPollInterval paces the checks.
+const (
+	shellReadyTimeout = 10 * time.Second
+	shellPollInterval = 300 * time.Millisecond
+)
+
 // waitForShellAndSend waits for the default shell to become ready in a new
 // tmux session, then sends the agent command via send-keys. This ensures
 // shell init files (.zshrc/.bashrc) are sourced before the agent starts,
 // making tool paths (proto, nvm, pyenv, etc.) available.
-func (s *Server) waitForShellAndSend(tmuxTarget, agentCmd string) {
+func (s *Server) waitForShellAndSend(tmuxTarget, agentCmd string, clearInput bool) {
 	// Wait for the shell prompt to appear (content stabilizes).
-	deadline := time.Now().Add(10 * time.Second)
+	deadline := time.Now().Add(shellReadyTimeout)
 	var lastOutput string
 	stableCount := 0
 	for time.Now().Before(deadline) {
-		out, err := tmuxCapturePane(tmuxTarget, 10)
+		out, err := s.slotCapturePane(tmuxTarget, 10)
 		if err != nil {
-			time.Sleep(300 * time.Millisecond)
+			time.Sleep(shellPollInterval)
 			continue
 		}
 		trimmed := strings.TrimSpace(out)
 		if trimmed == "" {
-			time.Sleep(300 * time.Millisecond)
+			time.Sleep(shellPollInterval)
 			continue
 		}
 		if trimmed == lastOutput {
@@ -577,24 +875,33 @@ func (s *Server) waitForShellAndSend(tmuxTarget, agentCmd string) {
 			stableCount = 0
 		}
 		lastOutput = trimmed
-		time.Sleep(300 * time.Millisecond)
+		time.Sleep(shellPollInterval)
 	}
 
-	if err := tmuxClearInputLine(tmuxTarget); err != nil {
-		log.Printf("Warning: failed to clear input line on %s: %v", tmuxTarget, err)
-	}
-	if err := tmuxSendKeys(tmuxTarget, agentCmd); err != nil {
+	if err := s.sendAgentInput(tmuxTarget, agentCmd, clearInput); err != nil {
 		log.Printf("Warning: failed to send agent command to %s: %v", tmuxTarget, err)
 	}
 }
 
-// waitAndSendTask waits for an agent to become ready, then sends the task text.
+// Timing for waitAndSendTask. These pace the agent TUI's own startup, not the
+// terminal startup agent_mode's window settings cover, so they stay fixed:
+// agentReadyTimeout bounds the wait for ready_pattern or stable output, and
+// agentReadySettle gives the TUI's input handler time to come up after
+// rendering.
+const (
+	agentReadyTimeout      = 30 * time.Second
+	agentReadyPollInterval = 500 * time.Millisecond
+	agentReadySettle       = 2 * time.Second
+)
+
+// waitAndSendTask waits for an agent to become ready, then sends its
+// post_spawn_keys and the task text. An empty task sends only the keys.
 func (s *Server) waitAndSendTask(tmuxTarget, agentType, task string, agentCfg config.AgentConfig) {
 	readyPattern := agentCfg.ReadyPattern
-	timeout := 30 * time.Second
+	timeout := agentReadyTimeout
 
 	if readyPattern != "" {
-		if _, err := tmuxWaitFor(tmuxTarget, readyPattern, timeout, 50); err != nil {
+		if _, err := s.slotWaitFor(tmuxTarget, readyPattern, timeout, 50, false); err != nil {
 			log.Printf("Warning: agent %q (target %s) not ready after %s, sending task anyway", agentType, tmuxTarget, timeout)
 		}
 	} else {
@@ -606,14 +913,14 @@ func (s *Server) waitAndSendTask(tmuxTarget, agentType, task string, agentCfg co
 		var lastOutput string
 		stableCount := 0
 		for time.Now().Before(deadline) {
-			out, err := tmuxCapturePane(tmuxTarget, 30)
+			out, err := s.slotCapturePane(tmuxTarget, 30)
 			if err != nil {
-				time.Sleep(500 * time.Millisecond)
+				time.Sleep(agentReadyPollInterval)
 				continue
 			}
 			trimmed := strings.TrimSpace(out)
 			if trimmed == "" {
-				time.Sleep(500 * time.Millisecond)
+				time.Sleep(agentReadyPollInterval)
 				continue
 			}
 			// Content exists. Check if it has stabilized (same for 2 consecutive polls).
@@ -626,36 +933,96 @@ func (s *Server) waitAndSendTask(tmuxTarget, agentType, task string, agentCfg co
 				stableCount = 0
 			}
 			lastOutput = trimmed
-			time.Sleep(500 * time.Millisecond)
+			time.Sleep(agentReadyPollInterval)
 		}
 		// Extra settle time for TUI input handler to become interactive
 		// after visual rendering completes.
-		time.Sleep(2 * time.Second)
+		time.Sleep(agentReadySettle)
 	}
 
-	if err := tmuxClearInputLine(tmuxTarget); err != nil {
-		log.Printf("Warning: failed to clear input line on %s: %v", tmuxTarget, err)
+	s.sendPostSpawnInputs(tmuxTarget, agentCfg.PostSpawnKeys, task, agentCfg.GetClearInputBeforeSend())
+}
+
+// applySessionOptions sets agent_mode.tmux_session_options on a session
+// spawn_agent just created, one set-option per option in name order, and
+// returns the options tmux rejected. tmux applies history-limit only to
+// panes created after it is set, so when it is set the session's first
+// window is replaced by a new one in cwd, which keeps index 0.
+func applySessionOptions(sessionName, cwd string, options map[string]string) error {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	target := "=" + sessionName + ":"
+	var errs []error
+	for _, name := range names {
+		if out, err := exec.Command("tmux", "set-option", "-t", target, name, options[name]).CombinedOutput(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w (%s)", name, err, strings.TrimSpace(string(out))))
+			continue
+		}
+		if name != "history-limit" {
+			continue
+		}
+		out, err := exec.Command("tmux",
+			"new-window", "-t", target, "-c", cwd, ";",
+			"kill-window", "-a", ";",
+			"move-window", "-r", "-t", target,
+		).CombinedOutput()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("history-limit: replace first window: %w (%s)", err, strings.TrimSpace(string(out))))
+		}
 	}
-	if err := tmuxSendKeys(tmuxTarget, task); err != nil {
-		log.Printf("Warning: failed to send initial task to %s: %v", tmuxTarget, err)
+	return errors.Join(errs...)
+}
+
+// clearAgentInput clears partially typed input before automation types into
+// a pane.
+func (s *Server) clearAgentInput(tmuxTarget string) error {
+	if s.clearInputFn != nil {
+		return s.clearInputFn(tmuxTarget)
 	}
+	return s.slotClearInputLine(tmuxTarget)
 }
 
-func getActiveWindowID() (uint32, bool) {
-	backend, err := platform.NewLinuxBackendFromDisplay()
-	if err != nil {
-		return 0, false
+// sendAgentInput sends text followed by Enter to tmuxTarget, first clearing
+// any partially typed input when clearInput is set (the agent's
+// clear_input_before_send).
+func (s *Server) sendAgentInput(tmuxTarget, text string, clearInput bool) error {
+	if s.sendInputFn != nil {
+		return s.sendInputFn(tmuxTarget, text, clearInput)
 	}
-	defer backend.Disconnect()
+	if clearInput {
+		if err := s.clearAgentInput(tmuxTarget); err != nil {
+			log.Printf("Warning: failed to clear input line on %s: %v", tmuxTarget, err)
+		}
+	}
+	return s.slotSendKeys(tmuxTarget, text)
+}
 
-	active, err := backend.ActiveWindow()
-	if err != nil {
-		return 0, false
+// postSpawnKeyPause is the pause after each post-spawn key entry, so the
+// agent handles one input before the next arrives. Tests shorten it.
+var postSpawnKeyPause = 300 * time.Millisecond
+
+// sendPostSpawnInputs sends each non-blank post-spawn key entry in order,
+// then the task if there is one. Failures are logged and do not stop the
+// remaining inputs.
+func (s *Server) sendPostSpawnInputs(tmuxTarget string, keys []string, task string, clearInput bool) {
+	for _, key := range keys {
+		if strings.TrimSpace(key) == "" {
+			continue
+		}
+		if err := s.sendAgentInput(tmuxTarget, key, clearInput); err != nil {
+			log.Printf("Warning: failed to send post-spawn keys %q to %s: %v", key, tmuxTarget, err)
+		}
+		time.Sleep(postSpawnKeyPause)
 	}
-	if active == 0 {
-		return 0, false
+	if task == "" {
+		return
+	}
+	if err := s.sendAgentInput(tmuxTarget, task, clearInput); err != nil {
+		log.Printf("Warning: failed to send initial task to %s: %v", tmuxTarget, err)
 	}
-	return uint32(active), true
 }
 
 func (s *Server) handleSendToAgent(_ context.Context, _ *mcpsdk.CallToolRequest, args SendToAgentInput) (*mcpsdk.CallToolResult, any, error) {
@@ -682,8 +1049,11 @@ func (s *Server) handleSendToAgent(_ context.Context, _ *mcpsdk.CallToolRequest,
 	agentType := s.getAgentType(workspaceName, args.Slot)
 	responseFence := false
 	if args.Text != "" && agentType != "" {
-		if agentCfg, ok := s.config.Agents[agentType]; ok && agentCfg.ResponseFence {
-			responseFence = true
+		if agentCfg, ok := s.config.Agents[agentType]; ok && (agentCfg.ResponseFence || outputModeUsesFence(agentCfg.OutputMode, false)) {
+			// With no_fence the text goes out raw and checkIdle skips the
+			// fence tiers for this task, but the baseline is still taken so
+			// close tags already on screen never count as a new response.
+			responseFence = !args.NoFence
 			// Snapshot current standalone close-tag count BEFORE sending so
 			// checkIdle can detect the new response by comparing counts.
 			// Prefer pipe file if available (more reliable than capture-pane).
@@ -692,20 +1062,26 @@ func (s *Server) handleSendToAgent(_ context.Context, _ *mcpsdk.CallToolRequest,
 			if pipePath != "" {
 				if count, size, err := countCloseTagsInPipeFile(pipePath); err == nil {
 					baseline = count
-					s.updateLastPipeSize(workspaceName, args.Slot, size)
+					s.updateLastPipeSize(workspaceName, args.Slot, size, count)
 				}
 			}
 			if pipePath == "" {
-				if out, err := tmuxCapturePane(target, 100); err == nil {
+				if out, err := s.slotCapturePane(target, 100); err == nil {
 					baseline = countCloseTags(out)
 				}
 			}
-			s.updateFenceState(workspaceName, args.Slot, true, baseline)
-			textToSend = wrapTaskWithFence(args.Text)
+			s.updateFenceState(workspaceName, args.Slot, responseFence, baseline)
+			if responseFence {
+				textToSend = wrapTaskWithFence(args.Text)
+			}
 		}
 	}
 
-	if err := tmuxSendKeys(target, textToSend); err != nil {
+	sendKeys := s.slotSendKeys
+	if s.sendKeysFn != nil {
+		sendKeys = s.sendKeysFn
+	}
+	if err := sendKeys(target, textToSend); err != nil {
 		if s.logger != nil {
 			details := map[string]interface{}{
 				"agent_type":     agentType,
@@ -716,8 +1092,9 @@ func (s *Server) handleSendToAgent(_ context.Context, _ *mcpsdk.CallToolRequest,
 			s.addTextDetails(details, args.Text)
 			s.logger.Log(agent.ActionSend, workspaceName, args.Slot, details)
 		}
-		return nil, nil, fmt.Errorf("failed to send to slot %d (target %s): %w", args.Slot, target, err)
+		return nil, nil, s.tmuxError(fmt.Errorf("failed to send to slot %d (target %s): %w", args.Slot, target, err))
 	}
+	s.resetComplete(workspaceName, args.Slot)
 	if s.logger != nil {
 		details := map[string]interface{}{
 			"agent_type":     agentType,
@@ -745,27 +1122,12 @@ func (s *Server) handleReadFromAgent(_ context.Context, _ *mcpsdk.CallToolReques
 		}
 		return nil, ReadFromAgentOutput{}, err
 	}
-	target, ok := s.getTmuxTarget(workspaceName, args.Slot)
-	if !ok {
-		if s.logger != nil {
-			s.logger.Log(agent.ActionRead, workspaceName, args.Slot, map[string]interface{}{
-				"error": "agent_not_tracked",
-			})
-		}
-		return nil, ReadFromAgentOutput{}, fmt.Errorf("no agent tracked in workspace %q slot %d", workspaceName, args.Slot)
-	}
-	agentType := s.getAgentType(workspaceName, args.Slot)
-
 	linesRequested := args.Lines
-	lines := normalizeReadLines(args.Lines)
+	defaultLines, maxLines := readLineLimits()
+	lines := normalizeReadLines(args.Lines, defaultLines, maxLines)
 
 	preProcess := func(raw string) string {
-		output := raw
-		if args.Clean {
-			output = cleanOutput(output)
-		}
-		output = tailOutputLines(output, lines)
-		return output
+		return ProcessReadOutput(raw, args.Clean, lines)
 	}
 
 	postProcess := func(raw string) string {
@@ -779,6 +1141,45 @@ func (s *Server) handleReadFromAgent(_ context.Context, _ *mcpsdk.CallToolReques
 		return output
 	}
 
+	target, ok := s.getTmuxTarget(workspaceName, args.Slot)
+	if !ok {
+		// A killed agent is no longer tracked; serve the output kill_agent
+		// saved instead.
+		ended, endedErr := ReadEndedOutput(workspaceName, args.Slot)
+		if endedErr != nil {
+			if s.logger != nil {
+				s.logger.Log(agent.ActionRead, workspaceName, args.Slot, map[string]interface{}{
+					"error": "agent_not_tracked",
+				})
+			}
+			return nil, ReadFromAgentOutput{}, fmt.Errorf("no agent tracked in workspace %q slot %d", workspaceName, args.Slot)
+		}
+		output := postProcess(ended.Output)
+		result := ReadFromAgentOutput{
+			Output:      output,
+			SessionName: ended.SessionName,
+			Ended:       true,
+		}
+		if args.Pattern != "" {
+			found := strings.Contains(preProcess(ended.Output), args.Pattern)
+			result.Found = &found
+		}
+		if s.logger != nil {
+			details := map[string]interface{}{
+				"lines_requested": linesRequested,
+				"lines_effective": lines,
+				"lines":           lines,
+				"clean":           args.Clean,
+				"since_last":      args.SinceLast,
+				"ended":           true,
+			}
+			s.addOutputDetails(details, output)
+			s.logger.Log(agent.ActionRead, workspaceName, args.Slot, details)
+		}
+		return nil, result, nil
+	}
+	agentType := s.getAgentType(workspaceName, args.Slot)
+
 	// When a pattern is provided, poll until it appears or timeout.
 	if args.Pattern != "" {
 		timeout := time.Duration(args.Timeout) * time.Second
@@ -786,7 +1187,7 @@ func (s *Server) handleReadFromAgent(_ context.Context, _ *mcpsdk.CallToolReques
 			timeout = 30 * time.Second
 		}
 
-		raw, waitErr := tmuxWaitFor(target, args.Pattern, timeout, lines)
+		raw, waitErr := s.slotWaitFor(target, args.Pattern, timeout, lines, s.captureEscapes())
 		output := postProcess(raw)
 		found := waitErr == nil
 
@@ -838,7 +1239,7 @@ func (s *Server) handleReadFromAgent(_ context.Context, _ *mcpsdk.CallToolReques
 	}
 
 	// One-shot read (no pattern): return a bounded tail preview window.
-	output, captureErr := tmuxCapturePane(target, lines)
+	output, captureErr := s.slotCapture(target, lines, s.captureEscapes())
 	if captureErr != nil {
 		if s.logger != nil {
 			s.logger.Log(agent.ActionRead, workspaceName, args.Slot, map[string]interface{}{
@@ -851,7 +1252,7 @@ func (s *Server) handleReadFromAgent(_ context.Context, _ *mcpsdk.CallToolReques
 				"error":           "capture_failed",
 			})
 		}
-		return nil, ReadFromAgentOutput{}, fmt.Errorf("failed to read from slot %d (target %s): %w", args.Slot, target, captureErr)
+		return nil, ReadFromAgentOutput{}, s.tmuxError(fmt.Errorf("failed to read from slot %d (target %s): %w", args.Slot, target, captureErr))
 	}
 
 	output = postProcess(output)
@@ -909,9 +1310,18 @@ func (s *Server) handleListAgents(_ context.Context, _ *mcpsdk.CallToolRequest,
 	if err != nil {
 		return nil, ListAgentsOutput{}, err
 	}
+	// With no tmux server running every tracked slot is dropped; the ones in
+	// this workspace are still reported below, once, with exists=false.
 	tracked := s.getTracked(workspaceName)
+	serverDown := s.checkTmuxServer() != nil
+	if serverDown && s.logger != nil {
+		s.logger.Log(agent.ActionListAgents, workspaceName, -1, map[string]interface{}{
+			"error": "tmux_server_down",
+		})
+	}
 
 	agents := make([]AgentInfo, 0, len(tracked))
+	pruned := 0
 	for slot, ta := range tracked {
 		info := AgentInfo{
 			Slot:        slot,
@@ -920,15 +1330,29 @@ func (s *Server) handleListAgents(_ context.Context, _ *mcpsdk.CallToolRequest,
 			Exists:      true,
 			SpawnMode:   ta.spawnMode,
 		}
+		var metaSpawnedAt time.Time
+		if meta, err := readAgentMeta(workspaceName, slot); err == nil {
+			applyAgentMeta(&info, meta)
+			metaSpawnedAt = meta.SpawnedAt
+		}
+		pipePath, _ := s.getPipeState(workspaceName, slot)
+		uptime, lastActivity := agentActivity(ta, metaSpawnedAt, pipeFileModTime(pipePath), time.Now())
+		info.UptimeSeconds = int64(uptime / time.Second)
+		if !lastActivity.IsZero() {
+			info.LastActivity = lastActivity.UTC().Format(time.RFC3339)
+		}
 
-		// Check if target still exists by trying to query it.
-		cmd := exec.Command("tmux", "display-message", "-t", ta.tmuxTarget, "-p", "#{pane_current_command}")
-		if out, err := cmd.Output(); err == nil {
-			info.CurrentCommand = strings.TrimSpace(string(out))
-			info.IsIdle = s.checkIdle(ta.tmuxTarget, ta.agentType, workspaceName, slot)
-		} else {
+		// Vanished sessions are reported once with exists=false and then
+		// dropped from tracking.
+		if serverDown || !s.targetExists(ta.tmuxTarget) {
 			info.Exists = false
+			s.removeTracked(workspaceName, slot)
+			pruned++
+			agents = append(agents, info)
+			continue
 		}
+		info.CurrentCommand = s.slotCurrentCommand(ta.tmuxTarget)
+		info.IsIdle = s.checkIdle(ta.tmuxTarget, ta.agentType, workspaceName, slot)
 
 		agents = append(agents, info)
 	}
@@ -952,6 +1376,7 @@ func (s *Server) handleListAgents(_ context.Context, _ *mcpsdk.CallToolRequest,
 			"agent_count":   len(agents),
 			"idle_count":    idleCount,
 			"missing_count": missingCount,
+			"pruned_count":  pruned,
 		})
 	}
 
@@ -1005,6 +1430,14 @@ func (s *Server) handleKillAgent(_ context.Context, _ *mcpsdk.CallToolRequest, a
 		log.Printf("Warning: failed to restore project file hooks for workspace %q slot %d: %v", workspaceName, args.Slot, err)
 	}
 
+	// Keep the final output so read_from_agent still works after the kill.
+	// Prefer the screen; fall back to the hook artifact if the target is
+	// already gone.
+	finalOutput, finalErr := s.slotCapturePane(target, maxReadLines)
+	if finalErr != nil {
+		finalOutput, finalErr = readArtifactOutputField(workspaceName, args.Slot)
+	}
+
 	// Stop pipe-pane and remove the pipe file before killing the session.
 	pipePath, _ := s.getPipeState(workspaceName, args.Slot)
 	if pipePath != "" {
@@ -1012,15 +1445,10 @@ func (s *Server) handleKillAgent(_ context.Context, _ *mcpsdk.CallToolRequest, a
 		removePipeFile(pipePath)
 	}
 
-	if mode == "window" {
-		// Window-mode: kill the entire tmux session. The terminal window
-		// closes because its process (tmux attach) exits.
-		// Extract session name from target (e.g., "termtile-ws-0:0.0" → "termtile-ws-0").
-		sessionName := target
-		if idx := strings.Index(target, ":"); idx >= 0 {
-			sessionName = target[:idx]
-		}
-		_ = exec.Command("tmux", "kill-session", "-t", sessionName).Run()
+	if mode == "window" || mode == "detached" {
+		// Window/detached mode: kill the entire session. A window-mode
+		// terminal closes because its process (the attached client) exits.
+		_ = s.killSlotSession(target)
 	} else {
 		// Pane-mode: kill just the pane.
 		_ = exec.Command("tmux", "kill-pane", "-t", target).Run()
@@ -1031,7 +1459,14 @@ func (s *Server) handleKillAgent(_ context.Context, _ *mcpsdk.CallToolRequest, a
 	if err := CleanupArtifact(workspaceName, args.Slot); err != nil {
 		log.Printf("Warning: failed to clean artifact directory for workspace %q slot %d: %v", workspaceName, args.Slot, err)
 	}
+	if finalErr == nil {
+		ended := EndedOutput{SessionName: target, Output: finalOutput, EndedAt: time.Now().UTC()}
+		if err := writeEndedOutput(workspaceName, args.Slot, ended); err != nil {
+			log.Printf("Warning: failed to save final output for workspace %q slot %d: %v", workspaceName, args.Slot, err)
+		}
+	}
 
+	var compactErr error
 	if mode == "window" {
 		if wsInfo, err := workspacepkg.GetWorkspaceByName(workspaceName); err == nil {
 			if err := workspacepkg.RemoveTerminalFromWorkspace(wsInfo.Desktop, args.Slot); err != nil {
@@ -1039,6 +1474,7 @@ func (s *Server) handleKillAgent(_ context.Context, _ *mcpsdk.CallToolRequest, a
 			} else {
 				if err := s.compactWindowSlots(workspaceName, args.Slot); err != nil {
 					log.Printf("Warning: failed to compact slots for workspace %q after removing slot %d: %v", workspaceName, args.Slot, err)
+					compactErr = err
 				}
 			}
 		}
@@ -1049,25 +1485,33 @@ func (s *Server) handleKillAgent(_ context.Context, _ *mcpsdk.CallToolRequest, a
 	if mode == "window" {
 		// Give the terminal window time to close before re-tiling.
 		time.Sleep(300 * time.Millisecond)
-		s.triggerRetile()
-	} else {
+		s.triggerRetile(workspaceName)
+	} else if mode != "detached" {
 		if remainingPane := s.anyPaneModeTarget(workspaceName); remainingPane != "" {
 			_ = exec.Command("tmux", "select-layout", "-t", remainingPane, "tiled").Run()
 		}
 	}
+	out := KillAgentOutput{
+		SessionName: target,
+		Killed:      true,
+	}
+	if compactErr != nil {
+		out.Warning = compactWarning(compactErr)
+	}
 	if s.logger != nil {
-		s.logger.Log(agent.ActionKillAgent, workspaceName, args.Slot, map[string]interface{}{
+		details := map[string]interface{}{
 			"agent_type":   agentType,
 			"spawn_mode":   mode,
 			"session_name": target,
 			"killed":       true,
-		})
+		}
+		if out.Warning != "" {
+			details["warning"] = out.Warning
+		}
+		s.logger.Log(agent.ActionKillAgent, workspaceName, args.Slot, details)
 	}
 
-	return nil, KillAgentOutput{
-		SessionName: target,
-		Killed:      true,
-	}, nil
+	return nil, out, nil
 }
 
 func (s *Server) handleGetArtifact(_ context.Context, _ *mcpsdk.CallToolRequest, args GetArtifactArgs) (*mcpsdk.CallToolResult, GetArtifactOutput, error) {
@@ -1096,16 +1540,32 @@ func (s *Server) handleGetArtifact(_ context.Context, _ *mcpsdk.CallToolRequest,
 		}
 	}
 
-	output := payload.Output
+	full := payload.Output
+	cursor := artifactCursor(full)
+	output := full
+	incremental := false
+	warning := ""
+	if since := strings.TrimSpace(args.Since); since != "" {
+		if delta, ok := artifactDelta(s.getArtifactMark(workspaceName, args.Slot), since, full); ok {
+			output = delta
+			incremental = true
+		} else {
+			warning = "since cursor does not match the last fetch for this slot; returning full artifact"
+		}
+	}
+	s.setArtifactMark(workspaceName, args.Slot, artifactMark{cursor: cursor, length: len(full)})
+
 	return nil, GetArtifactOutput{
 		Workspace:      workspaceName,
 		Slot:           args.Slot,
 		Output:         output,
 		Truncated:      false,
-		Warning:        "",
-		OriginalBytes:  len(output),
-		StoredBytes:    len(output),
+		Warning:        warning,
+		OriginalBytes:  len(full),
+		StoredBytes:    len(full),
 		LastUpdatedUTC: lastUpdated,
+		Cursor:         cursor,
+		Incremental:    incremental,
 	}, nil
 }
 
@@ -1131,7 +1591,7 @@ func (s *Server) handleWaitForIdle(_ context.Context, _ *mcpsdk.CallToolRequest,
 
 	timeout := time.Duration(args.Timeout) * time.Second
 	if timeout <= 0 {
-		timeout = 120 * time.Second
+		timeout = s.agentModeConfig().GetDefaultIdleTimeout()
 	}
 	lines := args.Lines
 	if lines <= 0 {
@@ -1139,14 +1599,23 @@ func (s *Server) handleWaitForIdle(_ context.Context, _ *mcpsdk.CallToolRequest,
 	}
 
 	agentType := s.getAgentType(workspaceName, args.Slot)
-	const waitOutputMode = "hooks"
+	waitOutputMode := "hooks"
 
 	start := time.Now()
 	deadline := time.Now().Add(timeout)
 
 	for {
+		// Prefer the hook artifact; under output_mode "both" a new fenced
+		// response also counts.
 		raw, ready, readErr := readHookArtifactOutput(workspaceName, args.Slot)
+		if readErr != nil || !ready {
+			if fenced, ok := s.fenceIdleOutput(target, agentType, workspaceName, args.Slot); ok {
+				raw, ready, readErr = fenced, true, nil
+				waitOutputMode = "fence"
+			}
+		}
 		if readErr == nil && ready {
+			s.notifyComplete(workspaceName, args.Slot)
 			if s.logger != nil {
 				details := map[string]interface{}{
 					"agent_type":      agentType,
@@ -1185,10 +1654,83 @@ func (s *Server) handleWaitForIdle(_ context.Context, _ *mcpsdk.CallToolRequest,
 			}, nil
 		}
 
-		time.Sleep(2 * time.Second)
+		time.Sleep(s.idlePollInterval())
 	}
 }
 
+// windowTerminalClass returns the terminal class a window spawn into
+// workspace uses, preferring the class the workspace was saved with, and the
+// saved cwd of its first terminal.
+func (s *Server) windowTerminalClass(workspace string) (termClass, savedCwd string) {
+	if savedWs, err := workspacepkg.Read(workspace); err == nil && len(savedWs.Terminals) > 0 {
+		termClass = savedWs.Terminals[0].WMClass
+		savedCwd = strings.TrimSpace(savedWs.Terminals[0].Cwd)
+	}
+	if termClass == "" {
+		termClass = s.config.ResolveTerminal()
+	}
+	return termClass, savedCwd
+}
+
+// windowSpawnEnv merges the terminal class's terminal_spawn_env with the
+// agent env for a window spawn, agent entries winning.
+func windowSpawnEnv(termEnv, agentEnv map[string]string) map[string]string {
+	env := make(map[string]string, len(termEnv)+len(agentEnv))
+	for _, src := range []map[string]string{termEnv, agentEnv} {
+		for k, v := range src {
+			env[k] = v
+		}
+	}
+	return env
+}
+
+// windowSpawnCmd builds the terminal command for a window-mode spawn. The
+// terminal class's terminal_spawn_env is applied first, then the agent's
+// env, so an agent setting wins over the terminal's.
+func windowSpawnCmd(argv []string, termEnv, agentEnv map[string]string) *exec.Cmd {
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Env = cmd.Environ()
+	for k, v := range windowSpawnEnv(termEnv, agentEnv) {
+		cmd.Env = upsertEnv(cmd.Env, k, v)
+	}
+	return cmd
+}
+
+// inlineTaskDelivery decides whether spawn_agent passes the task as a
+// command-line argument or pipes it on stdin. Tasks over maxBytes
+// (agent_mode.max_inline_task_bytes) use neither and are typed in with
+// send-keys after the agent starts.
+func inlineTaskDelivery(agentCfg config.AgentConfig, hasTask bool, task string, maxBytes int) (promptInCmd, pipeInCmd bool) {
+	if !hasTask || len(task) > maxBytes {
+		return false, false
+	}
+	if agentCfg.PromptAsArg {
+		return true, false
+	}
+	return false, agentCfg.PipeTask
+}
+
+// fenceIdleOutput is wait_for_idle's fallback for output_mode "both": it
+// returns the latest fenced response once checkIdle sees one newer than the
+// slot's fence baseline.
+func (s *Server) fenceIdleOutput(target, agentType, workspace string, slot int) (string, bool) {
+	agentCfg, ok := s.config.Agents[agentType]
+	if !ok || !strings.EqualFold(strings.TrimSpace(agentCfg.OutputMode), "both") {
+		return "", false
+	}
+	if hasFence, _ := s.getFenceState(workspace, slot); !hasFence {
+		return "", false
+	}
+	if !s.checkIdle(target, agentType, workspace, slot) {
+		return "", false
+	}
+	out, err := s.slotCapturePane(target, maxReadLines)
+	if err != nil {
+		return "", false
+	}
+	return trimOutput(out, true), true
+}
+
 func (s *Server) handleMoveTerminal(_ context.Context, _ *mcpsdk.CallToolRequest, args MoveTerminalInput) (*mcpsdk.CallToolResult, MoveTerminalOutput, error) {
 	srcWorkspace, err := resolveWorkspaceForRead(args.Workspace, args.SourceWorkspace, "move_terminal")
 	if err != nil {
@@ -1241,7 +1783,7 @@ func (s *Server) handleMoveTerminal(_ context.Context, _ *mcpsdk.CallToolRequest
 		return nil, MoveTerminalOutput{}, fmt.Errorf("failed to update workspace registry: %w", err)
 	}
 
-	if err := moveArtifactDir(srcWorkspace, args.Slot, dstWorkspace, newSlot); err != nil {
+	if err := MoveArtifactDir(srcWorkspace, args.Slot, dstWorkspace, newSlot); err != nil {
 		log.Printf(
 			"Warning: failed to move artifact directory for %q slot %d -> %q slot %d: %v",
 			srcWorkspace,
@@ -1252,12 +1794,16 @@ func (s *Server) handleMoveTerminal(_ context.Context, _ *mcpsdk.CallToolRequest
 		)
 	}
 
-	// Rename tmux session from old workspace naming to new.
-	newSessionName := agent.SessionName(dstWorkspace, newSlot)
-	newTarget := agent.TargetForSession(newSessionName)
-	if err := s.multiplexer.RenameSession(oldSessionName, newSessionName); err != nil {
-		log.Printf("Warning: failed to rename tmux session %q to %q: %v", oldSessionName, newSessionName, err)
+	// Rename tmux session from old workspace naming to new. Custom session
+	// names are kept as they are.
+	newSessionName := oldSessionName
+	if agent.IsDerivedSessionName(oldSessionName) {
+		newSessionName = agent.SessionName(dstWorkspace, newSlot)
+		if err := s.multiplexer.RenameSession(oldSessionName, newSessionName); err != nil {
+			log.Printf("Warning: failed to rename tmux session %q to %q: %v", oldSessionName, newSessionName, err)
+		}
 	}
+	newTarget := agent.TargetForSession(newSessionName)
 
 	// Transfer MCP tracking state: copy tracked agent, remove from source, compact
 	// shifted source slots, then add to destination.
@@ -1269,9 +1815,11 @@ func (s *Server) handleMoveTerminal(_ context.Context, _ *mcpsdk.CallToolRequest
 	}
 	s.mu.Unlock()
 
+	var compactErr error
 	if mode == "window" {
 		if err := s.compactWindowSlots(srcWorkspace, args.Slot); err != nil {
 			log.Printf("Warning: failed to compact source workspace %q after moving slot %d: %v", srcWorkspace, args.Slot, err)
+			compactErr = err
 		}
 	}
 
@@ -1291,10 +1839,21 @@ func (s *Server) handleMoveTerminal(_ context.Context, _ *mcpsdk.CallToolRequest
 
 	// Retile the current desktop.
 	time.Sleep(300 * time.Millisecond)
-	s.triggerRetile()
+	s.triggerRetile(srcWorkspace, dstWorkspace)
 
+	out := MoveTerminalOutput{
+		SourceWorkspace: srcWorkspace,
+		TargetWorkspace: dstWorkspace,
+		SourceSlot:      args.Slot,
+		TargetSlot:      newSlot,
+		SessionName:     newTarget,
+		Moved:           true,
+	}
+	if compactErr != nil {
+		out.Warning = compactWarning(compactErr)
+	}
 	if s.logger != nil {
-		s.logger.Log(agent.ActionMoveTerminal, srcWorkspace, args.Slot, map[string]interface{}{
+		details := map[string]interface{}{
 			"agent_type":       agentType,
 			"spawn_mode":       mode,
 			"source_workspace": srcWorkspace,
@@ -1303,27 +1862,28 @@ func (s *Server) handleMoveTerminal(_ context.Context, _ *mcpsdk.CallToolRequest
 			"target_slot":      newSlot,
 			"old_session":      oldSessionName,
 			"new_session":      newSessionName,
-		})
+		}
+		if out.Warning != "" {
+			details["warning"] = out.Warning
+		}
+		s.logger.Log(agent.ActionMoveTerminal, srcWorkspace, args.Slot, details)
 	}
 
-	return nil, MoveTerminalOutput{
-		SourceWorkspace: srcWorkspace,
-		TargetWorkspace: dstWorkspace,
-		SourceSlot:      args.Slot,
-		TargetSlot:      newSlot,
-		SessionName:     newTarget,
-		Moved:           true,
-	}, nil
+	return nil, out, nil
 }
 
 type sessionRename struct {
-	old string
-	new string
+	fromSlot int
+	toSlot   int
+	old      string
+	new      string
 }
 
 // compactWindowSlots shifts tracked window-mode slots down after removing a
 // slot from the workspace registry (which compacts indices). It also migrates
 // artifacts/read snapshots and renames tmux sessions to keep slot suffixes aligned.
+// Every step is attempted; artifact-move and rename failures are returned
+// joined, one error per slot, since tracking no longer matches tmux/disk.
 func (s *Server) compactWindowSlots(workspace string, removedSlot int) error {
 	if removedSlot < 0 {
 		return nil
@@ -1355,9 +1915,13 @@ func (s *Server) compactWindowSlots(workspace string, removedSlot int) error {
 			if oldSession == "" {
 				oldSession = agent.SessionName(workspace, slot)
 			}
-			newSession := agent.SessionName(workspace, newSlot)
+			// Custom session names are not tied to the slot; keep them.
+			newSession := oldSession
+			if agent.IsDerivedSessionName(oldSession) {
+				newSession = agent.SessionName(workspace, newSlot)
+			}
 			if oldSession != newSession {
-				shifts = append(shifts, sessionRename{old: oldSession, new: newSession})
+				shifts = append(shifts, sessionRename{fromSlot: slot, toSlot: newSlot, old: oldSession, new: newSession})
 			}
 			ta.tmuxTarget = agent.TargetForSession(newSession)
 		}
@@ -1377,15 +1941,27 @@ func (s *Server) compactWindowSlots(workspace string, removedSlot int) error {
 		}
 		s.readSnapshots[workspace] = newSnaps
 	}
+	if marks := s.artifactMarks[workspace]; marks != nil {
+		newMarks := make(map[int]artifactMark, len(marks))
+		for slot, mark := range marks {
+			if slot <= removedSlot {
+				newMarks[slot] = mark
+				continue
+			}
+			newMarks[slot-1] = mark
+		}
+		s.artifactMarks[workspace] = newMarks
+	}
 	s.mu.Unlock()
 
+	var errs []error
 	sort.Slice(artifactMoves, func(i, j int) bool {
 		return artifactMoves[i][0] < artifactMoves[j][0]
 	})
 	for _, mv := range artifactMoves {
 		fromSlot := mv[0]
 		toSlot := mv[1]
-		if err := moveArtifactDir(workspace, fromSlot, workspace, toSlot); err != nil {
+		if err := MoveArtifactDir(workspace, fromSlot, workspace, toSlot); err != nil {
 			log.Printf(
 				"Warning: failed to move artifact directory for workspace %q slot %d -> %d: %v",
 				workspace,
@@ -1393,17 +1969,37 @@ func (s *Server) compactWindowSlots(workspace string, removedSlot int) error {
 				toSlot,
 				err,
 			)
+			errs = append(errs, fmt.Errorf("slot %d -> %d: move artifacts: %w", fromSlot, toSlot, err))
 		}
 	}
 
+	sort.Slice(shifts, func(i, j int) bool {
+		return shifts[i].fromSlot < shifts[j].fromSlot
+	})
 	for _, rename := range shifts {
-		if err := s.multiplexer.RenameSession(rename.old, rename.new); err != nil {
-			// Best effort: keep tracking in sync even if an external process already moved/killed it.
+		if err := s.renameSession(rename.old, rename.new); err != nil {
+			// Best effort: tracking already points at the new name, so keep
+			// renaming the rest and report the mismatch to the caller.
 			log.Printf("Warning: failed to rename shifted session %q -> %q: %v", rename.old, rename.new, err)
+			errs = append(errs, fmt.Errorf("slot %d -> %d: rename session %q -> %q: %w", rename.fromSlot, rename.toSlot, rename.old, rename.new, err))
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
+}
+
+// renameSession renames a tmux session through renameSessionFn when set.
+func (s *Server) renameSession(oldName, newName string) error {
+	if s.renameSessionFn != nil {
+		return s.renameSessionFn(oldName, newName)
+	}
+	return s.multiplexer.RenameSession(oldName, newName)
+}
+
+// compactWarning is the kill_agent/move_terminal warning for a failed
+// compactWindowSlots.
+func compactWarning(err error) string {
+	return fmt.Sprintf("slot compaction incomplete, tracking may be inconsistent: %v", err)
 }
 
 // isAgentModeWorkspace returns true if the given workspace name corresponds
@@ -1438,6 +2034,37 @@ type projectWorkspaceBinding struct {
 	SourcePath string
 }
 
+// workspaceDefaultAgent returns the default_agent saved for workspaceName,
+// used by spawn_agent when agent_type is omitted.
+func workspaceDefaultAgent(workspaceName string) (string, error) {
+	if savedWs, err := workspacepkg.Read(workspaceName); err == nil {
+		if agentType := strings.TrimSpace(savedWs.DefaultAgent); agentType != "" {
+			return agentType, nil
+		}
+	}
+	return "", spawnError(SpawnReasonNoAgentType, fmt.Errorf(
+		"agent_type is required: workspace %q has no default_agent (set one with 'termtile workspace save --default-agent <type> %s')",
+		workspaceName, workspaceName,
+	))
+}
+
+// workspaceSpawnEnv returns the env for an agent spawned in workspaceName:
+// the workspace's saved env with agentEnv layered on top.
+func workspaceSpawnEnv(workspaceName string, agentEnv map[string]string) map[string]string {
+	savedWs, err := workspacepkg.Read(workspaceName)
+	if err != nil || len(savedWs.Env) == 0 {
+		return agentEnv
+	}
+	env := make(map[string]string, len(savedWs.Env)+len(agentEnv))
+	for k, v := range savedWs.Env {
+		env[k] = v
+	}
+	for k, v := range agentEnv {
+		env[k] = v
+	}
+	return env
+}
+
 // resolveWorkspaceForSpawn resolves workspace selection for spawn_agent using
 // deterministic precedence:
 // explicit_arg -> source_workspace_hint -> project_marker -> single_registered_agent_workspace -> error
@@ -1462,7 +2089,7 @@ func resolveWorkspaceDeterministic(ws, sourceWorkspace, toolName string, require
 	}
 
 	projectWorkspace, projectSource, err := resolveWorkspaceFromProjectMarker()
-	if err != nil {
+	if err != nil && !errors.Is(err, ErrNoProjectBinding) {
 		return "", fmt.Errorf("failed to resolve workspace for %s: %w", toolName, err)
 	}
 	if projectWorkspace != "" {
@@ -1559,6 +2186,21 @@ func listRegisteredAgentWorkspaces() ([]string, error) {
 	return candidates, nil
 }
 
+// Project binding errors returned by findProjectBinding, for callers that
+// need to tell the cases apart.
+var (
+	// ErrNoProjectBinding means no .termtile/workspace.yaml or local.yaml
+	// naming a workspace was found from the working directory upward.
+	ErrNoProjectBinding = errors.New("no project binding found")
+	// ErrProjectRootMarkerMissing means a project config was found but its
+	// project.root_marker does not exist; `termtile workspace init --force`
+	// rewrites the config.
+	ErrProjectRootMarkerMissing = errors.New("project root_marker missing")
+)
+
+// resolveWorkspaceFromProjectMarker returns the workspace bound by the
+// project config around the working directory. Errors wrap
+// ErrNoProjectBinding or ErrProjectRootMarkerMissing where they apply.
 func resolveWorkspaceFromProjectMarker() (workspace string, sourcePath string, err error) {
 	workspace, _, sourcePath, err = findProjectBinding()
 	return workspace, sourcePath, err
@@ -1572,8 +2214,30 @@ func resolveProjectRoot() string {
 	return root
 }
 
+// readLineLimits returns the read_from_agent default and maximum line counts.
+// When the working directory resolves to a project binding, its
+// mcp.read.default_lines and max_lines replace the built-in 50 and 100.
+func readLineLimits() (defaultLines, maxLines int) {
+	defaultLines, maxLines = defaultReadLines, maxReadLines
+	root := resolveProjectRoot()
+	if root == "" {
+		return defaultLines, maxLines
+	}
+	projectCfg, err := config.LoadProjectWorkspace(root)
+	if err != nil {
+		log.Printf("Warning: ignoring project mcp.read settings: %v", err)
+		return defaultLines, maxLines
+	}
+	if projectCfg == nil {
+		return defaultLines, maxLines
+	}
+	return projectCfg.MCP.Read.DefaultLines, projectCfg.MCP.Read.MaxLines
+}
+
 // findProjectBinding walks up from cwd looking for .termtile/workspace.yaml
 // and returns the workspace name, project root directory, and source path.
+// It returns ErrNoProjectBinding when there is none, and an error wrapping
+// ErrProjectRootMarkerMissing when the config's root marker is absent.
 func findProjectBinding() (workspace string, projectRoot string, sourcePath string, err error) {
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -1598,9 +2262,10 @@ func findProjectBinding() (workspace string, projectRoot string, sourcePath stri
 			if _, err := os.Stat(markerPath); err != nil {
 				if os.IsNotExist(err) {
 					return "", "", "", fmt.Errorf(
-						"project workspace config %q references missing project.root_marker %q; pass workspace explicitly or fix project config",
+						"project workspace config %q references missing project.root_marker %q; pass workspace explicitly, fix project config, or rerun 'termtile workspace init --force': %w",
 						binding.SourcePath,
 						marker,
+						ErrProjectRootMarkerMissing,
 					)
 				}
 				return "", "", "", fmt.Errorf(
@@ -1620,7 +2285,7 @@ func findProjectBinding() (workspace string, projectRoot string, sourcePath stri
 		}
 	}
 
-	return "", "", "", nil
+	return "", "", "", ErrNoProjectBinding
 }
 
 func loadProjectWorkspaceBinding(dir string) (projectWorkspaceBinding, bool, error) {
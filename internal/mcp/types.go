@@ -4,18 +4,22 @@ import "time"
 
 // SpawnAgentInput is the input for the spawn_agent tool.
 type SpawnAgentInput struct {
-	AgentType string `json:"agent_type" jsonschema:"required,The agent type from config (e.g. claude, codex, aider)"`
+	AgentType string `json:"agent_type,omitempty" jsonschema:"The agent type from config (e.g. claude, codex, aider). Defaults to the workspace's default_agent when omitted."`
 	Workspace string `json:"workspace,omitempty" jsonschema:"Workspace name (default: active workspace on current desktop). When no active workspace is detected, pass this explicitly."`
 	// SourceWorkspace is an optional request-scoped hint used when workspace is omitted.
 	SourceWorkspace string  `json:"source_workspace,omitempty" jsonschema:"Optional source workspace hint from the caller. Used only when workspace is omitted."`
 	Cwd             string  `json:"cwd,omitempty" jsonschema:"Working directory for the agent"`
 	Task            string  `json:"task,omitempty" jsonschema:"Initial task/prompt to send after agent starts. When prompt_as_arg is true for the agent, the task is passed as a CLI argument for instant delivery; otherwise it is sent via tmux send-keys after the agent is ready."`
 	Model           *string `json:"model,omitempty" jsonschema:"Optional model name to pass to the agent CLI. If omitted, the agent config default_model is used when configured."`
-	Window          *bool   `json:"window,omitempty" jsonschema:"When true, spawn the agent in a new terminal window instead of a tmux pane. Overrides the agent's configured spawn_mode."`
+	Window          *bool   `json:"window,omitempty" jsonschema:"When true, spawn the agent in a new terminal window instead of a tmux pane. Overrides the agent's configured spawn_mode, including detached."`
 	DependsOn       []int   `json:"depends_on,omitempty" jsonschema:"Optional list of slot numbers that must be idle before spawning this agent. If any dependency slot is missing or killed, spawn fails."`
 	// DependsOnTimeout is only used when DependsOn is set.
 	// Value is seconds; default is 300.
-	DependsOnTimeout int `json:"depends_on_timeout,omitempty" jsonschema:"Timeout in seconds to wait for depends_on slots to become idle (default: 300). Only used when depends_on is set."`
+	DependsOnTimeout int  `json:"depends_on_timeout,omitempty" jsonschema:"Timeout in seconds to wait for depends_on slots to become idle (default: agent_mode.default_dep_timeout_s, 300). Only used when depends_on is set."`
+	DryRun           bool `json:"dry_run,omitempty" jsonschema:"When true, build the agent command (model, hook flags, fence wrapping, task delivery, env) and return it without spawning anything. depends_on is not waited for."`
+	NoFence          bool `json:"no_fence,omitempty" jsonschema:"When true, send the task without response fence instructions even if the agent has response_fence enabled. Idle detection then uses idle patterns and process checks for this task."`
+	// SessionName overrides the derived termtile-<workspace>-<slot> name.
+	SessionName string `json:"session_name,omitempty" jsonschema:"Optional tmux session name to use instead of the derived termtile-<workspace>-<slot> name. Letters, digits, '-' and '_' only; must not start with termtile- and must not already be in use. Not supported for pane spawns."`
 }
 
 // SpawnAgentOutput is the output for the spawn_agent tool.
@@ -25,6 +29,15 @@ type SpawnAgentOutput struct {
 	AgentType   string `json:"agent_type"`
 	Workspace   string `json:"workspace"`
 	SpawnMode   string `json:"spawn_mode"`
+	// Dry-run fields; Slot is then the slot the spawn would most likely get.
+	DryRun       bool     `json:"dry_run,omitempty"`
+	Command      string   `json:"command,omitempty"`
+	Env          []string `json:"env,omitempty"`
+	TaskDelivery string   `json:"task_delivery,omitempty"`
+	SendKeysTask string   `json:"send_keys_task,omitempty"`
+	// Failure fields, set on error results; Reason is a SpawnReason code.
+	Error  string `json:"error,omitempty"`
+	Reason string `json:"reason,omitempty"`
 }
 
 // SendToAgentInput is the input for the send_to_agent tool.
@@ -34,6 +47,7 @@ type SendToAgentInput struct {
 	Workspace string `json:"workspace,omitempty" jsonschema:"Workspace name (default: resolved from explicit/source_workspace/project marker/single registered workspace)."`
 	// SourceWorkspace is an optional request-scoped hint used when workspace is omitted.
 	SourceWorkspace string `json:"source_workspace,omitempty" jsonschema:"Optional source workspace hint from the caller. Used only when workspace is omitted."`
+	NoFence         bool   `json:"no_fence,omitempty" jsonschema:"When true, send the text without response fence instructions even if the agent has response_fence enabled. Idle detection then uses idle patterns and process checks for this task."`
 }
 
 // ReadFromAgentInput is the input for the read_from_agent tool.
@@ -54,6 +68,9 @@ type ReadFromAgentOutput struct {
 	Output      string `json:"output"`
 	SessionName string `json:"session_name"`
 	Found       *bool  `json:"found,omitempty"`
+	// Ended is set when the agent was killed and Output is the final
+	// output kill_agent saved.
+	Ended bool `json:"ended,omitempty"`
 }
 
 // ListAgentsInput is the input for the list_agents tool.
@@ -72,6 +89,11 @@ type AgentInfo struct {
 	IsIdle         bool   `json:"is_idle"`
 	Exists         bool   `json:"exists"`
 	SpawnMode      string `json:"spawn_mode"`
+	Model          string `json:"model,omitempty"`
+	Cwd            string `json:"cwd,omitempty"`
+	SpawnedAt      string `json:"spawned_at,omitempty"` // RFC 3339, from the slot's agent meta
+	UptimeSeconds  int64  `json:"uptime_seconds,omitempty"`
+	LastActivity   string `json:"last_activity,omitempty"` // RFC 3339; last observed output
 }
 
 // ListAgentsOutput is the output for the list_agents tool.
@@ -92,12 +114,15 @@ type KillAgentInput struct {
 type KillAgentOutput struct {
 	SessionName string `json:"session_name"`
 	Killed      bool   `json:"killed"`
+	// Warning is set when later slots could not be fully compacted, so
+	// tracking may not match tmux sessions or artifacts.
+	Warning string `json:"warning,omitempty"`
 }
 
 // WaitForIdleInput is the input for the wait_for_idle tool.
 type WaitForIdleInput struct {
 	Slot      int    `json:"slot" jsonschema:"required,Slot index to monitor"`
-	Timeout   int    `json:"timeout,omitempty" jsonschema:"Timeout in seconds (default: 120)"`
+	Timeout   int    `json:"timeout,omitempty" jsonschema:"Timeout in seconds (default: agent_mode.default_idle_timeout_s, 120)"`
 	Lines     int    `json:"lines,omitempty" jsonschema:"Number of lines to capture when idle (default: 100)"`
 	Workspace string `json:"workspace,omitempty" jsonschema:"Workspace name (default: resolved from explicit/source_workspace/project marker/single registered workspace)."`
 	// SourceWorkspace is an optional request-scoped hint used when workspace is omitted.
@@ -128,6 +153,9 @@ type MoveTerminalOutput struct {
 	TargetSlot      int    `json:"target_slot"`
 	SessionName     string `json:"session_name"`
 	Moved           bool   `json:"moved"`
+	// Warning is set when the source workspace's later slots could not be
+	// fully compacted, so tracking may not match tmux sessions or artifacts.
+	Warning string `json:"warning,omitempty"`
 }
 
 // GetArtifactArgs is the input for the get_artifact tool.
@@ -136,6 +164,7 @@ type GetArtifactArgs struct {
 	Workspace string `json:"workspace,omitempty" jsonschema:"Workspace name (default: resolved from explicit/source_workspace/project marker/single registered workspace)."`
 	// SourceWorkspace is an optional request-scoped hint used when workspace is omitted.
 	SourceWorkspace string `json:"source_workspace,omitempty" jsonschema:"Optional source workspace hint from the caller. Used only when workspace is omitted."`
+	Since           string `json:"since,omitempty" jsonschema:"Cursor from a previous get_artifact response for this slot. When it still matches, only output appended since that fetch is returned; otherwise the full artifact is returned with a warning."`
 }
 
 // GetArtifactOutput is the output for the get_artifact tool.
@@ -148,4 +177,32 @@ type GetArtifactOutput struct {
 	OriginalBytes  int       `json:"original_bytes"`
 	StoredBytes    int       `json:"stored_bytes"`
 	LastUpdatedUTC time.Time `json:"last_updated_utc"`
+	// Cursor identifies the full artifact output; pass it back as since.
+	Cursor      string `json:"cursor"`
+	Incremental bool   `json:"incremental,omitempty"`
+}
+
+// GetLogsInput is the input for the get_logs tool.
+type GetLogsInput struct {
+	Slot      *int   `json:"slot,omitempty" jsonschema:"Only return entries for this slot (default: all entries for the workspace, including workspace-level actions)"`
+	Action    string `json:"action,omitempty" jsonschema:"Only return entries for this action, e.g. spawn_agent or SPAWN-AGENT"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"Maximum number of most recent entries to return (default: 50, max: 500)"`
+	Workspace string `json:"workspace,omitempty" jsonschema:"Workspace name (default: resolved from explicit/source_workspace/project marker/single registered workspace)."`
+	// SourceWorkspace is an optional request-scoped hint used when workspace is omitted.
+	SourceWorkspace string `json:"source_workspace,omitempty" jsonschema:"Optional source workspace hint from the caller. Used only when workspace is omitted."`
+}
+
+// LogEntryInfo describes a single agent action log entry.
+type LogEntryInfo struct {
+	Time    string            `json:"time"` // RFC 3339
+	Action  string            `json:"action"`
+	Slot    *int              `json:"slot,omitempty"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// GetLogsOutput is the output for the get_logs tool.
+type GetLogsOutput struct {
+	Workspace string         `json:"workspace"`
+	LogFile   string         `json:"log_file"`
+	Entries   []LogEntryInfo `json:"entries"`
 }
@@ -1,11 +1,14 @@
 package mcp
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/1broseidon/termtile/internal/config"
 	workspacepkg "github.com/1broseidon/termtile/internal/workspace"
 )
 
@@ -136,6 +139,79 @@ func TestResolveWorkspaceForSpawn_ProjectMarkerMissingRootMarkerReturnsClearErro
 	if !strings.Contains(err.Error(), "project.root_marker") {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if !errors.Is(err, ErrProjectRootMarkerMissing) {
+		t.Fatalf("error %v does not wrap ErrProjectRootMarkerMissing", err)
+	}
+}
+
+func TestFindProjectBinding_Errors(t *testing.T) {
+	writeConfig := func(t *testing.T, root, content string) {
+		t.Helper()
+		termtileDir := filepath.Join(root, ".termtile")
+		if err := os.MkdirAll(termtileDir, 0o755); err != nil {
+			t.Fatalf("MkdirAll: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(termtileDir, "workspace.yaml"), []byte(content), 0o644); err != nil {
+			t.Fatalf("WriteFile workspace.yaml: %v", err)
+		}
+	}
+
+	t.Run("no binding", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		_, _, _, err := findProjectBinding()
+		if !errors.Is(err, ErrNoProjectBinding) {
+			t.Fatalf("err = %v, want ErrNoProjectBinding", err)
+		}
+	})
+
+	t.Run("config without workspace", func(t *testing.T) {
+		root := t.TempDir()
+		writeConfig(t, root, "project:\n  root_marker: .git\n")
+		t.Chdir(root)
+		_, _, _, err := findProjectBinding()
+		if !errors.Is(err, ErrNoProjectBinding) {
+			t.Fatalf("err = %v, want ErrNoProjectBinding", err)
+		}
+	})
+
+	t.Run("missing root marker", func(t *testing.T) {
+		root := t.TempDir()
+		writeConfig(t, root, "workspace: ws-project\nproject:\n  root_marker: does-not-exist\n")
+		t.Chdir(root)
+		_, _, _, err := findProjectBinding()
+		if !errors.Is(err, ErrProjectRootMarkerMissing) {
+			t.Fatalf("err = %v, want ErrProjectRootMarkerMissing", err)
+		}
+		if errors.Is(err, ErrNoProjectBinding) {
+			t.Fatalf("err = %v also matches ErrNoProjectBinding", err)
+		}
+		if !strings.Contains(err.Error(), "workspace init --force") {
+			t.Fatalf("err = %v, want a workspace init --force hint", err)
+		}
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		root := t.TempDir()
+		writeConfig(t, root, "workspace: [unterminated\n")
+		t.Chdir(root)
+		_, _, _, err := findProjectBinding()
+		if err == nil || errors.Is(err, ErrNoProjectBinding) || errors.Is(err, ErrProjectRootMarkerMissing) {
+			t.Fatalf("err = %v, want a parse error distinct from both sentinels", err)
+		}
+	})
+
+	t.Run("bound", func(t *testing.T) {
+		root := t.TempDir()
+		writeProjectWorkspaceFile(t, root, "ws-project")
+		t.Chdir(root)
+		ws, gotRoot, _, err := findProjectBinding()
+		if err != nil {
+			t.Fatalf("findProjectBinding: %v", err)
+		}
+		if ws != "ws-project" || gotRoot != root {
+			t.Fatalf("binding = (%q, %q), want (ws-project, %q)", ws, gotRoot, root)
+		}
+	})
 }
 
 func TestResolveWorkspaceForSpawn_SingleRegisteredWorkspaceFallback(t *testing.T) {
@@ -214,3 +290,79 @@ func writeProjectWorkspaceFile(t *testing.T, root, workspace string) {
 		t.Fatalf("WriteFile workspace.yaml: %v", err)
 	}
 }
+
+func TestWorkspaceDefaultAgent(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if err := workspacepkg.Write(&workspacepkg.WorkspaceConfig{Name: "ws-default", Layout: "grid", DefaultAgent: "codex"}); err != nil {
+		t.Fatalf("Write ws-default: %v", err)
+	}
+	got, err := workspaceDefaultAgent("ws-default")
+	if err != nil {
+		t.Fatalf("workspaceDefaultAgent returned error: %v", err)
+	}
+	if got != "codex" {
+		t.Fatalf("workspaceDefaultAgent = %q, want %q", got, "codex")
+	}
+
+	if err := workspacepkg.Write(&workspacepkg.WorkspaceConfig{Name: "ws-none", Layout: "grid"}); err != nil {
+		t.Fatalf("Write ws-none: %v", err)
+	}
+	for _, name := range []string{"ws-none", "ws-unsaved"} {
+		if _, err := workspaceDefaultAgent(name); err == nil || !strings.Contains(err.Error(), "agent_type is required") {
+			t.Fatalf("workspaceDefaultAgent(%q) error = %v, want agent_type is required", name, err)
+		}
+	}
+}
+
+func TestWorkspaceSpawnEnv_AgentEnvWins(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if err := workspacepkg.Write(&workspacepkg.WorkspaceConfig{
+		Name:   "ws-env",
+		Layout: "grid",
+		Env:    map[string]string{"API_KEY": "workspace", "REGION": "eu"},
+	}); err != nil {
+		t.Fatalf("Write ws-env: %v", err)
+	}
+
+	agentEnv := map[string]string{"API_KEY": "agent", "DEBUG": "1"}
+	got := workspaceSpawnEnv("ws-env", agentEnv)
+	want := map[string]string{"API_KEY": "agent", "REGION": "eu", "DEBUG": "1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("workspaceSpawnEnv = %v, want %v", got, want)
+	}
+	if len(agentEnv) != 2 || agentEnv["API_KEY"] != "agent" {
+		t.Fatalf("agent env mutated: %v", agentEnv)
+	}
+
+	if got := workspaceSpawnEnv("ws-unsaved", agentEnv); !reflect.DeepEqual(got, agentEnv) {
+		t.Fatalf("workspaceSpawnEnv(unsaved) = %v, want agent env %v", got, agentEnv)
+	}
+}
+
+func TestHandleSpawnAgent_OmittedAgentTypeUsesWorkspaceDefault(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	if err := workspacepkg.SetActiveWorkspace("ws-agents", 1, true, 0, []int{0}); err != nil {
+		t.Fatalf("SetActiveWorkspace: %v", err)
+	}
+	s := &Server{config: config.DefaultConfig()}
+
+	// Neither agent_type nor default_agent: clear error before any spawn.
+	_, _, err := s.handleSpawnAgent(nil, nil, SpawnAgentInput{Workspace: "ws-agents"})
+	if err == nil || !strings.Contains(err.Error(), "agent_type is required") {
+		t.Fatalf("expected agent_type is required error, got %v", err)
+	}
+
+	// The saved default is looked up like an explicit agent_type; an
+	// unconfigured one fails the same way without spawning anything.
+	if err := workspacepkg.Write(&workspacepkg.WorkspaceConfig{Name: "ws-agents", Layout: "grid", DefaultAgent: "ghost"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	_, _, err = s.handleSpawnAgent(nil, nil, SpawnAgentInput{Workspace: "ws-agents"})
+	if err == nil || !strings.Contains(err.Error(), `unknown agent type "ghost"`) {
+		t.Fatalf("expected default agent to be resolved, got %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package movemode
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+
+	"github.com/1broseidon/termtile/internal/config"
+	"github.com/1broseidon/termtile/internal/terminals"
+)
+
+// AnnounceRunner runs the move_mode_announce_command argv.
+type AnnounceRunner func(argv []string) error
+
+// announceQueueSize bounds pending announcements; when the command is slower
+// than key presses, newer descriptions are dropped rather than blocking keys.
+const announceQueueSize = 8
+
+// announceLocked describes the current state through the announce command
+// when move_mode_announce is enabled. Repeated descriptions are skipped so a
+// redraw without a state change stays quiet. Commands run one at a time in
+// order by RunAnnouncements.
+func (m *Mode) announceLocked() {
+	if m.config == nil || !m.config.MoveModeAnnounce || m.announcer == nil {
+		return
+	}
+	text := describeState(m.state)
+	if text == m.lastAnnouncement {
+		return
+	}
+	m.lastAnnouncement = text
+
+	select {
+	case m.announceQueue <- announceArgs(m.config.MoveModeAnnounceCommand, text):
+	default:
+		log.Printf("Move mode: announce queue full, dropping %q", text)
+	}
+}
+
+// RunAnnouncements runs queued announce commands until ctx is cancelled.
+func (m *Mode) RunAnnouncements(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case argv := <-m.announceQueue:
+			if err := m.announcer(argv); err != nil {
+				log.Printf("Move mode: announce command failed: %v", err)
+			}
+		}
+	}
+}
+
+// announceArgs splits the configured command on whitespace and appends the
+// description as a single final argument.
+func announceArgs(command, text string) []string {
+	argv := strings.Fields(command)
+	if len(argv) == 0 {
+		argv = []string{config.DefaultMoveModeAnnounceCommand}
+	}
+	return append(argv, text)
+}
+
+func runAnnounceCommand(argv []string) error {
+	out, err := exec.Command(argv[0], argv[1:]...).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s: %w: %s", argv[0], err, msg)
+		}
+		return fmt.Errorf("%s: %w", argv[0], err)
+	}
+	return nil
+}
+
+// describeState returns a short spoken description of the selection and
+// target slot for the current phase.
+func describeState(s *State) string {
+	noun := "terminal"
+	if s.AllWindows {
+		noun = "window"
+	}
+
+	switch s.Phase {
+	case PhaseInactive:
+		return "Move mode off"
+
+	case PhaseSelecting:
+		term := s.SelectedTerminal()
+		if term == nil {
+			return fmt.Sprintf("Move mode: no %ss", noun)
+		}
+		return fmt.Sprintf("Move mode: %s %d of %d, %s, slot %d",
+			noun, s.SelectedIndex+1, len(s.Terminals), windowLabel(term.Window), term.SlotIdx)
+
+	case PhaseGrabbed:
+		grabbed := s.GrabbedTerminal()
+		if grabbed == nil {
+			return fmt.Sprintf("Moving to slot %d", s.TargetSlotIndex)
+		}
+		label := windowLabel(grabbed.Window)
+		if s.TargetSlotIndex == grabbed.SlotIdx {
+			return fmt.Sprintf("Moving %s, slot %d, current position", label, s.TargetSlotIndex)
+		}
+		if other := s.terminalInSlot(s.TargetSlotIndex); other != nil {
+			return fmt.Sprintf("Moving %s to slot %d, swap with %s", label, s.TargetSlotIndex, windowLabel(other.Window))
+		}
+		return fmt.Sprintf("Moving %s to slot %d, empty", label, s.TargetSlotIndex)
+
+	case PhaseConfirmDelete:
+		if term := s.terminalInSlot(s.PendingSlot); term != nil {
+			return fmt.Sprintf("Delete slot %d, %s? Enter to confirm, Escape to cancel", s.PendingSlot, windowLabel(term.Window))
+		}
+		return fmt.Sprintf("Delete slot %d? Enter to confirm, Escape to cancel", s.PendingSlot)
+	}
+	return ""
+}
+
+// windowLabel names a window by class and title, falling back to its ID.
+func windowLabel(w terminals.TerminalWindow) string {
+	class := strings.TrimSpace(w.Class)
+	title := strings.TrimSpace(w.Title)
+	switch {
+	case class != "" && title != "":
+		return class + " " + title
+	case class != "":
+		return class
+	case title != "":
+		return title
+	default:
+		return fmt.Sprintf("window %d", w.WindowID)
+	}
+}
@@ -0,0 +1,71 @@
+package movemode
+
+import (
+	"testing"
+
+	"github.com/1broseidon/termtile/internal/config"
+	"github.com/1broseidon/termtile/internal/platform"
+	"github.com/1broseidon/termtile/internal/terminals"
+)
+
+type focusBackend struct {
+	platform.Backend
+	active  platform.WindowID
+	focused []platform.WindowID
+}
+
+func (f *focusBackend) ActiveWindow() (platform.WindowID, error) { return f.active, nil }
+func (f *focusBackend) Focus(id platform.WindowID) error {
+	f.focused = append(f.focused, id)
+	f.active = id
+	return nil
+}
+
+func enteredMode(t *testing.T, restore bool) (*Mode, *focusBackend) {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.MoveModeRestoreFocus = restore
+	backend := &focusBackend{active: 12}
+	m := NewMode(backend, terminals.NewDetector([]string{"kitty"}), cfg, nil)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.state.Phase = PhaseSelecting
+	m.state.Terminals = []TerminalSlot{
+		{Window: terminals.TerminalWindow{WindowID: 11}, SlotIdx: 0},
+		{Window: terminals.TerminalWindow{WindowID: 12}, SlotIdx: 1},
+	}
+	m.captureFocusLocked()
+	return m, backend
+}
+
+func TestMoveModeRestoresFocusOnExit(t *testing.T) {
+	m, backend := enteredMode(t, true)
+	if m.state.FocusOnEnter != 12 {
+		t.Fatalf("FocusOnEnter=%d, want 12", m.state.FocusOnEnter)
+	}
+	if m.state.SelectedIndex != 1 {
+		t.Fatalf("SelectedIndex=%d, want the focused terminal preselected", m.state.SelectedIndex)
+	}
+
+	// A swap moves focus to the other window; exit must put it back.
+	backend.active = 11
+	m.Exit()
+
+	if len(backend.focused) != 1 || backend.focused[0] != 12 {
+		t.Fatalf("focused=%v, want [12]", backend.focused)
+	}
+	if m.state.FocusOnEnter != 0 || m.state.Phase != PhaseInactive {
+		t.Fatalf("state not reset after exit: %+v", m.state)
+	}
+}
+
+func TestMoveModeLeavesFocusWhenDisabled(t *testing.T) {
+	m, backend := enteredMode(t, false)
+	backend.active = 11
+	m.Exit()
+
+	if len(backend.focused) != 0 {
+		t.Fatalf("focused=%v, want no refocus when move_mode_restore_focus is off", backend.focused)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	"github.com/1broseidon/termtile/internal/config"
 	"github.com/1broseidon/termtile/internal/platform"
@@ -32,12 +33,6 @@ const (
 	keysymReturn  = 0xff0d
 	keysymEscape  = 0xff1b
 	keysymKPEnter = 0xff8d
-	keysymA       = 0x0041
-	keysyma       = 0x0061
-	keysymD       = 0x0044
-	keysymd       = 0x0064
-	keysymN       = 0x004e
-	keysymn       = 0x006e
 )
 
 // LayoutProvider supplies the currently active layout name.
@@ -59,6 +54,26 @@ type MoveResult struct {
 	TargetSlot int
 	// IsSwap indicates whether two windows were swapped.
 	IsSwap bool
+	// DisplayID is the monitor the move happened on.
+	DisplayID int
+	// Previous holds the geometry of each moved window before the move.
+	Previous map[platform.WindowID]tiling.Rect
+	// KeepSessions tells callbacks not to rename slot sessions: the move
+	// did not map onto a swap of terminal slots (move_mode_all_windows).
+	KeepSessions bool
+}
+
+// Reverse returns the result that moves the windows back: a swap is swapped
+// again and a move to an empty slot goes back to its source slot. Previous
+// is not carried over.
+func (r MoveResult) Reverse() MoveResult {
+	return MoveResult{
+		SourceSlot:   r.TargetSlot,
+		TargetSlot:   r.SourceSlot,
+		IsSwap:       r.IsSwap,
+		DisplayID:    r.DisplayID,
+		KeepSessions: r.KeepSessions,
+	}
 }
 
 type overlayHighlight struct {
@@ -100,6 +115,11 @@ type Mode struct {
 	// OnMoveComplete is called after a successful move/swap operation.
 	OnMoveComplete OnMoveCompleteFunc
 	actionRunner   TerminalActionRunner
+	actionKeys     map[uint32]Action
+
+	announcer        AnnounceRunner
+	announceQueue    chan []string
+	lastAnnouncement string
 }
 
 // NewMode creates a new move mode controller
@@ -117,6 +137,10 @@ func NewMode(backend platform.Backend, detector *terminals.Detector, cfg *config
 		root = accessor.RootWindow()
 	}
 
+	overlay := NewOverlayManager(xu, root)
+	overlay.SetHints(cfg.MoveMode.Hints)
+	overlay.SetActionKeys(cfg.MoveMode.ActionKeys())
+
 	return &Mode{
 		backend:         backend,
 		xu:              xu,
@@ -125,9 +149,12 @@ func NewMode(backend platform.Backend, detector *terminals.Detector, cfg *config
 		config:          cfg,
 		layoutProvider:  layoutProvider,
 		state:           NewState(),
-		overlay:         NewOverlayManager(xu, root),
+		overlay:         overlay,
 		timeoutDuration: time.Duration(timeout) * time.Second,
 		actionRunner:    runTerminalActionViaCLI,
+		actionKeys:      actionKeymap(cfg.MoveMode.ActionKeys()),
+		announcer:       runAnnounceCommand,
+		announceQueue:   make(chan []string, announceQueueSize),
 	}
 }
 
@@ -171,7 +198,7 @@ func (m *Mode) Enter() error {
 	}
 
 	// Apply screen padding (match tiler behavior).
-	padding := m.config.ScreenPadding
+	padding := m.config.GetScreenPadding(display.Name)
 	bounds := display.Bounds
 	bounds.X += padding.Left
 	bounds.Y += padding.Top
@@ -184,8 +211,8 @@ func (m *Mode) Enter() error {
 		)
 	}
 
-	// Find terminals on the current monitor (after padding).
-	terminalWindows, err := m.detector.FindTerminals(m.backend, display.ID, bounds)
+	// Find terminals (or all windows) on the current monitor (after padding).
+	terminalWindows, err := m.findWindows(display.ID, bounds)
 	if err != nil {
 		log.Printf("Move mode: failed to find terminals: %v", err)
 		return err
@@ -221,7 +248,7 @@ func (m *Mode) Enter() error {
 		len(terminalWindows),
 		adjMonitor,
 		layout,
-		m.config.GapSize,
+		m.config.LayoutGapSize(layout),
 	)
 	if err != nil {
 		log.Printf("Move mode: failed to calculate positions: %v", err)
@@ -252,21 +279,16 @@ func (m *Mode) Enter() error {
 	m.state.Phase = PhaseSelecting
 	m.state.Terminals = termSlots
 	m.state.SlotPositions = positions
+	m.state.DisplayID = display.ID
 	m.state.GridRows = rows
 	m.state.GridCols = cols
 	m.state.SelectedIndex = 0
 	m.state.GrabbedWindow = 0
 	m.state.TargetSlotIndex = 0
+	m.state.AllWindows = m.config.MoveModeAllWindows
 	m.state.ClearPendingAction()
 
-	// Find the active window and select it if it's a terminal
-	activeWin, _ := m.backend.ActiveWindow()
-	for i, ts := range termSlots {
-		if ts.Window.WindowID == activeWin {
-			m.state.SelectedIndex = i
-			break
-		}
-	}
+	m.captureFocusLocked()
 
 	// Grab keyboard for navigation
 	if err := m.grabKeyboard(); err != nil {
@@ -285,6 +307,15 @@ func (m *Mode) Enter() error {
 	return nil
 }
 
+// findWindows lists the windows move mode arranges: detected terminals, or
+// every normal window when move_mode_all_windows is set.
+func (m *Mode) findWindows(displayID int, bounds platform.Rect) ([]terminals.TerminalWindow, error) {
+	if m.config.MoveModeAllWindows {
+		return m.detector.FindWindows(m.backend, displayID, bounds)
+	}
+	return m.detector.FindTerminals(m.backend, displayID, bounds)
+}
+
 // Exit deactivates move mode
 func (m *Mode) Exit() {
 	m.mu.Lock()
@@ -312,8 +343,36 @@ func (m *Mode) exitLocked() {
 	// Hide all overlays
 	m.overlay.HideAll()
 
+	m.restoreFocusLocked()
+
 	// Reset state
 	m.state.Reset()
+	m.announceLocked()
+	m.lastAnnouncement = ""
+}
+
+// captureFocusLocked records the active window so exit can restore it, and
+// preselects it when it is one of the movable terminals.
+func (m *Mode) captureFocusLocked() {
+	activeWin, _ := m.backend.ActiveWindow()
+	m.state.FocusOnEnter = activeWin
+	for i, ts := range m.state.Terminals {
+		if ts.Window.WindowID == activeWin {
+			m.state.SelectedIndex = i
+			break
+		}
+	}
+}
+
+// restoreFocusLocked refocuses the window that was active when move mode was
+// entered, if move_mode_restore_focus is enabled.
+func (m *Mode) restoreFocusLocked() {
+	if !m.config.MoveModeRestoreFocus || m.state.FocusOnEnter == 0 {
+		return
+	}
+	if err := m.backend.Focus(m.state.FocusOnEnter); err != nil {
+		log.Printf("Move mode: failed to restore focus to window %d: %v", m.state.FocusOnEnter, err)
+	}
 }
 
 // HandleArrowKey processes an arrow key press
@@ -432,22 +491,72 @@ func (m *Mode) executeMove() {
 		targetSlot := m.state.TargetSlotIndex
 		isSwap := targetTermIdx >= 0 && targetTermIdx != grabbedTermIdx
 
+		previous := map[platform.WindowID]tiling.Rect{
+			grabbedTerm.Window.WindowID: windowRect(grabbedTerm.Window),
+		}
+		if isSwap {
+			other := m.state.Terminals[targetTermIdx].Window
+			previous[other.WindowID] = windowRect(other)
+		}
+
 		// Call callback without holding the lock (callback may need to do I/O)
 		result := MoveResult{
 			SourceSlot: sourceSlot,
 			TargetSlot: targetSlot,
 			IsSwap:     isSwap,
+			DisplayID:  m.state.DisplayID,
+			Previous:   previous,
+		}
+		if m.state.AllWindows {
+			m.mapTerminalSlots(&result, grabbedTermIdx, targetTermIdx)
 		}
 		go m.OnMoveComplete(result)
 	}
 }
 
+// mapTerminalSlots rewrites an all-windows result in terminal slot indices,
+// which is what session and artifact names follow. Only a swap of two
+// terminals maps onto such a swap; any other move keeps the sessions.
+func (m *Mode) mapTerminalSlots(result *MoveResult, grabbedIdx, targetIdx int) {
+	result.KeepSessions = true
+	if !result.IsSwap {
+		return
+	}
+	grabbed := m.state.Terminals[grabbedIdx]
+	other := m.state.Terminals[targetIdx]
+	if !m.detector.IsTerminal(grabbed.Window) || !m.detector.IsTerminal(other.Window) {
+		log.Printf("Move mode: swap with a non-terminal window; keeping slot sessions")
+		return
+	}
+	result.SourceSlot = m.terminalSlot(grabbed.SlotIdx)
+	result.TargetSlot = m.terminalSlot(other.SlotIdx)
+	result.KeepSessions = false
+}
+
+// terminalSlot returns the index slotIdx has among the slots held by
+// terminals, skipping the slots of other windows.
+func (m *Mode) terminalSlot(slotIdx int) int {
+	n := 0
+	for _, t := range m.state.Terminals {
+		if t.SlotIdx < slotIdx && m.detector.IsTerminal(t.Window) {
+			n++
+		}
+	}
+	return n
+}
+
+// windowRect returns the geometry w had when move mode was entered.
+func windowRect(w terminals.TerminalWindow) tiling.Rect {
+	return tiling.Rect{X: w.X, Y: w.Y, Width: w.Width, Height: w.Height}
+}
+
 // updateOverlays updates the visual overlays based on current state
 func (m *Mode) updateOverlays() {
 	if m.state.Phase == PhaseInactive {
 		m.overlay.HideAll()
 		return
 	}
+	m.announceLocked()
 
 	model, ok := m.buildRenderModel()
 	if !ok {
@@ -635,46 +744,11 @@ func (m *Mode) calculateGridDimensions(termCount int, layout *config.Layout) (ro
 }
 
 func sortTerminals(backend platform.Backend, windows []terminals.TerminalWindow, mode string) {
-	switch mode {
-	case "client_list":
-		return
-	case "window_id":
-		sort.Slice(windows, func(i, j int) bool {
-			return windows[i].WindowID < windows[j].WindowID
-		})
-	case "active_first":
-		activeWin, _ := backend.ActiveWindow()
-		sort.SliceStable(windows, func(i, j int) bool {
-			wi, wj := windows[i], windows[j]
-			if activeWin != 0 {
-				if wi.WindowID == activeWin && wj.WindowID != activeWin {
-					return true
-				}
-				if wj.WindowID == activeWin && wi.WindowID != activeWin {
-					return false
-				}
-			}
-
-			if wi.Y != wj.Y {
-				return wi.Y < wj.Y
-			}
-			if wi.X != wj.X {
-				return wi.X < wj.X
-			}
-			return wi.WindowID < wj.WindowID
-		})
-	default:
-		sort.Slice(windows, func(i, j int) bool {
-			wi, wj := windows[i], windows[j]
-			if wi.Y != wj.Y {
-				return wi.Y < wj.Y
-			}
-			if wi.X != wj.X {
-				return wi.X < wj.X
-			}
-			return wi.WindowID < wj.WindowID
-		})
+	var activeWin platform.WindowID
+	if mode == "active_first" {
+		activeWin, _ = backend.ActiveWindow()
 	}
+	terminals.SortWindows(windows, mode, activeWin, terminals.TerminalWindow.SortKey)
 }
 
 func assignTerminalsToSlots(windows []terminals.TerminalWindow, slots []tiling.Rect) []int {
@@ -760,6 +834,9 @@ func (m *Mode) UpdateConfig(cfg *config.Config) {
 	defer m.mu.Unlock()
 
 	m.config = cfg
+	m.overlay.SetHints(cfg.MoveMode.Hints)
+	m.overlay.SetActionKeys(cfg.MoveMode.ActionKeys())
+	m.actionKeys = actionKeymap(cfg.MoveMode.ActionKeys())
 
 	timeout := DefaultTimeout
 	if cfg.MoveModeTimeout > 0 {
@@ -823,6 +900,9 @@ func (m *Mode) grabKeyboard() error {
 // ungrabKeyboard releases the keyboard grab
 func (m *Mode) ungrabKeyboard() {
 	xu := m.xu
+	if xu == nil {
+		return
+	}
 
 	// Ungrab the keyboard
 	xproto.UngrabKeyboard(xu.Conn(), xproto.TimeCurrentTime)
@@ -878,8 +958,13 @@ func (m *Mode) ensureGrabWindow() error {
 
 // handleKeyPress processes key events while keyboard is grabbed
 func (m *Mode) handleKeyPress(xu *xgbutil.XUtil, ev xevent.KeyPressEvent) {
-	// Look up the keysym for this keycode
-	keysym := keybind.KeysymGet(xu, ev.Detail, 0)
+	// Look up the keysym for this keycode in the column the modifiers
+	// select, so shifted bindings such as "!" or ":" fire. Keys with
+	// nothing in the shifted column fall back to the unshifted one.
+	keysym := keybind.KeysymGet(xu, ev.Detail, keysymColumn(ev.State))
+	if keysym == 0 {
+		keysym = keybind.KeysymGet(xu, ev.Detail, 0)
+	}
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -902,7 +987,7 @@ func (m *Mode) handleKeyPress(xu *xgbutil.XUtil, ev xevent.KeyPressEvent) {
 	case keysymEscape:
 		m.handleCancelLocked()
 	default:
-		if action, ok := actionFromKeysym(uint32(keysym)); ok {
+		if action, ok := actionFromKeysym(uint32(keysym), m.actionKeys); ok {
 			m.handleActionKeyLocked(action)
 		}
 	}
@@ -994,6 +1079,12 @@ func (m *Mode) handleActionKeyLocked(action Action) {
 	if m.state.Phase == PhaseConfirmDelete && action != ActionDeleteSelected {
 		return
 	}
+	// Slot indices include non-terminal windows, so they do not name
+	// workspace terminal slots.
+	if m.state.AllWindows && action != ActionAppend {
+		log.Printf("Move mode: %s is unavailable with move_mode_all_windows", action)
+		return
+	}
 
 	switch action {
 	case ActionDeleteSelected:
@@ -1049,17 +1140,47 @@ func (m *Mode) executeTerminalActionLocked(action Action, selectedSlot int) {
 	}(action, append([]string(nil), args...))
 }
 
-func actionFromKeysym(keysym uint32) (Action, bool) {
-	switch keysym {
-	case keysymD, keysymd:
-		return ActionDeleteSelected, true
-	case keysymN, keysymn:
-		return ActionInsertAfterSelected, true
-	case keysymA, keysyma:
-		return ActionAppend, true
-	default:
-		return ActionNone, false
+// actionsByName maps move_mode.keys action names to their actions.
+var actionsByName = map[string]Action{
+	config.MoveModeActionDelete: ActionDeleteSelected,
+	config.MoveModeActionInsert: ActionInsertAfterSelected,
+	config.MoveModeActionAppend: ActionAppend,
+}
+
+// keysymColumn returns the keysym column of a key press with modifier state:
+// the shifted column while Shift is held.
+func keysymColumn(state uint16) byte {
+	if state&xproto.ModMaskShift != 0 {
+		return 1
+	}
+	return 0
+}
+
+// actionKeymap maps the keysyms of the configured action keys to their
+// actions. Letters match in either case; an ASCII keysym equals its code
+// point.
+func actionKeymap(keys map[string]string) map[uint32]Action {
+	out := make(map[uint32]Action, 2*len(keys))
+	for name, key := range keys {
+		action, ok := actionsByName[name]
+		if !ok {
+			continue
+		}
+		r, err := config.ParseMoveModeKey(key)
+		if err != nil {
+			continue
+		}
+		out[uint32(r)] = action
+		out[uint32(unicode.ToUpper(r))] = action
+	}
+	return out
+}
+
+func actionFromKeysym(keysym uint32, keymap map[uint32]Action) (Action, bool) {
+	if action, ok := keymap[keysym]; ok {
+		return action, true
 	}
+	return ActionNone, false
 }
 
 func terminalActionArgs(action Action, selectedSlot int) ([]string, error) {
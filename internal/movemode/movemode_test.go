@@ -0,0 +1,273 @@
+package movemode
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1broseidon/termtile/internal/config"
+	"github.com/1broseidon/termtile/internal/platform"
+	"github.com/1broseidon/termtile/internal/terminals"
+	"github.com/1broseidon/termtile/internal/tiling"
+)
+
+type windowListBackend struct {
+	platform.Backend
+	windows []platform.Window
+}
+
+func (b *windowListBackend) ListWindowsOnDisplay(int) ([]platform.Window, error) {
+	return b.windows, nil
+}
+
+func TestFindWindows_AllWindowsToggle(t *testing.T) {
+	backend := &windowListBackend{windows: []platform.Window{
+		{ID: 1, AppID: "kitty", Bounds: platform.Rect{X: 0, Y: 0, Width: 400, Height: 300}},
+		{ID: 2, AppID: "firefox", Bounds: platform.Rect{X: 400, Y: 0, Width: 400, Height: 300}},
+		{ID: 3, AppID: "Code", Bounds: platform.Rect{X: 0, Y: 300, Width: 400, Height: 300}},
+		// Centered on another monitor; never listed.
+		{ID: 4, AppID: "firefox", Bounds: platform.Rect{X: 2000, Y: 0, Width: 400, Height: 300}},
+	}}
+	bounds := platform.Rect{X: 0, Y: 0, Width: 1000, Height: 800}
+
+	tests := []struct {
+		allWindows bool
+		want       []platform.WindowID
+	}{
+		{allWindows: false, want: []platform.WindowID{1}},
+		{allWindows: true, want: []platform.WindowID{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		cfg := config.DefaultConfig()
+		cfg.MoveModeAllWindows = tt.allWindows
+		m := NewMode(backend, terminals.NewDetector([]string{"kitty"}), cfg, nil)
+
+		got, err := m.findWindows(0, bounds)
+		if err != nil {
+			t.Fatalf("allWindows=%v: findWindows error = %v", tt.allWindows, err)
+		}
+		if len(got) != len(tt.want) {
+			t.Fatalf("allWindows=%v: got %d windows %+v, want %v", tt.allWindows, len(got), got, tt.want)
+		}
+		for i, w := range got {
+			if w.WindowID != tt.want[i] {
+				t.Fatalf("allWindows=%v: window %d = %d, want %d", tt.allWindows, i, w.WindowID, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestAllWindowsDisablesSlotActions(t *testing.T) {
+	m, _ := enteredMode(t, false)
+	var ran [][]string
+	m.actionRunner = func(args []string) error {
+		ran = append(ran, args)
+		return nil
+	}
+
+	m.mu.Lock()
+	m.state.AllWindows = true
+	m.handleActionKeyLocked(ActionDeleteSelected)
+	m.handleActionKeyLocked(ActionInsertAfterSelected)
+	phase := m.state.Phase
+	m.mu.Unlock()
+
+	if phase != PhaseSelecting {
+		t.Fatalf("phase = %v, want selecting after disabled actions", phase)
+	}
+	if len(ran) != 0 {
+		t.Fatalf("actions ran %v, want none", ran)
+	}
+}
+
+func TestMoveResultReverse(t *testing.T) {
+	swap := MoveResult{SourceSlot: 0, TargetSlot: 2, IsSwap: true, DisplayID: 1}
+	if got := swap.Reverse(); got.SourceSlot != 2 || got.TargetSlot != 0 || !got.IsSwap || got.DisplayID != 1 {
+		t.Fatalf("swap reverse = %+v", got)
+	}
+
+	move := MoveResult{SourceSlot: 1, TargetSlot: 3}
+	if got := move.Reverse(); got.SourceSlot != 3 || got.TargetSlot != 1 || got.IsSwap {
+		t.Fatalf("move reverse = %+v", got)
+	}
+}
+
+type moveBackend struct {
+	platform.Backend
+}
+
+func (moveBackend) MoveResize(platform.WindowID, platform.Rect) error { return nil }
+
+func TestExecuteMove_AllWindowsMapsTerminalSlots(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     int
+		wantResult MoveResult
+	}{
+		// A browser holds slot 0, so the terminals hold terminal slots 0 and 1.
+		{name: "terminal swap", target: 2, wantResult: MoveResult{SourceSlot: 0, TargetSlot: 1, IsSwap: true}},
+		{name: "swap with browser", target: 0, wantResult: MoveResult{SourceSlot: 1, TargetSlot: 0, IsSwap: true, KeepSessions: true}},
+		{name: "move to empty slot", target: 3, wantResult: MoveResult{SourceSlot: 1, TargetSlot: 3, KeepSessions: true}},
+	}
+	for _, tt := range tests {
+		m := NewMode(moveBackend{}, terminals.NewDetector([]string{"kitty"}), config.DefaultConfig(), nil)
+		results := make(chan MoveResult, 1)
+		m.OnMoveComplete = func(r MoveResult) { results <- r }
+
+		m.state.Phase = PhaseGrabbed
+		m.state.AllWindows = true
+		m.state.SlotPositions = make([]tiling.Rect, 4)
+		m.state.Terminals = []TerminalSlot{
+			{Window: terminals.TerminalWindow{WindowID: 10, Class: "firefox"}, SlotIdx: 0},
+			{Window: terminals.TerminalWindow{WindowID: 11, Class: "kitty"}, SlotIdx: 1},
+			{Window: terminals.TerminalWindow{WindowID: 12, Class: "kitty"}, SlotIdx: 2},
+		}
+		m.state.GrabbedWindow = 11
+		m.state.TargetSlotIndex = tt.target
+		m.executeMove()
+
+		got := <-results
+		want := tt.wantResult
+		if got.SourceSlot != want.SourceSlot || got.TargetSlot != want.TargetSlot ||
+			got.IsSwap != want.IsSwap || got.KeepSessions != want.KeepSessions {
+			t.Fatalf("%s: result = %+v, want %+v", tt.name, got, want)
+		}
+	}
+}
+
+func TestDescribeState(t *testing.T) {
+	terms := []TerminalSlot{
+		{Window: terminals.TerminalWindow{WindowID: 11, Class: "kitty", Title: "vim"}, SlotIdx: 0},
+		{Window: terminals.TerminalWindow{WindowID: 12, Class: "Alacritty", Title: "htop"}, SlotIdx: 1},
+		{Window: terminals.TerminalWindow{WindowID: 13}, SlotIdx: 2},
+	}
+	tests := []struct {
+		name  string
+		state State
+		want  string
+	}{
+		{
+			name:  "inactive",
+			state: State{Phase: PhaseInactive},
+			want:  "Move mode off",
+		},
+		{
+			name:  "selecting",
+			state: State{Phase: PhaseSelecting, SelectedIndex: 1, Terminals: terms},
+			want:  "Move mode: terminal 2 of 3, Alacritty htop, slot 1",
+		},
+		{
+			name:  "selecting all windows",
+			state: State{Phase: PhaseSelecting, SelectedIndex: 2, Terminals: terms, AllWindows: true},
+			want:  "Move mode: window 3 of 3, window 13, slot 2",
+		},
+		{
+			name:  "selecting empty",
+			state: State{Phase: PhaseSelecting},
+			want:  "Move mode: no terminals",
+		},
+		{
+			name:  "grabbed current slot",
+			state: State{Phase: PhaseGrabbed, GrabbedWindow: 11, TargetSlotIndex: 0, Terminals: terms},
+			want:  "Moving kitty vim, slot 0, current position",
+		},
+		{
+			name:  "grabbed swap",
+			state: State{Phase: PhaseGrabbed, GrabbedWindow: 11, TargetSlotIndex: 1, Terminals: terms},
+			want:  "Moving kitty vim to slot 1, swap with Alacritty htop",
+		},
+		{
+			name:  "grabbed empty slot",
+			state: State{Phase: PhaseGrabbed, GrabbedWindow: 11, TargetSlotIndex: 3, Terminals: terms},
+			want:  "Moving kitty vim to slot 3, empty",
+		},
+		{
+			name:  "confirm delete",
+			state: State{Phase: PhaseConfirmDelete, PendingAction: ActionDeleteSelected, PendingSlot: 1, Terminals: terms},
+			want:  "Delete slot 1, Alacritty htop? Enter to confirm, Escape to cancel",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describeState(&tt.state); got != tt.want {
+				t.Fatalf("describeState() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoveModeAnnouncesTransitions(t *testing.T) {
+	m, _ := enteredMode(t, false)
+	got := make(chan []string, 16)
+	m.mu.Lock()
+	m.config.MoveModeAnnounce = true
+	m.config.MoveModeAnnounceCommand = "notify-send -t 1000"
+	m.announcer = func(argv []string) error {
+		got <- argv
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.RunAnnouncements(ctx)
+
+	m.updateOverlays()
+	m.updateOverlays() // unchanged state is not repeated
+	m.handleConfirmLocked()
+	m.handleCancelLocked()
+	m.mu.Unlock()
+
+	want := []string{
+		"Move mode: terminal 2 of 2, window 12, slot 1",
+		"Moving window 12, slot 1, current position",
+		"Move mode off",
+	}
+	for i, text := range want {
+		select {
+		case argv := <-got:
+			wantArgv := []string{"notify-send", "-t", "1000", text}
+			if strings.Join(argv, "|") != strings.Join(wantArgv, "|") {
+				t.Fatalf("announcement %d = %q, want %q", i, argv, wantArgv)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("announcement %d (%q) not run", i, text)
+		}
+	}
+	select {
+	case argv := <-got:
+		t.Fatalf("unexpected extra announcement %q", argv)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestRunAnnouncementsStopsOnCancel(t *testing.T) {
+	m, _ := enteredMode(t, false)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		m.RunAnnouncements(ctx)
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("RunAnnouncements did not return after cancel")
+	}
+}
+
+func TestMoveModeAnnounceDisabled(t *testing.T) {
+	m, _ := enteredMode(t, false)
+	ran := false
+	m.mu.Lock()
+	m.announcer = func([]string) error {
+		ran = true
+		return nil
+	}
+	m.updateOverlays()
+	m.mu.Unlock()
+	if len(m.announceQueue) != 0 || ran {
+		t.Fatal("announcer used while move_mode_announce is disabled")
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/1broseidon/termtile/internal/config"
 	"github.com/1broseidon/termtile/internal/tiling"
+	"github.com/BurntSushi/xgb/xproto"
 )
 
 func TestNavigateSlot_UniformGrid(t *testing.T) {
@@ -234,18 +236,18 @@ func TestActionFromKeysym(t *testing.T) {
 		want   Action
 		ok     bool
 	}{
-		{name: "d", keysym: keysymd, want: ActionDeleteSelected, ok: true},
-		{name: "D", keysym: keysymD, want: ActionDeleteSelected, ok: true},
-		{name: "n", keysym: keysymn, want: ActionInsertAfterSelected, ok: true},
-		{name: "N", keysym: keysymN, want: ActionInsertAfterSelected, ok: true},
-		{name: "a", keysym: keysyma, want: ActionAppend, ok: true},
-		{name: "A", keysym: keysymA, want: ActionAppend, ok: true},
+		{name: "d", keysym: 'd', want: ActionDeleteSelected, ok: true},
+		{name: "D", keysym: 'D', want: ActionDeleteSelected, ok: true},
+		{name: "n", keysym: 'n', want: ActionInsertAfterSelected, ok: true},
+		{name: "N", keysym: 'N', want: ActionInsertAfterSelected, ok: true},
+		{name: "a", keysym: 'a', want: ActionAppend, ok: true},
+		{name: "A", keysym: 'A', want: ActionAppend, ok: true},
 		{name: "unsupported", keysym: keysymRight, want: ActionNone, ok: false},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, ok := actionFromKeysym(tt.keysym)
+			got, ok := actionFromKeysym(tt.keysym, actionKeymap(config.MoveModeConfig{}.ActionKeys()))
 			if ok != tt.ok {
 				t.Fatalf("ok = %v, want %v", ok, tt.ok)
 			}
@@ -256,6 +258,65 @@ func TestActionFromKeysym(t *testing.T) {
 	}
 }
 
+func TestActionFromKeysym_CustomKeys(t *testing.T) {
+	keymap := actionKeymap(config.MoveModeConfig{Keys: map[string]string{
+		"delete": "x",
+		"append": ";",
+	}}.ActionKeys())
+
+	tests := []struct {
+		name   string
+		keysym uint32
+		want   Action
+		ok     bool
+	}{
+		{name: "x", keysym: 'x', want: ActionDeleteSelected, ok: true},
+		{name: "X", keysym: 'X', want: ActionDeleteSelected, ok: true},
+		{name: "semicolon", keysym: ';', want: ActionAppend, ok: true},
+		{name: "default insert kept", keysym: 'n', want: ActionInsertAfterSelected, ok: true},
+		{name: "old delete unbound", keysym: 'd', want: ActionNone, ok: false},
+		{name: "old append unbound", keysym: 'a', want: ActionNone, ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := actionFromKeysym(tt.keysym, keymap)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if got != tt.want {
+				t.Fatalf("action = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActionFromKeysym_ShiftedCustomKey(t *testing.T) {
+	keymap := actionKeymap(config.MoveModeConfig{Keys: map[string]string{
+		"insert": "!",
+	}}.ActionKeys())
+
+	if got, ok := actionFromKeysym('!', keymap); !ok || got != ActionInsertAfterSelected {
+		t.Fatalf("'!' = %v, %v; want insert", got, ok)
+	}
+	if got, ok := actionFromKeysym('n', keymap); ok {
+		t.Fatalf("old insert key 'n' = %v, want unbound", got)
+	}
+}
+
+func TestKeysymColumn(t *testing.T) {
+	if got := keysymColumn(0); got != 0 {
+		t.Fatalf("unshifted column = %d, want 0", got)
+	}
+	// Shift+1 must be looked up in the shifted column to produce "!".
+	if got := keysymColumn(xproto.ModMaskShift); got != 1 {
+		t.Fatalf("shifted column = %d, want 1", got)
+	}
+	if got := keysymColumn(xproto.ModMaskShift | xproto.ModMask2); got != 1 {
+		t.Fatalf("shifted column with NumLock = %d, want 1", got)
+	}
+}
+
 func TestTerminalActionArgs(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -3,6 +3,7 @@ package movemode
 import (
 	"fmt"
 
+	"github.com/1broseidon/termtile/internal/config"
 	"github.com/1broseidon/termtile/internal/tiling"
 	"github.com/BurntSushi/xgb/xproto"
 	"github.com/BurntSushi/xgbutil"
@@ -68,6 +69,8 @@ type OverlayManager struct {
 	terminalBorders []*BorderOverlay // Borders around terminal windows (decorated rects)
 	slotBorders     []*BorderOverlay // Borders around every grid slot (preview)
 	hint            *hintOverlay     // Text legend for move-mode shortcuts
+	hints           config.MoveModeHints
+	actionKeys      map[string]string // Action name -> key, for the built-in legend
 }
 
 // NewOverlayManager creates a new overlay manager
@@ -81,10 +84,23 @@ func NewOverlayManager(xu *xgbutil.XUtil, root xproto.Window) *OverlayManager {
 	}
 }
 
+// SetHints sets the hint text overrides and placement used by Render.
+func (m *OverlayManager) SetHints(hints config.MoveModeHints) {
+	m.hints = hints
+}
+
+// SetActionKeys sets the action keys shown in the built-in selecting legend.
+func (m *OverlayManager) SetActionKeys(keys map[string]string) {
+	m.actionKeys = keys
+}
+
 // Render draws borders for all terminals and all grid slots.
 //
 // Slots are rendered first and terminals after, so terminal borders appear on top.
 func (m *OverlayManager) Render(terminalRects []tiling.Rect, terminalColors []uint32, slotRects []tiling.Rect, slotColors []uint32, allSlotRects []tiling.Rect, hintPhase HintPhase) error {
+	if m.xu == nil {
+		return fmt.Errorf("no X connection")
+	}
 	if len(terminalRects) != len(terminalColors) {
 		return fmt.Errorf("terminal rect/color length mismatch")
 	}
@@ -332,7 +348,7 @@ func (m *OverlayManager) updateWindow(wid xproto.Window, x, y, width, height int
 }
 
 func (m *OverlayManager) renderHint(phase HintPhase, allSlotRects []tiling.Rect, avoidRects []tiling.Rect) {
-	lines := hintLinesForPhase(phase)
+	lines := hintLinesForPhase(phase, m.hints, m.actionKeys)
 	if len(lines) == 0 {
 		m.hideHint()
 		return
@@ -383,7 +399,7 @@ func (m *OverlayManager) renderHint(phase HintPhase, allSlotRects []tiling.Rect,
 		height = 1
 	}
 
-	x, y := chooseHintPosition(bounds, avoidRects, width, height)
+	x, y := placeHint(m.hints.Position, bounds, avoidRects, width, height)
 
 	xproto.ConfigureWindow(
 		conn,
@@ -549,19 +565,31 @@ func (m *OverlayManager) destroyHint() {
 	m.hint.mapped = false
 }
 
-func hintLinesForPhase(phase HintPhase) []string {
+// hintLinesForPhase returns the legend for phase, preferring the configured
+// lines over the built-in ones. keys supplies the action keys for the
+// built-in selecting legend; nil shows the defaults.
+func hintLinesForPhase(phase HintPhase, hints config.MoveModeHints, keys map[string]string) []string {
 	switch phase {
 	case HintPhaseSelecting:
+		if len(hints.Selecting) > 0 {
+			return hints.Selecting
+		}
+		if keys == nil {
+			keys = config.MoveModeConfig{}.ActionKeys()
+		}
 		return []string{
 			"Move Mode: select terminal",
 			"Arrows  cycle terminals",
 			"Enter   grab selected",
-			"d       delete selected",
-			"n       add after selected",
-			"a       append terminal",
+			fmt.Sprintf("%-8sdelete selected", keys[config.MoveModeActionDelete]),
+			fmt.Sprintf("%-8sadd after selected", keys[config.MoveModeActionInsert]),
+			fmt.Sprintf("%-8sappend terminal", keys[config.MoveModeActionAppend]),
 			"Esc     cancel",
 		}
 	case HintPhaseMove:
+		if len(hints.Move) > 0 {
+			return hints.Move
+		}
 		return []string{
 			"Move Mode: choose target slot",
 			"Arrows  select target slot",
@@ -569,6 +597,9 @@ func hintLinesForPhase(phase HintPhase) []string {
 			"Esc     cancel",
 		}
 	case HintPhaseConfirmDelete:
+		if len(hints.ConfirmDelete) > 0 {
+			return hints.ConfirmDelete
+		}
 		return []string{
 			"Move Mode: confirm delete",
 			"Enter   delete terminal",
@@ -610,6 +641,23 @@ func (m *OverlayManager) resolveHintBounds(allSlotRects []tiling.Rect) tiling.Re
 	}
 }
 
+// placeHint returns the hint origin for the configured position. "auto" (and
+// any unknown value) picks the first corner clear of avoidRects; the fixed
+// positions center the hint horizontally.
+func placeHint(position string, bounds tiling.Rect, avoidRects []tiling.Rect, width, height int) (int, int) {
+	x := bounds.X + (bounds.Width-width)/2
+	switch position {
+	case config.HintPositionTop:
+		return clampHintOrigin(x, bounds.Y+hintMargin, bounds, width, height)
+	case config.HintPositionBottom:
+		return clampHintOrigin(x, bounds.Y+bounds.Height-hintMargin-height, bounds, width, height)
+	case config.HintPositionCenter:
+		return clampHintOrigin(x, bounds.Y+(bounds.Height-height)/2, bounds, width, height)
+	default:
+		return chooseHintPosition(bounds, avoidRects, width, height)
+	}
+}
+
 func chooseHintPosition(bounds tiling.Rect, avoidRects []tiling.Rect, width, height int) (int, int) {
 	if width < 1 {
 		width = 1
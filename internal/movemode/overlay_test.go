@@ -4,11 +4,12 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/1broseidon/termtile/internal/config"
 	"github.com/1broseidon/termtile/internal/tiling"
 )
 
 func TestHintLinesForPhaseSelectingIncludesActionKeys(t *testing.T) {
-	lines := hintLinesForPhase(HintPhaseSelecting)
+	lines := hintLinesForPhase(HintPhaseSelecting, config.MoveModeHints{}, nil)
 	text := strings.Join(lines, "\n")
 
 	expected := []string{
@@ -23,6 +24,18 @@ func TestHintLinesForPhaseSelectingIncludesActionKeys(t *testing.T) {
 	}
 }
 
+func TestHintLinesForPhaseSelectingShowsCustomKeys(t *testing.T) {
+	keys := config.MoveModeConfig{Keys: map[string]string{"delete": "x"}}.ActionKeys()
+	text := strings.Join(hintLinesForPhase(HintPhaseSelecting, config.MoveModeHints{}, keys), "\n")
+
+	if !strings.Contains(text, "x       delete selected") {
+		t.Fatalf("selecting hint missing custom delete key; got:\n%s", text)
+	}
+	if !strings.Contains(text, "n       add after selected") {
+		t.Fatalf("selecting hint lost default insert key; got:\n%s", text)
+	}
+}
+
 func TestChooseHintPositionAvoidsOverlapWhenPossible(t *testing.T) {
 	bounds := tiling.Rect{X: 0, Y: 0, Width: 800, Height: 600}
 	width, height := 220, 80
@@ -51,3 +64,48 @@ func TestChooseHintPositionClampsOversizedHintToBoundsOrigin(t *testing.T) {
 		t.Fatalf("expected oversized hint to clamp to bounds origin (%d,%d), got (%d,%d)", bounds.X, bounds.Y, x, y)
 	}
 }
+
+func TestHintLinesForPhaseUsesConfiguredLines(t *testing.T) {
+	hints := config.MoveModeHints{Move: []string{"pick a slot"}}
+
+	if got := hintLinesForPhase(HintPhaseMove, hints, nil); len(got) != 1 || got[0] != "pick a slot" {
+		t.Fatalf("move hint = %q, want configured line", got)
+	}
+	// Phases without overrides keep the built-in legend.
+	if got := hintLinesForPhase(HintPhaseConfirmDelete, hints, nil); len(got) == 0 || got[0] != "Move Mode: confirm delete" {
+		t.Fatalf("confirm delete hint = %q, want built-in legend", got)
+	}
+}
+
+func TestPlaceHintPositions(t *testing.T) {
+	bounds := tiling.Rect{X: 100, Y: 50, Width: 800, Height: 600}
+	width, height := 220, 80
+	avoid := []tiling.Rect{{X: 668, Y: 62, Width: 220, Height: 80}}
+
+	tests := []struct {
+		position string
+		wantX    int
+		wantY    int
+	}{
+		{position: config.HintPositionTop, wantX: 390, wantY: 62},
+		{position: config.HintPositionBottom, wantX: 390, wantY: 558},
+		{position: config.HintPositionCenter, wantX: 390, wantY: 310},
+		// auto skips the occupied top-right corner for top-left.
+		{position: config.HintPositionAuto, wantX: 112, wantY: 62},
+	}
+	for _, tt := range tests {
+		x, y := placeHint(tt.position, bounds, avoid, width, height)
+		if x != tt.wantX || y != tt.wantY {
+			t.Errorf("placeHint(%q) = (%d,%d), want (%d,%d)", tt.position, x, y, tt.wantX, tt.wantY)
+		}
+	}
+}
+
+func TestPlaceHintClampsOversizedHint(t *testing.T) {
+	bounds := tiling.Rect{X: 100, Y: 200, Width: 140, Height: 90}
+	for _, position := range []string{config.HintPositionTop, config.HintPositionBottom, config.HintPositionCenter} {
+		if x, y := placeHint(position, bounds, nil, 260, 160); x != bounds.X || y != bounds.Y {
+			t.Errorf("placeHint(%q) = (%d,%d), want bounds origin (%d,%d)", position, x, y, bounds.X, bounds.Y)
+		}
+	}
+}
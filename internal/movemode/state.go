@@ -95,6 +95,9 @@ type State struct {
 	SlotPositions   []tiling.Rect     // Grid slot geometries
 	GridRows        int               // Number of rows in the grid
 	GridCols        int               // Number of columns in the grid
+	FocusOnEnter    platform.WindowID // Window focused when move mode was entered (0 if unknown)
+	AllWindows      bool              // Terminals holds every window, not only terminals (move_mode_all_windows)
+	DisplayID       int               // Monitor move mode was entered on
 }
 
 // NewState creates a new inactive state
@@ -123,6 +126,9 @@ func (s *State) Reset() {
 	s.SlotPositions = nil
 	s.GridRows = 0
 	s.GridCols = 0
+	s.FocusOnEnter = 0
+	s.AllWindows = false
+	s.DisplayID = 0
 }
 
 // BeginDeleteConfirmation transitions state into delete-confirm mode.
@@ -153,3 +159,23 @@ func (s *State) TargetSlotRect() *tiling.Rect {
 	}
 	return &s.SlotPositions[s.TargetSlotIndex]
 }
+
+// GrabbedTerminal returns the grabbed terminal, or nil if none
+func (s *State) GrabbedTerminal() *TerminalSlot {
+	for i := range s.Terminals {
+		if s.Terminals[i].Window.WindowID == s.GrabbedWindow {
+			return &s.Terminals[i]
+		}
+	}
+	return nil
+}
+
+// terminalInSlot returns the terminal assigned to slot, or nil.
+func (s *State) terminalInSlot(slot int) *TerminalSlot {
+	for i := range s.Terminals {
+		if s.Terminals[i].SlotIdx == slot {
+			return &s.Terminals[i]
+		}
+	}
+	return nil
+}
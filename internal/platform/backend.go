@@ -1,5 +1,10 @@
 package platform
 
+import (
+	"errors"
+	"fmt"
+)
+
 // WindowID is a platform-neutral window identifier.
 type WindowID uint32
 
@@ -21,11 +26,19 @@ type Display struct {
 
 // Window contains metadata and geometry for a top-level window.
 type Window struct {
-	ID     WindowID
-	PID    int
-	AppID  string
-	Title  string
-	Bounds Rect
+	ID    WindowID
+	PID   int
+	AppID string
+	// Instance is the instance part of WM_CLASS, when the platform has one.
+	Instance string
+	Title    string
+	Bounds   Rect
+}
+
+// WindowRect pairs a window with a target geometry for MoveResizeBatch.
+type WindowRect struct {
+	WindowID WindowID
+	Bounds   Rect
 }
 
 // Backend abstracts window-system operations across platforms.
@@ -35,7 +48,22 @@ type Backend interface {
 	ActiveWindow() (WindowID, error)
 	ListWindowsOnDisplay(displayID int) ([]Window, error)
 	MoveResize(windowID WindowID, bounds Rect) error
+	// MoveResizeBatch applies several geometries at once. Backends without a
+	// cheaper bulk path can implement it with MoveResizeEach.
+	MoveResizeBatch(moves []WindowRect) error
 	Minimize(windowID WindowID) error
 	Focus(windowID WindowID) error
 	Close(windowID WindowID) error
 }
+
+// MoveResizeEach is the sequential MoveResizeBatch fallback. Every move is
+// attempted; failures are joined and returned together.
+func MoveResizeEach(b Backend, moves []WindowRect) error {
+	var errs []error
+	for _, m := range moves {
+		if err := b.MoveResize(m.WindowID, m.Bounds); err != nil {
+			errs = append(errs, fmt.Errorf("window %d: %w", m.WindowID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
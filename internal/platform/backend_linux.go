@@ -224,12 +224,14 @@ func (b *LinuxBackend) listWindowsOnDisplay(displayID int, filterDesktop bool) (
 			pid = int(p)
 		}
 
+		class, instance := b.windowClass(windowID)
 		windows = append(windows, Window{
-			ID:     WindowID(windowID),
-			PID:    pid,
-			AppID:  b.windowAppID(windowID),
-			Title:  b.windowTitle(windowID),
-			Bounds: rect,
+			ID:       WindowID(windowID),
+			PID:      pid,
+			AppID:    class,
+			Instance: instance,
+			Title:    b.windowTitle(windowID),
+			Bounds:   rect,
 		})
 	}
 
@@ -256,6 +258,27 @@ func (b *LinuxBackend) MoveResize(windowID WindowID, bounds Rect) error {
 	)
 }
 
+// MoveResizeBatch moves and resizes several windows, flushing the X11 requests
+// once instead of waiting on a round trip per window.
+func (b *LinuxBackend) MoveResizeBatch(moves []WindowRect) error {
+	conn, err := b.connection()
+	if err != nil {
+		return err
+	}
+
+	geometries := make([]x11.WindowGeometry, 0, len(moves))
+	for _, m := range moves {
+		geometries = append(geometries, x11.WindowGeometry{
+			Window: xproto.Window(m.WindowID),
+			X:      m.Bounds.X,
+			Y:      m.Bounds.Y,
+			Width:  m.Bounds.Width,
+			Height: m.Bounds.Height,
+		})
+	}
+	return conn.MoveResizeWindows(geometries)
+}
+
 // Focus activates and raises a window via _NET_ACTIVE_WINDOW.
 func (b *LinuxBackend) Focus(windowID WindowID) error {
 	conn, err := b.connection()
@@ -265,6 +288,15 @@ func (b *LinuxBackend) Focus(windowID WindowID) error {
 	return conn.FocusWindow(uint32(windowID))
 }
 
+// SwitchDesktop makes desktop the current virtual desktop.
+func (b *LinuxBackend) SwitchDesktop(desktop int) error {
+	conn, err := b.connection()
+	if err != nil {
+		return err
+	}
+	return conn.SetCurrentDesktop(desktop)
+}
+
 // Minimize minimizes a window via WM_CHANGE_STATE.
 func (b *LinuxBackend) Minimize(windowID WindowID) error {
 	conn, err := b.connection()
@@ -391,12 +423,13 @@ func (b *LinuxBackend) windowRect(windowID xproto.Window) (Rect, bool) {
 	}, true
 }
 
-func (b *LinuxBackend) windowAppID(windowID xproto.Window) string {
+// windowClass returns the class and instance parts of a window's WM_CLASS.
+func (b *LinuxBackend) windowClass(windowID xproto.Window) (class, instance string) {
 	wmClass, err := icccm.WmClassGet(b.conn.XUtil, windowID)
 	if err != nil {
-		return ""
+		return "", ""
 	}
-	return strings.TrimSpace(wmClass.Class)
+	return strings.TrimSpace(wmClass.Class), strings.TrimSpace(wmClass.Instance)
 }
 
 func (b *LinuxBackend) windowTitle(windowID xproto.Window) string {
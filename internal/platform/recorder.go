@@ -0,0 +1,137 @@
+package platform
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// RecordEnvVar names the environment variable that, when set to a file path,
+// makes the daemon record every tiler MoveResize call to that file.
+const RecordEnvVar = "TERMTILE_RECORD"
+
+// MoveResizeRecord is a single recorded MoveResize call, stored one per line
+// as JSON.
+type MoveResizeRecord struct {
+	Time     time.Time `json:"time"`
+	WindowID WindowID  `json:"window_id"`
+	X        int       `json:"x"`
+	Y        int       `json:"y"`
+	Width    int       `json:"width"`
+	Height   int       `json:"height"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Bounds returns the recorded geometry as a Rect.
+func (r MoveResizeRecord) Bounds() Rect {
+	return Rect{X: r.X, Y: r.Y, Width: r.Width, Height: r.Height}
+}
+
+// RecordingBackend wraps a Backend and appends every MoveResize call to a
+// JSONL stream. All other operations pass straight through.
+type RecordingBackend struct {
+	Backend
+
+	mu  sync.Mutex
+	enc *json.Encoder
+	now func() time.Time
+}
+
+var _ Backend = (*RecordingBackend)(nil)
+
+// NewRecordingBackend returns a Backend that records MoveResize calls made on
+// inner to w.
+func NewRecordingBackend(inner Backend, w io.Writer) *RecordingBackend {
+	return &RecordingBackend{
+		Backend: inner,
+		enc:     json.NewEncoder(w),
+		now:     time.Now,
+	}
+}
+
+// MoveResize forwards to the wrapped backend and records the call. Failed
+// calls are recorded too, with their error, so a repro shows what was tried.
+func (b *RecordingBackend) MoveResize(windowID WindowID, bounds Rect) error {
+	err := b.Backend.MoveResize(windowID, bounds)
+
+	rec := MoveResizeRecord{
+		Time:     b.now(),
+		WindowID: windowID,
+		X:        bounds.X,
+		Y:        bounds.Y,
+		Width:    bounds.Width,
+		Height:   bounds.Height,
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+
+	b.mu.Lock()
+	// Recording is best-effort; a full disk must not break tiling.
+	_ = b.enc.Encode(rec)
+	b.mu.Unlock()
+
+	return err
+}
+
+// MoveResizeBatch records each move individually so a replay re-issues the
+// same calls. Recording is a debugging aid, so the per-window path is fine.
+func (b *RecordingBackend) MoveResizeBatch(moves []WindowRect) error {
+	return MoveResizeEach(b, moves)
+}
+
+// ReadRecording parses a JSONL recording produced by RecordingBackend.
+// Blank lines are skipped.
+func ReadRecording(r io.Reader) ([]MoveResizeRecord, error) {
+	var records []MoveResizeRecord
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := scanner.Bytes()
+		if len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+		var rec MoveResizeRecord
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if rec.WindowID == 0 {
+			return nil, fmt.Errorf("line %d: missing window_id", line)
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Replay re-issues recorded MoveResize calls against backend in order. When
+// sleep is non-nil it is called with the original gap between consecutive
+// records so the replay keeps the recorded timing. Calls that fail (for
+// example because the window no longer exists) do not stop the replay; their
+// errors are joined and returned along with the number of successful calls.
+func Replay(backend Backend, records []MoveResizeRecord, sleep func(time.Duration)) (int, error) {
+	applied := 0
+	var errs []error
+	for i, rec := range records {
+		if sleep != nil && i > 0 {
+			if gap := rec.Time.Sub(records[i-1].Time); gap > 0 {
+				sleep(gap)
+			}
+		}
+		if err := backend.MoveResize(rec.WindowID, rec.Bounds()); err != nil {
+			errs = append(errs, fmt.Errorf("window %d: %w", rec.WindowID, err))
+			continue
+		}
+		applied++
+	}
+	return applied, errors.Join(errs...)
+}
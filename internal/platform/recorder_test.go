@@ -0,0 +1,114 @@
+package platform
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type stubBackend struct {
+	Backend
+	moves []MoveResizeRecord
+	fail  map[WindowID]bool
+}
+
+func (s *stubBackend) MoveResize(windowID WindowID, bounds Rect) error {
+	if s.fail[windowID] {
+		return errors.New("bad window")
+	}
+	s.moves = append(s.moves, MoveResizeRecord{WindowID: windowID, X: bounds.X, Y: bounds.Y, Width: bounds.Width, Height: bounds.Height})
+	return nil
+}
+
+func (s *stubBackend) MoveResizeBatch(moves []WindowRect) error {
+	return MoveResizeEach(s, moves)
+}
+
+func TestMoveResizeEachAttemptsEveryMove(t *testing.T) {
+	b := &stubBackend{fail: map[WindowID]bool{2: true}}
+	err := MoveResizeEach(b, []WindowRect{
+		{WindowID: 1, Bounds: Rect{Width: 10, Height: 10}},
+		{WindowID: 2, Bounds: Rect{Width: 20, Height: 20}},
+		{WindowID: 3, Bounds: Rect{X: 5, Width: 30, Height: 30}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "window 2") {
+		t.Fatalf("err=%v, want failure for window 2", err)
+	}
+	if len(b.moves) != 2 || b.moves[1].WindowID != 3 || b.moves[1].X != 5 {
+		t.Fatalf("moves=%+v, want windows 1 and 3 applied", b.moves)
+	}
+}
+
+func TestRecordingBackendRoundTrip(t *testing.T) {
+	inner := &stubBackend{fail: map[WindowID]bool{3: true}}
+	var buf bytes.Buffer
+	rec := NewRecordingBackend(inner, &buf)
+	base := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tick := 0
+	rec.now = func() time.Time {
+		tick++
+		return base.Add(time.Duration(tick) * 100 * time.Millisecond)
+	}
+
+	if err := rec.MoveResize(1, Rect{X: 0, Y: 0, Width: 960, Height: 1080}); err != nil {
+		t.Fatalf("MoveResize(1): %v", err)
+	}
+	if err := rec.MoveResize(2, Rect{X: 960, Y: 0, Width: 960, Height: 1080}); err != nil {
+		t.Fatalf("MoveResize(2): %v", err)
+	}
+	if err := rec.MoveResize(3, Rect{X: 1, Y: 2, Width: 3, Height: 4}); err == nil {
+		t.Fatal("MoveResize(3) expected wrapped backend error")
+	}
+
+	if n := strings.Count(buf.String(), "\n"); n != 3 {
+		t.Fatalf("recorded %d lines, want 3:\n%s", n, buf.String())
+	}
+
+	records, err := ReadRecording(&buf)
+	if err != nil {
+		t.Fatalf("ReadRecording: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("len(records)=%d, want 3", len(records))
+	}
+	if got := records[1]; got.WindowID != 2 || got.Bounds() != (Rect{X: 960, Y: 0, Width: 960, Height: 1080}) {
+		t.Fatalf("records[1]=%+v", got)
+	}
+	if !records[1].Time.Equal(base.Add(200 * time.Millisecond)) {
+		t.Fatalf("records[1].Time=%v", records[1].Time)
+	}
+	if records[2].Error != "bad window" {
+		t.Fatalf("records[2].Error=%q, want recorded failure", records[2].Error)
+	}
+
+	target := &stubBackend{fail: map[WindowID]bool{3: true}}
+	var sleeps []time.Duration
+	applied, err := Replay(target, records, func(d time.Duration) { sleeps = append(sleeps, d) })
+	if applied != 2 {
+		t.Fatalf("applied=%d, want 2", applied)
+	}
+	if err == nil || !strings.Contains(err.Error(), "window 3") {
+		t.Fatalf("err=%v, want failure for window 3", err)
+	}
+	if len(target.moves) != 2 || target.moves[0].WindowID != 1 || target.moves[1].X != 960 {
+		t.Fatalf("replayed moves=%+v", target.moves)
+	}
+	if len(sleeps) != 2 || sleeps[0] != 100*time.Millisecond {
+		t.Fatalf("sleeps=%v, want two 100ms gaps", sleeps)
+	}
+}
+
+func TestReadRecordingRejectsBadLines(t *testing.T) {
+	if _, err := ReadRecording(strings.NewReader("{\"window_id\":1}\n\nnot json\n")); err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Fatalf("err=%v, want line 3 parse error", err)
+	}
+	if _, err := ReadRecording(strings.NewReader("{\"x\":1}\n")); err == nil || !strings.Contains(err.Error(), "window_id") {
+		t.Fatalf("err=%v, want missing window_id", err)
+	}
+	records, err := ReadRecording(strings.NewReader("\n  \n"))
+	if err != nil || len(records) != 0 {
+		t.Fatalf("records=%v err=%v, want empty", records, err)
+	}
+}
@@ -11,46 +11,88 @@ import (
 type TerminalWindow struct {
 	WindowID platform.WindowID
 	Class    string
+	Instance string
 	Title    string
+	PID      int
 	X        int
 	Y        int
 	Width    int
 	Height   int
 }
 
+// ClassMatch selects terminal windows by WM_CLASS. An empty Instance
+// matches every instance of Class. Both parts compare case-insensitively.
+type ClassMatch struct {
+	Class    string
+	Instance string
+}
+
 // Detector identifies terminal windows on the display
 type Detector struct {
-	mu              sync.RWMutex
-	terminalClasses map[string]bool
+	mu sync.RWMutex
+	// terminalClasses maps a lowercased class to the lowercased instances
+	// it accepts; a nil set accepts any instance.
+	terminalClasses map[string]map[string]bool
 }
 
 // NewDetector creates a new terminal detector with the given terminal class list
 func NewDetector(terminalClasses []string) *Detector {
-	classMap := make(map[string]bool)
-	for _, class := range terminalClasses {
-		// Store both original and lowercase for case-insensitive matching
-		classMap[class] = true
-		classMap[strings.ToLower(class)] = true
-	}
+	return NewMatchDetector(classMatches(terminalClasses))
+}
 
+// NewMatchDetector creates a terminal detector from class/instance matches.
+func NewMatchDetector(matches []ClassMatch) *Detector {
 	return &Detector{
-		terminalClasses: classMap,
+		terminalClasses: buildClassMap(matches),
 	}
 }
 
 // UpdateTerminalClasses updates the terminal classes for detection
 func (d *Detector) UpdateTerminalClasses(terminalClasses []string) {
-	classMap := make(map[string]bool)
-	for _, class := range terminalClasses {
-		classMap[class] = true
-		classMap[strings.ToLower(class)] = true
-	}
+	d.UpdateMatches(classMatches(terminalClasses))
+}
+
+// UpdateMatches replaces the class/instance matches used for detection.
+func (d *Detector) UpdateMatches(matches []ClassMatch) {
+	classMap := buildClassMap(matches)
 
 	d.mu.Lock()
 	defer d.mu.Unlock()
 	d.terminalClasses = classMap
 }
 
+func classMatches(terminalClasses []string) []ClassMatch {
+	matches := make([]ClassMatch, 0, len(terminalClasses))
+	for _, class := range terminalClasses {
+		matches = append(matches, ClassMatch{Class: class})
+	}
+	return matches
+}
+
+// buildClassMap indexes matches by lowercased class. A class-only match
+// wins over instance matches for the same class.
+func buildClassMap(matches []ClassMatch) map[string]map[string]bool {
+	classMap := make(map[string]map[string]bool)
+	anyInstance := make(map[string]bool)
+	for _, m := range matches {
+		class := strings.ToLower(m.Class)
+		instance := strings.ToLower(m.Instance)
+		if instance == "" {
+			anyInstance[class] = true
+			classMap[class] = nil
+			continue
+		}
+		if anyInstance[class] {
+			continue
+		}
+		if classMap[class] == nil {
+			classMap[class] = make(map[string]bool)
+		}
+		classMap[class][instance] = true
+	}
+	return classMap
+}
+
 // FindTerminals finds all terminal windows on the specified display within the given bounds.
 // The bounds parameter is used to filter windows whose center falls inside that rectangle
 // (typically the padded monitor area).
@@ -59,34 +101,17 @@ func (d *Detector) FindTerminals(backend platform.Backend, displayID int, bounds
 	if err != nil {
 		return nil, err
 	}
+	return windowsInBounds(windows, bounds, d.isTerminalClass), nil
+}
 
-	var terminals []TerminalWindow
-	for _, w := range windows {
-		// Check if this is a terminal
-		if !d.isTerminalClass(w.AppID) {
-			continue
-		}
-
-		// Check if window center is within bounds
-		centerX := w.Bounds.X + w.Bounds.Width/2
-		centerY := w.Bounds.Y + w.Bounds.Height/2
-		if centerX < bounds.X || centerX >= bounds.X+bounds.Width ||
-			centerY < bounds.Y || centerY >= bounds.Y+bounds.Height {
-			continue
-		}
-
-		terminals = append(terminals, TerminalWindow{
-			WindowID: w.ID,
-			Class:    w.AppID,
-			Title:    w.Title,
-			X:        w.Bounds.X,
-			Y:        w.Bounds.Y,
-			Width:    w.Bounds.Width,
-			Height:   w.Bounds.Height,
-		})
+// FindWindows is like FindTerminals but keeps every window the backend lists
+// (normal top-level windows), not only terminals.
+func (d *Detector) FindWindows(backend platform.Backend, displayID int, bounds platform.Rect) ([]TerminalWindow, error) {
+	windows, err := backend.ListWindowsOnDisplay(displayID)
+	if err != nil {
+		return nil, err
 	}
-
-	return terminals, nil
+	return windowsInBounds(windows, bounds, nil), nil
 }
 
 // AllDesktopsWindowLister is an optional interface for backends that can list
@@ -108,13 +133,19 @@ func (d *Detector) FindTerminalsAllDesktops(backend platform.Backend, displayID
 	if err != nil {
 		return nil, err
 	}
+	return windowsInBounds(windows, bounds, d.isTerminalClass), nil
+}
 
-	var terminals []TerminalWindow
+// windowsInBounds returns the windows whose center lies within bounds and,
+// when isTerminal is non-nil, whose WM_CLASS it accepts.
+func windowsInBounds(windows []platform.Window, bounds platform.Rect, isTerminal func(class, instance string) bool) []TerminalWindow {
+	var out []TerminalWindow
 	for _, w := range windows {
-		if !d.isTerminalClass(w.AppID) {
+		if isTerminal != nil && !isTerminal(w.AppID, w.Instance) {
 			continue
 		}
 
+		// Check if window center is within bounds
 		centerX := w.Bounds.X + w.Bounds.Width/2
 		centerY := w.Bounds.Y + w.Bounds.Height/2
 		if centerX < bounds.X || centerX >= bounds.X+bounds.Width ||
@@ -122,29 +153,34 @@ func (d *Detector) FindTerminalsAllDesktops(backend platform.Backend, displayID
 			continue
 		}
 
-		terminals = append(terminals, TerminalWindow{
+		out = append(out, TerminalWindow{
 			WindowID: w.ID,
 			Class:    w.AppID,
+			Instance: w.Instance,
 			Title:    w.Title,
+			PID:      w.PID,
 			X:        w.Bounds.X,
 			Y:        w.Bounds.Y,
 			Width:    w.Bounds.Width,
 			Height:   w.Bounds.Height,
 		})
 	}
+	return out
+}
 
-	return terminals, nil
+// IsTerminal reports whether w's WM_CLASS matches a known terminal.
+func (d *Detector) IsTerminal(w TerminalWindow) bool {
+	return d.isTerminalClass(w.Class, w.Instance)
 }
 
 // isTerminalClass checks if the given WM_CLASS matches a known terminal
-func (d *Detector) isTerminalClass(class string) bool {
+func (d *Detector) isTerminalClass(class, instance string) bool {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
 
-	// Check exact match first
-	if d.terminalClasses[class] {
-		return true
+	instances, ok := d.terminalClasses[strings.ToLower(class)]
+	if !ok {
+		return false
 	}
-	// Check lowercase match
-	return d.terminalClasses[strings.ToLower(class)]
+	return instances == nil || instances[strings.ToLower(instance)]
 }
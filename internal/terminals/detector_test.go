@@ -0,0 +1,97 @@
+package terminals
+
+import (
+	"testing"
+
+	"github.com/1broseidon/termtile/internal/platform"
+)
+
+func TestIsTerminalClass_ClassOnly(t *testing.T) {
+	d := NewDetector([]string{"kitty", "Alacritty"})
+	tests := []struct {
+		class, instance string
+		want            bool
+	}{
+		{"kitty", "kitty", true},
+		{"kitty", "scratchpad", true},
+		{"KITTY", "", true},
+		{"alacritty", "Alacritty", true},
+		{"xterm", "xterm", false},
+	}
+	for _, tt := range tests {
+		if got := d.isTerminalClass(tt.class, tt.instance); got != tt.want {
+			t.Fatalf("isTerminalClass(%q, %q) = %v, want %v", tt.class, tt.instance, got, tt.want)
+		}
+	}
+}
+
+func TestIsTerminalClass_ClassAndInstance(t *testing.T) {
+	d := NewMatchDetector([]ClassMatch{
+		{Class: "kitty", Instance: "scratchpad"},
+		{Class: "kitty", Instance: "Logs"},
+		{Class: "Alacritty"},
+	})
+	tests := []struct {
+		class, instance string
+		want            bool
+	}{
+		{"kitty", "scratchpad", true},
+		{"Kitty", "SCRATCHPAD", true},
+		{"kitty", "logs", true},
+		{"kitty", "kitty", false},
+		{"kitty", "", false},
+		{"Alacritty", "anything", true},
+	}
+	for _, tt := range tests {
+		if got := d.isTerminalClass(tt.class, tt.instance); got != tt.want {
+			t.Fatalf("isTerminalClass(%q, %q) = %v, want %v", tt.class, tt.instance, got, tt.want)
+		}
+	}
+}
+
+func TestIsTerminalClass_ClassOnlyEntryWins(t *testing.T) {
+	for _, matches := range [][]ClassMatch{
+		{{Class: "kitty", Instance: "scratchpad"}, {Class: "kitty"}},
+		{{Class: "kitty"}, {Class: "kitty", Instance: "scratchpad"}},
+	} {
+		d := NewMatchDetector(matches)
+		if !d.isTerminalClass("kitty", "other") {
+			t.Fatalf("matches %+v: class-only entry did not accept other instances", matches)
+		}
+	}
+}
+
+type listOnlyBackend struct {
+	platform.Backend
+	windows []platform.Window
+}
+
+func (b listOnlyBackend) ListWindowsOnDisplay(int) ([]platform.Window, error) {
+	return b.windows, nil
+}
+
+func TestFindTerminals_MatchesInstance(t *testing.T) {
+	bounds := platform.Rect{X: 0, Y: 0, Width: 1000, Height: 1000}
+	backend := listOnlyBackend{windows: []platform.Window{
+		{ID: 1, AppID: "kitty", Instance: "kitty", Bounds: platform.Rect{Width: 100, Height: 100}},
+		{ID: 2, AppID: "kitty", Instance: "scratchpad", Bounds: platform.Rect{Width: 100, Height: 100}},
+	}}
+
+	d := NewDetector([]string{"kitty"})
+	got, err := d.FindTerminals(backend, 0, bounds)
+	if err != nil {
+		t.Fatalf("FindTerminals: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("class-only found %d windows, want 2", len(got))
+	}
+
+	d.UpdateMatches([]ClassMatch{{Class: "kitty", Instance: "scratchpad"}})
+	got, err = d.FindTerminals(backend, 0, bounds)
+	if err != nil {
+		t.Fatalf("FindTerminals: %v", err)
+	}
+	if len(got) != 1 || got[0].WindowID != 2 || got[0].Instance != "scratchpad" {
+		t.Fatalf("class+instance found %+v, want only window 2", got)
+	}
+}
@@ -0,0 +1,71 @@
+package terminals
+
+import (
+	"sort"
+
+	"github.com/1broseidon/termtile/internal/platform"
+)
+
+// SortKey holds the window fields terminal_sort orders by.
+type SortKey struct {
+	WindowID platform.WindowID
+	Title    string
+	PID      int
+	X        int
+	Y        int
+}
+
+// SortKey returns the fields terminal_sort orders w by.
+func (w TerminalWindow) SortKey() SortKey {
+	return SortKey{WindowID: w.WindowID, Title: w.Title, PID: w.PID, X: w.X, Y: w.Y}
+}
+
+// SortWindows orders windows by a terminal_sort mode: client_list keeps
+// the given order, window_id, title and pid sort by that field, active_first
+// puts activeWin first and the rest by position, and any other mode sorts by
+// position (top to bottom, then left to right). Ties fall back to the window
+// ID. key returns the fields of a window.
+func SortWindows[W any](windows []W, mode string, activeWin platform.WindowID, key func(W) SortKey) {
+	if mode == "client_list" {
+		return
+	}
+	less := func(a, b SortKey) bool {
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		if a.X != b.X {
+			return a.X < b.X
+		}
+		return a.WindowID < b.WindowID
+	}
+	switch mode {
+	case "window_id":
+		less = func(a, b SortKey) bool { return a.WindowID < b.WindowID }
+	case "title":
+		less = func(a, b SortKey) bool {
+			if a.Title != b.Title {
+				return a.Title < b.Title
+			}
+			return a.WindowID < b.WindowID
+		}
+	case "pid":
+		// Windows without a known PID (0) sort last.
+		less = func(a, b SortKey) bool {
+			if a.PID != b.PID {
+				return b.PID == 0 || (a.PID != 0 && a.PID < b.PID)
+			}
+			return a.WindowID < b.WindowID
+		}
+	case "active_first":
+		byPosition := less
+		less = func(a, b SortKey) bool {
+			if activeWin != 0 && (a.WindowID == activeWin) != (b.WindowID == activeWin) {
+				return a.WindowID == activeWin
+			}
+			return byPosition(a, b)
+		}
+	}
+	sort.SliceStable(windows, func(i, j int) bool {
+		return less(key(windows[i]), key(windows[j]))
+	})
+}
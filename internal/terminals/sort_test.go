@@ -0,0 +1,41 @@
+package terminals
+
+import (
+	"testing"
+
+	"github.com/1broseidon/termtile/internal/platform"
+)
+
+func TestSortWindows(t *testing.T) {
+	windows := func() []TerminalWindow {
+		return []TerminalWindow{
+			{WindowID: 1, Title: "logs", PID: 300, X: 500, Y: 0},
+			{WindowID: 2, Title: "editor", PID: 0, X: 0, Y: 400},
+			{WindowID: 3, Title: "build", PID: 100, X: 0, Y: 0},
+			{WindowID: 4, Title: "editor", PID: 200, X: 500, Y: 400},
+		}
+	}
+
+	tests := []struct {
+		mode   string
+		active platform.WindowID
+		want   []platform.WindowID
+	}{
+		{mode: "client_list", want: []platform.WindowID{1, 2, 3, 4}},
+		{mode: "position", want: []platform.WindowID{3, 1, 2, 4}},
+		{mode: "window_id", want: []platform.WindowID{1, 2, 3, 4}},
+		{mode: "title", want: []platform.WindowID{3, 2, 4, 1}},
+		{mode: "pid", want: []platform.WindowID{3, 4, 1, 2}},
+		{mode: "active_first", active: 4, want: []platform.WindowID{4, 3, 1, 2}},
+		{mode: "active_first", want: []platform.WindowID{3, 1, 2, 4}},
+	}
+	for _, tt := range tests {
+		got := windows()
+		SortWindows(got, tt.mode, tt.active, TerminalWindow.SortKey)
+		for i, w := range got {
+			if w.WindowID != tt.want[i] {
+				t.Fatalf("%s (active %d) order = %+v, want %v", tt.mode, tt.active, got, tt.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package tiling
+
+// EventKind identifies what an Event reports.
+type EventKind string
+
+const (
+	// EventTiled follows a successful TileCurrentMonitor, TileAllMonitors
+	// or TileWithOrder.
+	EventTiled EventKind = "tile"
+	// EventLayoutChanged follows a change of the active layout.
+	EventLayoutChanged EventKind = "layout_changed"
+)
+
+// Event describes a change the tiler made.
+type Event struct {
+	Kind EventKind
+	// Layout is the active layout name.
+	Layout string
+	// MonitorID is the monitor that was tiled, or -1 when every monitor
+	// was. Unused for EventLayoutChanged.
+	MonitorID int
+	// Terminals is the number of terminals placed. Unused for
+	// EventLayoutChanged.
+	Terminals int
+}
+
+// SetEventHandler registers fn to be called for every tiler Event; nil
+// removes it. fn runs with the tiler locked, so it must return quickly and
+// must not call back into the Tiler.
+func (t *Tiler) SetEventHandler(fn func(Event)) {
+	t.eventMu.Lock()
+	defer t.eventMu.Unlock()
+	t.onEvent = fn
+}
+
+// emit passes ev to the registered event handler, if any.
+func (t *Tiler) emit(ev Event) {
+	t.eventMu.Lock()
+	fn := t.onEvent
+	t.eventMu.Unlock()
+	if fn != nil {
+		fn(ev)
+	}
+}
@@ -67,6 +67,41 @@ func CalculatePositions(numWindows int, monitor Rect, gapSize int) []Rect {
 	return positions
 }
 
+// Geometry holds everything Positions needs to place windows, independent
+// of how the layout was configured.
+type Geometry struct {
+	Mode              config.LayoutMode
+	Order             config.TileOrder
+	RemainderBias     config.RemainderBias // auto mode with a row order only
+	Gap               int
+	Grid              config.FixedGrid   // rows and cols for LayoutModeFixed
+	MasterStack       config.MasterStack // for LayoutModeMasterStack
+	MaxTileWidth      int                // 0 = unlimited
+	MaxTileHeight     int                // 0 = unlimited
+	FlexibleLastRow   bool
+	SingleWindowNoGap bool
+	Reserved          *config.TileRegion // edge strip for ReservedSlot, nil = none
+	ReservedSlot      int
+}
+
+// GeometryFor returns the geometry of layout with the given gap size.
+func GeometryFor(layout *config.Layout, gapSize int) Geometry {
+	return Geometry{
+		Mode:              layout.Mode,
+		Order:             layout.Order,
+		RemainderBias:     layout.RemainderBias,
+		Gap:               gapSize,
+		Grid:              layout.FixedGrid,
+		MasterStack:       layout.MasterStack,
+		MaxTileWidth:      layout.MaxTerminalWidth,
+		MaxTileHeight:     layout.MaxTerminalHeight,
+		FlexibleLastRow:   layout.FlexibleLastRow,
+		SingleWindowNoGap: layout.SingleWindowNoGap,
+		Reserved:          layout.ReservedRegion,
+		ReservedSlot:      layout.ReservedSlot,
+	}
+}
+
 // CalculatePositionsWithLayout computes window positions using layout configuration
 func CalculatePositionsWithLayout(
 	numWindows int,
@@ -74,20 +109,36 @@ func CalculatePositionsWithLayout(
 	layout *config.Layout,
 	gapSize int,
 ) ([]Rect, error) {
+	return Positions(numWindows, monitor, GeometryFor(layout, gapSize))
+}
+
+// Positions computes the bounds of numWindows windows tiled in area. It is
+// pure: the result depends only on its arguments. Capped layouts (fixed
+// grids, master-stack) may return fewer positions than numWindows.
+func Positions(numWindows int, area Rect, g Geometry) ([]Rect, error) {
 	if numWindows == 0 {
 		return nil, nil
 	}
+	if numWindows == 1 && g.SingleWindowNoGap {
+		g.Gap = 0
+	}
+	if g.Reserved != nil {
+		return positionsWithReservedRegion(numWindows, area, g)
+	}
+	gapSize := g.Gap
 
 	var rows, cols int
-	flexibleLastRow := layout.FlexibleLastRow
+	// A column order leaves its partial line in the last column, so there
+	// is no short last row to stretch.
+	flexibleLastRow := g.FlexibleLastRow && !g.Order.IsColumn()
 
-	switch layout.Mode {
+	switch g.Mode {
 	case config.LayoutModeAuto:
 		rows, cols = CalculateGrid(numWindows)
 
 	case config.LayoutModeFixed:
-		rows = layout.FixedGrid.Rows
-		cols = layout.FixedGrid.Cols
+		rows = g.Grid.Rows
+		cols = g.Grid.Cols
 		// Only tile up to rows*cols terminals
 		if numWindows > rows*cols {
 			numWindows = rows * cols
@@ -108,39 +159,30 @@ func CalculatePositionsWithLayout(
 		flexibleLastRow = false
 
 	case config.LayoutModeMasterStack:
-		ms := layout.MasterStack
+		ms := g.MasterStack
 
 		// Master pane always uses MasterWidthPercent regardless of window count.
 		// No auto-expand — agents spawn into their right-side slots.
-		masterWidth := (monitor.Width * ms.MasterWidthPercent / 100) - gapSize
+		masterWidth := (area.Width * ms.MasterWidthPercent / 100) - gapSize
 
 		if numWindows == 1 {
 			return []Rect{{
-				X:      monitor.X + gapSize,
-				Y:      monitor.Y + gapSize,
+				X:      area.X + gapSize,
+				Y:      area.Y + gapSize,
 				Width:  masterWidth,
-				Height: monitor.Height - 2*gapSize,
+				Height: area.Height - 2*gapSize,
 			}}, nil
 		}
 
 		// Right region for stack grid
-		rightStartX := monitor.X + masterWidth + 2*gapSize
-		rightRegionWidth := monitor.Width - masterWidth - 3*gapSize
-		stackHeight := monitor.Height - 2*gapSize
+		rightStartX := area.X + masterWidth + 2*gapSize
+		rightRegionWidth := area.Width - masterWidth - 3*gapSize
+		stackHeight := area.Height - 2*gapSize
 
 		stackCount := numWindows - 1
-
-		// Auto-grid: cols = ceil(stackCount / MaxStackRows) capped at MaxStackCols
-		stackCols := int(math.Ceil(float64(stackCount) / float64(ms.MaxStackRows)))
-		if stackCols > ms.MaxStackCols {
-			stackCols = ms.MaxStackCols
-		}
-		if stackCols < 1 {
-			stackCols = 1
-		}
-		stackRows := int(math.Ceil(float64(stackCount) / float64(stackCols)))
-		if stackRows > ms.MaxStackRows {
-			stackRows = ms.MaxStackRows
+		stackRows, stackCols := masterStackGrid(stackCount, ms)
+		if stackRows <= 0 || stackCols <= 0 {
+			return nil, fmt.Errorf("invalid stack grid dimensions: rows=%d cols=%d", stackRows, stackCols)
 		}
 
 		// Cap to grid capacity
@@ -157,24 +199,23 @@ func CalculatePositionsWithLayout(
 		if masterWidth <= 0 || cellWidth <= 0 || cellHeight <= 0 {
 			return nil, fmt.Errorf(
 				"insufficient space for master-stack layout: monitor=%dx%d masterWidth=%d cellWidth=%d cellHeight=%d gap=%d",
-				monitor.Width, monitor.Height, masterWidth, cellWidth, cellHeight, gapSize,
+				area.Width, area.Height, masterWidth, cellWidth, cellHeight, gapSize,
 			)
 		}
 
 		positions := make([]Rect, numWindows)
 		positions[0] = Rect{
-			X:      monitor.X + gapSize,
-			Y:      monitor.Y + gapSize,
+			X:      area.X + gapSize,
+			Y:      area.Y + gapSize,
 			Width:  masterWidth,
 			Height: stackHeight,
 		}
 
 		for i := 0; i < stackCount; i++ {
-			row := i / stackCols
-			col := i % stackCols
+			row, col := gridCell(i, stackRows, stackCols, g.Order)
 			positions[i+1] = Rect{
 				X:      rightStartX + col*(cellWidth+gapSize),
-				Y:      monitor.Y + gapSize + row*(cellHeight+gapSize),
+				Y:      area.Y + gapSize + row*(cellHeight+gapSize),
 				Width:  cellWidth,
 				Height: cellHeight,
 			}
@@ -183,7 +224,7 @@ func CalculatePositionsWithLayout(
 		return positions, nil
 
 	default:
-		return nil, fmt.Errorf("unsupported layout mode: %q", layout.Mode)
+		return nil, fmt.Errorf("unsupported layout mode: %q", g.Mode)
 	}
 
 	if rows <= 0 || cols <= 0 {
@@ -194,13 +235,13 @@ func CalculatePositionsWithLayout(
 	totalHorizontalGaps := (cols + 1) * gapSize
 	totalVerticalGaps := (rows + 1) * gapSize
 
-	slotWidth := (monitor.Width - totalHorizontalGaps) / cols
-	slotHeight := (monitor.Height - totalVerticalGaps) / rows
+	slotWidth := (area.Width - totalHorizontalGaps) / cols
+	slotHeight := (area.Height - totalVerticalGaps) / rows
 
 	if slotWidth <= 0 || slotHeight <= 0 {
 		return nil, fmt.Errorf(
 			"insufficient space for layout: monitor=%dx%d rows=%d cols=%d gap=%d (slot=%dx%d)",
-			monitor.Width, monitor.Height, rows, cols, gapSize, slotWidth, slotHeight,
+			area.Width, area.Height, rows, cols, gapSize, slotWidth, slotHeight,
 		)
 	}
 
@@ -208,11 +249,11 @@ func CalculatePositionsWithLayout(
 	windowHeight := slotHeight
 
 	// Apply max dimension constraints (within each slot)
-	if layout.MaxTerminalWidth > 0 && windowWidth > layout.MaxTerminalWidth {
-		windowWidth = layout.MaxTerminalWidth
+	if g.MaxTileWidth > 0 && windowWidth > g.MaxTileWidth {
+		windowWidth = g.MaxTileWidth
 	}
-	if layout.MaxTerminalHeight > 0 && windowHeight > layout.MaxTerminalHeight {
-		windowHeight = layout.MaxTerminalHeight
+	if g.MaxTileHeight > 0 && windowHeight > g.MaxTileHeight {
+		windowHeight = g.MaxTileHeight
 	}
 
 	// Calculate last row info for flexible layout
@@ -222,44 +263,62 @@ func CalculatePositionsWithLayout(
 		windowsInLastRow = cols // Full row
 	}
 
-	// Calculate last row dimensions if flexible
-	var lastRowSlotWidth, lastRowWindowWidth int
-	if flexibleLastRow && windowsInLastRow < cols && windowsInLastRow > 0 {
-		// Last row has fewer windows - they expand to fill the width
-		lastRowHorizontalGaps := (windowsInLastRow + 1) * gapSize
-		lastRowSlotWidth = (monitor.Width - lastRowHorizontalGaps) / windowsInLastRow
-		lastRowWindowWidth = lastRowSlotWidth
-		if layout.MaxTerminalWidth > 0 && lastRowWindowWidth > layout.MaxTerminalWidth {
-			lastRowWindowWidth = layout.MaxTerminalWidth
-		}
+	// rowLens holds the window count of every row when the remainder bias
+	// moves the partial row away from the bottom; nil means it stays there.
+	var rowLens []int
+	if g.Mode == config.LayoutModeAuto && !g.Order.IsColumn() {
+		rowLens = remainderRows(numWindows, rows, cols, g.RemainderBias)
 	}
+	reverse := g.Order == config.TileOrderRowReverse
 
 	positions := make([]Rect, numWindows)
 
 	for i := 0; i < numWindows; i++ {
-		row := i / cols
-		col := i % cols
-
-		// Check if this is on the last row and we need flexible sizing
-		isLastRow := row == lastRowIndex
-		useFlexible := flexibleLastRow && isLastRow && windowsInLastRow < cols
+		row, col := gridCell(i, rows, cols, g.Order)
+
+		// rowLen is the number of windows in this row and k the index of
+		// this window among them, in fill order.
+		rowLen, k := cols, 0
+		if rowLens != nil {
+			row, k, rowLen = rowCell(i, rowLens)
+			col = k
+			if reverse {
+				col = cols - 1 - k
+			}
+		} else if row == lastRowIndex && !g.Order.IsColumn() {
+			rowLen = windowsInLastRow
+			k = i - (lastRowIndex * cols)
+		}
 
-		var thisSlotWidth, thisWindowWidth int
+		thisSlotWidth := slotWidth
+		thisWindowWidth := windowWidth
 		var x int
 
-		if useFlexible {
-			// Recalculate column index for the last row (0-based within last row)
-			lastRowCol := i - (lastRowIndex * cols)
-			thisSlotWidth = lastRowSlotWidth
-			thisWindowWidth = lastRowWindowWidth
-			x = monitor.X + gapSize + lastRowCol*(thisSlotWidth+gapSize)
-		} else {
-			thisSlotWidth = slotWidth
-			thisWindowWidth = windowWidth
-			x = monitor.X + gapSize + col*(slotWidth+gapSize)
+		switch {
+		case flexibleLastRow && rowLen < cols:
+			// Short row - its windows expand to fill the width
+			pos := k
+			if reverse {
+				pos = rowLen - 1 - k
+			}
+			thisSlotWidth = (area.Width - (rowLen+1)*gapSize) / rowLen
+			thisWindowWidth = thisSlotWidth
+			if g.MaxTileWidth > 0 && thisWindowWidth > g.MaxTileWidth {
+				thisWindowWidth = g.MaxTileWidth
+			}
+			x = area.X + gapSize + pos*(thisSlotWidth+gapSize)
+		case g.RemainderBias == config.RemainderBiasBalanced && rowLens != nil && rowLen < cols:
+			// Short row - keep the column width and center the row
+			pos := k
+			if reverse {
+				pos = rowLen - 1 - k
+			}
+			x = area.X + gapSize + pos*(slotWidth+gapSize) + (cols-rowLen)*(slotWidth+gapSize)/2
+		default:
+			x = area.X + gapSize + col*(slotWidth+gapSize)
 		}
 
-		y := monitor.Y + gapSize + row*(slotHeight+gapSize)
+		y := area.Y + gapSize + row*(slotHeight+gapSize)
 
 		// Center within the slot if terminal is smaller than available space
 		if thisWindowWidth < thisSlotWidth {
@@ -280,6 +339,256 @@ func CalculatePositionsWithLayout(
 	return positions, nil
 }
 
+// remainderRows returns how many of numWindows windows each row of a rows x
+// cols auto grid holds under bias, or nil for the default last-row bias.
+func remainderRows(numWindows, rows, cols int, bias config.RemainderBias) []int {
+	var lens []int
+	switch bias {
+	case config.RemainderBiasFirstRow:
+		lens = make([]int, rows)
+		for r := range lens {
+			lens[r] = cols
+		}
+		lens[0] = numWindows - (rows-1)*cols
+	case config.RemainderBiasBalanced:
+		lens = make([]int, rows)
+		for r := range lens {
+			lens[r] = numWindows / rows
+			if r < numWindows%rows {
+				lens[r]++
+			}
+		}
+	}
+	return lens
+}
+
+// rowCell returns the row of the i-th window, its index within that row and
+// the row's window count, for rows holding rowLens windows each.
+func rowCell(i int, rowLens []int) (row, k, rowLen int) {
+	for row, rowLen = range rowLens {
+		if i < rowLen {
+			return row, i, rowLen
+		}
+		i -= rowLen
+	}
+	return len(rowLens) - 1, i, rowLen
+}
+
+// gridCell returns the row and column of the i-th slot when slots fill a
+// rows x cols grid in order. Row orders leave a partial last row; column
+// orders leave a partial last column.
+func gridCell(i, rows, cols int, order config.TileOrder) (row, col int) {
+	switch order {
+	case config.TileOrderRowReverse:
+		return i / cols, cols - 1 - i%cols
+	case config.TileOrderColumn:
+		return i % rows, i / rows
+	case config.TileOrderColumnReverse:
+		return rows - 1 - i%rows, i / rows
+	default:
+		return i / cols, i % cols
+	}
+}
+
+// positionsWithReservedRegion places g.ReservedSlot in the reserved
+// strip and tiles the remaining windows in the rest of area.
+// While there are too few windows to reach the reserved slot the strip
+// stays empty, so the grid does not jump when that window appears.
+func positionsWithReservedRegion(numWindows int, area Rect, g Geometry) ([]Rect, error) {
+	reserved, rest := splitReservedRegion(area, *g.Reserved, g.Gap)
+	if reserved.Width <= 0 || reserved.Height <= 0 || rest.Width <= 0 || rest.Height <= 0 {
+		return nil, fmt.Errorf(
+			"insufficient space for reserved region: monitor=%dx%d reserved=%dx%d rest=%dx%d gap=%d",
+			area.Width, area.Height, reserved.Width, reserved.Height, rest.Width, rest.Height, g.Gap,
+		)
+	}
+
+	restGeometry := g
+	restGeometry.Reserved = nil
+
+	gridCount := reservedGridCount(numWindows, g.ReservedSlot)
+	if gridCount == numWindows {
+		return Positions(numWindows, rest, restGeometry)
+	}
+
+	others, err := Positions(gridCount, rest, restGeometry)
+	if err != nil {
+		return nil, err
+	}
+	// A capped layout may place fewer windows than asked; keep the
+	// reserved window directly after the last placed one in that case.
+	slot := g.ReservedSlot
+	if slot > len(others) {
+		slot = len(others)
+	}
+	positions := make([]Rect, 0, len(others)+1)
+	positions = append(positions, others[:slot]...)
+	positions = append(positions, reserved)
+	positions = append(positions, others[slot:]...)
+	return positions, nil
+}
+
+// splitReservedRegion divides monitor into the window bounds of an edge
+// strip region and the area left for the other windows. The reserved window
+// is inset by gapSize on every side except the one it shares with the rest
+// area, whose own outer gap separates the two.
+func splitReservedRegion(monitor Rect, region config.TileRegion, gapSize int) (reserved, rest Rect) {
+	strip := ApplyRegion(monitor, region)
+	right := monitor.X + monitor.Width
+	bottom := monitor.Y + monitor.Height
+
+	rest = monitor
+	reserved = Rect{
+		X:      monitor.X + gapSize,
+		Y:      monitor.Y + gapSize,
+		Width:  monitor.Width - 2*gapSize,
+		Height: monitor.Height - 2*gapSize,
+	}
+
+	switch {
+	case strip.Width >= monitor.Width && strip.Y == monitor.Y:
+		// Top strip.
+		rest.Y = strip.Y + strip.Height
+		rest.Height = bottom - rest.Y
+		reserved.Height = strip.Height - gapSize
+	case strip.Width >= monitor.Width:
+		// Bottom strip: stretch to the bottom edge to absorb rounding.
+		rest.Height = strip.Y - monitor.Y
+		reserved.Y = strip.Y
+		reserved.Height = bottom - strip.Y - gapSize
+	case strip.X == monitor.X:
+		// Left strip.
+		rest.X = strip.X + strip.Width
+		rest.Width = right - rest.X
+		reserved.Width = strip.Width - gapSize
+	default:
+		// Right strip.
+		rest.Width = strip.X - monitor.X
+		reserved.X = strip.X
+		reserved.Width = right - strip.X - gapSize
+	}
+	return reserved, rest
+}
+
+// reservedGridCount returns how many of numWindows windows a layout with a
+// reserved region tiles outside the strip: all of them until the window
+// for slot appears, then one fewer.
+func reservedGridCount(numWindows, slot int) int {
+	if slot < numWindows {
+		return numWindows - 1
+	}
+	return numWindows
+}
+
+// masterStackGrid returns the grid of the stack beside the master pane for
+// stackCount windows: columns fill up to MaxStackRows rows each, capped at
+// MaxStackCols. It returns 0, 0 when there is nothing to stack.
+func masterStackGrid(stackCount int, ms config.MasterStack) (rows, cols int) {
+	if stackCount <= 0 || ms.MaxStackRows <= 0 || ms.MaxStackCols <= 0 {
+		return 0, 0
+	}
+	cols = int(math.Ceil(float64(stackCount) / float64(ms.MaxStackRows)))
+	if cols > ms.MaxStackCols {
+		cols = ms.MaxStackCols
+	}
+	rows = int(math.Ceil(float64(stackCount) / float64(cols)))
+	if rows > ms.MaxStackRows {
+		rows = ms.MaxStackRows
+	}
+	return rows, cols
+}
+
+// LayoutCapacity returns the maximum number of windows layout places, or 0
+// when the layout grows with the window count (auto, vertical, horizontal).
+// A reserved region adds one window to a capped layout.
+func LayoutCapacity(layout *config.Layout) int {
+	capacity := 0
+	switch layout.Mode {
+	case config.LayoutModeFixed:
+		capacity = layout.FixedGrid.Rows * layout.FixedGrid.Cols
+	case config.LayoutModeMasterStack:
+		capacity = 1 + layout.MasterStack.MaxStackRows*layout.MasterStack.MaxStackCols
+	}
+	if capacity > 0 && layout.ReservedRegion != nil {
+		capacity++
+	}
+	return capacity
+}
+
+// LayoutGrid returns the grid layout uses to tile count windows and how
+// many of them it places. For master-stack layouts the grid is the stack
+// beside the master pane; with a reserved region it is the grid outside
+// the reserved strip.
+func LayoutGrid(layout *config.Layout, count int) (rows, cols, placed int) {
+	if count <= 0 {
+		return 0, 0, 0
+	}
+	placed = count
+	if capacity := LayoutCapacity(layout); capacity > 0 && placed > capacity {
+		placed = capacity
+	}
+	n := placed
+	if layout.ReservedRegion != nil {
+		n = reservedGridCount(placed, layout.ReservedSlot)
+	}
+	if n <= 0 {
+		return 0, 0, placed
+	}
+
+	switch layout.Mode {
+	case config.LayoutModeAuto:
+		rows, cols = CalculateGrid(n)
+	case config.LayoutModeFixed:
+		rows, cols = layout.FixedGrid.Rows, layout.FixedGrid.Cols
+	case config.LayoutModeVertical:
+		rows, cols = n, 1
+	case config.LayoutModeHorizontal:
+		rows, cols = 1, n
+	case config.LayoutModeMasterStack:
+		rows, cols = masterStackGrid(n-1, layout.MasterStack)
+	}
+	return rows, cols, placed
+}
+
+// FillCount returns how many windows must be added to the current count to
+// fill layout to capacity. Layouts without a fixed capacity never need filling.
+func FillCount(layout *config.Layout, current int) int {
+	capacity := LayoutCapacity(layout)
+	if capacity <= 0 || current >= capacity {
+		return 0
+	}
+	if current < 0 {
+		current = 0
+	}
+	return capacity - current
+}
+
+// FitMinTileSize returns how many of count windows layout can tile in
+// monitor without a tile narrower than minWidth or shorter than minHeight;
+// the rest overflow. Fewer windows means a smaller grid with larger tiles.
+// A zero minimum is not checked, and at least one window is always tiled.
+func FitMinTileSize(count int, monitor Rect, layout *config.Layout, gapSize, minWidth, minHeight int) int {
+	if count <= 1 || (minWidth <= 0 && minHeight <= 0) {
+		return count
+	}
+	for n := count; n > 1; n-- {
+		positions, err := CalculatePositionsWithLayout(n, monitor, layout, gapSize)
+		if err == nil && tilesAtLeast(positions, minWidth, minHeight) {
+			return n
+		}
+	}
+	return 1
+}
+
+func tilesAtLeast(positions []Rect, minWidth, minHeight int) bool {
+	for _, pos := range positions {
+		if pos.Width < minWidth || pos.Height < minHeight {
+			return false
+		}
+	}
+	return true
+}
+
 // ApplyRegion applies the tile region to a monitor, returning adjusted bounds
 func ApplyRegion(monitor Rect, region config.TileRegion) Rect {
 	adjusted := monitor
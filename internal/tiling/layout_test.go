@@ -209,3 +209,447 @@ func TestApplyRegion_CustomClampsToMinimumSize(t *testing.T) {
 		t.Fatalf("expected 1x1, got %dx%d", adjusted.Width, adjusted.Height)
 	}
 }
+
+func TestCalculatePositionsWithLayout_LayoutGapOverride(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.GapSize = 4
+
+	gap := 20
+	withOverride := &config.Layout{
+		Mode:       config.LayoutModeHorizontal,
+		TileRegion: config.TileRegion{Type: config.RegionFull},
+		GapSize:    &gap,
+	}
+	withoutOverride := &config.Layout{
+		Mode:       config.LayoutModeHorizontal,
+		TileRegion: config.TileRegion{Type: config.RegionFull},
+	}
+	monitor := Rect{X: 0, Y: 0, Width: 1000, Height: 500}
+
+	positions, err := CalculatePositionsWithLayout(2, monitor, withOverride, cfg.LayoutGapSize(withOverride))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if positions[0].X != 20 || positions[0].Y != 20 {
+		t.Fatalf("expected override gap of 20 at origin, got (%d,%d)", positions[0].X, positions[0].Y)
+	}
+
+	positions, err = CalculatePositionsWithLayout(2, monitor, withoutOverride, cfg.LayoutGapSize(withoutOverride))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if positions[0].X != 4 || positions[0].Y != 4 {
+		t.Fatalf("expected global gap of 4 at origin, got (%d,%d)", positions[0].X, positions[0].Y)
+	}
+}
+
+func TestCalculatePositionsWithLayout_SingleWindowNoGap(t *testing.T) {
+	layout := &config.Layout{
+		Mode:              config.LayoutModeAuto,
+		TileRegion:        config.TileRegion{Type: config.RegionFull},
+		SingleWindowNoGap: true,
+	}
+	monitor := Rect{X: 100, Y: 50, Width: 1000, Height: 500}
+
+	positions, err := CalculatePositionsWithLayout(1, monitor, layout, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(positions) != 1 || positions[0] != monitor {
+		t.Fatalf("one window = %+v, want it to fill %+v", positions, monitor)
+	}
+
+	positions, err = CalculatePositionsWithLayout(2, monitor, layout, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Rect{
+		{X: 110, Y: 60, Width: 485, Height: 480},
+		{X: 605, Y: 60, Width: 485, Height: 480},
+	}
+	if len(positions) != 2 || positions[0] != want[0] || positions[1] != want[1] {
+		t.Fatalf("two windows = %+v, want gaps applied %+v", positions, want)
+	}
+
+	// A fixed grid keeps the lone window in its cell, without the gap.
+	fixed := &config.Layout{
+		Mode:              config.LayoutModeFixed,
+		TileRegion:        config.TileRegion{Type: config.RegionFull},
+		FixedGrid:         config.FixedGrid{Rows: 2, Cols: 2},
+		SingleWindowNoGap: true,
+	}
+	positions, err = CalculatePositionsWithLayout(1, monitor, fixed, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (Rect{X: 100, Y: 50, Width: 500, Height: 250}); len(positions) != 1 || positions[0] != want {
+		t.Fatalf("one window in fixed grid = %+v, want %+v", positions, want)
+	}
+
+	// Without the flag a lone window keeps its gap.
+	layout.SingleWindowNoGap = false
+	positions, err = CalculatePositionsWithLayout(1, monitor, layout, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (Rect{X: 110, Y: 60, Width: 980, Height: 480}); positions[0] != want {
+		t.Fatalf("one window without flag = %+v, want %+v", positions[0], want)
+	}
+}
+
+func TestFillCount(t *testing.T) {
+	fixed := &config.Layout{Mode: config.LayoutModeFixed, FixedGrid: config.FixedGrid{Rows: 2, Cols: 2}}
+	masterStack := &config.Layout{
+		Mode:        config.LayoutModeMasterStack,
+		MasterStack: config.MasterStack{MasterWidthPercent: 50, MaxStackRows: 3, MaxStackCols: 2},
+	}
+	docked := *fixed
+	docked.ReservedRegion = &config.TileRegion{Type: config.RegionBottomHalf}
+
+	tests := []struct {
+		name    string
+		layout  *config.Layout
+		current int
+		want    int
+	}{
+		{"fixed empty", fixed, 0, 4},
+		{"fixed half", fixed, 2, 2},
+		{"fixed full", fixed, 4, 0},
+		{"fixed overfull", fixed, 6, 0},
+		{"master-stack one", masterStack, 1, 6},
+		{"master-stack full", masterStack, 7, 0},
+		{"fixed with reserved region", &docked, 0, 5},
+		{"auto unbounded", &config.Layout{Mode: config.LayoutModeAuto}, 2, 0},
+		{"vertical unbounded", &config.Layout{Mode: config.LayoutModeVertical}, 2, 0},
+		{"horizontal unbounded", &config.Layout{Mode: config.LayoutModeHorizontal}, 2, 0},
+	}
+	for _, tt := range tests {
+		if got := FillCount(tt.layout, tt.current); got != tt.want {
+			t.Errorf("%s: FillCount(%d)=%d, want %d", tt.name, tt.current, got, tt.want)
+		}
+	}
+
+	// Capacity must match how many windows the layout actually places.
+	monitor := Rect{X: 0, Y: 0, Width: 1920, Height: 1080}
+	for _, layout := range []*config.Layout{fixed, masterStack, &docked} {
+		capacity := LayoutCapacity(layout)
+		positions, err := CalculatePositionsWithLayout(capacity+3, monitor, layout, 0)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", layout.Mode, err)
+		}
+		if len(positions) != capacity {
+			t.Fatalf("%s: placed %d windows, capacity %d", layout.Mode, len(positions), capacity)
+		}
+	}
+}
+
+func TestLayoutGrid(t *testing.T) {
+	fixed := &config.Layout{Mode: config.LayoutModeFixed, FixedGrid: config.FixedGrid{Rows: 3, Cols: 3}}
+	masterStack := &config.Layout{
+		Mode:        config.LayoutModeMasterStack,
+		MasterStack: config.MasterStack{MasterWidthPercent: 50, MaxStackRows: 3, MaxStackCols: 2},
+	}
+	docked := &config.Layout{
+		Mode:           config.LayoutModeFixed,
+		FixedGrid:      config.FixedGrid{Rows: 2, Cols: 2},
+		ReservedRegion: &config.TileRegion{Type: config.RegionBottomHalf},
+	}
+
+	tests := []struct {
+		name                   string
+		layout                 *config.Layout
+		count                  int
+		rows, cols, wantPlaced int
+	}{
+		{"auto 5", &config.Layout{Mode: config.LayoutModeAuto}, 5, 2, 3, 5},
+		{"auto 9", &config.Layout{Mode: config.LayoutModeAuto}, 9, 3, 3, 9},
+		{"auto none", &config.Layout{Mode: config.LayoutModeAuto}, 0, 0, 0, 0},
+		{"fixed under", fixed, 4, 3, 3, 4},
+		{"fixed over", fixed, 12, 3, 3, 9},
+		{"vertical", &config.Layout{Mode: config.LayoutModeVertical}, 4, 4, 1, 4},
+		{"horizontal", &config.Layout{Mode: config.LayoutModeHorizontal}, 3, 1, 3, 3},
+		{"master only", masterStack, 1, 0, 0, 1},
+		{"master + 2", masterStack, 3, 2, 1, 3},
+		{"master + 4", masterStack, 5, 2, 2, 5},
+		{"master over", masterStack, 10, 3, 2, 7},
+		{"reserved excluded", docked, 5, 2, 2, 5},
+		{"reserved over", docked, 8, 2, 2, 5},
+	}
+	for _, tt := range tests {
+		rows, cols, placed := LayoutGrid(tt.layout, tt.count)
+		if rows != tt.rows || cols != tt.cols || placed != tt.wantPlaced {
+			t.Errorf("%s: LayoutGrid(%d) = %dx%d placed %d, want %dx%d placed %d",
+				tt.name, tt.count, rows, cols, placed, tt.rows, tt.cols, tt.wantPlaced)
+		}
+	}
+}
+
+func TestCalculatePositionsWithLayout_ReservedBottomStrip(t *testing.T) {
+	layout := &config.Layout{
+		Mode:       config.LayoutModeHorizontal,
+		TileRegion: config.TileRegion{Type: config.RegionFull},
+		ReservedRegion: &config.TileRegion{
+			Type:          config.RegionCustom,
+			YPercent:      75,
+			WidthPercent:  100,
+			HeightPercent: 25,
+		},
+	}
+	monitor := Rect{X: 0, Y: 0, Width: 1000, Height: 800}
+
+	positions, err := CalculatePositionsWithLayout(3, monitor, layout, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Strip starts at y=600. Slot 0 fills it inside the outer gaps; the
+	// complement (0,0 1000x600) tiles two windows side by side, whose
+	// bottom gap separates them from the strip.
+	want := []Rect{
+		{X: 10, Y: 600, Width: 980, Height: 190},
+		{X: 10, Y: 10, Width: 485, Height: 580},
+		{X: 505, Y: 10, Width: 485, Height: 580},
+	}
+	if len(positions) != len(want) {
+		t.Fatalf("got %d positions, want %d", len(positions), len(want))
+	}
+	for i := range want {
+		if positions[i] != want[i] {
+			t.Errorf("pos%d = %+v, want %+v", i, positions[i], want[i])
+		}
+	}
+}
+
+func TestCalculatePositionsWithLayout_ReservedSlotOrder(t *testing.T) {
+	layout := &config.Layout{
+		Mode:           config.LayoutModeAuto,
+		TileRegion:     config.TileRegion{Type: config.RegionFull},
+		ReservedRegion: &config.TileRegion{Type: config.RegionLeftHalf},
+		ReservedSlot:   1,
+	}
+	monitor := Rect{X: 0, Y: 0, Width: 1000, Height: 800}
+
+	positions, err := CalculatePositionsWithLayout(3, monitor, layout, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Rect{
+		{X: 500, Y: 0, Width: 250, Height: 800},
+		{X: 0, Y: 0, Width: 500, Height: 800},
+		{X: 750, Y: 0, Width: 250, Height: 800},
+	}
+	for i := range want {
+		if positions[i] != want[i] {
+			t.Errorf("pos%d = %+v, want %+v", i, positions[i], want[i])
+		}
+	}
+
+	// With too few windows to reach the reserved slot, the strip stays
+	// empty and the rest still only uses the complement.
+	positions, err = CalculatePositionsWithLayout(1, monitor, layout, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(positions) != 1 || positions[0] != (Rect{X: 500, Y: 0, Width: 500, Height: 800}) {
+		t.Fatalf("positions = %+v, want one window in the right half", positions)
+	}
+}
+
+func TestCalculatePositionsWithLayout_Order(t *testing.T) {
+	// 6 windows tile a 2x3 grid of 90x90 cells with 10px gaps, so the cell
+	// at (row, col) starts at (10+col*100, 10+row*100).
+	monitor := Rect{X: 0, Y: 0, Width: 310, Height: 210}
+	type cell struct{ row, col int }
+	tests := []struct {
+		order config.TileOrder
+		want  []cell
+	}{
+		{"", []cell{{0, 0}, {0, 1}, {0, 2}, {1, 0}, {1, 1}, {1, 2}}},
+		{config.TileOrderRow, []cell{{0, 0}, {0, 1}, {0, 2}, {1, 0}, {1, 1}, {1, 2}}},
+		{config.TileOrderRowReverse, []cell{{0, 2}, {0, 1}, {0, 0}, {1, 2}, {1, 1}, {1, 0}}},
+		{config.TileOrderColumn, []cell{{0, 0}, {1, 0}, {0, 1}, {1, 1}, {0, 2}, {1, 2}}},
+		{config.TileOrderColumnReverse, []cell{{1, 0}, {0, 0}, {1, 1}, {0, 1}, {1, 2}, {0, 2}}},
+	}
+	for _, tt := range tests {
+		layout := &config.Layout{Mode: config.LayoutModeAuto, Order: tt.order}
+		positions, err := CalculatePositionsWithLayout(6, monitor, layout, 10)
+		if err != nil {
+			t.Fatalf("order %q: %v", tt.order, err)
+		}
+		for i, c := range tt.want {
+			want := Rect{X: 10 + c.col*100, Y: 10 + c.row*100, Width: 90, Height: 90}
+			if positions[i] != want {
+				t.Fatalf("order %q slot %d = %+v, want %+v", tt.order, i, positions[i], want)
+			}
+		}
+	}
+}
+
+func TestCalculatePositionsWithLayout_OrderFlexibleLastRow(t *testing.T) {
+	// 5 windows: 3 on the first row, 2 stretched across the second.
+	monitor := Rect{X: 0, Y: 0, Width: 310, Height: 210}
+
+	layout := &config.Layout{Mode: config.LayoutModeAuto, FlexibleLastRow: true, Order: config.TileOrderRowReverse}
+	positions, err := CalculatePositionsWithLayout(5, monitor, layout, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Last-row slots are (310-30)/2 = 140 wide; row-reverse fills right first.
+	if got, want := positions[3], (Rect{X: 160, Y: 110, Width: 140, Height: 90}); got != want {
+		t.Fatalf("slot 3 = %+v, want %+v", got, want)
+	}
+	if got, want := positions[4], (Rect{X: 10, Y: 110, Width: 140, Height: 90}); got != want {
+		t.Fatalf("slot 4 = %+v, want %+v", got, want)
+	}
+
+	// Column order leaves the gap in the last column instead; nothing stretches.
+	layout.Order = config.TileOrderColumn
+	positions, err = CalculatePositionsWithLayout(5, monitor, layout, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := positions[4], (Rect{X: 210, Y: 10, Width: 90, Height: 90}); got != want {
+		t.Fatalf("slot 4 = %+v, want %+v", got, want)
+	}
+}
+
+func TestCalculatePositionsWithLayout_RemainderBias(t *testing.T) {
+	// 3 columns of 90px slots at x = 10, 110, 210; a centered short row of
+	// two starts half a slot in, at x = 60.
+	monitor := Rect{X: 0, Y: 0, Width: 310, Height: 310}
+	type cell struct{ x, y int }
+
+	tests := []struct {
+		name   string
+		count  int
+		bias   config.RemainderBias
+		height int
+		want   []cell
+	}{
+		{"5 last-row", 5, config.RemainderBiasLastRow, 140,
+			[]cell{{10, 10}, {110, 10}, {210, 10}, {10, 160}, {110, 160}}},
+		{"5 default", 5, "", 140,
+			[]cell{{10, 10}, {110, 10}, {210, 10}, {10, 160}, {110, 160}}},
+		{"5 first-row", 5, config.RemainderBiasFirstRow, 140,
+			[]cell{{10, 10}, {110, 10}, {10, 160}, {110, 160}, {210, 160}}},
+		{"5 balanced", 5, config.RemainderBiasBalanced, 140,
+			[]cell{{10, 10}, {110, 10}, {210, 10}, {60, 160}, {160, 160}}},
+		{"7 last-row", 7, config.RemainderBiasLastRow, 90,
+			[]cell{{10, 10}, {110, 10}, {210, 10}, {10, 110}, {110, 110}, {210, 110}, {10, 210}}},
+		{"7 first-row", 7, config.RemainderBiasFirstRow, 90,
+			[]cell{{10, 10}, {10, 110}, {110, 110}, {210, 110}, {10, 210}, {110, 210}, {210, 210}}},
+		{"7 balanced", 7, config.RemainderBiasBalanced, 90,
+			[]cell{{10, 10}, {110, 10}, {210, 10}, {60, 110}, {160, 110}, {60, 210}, {160, 210}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := &config.Layout{Mode: config.LayoutModeAuto, RemainderBias: tt.bias}
+			positions, err := CalculatePositionsWithLayout(tt.count, monitor, layout, 10)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(positions) != len(tt.want) {
+				t.Fatalf("got %d positions, want %d", len(positions), len(tt.want))
+			}
+			for i, pos := range positions {
+				want := Rect{X: tt.want[i].x, Y: tt.want[i].y, Width: 90, Height: tt.height}
+				if pos != want {
+					t.Fatalf("slot %d = %+v, want %+v", i, pos, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCalculatePositionsWithLayout_RemainderBiasFlexibleAndReverse(t *testing.T) {
+	monitor := Rect{X: 0, Y: 0, Width: 310, Height: 310}
+
+	// first-row with flexible_last_row stretches the short top row.
+	layout := &config.Layout{Mode: config.LayoutModeAuto, RemainderBias: config.RemainderBiasFirstRow, FlexibleLastRow: true}
+	positions, err := CalculatePositionsWithLayout(7, monitor, layout, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := positions[0], (Rect{X: 10, Y: 10, Width: 290, Height: 90}); got != want {
+		t.Fatalf("slot 0 = %+v, want %+v", got, want)
+	}
+
+	// balanced with flexible_last_row stretches every short row.
+	layout.RemainderBias = config.RemainderBiasBalanced
+	positions, err = CalculatePositionsWithLayout(7, monitor, layout, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, want := range []Rect{
+		{X: 10, Y: 110, Width: 140, Height: 90},
+		{X: 160, Y: 110, Width: 140, Height: 90},
+		{X: 10, Y: 210, Width: 140, Height: 90},
+		{X: 160, Y: 210, Width: 140, Height: 90},
+	} {
+		if got := positions[i+3]; got != want {
+			t.Fatalf("slot %d = %+v, want %+v", i+3, got, want)
+		}
+	}
+
+	// row-reverse keeps a first-row remainder on the right.
+	layout = &config.Layout{Mode: config.LayoutModeAuto, RemainderBias: config.RemainderBiasFirstRow, Order: config.TileOrderRowReverse}
+	positions, err = CalculatePositionsWithLayout(5, monitor, layout, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := positions[0], (Rect{X: 210, Y: 10, Width: 90, Height: 140}); got != want {
+		t.Fatalf("slot 0 = %+v, want %+v", got, want)
+	}
+	if got, want := positions[1], (Rect{X: 110, Y: 10, Width: 90, Height: 140}); got != want {
+		t.Fatalf("slot 1 = %+v, want %+v", got, want)
+	}
+
+	// Column orders and non-auto modes ignore the bias.
+	layout = &config.Layout{Mode: config.LayoutModeAuto, RemainderBias: config.RemainderBiasBalanced, Order: config.TileOrderColumn}
+	positions, err = CalculatePositionsWithLayout(5, monitor, layout, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := positions[4], (Rect{X: 210, Y: 10, Width: 90, Height: 140}); got != want {
+		t.Fatalf("slot 4 = %+v, want %+v", got, want)
+	}
+	layout = &config.Layout{Mode: config.LayoutModeFixed, FixedGrid: config.FixedGrid{Rows: 2, Cols: 3}, RemainderBias: config.RemainderBiasFirstRow}
+	positions, err = CalculatePositionsWithLayout(4, monitor, layout, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := positions[3], (Rect{X: 10, Y: 160, Width: 90, Height: 140}); got != want {
+		t.Fatalf("slot 3 = %+v, want %+v", got, want)
+	}
+}
+
+func TestFitMinTileSize(t *testing.T) {
+	auto := &config.Layout{Mode: config.LayoutModeAuto}
+	tight := Rect{X: 0, Y: 0, Width: 1200, Height: 800}
+
+	tests := []struct {
+		name                string
+		count               int
+		minWidth, minHeight int
+		layout              *config.Layout
+		want                int
+	}{
+		{"no minimum", 20, 0, 0, auto, 20},
+		{"everything fits", 4, 500, 300, auto, 4},
+		// 12 windows need a 3x4 grid of 300x266 tiles; 400px wide tiles
+		// allow at most 3 columns, and 9 windows fill a 3x3 grid.
+		{"width caps the grid", 12, 400, 0, auto, 9},
+		// 266px tall tiles break a 300px minimum; 2 rows hold 6 windows in
+		// 3 columns of 400x400.
+		{"height caps the grid", 12, 0, 300, auto, 6},
+		{"region too small keeps one", 5, 2000, 0, auto, 1},
+		// A fixed grid's cells never grow, so only one window is kept.
+		{"fixed grid too small", 6, 700, 0, &config.Layout{Mode: config.LayoutModeFixed, FixedGrid: config.FixedGrid{Rows: 2, Cols: 3}}, 1},
+	}
+	for _, tt := range tests {
+		got := FitMinTileSize(tt.count, tight, tt.layout, 0, tt.minWidth, tt.minHeight)
+		if got != tt.want {
+			t.Fatalf("%s: FitMinTileSize = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package tiling
+
+import "time"
+
+// Metrics summarises the tiling operations the tiler has completed.
+type Metrics struct {
+	// TilesTotal counts successful tile operations since the tiler started.
+	TilesTotal uint64
+	// LastDuration is how long the most recent successful tile took.
+	LastDuration time.Duration
+	// LastTerminals is the number of terminals the most recent tile placed.
+	LastTerminals int
+	// LastTiledAt is when the most recent tile finished; zero before the
+	// first one.
+	LastTiledAt time.Time
+}
+
+// Metrics returns a snapshot of the tiling counters. It does not wait for a
+// tile in progress.
+func (t *Tiler) Metrics() Metrics {
+	t.metricsMu.Lock()
+	defer t.metricsMu.Unlock()
+	return t.metrics
+}
+
+// recordTile updates the counters after a successful tile that started at
+// start and placed terminalCount terminals.
+func (t *Tiler) recordTile(start time.Time, terminalCount int) {
+	now := time.Now()
+	t.metricsMu.Lock()
+	defer t.metricsMu.Unlock()
+	t.metrics.TilesTotal++
+	t.metrics.LastDuration = now.Sub(start)
+	t.metrics.LastTerminals = terminalCount
+	t.metrics.LastTiledAt = now
+}
+
+// tiledCountLocked returns how many terminals the last tile of displayID
+// recorded. The caller must hold t.mu.
+func (t *Tiler) tiledCountLocked(displayID int) int {
+	if ws, ok := t.workspaces[displayID]; ok {
+		return len(ws.Terminals)
+	}
+	return 0
+}
@@ -0,0 +1,144 @@
+package tiling
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/1broseidon/termtile/internal/config"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite testdata golden files")
+
+// positionsCase is one Positions call in the golden file: count windows in
+// the region of monitor, shaped by geometry.
+type positionsCase struct {
+	name     string
+	count    int
+	monitor  Rect
+	region   config.TileRegion
+	geometry Geometry
+}
+
+var (
+	goldenFHD    = Rect{X: 0, Y: 0, Width: 1920, Height: 1080}
+	goldenQHD    = Rect{X: 1920, Y: 0, Width: 2560, Height: 1440}
+	goldenFull   = config.TileRegion{Type: config.RegionFull}
+	goldenMaster = config.MasterStack{MasterWidthPercent: 50, MaxStackRows: 3, MaxStackCols: 2}
+)
+
+var positionsCases = []positionsCase{
+	{name: "auto-1-full", count: 1, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeAuto, Gap: 8}},
+	{name: "auto-1-single-window-no-gap", count: 1, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeAuto, Gap: 8, SingleWindowNoGap: true}},
+	{name: "auto-2-full", count: 2, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeAuto, Gap: 8}},
+	{name: "auto-3-full-no-gap", count: 3, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeAuto}},
+	{name: "auto-4-full", count: 4, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeAuto, Gap: 8}},
+	{name: "auto-5-flexible-last-row", count: 5, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeAuto, Gap: 8, FlexibleLastRow: true}},
+	{name: "auto-5-flexible-row-reverse", count: 5, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeAuto, Gap: 8, FlexibleLastRow: true, Order: config.TileOrderRowReverse}},
+	{name: "auto-5-column-order", count: 5, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeAuto, Gap: 8, FlexibleLastRow: true, Order: config.TileOrderColumn}},
+	{name: "auto-7-column-reverse", count: 7, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeAuto, Gap: 4, Order: config.TileOrderColumnReverse}},
+	{name: "auto-9-offset-monitor", count: 9, monitor: goldenQHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeAuto, Gap: 10}},
+	{name: "auto-4-max-tile-size", count: 4, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeAuto, Gap: 8, MaxTileWidth: 800, MaxTileHeight: 400}},
+	{name: "auto-3-flexible-max-width", count: 3, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeAuto, Gap: 8, FlexibleLastRow: true, MaxTileWidth: 1000}},
+	{name: "auto-4-left-half", count: 4, monitor: goldenFHD, region: config.TileRegion{Type: config.RegionLeftHalf}, geometry: Geometry{Mode: config.LayoutModeAuto, Gap: 8}},
+	{name: "auto-4-right-half-offset", count: 4, monitor: goldenQHD, region: config.TileRegion{Type: config.RegionRightHalf}, geometry: Geometry{Mode: config.LayoutModeAuto, Gap: 8}},
+	{name: "auto-2-top-half", count: 2, monitor: goldenFHD, region: config.TileRegion{Type: config.RegionTopHalf}, geometry: Geometry{Mode: config.LayoutModeAuto, Gap: 8}},
+	{name: "auto-3-bottom-half", count: 3, monitor: goldenFHD, region: config.TileRegion{Type: config.RegionBottomHalf}, geometry: Geometry{Mode: config.LayoutModeAuto, Gap: 8}},
+	{name: "auto-4-custom-region", count: 4, monitor: goldenFHD, region: config.TileRegion{Type: config.RegionCustom, XPercent: 10, YPercent: 5, WidthPercent: 80, HeightPercent: 90}, geometry: Geometry{Mode: config.LayoutModeAuto, Gap: 6}},
+	{name: "auto-5-first-row", count: 5, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeAuto, Gap: 8, RemainderBias: config.RemainderBiasFirstRow}},
+	{name: "auto-7-balanced", count: 7, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeAuto, Gap: 8, RemainderBias: config.RemainderBiasBalanced}},
+	{name: "auto-10-balanced-flexible-reverse", count: 10, monitor: goldenQHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeAuto, Gap: 8, RemainderBias: config.RemainderBiasBalanced, FlexibleLastRow: true, Order: config.TileOrderRowReverse}},
+	{name: "fixed-2x2-with-3", count: 3, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeFixed, Gap: 8, Grid: config.FixedGrid{Rows: 2, Cols: 2}}},
+	{name: "fixed-2x2-capped-at-4", count: 6, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeFixed, Gap: 8, Grid: config.FixedGrid{Rows: 2, Cols: 2}}},
+	{name: "fixed-1x3-column-order", count: 3, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeFixed, Gap: 8, Grid: config.FixedGrid{Rows: 1, Cols: 3}, Order: config.TileOrderColumn}},
+	{name: "vertical-3", count: 3, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeVertical, Gap: 8}},
+	{name: "vertical-4-left-half", count: 4, monitor: goldenFHD, region: config.TileRegion{Type: config.RegionLeftHalf}, geometry: Geometry{Mode: config.LayoutModeVertical, Gap: 0}},
+	{name: "horizontal-3", count: 3, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeHorizontal, Gap: 8}},
+	{name: "horizontal-5-offset", count: 5, monitor: goldenQHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeHorizontal, Gap: 12}},
+	{name: "master-stack-1", count: 1, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeMasterStack, Gap: 8, MasterStack: goldenMaster}},
+	{name: "master-stack-3", count: 3, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeMasterStack, Gap: 8, MasterStack: goldenMaster}},
+	{name: "master-stack-6", count: 6, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeMasterStack, Gap: 8, MasterStack: goldenMaster}},
+	{name: "master-stack-capped", count: 10, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeMasterStack, Gap: 8, MasterStack: goldenMaster}},
+	{name: "master-stack-60-column-order", count: 5, monitor: goldenQHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeMasterStack, Gap: 4, MasterStack: config.MasterStack{MasterWidthPercent: 60, MaxStackRows: 2, MaxStackCols: 2}, Order: config.TileOrderColumn}},
+	{name: "reserved-bottom-slot-0", count: 4, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeAuto, Gap: 8, Reserved: &config.TileRegion{Type: config.RegionBottomHalf}}},
+	{name: "reserved-right-strip-slot-2", count: 4, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeAuto, Gap: 8, Reserved: &config.TileRegion{Type: config.RegionCustom, XPercent: 75, YPercent: 0, WidthPercent: 25, HeightPercent: 100}, ReservedSlot: 2}},
+	{name: "reserved-top-strip-not-reached", count: 2, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeVertical, Gap: 8, Reserved: &config.TileRegion{Type: config.RegionCustom, XPercent: 0, YPercent: 0, WidthPercent: 100, HeightPercent: 20}, ReservedSlot: 3}},
+	{name: "reserved-left-strip-fixed-capped", count: 6, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeFixed, Gap: 8, Grid: config.FixedGrid{Rows: 1, Cols: 2}, Reserved: &config.TileRegion{Type: config.RegionLeftHalf}, ReservedSlot: 5}},
+	{name: "error-too-small", count: 4, monitor: Rect{Width: 20, Height: 20}, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeAuto, Gap: 8}},
+	{name: "master-stack-narrow", count: 3, monitor: Rect{Width: 40, Height: 300}, region: goldenFull, geometry: Geometry{Mode: config.LayoutModeMasterStack, Gap: 8, MasterStack: goldenMaster}},
+	{name: "error-unknown-mode", count: 2, monitor: goldenFHD, region: goldenFull, geometry: Geometry{Mode: "spiral", Gap: 8}},
+}
+
+func formatPositionsCase(c positionsCase) string {
+	var b strings.Builder
+	area := ApplyRegion(c.monitor, c.region)
+	fmt.Fprintf(&b, "== %s\n", c.name)
+	fmt.Fprintf(&b, "count=%d area=%d,%d %dx%d %s\n", c.count, area.X, area.Y, area.Width, area.Height, describeGeometry(c.geometry))
+	positions, err := Positions(c.count, area, c.geometry)
+	if err != nil {
+		fmt.Fprintf(&b, "error: %v\n", err)
+		return b.String()
+	}
+	for i, p := range positions {
+		fmt.Fprintf(&b, "%d: %d,%d %dx%d\n", i, p.X, p.Y, p.Width, p.Height)
+	}
+	return b.String()
+}
+
+// describeGeometry lists the fields of g that are set, so adding a field to
+// Geometry does not change the header of every case.
+func describeGeometry(g Geometry) string {
+	v := reflect.ValueOf(g)
+	var parts []string
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if f.IsZero() {
+			continue
+		}
+		if f.Kind() == reflect.Pointer {
+			f = f.Elem()
+		}
+		parts = append(parts, fmt.Sprintf("%s=%+v", v.Type().Field(i).Name, f.Interface()))
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestPositions_Golden(t *testing.T) {
+	var b strings.Builder
+	seen := make(map[string]bool)
+	for _, c := range positionsCases {
+		if seen[c.name] {
+			t.Fatalf("duplicate case name %q", c.name)
+		}
+		seen[c.name] = true
+		b.WriteString(formatPositionsCase(c))
+		b.WriteString("\n")
+	}
+	got := b.String()
+
+	path := filepath.Join("testdata", "positions.golden")
+	if *updateGolden {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("write golden: %v", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden (run 'go test ./internal/tiling -run TestPositions_Golden -update' to create it): %v", err)
+	}
+	if got != string(want) {
+		gotCases := strings.Split(got, "\n\n")
+		wantCases := strings.Split(string(want), "\n\n")
+		for i := 0; i < len(gotCases) && i < len(wantCases); i++ {
+			if gotCases[i] != wantCases[i] {
+				t.Fatalf("positions differ from %s:\n--- got\n%s\n--- want\n%s", path, gotCases[i], wantCases[i])
+			}
+		}
+		t.Fatalf("positions differ from %s: got %d cases, want %d", path, len(gotCases), len(wantCases))
+	}
+}
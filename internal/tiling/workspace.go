@@ -1,6 +1,7 @@
 package tiling
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"math"
@@ -19,6 +20,11 @@ import (
 // e.g. "termtile-my-agents-0" → "0"
 var sessionSlotRe = regexp.MustCompile(`^termtile-.*-(\d+)$`)
 
+// ErrNoTerminals is returned by the tiling entry points when no terminal
+// windows were found to tile. Nothing is moved; callers that tile
+// opportunistically can treat it as a no-op.
+var ErrNoTerminals = errors.New("no terminals to tile")
+
 // Workspace tracks the tiling state for a monitor
 type Workspace struct {
 	MonitorID          int
@@ -38,6 +44,22 @@ type Tiler struct {
 	previewID       int
 	previewTimer    *time.Timer
 	previewSnapshot map[platform.WindowID]Rect
+	// monocle holds, per monitor ID, the geometry captured when monocle was
+	// toggled on. A monitor is in monocle while it has an entry.
+	monocle map[int]map[platform.WindowID]Rect
+	// moveRevert holds, per monitor ID, the callback recorded with the last
+	// move-mode result. Undo runs it after restoring the geometry; tiling
+	// the monitor again drops it.
+	moveRevert map[int]func()
+
+	// metricsMu guards metrics on its own so readers never wait behind a
+	// tile holding mu.
+	metricsMu sync.Mutex
+	metrics   Metrics
+
+	// eventMu guards onEvent, the handler set with SetEventHandler.
+	eventMu sync.Mutex
+	onEvent func(Event)
 }
 
 // NewTiler creates a new tiler instance
@@ -48,18 +70,150 @@ func NewTiler(backend platform.Backend, detector *terminals.Detector, cfg *confi
 		config:       cfg,
 		activeLayout: cfg.DefaultLayout,
 		workspaces:   make(map[int]*Workspace),
+		monocle:      make(map[int]map[platform.WindowID]Rect),
+		moveRevert:   make(map[int]func()),
 	}
 }
 
-// TileCurrentMonitor tiles all terminals on the currently active monitor
+// TileCurrentMonitor tiles all terminals on the currently active monitor.
+// It returns ErrNoTerminals if the monitor has none.
 func (t *Tiler) TileCurrentMonitor() error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
 	t.cancelPreviewLocked()
 
+	start := time.Now()
 	log.Println("=== Starting tiling operation ===")
 
+	layout, err := t.activeLayoutLocked()
+	if err != nil {
+		return err
+	}
+
+	// Step 2: Get the active monitor
+	display, err := t.backend.ActiveDisplay()
+	if err != nil {
+		log.Printf("Failed to get active monitor: %v", err)
+		return err
+	}
+
+	previous := t.activeWindowForFocusLocked()
+	if err := t.tileDisplayLocked(display, layout); err != nil {
+		return err
+	}
+	t.applyFocusAfterTileLocked(display.ID, previous)
+	t.recordTile(start, t.tiledCountLocked(display.ID))
+	t.emit(Event{Kind: EventTiled, Layout: t.activeLayoutNameLocked(), MonitorID: display.ID, Terminals: t.tiledCountLocked(display.ID)})
+
+	log.Printf("=== Tiling completed successfully ===")
+	return nil
+}
+
+// TileAllMonitors tiles the terminals on every connected monitor with the
+// active layout. Each monitor keeps its own undo snapshot. A failure on one
+// monitor does not stop the others; all failures are returned together.
+// Monitors without terminals are skipped; ErrNoTerminals is returned only
+// when no monitor had any.
+func (t *Tiler) TileAllMonitors() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.cancelPreviewLocked()
+
+	start := time.Now()
+	log.Println("=== Starting tiling operation (all monitors) ===")
+
+	layout, err := t.activeLayoutLocked()
+	if err != nil {
+		return err
+	}
+
+	displays, err := t.backend.Displays()
+	if err != nil {
+		log.Printf("Failed to list monitors: %v", err)
+		return err
+	}
+
+	previous := t.activeWindowForFocusLocked()
+	var errs []error
+	tiled := 0
+	terminalCount := 0
+	for _, display := range displays {
+		err := t.tileDisplayLocked(display, layout)
+		switch {
+		case errors.Is(err, ErrNoTerminals):
+		case err != nil:
+			log.Printf("Failed to tile monitor %s: %v", display.Name, err)
+			errs = append(errs, fmt.Errorf("monitor %s: %w", display.Name, err))
+		default:
+			tiled++
+			terminalCount += t.tiledCountLocked(display.ID)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if tiled == 0 {
+		return ErrNoTerminals
+	}
+	if active, err := t.backend.ActiveDisplay(); err == nil {
+		t.applyFocusAfterTileLocked(active.ID, previous)
+	}
+	t.recordTile(start, terminalCount)
+	t.emit(Event{Kind: EventTiled, Layout: t.activeLayoutNameLocked(), MonitorID: -1, Terminals: terminalCount})
+
+	log.Printf("=== Tiling completed successfully (%d monitors) ===", len(displays))
+	return nil
+}
+
+// activeWindowForFocusLocked returns the window to restore focus to under
+// focus_after_tile "active", or 0 when the mode does not need it.
+func (t *Tiler) activeWindowForFocusLocked() platform.WindowID {
+	if t.config.FocusAfterTile != config.FocusAfterTileActive {
+		return 0
+	}
+	id, err := t.backend.ActiveWindow()
+	if err != nil {
+		log.Printf("Failed to get active window: %v", err)
+		return 0
+	}
+	return id
+}
+
+// applyFocusAfterTileLocked focuses the window selected by focus_after_tile
+// once the given monitor has been tiled.
+func (t *Tiler) applyFocusAfterTileLocked(monitorID int, previous platform.WindowID) {
+	target, ok := focusAfterTileTarget(t.config.FocusAfterTile, previous, t.workspaces[monitorID])
+	if !ok {
+		return
+	}
+	if err := t.backend.Focus(target); err != nil {
+		log.Printf("Warning: Failed to focus window %d after tiling: %v", target, err)
+	}
+}
+
+// focusAfterTileTarget picks the window to focus for mode. It returns false
+// when focus should be left alone.
+func focusAfterTileTarget(mode string, previous platform.WindowID, ws *Workspace) (platform.WindowID, bool) {
+	switch mode {
+	case config.FocusAfterTileFirst:
+		if ws == nil || len(ws.Terminals) == 0 {
+			return 0, false
+		}
+		return ws.Terminals[0].WindowID, true
+	case config.FocusAfterTileActive:
+		if previous == 0 {
+			return 0, false
+		}
+		return previous, true
+	default:
+		return 0, false
+	}
+}
+
+// activeLayoutLocked resolves the active layout, falling back to the default.
+func (t *Tiler) activeLayoutLocked() (*config.Layout, error) {
 	// Step 1: Get the active layout
 	layoutName := t.activeLayout
 	if layoutName == "" {
@@ -69,23 +223,21 @@ func (t *Tiler) TileCurrentMonitor() error {
 	layout, err := t.config.GetLayout(layoutName)
 	if err != nil {
 		log.Printf("Failed to get layout: %v", err)
-		return err
+		return nil, err
 	}
 	log.Printf("Using layout: %s (mode: %s, region: %s)", layoutName, layout.Mode, layout.TileRegion.Type)
+	return layout, nil
+}
 
-	// Step 2: Get the active monitor
-	display, err := t.backend.ActiveDisplay()
-	if err != nil {
-		log.Printf("Failed to get active monitor: %v", err)
-		return err
-	}
-
+// tileDisplayLocked tiles the terminals on a single display and records the
+// pre-tiling geometry for that display's undo.
+func (t *Tiler) tileDisplayLocked(display platform.Display, layout *config.Layout) error {
 	bounds := display.Bounds
-	log.Printf("Active monitor: %s (%dx%d at %d,%d)",
+	log.Printf("Monitor: %s (%dx%d at %d,%d)",
 		display.Name, bounds.Width, bounds.Height, bounds.X, bounds.Y)
 
 	// Apply screen padding to create a safe area
-	padding := t.config.ScreenPadding
+	padding := t.config.GetScreenPadding(display.Name)
 	if padding.Top != 0 || padding.Bottom != 0 || padding.Left != 0 || padding.Right != 0 {
 		log.Printf("Applying screen padding: top=%d, bottom=%d, left=%d, right=%d",
 			padding.Top, padding.Bottom, padding.Left, padding.Right)
@@ -119,8 +271,14 @@ func (t *Tiler) TileCurrentMonitor() error {
 		)
 	}
 
-	// Step 4: Find all terminals on this monitor
-	terminalWindows, err := t.detector.FindTerminals(t.backend, display.ID, bounds)
+	// Step 4: Find all terminals on this monitor, in slot order.
+	// Master-stack sorts by session slot so agent-0 is always master.
+	// The slot number is parsed from the tmux session name in the window title.
+	sortMode := t.config.TerminalSort
+	if layout.Mode == config.LayoutModeMasterStack {
+		sortMode = "session_slot"
+	}
+	terminalWindows, focused, err := t.findTileWindowsLocked(display.ID, bounds, sortMode)
 	if err != nil {
 		log.Printf("Failed to find terminals: %v", err)
 		return err
@@ -130,19 +288,14 @@ func (t *Tiler) TileCurrentMonitor() error {
 
 	if len(terminalWindows) == 0 {
 		log.Println("No terminals to tile")
-		return nil
+		return ErrNoTerminals
 	}
 
-	// Master-stack sorts by session slot so agent-0 is always master.
-	// The slot number is parsed from the tmux session name in the window title.
-	sortMode := t.config.TerminalSort
-	if layout.Mode == config.LayoutModeMasterStack {
-		sortMode = "session_slot"
-	}
-	sortTerminals(t.backend, terminalWindows, sortMode)
+	terminalWindows = t.overflowLocked(terminalWindows, focused, adjustedMonitor, layout)
+	tileWindows := withFocused(terminalWindows, focused)
 
-	previous := make(map[platform.WindowID]Rect, len(terminalWindows))
-	for _, term := range terminalWindows {
+	previous := make(map[platform.WindowID]Rect, len(tileWindows))
+	for _, term := range tileWindows {
 		previous[term.WindowID] = Rect{
 			X:      term.X,
 			Y:      term.Y,
@@ -152,16 +305,16 @@ func (t *Tiler) TileCurrentMonitor() error {
 	}
 
 	// Log detected terminals
-	for i, term := range terminalWindows {
+	for i, term := range tileWindows {
 		log.Printf("  Terminal %d: %s (ID: %d, title: %s)", i+1, term.Class, term.WindowID, term.Title)
 	}
 
 	// Step 5: Calculate positions using layout
 	positions, err := CalculatePositionsWithLayout(
-		len(terminalWindows),
+		len(tileWindows),
 		adjustedMonitor,
 		layout,
-		t.config.GapSize,
+		t.config.LayoutGapSize(layout),
 	)
 	if err != nil {
 		return err
@@ -171,19 +324,20 @@ func (t *Tiler) TileCurrentMonitor() error {
 	var rows, cols int
 	switch layout.Mode {
 	case config.LayoutModeAuto:
-		rows, cols = CalculateGrid(len(terminalWindows))
+		rows, cols = CalculateGrid(len(tileWindows))
 	case config.LayoutModeFixed:
 		rows, cols = layout.FixedGrid.Rows, layout.FixedGrid.Cols
 	case config.LayoutModeVertical:
-		rows, cols = len(terminalWindows), 1
+		rows, cols = len(tileWindows), 1
 	case config.LayoutModeHorizontal:
-		rows, cols = 1, len(terminalWindows)
+		rows, cols = 1, len(tileWindows)
 	}
 	log.Printf("Layout: %dx%d grid (%s mode) with %dpx gaps",
-		rows, cols, layout.Mode, t.config.GapSize)
+		rows, cols, layout.Mode, t.config.LayoutGapSize(layout))
 
 	// Step 6: Move and resize each terminal
-	for i, term := range terminalWindows {
+	moves := make([]platform.WindowRect, 0, len(tileWindows))
+	for i, term := range tileWindows {
 		if i >= len(positions) {
 			log.Printf("Skipping terminal %d (exceeds layout capacity)", i+1)
 			continue
@@ -216,18 +370,21 @@ func (t *Tiler) TileCurrentMonitor() error {
 			continue
 		}
 
-		err := t.backend.MoveResize(
-			term.WindowID,
-			platform.Rect{X: adjustedPos.X, Y: adjustedPos.Y, Width: adjustedPos.Width, Height: adjustedPos.Height},
-		)
+		moves = append(moves, platform.WindowRect{
+			WindowID: term.WindowID,
+			Bounds:   platform.Rect{X: adjustedPos.X, Y: adjustedPos.Y, Width: adjustedPos.Width, Height: adjustedPos.Height},
+		})
+	}
 
-		if err != nil {
-			log.Printf("Warning: Failed to tile terminal %d: %v", i+1, err)
-			// Continue with other windows even if one fails
-		}
+	// Issue all moves together; failures are per-window and never abort the rest.
+	if err := t.backend.MoveResizeBatch(moves); err != nil {
+		log.Printf("Warning: Failed to tile some terminals: %v", err)
 	}
 
-	// Step 7: Update workspace state
+	// Step 7: Update workspace state. Re-tiling ends monocle on this display
+	// and replaces any recorded move as the thing undo reverts.
+	delete(t.monocle, display.ID)
+	delete(t.moveRevert, display.ID)
 	t.workspaces[display.ID] = &Workspace{
 		MonitorID:          display.ID,
 		Terminals:          terminalWindows,
@@ -235,10 +392,90 @@ func (t *Tiler) TileCurrentMonitor() error {
 		PreviousGeometries: previous,
 	}
 
-	log.Printf("=== Tiling completed successfully ===")
 	return nil
 }
 
+// findTileWindowsLocked lists the windows a tile places on a display: its
+// terminals sorted by sortMode and, when tile_ignores_non_terminals is false,
+// the focused window if it is a non-terminal on that display (nil
+// otherwise). That window is tiled after the terminals so it never shifts a
+// terminal's slot index, which agent sessions are named after.
+func (t *Tiler) findTileWindowsLocked(displayID int, bounds platform.Rect, sortMode string) ([]terminals.TerminalWindow, *terminals.TerminalWindow, error) {
+	terms, err := t.detector.FindTerminals(t.backend, displayID, bounds)
+	if err != nil {
+		return nil, nil, err
+	}
+	sortTerminals(t.backend, terms, sortMode)
+	return terms, t.focusedNonTerminalLocked(displayID, bounds, terms), nil
+}
+
+// focusedNonTerminalLocked returns the focused window when it is a
+// non-terminal on the display and tile_ignores_non_terminals is false. It
+// only joins a tile that has terminals.
+func (t *Tiler) focusedNonTerminalLocked(displayID int, bounds platform.Rect, terms []terminals.TerminalWindow) *terminals.TerminalWindow {
+	if t.config.TileIgnoresNonTerminals || len(terms) == 0 {
+		return nil
+	}
+
+	active, err := t.backend.ActiveWindow()
+	if err != nil || active == 0 {
+		return nil
+	}
+	for _, term := range terms {
+		if term.WindowID == active {
+			return nil
+		}
+	}
+	windows, err := t.detector.FindWindows(t.backend, displayID, bounds)
+	if err != nil {
+		return nil
+	}
+	for i, w := range windows {
+		if w.WindowID == active {
+			log.Printf("Including focused non-terminal window %d (%s) in tiling", w.WindowID, w.Class)
+			return &windows[i]
+		}
+	}
+	return nil
+}
+
+// withFocused returns terms followed by focused, when there is one.
+func withFocused(terms []terminals.TerminalWindow, focused *terminals.TerminalWindow) []terminals.TerminalWindow {
+	if focused == nil {
+		return terms
+	}
+	out := make([]terminals.TerminalWindow, 0, len(terms)+1)
+	out = append(out, terms...)
+	return append(out, *focused)
+}
+
+// overflowLocked keeps the terminals that layout can tile in area without
+// breaking min_tile_width/min_tile_height and minimizes the rest, so a
+// crowded monitor gets fewer usable tiles instead of unusably small ones.
+// A focused non-terminal joining the tile keeps its tile; terminals are
+// minimized to make room for it.
+func (t *Tiler) overflowLocked(terms []terminals.TerminalWindow, focused *terminals.TerminalWindow, area Rect, layout *config.Layout) []terminals.TerminalWindow {
+	reserved := 0
+	if focused != nil {
+		reserved = 1
+	}
+	fit := FitMinTileSize(len(terms)+reserved, area, layout, t.config.LayoutGapSize(layout), t.config.MinTileWidth, t.config.MinTileHeight) - reserved
+	if fit >= len(terms) {
+		return terms
+	}
+	if fit < 0 {
+		fit = 0
+	}
+	log.Printf("Minimum tile size %dx%d fits %d of %d terminal(s); minimizing the rest",
+		t.config.MinTileWidth, t.config.MinTileHeight, fit, len(terms))
+	for _, term := range terms[fit:] {
+		if err := t.backend.Minimize(term.WindowID); err != nil {
+			log.Printf("Warning: failed to minimize overflow terminal %d: %v", term.WindowID, err)
+		}
+	}
+	return terms[:fit]
+}
+
 // TileWithOrder tiles terminals using a specific window order instead of sorting by position.
 // This is used by workspace load to ensure windows end up in the correct slots.
 func (t *Tiler) TileWithOrder(windowOrder []uint32) error {
@@ -247,6 +484,7 @@ func (t *Tiler) TileWithOrder(windowOrder []uint32) error {
 
 	t.cancelPreviewLocked()
 
+	start := time.Now()
 	log.Println("=== Starting ordered tiling operation ===")
 
 	// Step 1: Get the active layout
@@ -268,13 +506,14 @@ func (t *Tiler) TileWithOrder(windowOrder []uint32) error {
 		log.Printf("Failed to get active monitor: %v", err)
 		return err
 	}
+	focusPrevious := t.activeWindowForFocusLocked()
 
 	bounds := display.Bounds
 	log.Printf("Active monitor: %s (%dx%d at %d,%d)",
 		display.Name, bounds.Width, bounds.Height, bounds.X, bounds.Y)
 
 	// Apply screen padding to create a safe area
-	padding := t.config.ScreenPadding
+	padding := t.config.GetScreenPadding(display.Name)
 	if padding.Top != 0 || padding.Bottom != 0 || padding.Left != 0 || padding.Right != 0 {
 		log.Printf("Applying screen padding: top=%d, bottom=%d, left=%d, right=%d",
 			padding.Top, padding.Bottom, padding.Left, padding.Right)
@@ -320,7 +559,7 @@ func (t *Tiler) TileWithOrder(windowOrder []uint32) error {
 
 	if len(terminalWindows) == 0 {
 		log.Println("No terminals to tile")
-		return nil
+		return ErrNoTerminals
 	}
 
 	// Build a map of window ID to terminal for quick lookup.
@@ -358,9 +597,12 @@ func (t *Tiler) TileWithOrder(windowOrder []uint32) error {
 	if extra > 0 {
 		log.Printf("Added %d extra terminals not in provided order (preserving detector order)", extra)
 	}
+	focused := t.focusedNonTerminalLocked(display.ID, bounds, orderedTerminals)
+	orderedTerminals = t.overflowLocked(orderedTerminals, focused, adjustedMonitor, layout)
+	tileWindows := withFocused(orderedTerminals, focused)
 
-	previous := make(map[platform.WindowID]Rect, len(orderedTerminals))
-	for _, term := range orderedTerminals {
+	previous := make(map[platform.WindowID]Rect, len(tileWindows))
+	for _, term := range tileWindows {
 		previous[term.WindowID] = Rect{
 			X:      term.X,
 			Y:      term.Y,
@@ -370,23 +612,24 @@ func (t *Tiler) TileWithOrder(windowOrder []uint32) error {
 	}
 
 	// Log ordered terminals
-	for i, term := range orderedTerminals {
+	for i, term := range tileWindows {
 		log.Printf("  Terminal %d: %s (ID: %d)", i+1, term.Class, term.WindowID)
 	}
 
 	// Step 5: Calculate positions using layout
 	positions, err := CalculatePositionsWithLayout(
-		len(orderedTerminals),
+		len(tileWindows),
 		adjustedMonitor,
 		layout,
-		t.config.GapSize,
+		t.config.LayoutGapSize(layout),
 	)
 	if err != nil {
 		return err
 	}
 
 	// Step 6: Move and resize each terminal
-	for i, term := range orderedTerminals {
+	moves := make([]platform.WindowRect, 0, len(tileWindows))
+	for i, term := range tileWindows {
 		if i >= len(positions) {
 			log.Printf("Skipping terminal %d (exceeds layout capacity)", i+1)
 			continue
@@ -419,30 +662,47 @@ func (t *Tiler) TileWithOrder(windowOrder []uint32) error {
 			continue
 		}
 
-		err := t.backend.MoveResize(
-			term.WindowID,
-			platform.Rect{X: adjustedPos.X, Y: adjustedPos.Y, Width: adjustedPos.Width, Height: adjustedPos.Height},
-		)
+		moves = append(moves, platform.WindowRect{
+			WindowID: term.WindowID,
+			Bounds:   platform.Rect{X: adjustedPos.X, Y: adjustedPos.Y, Width: adjustedPos.Width, Height: adjustedPos.Height},
+		})
+	}
 
-		if err != nil {
-			log.Printf("Warning: Failed to tile terminal %d: %v", i+1, err)
-		}
+	// Issue all moves together; failures are per-window and never abort the rest.
+	if err := t.backend.MoveResizeBatch(moves); err != nil {
+		log.Printf("Warning: Failed to tile some terminals: %v", err)
 	}
 
-	// Step 7: Update workspace state
+	// Step 7: Update workspace state. Re-tiling ends monocle on this display
+	// and replaces any recorded move as the thing undo reverts.
+	delete(t.monocle, display.ID)
+	delete(t.moveRevert, display.ID)
 	t.workspaces[display.ID] = &Workspace{
 		MonitorID:          display.ID,
 		Terminals:          orderedTerminals,
 		LastTiledAt:        time.Now(),
 		PreviousGeometries: previous,
 	}
+	t.applyFocusAfterTileLocked(display.ID, focusPrevious)
+	t.recordTile(start, len(orderedTerminals))
+	t.emit(Event{Kind: EventTiled, Layout: layoutName, MonitorID: display.ID, Terminals: len(orderedTerminals)})
 
 	log.Printf("=== Ordered tiling completed successfully ===")
 	return nil
 }
 
 // UndoCurrentMonitor restores terminal windows to the geometry captured before the last tiling operation.
+// When the last operation was a move recorded with RecordMove, its revert
+// callback runs after the geometry is restored.
 func (t *Tiler) UndoCurrentMonitor() error {
+	revert, err := t.undoCurrentMonitor()
+	if revert != nil {
+		revert()
+	}
+	return err
+}
+
+func (t *Tiler) undoCurrentMonitor() (func(), error) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
@@ -450,16 +710,132 @@ func (t *Tiler) UndoCurrentMonitor() error {
 
 	display, err := t.backend.ActiveDisplay()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	ws := t.workspaces[display.ID]
 	if ws == nil || len(ws.PreviousGeometries) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	t.restoreWindowsLocked(ws.PreviousGeometries)
 	ws.PreviousGeometries = nil
+	delete(t.monocle, display.ID)
+
+	revert := t.moveRevert[display.ID]
+	delete(t.moveRevert, display.ID)
+	return revert, nil
+}
+
+// RecordMove makes a move-mode result the operation undo reverts on
+// displayID: previous is the geometry of the moved windows before the move,
+// and revert (optional) is called after undo restores it, without the tiler
+// lock held.
+func (t *Tiler) RecordMove(displayID int, previous map[platform.WindowID]Rect, revert func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(previous) == 0 {
+		return
+	}
+	snapshot := make(map[platform.WindowID]Rect, len(previous))
+	for windowID, rect := range previous {
+		snapshot[windowID] = rect
+	}
+
+	ws := t.workspaces[displayID]
+	if ws == nil {
+		ws = &Workspace{MonitorID: displayID}
+		t.workspaces[displayID] = ws
+	}
+	ws.PreviousGeometries = snapshot
+	if revert != nil {
+		t.moveRevert[displayID] = revert
+	} else {
+		delete(t.moveRevert, displayID)
+	}
+}
+
+// ToggleMonocleCurrentMonitor toggles monocle on the active monitor. Turning
+// it on snapshots the geometry of every terminal on the monitor, then resizes
+// the focused terminal to fill the active layout's tile region and raises it
+// over the others. Turning it off restores the snapshot. It returns
+// ErrNoTerminals if the monitor has none.
+func (t *Tiler) ToggleMonocleCurrentMonitor() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.cancelPreviewLocked()
+
+	display, err := t.backend.ActiveDisplay()
+	if err != nil {
+		return err
+	}
+
+	if snapshot, ok := t.monocle[display.ID]; ok {
+		log.Printf("Monocle off on monitor %s", display.Name)
+		t.restoreWindowsLocked(snapshot)
+		delete(t.monocle, display.ID)
+		return nil
+	}
+
+	layout, err := t.activeLayoutLocked()
+	if err != nil {
+		return err
+	}
+	bounds, region, err := t.tileAreaLocked(display, layout)
+	if err != nil {
+		return err
+	}
+
+	terminalWindows, err := t.detector.FindTerminals(t.backend, display.ID, bounds)
+	if err != nil {
+		return err
+	}
+	if len(terminalWindows) == 0 {
+		return ErrNoTerminals
+	}
+
+	active, err := t.backend.ActiveWindow()
+	if err != nil {
+		return err
+	}
+	var focused *terminals.TerminalWindow
+	snapshot := make(map[platform.WindowID]Rect, len(terminalWindows))
+	for i, term := range terminalWindows {
+		snapshot[term.WindowID] = Rect{
+			X:      term.X,
+			Y:      term.Y,
+			Width:  term.Width,
+			Height: term.Height,
+		}
+		if term.WindowID == active {
+			focused = &terminalWindows[i]
+		}
+	}
+	if focused == nil {
+		return fmt.Errorf("focused window %d is not a terminal on monitor %s", active, display.Name)
+	}
+
+	margins := t.config.GetMargins(focused.Class)
+	full := platform.Rect{
+		X:      region.X + margins.Left,
+		Y:      region.Y + margins.Top,
+		Width:  region.Width - margins.Left - margins.Right,
+		Height: region.Height - margins.Top - margins.Bottom,
+	}
+	if full.Width < 1 || full.Height < 1 {
+		return fmt.Errorf("margins for %s leave no usable space: %dx%d", focused.Class, full.Width, full.Height)
+	}
+
+	log.Printf("Monocle on for window %d on monitor %s", focused.WindowID, display.Name)
+	if err := t.backend.MoveResize(focused.WindowID, full); err != nil {
+		return err
+	}
+	if err := t.backend.Focus(focused.WindowID); err != nil {
+		log.Printf("Warning: Failed to raise window %d for monocle: %v", focused.WindowID, err)
+	}
+	t.monocle[display.ID] = snapshot
 	return nil
 }
 
@@ -472,58 +848,43 @@ func (t *Tiler) PreviewLayout(layoutName string, duration time.Duration) error {
 		duration = 3 * time.Second
 	}
 
-	// If a previous preview is active, restore it first.
-	if t.previewTimer != nil {
-		t.previewTimer.Stop()
-		t.previewTimer = nil
-		if t.previewSnapshot != nil {
-			t.restoreWindowsLocked(t.previewSnapshot)
-		}
-		t.previewSnapshot = nil
-	}
+	t.endPreviewLocked()
 
 	layout, err := t.config.GetLayout(layoutName)
 	if err != nil {
 		return err
 	}
 
-	display, err := t.backend.ActiveDisplay()
-	if err != nil {
+	return t.previewLayoutLocked(layout, duration)
+}
+
+// PreviewLayoutDefinition is like PreviewLayout but takes an ad-hoc layout
+// that is not part of the config, e.g. one read from a file for testing.
+func (t *Tiler) PreviewLayoutDefinition(layout config.Layout, duration time.Duration) error {
+	if err := config.ValidateLayout(&layout); err != nil {
 		return err
 	}
 
-	bounds := display.Bounds
+	t.mu.Lock()
+	defer t.mu.Unlock()
 
-	// Apply screen padding to create a safe area
-	padding := t.config.ScreenPadding
-	if padding.Top != 0 || padding.Bottom != 0 || padding.Left != 0 || padding.Right != 0 {
-		bounds.X += padding.Left
-		bounds.Y += padding.Top
-		bounds.Width -= (padding.Left + padding.Right)
-		bounds.Height -= (padding.Top + padding.Bottom)
-		if bounds.Width < 1 || bounds.Height < 1 {
-			return fmt.Errorf(
-				"screen_padding leaves no usable space: %dx%d at %d,%d",
-				bounds.Width, bounds.Height, bounds.X, bounds.Y,
-			)
-		}
+	if duration <= 0 {
+		duration = 3 * time.Second
 	}
+	t.endPreviewLocked()
 
-	monitorRect := rectFromPlatform(bounds)
-	adjustedMonitor := ApplyRegion(monitorRect, layout.TileRegion)
-	if adjustedMonitor.Width < 1 || adjustedMonitor.Height < 1 {
-		return fmt.Errorf(
-			"tile_region leaves no usable space: %dx%d at %d,%d",
-			adjustedMonitor.Width, adjustedMonitor.Height, adjustedMonitor.X, adjustedMonitor.Y,
-		)
-	}
+	return t.previewLayoutLocked(&layout, duration)
+}
 
-	terminalWindows, err := t.detector.FindTerminals(t.backend, display.ID, bounds)
+func (t *Tiler) previewLayoutLocked(layout *config.Layout, duration time.Duration) error {
+	display, err := t.backend.ActiveDisplay()
 	if err != nil {
 		return err
 	}
-	if len(terminalWindows) == 0 {
-		return nil
+
+	bounds, adjustedMonitor, err := t.tileAreaLocked(display, layout)
+	if err != nil {
+		return err
 	}
 
 	// Master-stack sorts by session slot so agent-0 is always master.
@@ -531,7 +892,14 @@ func (t *Tiler) PreviewLayout(layoutName string, duration time.Duration) error {
 	if layout.Mode == config.LayoutModeMasterStack {
 		sortMode = "session_slot"
 	}
-	sortTerminals(t.backend, terminalWindows, sortMode)
+	terms, focused, err := t.findTileWindowsLocked(display.ID, bounds, sortMode)
+	if err != nil {
+		return err
+	}
+	if len(terms) == 0 {
+		return nil
+	}
+	terminalWindows := withFocused(terms, focused)
 
 	snapshot := make(map[platform.WindowID]Rect, len(terminalWindows))
 	for _, term := range terminalWindows {
@@ -547,12 +915,13 @@ func (t *Tiler) PreviewLayout(layoutName string, duration time.Duration) error {
 		len(terminalWindows),
 		adjustedMonitor,
 		layout,
-		t.config.GapSize,
+		t.config.LayoutGapSize(layout),
 	)
 	if err != nil {
 		return err
 	}
 
+	moves := make([]platform.WindowRect, 0, len(terminalWindows))
 	for i, term := range terminalWindows {
 		if i >= len(positions) {
 			continue
@@ -570,11 +939,12 @@ func (t *Tiler) PreviewLayout(layoutName string, duration time.Duration) error {
 			continue
 		}
 
-		_ = t.backend.MoveResize(
-			term.WindowID,
-			platform.Rect{X: adjustedPos.X, Y: adjustedPos.Y, Width: adjustedPos.Width, Height: adjustedPos.Height},
-		)
+		moves = append(moves, platform.WindowRect{
+			WindowID: term.WindowID,
+			Bounds:   platform.Rect{X: adjustedPos.X, Y: adjustedPos.Y, Width: adjustedPos.Width, Height: adjustedPos.Height},
+		})
 	}
+	_ = t.backend.MoveResizeBatch(moves)
 
 	t.previewID++
 	previewID := t.previewID
@@ -599,6 +969,50 @@ func (t *Tiler) PreviewLayout(layoutName string, duration time.Duration) error {
 	return nil
 }
 
+// tileAreaLocked returns the display bounds after screen padding and the
+// layout's tile region within them.
+func (t *Tiler) tileAreaLocked(display platform.Display, layout *config.Layout) (platform.Rect, Rect, error) {
+	bounds := display.Bounds
+
+	// Apply screen padding to create a safe area
+	padding := t.config.GetScreenPadding(display.Name)
+	if padding.Top != 0 || padding.Bottom != 0 || padding.Left != 0 || padding.Right != 0 {
+		bounds.X += padding.Left
+		bounds.Y += padding.Top
+		bounds.Width -= (padding.Left + padding.Right)
+		bounds.Height -= (padding.Top + padding.Bottom)
+		if bounds.Width < 1 || bounds.Height < 1 {
+			return platform.Rect{}, Rect{}, fmt.Errorf(
+				"screen_padding leaves no usable space: %dx%d at %d,%d",
+				bounds.Width, bounds.Height, bounds.X, bounds.Y,
+			)
+		}
+	}
+
+	region := ApplyRegion(rectFromPlatform(bounds), layout.TileRegion)
+	if region.Width < 1 || region.Height < 1 {
+		return platform.Rect{}, Rect{}, fmt.Errorf(
+			"tile_region leaves no usable space: %dx%d at %d,%d",
+			region.Width, region.Height, region.X, region.Y,
+		)
+	}
+	return bounds, region, nil
+}
+
+// endPreviewLocked restores the windows moved by an active preview, if any.
+func (t *Tiler) endPreviewLocked() {
+	if t.previewTimer == nil {
+		return
+	}
+
+	t.previewTimer.Stop()
+	t.previewTimer = nil
+	if t.previewSnapshot != nil {
+		t.restoreWindowsLocked(t.previewSnapshot)
+	}
+	t.previewSnapshot = nil
+}
+
 func (t *Tiler) cancelPreviewLocked() {
 	if t.previewTimer == nil {
 		return
@@ -610,9 +1024,14 @@ func (t *Tiler) cancelPreviewLocked() {
 }
 
 func (t *Tiler) restoreWindowsLocked(snapshot map[platform.WindowID]Rect) {
+	moves := make([]platform.WindowRect, 0, len(snapshot))
 	for windowID, rect := range snapshot {
-		_ = t.backend.MoveResize(windowID, platform.Rect{X: rect.X, Y: rect.Y, Width: rect.Width, Height: rect.Height})
+		moves = append(moves, platform.WindowRect{
+			WindowID: windowID,
+			Bounds:   platform.Rect{X: rect.X, Y: rect.Y, Width: rect.Width, Height: rect.Height},
+		})
 	}
+	_ = t.backend.MoveResizeBatch(moves)
 }
 
 // parseSessionSlot extracts the slot number from a termtile tmux session title.
@@ -629,54 +1048,21 @@ func parseSessionSlot(title string) int {
 	return n
 }
 
-func sortTerminals(backend platform.Backend, terminals []terminals.TerminalWindow, mode string) {
+func sortTerminals(backend platform.Backend, windows []terminals.TerminalWindow, mode string) {
 	switch mode {
-	case "client_list":
-		return
 	case "session_slot":
-		sort.SliceStable(terminals, func(i, j int) bool {
-			si, sj := parseSessionSlot(terminals[i].Title), parseSessionSlot(terminals[j].Title)
+		sort.SliceStable(windows, func(i, j int) bool {
+			si, sj := parseSessionSlot(windows[i].Title), parseSessionSlot(windows[j].Title)
 			if si != sj {
 				return si < sj
 			}
-			return terminals[i].WindowID < terminals[j].WindowID
-		})
-	case "window_id":
-		sort.Slice(terminals, func(i, j int) bool {
-			return terminals[i].WindowID < terminals[j].WindowID
+			return windows[i].WindowID < windows[j].WindowID
 		})
 	case "active_first":
 		activeWin, _ := backend.ActiveWindow()
-		sort.SliceStable(terminals, func(i, j int) bool {
-			ti, tj := terminals[i], terminals[j]
-			if activeWin != 0 {
-				if ti.WindowID == activeWin && tj.WindowID != activeWin {
-					return true
-				}
-				if tj.WindowID == activeWin && ti.WindowID != activeWin {
-					return false
-				}
-			}
-
-			if ti.Y != tj.Y {
-				return ti.Y < tj.Y
-			}
-			if ti.X != tj.X {
-				return ti.X < tj.X
-			}
-			return ti.WindowID < tj.WindowID
-		})
+		terminals.SortWindows(windows, mode, activeWin, terminals.TerminalWindow.SortKey)
 	default:
-		sort.Slice(terminals, func(i, j int) bool {
-			ti, tj := terminals[i], terminals[j]
-			if ti.Y != tj.Y {
-				return ti.Y < tj.Y
-			}
-			if ti.X != tj.X {
-				return ti.X < tj.X
-			}
-			return ti.WindowID < tj.WindowID
-		})
+		terminals.SortWindows(windows, mode, 0, terminals.TerminalWindow.SortKey)
 	}
 }
 
@@ -716,18 +1102,24 @@ func (t *Tiler) GetTerminalCount(monitorID int) int {
 	return len(ws.Terminals)
 }
 
-// GetActiveLayoutName returns the current active layout name.
-func (t *Tiler) GetActiveLayoutName() string {
-	t.mu.RLock()
-	defer t.mu.RUnlock()
-
+// activeLayoutNameLocked returns the active layout name, falling back to the
+// configured default. The caller must hold t.mu.
+func (t *Tiler) activeLayoutNameLocked() string {
 	if t.activeLayout != "" {
 		return t.activeLayout
 	}
 	return t.config.DefaultLayout
 }
 
+// GetActiveLayoutName returns the current active layout name.
+func (t *Tiler) GetActiveLayoutName() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.activeLayoutNameLocked()
+}
+
 // SetActiveLayout sets the current active layout (used by TileCurrentMonitor).
+// Aliases are stored as the layout's primary name.
 func (t *Tiler) SetActiveLayout(name string) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -735,7 +1127,9 @@ func (t *Tiler) SetActiveLayout(name string) error {
 	if _, err := t.config.GetLayout(name); err != nil {
 		return err
 	}
-	t.activeLayout = name
+	previous := t.activeLayoutNameLocked()
+	t.activeLayout, _ = t.config.ResolveLayoutName(name)
+	t.emitLayoutChangeLocked(previous)
 	return nil
 }
 
@@ -754,10 +1148,7 @@ func (t *Tiler) CycleActiveLayout(delta int) (string, error) {
 	}
 	sort.Strings(names)
 
-	current := t.activeLayout
-	if current == "" {
-		current = t.config.DefaultLayout
-	}
+	current := t.activeLayoutNameLocked()
 
 	idx := 0
 	for i, name := range names {
@@ -774,13 +1165,24 @@ func (t *Tiler) CycleActiveLayout(delta int) (string, error) {
 	}
 
 	t.activeLayout = names[next]
+	t.emitLayoutChangeLocked(current)
 	return t.activeLayout, nil
 }
 
+// emitLayoutChangeLocked emits EventLayoutChanged when the active layout
+// differs from previous. The caller must hold t.mu.
+func (t *Tiler) emitLayoutChangeLocked(previous string) {
+	if current := t.activeLayoutNameLocked(); current != previous {
+		t.emit(Event{Kind: EventLayoutChanged, Layout: current})
+	}
+}
+
 // UpdateConfig updates the tiler's configuration
 func (t *Tiler) UpdateConfig(cfg *config.Config) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
+	previous := t.activeLayoutNameLocked()
+	defer t.emitLayoutChangeLocked(previous)
 	t.config = cfg
 	if t.activeLayout == "" {
 		t.activeLayout = cfg.DefaultLayout
@@ -0,0 +1,745 @@
+package tiling
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/1broseidon/termtile/internal/config"
+	"github.com/1broseidon/termtile/internal/platform"
+	"github.com/1broseidon/termtile/internal/terminals"
+)
+
+// fakeBackend is an in-memory multi-monitor platform.Backend.
+type fakeBackend struct {
+	mu       sync.Mutex // guards moves against preview restore timers
+	displays []platform.Display
+	active   int
+	windows  map[int][]platform.Window
+	moves    map[platform.WindowID]platform.Rect
+
+	activeWindow platform.WindowID
+	focused      []platform.WindowID
+	minimized    []platform.WindowID
+	batches      int
+	// bulk makes MoveResizeBatch apply moves directly instead of going
+	// through the sequential MoveResize fallback.
+	bulk bool
+}
+
+func newFakeBackend(displays ...platform.Display) *fakeBackend {
+	return &fakeBackend{
+		displays: displays,
+		windows:  make(map[int][]platform.Window),
+		moves:    make(map[platform.WindowID]platform.Rect),
+	}
+}
+
+func (f *fakeBackend) addWindow(displayID int, id platform.WindowID, bounds platform.Rect) {
+	f.windows[displayID] = append(f.windows[displayID], platform.Window{ID: id, AppID: "kitty", Bounds: bounds})
+}
+
+func (f *fakeBackend) Displays() ([]platform.Display, error) { return f.displays, nil }
+func (f *fakeBackend) ActiveDisplay() (platform.Display, error) {
+	return f.displays[f.active], nil
+}
+func (f *fakeBackend) ActiveWindow() (platform.WindowID, error) { return f.activeWindow, nil }
+func (f *fakeBackend) ListWindowsOnDisplay(displayID int) ([]platform.Window, error) {
+	return f.windows[displayID], nil
+}
+func (f *fakeBackend) MoveResize(windowID platform.WindowID, bounds platform.Rect) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.moves[windowID] = bounds
+	return nil
+}
+func (f *fakeBackend) MoveResizeBatch(moves []platform.WindowRect) error {
+	f.batches++
+	if !f.bulk {
+		return platform.MoveResizeEach(f, moves)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, m := range moves {
+		f.moves[m.WindowID] = m.Bounds
+	}
+	return nil
+}
+func (f *fakeBackend) moveFor(id platform.WindowID) platform.Rect {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.moves[id]
+}
+func (f *fakeBackend) Minimize(id platform.WindowID) error {
+	f.minimized = append(f.minimized, id)
+	return nil
+}
+func (f *fakeBackend) Focus(id platform.WindowID) error {
+	f.focused = append(f.focused, id)
+	return nil
+}
+func (f *fakeBackend) Close(platform.WindowID) error { return nil }
+
+func twoMonitorTiler(t *testing.T) (*Tiler, *fakeBackend) {
+	t.Helper()
+	backend := newFakeBackend(
+		platform.Display{ID: 0, Name: "left", Bounds: platform.Rect{X: 0, Y: 0, Width: 1000, Height: 800}},
+		platform.Display{ID: 1, Name: "right", Bounds: platform.Rect{X: 1000, Y: 0, Width: 1000, Height: 800}},
+	)
+	backend.addWindow(0, 11, platform.Rect{X: 100, Y: 100, Width: 200, Height: 200})
+	backend.addWindow(0, 12, platform.Rect{X: 400, Y: 100, Width: 200, Height: 200})
+	backend.addWindow(1, 21, platform.Rect{X: 1100, Y: 100, Width: 200, Height: 200})
+
+	cfg := config.DefaultConfig()
+	detector := terminals.NewDetector([]string{"kitty"})
+	return NewTiler(backend, detector, cfg), backend
+}
+
+func gridTiler(bulk bool, n int) (*Tiler, *fakeBackend) {
+	backend := newFakeBackend(platform.Display{ID: 0, Name: "main", Bounds: platform.Rect{X: 0, Y: 0, Width: 2560, Height: 1440}})
+	backend.bulk = bulk
+	for i := 0; i < n; i++ {
+		backend.addWindow(0, platform.WindowID(100+i), platform.Rect{X: 10 * i, Y: 5 * i, Width: 300, Height: 200})
+	}
+	return NewTiler(backend, terminals.NewDetector([]string{"kitty"}), config.DefaultConfig()), backend
+}
+
+func TestTileCurrentMonitor_BatchMatchesSequential(t *testing.T) {
+	seqTiler, seq := gridTiler(false, 7)
+	bulkTiler, bulk := gridTiler(true, 7)
+
+	if err := seqTiler.TileCurrentMonitor(); err != nil {
+		t.Fatalf("sequential TileCurrentMonitor: %v", err)
+	}
+	if err := bulkTiler.TileCurrentMonitor(); err != nil {
+		t.Fatalf("batch TileCurrentMonitor: %v", err)
+	}
+
+	if seq.batches != 1 || bulk.batches != 1 {
+		t.Fatalf("batches seq=%d bulk=%d, want one MoveResizeBatch per tile", seq.batches, bulk.batches)
+	}
+	if len(seq.moves) != 7 || len(bulk.moves) != len(seq.moves) {
+		t.Fatalf("moved seq=%d bulk=%d windows, want 7", len(seq.moves), len(bulk.moves))
+	}
+	for id, want := range seq.moves {
+		if got := bulk.moves[id]; got != want {
+			t.Fatalf("window %d: batch geometry %+v, sequential %+v", id, got, want)
+		}
+	}
+}
+
+func BenchmarkTileCurrentMonitor(b *testing.B) {
+	for _, mode := range []struct {
+		name string
+		bulk bool
+	}{{"sequential", false}, {"batch", true}} {
+		b.Run(mode.name, func(b *testing.B) {
+			tiler, _ := gridTiler(mode.bulk, 16)
+			for i := 0; i < b.N; i++ {
+				if err := tiler.TileCurrentMonitor(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestTileAllMonitors_TilesEveryDisplay(t *testing.T) {
+	tiler, backend := twoMonitorTiler(t)
+
+	if err := tiler.TileAllMonitors(); err != nil {
+		t.Fatalf("TileAllMonitors: %v", err)
+	}
+
+	for _, id := range []platform.WindowID{11, 12, 21} {
+		if _, ok := backend.moves[id]; !ok {
+			t.Fatalf("window %d was not tiled", id)
+		}
+	}
+	if got := backend.moves[21]; got.X < 1000 {
+		t.Fatalf("window on right monitor placed at X=%d, want >= 1000", got.X)
+	}
+	if got := backend.moves[12]; got.X+got.Width > 1000 {
+		t.Fatalf("window on left monitor overflows into right monitor: %+v", got)
+	}
+
+	if n := tiler.GetTerminalCount(0); n != 2 {
+		t.Fatalf("monitor 0 terminal count=%d, want 2", n)
+	}
+	if n := tiler.GetTerminalCount(1); n != 1 {
+		t.Fatalf("monitor 1 terminal count=%d, want 1", n)
+	}
+}
+
+func TestTileAllMonitors_UsesMonitorPadding(t *testing.T) {
+	tiler, backend := twoMonitorTiler(t)
+	tiler.config.ScreenPadding = config.Margins{Top: 10}
+	tiler.config.MonitorPadding = map[string]config.Margins{
+		"right": {Top: 50, Left: 200},
+	}
+
+	if err := tiler.TileAllMonitors(); err != nil {
+		t.Fatalf("TileAllMonitors: %v", err)
+	}
+
+	if got := backend.moves[21]; got.X < 1200 || got.Y < 50 {
+		t.Fatalf("right monitor window %+v ignores its monitor_padding", got)
+	}
+	for _, id := range []platform.WindowID{11, 12} {
+		got := backend.moves[id]
+		if got.Y < 10 || got.Y >= 50 {
+			t.Fatalf("left monitor window %d at Y=%d, want global screen_padding top 10", id, got.Y)
+		}
+	}
+	if got := backend.moves[11]; got.X >= 200 {
+		t.Fatalf("left monitor window picked up the right monitor's padding: %+v", got)
+	}
+}
+
+func TestTileAllMonitors_RecordsUndoPerMonitor(t *testing.T) {
+	tiler, backend := twoMonitorTiler(t)
+
+	if err := tiler.TileAllMonitors(); err != nil {
+		t.Fatalf("TileAllMonitors: %v", err)
+	}
+
+	left := tiler.GetWorkspace(0)
+	right := tiler.GetWorkspace(1)
+	if left == nil || len(left.PreviousGeometries) != 2 {
+		t.Fatalf("expected 2 undo geometries for monitor 0, got %+v", left)
+	}
+	if right == nil || len(right.PreviousGeometries) != 1 {
+		t.Fatalf("expected 1 undo geometry for monitor 1, got %+v", right)
+	}
+
+	// Undo on the active (left) monitor must leave the right monitor alone.
+	tiledRight := backend.moves[21]
+	if err := tiler.UndoCurrentMonitor(); err != nil {
+		t.Fatalf("UndoCurrentMonitor: %v", err)
+	}
+	if got := backend.moves[11]; got != (platform.Rect{X: 100, Y: 100, Width: 200, Height: 200}) {
+		t.Fatalf("window 11 not restored, got %+v", got)
+	}
+	if got := backend.moves[21]; got != tiledRight {
+		t.Fatalf("window 21 changed by undo on other monitor: %+v", got)
+	}
+	if ws := tiler.GetWorkspace(1); len(ws.PreviousGeometries) != 1 {
+		t.Fatalf("monitor 1 undo state was consumed")
+	}
+}
+
+func TestUndoCurrentMonitor_RevertsRecordedMove(t *testing.T) {
+	tiler, backend := twoMonitorTiler(t)
+	if err := tiler.TileCurrentMonitor(); err != nil {
+		t.Fatalf("TileCurrentMonitor: %v", err)
+	}
+
+	// A move-mode swap of 11 and 12 replaces the tile as the undo target.
+	before11 := Rect{X: 10, Y: 10, Width: 480, Height: 780}
+	before12 := Rect{X: 510, Y: 10, Width: 480, Height: 780}
+	reverted := 0
+	tiler.RecordMove(0, map[platform.WindowID]Rect{11: before11, 12: before12}, func() { reverted++ })
+
+	if err := tiler.UndoCurrentMonitor(); err != nil {
+		t.Fatalf("UndoCurrentMonitor: %v", err)
+	}
+	if got := backend.moveFor(11); got != (platform.Rect{X: 10, Y: 10, Width: 480, Height: 780}) {
+		t.Fatalf("window 11 = %+v, want pre-move geometry", got)
+	}
+	if got := backend.moveFor(12); got != (platform.Rect{X: 510, Y: 10, Width: 480, Height: 780}) {
+		t.Fatalf("window 12 = %+v, want pre-move geometry", got)
+	}
+	if reverted != 1 {
+		t.Fatalf("revert called %d times, want 1", reverted)
+	}
+
+	// The move is consumed; a second undo does nothing.
+	if err := tiler.UndoCurrentMonitor(); err != nil {
+		t.Fatalf("second UndoCurrentMonitor: %v", err)
+	}
+	if reverted != 1 {
+		t.Fatalf("revert called again on second undo")
+	}
+
+	// Tiling after a move drops its revert.
+	tiler.RecordMove(0, map[platform.WindowID]Rect{11: before11}, func() { reverted++ })
+	if err := tiler.TileCurrentMonitor(); err != nil {
+		t.Fatalf("TileCurrentMonitor: %v", err)
+	}
+	if err := tiler.UndoCurrentMonitor(); err != nil {
+		t.Fatalf("UndoCurrentMonitor after tile: %v", err)
+	}
+	if reverted != 1 {
+		t.Fatalf("revert ran after a later tile replaced the move")
+	}
+}
+
+func TestToggleMonocleCurrentMonitor_SnapshotAndRestore(t *testing.T) {
+	tiler, backend := twoMonitorTiler(t)
+	backend.bulk = true
+	if err := tiler.TileCurrentMonitor(); err != nil {
+		t.Fatalf("TileCurrentMonitor: %v", err)
+	}
+	// The fake backend does not move windows, so record the tiled geometry
+	// as the current geometry seen by the next detection.
+	tiled := map[platform.WindowID]platform.Rect{}
+	for i, w := range backend.windows[0] {
+		tiled[w.ID] = backend.moves[w.ID]
+		backend.windows[0][i].Bounds = backend.moves[w.ID]
+	}
+	backend.activeWindow = 12
+
+	if err := tiler.ToggleMonocleCurrentMonitor(); err != nil {
+		t.Fatalf("ToggleMonocleCurrentMonitor(on): %v", err)
+	}
+	if got, want := backend.moveFor(12), (platform.Rect{X: 0, Y: 0, Width: 1000, Height: 800}); got != want {
+		t.Fatalf("monocle window geometry = %+v, want full region %+v", got, want)
+	}
+	if got := backend.moveFor(11); got != tiled[11] {
+		t.Fatalf("unfocused window moved by monocle: %+v", got)
+	}
+	if len(backend.focused) != 1 || backend.focused[0] != 12 {
+		t.Fatalf("focused=%v, want monocle window raised", backend.focused)
+	}
+	if _, ok := backend.moves[21]; ok {
+		t.Fatal("monocle touched a window on another monitor")
+	}
+
+	if err := tiler.ToggleMonocleCurrentMonitor(); err != nil {
+		t.Fatalf("ToggleMonocleCurrentMonitor(off): %v", err)
+	}
+	for id, want := range tiled {
+		if got := backend.moveFor(id); got != want {
+			t.Fatalf("window %d restored to %+v, want %+v", id, got, want)
+		}
+	}
+
+	// Re-tiling discards the snapshot, so the next press turns monocle on again.
+	if err := tiler.ToggleMonocleCurrentMonitor(); err != nil {
+		t.Fatalf("ToggleMonocleCurrentMonitor(on again): %v", err)
+	}
+	if err := tiler.TileCurrentMonitor(); err != nil {
+		t.Fatalf("TileCurrentMonitor: %v", err)
+	}
+	backend.moves[12] = platform.Rect{}
+	if err := tiler.ToggleMonocleCurrentMonitor(); err != nil {
+		t.Fatalf("ToggleMonocleCurrentMonitor(after retile): %v", err)
+	}
+	if got := backend.moveFor(12); got.Width != 1000 {
+		t.Fatalf("after re-tile the toggle restored instead of entering monocle: %+v", got)
+	}
+}
+
+func TestToggleMonocleCurrentMonitor_FocusedWindowNotTerminal(t *testing.T) {
+	tiler, backend := twoMonitorTiler(t)
+	backend.activeWindow = 99
+
+	if err := tiler.ToggleMonocleCurrentMonitor(); err == nil {
+		t.Fatal("ToggleMonocleCurrentMonitor() succeeded with a non-terminal focused")
+	}
+	if len(backend.moves) != 0 {
+		t.Fatalf("moves=%v, want none", backend.moves)
+	}
+}
+
+func TestTileCurrentMonitor_OnlyTouchesActiveDisplay(t *testing.T) {
+	tiler, backend := twoMonitorTiler(t)
+	backend.active = 1
+
+	if err := tiler.TileCurrentMonitor(); err != nil {
+		t.Fatalf("TileCurrentMonitor: %v", err)
+	}
+	if _, ok := backend.moves[21]; !ok {
+		t.Fatalf("window on active monitor was not tiled")
+	}
+	if _, ok := backend.moves[11]; ok {
+		t.Fatalf("window on inactive monitor was tiled")
+	}
+}
+
+func TestTileCurrentMonitor_FocusAfterTile(t *testing.T) {
+	tests := []struct {
+		mode string
+		want []platform.WindowID
+	}{
+		{mode: config.FocusAfterTileKeep, want: nil},
+		{mode: config.FocusAfterTileFirst, want: []platform.WindowID{11}},
+		{mode: config.FocusAfterTileActive, want: []platform.WindowID{12}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			tiler, backend := twoMonitorTiler(t)
+			tiler.config.FocusAfterTile = tt.mode
+			// Window 12 is focused before tiling; window 11 sorts into slot 0.
+			backend.activeWindow = 12
+
+			if err := tiler.TileCurrentMonitor(); err != nil {
+				t.Fatalf("TileCurrentMonitor: %v", err)
+			}
+			if len(backend.focused) != len(tt.want) {
+				t.Fatalf("focused=%v, want %v", backend.focused, tt.want)
+			}
+			for i := range tt.want {
+				if backend.focused[i] != tt.want[i] {
+					t.Fatalf("focused=%v, want %v", backend.focused, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestTileCurrentMonitor_FocusedNonTerminalInclusion(t *testing.T) {
+	for _, ignore := range []bool{true, false} {
+		tiler, backend := twoMonitorTiler(t)
+		tiler.config.TileIgnoresNonTerminals = ignore
+		// A focused file manager on the left monitor, and an unfocused one.
+		backend.windows[0] = append(backend.windows[0],
+			platform.Window{ID: 31, AppID: "nautilus", Bounds: platform.Rect{X: 600, Y: 400, Width: 300, Height: 300}},
+			platform.Window{ID: 32, AppID: "firefox", Bounds: platform.Rect{X: 100, Y: 500, Width: 300, Height: 200}},
+		)
+		backend.activeWindow = 31
+
+		if err := tiler.TileCurrentMonitor(); err != nil {
+			t.Fatalf("ignore=%v: TileCurrentMonitor: %v", ignore, err)
+		}
+		_, placed := backend.moves[31]
+		if placed == ignore {
+			t.Fatalf("ignore=%v: focused non-terminal placed=%v", ignore, placed)
+		}
+		if _, ok := backend.moves[32]; ok {
+			t.Fatalf("ignore=%v: unfocused non-terminal was tiled", ignore)
+		}
+		if got := tiler.GetTerminalCount(0); got != 2 {
+			t.Fatalf("ignore=%v: terminal count = %d, want 2", ignore, got)
+		}
+	}
+}
+
+func TestTileCurrentMonitor_FocusedNonTerminalTiledLast(t *testing.T) {
+	tiler, backend := twoMonitorTiler(t)
+	tiler.config.TileIgnoresNonTerminals = false
+	tiler.config.TerminalSort = "title"
+	backend.windows[0][0].Title = "logs"
+	backend.windows[0][1].Title = "vim"
+	// Sorted by title with the terminals, the focused window would come
+	// first and shift both terminals' slot indices.
+	backend.windows[0] = append(backend.windows[0],
+		platform.Window{ID: 31, AppID: "nautilus", Title: "aaa", Bounds: platform.Rect{X: 600, Y: 400, Width: 300, Height: 300}},
+	)
+	backend.activeWindow = 31
+
+	if err := tiler.TileCurrentMonitor(); err != nil {
+		t.Fatalf("TileCurrentMonitor: %v", err)
+	}
+	ws := tiler.workspaces[0]
+	if ws == nil || len(ws.Terminals) != 2 {
+		t.Fatalf("tiled workspace = %+v, want the 2 terminals", ws)
+	}
+	for _, term := range ws.Terminals {
+		if term.WindowID == 31 {
+			t.Fatal("focused non-terminal recorded as a workspace terminal")
+		}
+	}
+	// A 3-window grid on 1000x800 puts the last slot on the second row.
+	if got := backend.moves[31]; got.Y <= backend.moves[11].Y || got.Y <= backend.moves[12].Y {
+		t.Fatalf("focused non-terminal at %+v, want the last slot below 11=%+v 12=%+v", got, backend.moves[11], backend.moves[12])
+	}
+}
+
+func TestTileCurrentMonitor_FocusedNonTerminalSurvivesOverflow(t *testing.T) {
+	tiler, backend := gridTiler(true, 7)
+	tiler.config.GapSize = 0
+	tiler.config.TileIgnoresNonTerminals = false
+	tiler.config.MinTileWidth = 1000
+	tiler.config.MinTileHeight = 700
+	backend.windows[0] = append(backend.windows[0],
+		platform.Window{ID: 31, AppID: "nautilus", Bounds: platform.Rect{X: 600, Y: 400, Width: 300, Height: 300}},
+	)
+	backend.activeWindow = 31
+
+	if err := tiler.TileCurrentMonitor(); err != nil {
+		t.Fatalf("TileCurrentMonitor: %v", err)
+	}
+	if _, ok := backend.moves[31]; !ok {
+		t.Fatal("focused non-terminal was not tiled")
+	}
+	for _, id := range backend.minimized {
+		if id == 31 {
+			t.Fatal("focused non-terminal was minimized as overflow")
+		}
+	}
+	if len(backend.moves) != 4 || len(backend.minimized) != 4 {
+		t.Fatalf("moved %d, minimized %v; want 4 tiled and 4 terminals minimized", len(backend.moves), backend.minimized)
+	}
+	if got := tiler.GetTerminalCount(0); got != 3 {
+		t.Fatalf("terminal count = %d, want 3", got)
+	}
+}
+
+func TestFocusAfterTileTarget_NothingToFocus(t *testing.T) {
+	if _, ok := focusAfterTileTarget(config.FocusAfterTileFirst, 0, nil); ok {
+		t.Fatal("first with no tiled workspace should not focus")
+	}
+	if _, ok := focusAfterTileTarget(config.FocusAfterTileActive, 0, &Workspace{}); ok {
+		t.Fatal("active with no previously active window should not focus")
+	}
+}
+
+func TestTileCurrentMonitor_NoTerminals(t *testing.T) {
+	tiler, backend := gridTiler(true, 0)
+	tiler.config.FocusAfterTile = config.FocusAfterTileFirst
+
+	if err := tiler.TileCurrentMonitor(); !errors.Is(err, ErrNoTerminals) {
+		t.Fatalf("TileCurrentMonitor() error = %v, want ErrNoTerminals", err)
+	}
+	if backend.batches != 0 || len(backend.focused) != 0 {
+		t.Fatalf("batches=%d focused=%v, want nothing moved or focused", backend.batches, backend.focused)
+	}
+}
+
+func TestTileAllMonitors_SkipsMonitorsWithoutTerminals(t *testing.T) {
+	tiler, backend := twoMonitorTiler(t)
+	delete(backend.windows, 1)
+
+	if err := tiler.TileAllMonitors(); err != nil {
+		t.Fatalf("TileAllMonitors() error = %v, want nil when one monitor has terminals", err)
+	}
+	if _, ok := backend.moves[11]; !ok {
+		t.Fatal("window on left monitor was not tiled")
+	}
+
+	delete(backend.windows, 0)
+	if err := tiler.TileAllMonitors(); !errors.Is(err, ErrNoTerminals) {
+		t.Fatalf("TileAllMonitors() error = %v, want ErrNoTerminals", err)
+	}
+}
+
+func TestTileWithOrder_NoTerminals(t *testing.T) {
+	tiler, _ := gridTiler(true, 0)
+
+	if err := tiler.TileWithOrder([]uint32{100, 101}); !errors.Is(err, ErrNoTerminals) {
+		t.Fatalf("TileWithOrder() error = %v, want ErrNoTerminals", err)
+	}
+}
+
+func TestTileWithOrder_IncludesFocusedNonTerminal(t *testing.T) {
+	tiler, backend := twoMonitorTiler(t)
+	tiler.config.TileIgnoresNonTerminals = false
+	backend.windows[0] = append(backend.windows[0],
+		platform.Window{ID: 31, AppID: "nautilus", Bounds: platform.Rect{X: 600, Y: 400, Width: 300, Height: 300}},
+	)
+	backend.activeWindow = 31
+
+	if err := tiler.TileWithOrder([]uint32{12, 11}); err != nil {
+		t.Fatalf("TileWithOrder: %v", err)
+	}
+	if _, ok := backend.moves[31]; !ok {
+		t.Fatal("focused non-terminal was not tiled")
+	}
+	ws := tiler.workspaces[0]
+	if ws == nil || len(ws.Terminals) != 2 || ws.Terminals[0].WindowID != 12 {
+		t.Fatalf("tiled workspace = %+v, want terminals 12, 11", ws)
+	}
+}
+
+func TestPreviewLayoutDefinition_RestoresAfterDuration(t *testing.T) {
+	tiler, backend := gridTiler(true, 2)
+	layoutCount := len(tiler.config.Layouts)
+	original := map[platform.WindowID]platform.Rect{}
+	for _, w := range backend.windows[0] {
+		original[w.ID] = w.Bounds
+	}
+
+	layout := config.Layout{Mode: config.LayoutModeVertical, TileRegion: config.TileRegion{Type: config.RegionFull}}
+	if err := tiler.PreviewLayoutDefinition(layout, 50*time.Millisecond); err != nil {
+		t.Fatalf("PreviewLayoutDefinition: %v", err)
+	}
+	for id, before := range original {
+		if got := backend.moveFor(id); got == before {
+			t.Fatalf("window %d not moved by preview", id)
+		}
+	}
+	if len(tiler.config.Layouts) != layoutCount {
+		t.Fatalf("preview added layout to config: %d layouts, want %d", len(tiler.config.Layouts), layoutCount)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		restored := true
+		for id, before := range original {
+			if backend.moveFor(id) != before {
+				restored = false
+			}
+		}
+		if restored {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("windows not restored after preview duration")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPreviewLayout_SequentialPreviewsRestoreOriginal(t *testing.T) {
+	tiler, backend := gridTiler(true, 3)
+	original := map[platform.WindowID]platform.Rect{}
+	for _, w := range backend.windows[0] {
+		original[w.ID] = w.Bounds
+	}
+
+	if err := tiler.PreviewLayout("grid", time.Hour); err != nil {
+		t.Fatalf("PreviewLayout(grid): %v", err)
+	}
+	gridMoves := map[platform.WindowID]platform.Rect{}
+	for id := range original {
+		gridMoves[id] = backend.moveFor(id)
+	}
+	if err := tiler.PreviewLayout("columns", 50*time.Millisecond); err != nil {
+		t.Fatalf("PreviewLayout(columns): %v", err)
+	}
+	changed := false
+	for id := range original {
+		if backend.moveFor(id) != gridMoves[id] {
+			changed = true
+		}
+	}
+	if !changed {
+		t.Fatal("second preview did not move windows")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		restored := true
+		for id, before := range original {
+			if backend.moveFor(id) != before {
+				restored = false
+			}
+		}
+		if restored {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("windows not restored to their pre-preview geometry after the last preview")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPreviewLayoutDefinition_RejectsInvalidLayout(t *testing.T) {
+	tiler, backend := gridTiler(true, 2)
+
+	layout := config.Layout{Mode: config.LayoutModeFixed, TileRegion: config.TileRegion{Type: config.RegionFull}}
+	if err := tiler.PreviewLayoutDefinition(layout, time.Second); err == nil {
+		t.Fatal("PreviewLayoutDefinition(fixed without grid) error = nil")
+	}
+	if backend.batches != 0 {
+		t.Fatalf("invalid preview moved windows (%d batches)", backend.batches)
+	}
+}
+
+func TestTileCurrentMonitor_RecordsMetrics(t *testing.T) {
+	tiler, _ := gridTiler(true, 3)
+	if m := tiler.Metrics(); m.TilesTotal != 0 || !m.LastTiledAt.IsZero() {
+		t.Fatalf("metrics before tiling = %+v, want zero", m)
+	}
+
+	if err := tiler.TileCurrentMonitor(); err != nil {
+		t.Fatal(err)
+	}
+	m := tiler.Metrics()
+	if m.TilesTotal != 1 || m.LastTerminals != 3 || m.LastTiledAt.IsZero() || m.LastDuration < 0 {
+		t.Fatalf("metrics after one tile = %+v, want 1 tile of 3 terminals", m)
+	}
+
+	if err := tiler.TileAllMonitors(); err != nil {
+		t.Fatal(err)
+	}
+	if m := tiler.Metrics(); m.TilesTotal != 2 || m.LastTerminals != 3 {
+		t.Fatalf("metrics after two tiles = %+v, want 2 tiles", m)
+	}
+}
+
+func TestTiler_EmitsTileAndLayoutEvents(t *testing.T) {
+	tiler, _ := gridTiler(true, 2)
+	var events []Event
+	tiler.SetEventHandler(func(ev Event) { events = append(events, ev) })
+
+	if err := tiler.TileCurrentMonitor(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tiler.SetActiveLayout("columns"); err != nil {
+		t.Fatal(err)
+	}
+	// Re-selecting the active layout is not a change.
+	if err := tiler.SetActiveLayout("columns"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tiler.TileAllMonitors(); err != nil {
+		t.Fatal(err)
+	}
+
+	defaultLayout := config.DefaultConfig().DefaultLayout
+	want := []Event{
+		{Kind: EventTiled, Layout: defaultLayout, MonitorID: 0, Terminals: 2},
+		{Kind: EventLayoutChanged, Layout: "columns"},
+		{Kind: EventTiled, Layout: "columns", MonitorID: -1, Terminals: 2},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("events = %+v, want %+v", events, want)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Fatalf("event %d = %+v, want %+v", i, events[i], want[i])
+		}
+	}
+
+	// Failed tiles emit nothing.
+	empty, _ := gridTiler(true, 0)
+	empty.SetEventHandler(func(ev Event) { t.Fatalf("unexpected event %+v", ev) })
+	if err := empty.TileCurrentMonitor(); !errors.Is(err, ErrNoTerminals) {
+		t.Fatalf("err = %v, want ErrNoTerminals", err)
+	}
+}
+
+func TestTileCurrentMonitor_NoTerminalsLeavesMetrics(t *testing.T) {
+	tiler, _ := gridTiler(true, 0)
+	if err := tiler.TileCurrentMonitor(); !errors.Is(err, ErrNoTerminals) {
+		t.Fatalf("err = %v, want ErrNoTerminals", err)
+	}
+	if m := tiler.Metrics(); m.TilesTotal != 0 {
+		t.Fatalf("TilesTotal = %d after failed tile, want 0", m.TilesTotal)
+	}
+}
+
+func TestTileCurrentMonitor_MinTileSizeMinimizesOverflow(t *testing.T) {
+	tiler, backend := gridTiler(true, 7)
+	tiler.config.GapSize = 0
+	// 2560x1440 fits a 2x2 grid of 1280x720 tiles but not 3 columns.
+	tiler.config.MinTileWidth = 1000
+	tiler.config.MinTileHeight = 700
+
+	if err := tiler.TileCurrentMonitor(); err != nil {
+		t.Fatalf("TileCurrentMonitor: %v", err)
+	}
+	if len(backend.moves) != 4 {
+		t.Fatalf("moved %d windows, want 4", len(backend.moves))
+	}
+	for id, r := range backend.moves {
+		if r.Width != 1280 || r.Height != 720 {
+			t.Fatalf("window %d tiled %dx%d, want 1280x720", id, r.Width, r.Height)
+		}
+	}
+	if len(backend.minimized) != 3 {
+		t.Fatalf("minimized %v, want the 3 overflow windows", backend.minimized)
+	}
+	for _, id := range backend.minimized {
+		if _, moved := backend.moves[id]; moved {
+			t.Fatalf("window %d was both tiled and minimized", id)
+		}
+	}
+	if got := tiler.GetTerminalCount(0); got != 4 {
+		t.Fatalf("workspace terminal count = %d, want 4", got)
+	}
+}
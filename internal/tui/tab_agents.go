@@ -244,6 +244,9 @@ func renderAgentDetail(item agentItem, width, height int) string {
 	field("spawn_mode:", ac.SpawnMode)
 	field("ready_pattern:", ac.ReadyPattern)
 	field("idle_pattern:", ac.IdlePattern)
+	if len(ac.IdlePatterns) > 0 {
+		field("idle_patterns:", strings.Join(ac.IdlePatterns, " "))
+	}
 	field("default_model:", ac.DefaultModel)
 	field("model_flag:", ac.ModelFlag)
 	field("description:", ac.Description)
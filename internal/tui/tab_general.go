@@ -126,6 +126,8 @@ func (g *GeneralTab) startEditing() {
 		huh.NewOption("window_id", "window_id"),
 		huh.NewOption("client_list", "client_list"),
 		huh.NewOption("active_first", "active_first"),
+		huh.NewOption("title", "title"),
+		huh.NewOption("pid", "pid"),
 	}
 
 	w := g.width - 4
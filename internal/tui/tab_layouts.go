@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/1broseidon/termtile/internal/config"
 	"github.com/1broseidon/termtile/internal/ipc"
+	"github.com/1broseidon/termtile/internal/tiling"
 )
 
 // layoutItem implements list.Item for the layout picker sidebar.
@@ -212,8 +214,8 @@ func (lt LayoutsTab) applySelected() (LayoutsTab, tea.Cmd) {
 			return clearStatusMsg{}
 		})
 	}
-	if err := lt.ipcClient.ApplyLayout(name, true); err != nil {
-		lt.statusText = fmt.Sprintf("error: %v", err)
+	if err := lt.ipcClient.ApplyLayout(name, true); err != nil && !errors.Is(err, tiling.ErrNoTerminals) {
+		lt.statusText = layoutErrorText(err)
 	} else {
 		lt.activeLayout = name
 		lt.statusText = fmt.Sprintf("applied: %s", name)
@@ -224,6 +226,16 @@ func (lt LayoutsTab) applySelected() (LayoutsTab, tea.Cmd) {
 	})
 }
 
+// layoutErrorText formats a daemon error for the status line, naming the
+// field at fault when the layout failed validation.
+func layoutErrorText(err error) string {
+	var invalid *ipc.LayoutValidationError
+	if errors.As(err, &invalid) {
+		return fmt.Sprintf("invalid layout: %s: %s", invalid.Field, invalid.Message)
+	}
+	return fmt.Sprintf("error: %v", err)
+}
+
 func (lt LayoutsTab) setDefaultSelected() (LayoutsTab, tea.Cmd) {
 	name := lt.selectedName()
 	if name == "" {
@@ -259,7 +271,7 @@ func (lt LayoutsTab) previewSelected() (LayoutsTab, tea.Cmd) {
 		})
 	}
 	if err := lt.ipcClient.PreviewLayout(name, 5); err != nil {
-		lt.statusText = fmt.Sprintf("error: %v", err)
+		lt.statusText = layoutErrorText(err)
 	} else {
 		lt.statusText = fmt.Sprintf("previewing: %s (5s)", name)
 	}
@@ -341,7 +353,7 @@ func (lt LayoutsTab) renderPreview(previewWidth int) string {
 	// Summary line
 	summary := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("250")).
-		Render(" " + summarizeLayout(&layout, lt.tileCount, 8))
+		Render(" " + summarizeLayout(&layout, lt.tileCount, lt.cfg.LayoutGapSize(&layout)))
 
 	// ASCII preview
 	previewHeight := lt.height - 6 // title + summary + status + padding
@@ -14,16 +14,26 @@ import (
 
 // terminalItem is a list item representing a terminal class.
 type terminalItem struct {
-	class       string
+	class string
+	// instance is the WM_CLASS instance the entry is limited to, if any.
+	instance    string
 	isDefault   bool
 	hasSpawnCmd bool
 }
 
 func (i terminalItem) Title() string {
 	if i.isDefault {
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("226")).Render("★") + " " + i.class
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("226")).Render("★") + " " + i.name()
 	}
-	return lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Render("✓") + " " + i.class
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Render("✓") + " " + i.name()
+}
+
+// name is the class, followed by the instance for instance entries.
+func (i terminalItem) name() string {
+	if i.instance != "" {
+		return i.class + " (" + i.instance + ")"
+	}
+	return i.class
 }
 
 func (i terminalItem) Description() string {
@@ -31,6 +41,9 @@ func (i terminalItem) Description() string {
 	if i.isDefault {
 		parts = append(parts, "default")
 	}
+	if i.instance != "" {
+		parts = append(parts, "instance "+i.instance)
+	}
 	if i.hasSpawnCmd {
 		parts = append(parts, "spawn command configured")
 	}
@@ -40,7 +53,7 @@ func (i terminalItem) Description() string {
 	return strings.Join(parts, " | ")
 }
 
-func (i terminalItem) FilterValue() string { return i.class }
+func (i terminalItem) FilterValue() string { return i.name() }
 
 // TerminalsTab is the sub-model for the Terminal Classes tab.
 type TerminalsTab struct {
@@ -115,14 +128,14 @@ func (t TerminalsTab) Update(msg tea.Msg) (TerminalsTab, tea.Cmd) {
 			return t, textinput.Blink
 		case "x", "delete":
 			if item, ok := t.list.SelectedItem().(terminalItem); ok {
-				t.removeTerminalClass(item.class)
+				t.removeTerminalClass(item.class, item.instance)
 				items := buildTerminalItems(t.cfg)
 				t.list.SetItems(items)
 			}
 			return t, nil
 		case "d":
 			if item, ok := t.list.SelectedItem().(terminalItem); ok {
-				t.toggleDefault(item.class)
+				t.toggleDefault(item.class, item.instance)
 				items := buildTerminalItems(t.cfg)
 				t.list.SetItems(items)
 			}
@@ -178,31 +191,31 @@ func (t *TerminalsTab) addTerminalClass(class string) {
 		return
 	}
 	for _, tc := range t.cfg.TerminalClasses {
-		if strings.EqualFold(tc.Class, class) {
+		if strings.EqualFold(tc.Class, class) && tc.Instance == "" {
 			return
 		}
 	}
 	t.cfg.TerminalClasses = append(t.cfg.TerminalClasses, config.TerminalClass{Class: class})
 }
 
-func (t *TerminalsTab) removeTerminalClass(class string) {
+func (t *TerminalsTab) removeTerminalClass(class, instance string) {
 	if t.cfg == nil || len(t.cfg.TerminalClasses) <= 1 {
 		return
 	}
 	for i, tc := range t.cfg.TerminalClasses {
-		if tc.Class == class {
+		if tc.Class == class && tc.Instance == instance {
 			t.cfg.TerminalClasses = append(t.cfg.TerminalClasses[:i], t.cfg.TerminalClasses[i+1:]...)
 			return
 		}
 	}
 }
 
-func (t *TerminalsTab) toggleDefault(class string) {
+func (t *TerminalsTab) toggleDefault(class, instance string) {
 	if t.cfg == nil {
 		return
 	}
 	for i, tc := range t.cfg.TerminalClasses {
-		if tc.Class == class {
+		if tc.Class == class && tc.Instance == instance {
 			t.cfg.TerminalClasses[i].Default = !tc.Default
 		} else {
 			t.cfg.TerminalClasses[i].Default = false
@@ -273,6 +286,7 @@ func buildTerminalItems(cfg *config.Config) []list.Item {
 		_, hasSpawn := cfg.TerminalSpawnCommands[tc.Class]
 		items = append(items, terminalItem{
 			class:       tc.Class,
+			instance:    tc.Instance,
 			isDefault:   tc.Default,
 			hasSpawnCmd: hasSpawn,
 		})
@@ -285,7 +299,7 @@ func renderTerminalDetail(item terminalItem, cfg *config.Config, width, height i
 	var b strings.Builder
 
 	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("15"))
-	b.WriteString(titleStyle.Render(item.class))
+	b.WriteString(titleStyle.Render(item.name()))
 	b.WriteString("\n\n")
 
 	if item.isDefault {
@@ -304,6 +318,10 @@ func renderTerminalDetail(item terminalItem, cfg *config.Config, width, height i
 		b.WriteString("\n")
 	}
 
+	if item.instance != "" {
+		field("instance:", item.instance)
+	}
+
 	if cfg != nil {
 		if cmd, ok := cfg.TerminalSpawnCommands[item.class]; ok {
 			field("spawn command:", cmd)
@@ -5,34 +5,52 @@ import (
 	"syscall"
 )
 
+// killTerminalProcess force-closes a terminal by PID; tests replace it.
+var killTerminalProcess = func(pid int) error {
+	return syscall.Kill(pid, syscall.SIGKILL)
+}
+
 // CloseTerminals closes all terminal windows by sending SIGKILL to their processes.
 // This ensures a clean close without "are you sure" prompts from terminals.
 // For agent-mode workspaces using tmux, the tmux sessions survive the terminal close.
 func CloseTerminals(lister TerminalLister) error {
+	windows, err := CloseTargets(lister)
+	if err != nil {
+		return err
+	}
+	return CloseWindows(windows)
+}
+
+// CloseTargets returns the terminals CloseTerminals would kill: every listed
+// terminal with a known PID. It does not touch them.
+func CloseTargets(lister TerminalLister) ([]TerminalWindow, error) {
 	if lister == nil {
-		return fmt.Errorf("terminal lister is nil")
+		return nil, fmt.Errorf("terminal lister is nil")
 	}
 
 	windows, err := lister.ListTerminals()
 	if err != nil {
-		return fmt.Errorf("failed to list terminals: %w", err)
+		return nil, fmt.Errorf("failed to list terminals: %w", err)
 	}
 
-	if len(windows) == 0 {
-		return nil
+	targets := make([]TerminalWindow, 0, len(windows))
+	for _, win := range windows {
+		if win.PID > 0 {
+			targets = append(targets, win)
+		}
 	}
+	return targets, nil
+}
 
+// CloseWindows kills the processes of windows, as returned by CloseTargets.
+func CloseWindows(windows []TerminalWindow) error {
 	var lastErr error
 	for _, win := range windows {
-		if win.PID <= 0 {
-			continue
-		}
-
 		// SIGKILL ensures the terminal closes without prompts.
 		// This is safe because:
 		// - Agent-mode workspaces have tmux sessions that survive
 		// - Non-agent workspaces: user has chosen to close, accepting data loss
-		if err := syscall.Kill(win.PID, syscall.SIGKILL); err != nil {
+		if err := killTerminalProcess(win.PID); err != nil {
 			lastErr = fmt.Errorf("failed to close terminal (PID %d): %w", win.PID, err)
 		}
 	}
@@ -0,0 +1,61 @@
+package workspace
+
+import (
+	"os"
+	"testing"
+)
+
+func TestCloseTargets_DoesNotKill(t *testing.T) {
+	killed := stubKillTerminalProcess(t)
+	lister := &spawnLister{before: []TerminalWindow{
+		{WindowID: 1, WMClass: "fake", PID: 100},
+		{WindowID: 2, WMClass: "fake"}, // no PID: cannot be closed
+		{WindowID: 3, WMClass: "fake", PID: 300},
+	}}
+
+	targets, err := CloseTargets(lister)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(targets) != 2 || targets[0].WindowID != 1 || targets[1].WindowID != 3 {
+		t.Fatalf("targets = %+v, want windows 1 and 3", targets)
+	}
+	if len(*killed) != 0 {
+		t.Fatalf("CloseTargets killed %v, want no side effects", *killed)
+	}
+
+	if err := CloseWindows(targets); err != nil {
+		t.Fatal(err)
+	}
+	if len(*killed) != 2 || (*killed)[0] != 100 || (*killed)[1] != 300 {
+		t.Fatalf("killed = %v, want [100 300]", *killed)
+	}
+}
+
+func TestDeleteTargets_KeepsFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := Write(&WorkspaceConfig{Name: "ws", Layout: "grid"}); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := DeleteTargets("ws")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 {
+		t.Fatalf("paths = %v, want the workspace file", paths)
+	}
+	if _, err := os.Stat(paths[0]); err != nil {
+		t.Fatalf("DeleteTargets removed %s: %v", paths[0], err)
+	}
+
+	if err := Delete("ws"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(paths[0]); !os.IsNotExist(err) {
+		t.Fatalf("Delete left %s behind: %v", paths[0], err)
+	}
+	if _, err := DeleteTargets("ws"); err == nil {
+		t.Fatal("DeleteTargets of a missing workspace succeeded")
+	}
+}
@@ -0,0 +1,146 @@
+package workspace
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+)
+
+// Geometry modes for WorkspaceConfig.GeometryMode. An empty mode saves no
+// per-terminal geometry and load relies on the layout alone.
+const (
+	GeometryModeAbsolute = "absolute"
+	GeometryModeRelative = "relative"
+)
+
+// Rect is a window or monitor rectangle in pixels.
+type Rect struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+// SlotGeometry is the saved rect of one terminal. In absolute mode the values
+// are pixels; in relative mode they are fractions of the monitor bounds, so
+// load re-derives the rect for whatever monitor the workspace lands on.
+type SlotGeometry struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// MonitorBoundsProvider is an optional interface TerminalLister
+// implementations can support to report the bounds of the monitor whose
+// terminals ListTerminals returns. Save and Load need it for geometry capture
+// and restore.
+type MonitorBoundsProvider interface {
+	MonitorBounds() (Rect, error)
+}
+
+// WindowMover moves and resizes a window. Load uses it to restore saved slot
+// geometry after tiling.
+type WindowMover interface {
+	MoveResizeWindow(windowID uint32, rect Rect) error
+}
+
+// NormalizeGeometryMode validates a geometry mode, returning it lowercased.
+// The empty string is valid and disables geometry capture.
+func NormalizeGeometryMode(mode string) (string, error) {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	switch mode {
+	case "", GeometryModeAbsolute, GeometryModeRelative:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid geometry mode %q (valid: %s, %s)", mode, GeometryModeRelative, GeometryModeAbsolute)
+	}
+}
+
+// captureGeometry converts a window rect to its saved form for mode.
+func captureGeometry(mode string, win, monitor Rect) (*SlotGeometry, error) {
+	switch mode {
+	case GeometryModeAbsolute:
+		return &SlotGeometry{
+			X:      float64(win.X),
+			Y:      float64(win.Y),
+			Width:  float64(win.Width),
+			Height: float64(win.Height),
+		}, nil
+	case GeometryModeRelative:
+		if monitor.Width <= 0 || monitor.Height <= 0 {
+			return nil, fmt.Errorf("monitor bounds %dx%d are empty", monitor.Width, monitor.Height)
+		}
+		return &SlotGeometry{
+			X:      float64(win.X-monitor.X) / float64(monitor.Width),
+			Y:      float64(win.Y-monitor.Y) / float64(monitor.Height),
+			Width:  float64(win.Width) / float64(monitor.Width),
+			Height: float64(win.Height) / float64(monitor.Height),
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid geometry mode %q", mode)
+	}
+}
+
+// resolveGeometry converts a saved geometry back to a pixel rect on monitor.
+func resolveGeometry(mode string, g SlotGeometry, monitor Rect) (Rect, error) {
+	switch mode {
+	case GeometryModeAbsolute:
+		return Rect{
+			X:      int(math.Round(g.X)),
+			Y:      int(math.Round(g.Y)),
+			Width:  int(math.Round(g.Width)),
+			Height: int(math.Round(g.Height)),
+		}, nil
+	case GeometryModeRelative:
+		return Rect{
+			X:      monitor.X + int(math.Round(g.X*float64(monitor.Width))),
+			Y:      monitor.Y + int(math.Round(g.Y*float64(monitor.Height))),
+			Width:  int(math.Round(g.Width * float64(monitor.Width))),
+			Height: int(math.Round(g.Height * float64(monitor.Height))),
+		}, nil
+	default:
+		return Rect{}, fmt.Errorf("invalid geometry mode %q", mode)
+	}
+}
+
+// restoreLoadGeometry moves the freshly tiled windows in order to the saved
+// geometry of the matching slots in terms. Slots without saved geometry keep
+// their tiled rect. Failures are logged, not returned: the workspace is
+// already usable with the layout's rects.
+func restoreLoadGeometry(mode string, terms []TerminalConfig, order []uint32, lister TerminalLister, mover WindowMover, debugf func(format string, args ...any)) {
+	var monitor Rect
+	if mode == GeometryModeRelative {
+		provider, ok := lister.(MonitorBoundsProvider)
+		if !ok {
+			log.Printf("workspace: warning: terminal lister does not provide monitor bounds; keeping layout geometry")
+			return
+		}
+		var err error
+		if monitor, err = provider.MonitorBounds(); err != nil {
+			log.Printf("workspace: warning: failed to read monitor bounds: %v; keeping layout geometry", err)
+			return
+		}
+	}
+
+	for i, term := range terms {
+		if i >= len(order) || term.Geometry == nil {
+			continue
+		}
+		rect, err := resolveGeometry(mode, *term.Geometry, monitor)
+		if err != nil {
+			log.Printf("workspace: warning: %v; keeping layout geometry", err)
+			return
+		}
+		if rect.Width <= 0 || rect.Height <= 0 {
+			continue
+		}
+		if debugf != nil {
+			debugf("Restoring slot=%d window=%d geometry=%+v", term.SlotIndex, order[i], rect)
+		}
+		if err := mover.MoveResizeWindow(order[i], rect); err != nil {
+			log.Printf("workspace: warning: slot %d: failed to restore geometry: %v", term.SlotIndex, err)
+		}
+	}
+}
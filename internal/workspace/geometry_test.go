@@ -0,0 +1,149 @@
+package workspace
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// boundsLister is a spawnLister on a monitor with fixed bounds.
+type boundsLister struct {
+	spawnLister
+	bounds Rect
+}
+
+func (l *boundsLister) MonitorBounds() (Rect, error) { return l.bounds, nil }
+
+type movedWindow struct {
+	id   uint32
+	rect Rect
+}
+
+type recordingMover struct{ moves []movedWindow }
+
+func (m *recordingMover) MoveResizeWindow(windowID uint32, rect Rect) error {
+	m.moves = append(m.moves, movedWindow{id: windowID, rect: rect})
+	return nil
+}
+
+func TestGeometry_RelativeScalesToNewMonitor(t *testing.T) {
+	saved := Rect{X: 0, Y: 0, Width: 1920, Height: 1080}
+	windows := []Rect{
+		{X: 0, Y: 0, Width: 1280, Height: 1080},
+		{X: 1280, Y: 0, Width: 640, Height: 540},
+		{X: 1280, Y: 540, Width: 640, Height: 540},
+	}
+	// A 2560x1440 monitor to the right of the original one.
+	target := Rect{X: 1920, Y: 0, Width: 2560, Height: 1440}
+	want := []Rect{
+		{X: 1920, Y: 0, Width: 1707, Height: 1440},
+		{X: 3627, Y: 0, Width: 853, Height: 720},
+		{X: 3627, Y: 720, Width: 853, Height: 720},
+	}
+
+	for i, win := range windows {
+		g, err := captureGeometry(GeometryModeRelative, win, saved)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := resolveGeometry(GeometryModeRelative, *g, target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want[i] {
+			t.Errorf("window %d: resolved %+v, want %+v", i, got, want[i])
+		}
+		// Resolving on the original monitor round-trips exactly.
+		if back, _ := resolveGeometry(GeometryModeRelative, *g, saved); back != win {
+			t.Errorf("window %d: round trip %+v, want %+v", i, back, win)
+		}
+	}
+}
+
+func TestGeometry_AbsoluteIgnoresMonitor(t *testing.T) {
+	win := Rect{X: 100, Y: 50, Width: 800, Height: 600}
+	g, err := captureGeometry(GeometryModeAbsolute, win, Rect{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := resolveGeometry(GeometryModeAbsolute, *g, Rect{X: 1920, Width: 2560, Height: 1440})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != win {
+		t.Fatalf("resolved %+v, want %+v", got, win)
+	}
+}
+
+func TestNormalizeGeometryMode(t *testing.T) {
+	for in, want := range map[string]string{"": "", " Relative ": "relative", "absolute": "absolute"} {
+		got, err := NormalizeGeometryMode(in)
+		if err != nil || got != want {
+			t.Errorf("NormalizeGeometryMode(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := NormalizeGeometryMode("pixels"); err == nil {
+		t.Error("NormalizeGeometryMode(\"pixels\") succeeded, want error")
+	}
+}
+
+func TestSave_RelativeGeometry(t *testing.T) {
+	lister := &boundsLister{
+		spawnLister: spawnLister{before: []TerminalWindow{
+			{WindowID: 2, WMClass: "fake", X: 960, Y: 0, Width: 960, Height: 1080},
+			{WindowID: 1, WMClass: "fake", X: 0, Y: 0, Width: 960, Height: 1080},
+		}},
+		bounds: Rect{Width: 1920, Height: 1080},
+	}
+
+	ws, err := Save("ws", "grid", "", false, GeometryModeRelative, lister)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ws.GeometryMode != GeometryModeRelative {
+		t.Fatalf("GeometryMode = %q, want relative", ws.GeometryMode)
+	}
+	want := []SlotGeometry{{X: 0, Y: 0, Width: 0.5, Height: 1}, {X: 0.5, Y: 0, Width: 0.5, Height: 1}}
+	for i, term := range ws.Terminals {
+		if term.Geometry == nil || *term.Geometry != want[i] {
+			t.Errorf("slot %d geometry = %+v, want %+v", i, term.Geometry, want[i])
+		}
+	}
+
+	if _, err := Save("ws", "grid", "", false, GeometryModeRelative, &lister.spawnLister); err == nil || !strings.Contains(err.Error(), "monitor bounds") {
+		t.Fatalf("Save() without monitor bounds err=%v, want monitor bounds error", err)
+	}
+}
+
+func TestLoad_RestoresRelativeGeometry(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	lister := &boundsLister{
+		spawnLister: spawnLister{
+			spawned: []TerminalWindow{{WindowID: 10, WMClass: "fake", PID: 110}, {WindowID: 11, WMClass: "fake", PID: 111}},
+		},
+		bounds: Rect{X: 1920, Width: 2560, Height: 1440},
+	}
+	terms := fakeTerminals("fake", "fake")
+	terms[0].Geometry = &SlotGeometry{X: 0, Y: 0, Width: 0.75, Height: 1}
+	terms[1].Geometry = &SlotGeometry{X: 0.75, Y: 0, Width: 0.25, Height: 0.5}
+	cfg := &WorkspaceConfig{Name: "ws", Layout: "grid", GeometryMode: GeometryModeRelative, Terminals: terms}
+	mover := &recordingMover{}
+
+	if err := Load(cfg, map[string]string{"fake": "true"}, lister, nil, &recordingApplier{}, LoadOptions{
+		Timeout:   time.Second,
+		NoReplace: true,
+		Mover:     mover,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []movedWindow{
+		{id: 10, rect: Rect{X: 1920, Y: 0, Width: 1920, Height: 1440}},
+		{id: 11, rect: Rect{X: 3840, Y: 0, Width: 640, Height: 720}},
+	}
+	if !reflect.DeepEqual(mover.moves, want) {
+		t.Fatalf("moves = %+v, want %+v", mover.moves, want)
+	}
+}
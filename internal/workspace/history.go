@@ -0,0 +1,180 @@
+package workspace
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SnapshotTimeFormat names history snapshots; it sorts chronologically.
+const SnapshotTimeFormat = "20060102-150405.000"
+
+// historyNow is the snapshot clock (replaced in tests).
+var historyNow = time.Now
+
+// historyDir holds the snapshots of one workspace. It lives under the
+// workspaces directory, which List skips because it is a directory.
+func historyDir(name string) (string, error) {
+	if err := validateWorkspaceName(name); err != nil {
+		return "", err
+	}
+	dir, err := workspacesDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history", name), nil
+}
+
+func snapshotPath(name, snapshot string) (string, error) {
+	dir, err := historyDir(name)
+	if err != nil {
+		return "", err
+	}
+	if snapshot == "" || snapshot != filepath.Base(snapshot) || strings.HasPrefix(snapshot, ".") {
+		return "", fmt.Errorf("invalid snapshot %q", snapshot)
+	}
+	return filepath.Join(dir, snapshot+".json"), nil
+}
+
+// SnapshotWorkspace copies the saved file for name into its history before
+// it is overwritten, then prunes the history to the newest depth snapshots.
+// It returns the new snapshot's name, or "" when depth is 0 or there is no
+// saved file yet.
+func SnapshotWorkspace(name string, depth int) (string, error) {
+	if depth <= 0 {
+		return "", nil
+	}
+	path, err := workspacePath(name)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read workspace %q: %w", name, err)
+	}
+
+	dir, err := historyDir(name)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create workspace history directory: %w", err)
+	}
+	snapshot := historyNow().UTC().Format(SnapshotTimeFormat)
+	target, err := snapshotPath(name, snapshot)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(target, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write snapshot of workspace %q: %w", name, err)
+	}
+	if err := pruneSnapshots(name, depth); err != nil {
+		return snapshot, err
+	}
+	return snapshot, nil
+}
+
+// ListSnapshots returns the snapshots kept for name, newest first.
+func ListSnapshots(name string) ([]string, error) {
+	dir, err := historyDir(name)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list snapshots of workspace %q: %w", name, err)
+	}
+
+	var out []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		out = append(out, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(out)))
+	return out, nil
+}
+
+func pruneSnapshots(name string, depth int) error {
+	snapshots, err := ListSnapshots(name)
+	if err != nil {
+		return err
+	}
+	if len(snapshots) <= depth {
+		return nil
+	}
+	for _, snapshot := range snapshots[depth:] {
+		path, err := snapshotPath(name, snapshot)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to prune snapshot %q of workspace %q: %w", snapshot, name, err)
+		}
+	}
+	return nil
+}
+
+// RenameHistory moves the snapshots of oldName to newName, replacing any
+// history newName already had. It does nothing when oldName has none.
+func RenameHistory(oldName, newName string) error {
+	oldDir, err := historyDir(oldName)
+	if err != nil {
+		return err
+	}
+	newDir, err := historyDir(newName)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(oldDir); os.IsNotExist(err) {
+		return nil
+	}
+	if err := os.RemoveAll(newDir); err != nil {
+		return fmt.Errorf("failed to clear history of workspace %q: %w", newName, err)
+	}
+	if err := os.Rename(oldDir, newDir); err != nil {
+		return fmt.Errorf("failed to move history of workspace %q to %q: %w", oldName, newName, err)
+	}
+	return nil
+}
+
+// RestoreSnapshot replaces the saved file for name with snapshot. The file
+// being replaced is itself snapshotted first (subject to depth), so a
+// restore can be rolled back too.
+func RestoreSnapshot(name, snapshot string, depth int) (*WorkspaceConfig, error) {
+	path, err := snapshotPath(name, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("workspace %q has no snapshot %q", name, snapshot)
+		}
+		return nil, fmt.Errorf("failed to read snapshot %q of workspace %q: %w", snapshot, name, err)
+	}
+	var cfg WorkspaceConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %q of workspace %q: %w", snapshot, name, err)
+	}
+	cfg.Name = name
+
+	if _, err := SnapshotWorkspace(name, depth); err != nil {
+		return nil, err
+	}
+	if err := Write(&cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
@@ -0,0 +1,169 @@
+package workspace
+
+import (
+	"testing"
+	"time"
+)
+
+// stepHistoryClock makes each snapshot one second newer than the last.
+func stepHistoryClock(t *testing.T) {
+	t.Helper()
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	orig := historyNow
+	historyNow = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	t.Cleanup(func() { historyNow = orig })
+}
+
+func saveWithHistory(t *testing.T, layout string, depth int) string {
+	t.Helper()
+	snapshot, err := SnapshotWorkspace("dev", depth)
+	if err != nil {
+		t.Fatalf("SnapshotWorkspace: %v", err)
+	}
+	if err := Write(&WorkspaceConfig{Name: "dev", Layout: layout}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	return snapshot
+}
+
+func TestSnapshotWorkspace_KeepsNewestDepth(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	stepHistoryClock(t)
+
+	// The first save has nothing to snapshot.
+	if got := saveWithHistory(t, "grid", 2); got != "" {
+		t.Fatalf("first save snapshot = %q, want none", got)
+	}
+	first := saveWithHistory(t, "columns", 2)
+	second := saveWithHistory(t, "rows", 2)
+	third := saveWithHistory(t, "master-stack", 2)
+
+	snapshots, err := ListSnapshots("dev")
+	if err != nil {
+		t.Fatalf("ListSnapshots: %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0] != third || snapshots[1] != second {
+		t.Fatalf("snapshots = %v, want [%s %s] (oldest %s pruned)", snapshots, third, second, first)
+	}
+
+	names, err := List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(names) != 1 || names[0] != "dev" {
+		t.Fatalf("List = %v, want only dev (history must not show up)", names)
+	}
+}
+
+func TestSnapshotWorkspace_DisabledAtZeroDepth(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	stepHistoryClock(t)
+
+	saveWithHistory(t, "grid", 0)
+	saveWithHistory(t, "rows", 0)
+	snapshots, err := ListSnapshots("dev")
+	if err != nil {
+		t.Fatalf("ListSnapshots: %v", err)
+	}
+	if len(snapshots) != 0 {
+		t.Fatalf("snapshots = %v, want none", snapshots)
+	}
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	stepHistoryClock(t)
+
+	saveWithHistory(t, "grid", 3)
+	good := saveWithHistory(t, "rows", 3) // holds the "grid" save
+
+	restored, err := RestoreSnapshot("dev", good, 3)
+	if err != nil {
+		t.Fatalf("RestoreSnapshot: %v", err)
+	}
+	if restored.Layout != "grid" {
+		t.Fatalf("restored layout = %q, want grid", restored.Layout)
+	}
+	ws, err := Read("dev")
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if ws.Layout != "grid" {
+		t.Fatalf("saved layout after restore = %q, want grid", ws.Layout)
+	}
+
+	// The replaced "rows" save was snapshotted, so the restore can be undone.
+	snapshots, err := ListSnapshots("dev")
+	if err != nil {
+		t.Fatalf("ListSnapshots: %v", err)
+	}
+	if len(snapshots) != 2 {
+		t.Fatalf("snapshots = %v, want the original plus the pre-restore one", snapshots)
+	}
+	undone, err := RestoreSnapshot("dev", snapshots[0], 3)
+	if err != nil {
+		t.Fatalf("RestoreSnapshot(undo): %v", err)
+	}
+	if undone.Layout != "rows" {
+		t.Fatalf("undo layout = %q, want rows", undone.Layout)
+	}
+
+	if _, err := RestoreSnapshot("dev", "19990101-000000.000", 3); err == nil {
+		t.Fatal("expected an error for a missing snapshot")
+	}
+	if _, err := RestoreSnapshot("dev", "../dev", 3); err == nil {
+		t.Fatal("expected an error for a snapshot name with a path")
+	}
+}
+
+func TestDelete_RemovesHistory(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	stepHistoryClock(t)
+
+	saveWithHistory(t, "grid", 3)
+	saveWithHistory(t, "rows", 3)
+	paths, err := DeleteTargets("dev")
+	if err != nil {
+		t.Fatalf("DeleteTargets: %v", err)
+	}
+	if len(paths) != 2 {
+		t.Fatalf("paths = %v, want the workspace file and its history", paths)
+	}
+	if err := Delete("dev"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	// A new workspace with the same name starts without the old snapshots.
+	saveWithHistory(t, "grid", 3)
+	snapshots, err := ListSnapshots("dev")
+	if err != nil {
+		t.Fatalf("ListSnapshots: %v", err)
+	}
+	if len(snapshots) != 0 {
+		t.Fatalf("snapshots = %v, want none after delete", snapshots)
+	}
+}
+
+func TestRenameHistory(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	stepHistoryClock(t)
+
+	saveWithHistory(t, "grid", 3)
+	want := saveWithHistory(t, "rows", 3)
+	if err := RenameHistory("dev", "prod"); err != nil {
+		t.Fatalf("RenameHistory: %v", err)
+	}
+	if old, _ := ListSnapshots("dev"); len(old) != 0 {
+		t.Fatalf("dev snapshots = %v, want none", old)
+	}
+	moved, err := ListSnapshots("prod")
+	if err != nil || len(moved) != 1 || moved[0] != want {
+		t.Fatalf("prod snapshots = %v (err=%v), want [%s]", moved, err, want)
+	}
+	if err := RenameHistory("missing", "other"); err != nil {
+		t.Fatalf("RenameHistory without history: %v", err)
+	}
+}
@@ -79,10 +79,19 @@ func ReconcileRegistry() error {
 
 // CheckCanAddTerminal verifies limits allow adding a terminal to an existing workspace.
 func CheckCanAddTerminal(wsName string, currentCount int, cfg *config.Config) error {
+	return CheckCanAddTerminals(wsName, currentCount, 1, cfg)
+}
+
+// CheckCanAddTerminals verifies limits allow adding count terminals to a
+// workspace that currently has currentCount.
+func CheckCanAddTerminals(wsName string, currentCount, count int, cfg *config.Config) error {
 	maxForWorkspace := cfg.GetMaxTerminalsForWorkspace(wsName)
 	if currentCount >= maxForWorkspace {
 		return fmt.Errorf("workspace %q at terminal limit (%d/%d)", wsName, currentCount, maxForWorkspace)
 	}
+	if currentCount+count > maxForWorkspace {
+		return fmt.Errorf("adding %d terminals would exceed workspace %q limit (%d+%d > %d)", count, wsName, currentCount, count, maxForWorkspace)
+	}
 
 	totalTerminals, err := countAllWorkspaceTerminals()
 	if err != nil {
@@ -92,6 +101,9 @@ func CheckCanAddTerminal(wsName string, currentCount int, cfg *config.Config) er
 	if totalTerminals >= maxTotal {
 		return fmt.Errorf("global terminal limit reached (%d/%d)", totalTerminals, maxTotal)
 	}
+	if totalTerminals+count > maxTotal {
+		return fmt.Errorf("would exceed global terminal limit (%d+%d > %d)", totalTerminals, count, maxTotal)
+	}
 
 	return nil
 }
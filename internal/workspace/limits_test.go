@@ -50,6 +50,33 @@ func TestWorkspaceLimits_Checks(t *testing.T) {
 	}
 }
 
+func TestCheckCanAddTerminals_CountsWholeBatch(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	cfg := config.DefaultConfig()
+	cfg.Limits.MaxTerminalsPerWorkspace = 4
+	cfg.Limits.MaxTerminalsTotal = 6
+
+	if err := SetActiveWorkspace("ws1", 2, false, 0, nil); err != nil {
+		t.Fatalf("set active workspace: %v", err)
+	}
+	if err := SetActiveWorkspace("ws2", 2, false, 1, nil); err != nil {
+		t.Fatalf("set active workspace: %v", err)
+	}
+
+	if err := CheckCanAddTerminals("ws1", 2, 2, cfg); err != nil {
+		t.Fatalf("expected batch of 2 to fit, got %v", err)
+	}
+	if err := CheckCanAddTerminals("ws1", 2, 3, cfg); err == nil || !strings.Contains(err.Error(), "would exceed workspace \"ws1\" limit") {
+		t.Fatalf("expected per-workspace batch limit error, got %v", err)
+	}
+
+	cfg.Limits.MaxTerminalsTotal = 5
+	if err := CheckCanAddTerminals("ws1", 2, 2, cfg); err == nil || !strings.Contains(err.Error(), "would exceed global terminal limit") {
+		t.Fatalf("expected global batch limit error, got %v", err)
+	}
+}
+
 func TestReconcileRegistry_RemovesStaleAgentWorkspace(t *testing.T) {
 	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
 
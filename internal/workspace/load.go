@@ -44,13 +44,36 @@ func notifyDesktop(summary, body string) {
 	_ = cmd.Start() // Fire and forget
 }
 
-// tmuxSessionExists checks if a tmux session with the given name exists.
-func tmuxSessionExists(session string) bool {
-	cmd := exec.Command("tmux", "has-session", "-t", session)
-	return cmd.Run() == nil
+// sessionExists checks if mux has a session with the given name.
+func sessionExists(mux agent.Multiplexer, session string) bool {
+	ok, err := mux.HasSession(session)
+	return err == nil && ok
 }
 
-func Load(cfg *WorkspaceConfig, spawnTemplates map[string]string, lister TerminalLister, minimizer WindowMinimizer, applier LayoutApplier, opts LoadOptions) error {
+// TmuxAttachCommand returns the command that attaches a terminal to an
+// existing tmux session, passing configPath with -f when it is set.
+func TmuxAttachCommand(configPath, session string) string {
+	if configPath != "" {
+		return fmt.Sprintf("tmux -f %s attach -t %s", configPath, session)
+	}
+	return fmt.Sprintf("tmux attach -t %s", session)
+}
+
+// AttachCommand returns the command that attaches a terminal to an existing
+// session of mux. Other multiplexers have no attach-only command, so their
+// create-or-attach SessionCommand is used.
+func AttachCommand(mux agent.Multiplexer, configPath, session string) string {
+	if mux.Name() == "tmux" {
+		return TmuxAttachCommand(configPath, session)
+	}
+	return mux.SessionCommand(session)
+}
+
+// Load spawns the terminals of cfg, waits for their windows and tiles them.
+// If it fails after spawning anything, the terminals it started are closed,
+// the multiplexer sessions it created are killed and their slot registry
+// entries are dropped, unless opts.KeepPartial is set.
+func Load(cfg *WorkspaceConfig, spawnTemplates map[string]string, lister TerminalLister, minimizer WindowMinimizer, applier LayoutApplier, opts LoadOptions) (retErr error) {
 	if cfg == nil {
 		return fmt.Errorf("workspace is nil")
 	}
@@ -73,7 +96,7 @@ func Load(cfg *WorkspaceConfig, spawnTemplates map[string]string, lister Termina
 	debugf := newWorkspaceLoadDebugf()
 	if debugf != nil {
 		debugf(
-			"Load start name=%q layout=%q agent_mode=%v terminals=%d timeout=%s rerun=%v no_replace=%v auto_save_layout=%q auto_save_sort=%q",
+			"Load start name=%q layout=%q agent_mode=%v terminals=%d timeout=%s rerun=%v no_replace=%v no_auto_save=%v auto_save_layout=%q auto_save_sort=%q",
 			cfg.Name,
 			cfg.Layout,
 			cfg.AgentMode,
@@ -81,6 +104,7 @@ func Load(cfg *WorkspaceConfig, spawnTemplates map[string]string, lister Termina
 			opts.Timeout,
 			opts.RerunCommand,
 			opts.NoReplace,
+			opts.NoAutoSave,
 			opts.AutoSaveLayout,
 			opts.AutoSaveTerminalSort,
 		)
@@ -125,7 +149,7 @@ func Load(cfg *WorkspaceConfig, spawnTemplates map[string]string, lister Termina
 			return fmt.Errorf("window minimizer is nil")
 		}
 
-		if cfg.Name != "_previous" {
+		if cfg.Name != "_previous" && !opts.NoAutoSave {
 			layout := strings.TrimSpace(opts.AutoSaveLayout)
 			if layout == "" {
 				return fmt.Errorf("auto-save layout is required")
@@ -134,7 +158,7 @@ func Load(cfg *WorkspaceConfig, spawnTemplates map[string]string, lister Termina
 			if debugf != nil {
 				debugf("Auto-saving previous workspace to %q layout=%q terminal_sort=%q", "_previous", layout, opts.AutoSaveTerminalSort)
 			}
-			prev, err := Save("_previous", layout, opts.AutoSaveTerminalSort, false, lister)
+			prev, err := Save("_previous", layout, opts.AutoSaveTerminalSort, false, "", lister)
 			if err != nil {
 				return err
 			}
@@ -193,8 +217,38 @@ func Load(cfg *WorkspaceConfig, spawnTemplates map[string]string, lister Termina
 		}
 	}
 
+	// Use cross-desktop listing for detection when NoReplace is set.
+	var crossDesktopLister CrossDesktopLister
+	if opts.NoReplace {
+		crossDesktopLister, _ = lister.(CrossDesktopLister)
+	}
+
+	// Track what this load spawned so a failure can be rolled back.
+	rb := &loadRollback{
+		lister:   lister,
+		cdl:      crossDesktopLister,
+		existing: existing,
+		timeout:  opts.Timeout,
+		debugf:   debugf,
+	}
+	if configMgr != nil {
+		rb.mux = configMgr.Multiplexer()
+	}
+	defer func() {
+		if retErr == nil || len(rb.spawned) == 0 {
+			return
+		}
+		if opts.KeepPartial {
+			log.Printf("workspace: keeping %d partially spawned terminal(s)", len(rb.spawned))
+			return
+		}
+		closed := rb.run()
+		retErr = fmt.Errorf("%w (rolled back %d spawned terminal(s))", retErr, closed)
+	}()
+
 	for _, term := range terms {
 		cmdOverride := ""
+		createdSession := ""
 		if cfg.AgentMode && configMgr != nil {
 			cwd := strings.TrimSpace(term.Cwd)
 			if cwd == "" {
@@ -209,23 +263,19 @@ func Load(cfg *WorkspaceConfig, spawnTemplates map[string]string, lister Termina
 
 			// Check if session already exists - if so, attach instead of create
 			var sessionCmd string
-			if tmuxSessionExists(session) {
+			if sessionExists(configMgr.Multiplexer(), session) {
 				if debugf != nil {
 					debugf("Session %q exists, will attach", session)
 				}
 				// Attach to existing session
-				configPath := configMgr.GetConfigPath()
-				if configPath != "" {
-					sessionCmd = fmt.Sprintf("tmux -f %s attach -t %s", configPath, session)
-				} else {
-					sessionCmd = fmt.Sprintf("tmux attach -t %s", session)
-				}
+				sessionCmd = AttachCommand(configMgr.Multiplexer(), configMgr.GetConfigPath(), session)
 			} else {
 				if debugf != nil {
 					debugf("Session %q does not exist, will create", session)
 				}
 				// Use the multiplexer's session command (includes config path if available)
 				sessionCmd = configMgr.SessionCommand(session)
+				createdSession = session
 			}
 
 			if debugf != nil {
@@ -241,7 +291,11 @@ func Load(cfg *WorkspaceConfig, spawnTemplates map[string]string, lister Termina
 				return fmt.Errorf("failed to parse multiplexer session command: %w", err)
 			}
 			muxArgs = append(muxArgs, baseArgs...)
-			muxArgs = append(muxArgs, "-c", cwd)
+			// screen's -c names a config file; its sessions start in the
+			// terminal's directory instead.
+			if configMgr.Name() == "tmux" {
+				muxArgs = append(muxArgs, "-c", cwd)
+			}
 			if opts.RerunCommand && len(term.Cmd) > 0 {
 				muxArgs = append(muxArgs, term.Cmd...)
 			}
@@ -254,18 +308,17 @@ func Load(cfg *WorkspaceConfig, spawnTemplates map[string]string, lister Termina
 				debugf("  cmd=%q", cmdOverride)
 			}
 		}
-		if err := spawnTerminal(term, spawnTemplates, opts.RerunCommand, cmdOverride); err != nil {
+		if err := spawnTerminal(term, spawnTemplates, opts.RerunCommand, cmdOverride, opts.AppConfig.SpawnEnvList(term.WMClass)); err != nil {
 			return err
 		}
-	}
-
-	// Use cross-desktop listing for detection when NoReplace is set.
-	var crossDesktopLister CrossDesktopLister
-	if opts.NoReplace {
-		crossDesktopLister, _ = lister.(CrossDesktopLister)
+		rb.spawned = append(rb.spawned, term)
+		if createdSession != "" {
+			rb.sessions = append(rb.sessions, createdSession)
+		}
 	}
 
 	newWindowIDs, err := waitForNewTerminals(lister, crossDesktopLister, existing, terms, opts.Timeout, debugf)
+	rb.windowIDs, rb.detected = newWindowIDs, true
 	if err != nil {
 		return err
 	}
@@ -285,6 +338,7 @@ func Load(cfg *WorkspaceConfig, spawnTemplates map[string]string, lister Termina
 	}
 
 	// For agent mode, verify window titles match expected slots and re-tile if needed
+	order := newWindowIDs
 	if cfg.AgentMode {
 		type windowTitleLister interface {
 			WindowTitle(windowID uint32) (string, error)
@@ -312,6 +366,7 @@ func Load(cfg *WorkspaceConfig, spawnTemplates map[string]string, lister Termina
 					}
 				}
 				if needsRetile {
+					order = matched
 					if debugf != nil {
 						debugf("Re-tiling required: spawn_order=%v matched_order=%v", newWindowIDs, matched)
 					}
@@ -333,6 +388,10 @@ func Load(cfg *WorkspaceConfig, spawnTemplates map[string]string, lister Termina
 		}
 	}
 
+	if cfg.GeometryMode != "" && opts.Mover != nil {
+		restoreLoadGeometry(cfg.GeometryMode, terms, order, lister, opts.Mover, debugf)
+	}
+
 	// Show completion notification
 	notifyDesktop("Workspace loaded", fmt.Sprintf("%s is ready (%d terminals)", cfg.Name, len(terms)))
 	if debugf != nil {
@@ -341,18 +400,111 @@ func Load(cfg *WorkspaceConfig, spawnTemplates map[string]string, lister Termina
 	return nil
 }
 
-func spawnTerminal(term TerminalConfig, templates map[string]string, rerun bool, cmdOverride string) error {
+// loadRollback records what a Load spawned so a failed load can undo it.
+type loadRollback struct {
+	lister   TerminalLister
+	cdl      CrossDesktopLister
+	existing map[uint32]struct{}
+	mux      agent.Multiplexer
+	timeout  time.Duration
+	debugf   func(string, ...any)
+
+	spawned   []TerminalConfig // terminals whose spawn command started
+	sessions  []string         // multiplexer sessions created (not attached) by this load
+	detected  bool             // window detection ran; windowIDs holds its matches
+	windowIDs []uint32
+}
+
+// run closes the spawned windows, kills the created sessions and removes
+// slot registry entries for the closed windows. It returns how many windows
+// were closed. Failures are logged rather than returned so they don't mask
+// the load error.
+func (rb *loadRollback) run() int {
+	ids := rb.windowIDs
+	if !rb.detected {
+		// Spawning itself failed; give the terminals that did start a chance
+		// to map so they can be found.
+		ids, _ = waitForNewTerminals(rb.lister, rb.cdl, rb.existing, rb.spawned, rb.timeout, rb.debugf)
+	}
+
+	var windows []TerminalWindow
+	var err error
+	if rb.cdl != nil {
+		windows, err = rb.cdl.ListTerminalsAllDesktops()
+	} else {
+		windows, err = rb.lister.ListTerminals()
+	}
+	if err != nil {
+		log.Printf("workspace: warning: rollback could not list terminals: %v", err)
+	}
+	pids := make(map[uint32]int, len(windows))
+	for _, w := range windows {
+		pids[w.WindowID] = w.PID
+	}
+
+	closed := 0
+	for _, id := range ids {
+		if _, ok := rb.existing[id]; ok {
+			continue
+		}
+		pid := pids[id]
+		if pid <= 0 {
+			log.Printf("workspace: warning: rollback cannot close window %d (unknown PID)", id)
+		} else if err := killTerminalProcess(pid); err != nil {
+			log.Printf("workspace: warning: rollback failed to close window %d: %v", id, err)
+		} else {
+			closed++
+		}
+		if _, ok := GetSlotByWindowID(id); ok {
+			if err := RemoveSlotByWindowID(id); err != nil {
+				log.Printf("workspace: warning: rollback failed to clear slot for window %d: %v", id, err)
+			}
+		}
+	}
+
+	if rb.mux != nil {
+		for _, session := range rb.sessions {
+			if ok, _ := rb.mux.HasSession(session); !ok {
+				continue
+			}
+			if err := rb.mux.KillSession(session); err != nil {
+				log.Printf("workspace: warning: rollback failed to kill session %q: %v", session, err)
+			}
+		}
+	}
+	if rb.debugf != nil {
+		rb.debugf("Rollback closed %d window(s) of %d spawned; sessions=%v", closed, len(rb.spawned), rb.sessions)
+	}
+	return closed
+}
+
+func spawnTerminal(term TerminalConfig, templates map[string]string, rerun bool, cmdOverride string, env []string) error {
+	cmd, err := TerminalSpawnCmd(term, templates, rerun, cmdOverride, env)
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to spawn %q: %w", strings.TrimSpace(term.WMClass), err)
+	}
+	// Do not wait; terminals are long-lived.
+	return nil
+}
+
+// TerminalSpawnCmd builds the command that opens term. cmdOverride, or
+// term.Cmd when rerun is set, fills the template's {{cmd}}. env (from
+// terminal_spawn_env) is added on top of termtile's own environment.
+func TerminalSpawnCmd(term TerminalConfig, templates map[string]string, rerun bool, cmdOverride string, env []string) (*exec.Cmd, error) {
 	class := strings.TrimSpace(term.WMClass)
 	if class == "" {
-		return fmt.Errorf("workspace terminal WMClass is empty")
+		return nil, fmt.Errorf("workspace terminal WMClass is empty")
 	}
 
 	template, ok := lookupSpawnTemplate(templates, class)
 	if !ok {
-		return fmt.Errorf("no spawn template configured for terminal class %q (set terminal_spawn_commands.%s)", class, class)
+		return nil, fmt.Errorf("no spawn template configured for terminal class %q (set terminal_spawn_commands.%s)", class, class)
 	}
 	if cmdOverride != "" && !strings.Contains(template, "{{cmd}}") {
-		return fmt.Errorf("spawn template for %q must include {{cmd}} for agent-mode workspaces (set terminal_spawn_commands.%s)", class, class)
+		return nil, fmt.Errorf("spawn template for %q must include {{cmd}} for agent-mode workspaces (set terminal_spawn_commands.%s)", class, class)
 	}
 
 	cwd := strings.TrimSpace(term.Cwd)
@@ -369,18 +521,17 @@ func spawnTerminal(term TerminalConfig, templates map[string]string, rerun bool,
 
 	argv, err := renderCommandTemplate(template, cwd, cmdStr)
 	if err != nil {
-		return fmt.Errorf("failed to render spawn template for %q: %w", class, err)
+		return nil, fmt.Errorf("failed to render spawn template for %q: %w", class, err)
 	}
 	if len(argv) == 0 {
-		return fmt.Errorf("spawn template for %q produced empty command", class)
+		return nil, fmt.Errorf("spawn template for %q produced empty command", class)
 	}
 
 	cmd := exec.Command(argv[0], argv[1:]...)
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("failed to spawn %q: %w", class, err)
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
 	}
-	// Do not wait; terminals are long-lived.
-	return nil
+	return cmd, nil
 }
 
 func lookupSpawnTemplate(templates map[string]string, class string) (string, bool) {
@@ -499,7 +650,13 @@ func waitForNewTerminals(lister TerminalLister, cdl CrossDesktopLister, existing
 			if len(missing) > 0 {
 				reason = fmt.Sprintf("%s; missing classes: %s", reason, strings.Join(missing, ", "))
 			}
-			return windowIDsBySlot[:assigned], fmt.Errorf("%s", reason)
+			matched := make([]uint32, 0, assigned)
+			for _, id := range windowIDsBySlot {
+				if id != 0 {
+					matched = append(matched, id)
+				}
+			}
+			return matched, fmt.Errorf("%s", reason)
 		}
 
 		<-ticker.C
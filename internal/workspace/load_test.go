@@ -1,6 +1,17 @@
 package workspace
 
-import "testing"
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1broseidon/termtile/internal/config"
+)
 
 func TestWMClassesMatch(t *testing.T) {
 	tests := []struct {
@@ -38,3 +49,230 @@ func TestWMClassesMatch(t *testing.T) {
 		})
 	}
 }
+
+// spawnLister reports before on its first call (Load's pre-spawn capture) and
+// before plus spawned afterwards, as if the spawned terminals mapped at once.
+type spawnLister struct {
+	before  []TerminalWindow
+	spawned []TerminalWindow
+	calls   int
+}
+
+func (l *spawnLister) ListTerminals() ([]TerminalWindow, error) {
+	l.calls++
+	if l.calls == 1 {
+		return l.before, nil
+	}
+	return append(append([]TerminalWindow{}, l.before...), l.spawned...), nil
+}
+
+func (l *spawnLister) ActiveWindowID() (uint32, error) { return 0, nil }
+
+type recordingApplier struct{ orders [][]uint32 }
+
+func (a *recordingApplier) ApplyLayout(string, bool) error { return nil }
+func (a *recordingApplier) ApplyLayoutWithOrder(_ string, order []uint32) error {
+	a.orders = append(a.orders, order)
+	return nil
+}
+
+func stubKillTerminalProcess(t *testing.T) *[]int {
+	t.Helper()
+	var killed []int
+	orig := killTerminalProcess
+	killTerminalProcess = func(pid int) error {
+		killed = append(killed, pid)
+		return nil
+	}
+	t.Cleanup(func() { killTerminalProcess = orig })
+	return &killed
+}
+
+func fakeTerminals(classes ...string) []TerminalConfig {
+	terms := make([]TerminalConfig, len(classes))
+	for i, class := range classes {
+		terms[i] = TerminalConfig{WMClass: class, Cwd: "/", SlotIndex: i}
+	}
+	return terms
+}
+
+func TestLoad_RollsBackOnMidSpawnFailure(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	killed := stubKillTerminalProcess(t)
+
+	// A pre-existing window and a stale entry for one of the new windows.
+	if err := SetSlotInfo(1, 0, "other", 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetSlotInfo(11, 1, "ws-1", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	lister := &spawnLister{
+		before:  []TerminalWindow{{WindowID: 1, WMClass: "fake", PID: 100}},
+		spawned: []TerminalWindow{{WindowID: 10, WMClass: "fake", PID: 110}, {WindowID: 11, WMClass: "fake", PID: 111}},
+	}
+	applier := &recordingApplier{}
+	cfg := &WorkspaceConfig{Name: "ws", Layout: "grid", Terminals: fakeTerminals("fake", "fake", "missing")}
+
+	err := Load(cfg, map[string]string{"fake": "true"}, lister, nil, applier, LoadOptions{
+		Timeout:   time.Second,
+		NoReplace: true,
+	})
+	if err == nil || !strings.Contains(err.Error(), "no spawn template") || !strings.Contains(err.Error(), "rolled back 2") {
+		t.Fatalf("Load() err=%v, want spawn failure with rollback of 2", err)
+	}
+	sort.Ints(*killed)
+	if !reflect.DeepEqual(*killed, []int{110, 111}) {
+		t.Fatalf("killed=%v, want [110 111]", *killed)
+	}
+	if len(applier.orders) != 0 {
+		t.Fatalf("layout applied %v, want none after failure", applier.orders)
+	}
+	if _, ok := GetSlotByWindowID(11); ok {
+		t.Fatal("slot for rolled-back window 11 still registered")
+	}
+	if _, ok := GetSlotByWindowID(1); !ok {
+		t.Fatal("slot for pre-existing window 1 was removed")
+	}
+}
+
+func TestLoad_TimeoutRollsBackUnlessKeepPartial(t *testing.T) {
+	for _, keep := range []bool{false, true} {
+		t.Run(fmt.Sprintf("keep_partial=%v", keep), func(t *testing.T) {
+			t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+			killed := stubKillTerminalProcess(t)
+
+			// Three terminals requested, only two ever appear.
+			lister := &spawnLister{
+				spawned: []TerminalWindow{{WindowID: 10, WMClass: "fake", PID: 110}, {WindowID: 11, WMClass: "fake", PID: 111}},
+			}
+			cfg := &WorkspaceConfig{Name: "ws", Layout: "grid", Terminals: fakeTerminals("fake", "fake", "fake")}
+
+			err := Load(cfg, map[string]string{"fake": "true"}, lister, nil, &recordingApplier{}, LoadOptions{
+				Timeout:     300 * time.Millisecond,
+				NoReplace:   true,
+				KeepPartial: keep,
+			})
+			if err == nil || !strings.Contains(err.Error(), "timeout waiting for spawned terminals") {
+				t.Fatalf("Load() err=%v, want timeout", err)
+			}
+			if keep {
+				if len(*killed) != 0 || strings.Contains(err.Error(), "rolled back") {
+					t.Fatalf("killed=%v err=%v, want nothing rolled back", *killed, err)
+				}
+				return
+			}
+			sort.Ints(*killed)
+			if !reflect.DeepEqual(*killed, []int{110, 111}) || !strings.Contains(err.Error(), "rolled back 2") {
+				t.Fatalf("killed=%v err=%v, want both spawned terminals closed", *killed, err)
+			}
+		})
+	}
+}
+
+type nopMinimizer struct{}
+
+func (nopMinimizer) MinimizeWindow(uint32) error { return nil }
+
+func TestLoad_NoAutoSaveSkipsPrevious(t *testing.T) {
+	for _, noAutoSave := range []bool{false, true} {
+		t.Run(fmt.Sprintf("no_auto_save=%v", noAutoSave), func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+			lister := &spawnLister{
+				before:  []TerminalWindow{{WindowID: 1, WMClass: "fake", PID: 100}},
+				spawned: []TerminalWindow{{WindowID: 10, WMClass: "fake", PID: 110}},
+			}
+			cfg := &WorkspaceConfig{Name: "ws", Layout: "grid", Terminals: fakeTerminals("fake")}
+
+			err := Load(cfg, map[string]string{"fake": "true"}, lister, nopMinimizer{}, &recordingApplier{}, LoadOptions{
+				Timeout:        time.Second,
+				AutoSaveLayout: "grid",
+				NoAutoSave:     noAutoSave,
+			})
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+
+			_, err = Read("_previous")
+			if noAutoSave && err == nil {
+				t.Fatal("_previous was saved with NoAutoSave set")
+			}
+			if !noAutoSave && err != nil {
+				t.Fatalf("_previous not saved: %v", err)
+			}
+		})
+	}
+}
+
+func TestLoad_AppliesTerminalSpawnEnv(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	out := filepath.Join(t.TempDir(), "env")
+	appCfg := config.DefaultConfig()
+	appCfg.TerminalSpawnEnv = map[string]map[string]string{"fake": {"TERMTILE_SPAWN_TEST": "x11"}}
+	templates := map[string]string{"fake": `sh -c "printenv TERMTILE_SPAWN_TEST > ` + out + `"`}
+	lister := &spawnLister{spawned: []TerminalWindow{{WindowID: 10, WMClass: "fake", PID: 110}}}
+	cfg := &WorkspaceConfig{Name: "ws", Layout: "grid", Terminals: fakeTerminals("fake")}
+
+	if err := Load(cfg, templates, lister, nil, &recordingApplier{}, LoadOptions{
+		Timeout:   time.Second,
+		NoReplace: true,
+		AppConfig: appCfg,
+	}); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, err := os.ReadFile(out)
+		if err == nil && strings.TrimSpace(string(data)) == "x11" {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("spawned terminal env = %q (err=%v), want TERMTILE_SPAWN_TEST=x11", data, err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestTerminalSpawnCmd_ClassEnv(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.TerminalSpawnEnv = map[string]map[string]string{
+		"kitty": {"GDK_BACKEND": "x11", "KITTY_ENABLE_WAYLAND": "0"},
+	}
+	templates := map[string]string{"kitty": "kitty --directory {{dir}}"}
+	term := TerminalConfig{WMClass: "Kitty", Cwd: "/tmp", SlotIndex: 0}
+
+	cmd, err := TerminalSpawnCmd(term, templates, false, "", cfg.SpawnEnvList(term.WMClass))
+	if err != nil {
+		t.Fatalf("TerminalSpawnCmd: %v", err)
+	}
+	for _, want := range []string{"GDK_BACKEND=x11", "KITTY_ENABLE_WAYLAND=0"} {
+		if !containsString(cmd.Env, want) {
+			t.Errorf("cmd.Env missing %q", want)
+		}
+	}
+
+	// Classes without an entry inherit termtile's environment unchanged.
+	other := TerminalConfig{WMClass: "alacritty", Cwd: "/tmp"}
+	cmd, err = TerminalSpawnCmd(other, map[string]string{"alacritty": "alacritty"}, false, "", cfg.SpawnEnvList(other.WMClass))
+	if err != nil {
+		t.Fatalf("TerminalSpawnCmd: %v", err)
+	}
+	if cmd.Env != nil {
+		t.Fatalf("cmd.Env = %v, want nil (inherit)", cmd.Env)
+	}
+}
+
+func containsString(list []string, want string) bool {
+	for _, s := range list {
+		if s == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package workspace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// registryExportVersion is bumped when the export document changes shape.
+const registryExportVersion = 1
+
+// RegistryExport is the portable form of the workspace registry.
+// Workspaces and slots are stored as lists (rather than maps keyed by desktop
+// or window ID) so that duplicate keys in a hand-edited file are detected on
+// import instead of being silently collapsed by the JSON decoder.
+type RegistryExport struct {
+	Version    int             `json:"version"`
+	ExportedAt time.Time       `json:"exported_at"`
+	Workspaces []WorkspaceInfo `json:"workspaces"`
+	Slots      []SlotInfo      `json:"slots,omitempty"`
+}
+
+// ExportRegistry writes the current workspace registry to w as indented JSON.
+func ExportRegistry(w io.Writer) error {
+	registry, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+
+	out := RegistryExport{
+		Version:    registryExportVersion,
+		ExportedAt: time.Now(),
+		Workspaces: make([]WorkspaceInfo, 0, len(registry.Workspaces)),
+	}
+	for _, ws := range registry.Workspaces {
+		out.Workspaces = append(out.Workspaces, ws)
+	}
+	sort.Slice(out.Workspaces, func(i, j int) bool {
+		return out.Workspaces[i].Desktop < out.Workspaces[j].Desktop
+	})
+	for _, slot := range registry.Slots {
+		out.Slots = append(out.Slots, slot)
+	}
+	sort.Slice(out.Slots, func(i, j int) bool {
+		if out.Slots[i].Desktop != out.Slots[j].Desktop {
+			return out.Slots[i].Desktop < out.Slots[j].Desktop
+		}
+		return out.Slots[i].SlotIndex < out.Slots[j].SlotIndex
+	})
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return fmt.Errorf("failed to encode workspace registry: %w", err)
+	}
+	return nil
+}
+
+// ImportRegistry validates a registry export read from r and replaces the
+// on-disk registry with it. The existing registry is left untouched if the
+// document fails validation. It returns the number of workspaces imported.
+func ImportRegistry(r io.Reader) (int, error) {
+	var in RegistryExport
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&in); err != nil {
+		return 0, fmt.Errorf("failed to parse registry export: %w", err)
+	}
+
+	registry, err := in.toRegistry()
+	if err != nil {
+		return 0, err
+	}
+	if err := saveRegistry(registry); err != nil {
+		return 0, err
+	}
+	return len(registry.Workspaces), nil
+}
+
+// toRegistry validates the export and converts it to the on-disk form.
+func (e *RegistryExport) toRegistry() (*workspaceRegistry, error) {
+	if e.Version != registryExportVersion {
+		return nil, fmt.Errorf("unsupported registry export version %d (expected %d)", e.Version, registryExportVersion)
+	}
+
+	registry := &workspaceRegistry{
+		Workspaces: make(map[int]WorkspaceInfo, len(e.Workspaces)),
+		Slots:      make(map[uint32]SlotInfo, len(e.Slots)),
+	}
+
+	names := make(map[string]int, len(e.Workspaces))
+	for i, ws := range e.Workspaces {
+		if err := validateWorkspaceName(ws.Name); err != nil {
+			return nil, fmt.Errorf("workspaces[%d]: %w", i, err)
+		}
+		if ws.Desktop < 0 {
+			return nil, fmt.Errorf("workspaces[%d]: desktop must be >= 0, got %d", i, ws.Desktop)
+		}
+		if existing, ok := registry.Workspaces[ws.Desktop]; ok {
+			return nil, fmt.Errorf("workspaces[%d]: duplicate desktop %d (already assigned to %q)", i, ws.Desktop, existing.Name)
+		}
+		if desktop, ok := names[ws.Name]; ok {
+			return nil, fmt.Errorf("workspaces[%d]: workspace %q already registered on desktop %d", i, ws.Name, desktop)
+		}
+		if ws.TerminalCount < 0 {
+			return nil, fmt.Errorf("workspaces[%d]: terminal_count must be >= 0, got %d", i, ws.TerminalCount)
+		}
+		for _, slot := range ws.AgentSlots {
+			if slot < 0 || slot >= ws.TerminalCount {
+				return nil, fmt.Errorf("workspaces[%d]: agent slot %d out of range (workspace has %d terminals)", i, slot, ws.TerminalCount)
+			}
+		}
+		names[ws.Name] = ws.Desktop
+		registry.Workspaces[ws.Desktop] = ws
+	}
+
+	for i, slot := range e.Slots {
+		if slot.WindowID == 0 {
+			return nil, fmt.Errorf("slots[%d]: window_id is required", i)
+		}
+		if _, ok := registry.Slots[slot.WindowID]; ok {
+			return nil, fmt.Errorf("slots[%d]: duplicate window_id %d", i, slot.WindowID)
+		}
+		if slot.SlotIndex < 0 {
+			return nil, fmt.Errorf("slots[%d]: slot_index must be >= 0, got %d", i, slot.SlotIndex)
+		}
+		registry.Slots[slot.WindowID] = slot
+	}
+
+	return registry, nil
+}
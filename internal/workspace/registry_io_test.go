@@ -0,0 +1,130 @@
+package workspace
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRegistryExportImport_RoundTrip(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	if err := SetActiveWorkspace("alpha", 3, true, 0, []int{0, 2}); err != nil {
+		t.Fatalf("set active workspace: %v", err)
+	}
+	if err := SetActiveWorkspace("beta", 1, false, 2, nil); err != nil {
+		t.Fatalf("set active workspace: %v", err)
+	}
+	if err := SetSlotInfo(101, 0, "termtile-alpha-0", 0); err != nil {
+		t.Fatalf("set slot info: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := ExportRegistry(&buf); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	exported := buf.String()
+
+	// Wipe the registry so the import has to restore everything.
+	if err := ClearWorkspace(0); err != nil {
+		t.Fatalf("clear workspace: %v", err)
+	}
+	if err := ClearWorkspace(2); err != nil {
+		t.Fatalf("clear workspace: %v", err)
+	}
+	if err := RemoveSlotByWindowID(101); err != nil {
+		t.Fatalf("remove slot: %v", err)
+	}
+
+	n, err := ImportRegistry(strings.NewReader(exported))
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 imported workspaces, got %d", n)
+	}
+
+	all, err := GetAllWorkspaces()
+	if err != nil {
+		t.Fatalf("get all workspaces: %v", err)
+	}
+	alpha := all[0]
+	if alpha.Name != "alpha" || alpha.TerminalCount != 3 || !alpha.AgentMode {
+		t.Fatalf("unexpected alpha entry: %+v", alpha)
+	}
+	if len(alpha.AgentSlots) != 2 || alpha.AgentSlots[0] != 0 || alpha.AgentSlots[1] != 2 {
+		t.Fatalf("expected agent slots [0 2], got %v", alpha.AgentSlots)
+	}
+	if all[2].Name != "beta" {
+		t.Fatalf("expected beta on desktop 2, got %+v", all[2])
+	}
+
+	slot, ok := GetSlotByWindowID(101)
+	if !ok || slot.SessionName != "termtile-alpha-0" {
+		t.Fatalf("expected slot 101 to be restored, got %+v (ok=%v)", slot, ok)
+	}
+
+	// Exporting again should produce the same workspaces and slots.
+	var again bytes.Buffer
+	if err := ExportRegistry(&again); err != nil {
+		t.Fatalf("re-export: %v", err)
+	}
+	if stripExportedAt(again.String()) != stripExportedAt(exported) {
+		t.Fatalf("round-trip mismatch:\nfirst:\n%s\nsecond:\n%s", exported, again.String())
+	}
+}
+
+func TestImportRegistry_RejectsDuplicateDesktop(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	if err := SetActiveWorkspace("existing", 1, false, 0, nil); err != nil {
+		t.Fatalf("set active workspace: %v", err)
+	}
+
+	doc := `{
+  "version": 1,
+  "workspaces": [
+    {"name": "one", "desktop": 1, "terminal_count": 1},
+    {"name": "two", "desktop": 1, "terminal_count": 2}
+  ]
+}`
+	_, err := ImportRegistry(strings.NewReader(doc))
+	if err == nil {
+		t.Fatalf("expected duplicate desktop error")
+	}
+	if !strings.Contains(err.Error(), "duplicate desktop 1") {
+		t.Fatalf("expected duplicate desktop error, got %v", err)
+	}
+
+	// A rejected import must leave the current registry alone.
+	all, err := GetAllWorkspaces()
+	if err != nil {
+		t.Fatalf("get all workspaces: %v", err)
+	}
+	if len(all) != 1 || all[0].Name != "existing" {
+		t.Fatalf("expected registry to be unchanged, got %+v", all)
+	}
+}
+
+func TestImportRegistry_RejectsInvalidName(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	doc := `{"version": 1, "workspaces": [{"name": "../escape", "desktop": 0, "terminal_count": 1}]}`
+	if _, err := ImportRegistry(strings.NewReader(doc)); err == nil {
+		t.Fatalf("expected invalid name error")
+	} else if !strings.Contains(err.Error(), "invalid workspace name") {
+		t.Fatalf("expected invalid name error, got %v", err)
+	}
+}
+
+func stripExportedAt(s string) string {
+	lines := strings.Split(s, "\n")
+	out := lines[:0]
+	for _, line := range lines {
+		if strings.Contains(line, `"exported_at"`) {
+			continue
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
@@ -5,13 +5,17 @@ import (
 	"log"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 
 	"github.com/1broseidon/termtile/internal/agent"
+	"github.com/1broseidon/termtile/internal/platform"
+	"github.com/1broseidon/termtile/internal/terminals"
 )
 
-func Save(name, layout, terminalSort string, includeCmd bool, lister TerminalLister) (*WorkspaceConfig, error) {
+// Save captures the terminals on the active monitor as a workspace. When
+// geometryMode is "absolute" or "relative" each terminal's rect is saved too;
+// relative mode needs lister to implement MonitorBoundsProvider.
+func Save(name, layout, terminalSort string, includeCmd bool, geometryMode string, lister TerminalLister) (*WorkspaceConfig, error) {
 	if lister == nil {
 		return nil, fmt.Errorf("terminal lister is nil")
 	}
@@ -21,6 +25,20 @@ func Save(name, layout, terminalSort string, includeCmd bool, lister TerminalLis
 	if strings.TrimSpace(layout) == "" {
 		return nil, fmt.Errorf("layout is required")
 	}
+	geometryMode, err := NormalizeGeometryMode(geometryMode)
+	if err != nil {
+		return nil, err
+	}
+	var monitor Rect
+	if geometryMode == GeometryModeRelative {
+		provider, ok := lister.(MonitorBoundsProvider)
+		if !ok {
+			return nil, fmt.Errorf("relative geometry requires monitor bounds, which this terminal lister does not provide")
+		}
+		if monitor, err = provider.MonitorBounds(); err != nil {
+			return nil, err
+		}
+	}
 
 	windows, err := lister.ListTerminals()
 	if err != nil {
@@ -30,9 +48,10 @@ func Save(name, layout, terminalSort string, includeCmd bool, lister TerminalLis
 	sortTerminalWindows(windows, terminalSort, activeWin)
 
 	out := &WorkspaceConfig{
-		Name:      name,
-		Layout:    layout,
-		Terminals: make([]TerminalConfig, 0, len(windows)),
+		Name:         name,
+		Layout:       layout,
+		GeometryMode: geometryMode,
+		Terminals:    make([]TerminalConfig, 0, len(windows)),
 	}
 
 	for idx, win := range windows {
@@ -41,6 +60,12 @@ func Save(name, layout, terminalSort string, includeCmd bool, lister TerminalLis
 			SlotIndex:   idx,
 			SessionName: agent.SessionName(name, idx),
 		}
+		if geometryMode != "" {
+			winRect := Rect{X: win.X, Y: win.Y, Width: win.Width, Height: win.Height}
+			if term.Geometry, err = captureGeometry(geometryMode, winRect, monitor); err != nil {
+				return nil, err
+			}
+		}
 
 		if win.PID > 0 {
 			// Walk the process tree to find the shell running inside the terminal.
@@ -73,45 +98,9 @@ func Save(name, layout, terminalSort string, includeCmd bool, lister TerminalLis
 }
 
 func sortTerminalWindows(windows []TerminalWindow, mode string, activeWin uint32) {
-	switch mode {
-	case "client_list":
-		return
-	case "window_id":
-		sort.Slice(windows, func(i, j int) bool {
-			return windows[i].WindowID < windows[j].WindowID
-		})
-	case "active_first":
-		sort.SliceStable(windows, func(i, j int) bool {
-			wi, wj := windows[i], windows[j]
-			if activeWin != 0 {
-				if wi.WindowID == activeWin && wj.WindowID != activeWin {
-					return true
-				}
-				if wj.WindowID == activeWin && wi.WindowID != activeWin {
-					return false
-				}
-			}
-
-			if wi.Y != wj.Y {
-				return wi.Y < wj.Y
-			}
-			if wi.X != wj.X {
-				return wi.X < wj.X
-			}
-			return wi.WindowID < wj.WindowID
-		})
-	default:
-		sort.Slice(windows, func(i, j int) bool {
-			wi, wj := windows[i], windows[j]
-			if wi.Y != wj.Y {
-				return wi.Y < wj.Y
-			}
-			if wi.X != wj.X {
-				return wi.X < wj.X
-			}
-			return wi.WindowID < wj.WindowID
-		})
-	}
+	terminals.SortWindows(windows, mode, platform.WindowID(activeWin), func(w TerminalWindow) terminals.SortKey {
+		return terminals.SortKey{WindowID: platform.WindowID(w.WindowID), Title: w.Title, PID: w.PID, X: w.X, Y: w.Y}
+	})
 }
 
 // findShellForWindow finds the shell process associated with a specific X11
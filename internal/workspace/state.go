@@ -3,6 +3,7 @@ package workspace
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"time"
@@ -11,6 +12,15 @@ import (
 	"github.com/1broseidon/termtile/internal/runtimepath"
 )
 
+// warningOutput receives non-fatal warnings; tests replace it.
+var warningOutput io.Writer = os.Stderr
+
+// SetWarningOutput redirects the warnings printed by registry operations.
+// Each warning is written as a single "warning: ..." line.
+func SetWarningOutput(w io.Writer) {
+	warningOutput = w
+}
+
 // WorkspaceInfo holds information about an active workspace on a specific desktop.
 type WorkspaceInfo struct {
 	Name          string    `json:"name"`
@@ -50,7 +60,10 @@ func loadRegistry() (*workspaceRegistry, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return &workspaceRegistry{Workspaces: make(map[int]WorkspaceInfo)}, nil
+			return &workspaceRegistry{
+				Workspaces: make(map[int]WorkspaceInfo),
+				Slots:      make(map[uint32]SlotInfo),
+			}, nil
 		}
 		return nil, fmt.Errorf("failed to read workspace registry: %w", err)
 	}
@@ -75,6 +88,7 @@ func loadRegistry() (*workspaceRegistry, error) {
 						OpenedAt:      oldState.LoadedAt,
 					},
 				},
+				Slots: make(map[uint32]SlotInfo),
 			}
 			return &registry, nil
 		}
@@ -118,7 +132,7 @@ func SetActiveWorkspace(name string, terminalCount int, agentMode bool, desktop
 		d, err := platform.GetCurrentDesktopStandalone()
 		if err != nil {
 			// Fallback to desktop 0 with warning
-			fmt.Fprintf(os.Stderr, "warning: failed to detect current desktop, using 0: %v\n", err)
+			fmt.Fprintf(warningOutput, "warning: failed to detect current desktop, using 0: %v\n", err)
 			desktop = 0
 		} else {
 			desktop = d
@@ -163,7 +177,7 @@ func GetActiveWorkspace() (WorkspaceInfo, error) {
 	desktop, err := platform.GetCurrentDesktopStandalone()
 	if err != nil {
 		// Fallback to desktop 0 with warning
-		fmt.Fprintf(os.Stderr, "warning: failed to detect current desktop, using 0: %v\n", err)
+		fmt.Fprintf(warningOutput, "warning: failed to detect current desktop, using 0: %v\n", err)
 		desktop = 0
 	}
 
@@ -215,7 +229,7 @@ func ClearWorkspace(desktop int) error {
 		d, err := platform.GetCurrentDesktopStandalone()
 		if err != nil {
 			// Fallback to desktop 0 with warning
-			fmt.Fprintf(os.Stderr, "warning: failed to detect current desktop, using 0: %v\n", err)
+			fmt.Fprintf(warningOutput, "warning: failed to detect current desktop, using 0: %v\n", err)
 			desktop = 0
 		} else {
 			desktop = d
@@ -420,6 +434,41 @@ func AddTerminalToWorkspace(desktop int, agentSlot bool) (int, error) {
 	return newSlot, nil
 }
 
+// SetWorkspaceAgentMode flips agent mode for the workspace on desktop and
+// records agentSlots as the slots with multiplexer sessions. Disabling clears
+// the slots. The terminal count and open time are kept.
+func SetWorkspaceAgentMode(desktop int, agentMode bool, agentSlots []int) error {
+	registry, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+
+	ws, ok := registry.Workspaces[desktop]
+	if !ok {
+		return fmt.Errorf("no workspace on desktop %d", desktop)
+	}
+
+	var slots []int
+	if agentMode {
+		seen := make(map[int]struct{})
+		for _, s := range agentSlots {
+			if s < 0 {
+				continue
+			}
+			if _, ok := seen[s]; !ok {
+				seen[s] = struct{}{}
+				slots = append(slots, s)
+			}
+		}
+		sort.Ints(slots)
+	}
+	ws.AgentMode = agentMode
+	ws.AgentSlots = slots
+
+	registry.Workspaces[desktop] = ws
+	return saveRegistry(registry)
+}
+
 // SwapSlotsInRegistry swaps two slot indices in the workspace's AgentSlots.
 // This is called after a move/swap operation to keep runtime state in sync.
 // If desktop is -1, auto-detect current desktop.
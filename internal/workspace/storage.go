@@ -105,16 +105,37 @@ func Read(name string) (*WorkspaceConfig, error) {
 }
 
 func Delete(name string) error {
-	path, err := workspacePath(name)
+	paths, err := DeleteTargets(name)
 	if err != nil {
 		return err
 	}
-	if err := os.Remove(path); err != nil {
-		return fmt.Errorf("failed to delete workspace %q: %w", name, err)
+	for _, path := range paths {
+		if err := os.RemoveAll(path); err != nil {
+			return fmt.Errorf("failed to delete workspace %q: %w", name, err)
+		}
 	}
 	return nil
 }
 
+// DeleteTargets returns the files Delete would remove for name without
+// removing them: the saved file and, when present, its history directory.
+func DeleteTargets(name string) ([]string, error) {
+	path, err := workspacePath(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to delete workspace %q: %w", name, err)
+	}
+	paths := []string{path}
+	if dir, err := historyDir(name); err == nil {
+		if _, err := os.Stat(dir); err == nil {
+			paths = append(paths, dir)
+		}
+	}
+	return paths, nil
+}
+
 func List() ([]string, error) {
 	dir, err := workspacesDir()
 	if err != nil {
@@ -8,10 +8,42 @@ import (
 
 // WorkspaceConfig is a persisted snapshot of a set of terminal sessions.
 type WorkspaceConfig struct {
-	Name      string           `json:"name"`
-	Layout    string           `json:"layout"`
-	AgentMode bool             `json:"agent_mode,omitempty"`
-	Terminals []TerminalConfig `json:"terminals"`
+	Name      string `json:"name"`
+	Layout    string `json:"layout"`
+	AgentMode bool   `json:"agent_mode,omitempty"`
+	// DefaultAgent is the agent type spawn_agent uses for this workspace
+	// when the caller omits agent_type.
+	DefaultAgent string `json:"default_agent,omitempty"`
+	// Env is set in every agent spawned in this workspace. An agent's own
+	// env wins on conflicting keys.
+	Env map[string]string `json:"env,omitempty"`
+	// AutoTile controls whether MCP spawn/kill/move and terminal
+	// add/remove/move retile the workspace afterwards. Nil means true;
+	// false leaves a manually arranged workspace alone.
+	AutoTile *bool `json:"auto_tile,omitempty"`
+	// GeometryMode is "absolute" or "relative" when Terminals carry saved
+	// rects; empty means load applies Layout only.
+	GeometryMode string           `json:"geometry_mode,omitempty"`
+	Terminals    []TerminalConfig `json:"terminals"`
+}
+
+// AutoTileEnabled reports whether operations on the workspace retile it
+// (auto_tile, default true).
+func (c *WorkspaceConfig) AutoTileEnabled() bool {
+	if c == nil || c.AutoTile == nil {
+		return true
+	}
+	return *c.AutoTile
+}
+
+// AutoTileEnabled reports whether the saved workspace name allows automatic
+// retiling. Workspaces without a saved config (or unreadable ones) do.
+func AutoTileEnabled(name string) bool {
+	cfg, err := Read(name)
+	if err != nil {
+		return true
+	}
+	return cfg.AutoTileEnabled()
 }
 
 type TerminalConfig struct {
@@ -20,14 +52,19 @@ type TerminalConfig struct {
 	Cmd         []string `json:"cmd,omitempty"`
 	SlotIndex   int      `json:"slot_index"`
 	SessionName string   `json:"session_name,omitempty"`
+	// Geometry is the saved rect, interpreted per WorkspaceConfig.GeometryMode.
+	Geometry *SlotGeometry `json:"geometry,omitempty"`
 }
 
 // TerminalWindow is a lightweight snapshot of a currently-open terminal window.
 type TerminalWindow struct {
 	WindowID uint32
 	WMClass  string
+	Title    string
 	X        int
 	Y        int
+	Width    int
+	Height   int
 	PID      int
 }
 
@@ -58,5 +95,8 @@ type LoadOptions struct {
 	NoReplace            bool
 	AutoSaveLayout       string
 	AutoSaveTerminalSort string
+	NoAutoSave           bool           // Skip saving the replaced terminals to _previous (autosave_previous: false)
 	AppConfig            *config.Config // Application config for agent mode multiplexer settings
+	KeepPartial          bool           // Leave spawned terminals in place when the load fails
+	Mover                WindowMover    // Restores saved slot geometry; nil leaves the layout's rects
 }
@@ -70,6 +70,32 @@ func (c *Connection) SetWindowDesktop(windowID uint32, desktop int) error {
 	).Check()
 }
 
+// SetCurrentDesktop switches to the specified virtual desktop.
+// Sends a _NET_CURRENT_DESKTOP client message to the root window per EWMH
+// spec, built manually for the same reason as SetWindowDesktop.
+func (c *Connection) SetCurrentDesktop(desktop int) error {
+	atomReply, err := xproto.InternAtom(c.XUtil.Conn(), false,
+		uint16(len("_NET_CURRENT_DESKTOP")), "_NET_CURRENT_DESKTOP").Reply()
+	if err != nil {
+		return fmt.Errorf("failed to intern _NET_CURRENT_DESKTOP: %w", err)
+	}
+
+	ev := xproto.ClientMessageEvent{
+		Format: 32,
+		Window: c.Root,
+		Type:   atomReply.Atom,
+		Data:   xproto.ClientMessageDataUnionData32New([]uint32{uint32(desktop), 0, 0, 0, 0}),
+	}
+
+	return xproto.SendEventChecked(
+		c.XUtil.Conn(),
+		false,
+		c.Root,
+		xproto.EventMaskSubstructureRedirect|xproto.EventMaskSubstructureNotify,
+		string(ev.Bytes()),
+	).Check()
+}
+
 // FocusWindow activates and raises a window using _NET_ACTIVE_WINDOW.
 // Sends a client message to the root window per EWMH spec.
 // We build the message manually (same as SetWindowDesktop) because the
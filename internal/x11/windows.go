@@ -1,8 +1,11 @@
 package x11
 
 import (
+	"github.com/BurntSushi/xgb"
 	"github.com/BurntSushi/xgb/xproto"
 	"github.com/BurntSushi/xgbutil/ewmh"
+	"github.com/BurntSushi/xgbutil/xevent"
+	"github.com/BurntSushi/xgbutil/xprop"
 	"github.com/BurntSushi/xgbutil/xwindow"
 )
 
@@ -32,6 +35,89 @@ func (c *Connection) MoveResizeWindow(windowID xproto.Window, x, y, width, heigh
 	return nil
 }
 
+// WindowGeometry is a target geometry for MoveResizeWindows.
+type WindowGeometry struct {
+	Window xproto.Window
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+// MoveResizeWindows applies several geometries with the same requests as
+// MoveResizeWindow, but without a round trip per window: the _NET_WM_STATE
+// queries are pipelined, the unmaximize and _NET_MOVERESIZE_WINDOW messages
+// are sent unchecked, and a single sync at the end flushes the queue.
+func (c *Connection) MoveResizeWindows(moves []WindowGeometry) error {
+	if len(moves) == 0 {
+		return nil
+	}
+
+	xu := c.XUtil
+	stateAtom, err := xprop.Atm(xu, "_NET_WM_STATE")
+	if err != nil {
+		return err
+	}
+	maxHAtom, err := xprop.Atm(xu, "_NET_WM_STATE_MAXIMIZED_HORZ")
+	if err != nil {
+		return err
+	}
+	maxVAtom, err := xprop.Atm(xu, "_NET_WM_STATE_MAXIMIZED_VERT")
+	if err != nil {
+		return err
+	}
+	moveAtom, err := xprop.Atm(xu, "_NET_MOVERESIZE_WINDOW")
+	if err != nil {
+		return err
+	}
+
+	evMask := uint32(xproto.EventMaskSubstructureNotify | xproto.EventMaskSubstructureRedirect)
+	send := func(win xproto.Window, msgType xproto.Atom, data ...interface{}) error {
+		cm, err := xevent.NewClientMessage(32, win, msgType, data...)
+		if err != nil {
+			return err
+		}
+		xproto.SendEvent(xu.Conn(), false, xu.RootWin(), evMask, string(cm.Bytes()))
+		return nil
+	}
+
+	// Issue every state query before waiting on any reply.
+	cookies := make([]xproto.GetPropertyCookie, len(moves))
+	for i, m := range moves {
+		cookies[i] = xproto.GetProperty(xu.Conn(), false, m.Window, stateAtom, xproto.AtomAtom, 0, 1<<16)
+	}
+
+	for i, m := range moves {
+		// Same as unmaximizeWindow: a failed lookup just skips the unmaximize.
+		if reply, err := cookies[i].Reply(); err == nil && reply != nil {
+			for j := 0; j+4 <= len(reply.Value); j += 4 {
+				switch xproto.Atom(xgb.Get32(reply.Value[j:])) {
+				case maxHAtom:
+					_ = send(m.Window, stateAtom, 0, int(maxHAtom), 0, 2)
+				case maxVAtom:
+					_ = send(m.Window, stateAtom, 0, int(maxVAtom), 0, 2)
+				}
+			}
+		}
+
+		// Flags match ewmh.MoveresizeWindow: forget gravity, pager source,
+		// x/y always set, width/height when positive.
+		flags := xproto.GravityBitForget | 2<<12 | 1<<8 | 1<<9
+		if m.Width > 0 {
+			flags |= 1 << 10
+		}
+		if m.Height > 0 {
+			flags |= 1 << 11
+		}
+		if err := send(m.Window, moveAtom, flags, m.X, m.Y, m.Width, m.Height); err != nil {
+			return err
+		}
+	}
+
+	xu.Sync()
+	return nil
+}
+
 // unmaximizeWindow removes maximized state from a window
 func (c *Connection) unmaximizeWindow(windowID xproto.Window) error {
 	// Get current window states